@@ -0,0 +1,326 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"fids-tui/models"
+)
+
+const (
+	// adsbFeedTimeout gives up on a poll of a slow or unreachable local receiver
+	adsbFeedTimeout = 3 * time.Second
+	// adsbPollInterval is how often the local receiver is polled, independently
+	// of the board's refreshes
+	adsbPollInterval = 5 * time.Second
+	// adsbSnapshotMaxAge is how old the last snapshot may be and still be used;
+	// older ones mean the receiver has stopped answering
+	adsbSnapshotMaxAge = time.Minute
+	// adsbMinAltitude is the barometric altitude (feet) above which an aircraft counts as airborne
+	adsbMinAltitude = 100
+	// adsbDepartureWindow limits matches to flights scheduled to leave soon, so an
+	// inbound aircraft reusing the same callsign is not mistaken for the departure
+	adsbDepartureWindow = 30 * time.Minute
+	// adsbDescentRate is the sink rate (feet per minute) beyond which an aircraft
+	// is taken to be landing rather than departing
+	adsbDescentRate = 300
+)
+
+// ADSBAircraft represents a single aircraft from a dump1090/readsb aircraft.json feed
+type ADSBAircraft struct {
+	Hex      string          `json:"hex"`
+	Flight   string          `json:"flight"`
+	AltBaro  json.RawMessage `json:"alt_baro"`
+	Altitude json.RawMessage `json:"altitude"`  // Older dump1090 versions
+	BaroRate *float64        `json:"baro_rate"` // Feet per minute, negative when descending
+	Seen     float64         `json:"seen"`
+}
+
+// ADSBSnapshot represents the aircraft.json document
+type ADSBSnapshot struct {
+	Now      float64        `json:"now"`
+	Aircraft []ADSBAircraft `json:"aircraft"`
+}
+
+// Airborne reports whether the aircraft is flying, based on its reported altitude
+func (a ADSBAircraft) Airborne() bool {
+	alt := a.AltBaro
+	if len(alt) == 0 {
+		alt = a.Altitude
+	}
+	if len(alt) == 0 {
+		return false
+	}
+
+	// alt_baro is either a number of feet or the string "ground"
+	var feet float64
+	if err := json.Unmarshal(alt, &feet); err != nil {
+		return false
+	}
+	return feet >= adsbMinAltitude
+}
+
+// Descending reports whether the aircraft is sinking fast enough to be on its
+// way in to land
+func (a ADSBAircraft) Descending() bool {
+	return a.BaroRate != nil && *a.BaroRate < -adsbDescentRate
+}
+
+// Callsign returns the trimmed, uppercase callsign of the aircraft
+func (a ADSBAircraft) Callsign() string {
+	return strings.ToUpper(strings.TrimSpace(a.Flight))
+}
+
+// ADSBProvider wraps a primary provider and upgrades flights observed airborne by a
+// local ADS-B receiver to departed, using the observed time as the wheels-up time.
+// The receiver is polled in the background from the first fetch until Close,
+// and each fetch uses the last snapshot, so a slow receiver never holds it up
+type ADSBProvider struct {
+	Primary FlightDataProvider
+	FeedURL string
+	Client  *http.Client
+
+	departed   map[string]time.Time // First time each ident was seen airborne
+	snapshot   *ADSBSnapshot        // Last snapshot polled from the receiver
+	snapshotAt time.Time            // When snapshot was polled
+	mu         sync.Mutex
+
+	start sync.Once
+	stop  sync.Once
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewADSBProvider creates a hybrid provider polling the aircraft.json feed at feedURL
+func NewADSBProvider(primary FlightDataProvider, feedURL string) *ADSBProvider {
+	return &ADSBProvider{
+		Primary: primary,
+		FeedURL: feedURL,
+		Client: &http.Client{
+			Timeout: adsbFeedTimeout,
+		},
+		departed: make(map[string]time.Time),
+		done:     make(chan struct{}),
+	}
+}
+
+// Close stops polling the receiver
+func (p *ADSBProvider) Close() {
+	p.stop.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+// Name returns the display name of the data source
+func (p *ADSBProvider) Name() string {
+	return p.Primary.Name() + " + ADS-B"
+}
+
+// SuggestedInterval returns the fetch interval of the primary provider
+func (p *ADSBProvider) SuggestedInterval() time.Duration {
+	return SuggestedInterval(p.Primary)
+}
+
+// GetDepartures fetches departures from the primary provider and applies live
+// ADS-B observations. If the local feed is unreachable the primary data is
+// returned unchanged
+func (p *ADSBProvider) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	result, err := GetFlights(ctx, p.Primary, models.Departure, airportCode, opts)
+	return p.observe(ctx, result, err)
+}
+
+// GetRouteDepartures fetches the departures of a route from the primary
+// provider and applies live ADS-B observations like GetDepartures
+func (p *ADSBProvider) GetRouteDepartures(ctx context.Context, origin, destination string, opts FetchOptions) (FetchResult, error) {
+	result, err := GetRoute(ctx, p.Primary, origin, destination, opts)
+	return p.observe(ctx, result, err)
+}
+
+// observe upgrades the departures in result to departed when the last
+// snapshot from the local feed has seen them airborne, returning the primary
+// data unchanged if the feed hasn't answered lately
+func (p *ADSBProvider) observe(ctx context.Context, result FetchResult, err error) (FetchResult, error) {
+	p.start.Do(func() {
+		p.wg.Add(1)
+		go p.poll()
+	})
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.snapshot == nil || now.Sub(p.snapshotAt) > adsbSnapshotMaxAge {
+		return result, nil
+	}
+	result.Source += " + ADS-B"
+	CorrelateAircraft(result.Flights, p.snapshot, p.departed, now)
+	return result, nil
+}
+
+// poll fetches a snapshot from the receiver every adsbPollInterval until
+// Close, keeping the last one that was fetched
+func (p *ADSBProvider) poll() {
+	defer p.wg.Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.done
+		cancel()
+	}()
+
+	ticker := time.NewTicker(adsbPollInterval)
+	defer ticker.Stop()
+	for {
+		p.pollOnce(ctx)
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollOnce fetches a snapshot from the receiver, keeping it if it was fetched
+func (p *ADSBProvider) pollOnce(ctx context.Context) {
+	snapshot, err := p.fetchSnapshot(ctx)
+	if err != nil {
+		slog.Debug("ADS-B feed unavailable", "url", p.FeedURL, "error", err)
+		return
+	}
+	p.mu.Lock()
+	p.snapshot = snapshot
+	p.snapshotAt = time.Now()
+	p.mu.Unlock()
+}
+
+// GetArrivals fetches arrivals from the primary provider
+// ADS-B observations are only used to detect departures
+func (p *ADSBProvider) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.Primary.GetArrivals(ctx, airportCode, opts)
+}
+
+// GetFlight looks up a flight with the primary provider
+func (p *ADSBProvider) GetFlight(ctx context.Context, id string) (models.Flight, error) {
+	return LookupFlight(ctx, p.Primary, id)
+}
+
+// fetchSnapshot downloads and parses the aircraft.json feed
+func (p *ADSBProvider) fetchSnapshot(ctx context.Context) (*ADSBSnapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.FeedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ADS-B feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ADS-B feed error (status %d)", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ADS-B feed: %w", err)
+	}
+
+	var snapshot ADSBSnapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse ADS-B feed: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// CorrelateAircraft marks flights whose ident begins the callsign of an
+// aircraft climbing out or cruising as departed. Descending aircraft are left
+// out, so an inbound aircraft reusing the callsign isn't taken for the
+// departure. departed remembers the first time each ident was seen airborne so
+// the wheels-up time stays fixed across polls; idents no longer on the flight
+// list are forgotten
+func CorrelateAircraft(flights []models.Flight, snapshot *ADSBSnapshot, departed map[string]time.Time, now time.Time) {
+	observedAt := now.UTC()
+	if snapshot.Now > 0 {
+		observedAt = time.Unix(0, int64(snapshot.Now*float64(time.Second))).UTC()
+	}
+
+	airborne := make(map[string]time.Time)
+	for _, ac := range snapshot.Aircraft {
+		callsign := ac.Callsign()
+		if callsign == "" || !ac.Airborne() || ac.Descending() {
+			continue
+		}
+		seenAt := observedAt.Add(-time.Duration(ac.Seen * float64(time.Second)))
+		airborne[callsign] = seenAt
+	}
+
+	current := make(map[string]bool)
+	for i := range flights {
+		ident := strings.ToUpper(flights[i].Ident)
+		if ident == "" {
+			continue
+		}
+		current[ident] = true
+
+		if flights[i].Status == models.StatusCancelled {
+			continue
+		}
+		if flights[i].ScheduledDeparture.After(now.Add(adsbDepartureWindow)) {
+			continue
+		}
+
+		if _, seen := departed[ident]; !seen {
+			seenAt, ok := matchCallsign(airborne, ident)
+			if !ok {
+				continue
+			}
+			departed[ident] = seenAt
+		}
+
+		offTime := departed[ident]
+		flights[i].Status = models.StatusDeparted
+		flights[i].Remarks = models.RemarksDeparted
+		flights[i].ActualOff = &offTime
+	}
+
+	for ident := range departed {
+		if !current[ident] {
+			delete(departed, ident)
+		}
+	}
+}
+
+// matchCallsign returns when the aircraft flying as ident was seen. Some
+// operators append a letter to the flight number in the callsign, e.g.
+// "UAL123A", so the callsign need only begin with ident, as long as it doesn't
+// go on with another digit: "UAL1234" is a different flight from "UAL123".
+// An exact match wins, then the first matching callsign in order
+func matchCallsign(airborne map[string]time.Time, ident string) (time.Time, bool) {
+	if seenAt, ok := airborne[ident]; ok {
+		return seenAt, true
+	}
+	match := ""
+	for callsign := range airborne {
+		if !strings.HasPrefix(callsign, ident) {
+			continue
+		}
+		if next := callsign[len(ident)]; next >= '0' && next <= '9' {
+			continue
+		}
+		if match == "" || callsign < match {
+			match = callsign
+		}
+	}
+	if match == "" {
+		return time.Time{}, false
+	}
+	return airborne[match], true
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// adsbNow is the receiver clock of the departing.json snapshot
+var adsbNow = time.Unix(1767268800, 0).UTC()
+
+// loadSnapshot reads an aircraft.json snapshot from testdata/adsb
+func loadSnapshot(t *testing.T, name string) *ADSBSnapshot {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "adsb", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot ADSBSnapshot
+	if err := json.Unmarshal(body, &snapshot); err != nil {
+		t.Fatalf("parsing %s: %v", name, err)
+	}
+	return &snapshot
+}
+
+// scheduledDeparture returns a departure of ident scheduled offset from now
+func scheduledDeparture(ident string, now time.Time, offset time.Duration) models.Flight {
+	return models.Flight{
+		Ident:              ident,
+		FlightNumber:       ident,
+		Direction:          models.Departure,
+		Status:             models.StatusOnTime,
+		ScheduledDeparture: now.Add(offset),
+	}
+}
+
+func TestCorrelateAircraft(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		flight   models.Flight
+		departed bool
+		off      time.Time // Wheels-up time when departed
+	}{
+		{"exact callsign", "departing.json", scheduledDeparture("UAL123", adsbNow, -10*time.Minute), true, adsbNow.Add(-2 * time.Second)},
+		{"lowercase ident", "departing.json", scheduledDeparture("ual123", adsbNow, -10*time.Minute), true, adsbNow.Add(-2 * time.Second)},
+		{"callsign with a letter after the ident", "departing.json", scheduledDeparture("DAL45", adsbNow, -5*time.Minute), true, adsbNow.Add(-500 * time.Millisecond)},
+		{"older dump1090 altitude", "departing.json", scheduledDeparture("JBU7", adsbNow, 5*time.Minute), true, adsbNow.Add(-time.Second)},
+		{"callsign with more digits", "departing.json", scheduledDeparture("UAL12", adsbNow, -10*time.Minute), false, time.Time{}},
+		{"inbound aircraft descending", "departing.json", scheduledDeparture("AAL88", adsbNow, 10*time.Minute), false, time.Time{}},
+		{"aircraft on the ground", "departing.json", scheduledDeparture("SWA9", adsbNow, -15*time.Minute), false, time.Time{}},
+		{"scheduled too far ahead", "climbing.json", scheduledDeparture("UAL1234", adsbNow, 2*time.Hour), false, time.Time{}},
+		{"not in the snapshot", "departing.json", scheduledDeparture("FFT200", adsbNow, 0), false, time.Time{}},
+		{"no ident", "departing.json", scheduledDeparture("", adsbNow, 0), false, time.Time{}},
+		{"empty snapshot", "empty.json", scheduledDeparture("UAL123", adsbNow, -10*time.Minute), false, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flights := []models.Flight{tt.flight}
+			CorrelateAircraft(flights, loadSnapshot(t, tt.snapshot), make(map[string]time.Time), adsbNow)
+
+			got := flights[0]
+			if !tt.departed {
+				if got.Status != tt.flight.Status || got.ActualOff != nil {
+					t.Errorf("flight upgraded to %v at %v, want it left alone", got.Status, got.ActualOff)
+				}
+				return
+			}
+			if got.Status != models.StatusDeparted || got.Remarks != models.RemarksDeparted {
+				t.Errorf("status %v with remarks %q, want departed", got.Status, got.Remarks)
+			}
+			if got.ActualOff == nil || !got.ActualOff.Equal(tt.off) {
+				t.Errorf("wheels up at %v, want %v", got.ActualOff, tt.off)
+			}
+		})
+	}
+}
+
+func TestCorrelateAircraftCancelled(t *testing.T) {
+	flight := scheduledDeparture("UAL123", adsbNow, -10*time.Minute)
+	flight.Status = models.StatusCancelled
+	flights := []models.Flight{flight}
+	CorrelateAircraft(flights, loadSnapshot(t, "departing.json"), make(map[string]time.Time), adsbNow)
+	if flights[0].Status != models.StatusCancelled {
+		t.Errorf("cancelled flight shown as %v", flights[0].Status)
+	}
+}
+
+func TestCorrelateAircraftAcrossPolls(t *testing.T) {
+	departed := make(map[string]time.Time)
+	wheelsUp := adsbNow.Add(-2 * time.Second)
+	polls := []struct {
+		snapshot string
+		idents   []string
+	}{
+		{"departing.json", []string{"UAL123", "AAL88"}},
+		// Seen again a minute later, the wheels-up time stays the first sighting
+		{"climbing.json", []string{"UAL123", "AAL88"}},
+		// Out of range of the receiver, the flight is still remembered as departed
+		{"empty.json", []string{"UAL123", "AAL88"}},
+	}
+	for _, poll := range polls {
+		var flights []models.Flight
+		for _, ident := range poll.idents {
+			flights = append(flights, scheduledDeparture(ident, adsbNow, -10*time.Minute))
+		}
+		CorrelateAircraft(flights, loadSnapshot(t, poll.snapshot), departed, adsbNow)
+
+		if flights[0].ActualOff == nil || !flights[0].ActualOff.Equal(wheelsUp) {
+			t.Errorf("%s: UAL123 wheels up at %v, want %v", poll.snapshot, flights[0].ActualOff, wheelsUp)
+		}
+		if flights[1].Status == models.StatusDeparted {
+			t.Errorf("%s: inbound AAL88 taken for the departure", poll.snapshot)
+		}
+	}
+
+	// Once off the flight list, the ident is forgotten
+	CorrelateAircraft(nil, loadSnapshot(t, "empty.json"), departed, adsbNow)
+	if len(departed) != 0 {
+		t.Errorf("departed still holds %v", departed)
+	}
+}
+
+// stubProvider serves a fixed list of flights
+type stubProvider struct {
+	flights []models.Flight
+}
+
+func (p stubProvider) Name() string { return "Stub" }
+
+func (p stubProvider) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return FetchResult{Flights: append([]models.Flight(nil), p.flights...)}, nil
+}
+
+func (p stubProvider) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return FetchResult{}, nil
+}
+
+func TestADSBProviderUsesLastSnapshot(t *testing.T) {
+	feed, err := os.ReadFile(filepath.Join("testdata", "adsb", "departing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(feed)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	provider := NewADSBProvider(stubProvider{[]models.Flight{scheduledDeparture("UAL123", now, -10*time.Minute)}}, server.URL)
+	defer provider.Close()
+	// Poll by hand rather than in the background, so the snapshot's age is known
+	provider.start.Do(func() {})
+	provider.pollOnce(context.Background())
+
+	result, err := provider.GetDepartures(context.Background(), "ORD", FetchOptions{})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if result.Source != "Stub + ADS-B" {
+		t.Errorf("source = %q, want Stub + ADS-B", result.Source)
+	}
+	if result.Flights[0].Status != models.StatusDeparted {
+		t.Errorf("status = %v, want departed", result.Flights[0].Status)
+	}
+
+	// A snapshot the receiver hasn't refreshed in a while is no longer used
+	provider.mu.Lock()
+	provider.snapshotAt = now.Add(-2 * adsbSnapshotMaxAge)
+	provider.departed = make(map[string]time.Time)
+	provider.mu.Unlock()
+	result, err = provider.GetDepartures(context.Background(), "ORD", FetchOptions{})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if result.Source != "Stub" || result.Flights[0].Status != models.StatusOnTime {
+		t.Errorf("stale snapshot used: source %q, status %v", result.Source, result.Flights[0].Status)
+	}
+}
+
+func TestADSBProviderFeedUnreachable(t *testing.T) {
+	// The feed answers slower than it may take to, as a hung receiver would
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	now := time.Now()
+	provider := NewADSBProvider(stubProvider{[]models.Flight{scheduledDeparture("UAL123", now, -10*time.Minute)}}, server.URL)
+
+	start := time.Now()
+	result, err := provider.GetDepartures(context.Background(), "ORD", FetchOptions{})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetDepartures waited %v for the feed", elapsed)
+	}
+	if result.Source != "Stub" || result.Flights[0].Status != models.StatusOnTime {
+		t.Errorf("got source %q and status %v, want the primary data unchanged", result.Source, result.Flights[0].Status)
+	}
+
+	// Closing stops the poll under way without waiting for the feed
+	start = time.Now()
+	provider.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close waited %v for the feed", elapsed)
+	}
+}
+
+func TestADSBAircraftStates(t *testing.T) {
+	snapshot := loadSnapshot(t, "departing.json")
+	var airborne, descending []string
+	for _, ac := range snapshot.Aircraft {
+		if ac.Airborne() {
+			airborne = append(airborne, ac.Callsign())
+		}
+		if ac.Descending() {
+			descending = append(descending, ac.Callsign())
+		}
+	}
+	if got, want := strings.Join(airborne, ","), "UAL123,DAL45A,AAL88,JBU7,"; got != want {
+		t.Errorf("airborne = %s, want %s", got, want)
+	}
+	if got, want := strings.Join(descending, ","), "AAL88"; got != want {
+		t.Errorf("descending = %s, want %s", got, want)
+	}
+}
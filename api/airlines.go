@@ -0,0 +1,265 @@
+package api
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// airline is an entry of the built-in airline table
+type airline struct {
+	IATA string `json:"iata"`
+	Name string `json:"name"`
+}
+
+// knownAirlines maps ICAO airline designators to their IATA equivalents and names
+var knownAirlines = map[string]airline{
+	// North America
+	"AAL": {"AA", "American Airlines"},
+	"DAL": {"DL", "Delta Air Lines"},
+	"UAL": {"UA", "United Airlines"},
+	"SWA": {"WN", "Southwest Airlines"},
+	"ASA": {"AS", "Alaska Airlines"},
+	"JBU": {"B6", "JetBlue Airways"},
+	"NKS": {"NK", "Spirit Airlines"},
+	"FFT": {"F9", "Frontier Airlines"},
+	"AAY": {"G4", "Allegiant Air"},
+	"HAL": {"HA", "Hawaiian Airlines"},
+	"SCX": {"SY", "Sun Country Airlines"},
+	"MXY": {"MX", "Breeze Airways"},
+	"VXP": {"XP", "Avelo Airlines"},
+	"SKW": {"OO", "SkyWest Airlines"},
+	"RPA": {"YX", "Republic Airways"},
+	"ENY": {"MQ", "Envoy Air"},
+	"EDV": {"9E", "Endeavor Air"},
+	"JIA": {"OH", "PSA Airlines"},
+	"PDT": {"PT", "Piedmont Airlines"},
+	"ASH": {"YV", "Mesa Airlines"},
+	"GJS": {"G7", "GoJet Airlines"},
+	"QXE": {"QX", "Horizon Air"},
+	"CPZ": {"C5", "CommutAir"},
+	"ASQ": {"EV", "ExpressJet"},
+	"AWI": {"ZW", "Air Wisconsin"},
+	"FDX": {"FX", "FedEx Express"},
+	"UPS": {"5X", "UPS Airlines"},
+	"GTI": {"5Y", "Atlas Air"},
+	"ABX": {"GB", "ABX Air"},
+	"ACA": {"AC", "Air Canada"},
+	"ROU": {"RV", "Air Canada Rouge"},
+	"JZA": {"QK", "Jazz Aviation"},
+	"WJA": {"WS", "WestJet"},
+	"WEN": {"WR", "WestJet Encore"},
+	"POE": {"PD", "Porter Airlines"},
+	"TSC": {"TS", "Air Transat"},
+	"SWG": {"WG", "Sunwing Airlines"},
+	"FLE": {"F8", "Flair Airlines"},
+	"AMX": {"AM", "Aeromexico"},
+	"SLI": {"5D", "Aeromexico Connect"},
+	"VOI": {"Y4", "Volaris"},
+	"VIV": {"VB", "VivaAerobus"},
+	"AIJ": {"4O", "Interjet"},
+	"BHS": {"UP", "Bahamasair"},
+	"BWA": {"BW", "Caribbean Airlines"},
+	"CAY": {"KX", "Cayman Airways"},
+
+	// Central and South America
+	"CMP": {"CM", "Copa Airlines"},
+	"AVA": {"AV", "Avianca"},
+	"LAN": {"LA", "LATAM Airlines"},
+	"TAM": {"JJ", "LATAM Brasil"},
+	"LPE": {"LP", "LATAM Peru"},
+	"GLO": {"G3", "Gol"},
+	"AZU": {"AD", "Azul"},
+	"ARG": {"AR", "Aerolineas Argentinas"},
+	"SKU": {"H2", "Sky Airline"},
+	"JAT": {"JA", "JetSMART"},
+	"BOV": {"OB", "Boliviana de Aviacion"},
+	"TAI": {"TA", "TACA"},
+	"LRC": {"LR", "LACSA"},
+	"VCV": {"V0", "Conviasa"},
+
+	// Europe
+	"BAW": {"BA", "British Airways"},
+	"VIR": {"VS", "Virgin Atlantic"},
+	"EZY": {"U2", "easyJet"},
+	"EJU": {"EC", "easyJet Europe"},
+	"EZS": {"DS", "easyJet Switzerland"},
+	"RYR": {"FR", "Ryanair"},
+	"RUK": {"RK", "Ryanair UK"},
+	"WZZ": {"W6", "Wizz Air"},
+	"WUK": {"W9", "Wizz Air UK"},
+	"EXS": {"LS", "Jet2"},
+	"TOM": {"BY", "TUI Airways"},
+	"LOG": {"LM", "Loganair"},
+	"EIN": {"EI", "Aer Lingus"},
+	"STK": {"EI", "Aer Lingus UK"},
+	"AFR": {"AF", "Air France"},
+	"HOP": {"A5", "HOP!"},
+	"TVF": {"TO", "Transavia France"},
+	"TRA": {"HV", "Transavia"},
+	"KLM": {"KL", "KLM"},
+	"KLC": {"WA", "KLM Cityhopper"},
+	"DLH": {"LH", "Lufthansa"},
+	"CLH": {"CL", "Lufthansa CityLine"},
+	"EWG": {"EW", "Eurowings"},
+	"CFG": {"DE", "Condor"},
+	"TUI": {"X3", "TUIfly"},
+	"SWR": {"LX", "Swiss"},
+	"EDW": {"WK", "Edelweiss Air"},
+	"AUA": {"OS", "Austrian Airlines"},
+	"BEL": {"SN", "Brussels Airlines"},
+	"SAS": {"SK", "Scandinavian Airlines"},
+	"NAX": {"DY", "Norwegian Air Shuttle"},
+	"NSZ": {"D8", "Norwegian Air Sweden"},
+	"FIN": {"AY", "Finnair"},
+	"ICE": {"FI", "Icelandair"},
+	"FPY": {"OG", "PLAY"},
+	"IBE": {"IB", "Iberia"},
+	"IBS": {"I2", "Iberia Express"},
+	"ANE": {"YW", "Air Nostrum"},
+	"VLG": {"VY", "Vueling"},
+	"AEA": {"UX", "Air Europa"},
+	"IBB": {"NT", "Binter Canarias"},
+	"VOE": {"V7", "Volotea"},
+	"TAP": {"TP", "TAP Air Portugal"},
+	"ITY": {"AZ", "ITA Airways"},
+	"AZA": {"AZ", "Alitalia"},
+	"LOT": {"LO", "LOT Polish Airlines"},
+	"CSA": {"OK", "Czech Airlines"},
+	"TVS": {"QS", "Smartwings"},
+	"ROT": {"RO", "TAROM"},
+	"BTI": {"BT", "airBaltic"},
+	"AEE": {"A3", "Aegean Airlines"},
+	"OAL": {"OA", "Olympic Air"},
+	"CTN": {"OU", "Croatia Airlines"},
+	"ASL": {"JU", "Air Serbia"},
+	"LGL": {"LG", "Luxair"},
+	"THY": {"TK", "Turkish Airlines"},
+	"PGT": {"PC", "Pegasus Airlines"},
+	"SXS": {"XQ", "SunExpress"},
+	"AFL": {"SU", "Aeroflot"},
+	"SBI": {"S7", "S7 Airlines"},
+	"AUI": {"PS", "Ukraine International Airlines"},
+	"ELY": {"LY", "El Al"},
+	"MSR": {"MS", "EgyptAir"},
+	"AMC": {"KM", "Air Malta"},
+	"CYP": {"CY", "Cyprus Airways"},
+
+	// Middle East
+	"UAE": {"EK", "Emirates"},
+	"FDB": {"FZ", "flydubai"},
+	"ETD": {"EY", "Etihad Airways"},
+	"ABY": {"G9", "Air Arabia"},
+	"QTR": {"QR", "Qatar Airways"},
+	"SVA": {"SV", "Saudia"},
+	"FAD": {"F3", "flyadeal"},
+	"KNE": {"XY", "flynas"},
+	"GFA": {"GF", "Gulf Air"},
+	"OMA": {"WY", "Oman Air"},
+	"KAC": {"KU", "Kuwait Airways"},
+	"JZR": {"J9", "Jazeera Airways"},
+	"RJA": {"RJ", "Royal Jordanian"},
+	"MEA": {"ME", "Middle East Airlines"},
+	"IRA": {"IR", "Iran Air"},
+
+	// Africa
+	"ETH": {"ET", "Ethiopian Airlines"},
+	"KQA": {"KQ", "Kenya Airways"},
+	"SAA": {"SA", "South African Airways"},
+	"RAM": {"AT", "Royal Air Maroc"},
+	"DAH": {"AH", "Air Algerie"},
+	"TAR": {"TU", "Tunisair"},
+	"RWD": {"WB", "RwandAir"},
+	"AAW": {"W3", "Arik Air"},
+	"MAU": {"MK", "Air Mauritius"},
+	"SEY": {"HM", "Air Seychelles"},
+
+	// Asia
+	"JAL": {"JL", "Japan Airlines"},
+	"ANA": {"NH", "All Nippon Airways"},
+	"APJ": {"MM", "Peach Aviation"},
+	"JJP": {"GK", "Jetstar Japan"},
+	"SKY": {"BC", "Skymark Airlines"},
+	"KAL": {"KE", "Korean Air"},
+	"AAR": {"OZ", "Asiana Airlines"},
+	"JJA": {"7C", "Jeju Air"},
+	"JNA": {"LJ", "Jin Air"},
+	"TWB": {"TW", "T'way Air"},
+	"CCA": {"CA", "Air China"},
+	"CES": {"MU", "China Eastern Airlines"},
+	"CSN": {"CZ", "China Southern Airlines"},
+	"CHH": {"HU", "Hainan Airlines"},
+	"CXA": {"MF", "Xiamen Airlines"},
+	"CSZ": {"ZH", "Shenzhen Airlines"},
+	"CSC": {"3U", "Sichuan Airlines"},
+	"CQH": {"9C", "Spring Airlines"},
+	"DKH": {"HO", "Juneyao Airlines"},
+	"CPA": {"CX", "Cathay Pacific"},
+	"HKE": {"UO", "HK Express"},
+	"CRK": {"HX", "Hong Kong Airlines"},
+	"AMU": {"NX", "Air Macau"},
+	"CAL": {"CI", "China Airlines"},
+	"EVA": {"BR", "EVA Air"},
+	"SJX": {"JX", "Starlux Airlines"},
+	"TTW": {"IT", "Tigerair Taiwan"},
+	"SIA": {"SQ", "Singapore Airlines"},
+	"TGW": {"TR", "Scoot"},
+	"MAS": {"MH", "Malaysia Airlines"},
+	"AXM": {"AK", "AirAsia"},
+	"XAX": {"D7", "AirAsia X"},
+	"THA": {"TG", "Thai Airways"},
+	"AIQ": {"FD", "Thai AirAsia"},
+	"TLM": {"SL", "Thai Lion Air"},
+	"BKP": {"PG", "Bangkok Airways"},
+	"GIA": {"GA", "Garuda Indonesia"},
+	"LNI": {"JT", "Lion Air"},
+	"CTV": {"QG", "Citilink"},
+	"PAL": {"PR", "Philippine Airlines"},
+	"CEB": {"5J", "Cebu Pacific"},
+	"HVN": {"VN", "Vietnam Airlines"},
+	"VJC": {"VJ", "VietJet Air"},
+	"BAV": {"QH", "Bamboo Airways"},
+	"AIC": {"AI", "Air India"},
+	"AXB": {"IX", "Air India Express"},
+	"IGO": {"6E", "IndiGo"},
+	"SEJ": {"SG", "SpiceJet"},
+	"AKJ": {"QP", "Akasa Air"},
+	"ALK": {"UL", "SriLankan Airlines"},
+	"PIA": {"PK", "Pakistan International Airlines"},
+	"BBC": {"BG", "Biman Bangladesh Airlines"},
+	"RNA": {"RA", "Nepal Airlines"},
+	"UZB": {"HY", "Uzbekistan Airways"},
+	"KZR": {"KC", "Air Astana"},
+	"MGL": {"OM", "MIAT Mongolian Airlines"},
+
+	// Oceania
+	"QFA": {"QF", "Qantas"},
+	"JST": {"JQ", "Jetstar"},
+	"VOZ": {"VA", "Virgin Australia"},
+	"RXA": {"ZL", "Rex Airlines"},
+	"ANZ": {"NZ", "Air New Zealand"},
+	"FJI": {"FJ", "Fiji Airways"},
+	"ANG": {"PX", "Air Niugini"},
+	"ACI": {"SB", "Aircalin"},
+	"THT": {"TN", "Air Tahiti Nui"},
+}
+
+// loggedAirlineMisses remembers unmapped operators so each is only logged once
+var loggedAirlineMisses sync.Map
+
+// AirlineIATA normalizes an airline code to its 2-character IATA designator
+// Codes that are already IATA are returned unchanged; unmapped ICAO codes are
+// returned as-is and logged once
+func AirlineIATA(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return code
+	}
+	if known, ok := knownAirlines[code]; ok {
+		return known.IATA
+	}
+	if _, seen := loggedAirlineMisses.LoadOrStore(code, true); !seen {
+		slog.Info("no IATA code for airline", "icao", code)
+	}
+	return code
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestAirlineIATA(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		code string
+		want string
+	}{
+		{"mapped ICAO", "AAL", "AA"},
+		{"mapped ICAO, lower case", "ual", "UA"},
+		{"mapped ICAO, padded", " DAL ", "DL"},
+		{"unmapped ICAO", "QQX", "QQX"},
+		{"already IATA", "AA", "AA"},
+		{"IATA with a digit", "B6", "B6"},
+		{"IATA, lower case", "dl", "DL"},
+		{"empty", "", ""},
+	} {
+		if got := AirlineIATA(tt.code); got != tt.want {
+			t.Errorf("%s: AirlineIATA(%q) = %q, want %q", tt.name, tt.code, got, tt.want)
+		}
+	}
+}
+
+// TestAirlineIATAMissLogged checks that an unmapped ICAO code is logged the
+// first time it's seen only
+func TestAirlineIATAMissLogged(t *testing.T) {
+	logs := recordLogs(t)
+	for range 3 {
+		AirlineIATA("QZY")
+	}
+	AirlineIATA("AAL")
+	if got := logs.messages(slog.LevelInfo); len(got) != 1 || got[0] != "no IATA code for airline" {
+		t.Errorf("logged %q, want one miss", got)
+	}
+	if icao := logs.attr("no IATA code for airline", "icao"); icao != "QZY" {
+		t.Errorf("miss logged for %q, want QZY", icao)
+	}
+}
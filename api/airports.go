@@ -0,0 +1,127 @@
+package api
+
+import "strings"
+
+// airportICAOCodes maps IATA airport codes to their ICAO equivalents
+var airportICAOCodes = map[string]string{
+	// US East Coast
+	"JFK": "KJFK",
+	"LGA": "KLGA",
+	"EWR": "KEWR",
+	"BOS": "KBOS",
+	"MIA": "KMIA",
+	"ATL": "KATL",
+	"CLT": "KCLT",
+	"DCA": "KDCA",
+	"IAD": "KIAD",
+	"PHL": "KPHL",
+	"BWI": "KBWI",
+
+	// US Central
+	"ORD": "KORD",
+	"MDW": "KMDW",
+	"DFW": "KDFW",
+	"IAH": "KIAH",
+	"MSP": "KMSP",
+	"STL": "KSTL",
+	"DTW": "KDTW",
+	"CLE": "KCLE",
+
+	// US Mountain
+	"DEN": "KDEN",
+	"PHX": "KPHX",
+	"SLC": "KSLC",
+
+	// US West Coast
+	"LAX": "KLAX",
+	"SFO": "KSFO",
+	"SAN": "KSAN",
+	"SEA": "KSEA",
+	"PDX": "KPDX",
+	"LAS": "KLAS",
+
+	// Alaska and Hawaii
+	"ANC": "PANC",
+	"HNL": "PHNL",
+
+	// Europe
+	"LHR": "EGLL",
+	"LGW": "EGKK",
+	"CDG": "LFPG",
+	"FRA": "EDDF",
+	"AMS": "EHAM",
+	"MAD": "LEMD",
+	"FCO": "LIRF",
+	"ZRH": "LSZH",
+	"VIE": "LOWW",
+	"CPH": "EKCH",
+	"ARN": "ESSA",
+	"OSL": "ENGM",
+	"HEL": "EFHK",
+	"DUB": "EIDW",
+	"BRU": "EBBR",
+
+	// Asia
+	"NRT": "RJAA",
+	"HND": "RJTT",
+	"ICN": "RKSI",
+	"PEK": "ZBAA",
+	"PVG": "ZSPD",
+	"HKG": "VHHH",
+	"SIN": "WSSS",
+	"BKK": "VTBS",
+	"DXB": "OMDB",
+	"AUH": "OMAA",
+	"IST": "LTFM",
+
+	// Middle East
+	"TLV": "LLBG",
+	"CAI": "HECA",
+	"JED": "OEJN",
+	"RUH": "OERK",
+
+	// Australia
+	"SYD": "YSSY",
+	"MEL": "YMML",
+	"BNE": "YBBN",
+	"PER": "YPPH",
+
+	// Canada
+	"YYZ": "CYYZ",
+	"YVR": "CYVR",
+	"YUL": "CYUL",
+	"YYC": "CYYC",
+
+	// South America
+	"GRU": "SBGR",
+	"GIG": "SBGL",
+	"EZE": "SAEZ",
+	"SCL": "SCEL",
+	"LIM": "SPJC",
+	"BOG": "SKBO",
+	"MEX": "MMMX",
+}
+
+// AirportICAO returns the ICAO code for an IATA airport code, or false if
+// the airport isn't in the table. A "K" prefix is right only for airports in
+// the contiguous US, so unknown codes aren't guessed
+func AirportICAO(iata string) (string, bool) {
+	icao, ok := airportICAOCodes[strings.ToUpper(iata)]
+	return icao, ok
+}
+
+// AirportIATA returns the IATA code for an ICAO airport code, or the ICAO code
+// itself if no mapping is known
+func AirportIATA(icao string) string {
+	icao = strings.ToUpper(icao)
+	for iata, code := range airportICAOCodes {
+		if code == icao {
+			return iata
+		}
+	}
+	// Contiguous US airports drop the "K" prefix
+	if len(icao) == 4 && strings.HasPrefix(icao, "K") {
+		return icao[1:]
+	}
+	return icao
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"strings"
+
+	"fids-tui/models"
+)
+
+// blockedIdent is the ident AeroAPI lists some blocked flights under, in
+// place of their callsign
+const blockedIdent = "BLOCKED"
+
+// BlockedAirline is the airline name blocked flights are listed under, so
+// the airline filter and summary can show or count them together
+const BlockedAirline = "Blocked"
+
+// isBlocked reports whether AeroAPI withholds a flight's identity because
+// its operator blocked it from public tracking: it is flagged as blocked,
+// or listed under the ident "BLOCKED" with whatever else left blank
+func isBlocked(blocked bool, ident string) bool {
+	return blocked || strings.EqualFold(strings.TrimSpace(ident), blockedIdent)
+}
+
+// withheld returns what the board keeps of a blocked flight: its ID, to
+// follow it across updates, and its times and status. Anything naming the
+// flight, its operator, route or gate is dropped, however much of it
+// AeroAPI sent
+func withheld(flight models.Flight) models.Flight {
+	return models.Flight{
+		ID:                 flight.ID,
+		Direction:          flight.Direction,
+		Status:             flight.Status,
+		AirlineName:        BlockedAirline,
+		Remarks:            flight.Remarks,
+		ScheduledDeparture: flight.ScheduledDeparture,
+		EstimatedDeparture: flight.EstimatedDeparture,
+		ActualOut:          flight.ActualOut,
+		ActualOff:          flight.ActualOff,
+		ScheduledArrival:   flight.ScheduledArrival,
+		EstimatedArrival:   flight.EstimatedArrival,
+		Blocked:            true,
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerThreshold = 3
+	defaultBreakerCooldown  = 30 * time.Minute
+)
+
+// CircuitBreaker stops calling a failing provider after a number of consecutive
+// failures and lets a single trial request through once the cooldown has elapsed,
+// holding back every other caller until the trial is recorded.
+// The cooldowns follow a RetrySchedule of Backoff, the same pacing as a
+// board's first fetches, then Cooldown once Backoff runs out
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+	Backoff   []time.Duration  // Cooldowns after the breaker opens and each failed trial, nil for Cooldown throughout
+	Now       func() time.Time // Clock timing the cooldown; time.Now if nil
+
+	failures int
+	openedAt time.Time
+	wait     time.Duration  // Cooldown since openedAt before the next trial
+	trials   *RetrySchedule // Cooldowns while open, nil while closed
+	trial    bool           // A trial request is under way
+	mu       sync.Mutex
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after threshold consecutive failures
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+	}
+}
+
+// Allow reports whether a request to the protected provider should be attempted.
+// Once open, only the first caller after the cooldown is allowed, and must
+// record the outcome with RecordSuccess or RecordFailure
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.openedAt.IsZero() {
+		return true
+	}
+	// Half-open: allow one trial request once the cooldown has elapsed
+	if cb.trial || cb.now().Sub(cb.openedAt) < cb.wait {
+		return false
+	}
+	cb.trial = true
+	return true
+}
+
+// IsOpen reports whether the breaker is currently open
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return !cb.openedAt.IsZero()
+}
+
+// RecordSuccess closes the breaker and resets the failure count
+func (cb *CircuitBreaker) RecordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+	cb.openedAt = time.Time{}
+	cb.trials = nil
+	cb.trial = false
+}
+
+// RecordFailure counts a failure and opens the breaker once the threshold is reached
+func (cb *CircuitBreaker) RecordFailure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	cb.trial = false
+	if cb.failures >= cb.Threshold {
+		// (Re)open the breaker, restarting the cooldown after a failed trial
+		if cb.trials == nil {
+			cb.trials = NewRetrySchedule(cb.Backoff)
+		}
+		wait, ok := cb.trials.Failed()
+		if !ok {
+			wait = cb.Cooldown
+		}
+		cb.openedAt, cb.wait = cb.now(), wait
+	}
+}
+
+// now returns the breaker's clock reading
+func (cb *CircuitBreaker) now() time.Time {
+	if cb.Now != nil {
+		return cb.Now()
+	}
+	return time.Now()
+}
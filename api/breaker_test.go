@@ -0,0 +1,124 @@
+package api
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// breakerAt returns a breaker opening after two failures, timed by a clock
+// the caller moves on
+func breakerAt(now *time.Time, backoff ...time.Duration) *CircuitBreaker {
+	cb := NewCircuitBreaker(2, 30*time.Minute)
+	cb.Backoff = backoff
+	cb.Now = func() time.Time { return *now }
+	return cb
+}
+
+// TestBreakerBackoff checks the cooldowns before each trial follow the
+// breaker's retry schedule, then Cooldown, and start over once a trial
+// succeeds
+func TestBreakerBackoff(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cb := breakerAt(&now, time.Minute, 5*time.Minute)
+	cb.RecordFailure()
+	if !cb.Allow() || cb.IsOpen() {
+		t.Fatal("breaker open below its threshold")
+	}
+	cb.RecordFailure()
+	for _, wait := range []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 30 * time.Minute} {
+		if !cb.IsOpen() {
+			t.Fatal("breaker closed after a failure")
+		}
+		now = now.Add(wait - time.Second)
+		if cb.Allow() {
+			t.Errorf("trial allowed %s before its %s cooldown elapsed", time.Second, wait)
+		}
+		now = now.Add(time.Second)
+		if !cb.Allow() {
+			t.Errorf("no trial after its %s cooldown", wait)
+		}
+		cb.RecordFailure()
+	}
+
+	cb.RecordSuccess()
+	if cb.IsOpen() || !cb.Allow() {
+		t.Fatal("breaker open after a successful trial")
+	}
+	cb.RecordFailure()
+	cb.RecordFailure()
+	now = now.Add(time.Minute)
+	if !cb.Allow() {
+		t.Error("schedule didn't start over after a successful trial")
+	}
+}
+
+// TestBreakerCooldown checks that a breaker without a schedule waits
+// Cooldown before every trial
+func TestBreakerCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cb := breakerAt(&now)
+	cb.RecordFailure()
+	cb.RecordFailure()
+	for range 2 {
+		now = now.Add(29 * time.Minute)
+		if cb.Allow() {
+			t.Error("trial allowed before the cooldown elapsed")
+		}
+		now = now.Add(time.Minute)
+		if !cb.Allow() {
+			t.Error("no trial after the cooldown")
+		}
+		cb.RecordFailure()
+	}
+}
+
+// TestBreakerSingleTrial checks that of callers racing once the cooldown has
+// elapsed, only one makes the trial request, and the rest are held back until
+// its outcome is recorded
+func TestBreakerSingleTrial(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cb := breakerAt(&now)
+	cb.RecordFailure()
+	cb.RecordFailure()
+	now = now.Add(30 * time.Minute)
+
+	allowed := func() int {
+		var wg sync.WaitGroup
+		var n atomic.Int32
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if cb.Allow() {
+					n.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		return int(n.Load())
+	}
+	if n := allowed(); n != 1 {
+		t.Fatalf("%d trials allowed after the cooldown, want 1", n)
+	}
+	if n := allowed(); n != 0 {
+		t.Errorf("%d more trials allowed while the first was under way", n)
+	}
+
+	// A failed trial restarts the cooldown, with no trial under way
+	cb.RecordFailure()
+	if n := allowed(); n != 0 {
+		t.Errorf("%d trials allowed straight after a failed trial", n)
+	}
+	now = now.Add(30 * time.Minute)
+	if n := allowed(); n != 1 {
+		t.Errorf("%d trials allowed after the next cooldown, want 1", n)
+	}
+
+	// A successful trial closes the breaker to everyone
+	cb.RecordSuccess()
+	if n := allowed(); n != 50 {
+		t.Errorf("%d of 50 callers allowed once closed", n)
+	}
+}
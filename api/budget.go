@@ -0,0 +1,189 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+// CallKind is a kind of API call drawing on a Budget
+type CallKind string
+
+const (
+	CallBoard      CallKind = "board"      // The flights of a board on screen
+	CallBackground CallKind = "background" // The flights of a tab not on screen
+	CallInbound    CallKind = "inbound"    // Looking up the aircraft flying in to operate a delayed departure
+	CallTracking   CallKind = "tracking"   // Following a watched flight after it left the board
+)
+
+// budgetWindow is the span a Budget counts calls over
+const budgetWindow = time.Hour
+
+// Budget allots API calls within a cap per hour. Each kind of call is
+// registered with a priority, 0 being the most important, and a cadence.
+// Calls of priority 0 are always made; any other call is made only if it
+// leaves room for the calls the more important kinds are still expected to
+// make in the hour, and is deferred otherwise. A nil Budget allows every
+// call. It is safe for concurrent use
+type Budget struct {
+	MaxPerHour int
+	Now        func() time.Time // The clock, replaced to simulate an hour
+
+	mu       sync.Mutex
+	kinds    map[CallKind]budgetKind
+	calls    []budgetCall // Calls made in the last hour, oldest first
+	deferred []budgetCall // Calls deferred in the last hour, oldest first
+}
+
+// budgetKind is how a kind of call was registered
+type budgetKind struct {
+	priority int
+	perHour  int // Calls expected an hour
+}
+
+// budgetCall is a number of calls made, or deferred, at a time
+type budgetCall struct {
+	at   time.Time
+	kind CallKind
+	n    int
+}
+
+// NewBudget creates a budget of maxPerHour calls an hour, or nil to allow
+// every call when maxPerHour isn't positive
+func NewBudget(maxPerHour int) *Budget {
+	if maxPerHour <= 0 {
+		return nil
+	}
+	return &Budget{MaxPerHour: maxPerHour, Now: time.Now, kinds: make(map[CallKind]budgetKind)}
+}
+
+// Register sets the priority of a kind of call and how many calls it makes
+// each time it falls due, every cadence. A cadence of zero expects no
+// calls, so none are kept back for the kind. Kinds not registered have the
+// lowest priority
+func (b *Budget) Register(kind CallKind, priority int, cadence time.Duration, calls int) {
+	if b == nil {
+		return
+	}
+	perHour := 0
+	if cadence > 0 {
+		perHour = int(math.Ceil(float64(budgetWindow)/float64(cadence))) * calls
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.kinds[kind] = budgetKind{priority: priority, perHour: perHour}
+}
+
+// Allow reports whether n calls of kind may be made now, counting them
+// against the budget if so
+func (b *Budget) Allow(kind CallKind, n int) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.Now()
+	b.prune(now)
+	priority := b.priority(kind)
+	used := b.used("")
+	if priority > 0 {
+		if reserved := b.reserved(priority); used+reserved+n > b.MaxPerHour {
+			b.deferred = append(b.deferred, budgetCall{at: now, kind: kind, n: n})
+			slog.Info("API call deferred to stay within budget", "kind", kind, "calls", n,
+				"used", used, "kept_back", reserved, "max_per_hour", b.MaxPerHour)
+			return false
+		}
+	} else if used+n > b.MaxPerHour {
+		slog.Warn("API budget exceeded by the board's own fetches", "kind", kind, "used", used, "max_per_hour", b.MaxPerHour)
+	}
+	b.calls = append(b.calls, budgetCall{at: now, kind: kind, n: n})
+	return true
+}
+
+// priority returns the priority kind was registered with
+func (b *Budget) priority(kind CallKind) int {
+	if k, ok := b.kinds[kind]; ok {
+		return k.priority
+	}
+	return math.MaxInt
+}
+
+// used returns the calls of kind made in the last hour, or of every kind
+// for ""
+func (b *Budget) used(kind CallKind) int {
+	total := 0
+	for _, c := range b.calls {
+		if kind == "" || c.kind == kind {
+			total += c.n
+		}
+	}
+	return total
+}
+
+// reserved returns the calls kept back for kinds more important than
+// priority: those they are expected to make in the hour and haven't yet
+func (b *Budget) reserved(priority int) int {
+	total := 0
+	for kind, k := range b.kinds {
+		if k.priority < priority {
+			total += max(0, k.perHour-b.used(kind))
+		}
+	}
+	return total
+}
+
+// prune forgets calls older than the window
+func (b *Budget) prune(now time.Time) {
+	cutoff := now.Add(-budgetWindow)
+	drop := func(calls []budgetCall) []budgetCall {
+		i := 0
+		for i < len(calls) && !calls[i].at.After(cutoff) {
+			i++
+		}
+		return calls[i:]
+	}
+	b.calls = drop(b.calls)
+	b.deferred = drop(b.deferred)
+}
+
+// Used returns the calls made in the last hour
+func (b *Budget) Used() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(b.Now())
+	return b.used("")
+}
+
+// Deferred returns the calls deferred in the last hour
+func (b *Budget) Deferred() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.prune(b.Now())
+	total := 0
+	for _, c := range b.deferred {
+		total += c.n
+	}
+	return total
+}
+
+// Status describes the budget for the status bar, e.g. "API 12/60 calls/h"
+// or "API 58/60 calls/h, 3 deferred", or "" for a nil Budget
+func (b *Budget) Status() string {
+	if b == nil {
+		return ""
+	}
+	status := fmt.Sprintf("API %d/%d calls/h", b.Used(), b.MaxPerHour)
+	if deferred := b.Deferred(); deferred > 0 {
+		status += fmt.Sprintf(", %d deferred", deferred)
+	}
+	return status
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+// budgetStart is the fake clock's reading when a simulated hour begins
+var budgetStart = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// budgetAt returns a budget of maxPerHour calls whose clock reads *now
+func budgetAt(maxPerHour int, now *time.Time) *Budget {
+	budget := NewBudget(maxPerHour)
+	budget.Now = func() time.Time { return *now }
+	return budget
+}
+
+// budgetCaller makes calls of a kind every cadence through a simulation
+type budgetCaller struct {
+	kind    CallKind
+	cadence time.Duration
+	calls   int // Calls made each time the kind falls due
+}
+
+// simulateBudget runs callers against budget minute by minute for hours on
+// the fake clock now, in the order given within each minute, and returns the
+// calls made and deferred of each kind in each hour. Every minute the calls
+// made in the last hour must be within the budget, unless the boards' own
+// fetches alone are over it
+func simulateBudget(t *testing.T, budget *Budget, now *time.Time, hours int, callers []budgetCaller) (made, deferred []map[CallKind]int) {
+	t.Helper()
+	for hour := range hours {
+		made = append(made, make(map[CallKind]int))
+		deferred = append(deferred, make(map[CallKind]int))
+		for minute := range 60 {
+			*now = budgetStart.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+			for _, c := range callers {
+				if (time.Duration(minute)*time.Minute)%c.cadence != 0 {
+					continue
+				}
+				if budget.Allow(c.kind, c.calls) {
+					made[hour][c.kind] += c.calls
+				} else {
+					deferred[hour][c.kind] += c.calls
+				}
+			}
+			if used := budget.Used(); used > budget.MaxPerHour && used > budget.used(CallBoard) {
+				t.Fatalf("%s: %d calls in the last hour, over the budget of %d", now.Format("15:04"), used, budget.MaxPerHour)
+			}
+		}
+	}
+	return made, deferred
+}
+
+// TestBudgetSimulatedHour checks, over two simulated hours, that the boards
+// on screen make every fetch, background tabs come next, and lookups that
+// only add detail get what is left and are deferred, and logged, once the
+// budget is tight, even when they ask first
+func TestBudgetSimulatedHour(t *testing.T) {
+	logs := recordLogs(t)
+	now := budgetStart
+	budget := budgetAt(40, &now)
+	budget.Register(CallBoard, 0, 5*time.Minute, 1)       // 12 calls an hour
+	budget.Register(CallBackground, 1, 10*time.Minute, 2) // 12 calls an hour
+	budget.Register(CallInbound, 2, 5*time.Minute, 3)     // 36 calls an hour
+	budget.Register(CallTracking, 3, 15*time.Minute, 1)   // 4 calls an hour
+	callers := []budgetCaller{
+		{CallInbound, 5 * time.Minute, 1},
+		{CallInbound, 5 * time.Minute, 1},
+		{CallInbound, 5 * time.Minute, 1},
+		{CallTracking, 15 * time.Minute, 1},
+		{CallBackground, 10 * time.Minute, 2},
+		{CallBoard, 5 * time.Minute, 1},
+	}
+	made, deferred := simulateBudget(t, budget, &now, 2, callers)
+
+	for hour := range made {
+		if made[hour][CallBoard] != 12 || deferred[hour][CallBoard] != 0 {
+			t.Errorf("hour %d: board made %d and deferred %d calls, want all 12 made", hour, made[hour][CallBoard], deferred[hour][CallBoard])
+		}
+		if made[hour][CallBackground] != 12 || deferred[hour][CallBackground] != 0 {
+			t.Errorf("hour %d: background made %d and deferred %d calls, want all 12 made", hour, made[hour][CallBackground], deferred[hour][CallBackground])
+		}
+		// 16 calls are left for the lookups, inbound first
+		if made[hour][CallInbound] != 16 || deferred[hour][CallInbound] != 20 {
+			t.Errorf("hour %d: inbound made %d and deferred %d calls, want 16 and 20", hour, made[hour][CallInbound], deferred[hour][CallInbound])
+		}
+		if made[hour][CallTracking] != 0 || deferred[hour][CallTracking] != 4 {
+			t.Errorf("hour %d: tracking made %d and deferred %d calls, want all 4 deferred", hour, made[hour][CallTracking], deferred[hour][CallTracking])
+		}
+	}
+
+	// Every deferral is logged with its kind, and the board never over-ran
+	deferrals := 0
+	for _, message := range logs.messages(slog.LevelInfo) {
+		if message == "API call deferred to stay within budget" {
+			deferrals++
+		}
+	}
+	if want := 2 * (20 + 4); deferrals != want {
+		t.Errorf("%d deferrals logged, want %d", deferrals, want)
+	}
+	// Tracking, the least important, is the first turned away
+	if got := logs.attr("API call deferred to stay within budget", "kind"); got != string(CallTracking) {
+		t.Errorf("first deferral logged for %q, want tracking", got)
+	}
+	if slices.Contains(logs.messages(slog.LevelWarn), "API budget exceeded by the board's own fetches") {
+		t.Error("board fetches exceeded a budget with room for them")
+	}
+	if got, want := budget.Status(), "API 40/40 calls/h, 24 deferred"; got != want {
+		t.Errorf("Status = %q, want %q", got, want)
+	}
+}
+
+// TestBudgetRoomAfterSpike checks that calls deferred in a busy stretch are
+// made again once the calls that filled the budget are an hour old
+func TestBudgetRoomAfterSpike(t *testing.T) {
+	now := budgetStart
+	budget := budgetAt(10, &now)
+	budget.Register(CallBoard, 0, time.Hour, 4)
+	budget.Register(CallInbound, 2, time.Hour, 1)
+
+	// A burst of lookups takes what the board doesn't keep back
+	made := 0
+	for range 10 {
+		if budget.Allow(CallInbound, 1) {
+			made++
+		}
+	}
+	if made != 6 {
+		t.Fatalf("%d lookups made in the burst, want the 6 the board leaves", made)
+	}
+	if !budget.Allow(CallBoard, 4) {
+		t.Fatal("board fetch deferred")
+	}
+	now = now.Add(30 * time.Minute)
+	if budget.Allow(CallInbound, 1) {
+		t.Error("lookup made half an hour into a full budget")
+	}
+	now = budgetStart.Add(time.Hour + time.Second)
+	if !budget.Allow(CallInbound, 1) {
+		t.Error("lookup deferred an hour after the burst")
+	}
+	if got := budget.Deferred(); got != 1 {
+		t.Errorf("Deferred = %d an hour on, want only the one at half past", got)
+	}
+}
+
+// TestBudgetTight checks that with a budget too small for the boards alone
+// they still fetch, with a warning, and nothing else is made
+func TestBudgetTight(t *testing.T) {
+	logs := recordLogs(t)
+	now := budgetStart
+	budget := budgetAt(6, &now)
+	budget.Register(CallBoard, 0, 5*time.Minute, 1)
+	budget.Register(CallBackground, 1, 10*time.Minute, 1)
+	made, deferred := simulateBudget(t, budget, &now, 1, []budgetCaller{
+		{CallBackground, 10 * time.Minute, 1},
+		{CallBoard, 5 * time.Minute, 1},
+	})
+	if made[0][CallBoard] != 12 {
+		t.Errorf("board made %d calls, want all 12 whatever the budget", made[0][CallBoard])
+	}
+	if made[0][CallBackground] != 0 || deferred[0][CallBackground] != 6 {
+		t.Errorf("background made %d and deferred %d calls, want all 6 deferred", made[0][CallBackground], deferred[0][CallBackground])
+	}
+	if !slices.Contains(logs.messages(slog.LevelWarn), "API budget exceeded by the board's own fetches") {
+		t.Error("board fetches over the budget weren't warned about")
+	}
+}
+
+func TestNilBudget(t *testing.T) {
+	budget := NewBudget(0)
+	if budget != nil {
+		t.Fatal("NewBudget(0) isn't nil")
+	}
+	budget.Register(CallBoard, 0, time.Minute, 1)
+	if !budget.Allow(CallInbound, 100) || budget.Used() != 0 || budget.Deferred() != 0 || budget.Status() != "" {
+		t.Error("nil budget doesn't allow every call without counting")
+	}
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Data files hold fresher airline and airport tables than the built-in ones,
+// downloaded by UpdateData from the OpenFlights database
+const (
+	airlinesFile = "airlines.json"
+	airportsFile = "airports.json"
+
+	airlinesURL = "https://raw.githubusercontent.com/jpatokal/openflights/master/data/airlines.dat"
+	airportsURL = "https://raw.githubusercontent.com/jpatokal/openflights/master/data/airports.dat"
+
+	// airlinesColumns and airportsColumns are the columns every row of the
+	// OpenFlights files has
+	airlinesColumns = 8  // ID, name, alias, IATA, ICAO, callsign, country, active
+	airportsColumns = 14 // ID, name, city, country, IATA, ICAO, latitude, longitude, altitude, offset, DST, timezone, type, source
+
+	// minAirlines and minAirports are fewer entries than the database has
+	// ever had; a smaller table means a truncated or wrong download
+	minAirlines = 500
+	minAirports = 3000
+
+	// maxDataSize bounds a download, a few times the size of the files
+	maxDataSize = 32 << 20
+)
+
+// builtinDataDate is when the built-in airline and airport tables were last
+// brought up to date. Data files written before then are older and ignored
+var builtinDataDate = time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+
+// airlinesData is the format of the airlines data file
+type airlinesData struct {
+	Updated  time.Time          `json:"updated"`
+	Source   string             `json:"source"`
+	Airlines map[string]airline `json:"airlines"` // By ICAO designator
+}
+
+// airport is an entry of the airports data file
+type airport struct {
+	ICAO     string    `json:"icao"`
+	TimeZone string    `json:"tz,omitempty"`       // IANA timezone, if known
+	Position *location `json:"position,omitempty"` // Latitude and longitude, if known
+}
+
+// airportsData is the format of the airports data file
+type airportsData struct {
+	Updated  time.Time          `json:"updated"`
+	Source   string             `json:"source"`
+	Airports map[string]airport `json:"airports"` // By IATA code
+}
+
+// dataFile is implemented by the formats of the data files
+type dataFile interface {
+	// validate checks that the table is complete enough to be used
+	validate() error
+	// updatedAt returns when the file was written
+	updatedAt() time.Time
+}
+
+func (d *airlinesData) updatedAt() time.Time { return d.Updated }
+func (d *airportsData) updatedAt() time.Time { return d.Updated }
+
+func (d *airlinesData) validate() error {
+	if len(d.Airlines) < minAirlines {
+		return fmt.Errorf("only %d airlines, expected at least %d", len(d.Airlines), minAirlines)
+	}
+	for icao, entry := range d.Airlines {
+		if !isCode(icao, 3) || !isCode(entry.IATA, 2) {
+			return fmt.Errorf("invalid airline %q (%q)", icao, entry.IATA)
+		}
+	}
+	return nil
+}
+
+func (d *airportsData) validate() error {
+	if len(d.Airports) < minAirports {
+		return fmt.Errorf("only %d airports, expected at least %d", len(d.Airports), minAirports)
+	}
+	for iata, entry := range d.Airports {
+		if !isCode(iata, 3) || !isCode(entry.ICAO, 4) {
+			return fmt.Errorf("invalid airport %q (%q)", iata, entry.ICAO)
+		}
+	}
+	return nil
+}
+
+// isCode reports whether s is an uppercase code of n letters and digits
+func isCode(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadData adds the airlines and airports of the data files in dir to the
+// built-in tables, replacing built-in entries for the same codes. Missing
+// files and files older than the built-in tables are skipped, as are
+// unreadable ones, which are reported in the error. Call it before looking
+// anything up, as the tables aren't locked
+func LoadData(dir string) error {
+	var errs []error
+	var airlines airlinesData
+	if ok, err := readDataFile(filepath.Join(dir, airlinesFile), &airlines); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		maps.Copy(knownAirlines, airlines.Airlines)
+		slog.Info("loaded airline data", "airlines", len(airlines.Airlines), "updated", airlines.Updated)
+	}
+
+	var airports airportsData
+	if ok, err := readDataFile(filepath.Join(dir, airportsFile), &airports); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		for iata, entry := range airports.Airports {
+			airportICAOCodes[iata] = entry.ICAO
+			if entry.TimeZone != "" {
+				airportTimezones[iata] = entry.TimeZone
+			}
+			if entry.Position != nil {
+				airportLocations[iata] = *entry.Position
+			}
+		}
+		slog.Info("loaded airport data", "airports", len(airports.Airports), "updated", airports.Updated)
+	}
+	return errors.Join(errs...)
+}
+
+// readDataFile decodes the data file at path into data and validates it,
+// reporting whether it should be used: it exists and was written after the
+// built-in tables
+func readDataFile(path string, data dataFile) (bool, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read data file: %w", err)
+	}
+	if err := json.Unmarshal(raw, data); err != nil {
+		return false, fmt.Errorf("failed to parse data file %s: %w", path, err)
+	}
+	if err := data.validate(); err != nil {
+		return false, fmt.Errorf("invalid data file %s: %w", path, err)
+	}
+	if updated := data.updatedAt(); !updated.After(builtinDataDate) {
+		slog.Info("data file older than built-in data, ignored", "path", path, "updated", updated)
+		return false, nil
+	}
+	return true, nil
+}
+
+// DataUpdate counts the entries of the data files written by UpdateData
+type DataUpdate struct {
+	Airlines int
+	Airports int
+}
+
+// UpdateData downloads the OpenFlights airline and airport databases with
+// client, converts them and writes them as data files to dir, for LoadData.
+// Both downloads are checked before either file is written, and each file is
+// written to a temporary file, read back and validated before it replaces
+// the old one, so a bad download never replaces working data
+func UpdateData(ctx context.Context, client *http.Client, dir string) (DataUpdate, error) {
+	now := time.Now().UTC()
+	airlineRows, err := downloadCSV(ctx, client, airlinesURL, airlinesColumns)
+	if err != nil {
+		return DataUpdate{}, fmt.Errorf("airlines: %w", err)
+	}
+	airlines := convertAirlines(airlineRows, now)
+	if err := airlines.validate(); err != nil {
+		return DataUpdate{}, fmt.Errorf("airlines: %w", err)
+	}
+
+	airportRows, err := downloadCSV(ctx, client, airportsURL, airportsColumns)
+	if err != nil {
+		return DataUpdate{}, fmt.Errorf("airports: %w", err)
+	}
+	airports := convertAirports(airportRows, now)
+	if err := airports.validate(); err != nil {
+		return DataUpdate{}, fmt.Errorf("airports: %w", err)
+	}
+
+	if err := writeDataFile(dir, airlinesFile, airlines, &airlinesData{}); err != nil {
+		return DataUpdate{}, err
+	}
+	if err := writeDataFile(dir, airportsFile, airports, &airportsData{}); err != nil {
+		return DataUpdate{}, err
+	}
+	return DataUpdate{Airlines: len(airlines.Airlines), Airports: len(airports.Airports)}, nil
+}
+
+// downloadCSV fetches the CSV file at url, returning its rows. Rows without
+// the expected number of columns are dropped, but a file where more than one
+// in a hundred rows is malformed is rejected as a whole
+func downloadCSV(ctx context.Context, client *http.Client, url string, columns int) ([][]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	reader := csv.NewReader(io.LimitReader(resp.Body, maxDataSize))
+	reader.FieldsPerRecord = columns
+	reader.LazyQuotes = true
+	var rows [][]string
+	malformed := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, csv.ErrFieldCount) {
+			malformed++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse download: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if malformed*100 > len(rows)+malformed {
+		return nil, fmt.Errorf("%d of %d rows don't have %d columns", malformed, len(rows)+malformed, columns)
+	}
+	return rows, nil
+}
+
+// openFlightsValue returns a column value, empty for the database's null \N
+func openFlightsValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == `\N` || value == "-" {
+		return ""
+	}
+	return value
+}
+
+// convertAirlines builds the airline table from the rows of airlines.dat,
+// keeping active airlines with both an IATA and an ICAO code. When several
+// share an ICAO code the first is kept
+func convertAirlines(rows [][]string, updated time.Time) *airlinesData {
+	data := &airlinesData{Updated: updated, Source: airlinesURL, Airlines: make(map[string]airline)}
+	for _, row := range rows {
+		name := openFlightsValue(row[1])
+		iata := strings.ToUpper(openFlightsValue(row[3]))
+		icao := strings.ToUpper(openFlightsValue(row[4]))
+		if openFlightsValue(row[7]) != "Y" || name == "" || !isCode(iata, 2) || !isCode(icao, 3) {
+			continue
+		}
+		if _, dup := data.Airlines[icao]; !dup {
+			data.Airlines[icao] = airline{IATA: iata, Name: name}
+		}
+	}
+	return data
+}
+
+// convertAirports builds the airport table from the rows of airports.dat,
+// keeping airports with both an IATA and an ICAO code
+func convertAirports(rows [][]string, updated time.Time) *airportsData {
+	data := &airportsData{Updated: updated, Source: airportsURL, Airports: make(map[string]airport)}
+	for _, row := range rows {
+		iata := strings.ToUpper(openFlightsValue(row[4]))
+		icao := strings.ToUpper(openFlightsValue(row[5]))
+		if !isCode(iata, 3) || !isCode(icao, 4) {
+			continue
+		}
+		if _, dup := data.Airports[iata]; !dup {
+			data.Airports[iata] = airport{ICAO: icao, TimeZone: openFlightsValue(row[11]), Position: parsePosition(row[6], row[7])}
+		}
+	}
+	return data
+}
+
+// parsePosition returns the position in the latitude and longitude columns
+// of airports.dat, or nil if either is missing or out of range
+func parsePosition(lat, lon string) *location {
+	latitude, err := strconv.ParseFloat(openFlightsValue(lat), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return nil
+	}
+	longitude, err := strconv.ParseFloat(openFlightsValue(lon), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return nil
+	}
+	return &location{Lat: latitude, Lon: longitude}
+}
+
+// writeDataFile writes data as the data file name in dir. It is written to a
+// temporary file first, which is read back into check and must be valid
+// before it replaces the file
+func writeDataFile(dir, name string, data, check dataFile) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+strings.TrimSuffix(name, ".json")+"-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := readDataFile(tmp.Name(), check); err != nil {
+		return fmt.Errorf("%s not replaced: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
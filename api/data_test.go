@@ -0,0 +1,339 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// keepTables restores the built-in airline and airport tables once the test
+// is over, undoing LoadData
+func keepTables(t *testing.T) {
+	t.Helper()
+	airlines, icao := maps.Clone(knownAirlines), maps.Clone(airportICAOCodes)
+	zones, locations := maps.Clone(airportTimezones), maps.Clone(airportLocations)
+	t.Cleanup(func() {
+		knownAirlines, airportICAOCodes = airlines, icao
+		airportTimezones, airportLocations = zones, locations
+	})
+}
+
+// fillerCode returns the n-character code of letters and digits numbered i
+func fillerCode(i, n int) string {
+	code := make([]byte, n)
+	for j := n - 1; j >= 0; j-- {
+		code[j] = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"[i%36]
+		i /= 36
+	}
+	return string(code)
+}
+
+// openFlightsFile returns the fixture file in testdata/openflights followed
+// by rows of made-up, digit-coded entries, so the download is as big as
+// the real database must be
+func openFlightsFile(t *testing.T, name string, rows int) string {
+	t.Helper()
+	fixture, err := os.ReadFile(filepath.Join("testdata", "openflights", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	b.Write(fixture)
+	for i := range rows {
+		switch name {
+		case "airlines.dat":
+			fmt.Fprintf(&b, "%d,\"Filler %d\",\\N,\"%s\",\"0%s\",\\N,\"Nowhere\",\"Y\"\n", 10000+i, i, fillerCode(i, 2), fillerCode(i, 2))
+		case "airports.dat":
+			fmt.Fprintf(&b, "%d,\"Filler %d\",\"Nowhere\",\"Nowhere\",\"%s\",\"0%s\",0,0,0,0,\"U\",\"Etc/UTC\",\"airport\",\"OurAirports\"\n", 10000+i, i, fillerCode(i, 3), fillerCode(i, 3))
+		}
+	}
+	return b.String()
+}
+
+// openFlightsServer serves the OpenFlights downloads from files, by name,
+// failing those not given, and returns a client whose requests for the
+// database go to it
+func openFlightsServer(t *testing.T, files map[string]string) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[filepath.Base(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+	return &http.Client{Transport: redirectTransport{target}}
+}
+
+// redirectTransport sends every request to target instead of its host
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = rt.target.Scheme, rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// goodDownloads returns the fixture downloads padded to a complete database
+func goodDownloads(t *testing.T) map[string]string {
+	t.Helper()
+	return map[string]string{
+		"airlines.dat": openFlightsFile(t, "airlines.dat", minAirlines),
+		"airports.dat": openFlightsFile(t, "airports.dat", minAirports),
+	}
+}
+
+// TestUpdateData downloads the fixture database and loads the data files it
+// writes, checking which rows became entries
+func TestUpdateData(t *testing.T) {
+	keepTables(t)
+	dir := filepath.Join(t.TempDir(), "data")
+	update, err := UpdateData(context.Background(), openFlightsServer(t, goodDownloads(t)), dir)
+	if err != nil {
+		t.Fatalf("UpdateData: %v", err)
+	}
+	// American, Delta and Example Air, leaving out the unknown, the inactive
+	// and the one without an IATA code; Example Air's second row is a duplicate
+	if want := (DataUpdate{Airlines: minAirlines + 3, Airports: minAirports + 3}); update != want {
+		t.Errorf("UpdateData wrote %+v, want %+v", update, want)
+	}
+	if err := LoadData(dir); err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+
+	if got := AirlineIATA("XAX"); got != "X9" {
+		t.Errorf("AirlineIATA(XAX) = %q, want X9 from its first row", got)
+	}
+	for _, icao := range []string{"TWA", "NCA"} {
+		if _, ok := knownAirlines[icao]; ok {
+			t.Errorf("airline %s loaded", icao)
+		}
+	}
+	if icao, ok := AirportICAO("XEX"); !ok || icao != "KXEX" {
+		t.Errorf("AirportICAO(XEX) = %q, %v; want KXEX", icao, ok)
+	}
+	if zone := GetAirportTimezone("XEX").String(); zone != "America/Los_Angeles" {
+		t.Errorf("XEX in %s, want America/Los_Angeles", zone)
+	}
+	if position := airportLocations["XEX"]; position != (location{Lat: 45.5, Lon: -122.5}) {
+		t.Errorf("XEX at %+v", position)
+	}
+	// \N leaves the time zone and position unknown rather than empty
+	if _, ok := AirportICAO("XUT"); !ok {
+		t.Error("airport without a time zone or position not loaded")
+	}
+	if zone, ok := airportTimezones["XUT"]; ok {
+		t.Errorf("XUT in time zone %q, want none", zone)
+	}
+	if position, ok := airportLocations["XUT"]; ok {
+		t.Errorf("XUT at %+v, want no position", position)
+	}
+}
+
+// TestLoadDataPrecedence checks that entries of a data file replace the
+// built-in ones for the same codes, unless the file is older than they are
+func TestLoadDataPrecedence(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		updated time.Time
+		want    string
+	}{
+		{"newer file", builtinDataDate.Add(24 * time.Hour), "A1"},
+		{"file older than the built-in data", builtinDataDate.Add(-24 * time.Hour), "AA"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			keepTables(t)
+			dir := t.TempDir()
+			airlines := fillerAirlines(tt.updated)
+			airlines.Airlines["AAL"] = airline{IATA: "A1", Name: "American Airlines"}
+			airports := fillerAirports(tt.updated)
+			airports.Airports["JFK"] = airport{ICAO: "KJFK", TimeZone: "America/Chicago"}
+			if err := writeDataFile(dir, airlinesFile, airlines, &airlinesData{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := writeDataFile(dir, airportsFile, airports, &airportsData{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := LoadData(dir); err != nil {
+				t.Fatalf("LoadData: %v", err)
+			}
+			if got := AirlineIATA("AAL"); got != tt.want {
+				t.Errorf("AirlineIATA(AAL) = %q, want %q", got, tt.want)
+			}
+			zone := GetAirportTimezone("JFK").String()
+			if replaced := zone == "America/Chicago"; replaced != (tt.want == "A1") {
+				t.Errorf("JFK in %s", zone)
+			}
+		})
+	}
+}
+
+// TestLoadDataMissing checks that a data directory without data files
+// leaves the built-in tables as they are
+func TestLoadDataMissing(t *testing.T) {
+	keepTables(t)
+	if err := LoadData(filepath.Join(t.TempDir(), "none")); err != nil {
+		t.Errorf("LoadData of a missing directory: %v", err)
+	}
+	if got := AirlineIATA("AAL"); got != "AA" {
+		t.Errorf("AirlineIATA(AAL) = %q, want the built-in AA", got)
+	}
+}
+
+// fillerAirlines returns a complete airline table of made-up entries
+func fillerAirlines(updated time.Time) *airlinesData {
+	data := &airlinesData{Updated: updated, Airlines: make(map[string]airline)}
+	for i := range minAirlines {
+		data.Airlines["0"+fillerCode(i, 2)] = airline{IATA: fillerCode(i, 2), Name: "Filler"}
+	}
+	return data
+}
+
+// fillerAirports returns a complete airport table of made-up entries
+func fillerAirports(updated time.Time) *airportsData {
+	data := &airportsData{Updated: updated, Airports: make(map[string]airport)}
+	for i := range minAirports {
+		data.Airports[fillerCode(i, 3)] = airport{ICAO: "0" + fillerCode(i, 3)}
+	}
+	return data
+}
+
+func TestDataValidate(t *testing.T) {
+	updated := builtinDataDate.Add(time.Hour)
+	for _, tt := range []struct {
+		name    string
+		data    func() dataFile
+		wantErr string
+	}{
+		{"complete airlines", func() dataFile { return fillerAirlines(updated) }, ""},
+		{"complete airports", func() dataFile { return fillerAirports(updated) }, ""},
+		{"too few airlines", func() dataFile {
+			return &airlinesData{Airlines: map[string]airline{"AAL": {IATA: "AA"}}}
+		}, "only 1 airlines"},
+		{"too few airports", func() dataFile {
+			return &airportsData{Airports: map[string]airport{"JFK": {ICAO: "KJFK"}}}
+		}, "only 1 airports"},
+		{"lowercase airline", func() dataFile {
+			d := fillerAirlines(updated)
+			d.Airlines["aal"] = airline{IATA: "AA"}
+			return d
+		}, `invalid airline "aal"`},
+		{"one-letter IATA airline", func() dataFile {
+			d := fillerAirlines(updated)
+			d.Airlines["AAL"] = airline{IATA: "A"}
+			return d
+		}, `invalid airline "AAL" ("A")`},
+		{"four-letter IATA airport", func() dataFile {
+			d := fillerAirports(updated)
+			d.Airports["KJFK"] = airport{ICAO: "KJFK"}
+			return d
+		}, `invalid airport "KJFK"`},
+		{"three-letter ICAO airport", func() dataFile {
+			d := fillerAirports(updated)
+			d.Airports["JFK"] = airport{ICAO: "JFK"}
+			return d
+		}, `invalid airport "JFK" ("JFK")`},
+		{"punctuated airport", func() dataFile {
+			d := fillerAirports(updated)
+			d.Airports["JF-"] = airport{ICAO: "KJF-"}
+			return d
+		}, `invalid airport "JF-"`},
+	} {
+		err := tt.data().validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOpenFlightsValue(t *testing.T) {
+	for value, want := range map[string]string{
+		`\N`:    "",
+		` \N `:  "",
+		"-":     "",
+		"":      "",
+		"AA":    "AA",
+		" JFK ": "JFK",
+		`\NX`:   `\NX`,
+	} {
+		if got := openFlightsValue(value); got != want {
+			t.Errorf("openFlightsValue(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+// TestUpdateDataFailure fails downloads after a good update, checking that
+// neither data file is replaced and no temporary file is left behind
+func TestUpdateDataFailure(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := UpdateData(context.Background(), openFlightsServer(t, goodDownloads(t)), dir); err != nil {
+		t.Fatalf("first UpdateData: %v", err)
+	}
+	before := dataDirContents(t, dir)
+
+	good := goodDownloads(t)
+	for _, tt := range []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{"airports download failed", map[string]string{"airlines.dat": good["airlines.dat"]}, "airports: download failed: 500"},
+		{"truncated airlines", map[string]string{"airlines.dat": openFlightsFile(t, "airlines.dat", 10), "airports.dat": good["airports.dat"]}, "airlines: only 13 airlines"},
+		{"malformed airports", map[string]string{"airlines.dat": good["airlines.dat"], "airports.dat": strings.Repeat("1,2,3\n", 100) + good["airports.dat"]}, "airports: 100 of"},
+	} {
+		_, err := UpdateData(context.Background(), openFlightsServer(t, tt.files), dir)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+		if after := dataDirContents(t, dir); !maps.EqualFunc(before, after, bytes.Equal) {
+			t.Errorf("%s: data directory changed from %d to %d files", tt.name, len(before), len(after))
+		}
+	}
+
+	// A file that doesn't read back valid isn't moved into place
+	err := writeDataFile(dir, airlinesFile, &airlinesData{Updated: time.Now()}, &airlinesData{})
+	if err == nil || !strings.Contains(err.Error(), "airlines.json not replaced") {
+		t.Errorf("writing an empty table: error = %v, want not replaced", err)
+	}
+	if after := dataDirContents(t, dir); !maps.EqualFunc(before, after, bytes.Equal) {
+		t.Errorf("invalid table written: data directory changed from %d to %d files", len(before), len(after))
+	}
+}
+
+// dataDirContents returns the files in dir by name
+func dataDirContents(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		raw, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = raw
+	}
+	return files
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"fids-tui/models"
+)
+
+const (
+	// demoInterval is how often the demo provider asks to be fetched, and how
+	// often its flights change, so the board stays lively
+	demoInterval = 20 * time.Second
+	// demoSpacing is the time between the demo flights of a board
+	demoSpacing = 10 * time.Minute
+	// demoWindow is how far ahead flights are made up when the fetch window has
+	// no end
+	demoWindow = 6 * time.Hour
+	// demoEpoch is how long a demo flight keeps a gate or delay before it may
+	// change, so changes are spread over the board rather than all at once
+	demoEpoch = 15 * demoInterval
+)
+
+// demoAirlines are the operators of the demo flights, by ICAO designator
+var demoAirlines = []string{"AAL", "DAL", "UAL", "JBU", "SWA", "ASA", "BAW", "AFR", "DLH", "ACA"}
+
+// demoPlaces are the airports the demo flights fly to and from
+var demoPlaces = []struct{ code, city string }{
+	{"ATL", "Atlanta"}, {"BOS", "Boston"}, {"DEN", "Denver"}, {"DFW", "Dallas"},
+	{"LAX", "Los Angeles"}, {"MIA", "Miami"}, {"ORD", "Chicago"}, {"SEA", "Seattle"},
+	{"SFO", "San Francisco"}, {"LHR", "London"}, {"CDG", "Paris"}, {"FRA", "Frankfurt"},
+	{"YYZ", "Toronto"}, {"MCO", "Orlando"}, {"LAS", "Las Vegas"}, {"PHX", "Phoenix"},
+}
+
+// DemoProvider makes up flights for any airport, for trying the board
+// without an API key and for showcases. The same airport and time always give
+// the same flights; every demoInterval some of them change gate, are delayed
+// or move on to the next status. Its results are marked Simulated
+type DemoProvider struct {
+	MaxFlights int         // Number of flights shown, the soonest made up
+	Window     FetchWindow // How far ahead flights are made up
+}
+
+// NewDemoProvider creates a demo provider
+func NewDemoProvider() *DemoProvider {
+	return &DemoProvider{MaxFlights: defaultTargetFlights}
+}
+
+// Name returns the display name of the data source
+func (p *DemoProvider) Name() string {
+	return "Demo"
+}
+
+// SuggestedInterval returns how often the demo flights change
+func (p *DemoProvider) SuggestedInterval() time.Duration {
+	return demoInterval
+}
+
+// GetDepartures makes up the departures of an airport
+func (p *DemoProvider) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.flights(airportCode, models.Departure, opts), nil
+}
+
+// GetArrivals makes up the arrivals of an airport
+func (p *DemoProvider) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.flights(airportCode, models.Arrival, opts), nil
+}
+
+// flights makes up a flight every demoSpacing from half an hour before now
+// to the end of the window
+func (p *DemoProvider) flights(airportCode string, direction models.Direction, opts FetchOptions) FetchResult {
+	now := p.Window.now()
+	end, ok := p.Window.End(now, airportCode, opts.Window)
+	if !ok {
+		end = now.Add(demoWindow)
+	}
+
+	var flights []models.Flight
+	for slot := now.Add(-30 * time.Minute).Truncate(demoSpacing); slot.Before(end); slot = slot.Add(demoSpacing) {
+		flight := demoFlight(airportCode, direction, slot, now)
+		if opts.keep(flight.ScheduledTime(), now) {
+			flights = append(flights, flight)
+		}
+	}
+	flights, total := capFlights(flights, opts.limit(p.MaxFlights))
+	return FetchResult{Flights: flights, Pages: 1, Total: total, Source: p.Name(), Simulated: true}
+}
+
+// demoFlight makes up the flight of airportCode scheduled at slot as it
+// stands at now. Its airline, number and route are drawn from the slot alone,
+// and its gate and delay from the slot and the demoEpoch now falls in, until
+// they are fixed once the slot has passed
+func demoFlight(airportCode string, direction models.Direction, slot, now time.Time) models.Flight {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s/%s/%d", airportCode, direction, slot.Unix())
+	seed := hash.Sum64()
+	r := rand.New(rand.NewPCG(seed, 0))
+
+	operator := demoAirlines[r.IntN(len(demoAirlines))]
+	i := r.IntN(len(demoPlaces))
+	if demoPlaces[i].code == airportCode {
+		i = (i + 1) % len(demoPlaces)
+	}
+	place := demoPlaces[i]
+	number := fmt.Sprint(100 + r.IntN(2900))
+	airlineCode := AirlineIATA(operator)
+	cancelled := r.IntN(40) == 0
+
+	// Each flight starts its epochs at its own offset
+	offset := time.Duration(r.Int64N(int64(demoEpoch)))
+	at := now
+	if slot.Before(now) {
+		at = slot
+	}
+	epoch := uint64(at.Add(offset).UnixNano() / int64(demoEpoch))
+	change := rand.New(rand.NewPCG(seed, epoch))
+	gate := fmt.Sprintf("%c%d", 'A'+rune(change.IntN(4)), 1+change.IntN(30))
+	var delay time.Duration
+	if change.IntN(5) == 0 {
+		delay = time.Duration(15+change.IntN(60)) * time.Minute
+	}
+
+	flight := models.Flight{
+		ID:           fmt.Sprintf("%s%s-demo-%d", operator, number, slot.Unix()),
+		Direction:    direction,
+		Ident:        operator + number,
+		AirlineCode:  airlineCode,
+		AirlineName:  operator,
+		FlightNumber: airlineCode + " " + number,
+		Gate:         gate,
+	}
+	estimate := slot.Add(delay)
+	if direction == models.Arrival {
+		flight.OriginCode, flight.OriginCity = place.code, place.city
+		flight.DestinationCode = airportCode
+		flight.ScheduledArrival = slot
+		if delay > 0 {
+			flight.EstimatedArrival = &estimate
+		}
+	} else {
+		flight.OriginCode = airportCode
+		flight.DestinationCode, flight.DestinationCity = place.code, place.city
+		flight.ScheduledDeparture = slot
+		if delay > 0 {
+			flight.EstimatedDeparture = &estimate
+		}
+	}
+
+	switch {
+	case cancelled:
+		flight.Status, flight.Remarks = models.StatusCancelled, models.RemarksCancelled
+	case direction == models.Arrival && !now.Before(estimate):
+		flight.Status, flight.Remarks = models.StatusArrived, models.RemarksArrived
+	case direction == models.Departure && !now.Before(estimate.Add(10*time.Minute)):
+		off := estimate.Add(10 * time.Minute)
+		flight.ActualOut, flight.ActualOff = &estimate, &off
+		flight.Status, flight.Remarks = models.StatusDeparted, models.RemarksDeparted
+	case direction == models.Departure && !now.Before(estimate) && delay > 0:
+		flight.ActualOut = &estimate
+		flight.Status, flight.Remarks = models.StatusTaxiingDelayed, models.RemarksTaxiingDelayed
+	case direction == models.Departure && !now.Before(estimate):
+		flight.ActualOut = &estimate
+		flight.Status, flight.Remarks = models.StatusTaxiingLeftGate, models.RemarksTaxiingLeftGate
+	case delay > 0:
+		flight.Status, flight.Remarks = models.StatusDelayed, models.RemarksDelayed
+	default:
+		flight.Status, flight.Remarks = models.StatusOnTime, models.RemarksOnTime
+	}
+	return flight
+}
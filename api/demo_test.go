@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// demoNow is the clock of the demo provider tests
+var demoNow = time.Date(2026, time.March, 2, 15, 4, 0, 0, time.UTC)
+
+// demoAt returns a demo provider whose clock reads now
+func demoAt(now time.Time) *DemoProvider {
+	provider := NewDemoProvider()
+	provider.Window.Now = func() time.Time { return now }
+	return provider
+}
+
+func TestDemoProvider(t *testing.T) {
+	provider := demoAt(demoNow)
+	if got := SuggestedInterval(provider); got != 20*time.Second {
+		t.Errorf("SuggestedInterval = %v, want 20s", got)
+	}
+
+	for _, direction := range []models.Direction{models.Departure, models.Arrival} {
+		opts := FetchOptions{Window: 2 * time.Hour, IncludePast: true}
+		result, err := GetFlights(context.Background(), provider, direction, "JFK", opts)
+		if err != nil {
+			t.Fatalf("%s: %v", direction, err)
+		}
+		if result.Source != "Demo" || !result.Simulated {
+			t.Errorf("%s: source %q, simulated %v, want Demo and simulated", direction, result.Source, result.Simulated)
+		}
+		// A flight every 10 minutes from 14:30 until 17:04
+		if len(result.Flights) != 16 {
+			t.Errorf("%s: %d flights, want 16", direction, len(result.Flights))
+		}
+		for i, flight := range result.Flights {
+			if flight.Direction != direction || flight.FlightNumber == "" || flight.ID == "" || flight.Gate == "" || !flight.HasRoute() {
+				t.Errorf("%s: flight %d is incomplete: %+v", direction, i, flight)
+			}
+			if got := flight.ScheduledTime(); i > 0 && !got.After(result.Flights[i-1].ScheduledTime()) {
+				t.Errorf("%s: flight %d at %v isn't after the one before", direction, i, got)
+			}
+			if flight.DestinationCode == flight.OriginCode {
+				t.Errorf("%s: %s flies from and to %s", direction, flight.FlightNumber, flight.OriginCode)
+			}
+		}
+
+		// The same clock makes up the same flights
+		again, _ := GetFlights(context.Background(), demoAt(demoNow), direction, "JFK", opts)
+		for i := range again.Flights {
+			if again.Flights[i].ID != result.Flights[i].ID || again.Flights[i].Gate != result.Flights[i].Gate {
+				t.Errorf("%s: flight %d differs on a second fetch at the same time", direction, i)
+			}
+		}
+	}
+
+	// Without IncludePast, flights already gone are left out
+	result, _ := provider.GetDepartures(context.Background(), "JFK", FetchOptions{Window: 2 * time.Hour, Limit: 5})
+	if len(result.Flights) != 5 || result.Total != 12 || result.Flights[0].ScheduledDeparture.Before(demoNow) {
+		t.Errorf("got %d of %d flights from %v, want 5 of 12 from after %v", len(result.Flights), result.Total, result.Flights[0].ScheduledDeparture, demoNow)
+	}
+}
+
+// TestDemoProviderChanges fetches every 20 seconds for an hour, checking the
+// flights change as they go but their schedule doesn't, and that flights
+// stop changing once they have left
+func TestDemoProviderChanges(t *testing.T) {
+	opts := FetchOptions{Window: 3 * time.Hour, IncludePast: true}
+	type state struct {
+		flight  models.Flight
+		changes int
+	}
+	flights := map[string]*state{}
+	changedFetches := 0
+	for now := demoNow; now.Before(demoNow.Add(time.Hour)); now = now.Add(20 * time.Second) {
+		result, _ := demoAt(now).GetDepartures(context.Background(), "JFK", opts)
+		changed := false
+		for _, flight := range result.Flights {
+			seen, ok := flights[flight.ID]
+			if !ok {
+				flights[flight.ID] = &state{flight: flight}
+				continue
+			}
+			old := seen.flight
+			if old.FlightNumber != flight.FlightNumber || old.DestinationCode != flight.DestinationCode || !old.ScheduledDeparture.Equal(flight.ScheduledDeparture) {
+				t.Errorf("%s changed schedule at %v: %+v to %+v", flight.ID, now, old, flight)
+			}
+			if old.Status == models.StatusDeparted && (flight.Status != models.StatusDeparted || flight.Gate != old.Gate) {
+				t.Errorf("%s changed after departing at %v: %+v to %+v", flight.ID, now, old, flight)
+			}
+			if old.Gate != flight.Gate || old.Status != flight.Status || !sameTime(old.EstimatedDeparture, flight.EstimatedDeparture) {
+				seen.changes++
+				changed = true
+			}
+			seen.flight = flight
+		}
+		if changed {
+			changedFetches++
+		}
+	}
+	// 180 fetches in the hour; most of them change something
+	if changedFetches < 90 {
+		t.Errorf("%d of 180 fetches changed a flight, want at least 90", changedFetches)
+	}
+	departed := 0
+	for _, seen := range flights {
+		if seen.flight.Status == models.StatusDeparted {
+			departed++
+		}
+	}
+	if departed == 0 {
+		t.Error("no flight departed in an hour")
+	}
+}
+
+// sameTime reports whether two optional times are both unset or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,8 @@
+// Package api fetches flight data for the board from FlightAware AeroAPI,
+// OpenSky and local ADS-B receivers, and can be used on its own by programs
+// that want the flights without the board; see the GetDepartures example.
+//
+// The flights are models.Flight values, which marshal to JSON with
+// snake_case keys. ScheduledDepartures returns the records as AeroAPI sent
+// them instead, for fields the model doesn't carry.
+package api
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"log/slog"
+
+	"fids-tui/models"
+)
+
+// pageFlights collects the flights of a paged fetch in page order. AeroAPI
+// can list a flight again at the top of the next page when the pages shift
+// between requests, so a flight already collected is replaced by the later,
+// fresher copy in its place rather than shown twice
+type pageFlights struct {
+	flights    []models.Flight
+	index      map[string]int // Position in flights, by fa_flight_id
+	duplicates int            // Flights listed again on a later page
+}
+
+// add collects flight, replacing an earlier copy with the same fa_flight_id.
+// Flights without one can't be matched and are always added
+func (p *pageFlights) add(flight models.Flight) {
+	if flight.ID == "" {
+		p.flights = append(p.flights, flight)
+		return
+	}
+	if p.index == nil {
+		p.index = make(map[string]int)
+	}
+	if i, ok := p.index[flight.ID]; ok {
+		p.flights[i] = flight
+		p.duplicates++
+		return
+	}
+	p.index[flight.ID] = len(p.flights)
+	p.flights = append(p.flights, flight)
+}
+
+// logDuplicates logs the flights listed on more than one page of an
+// airport's fetch, if any
+func logDuplicates(airportCode, endpoint string, collected *pageFlights) {
+	if collected.duplicates > 0 {
+		slog.Debug("dropped flights repeated across AeroAPI pages", "airport", airportCode, "endpoint", endpoint, "duplicates", collected.duplicates)
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultWindowLimit is assumed to be the longest window allowed when an
+// API rejects the requested window without saying what it allows
+const defaultWindowLimit = 24 * time.Hour
+
+// windowLimitPattern finds the limit in an error detail like "end must be
+// no more than 2 days in the future"
+var windowLimitPattern = regexp.MustCompile(`(\d+)\s*(hour|day)s?`)
+
+// loggedBodyBytes is how much of a response that can't be used is logged
+const loggedBodyBytes = 200
+
+// ErrNoData is returned when a source answers without the flights asked for,
+// such as with an empty body or without the list of flights, as opposed to
+// with a list that has no flights in the window. The board shows it as data
+// unavailable rather than as nothing scheduled
+var ErrNoData = errors.New("data unavailable")
+
+// APIError is an unsuccessful HTTP response from a flight data source
+type APIError struct {
+	Source     string // Name of the data source, e.g. "FlightAware"
+	StatusCode int
+	Reason     string // Machine-readable reason given by the source, e.g. "INVALID_ARGUMENT"
+	Detail     string // Explanation given by the source
+	Message    string // Describes the error to the user
+}
+
+// Error returns the message describing the error
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Transient reports whether the request may succeed if retried: rate limits
+// and server errors are transient, while failed authentication, unknown
+// airports and other client errors are not
+func (e *APIError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// newStatusError describes an unexpected status code from source. Bodies in
+// AeroAPI's error format ({"title", "reason", "detail", "status"}) are
+// described by their title and detail; anything else is shown as it is
+func newStatusError(source string, statusCode int, body []byte) *APIError {
+	e := &APIError{Source: source, StatusCode: statusCode}
+	var parsed struct {
+		Title  string `json:"title"`
+		Reason string `json:"reason"`
+		Detail string `json:"detail"`
+	}
+	text := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &parsed) == nil && (parsed.Title != "" || parsed.Detail != "") {
+		e.Reason = parsed.Reason
+		e.Detail = parsed.Detail
+		switch {
+		case parsed.Title == "":
+			text = parsed.Detail
+		case parsed.Detail == "":
+			text = parsed.Title
+		default:
+			text = parsed.Title + ": " + parsed.Detail
+		}
+	}
+	e.Message = fmt.Sprintf("%s API error (status %d): %s", source, statusCode, text)
+	return e
+}
+
+// WindowLimit reports whether the request was rejected for asking for
+// flights too far ahead, and the longest window the source allows: the one
+// the detail names, or defaultWindowLimit if it names none
+func (e *APIError) WindowLimit() (time.Duration, bool) {
+	detail := strings.ToLower(e.Detail)
+	if e.StatusCode != http.StatusBadRequest || !strings.Contains(detail, "end") {
+		return 0, false
+	}
+	if !strings.Contains(detail, "future") && !strings.Contains(detail, "range") && !strings.Contains(detail, "exceed") {
+		return 0, false
+	}
+	match := windowLimitPattern.FindStringSubmatch(detail)
+	if match == nil {
+		return defaultWindowLimit, true
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n <= 0 {
+		return defaultWindowLimit, true
+	}
+	if match[2] == "day" {
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	return time.Duration(n) * time.Hour, true
+}
+
+// windowLimit returns the window limit of err, if it is an APIError
+// rejecting the requested window
+func windowLimit(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	return apiErr.WindowLimit()
+}
+
+// IsTransient reports whether a fetch that failed with err is likely to
+// succeed on a later attempt: network failures, timeouts, responses without
+// data and transient API errors. Anything else, such as a rejected API key
+// or an unknown airport, needs fixing before a retry can help
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrNoData) {
+		return true // A response cut short or sent without its flights
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Transient()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// bodyStart returns the start of a response body for logging
+func bodyStart(body []byte) string {
+	return string(body[:min(len(body), loggedBodyBytes)])
+}
+
+// hasList reports whether body, a JSON object, holds the list key, even an
+// empty one; a null list counts as missing
+func hasList(body []byte, key string) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return false
+	}
+	list, ok := fields[key]
+	return ok && strings.TrimSpace(string(list)) != "null"
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIsTransient(t *testing.T) {
+	status := func(code int) error { return &APIError{Source: "FlightAware", StatusCode: code} }
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"no error", nil, false},
+		{"rate limited", status(429), true},
+		{"server error", status(500), true},
+		{"unavailable", status(503), true},
+		{"wrapped server error", fmt.Errorf("fetching JFK: %w", status(502)), true},
+		{"timeout", context.DeadlineExceeded, true},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"no data", ErrNoData, true},
+		{"bad request", status(400), false},
+		{"unauthorized", status(401), false},
+		{"unknown airport", status(404), false},
+		{"configuration", errors.New("unknown data source"), false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWindowLimit(t *testing.T) {
+	tests := []struct {
+		file   string
+		status int
+		want   time.Duration
+		ok     bool
+	}{
+		{"error_window_limit.json", 400, 48 * time.Hour, true},
+		{"error_window.json", 400, defaultWindowLimit, true},
+		{"error_argument.json", 400, 0, false},
+		// The same detail with another status isn't a window limit
+		{"error_window_limit.json", 403, 0, false},
+	}
+	for _, tt := range tests {
+		body, err := os.ReadFile(filepath.Join("testdata", "aeroapi", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		apiErr := newStatusError("FlightAware", tt.status, body)
+		if got, ok := apiErr.WindowLimit(); got != tt.want || ok != tt.ok {
+			t.Errorf("WindowLimit of %s (status %d) = %s, %v, want %s, %v", tt.file, tt.status, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNewStatusError(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"title":"Invalid argument","reason":"INVALID_ARGUMENT","detail":"end is out of range for your account","status":400}`,
+			"FlightAware API error (status 400): Invalid argument: end is out of range for your account"},
+		{`{"title":"Invalid argument"}`, "FlightAware API error (status 400): Invalid argument"},
+		{`{"detail":"end is out of range"}`, "FlightAware API error (status 400): end is out of range"},
+		{"Bad Request\n", "FlightAware API error (status 400): Bad Request"},
+	}
+	for _, tt := range tests {
+		if got := newStatusError("FlightAware", 400, []byte(tt.body)).Error(); got != tt.want {
+			t.Errorf("newStatusError(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"fids-tui/api"
+)
+
+// Fetches the next five departures of an airport and prints them. The
+// server stands in for AeroAPI, serving a recorded answer; a program of its
+// own leaves out WithBaseURL and the clock
+func ExampleFlightAwareClient_GetDepartures() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/aeroapi/departures_bgr.json")
+	}))
+	defer server.Close()
+	client := api.NewFlightAwareClient("your-api-key", api.WithBaseURL(server.URL))
+	client.Window.Now = func() time.Time { return time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC) }
+
+	result, err := client.GetDepartures(context.Background(), "BGR", api.FetchOptions{Window: 6 * time.Hour, Limit: 5})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, flight := range result.Flights {
+		fmt.Println(flight.ScheduledDeparture.Format("15:04"), flight.FlightNumber, flight.GetDestination())
+	}
+	// Output:
+	// 12:05 AA 100 BOS Boston
+	// 12:09 DL 107 ORD Chicago
+	// 12:13 UA 114 LAX Los Angeles
+	// 12:17 B6 121 DEN Denver
+	// 12:21 WN 128 SEA Seattle
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// expectedFields are the fields of each AeroAPI endpoint's flights the
+// board depends on. Each entry lists alternatives, any of which will do;
+// nested fields are named by path, e.g. "departure.scheduled"
+var expectedFields = map[string][][]string{
+	"scheduled_departures": {
+		{"ident"},
+		{"departure.scheduled", "scheduled_out"},
+		{"destination.code_iata", "destination.code"},
+		{"status"},
+		{"gate_origin"},
+	},
+	"scheduled_arrivals": {
+		{"ident"},
+		{"scheduled_in"},
+		{"origin.code_iata", "origin.code"},
+		{"status"},
+		{"gate_destination"},
+	},
+}
+
+// knownFields are the fields decoded from the flights of each endpoint, by
+// name, with the fields of the objects they hold, nil for plain values
+var knownFields = map[string]map[string]map[string]bool{
+	"scheduled_departures": fieldsOf(reflect.TypeFor[AeroAPIDeparture]()),
+	"scheduled_arrivals":   fieldsOf(reflect.TypeFor[AeroAPIArrival]()),
+}
+
+// fieldsOf returns the JSON fields of struct type t, with the fields of
+// those holding structs other than times
+func fieldsOf(t reflect.Type) map[string]map[string]bool {
+	fields := make(map[string]map[string]bool)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		var nested map[string]bool
+		if typ.Kind() == reflect.Struct && typ != reflect.TypeFor[time.Time]() {
+			nested = make(map[string]bool)
+			for sub := range fieldsOf(typ) {
+				nested[sub] = true
+			}
+		}
+		fields[name] = nested
+	}
+	return fields
+}
+
+// fieldCheck follows which fields the flights of one fetch carried, across
+// all of its pages, so a field AeroAPI renamed or dropped is noticed
+// instead of silently leaving blanks on the board
+type fieldCheck struct {
+	endpoint string
+	flights  int             // Flights seen on every page
+	present  map[string]bool // Fields present in at least one flight, by path
+	unknown  map[string]bool // Fields present that aren't decoded, by path
+}
+
+// newFieldCheck starts checking the flights of endpoint
+func newFieldCheck(endpoint string) *fieldCheck {
+	return &fieldCheck{endpoint: endpoint, present: make(map[string]bool), unknown: make(map[string]bool)}
+}
+
+// add records the fields of the flights of one page. Pages that don't
+// decode are left out; the typed decoding reports those
+func (fc *fieldCheck) add(body []byte) {
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return
+	}
+	var flights []map[string]json.RawMessage
+	if err := json.Unmarshal(page[fc.endpoint], &flights); err != nil {
+		return
+	}
+	known := knownFields[fc.endpoint]
+	for _, flight := range flights {
+		fc.flights++
+		for name, raw := range flight {
+			fc.present[name] = true
+			nested, ok := known[name]
+			if !ok {
+				fc.unknown[name] = true
+				continue
+			}
+			if nested == nil {
+				continue
+			}
+			var object map[string]json.RawMessage
+			if json.Unmarshal(raw, &object) != nil {
+				continue // null, or no longer an object
+			}
+			for sub := range object {
+				fc.present[name+"."+sub] = true
+				if !nested[sub] {
+					fc.unknown[name+"."+sub] = true
+				}
+			}
+		}
+	}
+}
+
+// missing returns the expected fields no flight carried, each as its
+// alternatives joined by "or". Nothing is missing from a fetch without
+// flights
+func (fc *fieldCheck) missing() []string {
+	if fc.flights == 0 {
+		return nil
+	}
+	var missing []string
+	for _, alternatives := range expectedFields[fc.endpoint] {
+		found := false
+		for _, name := range alternatives {
+			found = found || fc.present[name]
+		}
+		if !found {
+			missing = append(missing, strings.Join(alternatives, " or "))
+		}
+	}
+	return missing
+}
+
+// reportFields logs the fields of a fetch the client doesn't decode, at
+// debug level, and warns about expected fields missing from every flight.
+// Each missing field is warned about once until it is seen again
+func (c *FlightAwareClient) reportFields(fc *fieldCheck, airportCode string) {
+	if len(fc.unknown) > 0 {
+		unknown := make([]string, 0, len(fc.unknown))
+		for name := range fc.unknown {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		slog.Debug("AeroAPI fields not decoded", "endpoint", fc.endpoint, "fields", strings.Join(unknown, ","))
+	}
+	if fc.flights == 0 {
+		return
+	}
+	missing := make(map[string]bool)
+	for _, name := range fc.missing() {
+		missing[name] = true
+		if _, warned := c.missingFields.LoadOrStore(fc.endpoint+" "+name, true); !warned {
+			slog.Warn(fmt.Sprintf("no %s present in any of %d flights — API change?", name, fc.flights),
+				"endpoint", fc.endpoint, "airport", airportCode)
+		}
+	}
+	for _, alternatives := range expectedFields[fc.endpoint] {
+		if name := strings.Join(alternatives, " or "); !missing[name] {
+			c.missingFields.Delete(fc.endpoint + " " + name)
+		}
+	}
+}
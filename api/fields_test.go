@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// logRecorder keeps the message and attributes of each record logged
+type logRecorder struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (r *logRecorder) Enabled(context.Context, slog.Level) bool { return true }
+
+func (r *logRecorder) Handle(_ context.Context, record slog.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record.Clone())
+	return nil
+}
+
+func (r *logRecorder) WithAttrs([]slog.Attr) slog.Handler { return r }
+func (r *logRecorder) WithGroup(string) slog.Handler      { return r }
+
+// messages returns the messages logged at level
+func (r *logRecorder) messages(level slog.Level) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var messages []string
+	for _, record := range r.records {
+		if record.Level == level {
+			messages = append(messages, record.Message)
+		}
+	}
+	return messages
+}
+
+// attr returns the value of attribute key of the first record logged with
+// message, or "" if there is none
+func (r *logRecorder) attr(message, key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range r.records {
+		if record.Message != message {
+			continue
+		}
+		var value string
+		record.Attrs(func(a slog.Attr) bool {
+			if a.Key == key {
+				value = a.Value.String()
+				return false
+			}
+			return true
+		})
+		return value
+	}
+	return ""
+}
+
+// recordLogs sends the default logger to a recorder until the test ends
+func recordLogs(t *testing.T) *logRecorder {
+	recorder := &logRecorder{}
+	previous := slog.Default()
+	slog.SetDefault(slog.New(recorder))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return recorder
+}
+
+// TestMutatedPayloads fetches the deliberately altered responses in
+// testdata/aeroapi/mutated, checking each change to a field the board reads
+// gives a single warning naming it, and the fields it doesn't decode are
+// logged at debug level
+func TestMutatedPayloads(t *testing.T) {
+	tests := []struct {
+		name      string
+		pages     map[string]aeroAPIPage
+		airport   string
+		arrivals  bool
+		warnings  []string
+		undecoded string // Fields logged as not decoded
+	}{
+		{
+			name: "scheduled renamed on every page",
+			pages: map[string]aeroAPIPage{
+				"/airports/PDX/flights/scheduled_departures":                 {file: "mutated/no_scheduled_1.json"},
+				"/airports/PDX/flights/scheduled_departures?cursor=mutated2": {file: "mutated/no_scheduled_2.json"},
+			},
+			airport:   "PDX",
+			warnings:  []string{"no departure.scheduled or scheduled_out present in any of 24 flights — API change?"},
+			undecoded: "scheduled_off_block",
+		},
+		{
+			name:      "gate renamed",
+			pages:     map[string]aeroAPIPage{"/airports/BGR/flights/scheduled_departures": {file: "mutated/renamed_gate.json"}},
+			airport:   "BGR",
+			warnings:  []string{"no gate_origin present in any of 6 flights — API change?"},
+			undecoded: "origin_gate",
+		},
+		{
+			name:      "destination codes renamed",
+			pages:     map[string]aeroAPIPage{"/airports/BGR/flights/scheduled_departures": {file: "mutated/renamed_destination.json"}},
+			airport:   "BGR",
+			warnings:  []string{"no destination.code_iata or destination.code present in any of 6 flights — API change?"},
+			undecoded: "destination.iata,destination.icao",
+		},
+		{
+			// Some flights without a status is data, not an API change
+			name:    "status missing from some flights",
+			pages:   map[string]aeroAPIPage{"/airports/BGR/flights/scheduled_departures": {file: "mutated/partial_status.json"}},
+			airport: "BGR",
+		},
+		{
+			name:     "arrival times removed",
+			pages:    map[string]aeroAPIPage{"/airports/JFK/flights/scheduled_arrivals": {file: "mutated/no_scheduled_in.json"}},
+			airport:  "JFK",
+			arrivals: true,
+			warnings: []string{"no scheduled_in present in any of 3 flights — API change?"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := recordLogs(t)
+			client, _ := aeroAPIServer(t, tt.pages)
+			fetch := func() {
+				t.Helper()
+				var err error
+				if tt.arrivals {
+					_, err = client.GetArrivals(context.Background(), tt.airport, FetchOptions{IncludePast: true, MaxPages: 5})
+				} else {
+					_, err = client.GetDepartures(context.Background(), tt.airport, FetchOptions{IncludePast: true, MaxPages: 5})
+				}
+				if err != nil {
+					t.Fatalf("fetch: %v", err)
+				}
+			}
+			// The warning isn't repeated while the field stays missing
+			fetch()
+			fetch()
+
+			var warnings []string
+			for _, message := range logs.messages(slog.LevelWarn) {
+				if strings.HasPrefix(message, "no ") {
+					warnings = append(warnings, message)
+				}
+			}
+			if !slices.Equal(warnings, tt.warnings) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.warnings)
+			}
+			if got := logs.attr("AeroAPI fields not decoded", "fields"); got != tt.undecoded {
+				t.Errorf("fields not decoded = %q, want %q", got, tt.undecoded)
+			}
+		})
+	}
+}
+
+// TestMissingFieldWarnsAgain checks a field that comes back and goes missing
+// again is warned about again
+func TestMissingFieldWarnsAgain(t *testing.T) {
+	logs := recordLogs(t)
+	pages := map[string]aeroAPIPage{"/airports/BGR/flights/scheduled_departures": {file: "mutated/renamed_gate.json"}}
+	client, _ := aeroAPIServer(t, pages)
+	for _, file := range []string{"mutated/renamed_gate.json", "departures_bgr.json", "mutated/renamed_gate.json"} {
+		pages["/airports/BGR/flights/scheduled_departures"] = aeroAPIPage{file: file}
+		if _, err := client.GetDepartures(context.Background(), "BGR", FetchOptions{}); err != nil {
+			t.Fatalf("fetch of %s: %v", file, err)
+		}
+	}
+	if got := len(logs.messages(slog.LevelWarn)); got != 2 {
+		t.Errorf("%d warnings, want 2: %q", got, logs.messages(slog.LevelWarn))
+	}
+}
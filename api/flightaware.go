@@ -1,11 +1,17 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"fids-tui/models"
@@ -13,30 +19,106 @@ import (
 
 const (
 	flightAwareBaseURL = "https://aeroapi.flightaware.com/aeroapi"
+	// aeroAPIPageSize is the number of flights in a full AeroAPI result page
+	aeroAPIPageSize = 15
+	// defaultTargetFlights is the number of flights collected when TargetFlights is unset
+	defaultTargetFlights = 50
+	// defaultFlightAwareTimeout is the request timeout when none is configured
+	defaultFlightAwareTimeout = 30 * time.Second
 )
 
 // FlightAwareClient handles API interactions with FlightAware
 type FlightAwareClient struct {
-	APIKey  string
-	BaseURL string
-	Client  *http.Client
+	APIKey        string
+	BaseURL       string
+	Client        *http.Client
+	TargetFlights int          // Stop paging once this many flights are collected, and show the soonest this many
+	Usage         *Usage       // Counts every request made, for spend estimates
+	Window        FetchWindow  // How far ahead flights are fetched
+	Strict        bool         // Leave out flights missing required fields and report them, rather than filling in placeholders
+	TaxiLookups   int          // Taxiing departures looked up in the flights endpoint on each fetch to learn when they took off; none if zero
+	RateLimit     *RateLimiter // Spaces out the requests of every fetch made at once; nil for no limit
+	planLimit     atomic.Int64 // Longest window the API plan allows, learned from a rejected request; zero if unknown
+	missingFields sync.Map     // Expected response fields already warned missing, by endpoint and name
+}
+
+// FlightAwareOption configures a FlightAwareClient
+type FlightAwareOption func(*FlightAwareClient)
+
+// WithBaseURL points the client at an alternate AeroAPI base URL, such as a
+// sandbox or a local mock server
+func WithBaseURL(baseURL string) FlightAwareOption {
+	return func(c *FlightAwareClient) {
+		c.BaseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient makes the client send requests with httpClient
+func WithHTTPClient(httpClient *http.Client) FlightAwareOption {
+	return func(c *FlightAwareClient) {
+		c.Client = httpClient
+	}
+}
+
+// WithTimeout sets the request timeout. The HTTP client is copied so a client
+// passed to WithHTTPClient is not modified
+func WithTimeout(timeout time.Duration) FlightAwareOption {
+	return func(c *FlightAwareClient) {
+		client := *c.Client
+		client.Timeout = timeout
+		c.Client = &client
+	}
+}
+
+// WithUsage counts the client's requests and result pages in usage, which may
+// be shared with other clients
+func WithUsage(usage *Usage) FlightAwareOption {
+	return func(c *FlightAwareClient) {
+		c.Usage = usage
+	}
 }
 
 // NewFlightAwareClient creates a new FlightAware API client
-func NewFlightAwareClient(apiKey string) *FlightAwareClient {
-	return &FlightAwareClient{
+func NewFlightAwareClient(apiKey string, opts ...FlightAwareOption) *FlightAwareClient {
+	c := &FlightAwareClient{
 		APIKey:  apiKey,
 		BaseURL: flightAwareBaseURL,
 		Client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: defaultFlightAwareTimeout,
 		},
+		Usage: &Usage{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ValidateBaseURL checks that baseURL is an absolute http or https URL
+func ValidateBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid URL %q: scheme must be http or https", baseURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid URL %q: missing host", baseURL)
 	}
+	return nil
+}
+
+// Name returns the display name of the data source
+func (c *FlightAwareClient) Name() string {
+	return "FlightAware"
 }
 
 // AeroAPIDeparture represents a departure from FlightAware API
 type AeroAPIDeparture struct {
 	Ident        string     `json:"ident"`
 	FaFlightID   string     `json:"fa_flight_id"`
+	InboundID    string     `json:"inbound_fa_flight_id"` // fa_flight_id of the flight bringing the aircraft in
 	Operator     string     `json:"operator"`
 	OperatorIata string     `json:"operator_iata"`
 	FlightNumber string     `json:"flight_number"`
@@ -46,10 +128,12 @@ type AeroAPIDeparture struct {
 	ScheduledOut *time.Time `json:"scheduled_out"`
 	EstimatedOut *time.Time `json:"estimated_out"`
 	ActualOut    *time.Time `json:"actual_out"`
+	ActualOff    *time.Time `json:"actual_off"`
 	Status       string     `json:"status"`
 	Gate         string     `json:"gate_origin"`
 	BaggageClaim string     `json:"baggage_claim"`
 	Remarks      string     `json:"remarks"`
+	Blocked      bool       `json:"blocked"` // Blocked from public tracking by its operator
 }
 
 // Airport represents airport information
@@ -60,6 +144,17 @@ type Airport struct {
 	City     string `json:"city"`
 }
 
+// preferredCode returns the IATA code of the airport, falling back to the raw
+// (usually ICAO) code for airports without one
+func (a *Airport) preferredCode() string {
+	for _, code := range []string{a.CodeIata, a.Code, a.CodeIcao} {
+		if code = strings.TrimSpace(code); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
 // TimeInfo represents time information
 type TimeInfo struct {
 	Scheduled time.Time `json:"scheduled"`
@@ -67,52 +162,431 @@ type TimeInfo struct {
 	Actual    time.Time `json:"actual"`
 }
 
+// AeroAPIArrival represents an arrival from FlightAware API
+type AeroAPIArrival struct {
+	Ident        string     `json:"ident"`
+	FaFlightID   string     `json:"fa_flight_id"`
+	Operator     string     `json:"operator"`
+	OperatorIata string     `json:"operator_iata"`
+	FlightNumber string     `json:"flight_number"`
+	Origin       *Airport   `json:"origin"`
+	ActualOut    *time.Time `json:"actual_out"` // Set on flights looked up by ID
+	ActualOff    *time.Time `json:"actual_off"`
+	ScheduledIn  *time.Time `json:"scheduled_in"`
+	EstimatedIn  *time.Time `json:"estimated_in"`
+	ActualIn     *time.Time `json:"actual_in"`
+	Status       string     `json:"status"`
+	Gate         string     `json:"gate_destination"`
+	BaggageClaim string     `json:"baggage_claim"`
+	Blocked      bool       `json:"blocked"` // Blocked from public tracking by its operator
+}
+
 // AeroAPIResponse represents the response from FlightAware API
 type AeroAPIResponse struct {
 	ScheduledDepartures []AeroAPIDeparture `json:"scheduled_departures"`
+	ScheduledArrivals   []AeroAPIArrival   `json:"scheduled_arrivals"`
+	Links               *AeroAPILinks      `json:"links"`
+	NumPages            int                `json:"num_pages"`
+}
+
+// AeroAPILinks holds the cursor links of a paged response
+type AeroAPILinks struct {
+	Next string `json:"next"` // Path of the next page relative to the base URL
+}
+
+// nextPath returns the path of the next page, or an empty string on the last page
+func (l *AeroAPILinks) nextPath() string {
+	if l == nil {
+		return ""
+	}
+	return l.Next
 }
 
-// GetDepartures fetches scheduled departures for an airport within the specified hours
+// GetDepartures fetches scheduled departures for an airport selected by opts
 // Uses the scheduled_departures endpoint which defaults to 2 hours before current time
 // and excludes flights that have already departed (en route)
-func (c *FlightAwareClient) GetDepartures(airportCode string, hours int, maxPages int) ([]models.Flight, error) {
-	// Build base URL
-	baseURL := fmt.Sprintf("%s/airports/%s/flights/scheduled_departures", c.BaseURL, airportCode)
-	reqURL, err := url.Parse(baseURL)
+func (c *FlightAwareClient) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return c.departures(ctx, airportCode, "", opts)
+}
+
+// GetRouteDepartures fetches scheduled departures from origin to destination
+// selected by opts. AeroAPI's departures endpoint can't filter by
+// destination, so pages are searched until enough flights on the route were
+// found or opts.MaxPages is reached
+func (c *FlightAwareClient) GetRouteDepartures(ctx context.Context, origin, destination string, opts FetchOptions) (FetchResult, error) {
+	return c.departures(ctx, origin, destination, opts)
+}
+
+// ScheduledDepartures fetches the departures of an airport selected by opts
+// as AeroAPI returns them, for callers that want fields the Flight model
+// doesn't carry. Unlike GetDepartures it doesn't retry with the plan's
+// window when the one asked for is too long
+func (c *FlightAwareClient) ScheduledDepartures(ctx context.Context, airportCode string, opts FetchOptions) ([]AeroAPIDeparture, error) {
+	now := c.Window.now()
+	cutoffTime, _ := c.windowEnd(airportCode, opts.Window)
+	limit := opts.limit(c.targetFlights())
+	departures := make([]AeroAPIDeparture, 0)
+	_, err := c.fetchPages(ctx, airportCode, "scheduled_departures", cutoffTime, opts.maxPages(), func(page AeroAPIResponse) (int, bool) {
+		for _, dep := range page.ScheduledDepartures {
+			scheduled, ok := dep.ScheduledTime()
+			if !ok || !opts.keep(scheduled, now) || (cutoffTime != nil && scheduled.After(*cutoffTime)) {
+				continue
+			}
+			departures = append(departures, dep)
+		}
+		return len(page.ScheduledDepartures), len(departures) >= limit
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse URL: %w", err)
+		return nil, err
+	}
+	if len(departures) > limit {
+		departures = departures[:limit]
+	}
+	return departures, nil
+}
+
+// ScheduledTime returns the time the departure is listed under: the
+// scheduled departure, falling back to scheduled_out and then the estimate.
+// It returns false if the departure has none of them
+func (d AeroAPIDeparture) ScheduledTime() (time.Time, bool) {
+	switch {
+	case d.Departure != nil && !d.Departure.Scheduled.IsZero():
+		return d.Departure.Scheduled, true
+	case d.ScheduledOut != nil && !d.ScheduledOut.IsZero():
+		return *d.ScheduledOut, true
+	case d.EstimatedOut != nil && !d.EstimatedOut.IsZero():
+		return *d.EstimatedOut, true
+	}
+	return time.Time{}, false
+}
+
+// ScheduledTime returns the time the arrival is listed under: the scheduled
+// gate arrival, falling back to the estimate. It returns false if the
+// arrival has neither
+func (a AeroAPIArrival) ScheduledTime() (time.Time, bool) {
+	switch {
+	case a.ScheduledIn != nil && !a.ScheduledIn.IsZero():
+		return *a.ScheduledIn, true
+	case a.EstimatedIn != nil && !a.EstimatedIn.IsZero():
+		return *a.EstimatedIn, true
+	}
+	return time.Time{}, false
+}
+
+// departures fetches scheduled departures for an airport, keeping only those
+// bound for destination unless it is empty
+func (c *FlightAwareClient) departures(ctx context.Context, airportCode, destination string, opts FetchOptions) (FetchResult, error) {
+	return c.withinPlanLimit(airportCode, opts.Window, func(cutoffTime *time.Time) (FetchResult, error) {
+		return c.departuresUntil(ctx, airportCode, destination, cutoffTime, opts)
+	})
+}
+
+// departuresUntil fetches scheduled departures up to cutoffTime, or without
+// an end if it is nil, keeping only those bound for destination unless it
+// is empty
+func (c *FlightAwareClient) departuresUntil(ctx context.Context, airportCode, destination string, cutoffTime *time.Time, opts FetchOptions) (FetchResult, error) {
+	// scheduled_departures endpoint defaults to 2 hours before current time
+	// We only need to filter by the future cutoff time if the window has an end
+	now := c.Window.now()
+	limit := opts.limit(c.targetFlights())
+	var collected pageFlights
+	var skipped SkipReport
+	pages, err := c.fetchPages(ctx, airportCode, "scheduled_departures", cutoffTime, opts.maxPages(), func(page AeroAPIResponse) (int, bool) {
+		for _, dep := range page.ScheduledDepartures {
+			if c.Strict && !isBlocked(dep.Blocked, dep.Ident) {
+				// Blocked flights lack these fields by design, and are shown or hidden as such
+				if missing := dep.missingFields(); len(missing) > 0 {
+					skipped.add(sampleIdent(dep.Ident, dep.FaFlightID), missing)
+					continue
+				}
+			}
+			scheduled, ok := dep.ScheduledTime()
+			if !ok {
+				continue
+			}
+
+			// Filter flights departing within the specified future window (if cutoff is set)
+			// scheduled_departures endpoint already excludes en route flights and includes past 2 hours
+			if cutoffTime != nil && scheduled.After(*cutoffTime) {
+				continue
+			}
+			if !opts.keep(scheduled, now) {
+				continue
+			}
+
+			flight := c.convertToFlight(dep, scheduled)
+			if destination != "" && strings.TrimSpace(flight.DestinationCode) != destination {
+				continue
+			}
+			collected.add(flight)
+		}
+		return len(page.ScheduledDepartures), len(collected.flights) >= limit
+	})
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	logSkipped(airportCode, skipped)
+	logDuplicates(airportCode, "scheduled_departures", &collected)
+	flights, total := capFlights(collected.flights, limit)
+	pages += c.lookUpTakeoffs(ctx, flights)
+	return FetchResult{Flights: flights, Pages: pages, Total: total, Skipped: skipped}, nil
+}
+
+// lookUpTakeoffs looks up up to TaxiLookups of the flights that have left
+// the gate without a wheels-up time in the flights endpoint, which has
+// actual_off as soon as it is known, marking those that took off as
+// departed. It returns the number of lookups made. A failed lookup leaves
+// the flight taxiing until the next fetch
+func (c *FlightAwareClient) lookUpTakeoffs(ctx context.Context, flights []models.Flight) int {
+	lookups := 0
+	for i := range flights {
+		flight := &flights[i]
+		if lookups >= c.TaxiLookups || ctx.Err() != nil {
+			break
+		}
+		if flight.ID == "" || flight.ActualOut == nil || flight.ActualOff != nil || flight.Status == models.StatusCancelled {
+			continue
+		}
+		lookups++
+		found, err := c.GetFlight(ctx, flight.ID)
+		if err != nil {
+			slog.Debug("taxiing flight lookup failed", "flight", flight.Ident, "error", err)
+			continue
+		}
+		if found.ActualOff != nil {
+			flight.ActualOff = found.ActualOff
+			flight.Status, flight.Remarks = models.StatusDeparted, models.RemarksDeparted
+		}
+	}
+	return lookups
+}
+
+// GetArrivals fetches scheduled arrivals for an airport selected by opts
+// Uses the scheduled_arrivals endpoint, which lists flights that have not yet arrived
+func (c *FlightAwareClient) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return c.withinPlanLimit(airportCode, opts.Window, func(cutoffTime *time.Time) (FetchResult, error) {
+		return c.arrivalsUntil(ctx, airportCode, cutoffTime, opts)
+	})
+}
+
+// GetFlight fetches the flight with the fa_flight_id id from the flights
+// endpoint, converted as an arrival: its origin and the time it lands, the
+// actual time once it has, and when it left the gate and took off
+func (c *FlightAwareClient) GetFlight(ctx context.Context, id string) (models.Flight, error) {
+	body, err := c.get(ctx, "/flights/"+url.PathEscape(id), id)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		apiErr.Message = "flight not found: " + id
+	}
+	if err != nil {
+		return models.Flight{}, err
+	}
+	var page struct {
+		Flights []AeroAPIArrival `json:"flights"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return models.Flight{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	for _, arr := range page.Flights {
+		if arr.FaFlightID != id {
+			continue
+		}
+		scheduled, _ := arr.ScheduledTime()
+		flight := c.convertArrival(arr, scheduled)
+		if arr.ActualIn != nil && !arr.ActualIn.IsZero() {
+			flight.EstimatedArrival = arr.ActualIn
+		}
+		flight.ActualOut, flight.ActualOff = arr.ActualOut, arr.ActualOff
+		return flight, nil
+	}
+	return models.Flight{}, &APIError{Source: c.Name(), StatusCode: http.StatusNotFound, Message: "flight not found: " + id}
+}
+
+// arrivalsUntil fetches scheduled arrivals up to cutoffTime, or without an
+// end if it is nil
+func (c *FlightAwareClient) arrivalsUntil(ctx context.Context, airportCode string, cutoffTime *time.Time, opts FetchOptions) (FetchResult, error) {
+	now := c.Window.now()
+	limit := opts.limit(c.targetFlights())
+	var collected pageFlights
+	var skipped SkipReport
+	pages, err := c.fetchPages(ctx, airportCode, "scheduled_arrivals", cutoffTime, opts.maxPages(), func(page AeroAPIResponse) (int, bool) {
+		for _, arr := range page.ScheduledArrivals {
+			if c.Strict && !isBlocked(arr.Blocked, arr.Ident) {
+				// Blocked flights lack these fields by design, and are shown or hidden as such
+				if missing := arr.missingFields(); len(missing) > 0 {
+					skipped.add(sampleIdent(arr.Ident, arr.FaFlightID), missing)
+					continue
+				}
+			}
+			scheduled, ok := arr.ScheduledTime()
+			if !ok {
+				continue
+			}
+
+			if cutoffTime != nil && scheduled.After(*cutoffTime) {
+				continue
+			}
+			if !opts.keep(scheduled, now) {
+				continue
+			}
+
+			collected.add(c.convertArrival(arr, scheduled))
+		}
+		return len(page.ScheduledArrivals), len(collected.flights) >= limit
+	})
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	logSkipped(airportCode, skipped)
+	logDuplicates(airportCode, "scheduled_arrivals", &collected)
+	flights, total := capFlights(collected.flights, limit)
+	return FetchResult{Flights: flights, Pages: pages, Total: total, Skipped: skipped}, nil
+}
+
+// logSkipped logs the flights a strict client left out of an airport's
+// result, if any
+func logSkipped(airportCode string, skipped SkipReport) {
+	if skipped.Count > 0 {
+		slog.Warn("flights skipped for missing data", "airport", airportCode, "count", skipped.Count, "reasons", skipped.Reasons, "samples", skipped.Samples)
+	}
+}
+
+// windowEnd returns the end of the window flights are fetched for, or nil if
+// it has no end. A window longer than the plan allows is shortened to the
+// plan limit, which is returned too; otherwise the limit returned is zero
+func (c *FlightAwareClient) windowEnd(airportCode string, window time.Duration) (*time.Time, time.Duration) {
+	now := c.Window.now()
+	end, ok := c.Window.End(now, airportCode, window)
+	if limit := time.Duration(c.planLimit.Load()); limit > 0 && ok && end.Sub(now) > limit {
+		end = now.Add(limit)
+		return &end, limit
+	}
+	if !ok {
+		return nil, 0
+	}
+	return &end, 0
+}
+
+// withinPlanLimit runs fetch for the window flights are fetched for. When
+// the API rejects the window as longer than the plan allows, the limit is
+// remembered and fetch runs again for the longest window allowed, as every
+// later fetch does
+func (c *FlightAwareClient) withinPlanLimit(airportCode string, window time.Duration, fetch func(end *time.Time) (FetchResult, error)) (FetchResult, error) {
+	end, limited := c.windowEnd(airportCode, window)
+	result, err := fetch(end)
+	if limit, ok := windowLimit(err); ok && end != nil && limit < end.Sub(c.Window.now()) {
+		slog.Warn("AeroAPI plan limits the fetch window", "airport", airportCode, "limit", limit, "error", err)
+		c.planLimit.Store(int64(limit))
+		end, limited = c.windowEnd(airportCode, window)
+		result, err = fetch(end)
+	}
+	if err != nil {
+		return FetchResult{}, err
+	}
+	result.WindowLimit = limited
+	return result, nil
+}
+
+// targetFlights returns the number of flights to collect before paging stops
+func (c *FlightAwareClient) targetFlights() int {
+	if c.TargetFlights <= 0 {
+		return defaultTargetFlights
+	}
+	return c.TargetFlights
+}
+
+// fetchPages requests one of the airport flights endpoints a page at a time,
+// passing each page to collect. Further pages are only requested while collect
+// wants more flights, the previous page was full and fewer than maxPages have
+// been fetched, so quiet airports cost a single page. Flights are requested up
+// to end, the same cutoff the caller applies, or without an end if it is nil.
+// An empty body, or a first page without the endpoint's list of flights, fails
+// with ErrNoData; a later page without it ends the paging. Returns the number
+// of pages fetched
+func (c *FlightAwareClient) fetchPages(ctx context.Context, airportCode, endpoint string, end *time.Time, maxPages int, collect func(AeroAPIResponse) (int, bool)) (int, error) {
+	if maxPages < 1 {
+		maxPages = 1
 	}
 
 	// Build query parameters
 	params := url.Values{}
 
-	// Only add end time parameter if hours is specified (greater than 0)
-	if hours > 0 {
-		endTime := time.Now().Add(time.Duration(hours) * time.Hour)
-		endTimeISO8601 := endTime.Format(time.RFC3339)
+	// Only add end time parameter if the window has an end
+	if end != nil {
+		endTimeISO8601 := end.UTC().Format(time.RFC3339)
 		params.Add("end", endTimeISO8601)
 	}
 
-	// Only add max_pages parameter if it's greater than 1 (default is 1)
-	if maxPages > 1 {
-		params.Add("max_pages", fmt.Sprintf("%d", maxPages))
+	next := fmt.Sprintf("/airports/%s/flights/%s", airportCode, endpoint)
+	if len(params) > 0 {
+		next += "?" + params.Encode()
 	}
 
-	// Set query parameters if any were added
-	if len(params) > 0 {
-		reqURL.RawQuery = params.Encode()
+	check := newFieldCheck(endpoint)
+	pages := 0
+	for next != "" && pages < maxPages {
+		body, err := c.get(ctx, next, airportCode)
+		if err != nil {
+			return pages, err
+		}
+		pages++
+
+		if len(body) == 0 {
+			// AeroAPI always answers with JSON, so nothing at all was cut short
+			return pages, fmt.Errorf("%w: %s sent an empty response", ErrNoData, c.Name())
+		}
+
+		var page AeroAPIResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			slog.Warn("unparseable AeroAPI response", "airport", airportCode, "endpoint", endpoint, "bytes", len(body), "start", bodyStart(body))
+			return pages, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if !hasList(body, endpoint) {
+			slog.Warn("AeroAPI response without its flights", "airport", airportCode, "endpoint", endpoint, "page", pages, "start", bodyStart(body))
+			if pages == 1 {
+				return pages, fmt.Errorf("%w: %s sent no %s", ErrNoData, c.Name(), endpoint)
+			}
+			break
+		}
+		check.add(body)
+
+		count, done := collect(page)
+		if done || count < aeroAPIPageSize {
+			break
+		}
+		next = page.Links.nextPath()
 	}
 
-	fullURL := reqURL.String()
+	c.reportFields(check, airportCode)
+	slog.Debug("fetched AeroAPI pages", "airport", airportCode, "endpoint", endpoint, "pages", pages, "max_pages", maxPages)
+	return pages, nil
+}
+
+// get requests a path relative to the base URL and returns the raw body
+func (c *FlightAwareClient) get(ctx context.Context, path, airportCode string) ([]byte, error) {
+	reqURL, err := url.Parse(c.BaseURL + path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
 
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("x-apikey", c.APIKey)
 	req.Header.Set("Accept", "application/json")
+	// The wait comes before the trace, so the request timings leave it out
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	req, timer := traceRequest(req)
+	defer timer.done(c.Name(), path)
 
+	// Every request goes through here, so usage covers all endpoints
+	c.Usage.addRequest()
 	resp, err := c.Client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -120,84 +594,68 @@ func (c *FlightAwareClient) GetDepartures(airportCode string, hours int, maxPage
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusUnauthorized {
-		return nil, fmt.Errorf("API authentication failed: check your FLIGHTAWARE_API_KEY")
+		return nil, &APIError{Source: c.Name(), StatusCode: resp.StatusCode, Message: "API authentication failed: check your FLIGHTAWARE_API_KEY"}
 	}
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("airport not found: %s", airportCode)
+		return nil, &APIError{Source: c.Name(), StatusCode: resp.StatusCode, Message: "airport not found: " + airportCode}
 	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newStatusError(c.Name(), resp.StatusCode, body)
 	}
+	c.Usage.addPage()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	return body, nil
+}
 
-	if len(body) == 0 {
-		return []models.Flight{}, nil // Empty response is valid, just no flights
+// convertArrival converts an AeroAPI arrival to our Flight model
+func (c *FlightAwareClient) convertArrival(arr AeroAPIArrival, scheduled time.Time) models.Flight {
+	// Airline and flight number fields are shared with departures
+	flight := c.convertToFlight(AeroAPIDeparture{
+		Ident:        arr.Ident,
+		FaFlightID:   arr.FaFlightID,
+		Operator:     arr.Operator,
+		OperatorIata: arr.OperatorIata,
+		FlightNumber: arr.FlightNumber,
+	}, scheduled)
+
+	flight.Direction = models.Arrival
+	flight.ScheduledArrival = scheduled
+	flight.Gate = arr.Gate
+	flight.BaggageClaim = strings.TrimSpace(arr.BaggageClaim)
+	if arr.Origin != nil {
+		flight.OriginCode = arr.Origin.preferredCode()
+		flight.OriginCity = arr.Origin.City
 	}
-
-	var apiResp AeroAPIResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if arr.EstimatedIn != nil && !arr.EstimatedIn.IsZero() {
+		flight.EstimatedArrival = arr.EstimatedIn
 	}
 
-	// Filter and convert to our Flight model
-	flights := make([]models.Flight, 0)
-	now := time.Now()
-	// scheduled_departures endpoint defaults to 2 hours before current time
-	// We only need to filter by the future cutoff time if hours is specified
-	var cutoffTime *time.Time
-	if hours > 0 {
-		ct := now.Add(time.Duration(hours) * time.Hour)
-		cutoffTime = &ct
+	// Map arrival status to our enum
+	status := arr.Status
+	switch {
+	case status == "Cancelled":
+		flight.Status = models.StatusCancelled
+		flight.Remarks = models.RemarksCancelled
+	case strings.HasPrefix(status, "Arrived") || strings.HasPrefix(status, "Landed"):
+		flight.Status = models.StatusArrived
+		flight.Remarks = models.RemarksArrived
+	case strings.Contains(status, "Delayed"):
+		flight.Status = models.StatusDelayed
+		flight.Remarks = models.RemarksDelayed
+	default:
+		flight.Status = models.StatusOnTime
+		flight.Remarks = models.RemarksOnTime
 	}
-	maxFlights := 50
-
-	for _, dep := range apiResp.ScheduledDepartures {
-		if len(flights) >= maxFlights {
-			break
-		}
-
-		// Try to get scheduled time from various possible fields
-		var scheduled time.Time
-		var hasScheduled bool
-
-		// First try the nested departure.scheduled field
-		if dep.Departure != nil && !dep.Departure.Scheduled.IsZero() {
-			scheduled = dep.Departure.Scheduled
-			hasScheduled = true
-		} else if dep.ScheduledOut != nil && !dep.ScheduledOut.IsZero() {
-			// Try scheduled_out field
-			scheduled = *dep.ScheduledOut
-			hasScheduled = true
-		} else if dep.EstimatedOut != nil && !dep.EstimatedOut.IsZero() {
-			// Fall back to estimated_out if scheduled is not available
-			scheduled = *dep.EstimatedOut
-			hasScheduled = true
-		}
-
-		if !hasScheduled {
-			continue
-		}
 
-		if scheduled.IsZero() {
-			continue
-		}
-
-		// Filter flights departing within the specified future window (if cutoff is set)
-		// scheduled_departures endpoint already excludes en route flights and includes past 2 hours
-		if cutoffTime != nil && scheduled.After(*cutoffTime) {
-			continue
-		}
-
-		flight := c.convertToFlight(dep, scheduled)
-		flights = append(flights, flight)
+	if isBlocked(arr.Blocked, arr.Ident) {
+		return withheld(flight)
 	}
-
-	return flights, nil
+	return flight
 }
 
 // convertToFlight converts an AeroAPI departure to our Flight model
@@ -205,8 +663,8 @@ func (c *FlightAwareClient) convertToFlight(dep AeroAPIDeparture, scheduled time
 	// Use operator_iata (2-letter) for airline code
 	airlineCode := dep.OperatorIata
 	if airlineCode == "" {
-		// Fall back to operator (3-letter) if IATA not available
-		airlineCode = dep.Operator
+		// Map the operator (3-letter ICAO) to IATA, keeping it only if unmapped
+		airlineCode = AirlineIATA(dep.Operator)
 	}
 
 	// Use operator (3-letter code) as airline name, or fall back to IATA code
@@ -230,6 +688,9 @@ func (c *FlightAwareClient) convertToFlight(dep AeroAPIDeparture, scheduled time
 	fullFlightNumber := airlineCode + " " + flightNumber
 
 	flight := models.Flight{
+		ID:                 dep.FaFlightID,
+		InboundID:          dep.InboundID,
+		Ident:              dep.Ident,
 		AirlineCode:        airlineCode,
 		AirlineName:        airlineName,
 		FlightNumber:       fullFlightNumber,
@@ -237,28 +698,31 @@ func (c *FlightAwareClient) convertToFlight(dep AeroAPIDeparture, scheduled time
 	}
 
 	if dep.Destination != nil {
-		flight.DestinationCode = dep.Destination.CodeIata
-		if flight.DestinationCode == "" {
-			flight.DestinationCode = dep.Destination.Code
-		}
+		flight.DestinationCode = dep.Destination.preferredCode()
 		flight.DestinationCity = dep.Destination.City
 	}
 
 	flight.Gate = dep.Gate
+	flight.ActualOut = dep.ActualOut
+	flight.ActualOff = dep.ActualOff
 
 	// Determine status and remarks based on API status
 	status := dep.Status
 	remarks := dep.Remarks
 
-	// Map status to our enum
+	// Map status to our enum. Gate and wheels-up times win over the status
+	// text, which can lag behind them
 	switch {
 	case status == "Cancelled" || remarks == "Cancelled":
 		flight.Status = models.StatusCancelled
 		flight.Remarks = models.RemarksCancelled
+	case dep.ActualOff != nil:
+		flight.Status = models.StatusDeparted
+		flight.Remarks = models.RemarksDeparted
 	case status == "Taxiing / Delayed" || remarks == "Taxiing / Delayed":
 		flight.Status = models.StatusTaxiingDelayed
 		flight.Remarks = models.RemarksTaxiingDelayed
-	case status == "Taxiing / Left Gate" || remarks == "Taxiing / Left Gate":
+	case status == "Taxiing / Left Gate" || remarks == "Taxiing / Left Gate" || dep.ActualOut != nil:
 		flight.Status = models.StatusTaxiingLeftGate
 		flight.Remarks = models.RemarksTaxiingLeftGate
 	case status == "Scheduled / Delayed" || status == "Delayed" || remarks == "Delayed":
@@ -269,12 +733,14 @@ func (c *FlightAwareClient) convertToFlight(dep AeroAPIDeparture, scheduled time
 		} else if dep.Departure != nil && !dep.Departure.Estimated.IsZero() {
 			flight.EstimatedDeparture = &dep.Departure.Estimated
 		}
-		// Remarks will be set in UpdateFlights after timezone conversion
 		flight.Remarks = models.RemarksDelayed
 	default:
 		flight.Status = models.StatusOnTime
 		flight.Remarks = models.RemarksOnTime
 	}
 
+	if isBlocked(dep.Blocked, dep.Ident) {
+		return withheld(flight)
+	}
 	return flight
 }
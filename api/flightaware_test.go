@@ -0,0 +1,704 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// aeroAPINow is the clock the AeroAPI fixtures were recorded against
+var aeroAPINow = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// aeroAPIPage is a canned AeroAPI response: a fixture from testdata/aeroapi
+// and the status it is served with, 200 if zero
+type aeroAPIPage struct {
+	file   string
+	status int
+}
+
+// aeroAPIServer serves canned responses by request path, with "?cursor=" and
+// the cursor appended for later pages, answering 404 for any other path. It
+// returns a client of the server whose clock reads aeroAPINow, and the paths
+// requested
+func aeroAPIServer(t *testing.T, pages map[string]aeroAPIPage) (*FlightAwareClient, *[]string) {
+	t.Helper()
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Path
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			key += "?cursor=" + cursor
+		}
+		requested = append(requested, key)
+		page, ok := pages[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body []byte
+		if page.file != "" {
+			var err error
+			if body, err = os.ReadFile(filepath.Join("testdata", "aeroapi", page.file)); err != nil {
+				t.Errorf("fixture for %s: %v", key, err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if page.status != 0 {
+			w.WriteHeader(page.status)
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewFlightAwareClient("test-key", WithBaseURL(server.URL))
+	client.Window.Now = func() time.Time { return aeroAPINow }
+	return client, &requested
+}
+
+func TestPartialAirports(t *testing.T) {
+	client, _ := aeroAPIServer(t, map[string]aeroAPIPage{
+		"/airports/JFK/flights/scheduled_departures": {file: "partial_airports_departures.json"},
+		"/airports/JFK/flights/scheduled_arrivals":   {file: "partial_airports_arrivals.json"},
+	})
+
+	departures, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	arrivals, err := client.GetArrivals(context.Background(), "JFK", FetchOptions{})
+	if err != nil {
+		t.Fatalf("GetArrivals: %v", err)
+	}
+	flights := append(departures.Flights, arrivals.Flights...)
+
+	tests := []struct {
+		ident    string
+		code     string // Destination for departures, origin for arrivals
+		shown    string // GetDestination or GetOrigin
+		hasRoute bool
+	}{
+		{"AAL100", "LAX", "LAX Los Angeles", true},
+		{"N512GA", "", "", false},        // Positioning flight without a destination
+		{"ACA761", "CYYZ", "CYYZ", true}, // ICAO code only
+		{"JBU1101", "BOS", "BOS", true},  // No city, so no trailing space
+		{"DAL2207", "", "Springfield", true},
+		{"UAL523", "ORD", "ORD Chicago", true},
+		{"N77FR", "", "", false},                  // Arrival without an origin
+		{"WJA1500", "CYYC", "CYYC Calgary", true}, // Empty IATA code
+	}
+	if len(flights) != len(tests) {
+		t.Fatalf("got %d flights, want %d", len(flights), len(tests))
+	}
+	for i, tt := range tests {
+		flight := flights[i]
+		if flight.Ident != tt.ident {
+			t.Errorf("flight %d is %s, want %s", i, flight.Ident, tt.ident)
+			continue
+		}
+		code, shown := flight.DestinationCode, flight.GetDestination()
+		if i >= len(departures.Flights) {
+			code, shown = flight.OriginCode, flight.GetOrigin()
+		}
+		if code != tt.code || shown != tt.shown {
+			t.Errorf("%s: code %q shown as %q, want %q shown as %q", tt.ident, code, shown, tt.code, tt.shown)
+		}
+		if flight.HasRoute() != tt.hasRoute {
+			t.Errorf("%s: HasRoute = %v, want %v", tt.ident, flight.HasRoute(), tt.hasRoute)
+		}
+	}
+}
+
+// departurePages routes the departures fixtures of the small (BGR), medium
+// (PDX) and huge (ATL) airports
+func departurePages() map[string]aeroAPIPage {
+	pages := map[string]aeroAPIPage{
+		"/airports/BGR/flights/scheduled_departures":             {file: "departures_bgr.json"},
+		"/airports/PDX/flights/scheduled_departures":             {file: "departures_pdx_1.json"},
+		"/airports/PDX/flights/scheduled_departures?cursor=pdx2": {file: "departures_pdx_2.json"},
+		"/airports/ATL/flights/scheduled_departures":             {file: "departures_atl_1.json"},
+	}
+	for i := 2; i <= 5; i++ {
+		pages[fmt.Sprintf("/airports/ATL/flights/scheduled_departures?cursor=atl%d", i)] = aeroAPIPage{file: fmt.Sprintf("departures_atl_%d.json", i)}
+	}
+	return pages
+}
+
+func TestAdaptivePaging(t *testing.T) {
+	tests := []struct {
+		name     string
+		airport  string
+		maxPages int
+		target   int
+		pages    int // Pages requested
+		flights  int
+		total    int
+	}{
+		// A page that isn't full is the last, however many more are allowed
+		{"small airport", "BGR", 5, 0, 1, 6, 6},
+		{"medium airport", "PDX", 5, 0, 2, 24, 24},
+		// Paging stops once enough flights were collected
+		{"huge airport", "ATL", 10, 0, 4, 50, 60},
+		{"huge airport with a smaller target", "ATL", 10, 20, 2, 20, 30},
+		// MAX_PAGES bounds it
+		{"huge airport capped by max pages", "ATL", 3, 0, 3, 45, 45},
+		{"medium airport capped by max pages", "PDX", 1, 0, 1, 15, 15},
+		{"zero max pages fetches one", "ATL", 0, 0, 1, 15, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requested := aeroAPIServer(t, departurePages())
+			client.TargetFlights = tt.target
+			usage := client.Usage
+			result, err := client.GetDepartures(context.Background(), tt.airport, FetchOptions{MaxPages: tt.maxPages})
+			if err != nil {
+				t.Fatalf("GetDepartures: %v", err)
+			}
+			if len(*requested) != tt.pages || result.Pages != tt.pages {
+				t.Errorf("requested %d pages and reported %d, want %d: %v", len(*requested), result.Pages, tt.pages, *requested)
+			}
+			if len(result.Flights) != tt.flights || result.Total != tt.total {
+				t.Errorf("got %d of %d flights, want %d of %d", len(result.Flights), result.Total, tt.flights, tt.total)
+			}
+			if got := usage.Pages(); got != int64(tt.pages) {
+				t.Errorf("usage counted %d pages, want %d", got, tt.pages)
+			}
+			for i := 1; i < len(result.Flights); i++ {
+				if result.Flights[i].ScheduledDeparture.Before(result.Flights[i-1].ScheduledDeparture) {
+					t.Errorf("flight %d leaves before flight %d", i, i-1)
+				}
+			}
+		})
+	}
+}
+
+// TestOverlappingPages fetches two PDX pages the second of which lists the
+// last two flights of the first again, with a new gate and a delay, as
+// AeroAPI does when the pages shift between requests
+func TestOverlappingPages(t *testing.T) {
+	logs := recordLogs(t)
+	client, requested := aeroAPIServer(t, map[string]aeroAPIPage{
+		"/airports/PDX/flights/scheduled_departures":                 {file: "departures_overlap_1.json"},
+		"/airports/PDX/flights/scheduled_departures?cursor=overlap2": {file: "departures_overlap_2.json"},
+	})
+	// Twins would count towards the target, which the 24 flights only just
+	// reach without them
+	client.TargetFlights = 24
+	result, err := client.GetDepartures(context.Background(), "PDX", FetchOptions{MaxPages: 5})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if len(*requested) != 2 {
+		t.Errorf("requested %v, want both pages", *requested)
+	}
+	if len(result.Flights) != 24 || result.Total != 24 {
+		t.Errorf("got %d of %d flights, want the 24 different ones", len(result.Flights), result.Total)
+	}
+	seen := make(map[string]bool)
+	for i, flight := range result.Flights {
+		if seen[flight.ID] {
+			t.Errorf("flight %d, %s, shown twice", i, flight.ID)
+		}
+		seen[flight.ID] = true
+		if i > 0 && flight.ScheduledDeparture.Before(result.Flights[i-1].ScheduledDeparture) {
+			t.Errorf("flight %d leaves before flight %d", i, i-1)
+		}
+	}
+
+	// The later page's copies are kept, where the first page listed them
+	dal, ual := result.Flights[13], result.Flights[14]
+	if dal.Ident != "DAL191" || dal.Gate != "C9" {
+		t.Errorf("flight 13 is %s at gate %s, want DAL191 at its new gate C9", dal.Ident, dal.Gate)
+	}
+	if ual.Ident != "UAL198" || ual.Status != models.StatusDelayed {
+		t.Errorf("flight 14 is %s %s, want UAL198 delayed", ual.Ident, ual.Status)
+	}
+	if got := logs.attr("dropped flights repeated across AeroAPI pages", "duplicates"); got != "2" {
+		t.Errorf("logged %q duplicates, want 2", got)
+	}
+}
+
+// TestNoData fetches the answers in testdata/aeroapi/nodata that come back
+// without flights, checking that only a list with none in it passes as an
+// airport with nothing scheduled
+func TestNoData(t *testing.T) {
+	const departures = "/airports/BGR/flights/scheduled_departures"
+	tests := []struct {
+		name     string
+		file     string
+		arrivals bool
+		noData   bool   // Fails with ErrNoData
+		parse    bool   // Fails to parse
+		warning  string // Logged at warning level
+	}{
+		{"empty body", "nodata/empty.json", false, true, false, ""},
+		{"empty object", "nodata/object.json", false, true, false, "AeroAPI response without its flights"},
+		{"null list", "nodata/null_departures.json", false, true, false, "AeroAPI response without its flights"},
+		{"another endpoint's list", "nodata/no_departures.json", true, true, false, "AeroAPI response without its flights"},
+		{"truncated", "nodata/truncated.json", false, false, true, "unparseable AeroAPI response"},
+		{"no flights in the window", "nodata/no_departures.json", false, false, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := recordLogs(t)
+			path := departures
+			if tt.arrivals {
+				path = "/airports/BGR/flights/scheduled_arrivals"
+			}
+			client, _ := aeroAPIServer(t, map[string]aeroAPIPage{path: {file: tt.file}})
+			direction := models.Departure
+			if tt.arrivals {
+				direction = models.Arrival
+			}
+			result, err := GetFlights(context.Background(), client, direction, "BGR", FetchOptions{})
+			if got := errors.Is(err, ErrNoData); got != tt.noData {
+				t.Errorf("error %v, ErrNoData %v, want %v", err, got, tt.noData)
+			}
+			if got := err != nil && strings.Contains(err.Error(), "failed to parse response"); got != tt.parse {
+				t.Errorf("error %v, parse failure %v, want %v", err, got, tt.parse)
+			}
+			if err == nil && len(result.Flights) != 0 {
+				t.Errorf("got %d flights, want none", len(result.Flights))
+			}
+			warnings := logs.messages(slog.LevelWarn)
+			if tt.warning == "" && len(warnings) > 0 || tt.warning != "" && !slices.Contains(warnings, tt.warning) {
+				t.Errorf("warnings %q, want %q", warnings, tt.warning)
+			}
+		})
+	}
+}
+
+// TestNoDataTruncatedLogged checks that a response cut short is logged with
+// its first 200 bytes
+func TestNoDataTruncatedLogged(t *testing.T) {
+	logs := recordLogs(t)
+	client, _ := aeroAPIServer(t, map[string]aeroAPIPage{
+		"/airports/BGR/flights/scheduled_departures": {file: "nodata/truncated.json"},
+	})
+	if _, err := client.GetDepartures(context.Background(), "BGR", FetchOptions{}); err == nil {
+		t.Fatal("truncated response parsed")
+	}
+	body, err := os.ReadFile(filepath.Join("testdata", "aeroapi", "nodata", "truncated.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := logs.attr("unparseable AeroAPI response", "start"); got != string(body[:200]) {
+		t.Errorf("logged the start %q, want the first 200 bytes %q", got, body[:200])
+	}
+	if got := logs.attr("unparseable AeroAPI response", "bytes"); got != strconv.Itoa(len(body)) {
+		t.Errorf("logged %s bytes, want %d", got, len(body))
+	}
+}
+
+// TestNoDataLaterPage checks that a later page without its list ends the
+// paging with the flights of the pages before it
+func TestNoDataLaterPage(t *testing.T) {
+	logs := recordLogs(t)
+	client, requested := aeroAPIServer(t, map[string]aeroAPIPage{
+		"/airports/PDX/flights/scheduled_departures":                {file: "nodata/departures_pdx_1.json"},
+		"/airports/PDX/flights/scheduled_departures?cursor=nodata2": {file: "nodata/object.json"},
+	})
+	result, err := client.GetDepartures(context.Background(), "PDX", FetchOptions{MaxPages: 5})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if len(*requested) != 2 || len(result.Flights) != 15 {
+		t.Errorf("requested %v for %d flights, want both pages and the first page's 15", *requested, len(result.Flights))
+	}
+	if got := logs.attr("AeroAPI response without its flights", "page"); got != "2" {
+		t.Errorf("warned of page %q without its flights, want 2", got)
+	}
+}
+
+func TestFlightAwareOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		client := NewFlightAwareClient("key")
+		if client.BaseURL != flightAwareBaseURL {
+			t.Errorf("BaseURL = %q, want %q", client.BaseURL, flightAwareBaseURL)
+		}
+		if client.Client.Timeout != defaultFlightAwareTimeout {
+			t.Errorf("timeout = %v, want %v", client.Client.Timeout, defaultFlightAwareTimeout)
+		}
+		if client.Usage == nil {
+			t.Error("no usage counter")
+		}
+	})
+
+	t.Run("base URL loses its trailing slashes", func(t *testing.T) {
+		client := NewFlightAwareClient("key", WithBaseURL("http://localhost:8080/aeroapi//"))
+		if want := "http://localhost:8080/aeroapi"; client.BaseURL != want {
+			t.Errorf("BaseURL = %q, want %q", client.BaseURL, want)
+		}
+	})
+
+	t.Run("HTTP client", func(t *testing.T) {
+		httpClient := &http.Client{}
+		client := NewFlightAwareClient("key", WithHTTPClient(httpClient))
+		if client.Client != httpClient {
+			t.Error("client not used")
+		}
+	})
+
+	t.Run("timeout copies the HTTP client", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Minute}
+		client := NewFlightAwareClient("key", WithHTTPClient(httpClient), WithTimeout(5*time.Second))
+		if client.Client.Timeout != 5*time.Second {
+			t.Errorf("timeout = %v, want 5s", client.Client.Timeout)
+		}
+		if client.Client == httpClient || httpClient.Timeout != time.Minute {
+			t.Errorf("client passed in was modified: timeout %v", httpClient.Timeout)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		usage := &Usage{}
+		client := NewFlightAwareClient("key", WithUsage(usage))
+		if client.Usage != usage {
+			t.Error("usage not shared")
+		}
+	})
+}
+
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"https://aeroapi.flightaware.com/aeroapi", ""},
+		{"http://localhost:8080", ""},
+		{"ftp://example.com/aeroapi", "scheme must be http or https"},
+		{"aeroapi.flightaware.com/aeroapi", "scheme must be http or https"},
+		{"https:///aeroapi", "missing host"},
+		{"http://[::1", "invalid URL"},
+	}
+	for _, tt := range tests {
+		err := ValidateBaseURL(tt.url)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateBaseURL(%q): %v", tt.url, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidateBaseURL(%q) error = %v, want one containing %q", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+// TestTaxiPhases checks flights at the gate, taxiing and in the air are told
+// apart by their gate and wheels-up times, and that taxiing flights are
+// looked up for a wheels-up time the departures list doesn't have yet
+func TestTaxiPhases(t *testing.T) {
+	pages := map[string]aeroAPIPage{
+		"/airports/JFK/flights/scheduled_departures": {file: "departures_taxi.json"},
+		"/flights/AAL101-1767182400-schedule-0000":   {file: "flight_taxi_off.json"},
+		"/flights/AAL102-1767182400-schedule-0000":   {file: "flight_taxi_out.json"},
+	}
+	tests := []struct {
+		lookups int
+		pages   int
+		want    []string // Status and wheels-up time of each flight
+	}{
+		{0, 1, []string{"Departed 11:52", "Taxiing / Left Gate", "Taxiing / Left Gate", "On Time"}},
+		{1, 2, []string{"Departed 11:52", "Departed 11:58", "Taxiing / Left Gate", "On Time"}},
+		// A flight still taxiing when looked up stays taxiing
+		{2, 3, []string{"Departed 11:52", "Departed 11:58", "Taxiing / Left Gate", "On Time"}},
+		{5, 3, []string{"Departed 11:52", "Departed 11:58", "Taxiing / Left Gate", "On Time"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d lookups", tt.lookups), func(t *testing.T) {
+			client, requested := aeroAPIServer(t, pages)
+			client.TaxiLookups = tt.lookups
+			result, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{IncludePast: true})
+			if err != nil {
+				t.Fatalf("GetDepartures: %v", err)
+			}
+			if len(*requested) != tt.pages || result.Pages != tt.pages {
+				t.Errorf("requested %d pages and reported %d, want %d: %v", len(*requested), result.Pages, tt.pages, *requested)
+			}
+			var got []string
+			for _, f := range result.Flights {
+				phase := string(f.Remarks)
+				if f.ActualOff != nil {
+					phase += " " + f.ActualOff.UTC().Format("15:04")
+				}
+				if (f.ActualOut != nil) != (f.Status != models.StatusOnTime) {
+					t.Errorf("%s is %s with gate departure %v", f.Ident, f.Remarks, f.ActualOut)
+				}
+				got = append(got, phase)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("flights %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPlanWindowLimit serves AeroAPI's 400 for a window past the plan limit,
+// checking the client fetches again for the longest window allowed, keeps to
+// it for later fetches and reports it in the result
+func TestPlanWindowLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rejection string        // Fixture served for an end past the limit
+		allowed   time.Duration // Longest window the server accepts
+		window    time.Duration
+		want      time.Duration // WindowLimit reported
+		wantEnds  []time.Duration
+	}{
+		{"limit named", "error_window_limit.json", 48 * time.Hour, 72 * time.Hour, 48 * time.Hour, []time.Duration{72 * time.Hour, 48 * time.Hour, 48 * time.Hour}},
+		{"limit not named", "error_window.json", 24 * time.Hour, 48 * time.Hour, 24 * time.Hour, []time.Duration{48 * time.Hour, 24 * time.Hour, 24 * time.Hour}},
+		// A window the plan allows is never shortened
+		{"within the limit", "error_window.json", 24 * time.Hour, 6 * time.Hour, 0, []time.Duration{6 * time.Hour, 6 * time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ends []time.Duration
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				end, err := time.Parse(time.RFC3339, r.URL.Query().Get("end"))
+				if err != nil {
+					t.Errorf("request without an end: %s", r.URL)
+				}
+				ends = append(ends, end.Sub(aeroAPINow))
+				file := "departures_bgr.json"
+				if end.Sub(aeroAPINow) > tt.allowed {
+					file = tt.rejection
+					w.WriteHeader(http.StatusBadRequest)
+				}
+				body, err := os.ReadFile(filepath.Join("testdata", "aeroapi", file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.Write(body)
+			}))
+			defer server.Close()
+			client := NewFlightAwareClient("test-key", WithBaseURL(server.URL))
+			client.Window.Now = func() time.Time { return aeroAPINow }
+
+			for fetch := range 2 {
+				result, err := client.GetDepartures(context.Background(), "BGR", FetchOptions{Window: tt.window})
+				if err != nil {
+					t.Fatalf("fetch %d: %v", fetch+1, err)
+				}
+				if result.WindowLimit != tt.want || len(result.Flights) == 0 {
+					t.Errorf("fetch %d: WindowLimit = %s with %d flights, want %s with flights", fetch+1, result.WindowLimit, len(result.Flights), tt.want)
+				}
+			}
+			if fmt.Sprint(ends) != fmt.Sprint(tt.wantEnds) {
+				t.Errorf("windows requested = %v, want %v", ends, tt.wantEnds)
+			}
+		})
+	}
+}
+
+// TestOtherBadRequest checks that a 400 for anything but the window is
+// neither retried nor taken for a plan limit, and is shown by its detail
+func TestOtherBadRequest(t *testing.T) {
+	client, requested := aeroAPIServer(t, map[string]aeroAPIPage{
+		"/airports/BGR/flights/scheduled_departures": {file: "error_argument.json", status: http.StatusBadRequest},
+	})
+	_, err := client.GetDepartures(context.Background(), "BGR", FetchOptions{Window: 48 * time.Hour})
+	const want = "FlightAware API error (status 400): Invalid argument: max_pages must be a positive integer"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+	if len(*requested) != 1 {
+		t.Errorf("requested %v, want a single request", *requested)
+	}
+	if _, ok := windowLimit(err); ok {
+		t.Errorf("a rejected argument was taken for a window limit")
+	}
+}
+
+// TestBlockedFlights checks that flights AeroAPI marks blocked keep their
+// times and status but nothing naming them or their route, whether flagged
+// blocked with every field sent or listed under the ident BLOCKED
+func TestBlockedFlights(t *testing.T) {
+	client, _ := aeroAPIServer(t, map[string]aeroAPIPage{
+		"/airports/PDX/flights/scheduled_departures": {file: "departures_blocked.json"},
+	})
+	client.Strict = true // Blocked flights lack required fields by design, and are kept even so
+	result, err := client.GetDepartures(context.Background(), "PDX", FetchOptions{MaxPages: 1})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if len(result.Flights) != 3 {
+		t.Fatalf("got %d flights, want 3", len(result.Flights))
+	}
+	if open := result.Flights[0]; open.Blocked || open.Ident != "AAL100" || open.DestinationCode != "BOS" {
+		t.Errorf("flight not blocked withheld: %+v", open)
+	}
+
+	for i, want := range []struct {
+		id        string
+		scheduled string
+		estimated string
+		status    models.FlightStatus
+	}{
+		{"EJA512-1767182400-schedule-0001", "12:20", "12:45", models.StatusDelayed},
+		{"BLOCKED-1767182400-schedule-0002", "12:30", "", models.StatusOnTime},
+	} {
+		flight := result.Flights[i+1]
+		if !flight.Blocked || flight.ID != want.id || flight.AirlineName != BlockedAirline {
+			t.Errorf("flight %d not withheld as blocked: %+v", i+1, flight)
+		}
+		withheldFields := map[string]string{
+			"ident": flight.Ident, "flight number": flight.FlightNumber, "airline": flight.AirlineCode,
+			"origin": flight.OriginCode, "origin city": flight.OriginCity,
+			"destination": flight.DestinationCode, "destination city": flight.DestinationCity, "gate": flight.Gate,
+		}
+		for field, value := range withheldFields {
+			if value != "" {
+				t.Errorf("blocked flight %s shows its %s %q", want.id, field, value)
+			}
+		}
+		if got := flight.ScheduledDeparture.UTC().Format("15:04"); got != want.scheduled {
+			t.Errorf("blocked flight %s scheduled at %s, want %s", want.id, got, want.scheduled)
+		}
+		if want.estimated != "" && (flight.EstimatedDeparture == nil || flight.EstimatedDeparture.UTC().Format("15:04") != want.estimated) {
+			t.Errorf("blocked flight %s estimated at %v, want %s", want.id, flight.EstimatedDeparture, want.estimated)
+		}
+		if flight.Status != want.status {
+			t.Errorf("blocked flight %s %s, want %s", want.id, flight.Status, want.status)
+		}
+	}
+}
+
+// TestGetRoute fetches ATL routes from the paged fixtures, checking pages
+// are searched for flights on the route up to MAX_PAGES, and that a route
+// nothing flies comes back empty rather than failing
+func TestGetRoute(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		destination string
+		maxPages    int
+		flights     int
+	}{
+		{"many on the route", "BOS", 5, 13},
+		{"many, capped by max pages", "BOS", 2, 5},
+		{"none on the route", "PHX", 3, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requested := aeroAPIServer(t, departurePages())
+			result, err := GetRoute(context.Background(), client, "ATL", tt.destination, FetchOptions{MaxPages: tt.maxPages})
+			if err != nil {
+				t.Fatalf("GetRoute: %v", err)
+			}
+			if len(*requested) != tt.maxPages {
+				t.Errorf("requested %d pages, want all %d searched", len(*requested), tt.maxPages)
+			}
+			if len(result.Flights) != tt.flights || result.Total != tt.flights || result.Source != client.Name() {
+				t.Errorf("got %d of %d flights from %q, want %d from %s", len(result.Flights), result.Total, result.Source, tt.flights, client.Name())
+			}
+			for i, flight := range result.Flights {
+				if flight.DestinationCode != tt.destination {
+					t.Errorf("flight %d is bound for %s, off the route", i, flight.DestinationCode)
+				}
+			}
+		})
+	}
+}
+
+// TestGetRouteFiltered checks that the departures of a provider that can't
+// fetch routes are filtered to the route's destination
+func TestGetRouteFiltered(t *testing.T) {
+	flights := []models.Flight{
+		{ID: "1", OriginCode: "ATL", DestinationCode: "BOS"},
+		{ID: "2", OriginCode: "ATL", DestinationCode: "ORD"},
+		{ID: "3", OriginCode: "ATL", DestinationCode: "BOS "},
+	}
+	for destination, want := range map[string]int{"BOS": 2, "ORD": 1, "PHX": 0} {
+		result, err := GetRoute(context.Background(), stubProvider{flights: flights}, "ATL", destination, FetchOptions{})
+		if err != nil || len(result.Flights) != want || result.Total != want || result.Source != "Stub" {
+			t.Errorf("route to %s: %d of %d flights from %q, %v; want %d from Stub", destination, len(result.Flights), result.Total, result.Source, err, want)
+		}
+	}
+}
+
+// TestCapFlights fetches more ATL flights than the target from the paged
+// fixtures, checking the soonest are kept and the number found reported
+func TestCapFlights(t *testing.T) {
+	logs := recordLogs(t)
+	client, _ := aeroAPIServer(t, departurePages())
+	client.TargetFlights = 20
+	result, err := client.GetDepartures(context.Background(), "ATL", FetchOptions{MaxPages: 10})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if len(result.Flights) != 20 || result.Total != 30 {
+		t.Fatalf("got %d of %d flights, want the first 20 of 30", len(result.Flights), result.Total)
+	}
+	if logs.attr("flight list capped", "kept") != "20" || logs.attr("flight list capped", "found") != "30" {
+		t.Errorf("cap not logged: %v", logs.messages(slog.LevelInfo))
+	}
+
+	// The flights dropped are the latest, wherever the source listed them
+	all, total := capFlights(append([]models.Flight(nil), result.Flights...), 0)
+	if total != 20 || len(all) != 20 {
+		t.Errorf("capFlights without a cap kept %d of %d", len(all), total)
+	}
+	reversed := slices.Clone(result.Flights)
+	slices.Reverse(reversed)
+	kept, total := capFlights(reversed, 5)
+	if total != 20 || len(kept) != 5 {
+		t.Fatalf("capFlights kept %d of %d, want 5 of 20", len(kept), total)
+	}
+	for i, flight := range kept {
+		if flight.ID != result.Flights[i].ID {
+			t.Errorf("flight %d kept is %s, want the soonest %s", i, flight.ID, result.Flights[i].ID)
+		}
+	}
+}
+
+// TestBaggageClaim fetches arrivals before and after a belt is assigned,
+// checking the claim is carried onto the flights, trimmed, and shows as a
+// change of the flight between the fetches
+func TestBaggageClaim(t *testing.T) {
+	var fetched [][]models.Flight
+	for _, file := range []string{"arrivals_claim_1.json", "arrivals_claim_2.json"} {
+		client, _ := aeroAPIServer(t, map[string]aeroAPIPage{
+			"/airports/JFK/flights/scheduled_arrivals": {file: file},
+		})
+		result, err := client.GetArrivals(context.Background(), "JFK", FetchOptions{MaxPages: 1})
+		if err != nil {
+			t.Fatalf("%s: GetArrivals: %v", file, err)
+		}
+		fetched = append(fetched, result.Flights)
+	}
+	for i, want := range [][]string{{"", "5"}, {"7", "5"}} {
+		var claims []string
+		for _, flight := range fetched[i] {
+			claims = append(claims, flight.BaggageClaim)
+		}
+		if !slices.Equal(claims, want) {
+			t.Errorf("fetch %d: claims %q, want %q", i+1, claims, want)
+		}
+	}
+
+	diff := models.DiffFlights(fetched[0], fetched[1])
+	if len(diff.Matched) != 2 {
+		t.Fatalf("%d flights matched across the fetches, want 2", len(diff.Matched))
+	}
+	for _, change := range diff.Matched {
+		claim, ok := change.Field(models.FieldBaggageClaim)
+		if flight := fetched[1][change.New].Ident; flight == "UAL523" {
+			if !ok || claim.Old != "" || claim.New != "7" || len(change.Fields) != 1 {
+				t.Errorf("UAL523 changes %+v, want only its claim assigned belt 7", change.Fields)
+			}
+		} else if ok {
+			t.Errorf("%s claim changed %q to %q", flight, claim.Old, claim.New)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// nearbyRadius is how far away, in statute miles, an airport is still
+// suggested as an alternative
+const nearbyRadius = 150
+
+// earthRadiusMiles is the mean radius of the Earth in statute miles
+const earthRadiusMiles = 3958.8
+
+// location is an airport's position in decimal degrees
+type location struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// serviceAirports are airports known to have scheduled airline service,
+// which are suggested when a board stays empty: the airports of the ICAO
+// table and busy secondary airports near them
+var serviceAirports = map[string]location{
+	// US East Coast
+	"JFK": {40.6413, -73.7781},
+	"LGA": {40.7769, -73.8740},
+	"EWR": {40.6895, -74.1745},
+	"ISP": {40.7952, -73.1002},
+	"HPN": {41.0670, -73.7076},
+	"BOS": {42.3656, -71.0096},
+	"PVD": {41.7240, -71.4283},
+	"MHT": {42.9326, -71.4357},
+	"MIA": {25.7959, -80.2870},
+	"FLL": {26.0742, -80.1506},
+	"PBI": {26.6832, -80.0956},
+	"MCO": {28.4312, -81.3081},
+	"ATL": {33.6407, -84.4277},
+	"CLT": {35.2140, -80.9431},
+	"DCA": {38.8512, -77.0402},
+	"IAD": {38.9531, -77.4565},
+	"PHL": {39.8744, -75.2424},
+	"BWI": {39.1754, -76.6683},
+
+	// US Central
+	"ORD": {41.9742, -87.9073},
+	"MDW": {41.7868, -87.7522},
+	"DFW": {32.8998, -97.0403},
+	"DAL": {32.8471, -96.8518},
+	"IAH": {29.9902, -95.3368},
+	"HOU": {29.6454, -95.2789},
+	"AUS": {30.1975, -97.6664},
+	"BNA": {36.1263, -86.6774},
+	"MSP": {44.8848, -93.2223},
+	"STL": {38.7499, -90.3748},
+	"DTW": {42.2162, -83.3554},
+	"CLE": {41.4058, -81.8539},
+
+	// US Mountain
+	"DEN": {39.8561, -104.6737},
+	"PHX": {33.4342, -112.0116},
+	"SLC": {40.7899, -111.9791},
+
+	// US West Coast
+	"LAX": {33.9416, -118.4085},
+	"BUR": {34.2007, -118.3590},
+	"LGB": {33.8177, -118.1516},
+	"SNA": {33.6762, -117.8675},
+	"SFO": {37.6213, -122.3790},
+	"OAK": {37.7126, -122.2197},
+	"SJC": {37.3639, -121.9289},
+	"SAN": {32.7338, -117.1933},
+	"SEA": {47.4502, -122.3088},
+	"PDX": {45.5898, -122.5951},
+	"LAS": {36.0840, -115.1537},
+
+	// Alaska and Hawaii
+	"ANC": {61.1743, -149.9962},
+	"HNL": {21.3245, -157.9251},
+
+	// Europe
+	"LHR": {51.4700, -0.4543},
+	"LGW": {51.1537, -0.1821},
+	"CDG": {49.0097, 2.5479},
+	"FRA": {50.0379, 8.5622},
+	"AMS": {52.3105, 4.7683},
+	"MAD": {40.4983, -3.5676},
+	"FCO": {41.8003, 12.2389},
+	"ZRH": {47.4582, 8.5555},
+	"VIE": {48.1103, 16.5697},
+	"CPH": {55.6180, 12.6508},
+	"ARN": {59.6498, 17.9238},
+	"OSL": {60.1976, 11.1004},
+	"HEL": {60.3172, 24.9633},
+	"DUB": {53.4264, -6.2499},
+	"BRU": {50.9010, 4.4856},
+
+	// Asia
+	"NRT": {35.7720, 140.3929},
+	"HND": {35.5494, 139.7798},
+	"ICN": {37.4602, 126.4407},
+	"PEK": {40.0799, 116.6031},
+	"PVG": {31.1443, 121.8083},
+	"HKG": {22.3080, 113.9185},
+	"SIN": {1.3644, 103.9915},
+	"BKK": {13.6900, 100.7501},
+	"DXB": {25.2532, 55.3657},
+	"AUH": {24.4330, 54.6511},
+	"IST": {41.2753, 28.7519},
+
+	// Middle East
+	"TLV": {32.0055, 34.8854},
+	"CAI": {30.1219, 31.4056},
+	"JED": {21.6796, 39.1565},
+	"RUH": {24.9576, 46.6988},
+
+	// Australia
+	"SYD": {-33.9399, 151.1753},
+	"MEL": {-37.6690, 144.8410},
+	"BNE": {-27.3842, 153.1175},
+	"PER": {-31.9385, 115.9672},
+
+	// Canada
+	"YYZ": {43.6777, -79.6248},
+	"YVR": {49.1967, -123.1815},
+	"YUL": {45.4706, -73.7408},
+	"YYC": {51.1215, -114.0076},
+
+	// South America
+	"GRU": {-23.4356, -46.4731},
+	"GIG": {-22.8100, -43.2506},
+	"EZE": {-34.8222, -58.5358},
+	"SCL": {-33.3930, -70.7858},
+	"LIM": {-12.0219, -77.1143},
+	"BOG": {4.7016, -74.1469},
+	"MEX": {19.4361, -99.0719},
+}
+
+// airportLocations are the positions of airports without scheduled service,
+// such as busy general aviation fields, added to by the airports data file
+var airportLocations = map[string]location{
+	"FRG": {40.7288, -73.4134},
+	"TEB": {40.8501, -74.0608},
+	"VNY": {34.2098, -118.4898},
+	"PDK": {33.8756, -84.3020},
+	"APA": {39.5701, -104.8493},
+}
+
+// NearbyAirport is an airport with scheduled service near another
+type NearbyAirport struct {
+	Code  string
+	Miles int // Great-circle distance, rounded
+}
+
+// HasScheduledService reports whether code is an airport known to have
+// scheduled airline service
+func HasScheduledService(code string) bool {
+	_, ok := serviceAirports[strings.ToUpper(code)]
+	return ok
+}
+
+// NearbyServiceAirports returns up to n airports with scheduled service
+// within 150 miles of code, nearest first. It returns nil if code has
+// scheduled service itself or its position is unknown
+func NearbyServiceAirports(code string, n int) []NearbyAirport {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if HasScheduledService(code) {
+		return nil
+	}
+	from, ok := airportLocations[code]
+	if !ok {
+		return nil
+	}
+	var nearby []NearbyAirport
+	for other, at := range serviceAirports {
+		if miles := distanceMiles(from, at); miles <= nearbyRadius {
+			nearby = append(nearby, NearbyAirport{Code: other, Miles: int(math.Round(miles))})
+		}
+	}
+	sort.Slice(nearby, func(i, j int) bool {
+		if nearby[i].Miles != nearby[j].Miles {
+			return nearby[i].Miles < nearby[j].Miles
+		}
+		return nearby[i].Code < nearby[j].Code
+	})
+	if len(nearby) > n {
+		nearby = nearby[:n]
+	}
+	return nearby
+}
+
+// distanceMiles returns the great-circle distance between a and b in
+// statute miles, by the haversine formula
+func distanceMiles(a, b location) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(min(1, h)))
+}
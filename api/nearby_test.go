@@ -0,0 +1,48 @@
+package api
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNearbyServiceAirports(t *testing.T) {
+	tests := []struct {
+		code string
+		n    int
+		want []NearbyAirport
+	}{
+		// Nearest first, as many as asked for
+		{"FRG", 3, []NearbyAirport{{"ISP", 17}, {"JFK", 20}, {"LGA", 24}}},
+		{"TEB", 2, []NearbyAirport{{"LGA", 11}, {"EWR", 13}}},
+		// Only those within 150 miles, however many are asked for
+		{" frg ", 20, []NearbyAirport{{"ISP", 17}, {"JFK", 20}, {"LGA", 24}, {"HPN", 28}, {"EWR", 40}, {"PHL", 113}, {"PVD", 124}}},
+		{"APA", 3, []NearbyAirport{{"DEN", 22}}},
+		// Airports with service of their own, or unknown ones, get none
+		{"JFK", 3, nil},
+		{"XXX", 3, nil},
+	}
+	for _, tt := range tests {
+		if got := NearbyServiceAirports(tt.code, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NearbyServiceAirports(%q, %d) = %v, want %v", tt.code, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestDistanceMiles(t *testing.T) {
+	tests := []struct {
+		a, b location
+		want float64
+	}{
+		{serviceAirports["JFK"], serviceAirports["LAX"], 2470},
+		{serviceAirports["LAX"], serviceAirports["JFK"], 2470},
+		{serviceAirports["LHR"], serviceAirports["CDG"], 216},
+		{serviceAirports["SYD"], serviceAirports["SYD"], 0},
+		{location{0, 0}, location{0, 180}, math.Pi * earthRadiusMiles}, // Half way round
+	}
+	for _, tt := range tests {
+		if got := distanceMiles(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+			t.Errorf("distanceMiles(%v, %v) = %.1f, want %.0f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
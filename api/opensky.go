@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+const (
+	openSkyBaseURL = "https://opensky-network.org/api"
+)
+
+// OpenSkyClient fetches departures and arrivals from the free OpenSky Network API
+// OpenSky only knows about flights it has observed, so its data is limited to
+// callsigns, observed departure times and estimated destinations
+type OpenSkyClient struct {
+	BaseURL    string
+	Client     *http.Client
+	MaxFlights int         // Number of flights shown, the soonest observed
+	Window     FetchWindow // How far ahead flights are fetched
+}
+
+// NewOpenSkyClient creates a new OpenSky API client
+func NewOpenSkyClient() *OpenSkyClient {
+	return &OpenSkyClient{
+		BaseURL: openSkyBaseURL,
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		MaxFlights: defaultTargetFlights,
+	}
+}
+
+// OpenSkyFlight represents a flight from the OpenSky flights API
+type OpenSkyFlight struct {
+	ICAO24              string `json:"icao24"`
+	FirstSeen           int64  `json:"firstSeen"`
+	EstDepartureAirport string `json:"estDepartureAirport"`
+	LastSeen            int64  `json:"lastSeen"`
+	EstArrivalAirport   string `json:"estArrivalAirport"`
+	Callsign            string `json:"callsign"`
+}
+
+// Name returns the display name of the data source
+func (c *OpenSkyClient) Name() string {
+	return "OpenSky"
+}
+
+// GetDepartures fetches departures observed from an airport
+// OpenSky reports flights once they have been seen leaving, so the window starts
+// 2 hours before the current time to match the scheduled_departures endpoint.
+// Its endpoints are historical and reject an end in the future, so the
+// lookahead is ignored and the window ends now
+func (c *OpenSkyClient) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	now := c.Window.now()
+	osFlights, err := c.fetchFlights(ctx, "departure", airportCode, now.Add(-2*time.Hour), now)
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	flights := make([]models.Flight, 0, len(osFlights))
+	for _, osf := range osFlights {
+		if osf.FirstSeen == 0 {
+			continue
+		}
+		if flight := convertOpenSkyFlight(osf); opts.keep(flight.ScheduledTime(), now) {
+			flights = append(flights, flight)
+		}
+	}
+
+	flights, total := capFlights(flights, opts.limit(c.MaxFlights))
+	return FetchResult{Flights: flights, Pages: 1, Total: total}, nil
+}
+
+// GetArrivals fetches arrivals observed at an airport
+// OpenSky only reports flights after they have landed, so every arrival is
+// shown as arrived and the lookahead is ignored
+func (c *OpenSkyClient) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	now := c.Window.now()
+	osFlights, err := c.fetchFlights(ctx, "arrival", airportCode, now.Add(-2*time.Hour), now)
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	flights := make([]models.Flight, 0, len(osFlights))
+	for _, osf := range osFlights {
+		if osf.LastSeen == 0 {
+			continue
+		}
+		if flight := convertOpenSkyArrival(osf); opts.keep(flight.ScheduledTime(), now) {
+			flights = append(flights, flight)
+		}
+	}
+
+	flights, total := capFlights(flights, opts.limit(c.MaxFlights))
+	return FetchResult{Flights: flights, Pages: 1, Total: total}, nil
+}
+
+// fetchFlights queries the departure or arrival flights endpoint for an airport
+func (c *OpenSkyClient) fetchFlights(ctx context.Context, endpoint, airportCode string, begin, end time.Time) ([]OpenSkyFlight, error) {
+	reqURL, err := url.Parse(c.BaseURL + "/flights/" + endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	icao, ok := AirportICAO(airportCode)
+	if !ok {
+		return nil, fmt.Errorf("no ICAO code known for airport %s, which OpenSky needs (run with -update-data for the full airport table)", strings.ToUpper(airportCode))
+	}
+	params := url.Values{}
+	params.Add("airport", icao)
+	params.Add("begin", strconv.FormatInt(begin.Unix(), 10))
+	params.Add("end", strconv.FormatInt(end.Unix(), 10))
+	reqURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req, timer := traceRequest(req)
+	defer timer.done(c.Name(), reqURL.Path)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// OpenSky responds with 404 when no flights were observed in the window
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, newStatusError(c.Name(), resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var osFlights []OpenSkyFlight
+	if err := json.Unmarshal(body, &osFlights); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return osFlights, nil
+}
+
+// convertOpenSkyFlight converts an OpenSky flight to our Flight model
+// Gate and remarks are left blank since OpenSky has no such information
+func convertOpenSkyFlight(osf OpenSkyFlight) models.Flight {
+	callsign := strings.TrimSpace(osf.Callsign)
+	operator, number := splitCallsign(callsign)
+	airlineCode := AirlineIATA(operator)
+
+	airlineName := operator
+	if airlineName == "" {
+		airlineName = "UNK" // Unknown
+	}
+
+	flightNumber := callsign
+	if airlineCode != "" {
+		flightNumber = airlineCode + " " + number
+	}
+
+	flight := models.Flight{
+		Status:             models.StatusUnknown,
+		Ident:              callsign,
+		AirlineCode:        airlineCode,
+		AirlineName:        airlineName,
+		FlightNumber:       flightNumber,
+		ScheduledDeparture: time.Unix(osf.FirstSeen, 0).UTC(),
+	}
+
+	if osf.EstArrivalAirport != "" {
+		flight.DestinationCode = AirportIATA(osf.EstArrivalAirport)
+	}
+
+	return flight
+}
+
+// convertOpenSkyArrival converts an OpenSky arrival to our Flight model, using
+// the last time the aircraft was seen as its arrival time
+func convertOpenSkyArrival(osf OpenSkyFlight) models.Flight {
+	flight := convertOpenSkyFlight(osf)
+	flight.Direction = models.Arrival
+	flight.Status = models.StatusArrived
+	flight.ScheduledArrival = time.Unix(osf.LastSeen, 0).UTC()
+	flight.DestinationCode = ""
+	if osf.EstDepartureAirport != "" {
+		flight.OriginCode = AirportIATA(osf.EstDepartureAirport)
+	}
+	return flight
+}
+
+// splitCallsign splits an ICAO callsign like "DAL1234" into its operator code and
+// flight number, returning an empty operator for registrations like "N123AB"
+func splitCallsign(callsign string) (string, string) {
+	if len(callsign) < 4 {
+		return "", callsign
+	}
+	for i := 0; i < 3; i++ {
+		if callsign[i] < 'A' || callsign[i] > 'Z' {
+			return "", callsign
+		}
+	}
+	if callsign[3] < '0' || callsign[3] > '9' {
+		return "", callsign
+	}
+	return callsign[:3], callsign[3:]
+}
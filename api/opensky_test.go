@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// openSkyNow is the clock the OpenSky fixtures were recorded against
+var openSkyNow = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// openSkyServer serves the fixture file for each endpoint, recording the
+// queries it was sent
+func openSkyServer(t *testing.T, status int) (*OpenSkyClient, *[]url.Values) {
+	t.Helper()
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", "opensky", filepath.Base(r.URL.Path)+"s.json"))
+		if err != nil {
+			t.Errorf("no fixture for %s: %v", r.URL.Path, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewOpenSkyClient()
+	client.BaseURL = server.URL
+	client.Window.Now = func() time.Time { return openSkyNow }
+	return client, &queries
+}
+
+func TestOpenSkyDepartures(t *testing.T) {
+	client, queries := openSkyServer(t, http.StatusOK)
+	result, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{Window: 6 * time.Hour, IncludePast: true})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+
+	query := (*queries)[0]
+	if got := query.Get("airport"); got != "KJFK" {
+		t.Errorf("airport = %q, want KJFK", got)
+	}
+	if got, want := query.Get("begin"), strconv.FormatInt(openSkyNow.Add(-2*time.Hour).Unix(), 10); got != want {
+		t.Errorf("begin = %s, want %s", got, want)
+	}
+	// The historical endpoint rejects an end in the future, whatever the lookahead
+	if got, want := query.Get("end"), strconv.FormatInt(openSkyNow.Unix(), 10); got != want {
+		t.Errorf("end = %s, want now (%s)", got, want)
+	}
+
+	want := []struct {
+		number, airline, destination string
+		departs                      time.Time
+	}{
+		{"DL 1234", "DL", "ATL", time.Unix(1767265200, 0).UTC()},
+		{"BA 178", "BA", "LHR", time.Unix(1767266100, 0).UTC()},
+		{"N123AB", "", "", time.Unix(1767266400, 0).UTC()},
+	}
+	if len(result.Flights) != len(want) {
+		t.Fatalf("got %d flights, want %d (those never seen leaving are dropped)", len(result.Flights), len(want))
+	}
+	for i, w := range want {
+		flight := result.Flights[i]
+		if flight.FlightNumber != w.number || flight.AirlineCode != w.airline || flight.DestinationCode != w.destination {
+			t.Errorf("flight %d = %q %q to %q, want %q %q to %q", i, flight.FlightNumber, flight.AirlineCode, flight.DestinationCode, w.number, w.airline, w.destination)
+		}
+		if !flight.ScheduledDeparture.Equal(w.departs) {
+			t.Errorf("flight %d departs %v, want %v", i, flight.ScheduledDeparture, w.departs)
+		}
+		if flight.Status != models.StatusUnknown {
+			t.Errorf("flight %d status = %v, want unknown", i, flight.Status)
+		}
+		// OpenSky has no gates or remarks, so none are made up
+		if flight.Gate != "" || flight.Remarks != "" {
+			t.Errorf("flight %d has gate %q and remarks %q, want both blank", i, flight.Gate, flight.Remarks)
+		}
+	}
+}
+
+func TestOpenSkyArrivals(t *testing.T) {
+	client, _ := openSkyServer(t, http.StatusOK)
+	result, err := client.GetArrivals(context.Background(), "JFK", FetchOptions{IncludePast: true})
+	if err != nil {
+		t.Fatalf("GetArrivals: %v", err)
+	}
+	if len(result.Flights) != 1 {
+		t.Fatalf("got %d arrivals, want 1 (those never seen landing are dropped)", len(result.Flights))
+	}
+	flight := result.Flights[0]
+	if flight.FlightNumber != "UA 523" || flight.OriginCode != "ORD" || flight.DestinationCode != "" {
+		t.Errorf("arrival = %q from %q to %q, want UA 523 from ORD", flight.FlightNumber, flight.OriginCode, flight.DestinationCode)
+	}
+	if flight.Direction != models.Arrival || flight.Status != models.StatusArrived {
+		t.Errorf("arrival has direction %v and status %v, want an arrived arrival", flight.Direction, flight.Status)
+	}
+	if want := time.Unix(1767265800, 0).UTC(); !flight.ScheduledArrival.Equal(want) {
+		t.Errorf("arrival lands %v, want %v", flight.ScheduledArrival, want)
+	}
+}
+
+func TestOpenSkyNothingObserved(t *testing.T) {
+	// OpenSky answers 404 when it saw no flights in the window
+	client, _ := openSkyServer(t, http.StatusNotFound)
+	result, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{IncludePast: true})
+	if err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if len(result.Flights) != 0 {
+		t.Errorf("got %d flights, want none", len(result.Flights))
+	}
+}
+
+func TestOpenSkyServerError(t *testing.T) {
+	client, _ := openSkyServer(t, http.StatusServiceUnavailable)
+	_, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{IncludePast: true})
+	if !IsTransient(err) {
+		t.Errorf("err = %v, want a transient error", err)
+	}
+}
+
+func TestOpenSkyUnknownAirport(t *testing.T) {
+	client, queries := openSkyServer(t, http.StatusOK)
+	// Not in the table and not in the US, so a "K" prefix would be wrong
+	if _, err := client.GetDepartures(context.Background(), "ZZQ", FetchOptions{}); err == nil {
+		t.Error("GetDepartures succeeded for an airport without a known ICAO code")
+	}
+	if len(*queries) != 0 {
+		t.Errorf("made %d requests, want none", len(*queries))
+	}
+}
+
+func TestAirportICAO(t *testing.T) {
+	tests := []struct {
+		iata string
+		icao string
+		ok   bool
+	}{
+		{"JFK", "KJFK", true},
+		{"lhr", "EGLL", true},
+		{"ANC", "PANC", true},
+		{"ZZQ", "", false},
+	}
+	for _, tt := range tests {
+		icao, ok := AirportICAO(tt.iata)
+		if icao != tt.icao || ok != tt.ok {
+			t.Errorf("AirportICAO(%q) = %q, %v, want %q, %v", tt.iata, icao, ok, tt.icao, tt.ok)
+		}
+	}
+}
+
+func TestSplitCallsign(t *testing.T) {
+	tests := []struct {
+		callsign, operator, number string
+	}{
+		{"DAL1234", "DAL", "1234"},
+		{"BAW178", "BAW", "178"},
+		{"N123AB", "", "N123AB"},
+		{"AB1", "", "AB1"},
+		{"EZY12AB", "EZY", "12AB"},
+	}
+	for _, tt := range tests {
+		operator, number := splitCallsign(tt.callsign)
+		if operator != tt.operator || number != tt.number {
+			t.Errorf("splitCallsign(%q) = %q, %q, want %q, %q", tt.callsign, operator, number, tt.operator, tt.number)
+		}
+	}
+}
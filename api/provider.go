@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+// FlightDataProvider is a source of flight data for the board
+type FlightDataProvider interface {
+	// Name returns a short display name for the data source
+	Name() string
+	// GetDepartures fetches the departures of an airport selected by opts
+	GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error)
+	// GetArrivals fetches the arrivals of an airport selected by opts
+	GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error)
+}
+
+// FetchOptions selects the flights a provider fetches. The zero value asks
+// for the upcoming flights without an end to the window, as many as the
+// client keeps by default, from a single result page
+type FetchOptions struct {
+	// Window is how far ahead of now flights are fetched, zero for no end.
+	// Clients fetching for an operational day ignore it
+	Window time.Duration
+	// Limit keeps at most this many flights, soonest first; zero keeps the
+	// client's default number
+	Limit int
+	// MaxPages is an upper bound for paged sources, which fetch fewer pages
+	// when the first ones already hold enough flights; zero fetches one
+	MaxPages int
+	// IncludePast keeps flights whose scheduled time has passed, such as
+	// delayed departures still at the gate. Sources that only report
+	// flights once they have been seen, like OpenSky, return nothing
+	// without it
+	IncludePast bool
+}
+
+// maxPages returns the number of result pages that may be fetched, at least one
+func (o FetchOptions) maxPages() int {
+	return max(1, o.MaxPages)
+}
+
+// limit returns the number of flights to keep: Limit, or else defaultLimit
+func (o FetchOptions) limit(defaultLimit int) int {
+	if o.Limit > 0 {
+		return o.Limit
+	}
+	return defaultLimit
+}
+
+// keep reports whether a flight scheduled at scheduled is kept at now
+func (o FetchOptions) keep(scheduled, now time.Time) bool {
+	return o.IncludePast || !scheduled.Before(now)
+}
+
+// RouteProvider is implemented by providers that can fetch the departures
+// of a single route themselves, so paging stops once enough flights on the
+// route were found rather than enough departures of any destination
+type RouteProvider interface {
+	// GetRouteDepartures fetches the departures from origin to destination
+	// selected by opts
+	GetRouteDepartures(ctx context.Context, origin, destination string, opts FetchOptions) (FetchResult, error)
+}
+
+// FlightLookup is implemented by providers that can fetch a single flight
+// by the ID a board's flights carry, such as the inbound flight of a departure
+type FlightLookup interface {
+	// GetFlight fetches the flight with the source's ID id
+	GetFlight(ctx context.Context, id string) (models.Flight, error)
+}
+
+// ErrNoFlightLookup is returned by LookupFlight for providers that can't
+// fetch single flights
+var ErrNoFlightLookup = errors.New("the data source can't look up single flights")
+
+// LookupFlight fetches the flight with the ID id from provider, or returns
+// ErrNoFlightLookup if provider isn't a FlightLookup
+func LookupFlight(ctx context.Context, provider FlightDataProvider, id string) (models.Flight, error) {
+	if lookup, ok := provider.(FlightLookup); ok {
+		return lookup.GetFlight(ctx, id)
+	}
+	return models.Flight{}, ErrNoFlightLookup
+}
+
+// IntervalSuggester is implemented by providers whose data changes on a
+// cadence of their own, such as simulated sources, rather than one that should
+// follow the configured update interval
+type IntervalSuggester interface {
+	// SuggestedInterval returns the delay between fetches the provider wants,
+	// or zero to use the configured update interval
+	SuggestedInterval() time.Duration
+}
+
+// SuggestedInterval returns the fetch interval provider asks for, or zero if
+// it leaves the interval to the configuration
+func SuggestedInterval(provider FlightDataProvider) time.Duration {
+	if s, ok := provider.(IntervalSuggester); ok {
+		return s.SuggestedInterval()
+	}
+	return 0
+}
+
+// FetchResult holds the flights returned by a provider and how they were fetched
+type FetchResult struct {
+	Flights   []models.Flight
+	Pages     int    // Result pages requested from the source
+	Total     int    // Qualifying flights found, more than len(Flights) when the list was capped
+	Source    string // Name of the source that served the flights
+	Simulated bool   // Generated or replayed data rather than live data
+	// RecordedAt is when replayed flights were recorded, zero for live data
+	RecordedAt time.Time
+	// WindowLimit is the shorter window flights were fetched for because the
+	// source doesn't allow the one asked for, zero if it wasn't shortened
+	WindowLimit time.Duration
+	// Skipped reports the flights a strict client left out for missing
+	// fields; it is empty for other clients
+	Skipped SkipReport
+}
+
+// capFlights keeps the max soonest flights, so a source's ordering can't drop
+// flights from inside the board's time window. It returns the kept flights and
+// the number found; max of zero or less keeps them all
+func capFlights(flights []models.Flight, max int) ([]models.Flight, int) {
+	total := len(flights)
+	sort.SliceStable(flights, func(i, j int) bool {
+		return flights[i].ScheduledTime().Before(flights[j].ScheduledTime())
+	})
+	if max > 0 && total > max {
+		slog.Info("flight list capped", "kept", max, "found", total)
+		flights = flights[:max]
+	}
+	return flights, total
+}
+
+// GetFlights fetches departures or arrivals from provider depending on direction
+// Results that don't name their source are attributed to provider
+func GetFlights(ctx context.Context, provider FlightDataProvider, direction models.Direction, airportCode string, opts FetchOptions) (FetchResult, error) {
+	var result FetchResult
+	var err error
+	if direction == models.Arrival {
+		result, err = provider.GetArrivals(ctx, airportCode, opts)
+	} else {
+		result, err = provider.GetDepartures(ctx, airportCode, opts)
+	}
+	if err == nil && result.Source == "" {
+		result.Source = provider.Name()
+	}
+	return result, err
+}
+
+// GetRoute fetches the departures from origin to destination. Providers that
+// aren't RouteProviders have their departures filtered by destination.
+// Results that don't name their source are attributed to provider
+func GetRoute(ctx context.Context, provider FlightDataProvider, origin, destination string, opts FetchOptions) (FetchResult, error) {
+	var result FetchResult
+	var err error
+	if rp, ok := provider.(RouteProvider); ok {
+		result, err = rp.GetRouteDepartures(ctx, origin, destination, opts)
+	} else {
+		result, err = provider.GetDepartures(ctx, origin, opts)
+		if err == nil {
+			result.Flights = onRoute(result.Flights, destination)
+			result.Total = len(result.Flights)
+		}
+	}
+	if err == nil && result.Source == "" {
+		result.Source = provider.Name()
+	}
+	return result, err
+}
+
+// onRoute returns the flights bound for destination
+func onRoute(flights []models.Flight, destination string) []models.Flight {
+	kept := make([]models.Flight, 0, len(flights))
+	for _, flight := range flights {
+		if strings.TrimSpace(flight.DestinationCode) == destination {
+			kept = append(kept, flight)
+		}
+	}
+	return kept
+}
+
+// FallbackProvider serves data from a primary provider and switches to a
+// secondary provider while the primary's circuit breaker is open
+type FallbackProvider struct {
+	Primary   FlightDataProvider
+	Secondary FlightDataProvider
+	Breaker   *CircuitBreaker
+}
+
+// NewFallbackProvider creates a provider that falls back to secondary when primary keeps failing
+func NewFallbackProvider(primary, secondary FlightDataProvider) *FallbackProvider {
+	return &FallbackProvider{
+		Primary:   primary,
+		Secondary: secondary,
+		Breaker:   NewCircuitBreaker(defaultBreakerThreshold, defaultBreakerCooldown),
+	}
+}
+
+// Name returns the name of the provider that would serve the next request
+func (p *FallbackProvider) Name() string {
+	if p.Breaker.IsOpen() {
+		return p.Secondary.Name()
+	}
+	return p.Primary.Name()
+}
+
+// SuggestedInterval returns the fetch interval of the provider currently serving data
+func (p *FallbackProvider) SuggestedInterval() time.Duration {
+	if p.Breaker.IsOpen() {
+		return SuggestedInterval(p.Secondary)
+	}
+	return SuggestedInterval(p.Primary)
+}
+
+// GetDepartures fetches departures from the primary provider, falling back to the
+// secondary provider when the primary fails and its circuit breaker is open
+func (p *FallbackProvider) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.fetch(func(provider FlightDataProvider) (FetchResult, error) {
+		return GetFlights(ctx, provider, models.Departure, airportCode, opts)
+	})
+}
+
+// GetArrivals fetches arrivals with the same fallback behavior as GetDepartures
+func (p *FallbackProvider) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.fetch(func(provider FlightDataProvider) (FetchResult, error) {
+		return GetFlights(ctx, provider, models.Arrival, airportCode, opts)
+	})
+}
+
+// GetRouteDepartures fetches the departures of a route with the same fallback
+// behavior as GetDepartures
+func (p *FallbackProvider) GetRouteDepartures(ctx context.Context, origin, destination string, opts FetchOptions) (FetchResult, error) {
+	return p.fetch(func(provider FlightDataProvider) (FetchResult, error) {
+		return GetRoute(ctx, provider, origin, destination, opts)
+	})
+}
+
+// GetFlight looks up a flight with the provider currently serving data.
+// Lookups don't count towards the circuit breaker, which follows the
+// board's own fetches
+func (p *FallbackProvider) GetFlight(ctx context.Context, id string) (models.Flight, error) {
+	if p.Breaker.IsOpen() {
+		return LookupFlight(ctx, p.Secondary, id)
+	}
+	return LookupFlight(ctx, p.Primary, id)
+}
+
+// fetch fetches flights from the primary provider with get, falling back to
+// the secondary provider once the primary's circuit breaker is open
+func (p *FallbackProvider) fetch(get func(FlightDataProvider) (FetchResult, error)) (FetchResult, error) {
+	if p.Breaker.Allow() {
+		result, err := get(p.Primary)
+		if err == nil {
+			p.Breaker.RecordSuccess()
+			return result, nil
+		}
+		p.Breaker.RecordFailure()
+		if !p.Breaker.IsOpen() {
+			return FetchResult{}, err
+		}
+	}
+
+	result, err := get(p.Secondary)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("%s unavailable, %s fallback failed: %w", p.Primary.Name(), p.Secondary.Name(), err)
+	}
+	return result, nil
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces out requests so no more than a number are made in any
+// minute, however many fetches run at once, such as those of the airports
+// of a comparison board. A nil RateLimiter never waits. It is safe for
+// concurrent use
+type RateLimiter struct {
+	interval time.Duration // Time between requests
+	Now      func() time.Time
+
+	mu   sync.Mutex
+	next time.Time // When the next request may be made
+}
+
+// NewRateLimiter creates a limiter allowing perMinute requests a minute, or
+// nil to allow every request when perMinute isn't positive
+func NewRateLimiter(perMinute int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{interval: time.Minute / time.Duration(perMinute), Now: time.Now}
+}
+
+// reserve takes the next slot for a request and returns how long to wait
+// for it. The first request after an idle spell is made at once
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	return at.Sub(now)
+}
+
+// Wait blocks until a request may be made, or ctx is done. A request
+// given up on still takes its slot, so those after it aren't rushed
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
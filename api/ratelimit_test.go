@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(6) // One request every 10s
+	limiter.Now = func() time.Time { return now }
+
+	steps := []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{0, 0}, // The first request is made at once
+		{0, 10 * time.Second},
+		{0, 20 * time.Second}, // Requests made together queue up
+		{25 * time.Second, 5 * time.Second},
+		{time.Minute, 0}, // An idle spell doesn't save up requests
+		{0, 10 * time.Second},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if got := limiter.reserve(); got != step.want {
+			t.Errorf("request %d waits %s, want %s", i+1, got, step.want)
+		}
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	var none *RateLimiter
+	if err := none.Wait(context.Background()); err != nil {
+		t.Errorf("nil limiter: %v", err)
+	}
+	if NewRateLimiter(0) != nil {
+		t.Error("limiter of 0 a minute limits requests")
+	}
+
+	limiter := NewRateLimiter(1200) // One request every 50ms
+	start := time.Now()
+	for range 3 {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 requests made in %s, want at least 100ms", elapsed)
+	}
+
+	// A fetch given up on stops waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter.Wait(context.Background())
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait with a cancelled context = %v, want context.Canceled", err)
+	}
+}
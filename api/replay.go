@@ -0,0 +1,264 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"fids-tui/models"
+)
+
+// Snapshot is one fetch of a recorded session: the flights a source sent for
+// a board and when it sent them
+type Snapshot struct {
+	CapturedAt  time.Time        `json:"captured_at"`
+	Airport     string           `json:"airport"`
+	Destination string           `json:"destination,omitempty"` // Set for a route board
+	Direction   models.Direction `json:"direction"`
+	Source      string           `json:"source"`
+	Flights     []models.Flight  `json:"flights"`
+}
+
+// snapshotKey identifies the board a snapshot was recorded for
+type snapshotKey struct {
+	airport     string
+	destination string
+	direction   models.Direction
+}
+
+// fileName returns the name a snapshot is saved under, which sorts by
+// capture time, e.g. "20260101T120000.000Z-JFK-departures.json"
+func (s Snapshot) fileName() string {
+	board := s.Airport
+	if s.Destination != "" {
+		board += "-" + s.Destination
+	}
+	return fmt.Sprintf("%s-%s-%s.json", s.CapturedAt.UTC().Format("20060102T150405.000Z"), board, strings.ToLower(s.Direction.String()))
+}
+
+// Recorder passes fetches through to a provider and saves the flights of
+// each one that succeeds as a Snapshot in Dir, for ReplayProvider to show
+// again later. A snapshot that can't be saved is logged and the fetch still
+// returns its flights
+type Recorder struct {
+	Provider FlightDataProvider
+	Dir      string
+	Now      func() time.Time // Clock stamping the snapshots; time.Now if nil
+}
+
+// NewRecorder creates a recorder of provider's fetches, creating dir if it
+// doesn't exist
+func NewRecorder(provider FlightDataProvider, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Recorder{Provider: provider, Dir: dir}, nil
+}
+
+// Name returns the name of the recorded provider
+func (r *Recorder) Name() string {
+	return r.Provider.Name()
+}
+
+// SuggestedInterval returns the fetch interval of the recorded provider
+func (r *Recorder) SuggestedInterval() time.Duration {
+	return SuggestedInterval(r.Provider)
+}
+
+// GetDepartures fetches and records the departures of an airport
+func (r *Recorder) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	result, err := GetFlights(ctx, r.Provider, models.Departure, airportCode, opts)
+	return r.record(snapshotKey{airport: airportCode, direction: models.Departure}, result, err)
+}
+
+// GetArrivals fetches and records the arrivals of an airport
+func (r *Recorder) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	result, err := GetFlights(ctx, r.Provider, models.Arrival, airportCode, opts)
+	return r.record(snapshotKey{airport: airportCode, direction: models.Arrival}, result, err)
+}
+
+// GetFlight looks up a single flight through the recorded provider, without
+// recording it
+func (r *Recorder) GetFlight(ctx context.Context, id string) (models.Flight, error) {
+	return LookupFlight(ctx, r.Provider, id)
+}
+
+// Close stops the recorded provider's background work, if it has any
+func (r *Recorder) Close() {
+	if closer, ok := r.Provider.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// GetRouteDepartures fetches and records the departures of a route
+func (r *Recorder) GetRouteDepartures(ctx context.Context, origin, destination string, opts FetchOptions) (FetchResult, error) {
+	result, err := GetRoute(ctx, r.Provider, origin, destination, opts)
+	return r.record(snapshotKey{airport: origin, destination: destination, direction: models.Departure}, result, err)
+}
+
+// record saves the result of a successful fetch for key and returns it
+func (r *Recorder) record(key snapshotKey, result FetchResult, err error) (FetchResult, error) {
+	if err != nil {
+		return result, err
+	}
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+	snapshot := Snapshot{
+		CapturedAt:  now(),
+		Airport:     key.airport,
+		Destination: key.destination,
+		Direction:   key.direction,
+		Source:      result.Source,
+		Flights:     result.Flights,
+	}
+	path := filepath.Join(r.Dir, snapshot.fileName())
+	data, marshalErr := json.MarshalIndent(snapshot, "", "  ")
+	if marshalErr == nil {
+		marshalErr = os.WriteFile(path, data, 0o644)
+	}
+	if marshalErr != nil {
+		slog.Warn("failed to record snapshot", "path", path, "error", marshalErr)
+	}
+	return result, nil
+}
+
+// ReplayProvider shows the snapshots of a recorded session again. Each board
+// keeps its own place in its snapshots: every fetch moves on to the next one,
+// so the session replays at the pace it was recorded at, and the last one
+// stays up once the recording ends. Step moves through the snapshots by hand
+// instead, pausing that pacing until Resume. Results are marked Simulated
+// and carry the time their snapshot was captured
+type ReplayProvider struct {
+	mu        sync.Mutex
+	snapshots map[snapshotKey][]Snapshot // Each board's snapshots, oldest first
+	cursors   map[snapshotKey]int        // Snapshot each board shows, -1 before its first fetch
+	paused    bool
+}
+
+// NewReplayProvider loads the snapshots recorded in dir
+func NewReplayProvider(dir string) (*ReplayProvider, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	p := &ReplayProvider{
+		snapshots: make(map[snapshotKey][]Snapshot),
+		cursors:   make(map[snapshotKey]int),
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		key := snapshotKey{airport: snapshot.Airport, destination: snapshot.Destination, direction: snapshot.Direction}
+		p.snapshots[key] = append(p.snapshots[key], snapshot)
+		p.cursors[key] = -1
+	}
+	if len(p.snapshots) == 0 {
+		return nil, fmt.Errorf("no recorded snapshots in %s", dir)
+	}
+	for _, snapshots := range p.snapshots {
+		slices.SortStableFunc(snapshots, func(a, b Snapshot) int {
+			return a.CapturedAt.Compare(b.CapturedAt)
+		})
+	}
+	return p, nil
+}
+
+// Name returns the display name of the data source
+func (p *ReplayProvider) Name() string {
+	return "Replay"
+}
+
+// GetDepartures returns the next recorded departures of an airport
+func (p *ReplayProvider) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.next(snapshotKey{airport: airportCode, direction: models.Departure})
+}
+
+// GetArrivals returns the next recorded arrivals of an airport
+func (p *ReplayProvider) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return p.next(snapshotKey{airport: airportCode, direction: models.Arrival})
+}
+
+// GetRouteDepartures returns the next recorded departures of a route
+func (p *ReplayProvider) GetRouteDepartures(ctx context.Context, origin, destination string, opts FetchOptions) (FetchResult, error) {
+	return p.next(snapshotKey{airport: origin, destination: destination, direction: models.Departure})
+}
+
+// next moves the board of key on to its next snapshot, unless paused, and
+// returns the snapshot it shows
+func (p *ReplayProvider) next(key snapshotKey) (FetchResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	snapshots := p.snapshots[key]
+	if len(snapshots) == 0 {
+		return FetchResult{}, fmt.Errorf("%w: %s was not recorded", ErrNoData, key.describe())
+	}
+	cursor := max(p.cursors[key], 0)
+	if !p.paused && p.cursors[key] >= 0 {
+		cursor = min(cursor+1, len(snapshots)-1)
+	}
+	p.cursors[key] = cursor
+	snapshot := snapshots[cursor]
+	flights := append([]models.Flight(nil), snapshot.Flights...)
+	return FetchResult{
+		Flights:    flights,
+		Pages:      1,
+		Total:      len(flights),
+		Source:     p.Name(),
+		Simulated:  true,
+		RecordedAt: snapshot.CapturedAt,
+	}, nil
+}
+
+// Step moves the board of an airport delta snapshots back or forward, within
+// the recording, and pauses the replay's pacing so fetches show that snapshot
+// until Resume. It reports whether the board was recorded
+func (p *ReplayProvider) Step(airportCode, destination string, direction models.Direction, delta int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := snapshotKey{airport: airportCode, destination: destination, direction: direction}
+	snapshots := p.snapshots[key]
+	if len(snapshots) == 0 {
+		return false
+	}
+	p.paused = true
+	p.cursors[key] = min(max(p.cursors[key]+delta, 0), len(snapshots)-1)
+	return true
+}
+
+// Resume goes back to moving on a snapshot with each fetch
+func (p *ReplayProvider) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+}
+
+// Paused reports whether Step has paused the replay's pacing
+func (p *ReplayProvider) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// describe names the board of key in errors, e.g. "JFK departures"
+func (k snapshotKey) describe() string {
+	board := k.airport
+	if k.destination != "" {
+		board += "-" + k.destination
+	}
+	return board + " " + strings.ToLower(k.direction.String())
+}
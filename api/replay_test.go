@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// recordSession records JFK departures from the demo provider at each of
+// times, and a single fetch of the JFK-ORD route, in a new directory
+func recordSession(t *testing.T, times []time.Time) (string, [][]models.Flight) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "session")
+	now := times[0]
+	demo := NewDemoProvider()
+	demo.Window.Now = func() time.Time { return now }
+	recorder, err := NewRecorder(demo, dir)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	recorder.Now = func() time.Time { return now }
+
+	var fetched [][]models.Flight
+	for _, at := range times {
+		now = at
+		result, err := recorder.GetDepartures(context.Background(), "JFK", FetchOptions{Limit: 5})
+		if err != nil {
+			t.Fatalf("GetDepartures: %v", err)
+		}
+		fetched = append(fetched, result.Flights)
+	}
+	if _, err := GetRoute(context.Background(), recorder, "JFK", "ORD", FetchOptions{}); err != nil {
+		t.Fatalf("GetRoute: %v", err)
+	}
+	return dir, fetched
+}
+
+// sameFlights reports whether two lists hold the same flights at the same
+// gates and times
+func sameFlights(a, b []models.Flight) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID || a[i].Gate != b[i].Gate || !a[i].ScheduledTime().Equal(b[i].ScheduledTime()) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	times := []time.Time{demoNow, demoNow.Add(10 * time.Minute), demoNow.Add(20 * time.Minute)}
+	dir, fetched := recordSession(t, times)
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 4 {
+		t.Fatalf("recorded %d snapshots, want 4: %v", len(files), files)
+	}
+	if want := "20260302T150400.000Z-JFK-departures.json"; filepath.Base(files[0]) != want {
+		t.Errorf("first snapshot is named %s, want %s", filepath.Base(files[0]), want)
+	}
+
+	replay, err := NewReplayProvider(dir)
+	if err != nil {
+		t.Fatalf("NewReplayProvider: %v", err)
+	}
+	// fetch returns the snapshot the next JFK departures fetch shows
+	fetch := func() int {
+		t.Helper()
+		result, err := GetFlights(context.Background(), replay, models.Departure, "JFK", FetchOptions{})
+		if err != nil {
+			t.Fatalf("GetDepartures: %v", err)
+		}
+		if result.Source != "Replay" || !result.Simulated {
+			t.Errorf("result source %q, simulated %v, want Replay and simulated", result.Source, result.Simulated)
+		}
+		for i, at := range times {
+			if result.RecordedAt.Equal(at) {
+				if !sameFlights(result.Flights, fetched[i]) {
+					t.Errorf("snapshot %d flights differ from those recorded", i)
+				}
+				return i
+			}
+		}
+		t.Fatalf("result recorded at %s, not a recorded time", result.RecordedAt)
+		return -1
+	}
+
+	// Each fetch moves on a snapshot, and the last one stays up
+	for _, want := range []int{0, 1, 2, 2} {
+		if got := fetch(); got != want {
+			t.Fatalf("fetch showed snapshot %d, want %d", got, want)
+		}
+	}
+
+	// Stepping pauses the pacing on the snapshot stepped to, within the
+	// recording
+	if !replay.Step("JFK", "", models.Departure, -1) || !replay.Paused() {
+		t.Fatal("Step back didn't pause the replay")
+	}
+	for range 2 {
+		if got := fetch(); got != 1 {
+			t.Errorf("paused fetch showed snapshot %d, want 1", got)
+		}
+	}
+	replay.Step("JFK", "", models.Departure, -5)
+	if got := fetch(); got != 0 {
+		t.Errorf("fetch after stepping before the start showed snapshot %d, want 0", got)
+	}
+	replay.Step("JFK", "", models.Departure, 1)
+	if got := fetch(); got != 1 {
+		t.Errorf("fetch after stepping forward showed snapshot %d, want 1", got)
+	}
+
+	// Resuming moves on again with each fetch
+	replay.Resume()
+	if got := fetch(); got != 2 {
+		t.Errorf("fetch after Resume showed snapshot %d, want 2", got)
+	}
+
+	// Routes are replayed apart from the airport's board
+	route, err := GetRoute(context.Background(), replay, "JFK", "ORD", FetchOptions{})
+	if err != nil || !route.RecordedAt.Equal(times[2]) {
+		t.Errorf("GetRoute = recorded at %s, %v; want the route snapshot", route.RecordedAt, err)
+	}
+	for _, flight := range route.Flights {
+		if flight.DestinationCode != "ORD" {
+			t.Errorf("route flight %s goes to %s, want ORD", flight.FlightNumber, flight.DestinationCode)
+		}
+	}
+
+	// Boards that weren't recorded have no data
+	if _, err := replay.GetArrivals(context.Background(), "JFK", FetchOptions{}); !errors.Is(err, ErrNoData) {
+		t.Errorf("unrecorded arrivals error = %v, want ErrNoData", err)
+	}
+	if replay.Step("LAX", "", models.Departure, 1) {
+		t.Error("Step of an unrecorded board reported it recorded")
+	}
+}
+
+func TestRecorderFailedFetch(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(failingProvider{}, dir)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if _, err := recorder.GetDepartures(context.Background(), "JFK", FetchOptions{}); err == nil {
+		t.Fatal("GetDepartures of a failing provider succeeded")
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(files) != 0 {
+		t.Errorf("failed fetch recorded %v", files)
+	}
+}
+
+func TestReplayProviderErrors(t *testing.T) {
+	if _, err := NewReplayProvider(t.TempDir()); err == nil {
+		t.Error("NewReplayProvider of an empty directory succeeded")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReplayProvider(dir); err == nil {
+		t.Error("NewReplayProvider of a broken snapshot succeeded")
+	}
+}
+
+// failingProvider fails every fetch
+type failingProvider struct{}
+
+func (failingProvider) Name() string { return "Failing" }
+
+func (failingProvider) GetDepartures(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return FetchResult{}, errors.New("unavailable")
+}
+
+func (failingProvider) GetArrivals(ctx context.Context, airportCode string, opts FetchOptions) (FetchResult, error) {
+	return FetchResult{}, errors.New("unavailable")
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetrySchedule paces attempts at something that hasn't yet succeeded, such
+// as a board's first fetch: each failure waits the next of its delays, and
+// once they run out the caller's normal interval applies. Callers drop the
+// schedule once an attempt succeeds
+type RetrySchedule struct {
+	Delays   []time.Duration
+	attempts int // Attempts failed so far
+}
+
+// NewRetrySchedule creates a schedule retrying after each of delays in turn
+func NewRetrySchedule(delays []time.Duration) *RetrySchedule {
+	return &RetrySchedule{Delays: delays}
+}
+
+// ParseRetryDelays parses a comma-separated list of delays such as
+// "10s,30s,60s". An empty list, "0" or "none" retries at the normal
+// interval only
+func ParseRetryDelays(value string) ([]time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || strings.EqualFold(value, "none") {
+		return nil, nil
+	}
+	var delays []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid delay %q (expected a duration like 30s)", strings.TrimSpace(part))
+		}
+		delays = append(delays, d)
+	}
+	return delays, nil
+}
+
+// Failed counts a failed attempt and returns how long to wait before the
+// next, or false once the delays have run out and the normal interval
+// applies
+func (rs *RetrySchedule) Failed() (time.Duration, bool) {
+	rs.attempts++
+	if rs.attempts > len(rs.Delays) {
+		return 0, false
+	}
+	return rs.Delays[rs.attempts-1], true
+}
+
+// Retrying reports whether an attempt has failed
+func (rs *RetrySchedule) Retrying() bool {
+	return rs.attempts > 0
+}
+
+// Attempts returns the number of attempts failed so far
+func (rs *RetrySchedule) Attempts() int {
+	return rs.attempts
+}
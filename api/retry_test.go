@@ -0,0 +1,69 @@
+package api
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetrySchedule(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		delays []time.Duration
+	}{
+		{"startup default", []time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second}},
+		{"single delay", []time.Duration{5 * time.Second}},
+		{"no delays", nil},
+	} {
+		rs := NewRetrySchedule(tt.delays)
+		if rs.Retrying() || rs.Attempts() != 0 {
+			t.Errorf("%s: new schedule retrying after %d attempts", tt.name, rs.Attempts())
+		}
+		for i, want := range tt.delays {
+			if got, ok := rs.Failed(); !ok || got != want {
+				t.Errorf("%s: failure %d waits %s, %v; want %s", tt.name, i+1, got, ok, want)
+			}
+		}
+		// Exhausted: the normal interval applies from here on
+		for range 2 {
+			if got, ok := rs.Failed(); ok {
+				t.Errorf("%s: failure after the delays ran out waits %s", tt.name, got)
+			}
+		}
+		if !rs.Retrying() || rs.Attempts() != len(tt.delays)+2 {
+			t.Errorf("%s: %d attempts counted, want %d", tt.name, rs.Attempts(), len(tt.delays)+2)
+		}
+	}
+}
+
+func TestParseRetryDelays(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    []time.Duration
+		wantErr string
+	}{
+		{"10s,30s,60s", []time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second}, ""},
+		{" 10s, 1m30s ", []time.Duration{10 * time.Second, 90 * time.Second}, ""},
+		{"", nil, ""},
+		{"0", nil, ""},
+		{"none", nil, ""},
+		{"None", nil, ""},
+		{"abc", nil, `invalid delay "abc"`},
+		{"10s,-5s", nil, `invalid delay "-5s"`},
+		{"10s,0s", nil, `invalid delay "0s"`},
+		{"10s,,30s", nil, `invalid delay ""`},
+		{"10", nil, `invalid delay "10"`},
+	} {
+		got, err := ParseRetryDelays(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseRetryDelays(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseRetryDelays(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
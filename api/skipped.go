@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSkipSamples is the number of idents a SkipReport keeps as examples
+const maxSkipSamples = 5
+
+// Reasons a strict client leaves a flight out
+const (
+	reasonNoTime         = "no scheduled time"
+	reasonNoAirline      = "no airline"
+	reasonNoFlightNumber = "no flight number"
+	reasonNoDestination  = "no destination"
+	reasonNoOrigin       = "no origin"
+)
+
+// SkipReport describes the flights a strict client left out because the
+// source sent them without fields the board needs, rather than making up
+// placeholders such as "UNK" for them
+type SkipReport struct {
+	Count   int            // Flights left out
+	Reasons map[string]int // Flights left out for each reason; one flight may have several
+	Samples []string       // Idents of the first flights left out
+}
+
+// add records a flight left out for reasons
+func (r *SkipReport) add(ident string, reasons []string) {
+	if r.Reasons == nil {
+		r.Reasons = make(map[string]int)
+	}
+	r.Count++
+	for _, reason := range reasons {
+		r.Reasons[reason]++
+	}
+	if len(r.Samples) < maxSkipSamples {
+		r.Samples = append(r.Samples, ident)
+	}
+}
+
+// Merge adds the flights left out in other to the report, as when the
+// results of several airports are combined
+func (r *SkipReport) Merge(other SkipReport) {
+	if other.Count == 0 {
+		return
+	}
+	if r.Reasons == nil {
+		r.Reasons = make(map[string]int)
+	}
+	r.Count += other.Count
+	for reason, n := range other.Reasons {
+		r.Reasons[reason] += n
+	}
+	for _, ident := range other.Samples {
+		if len(r.Samples) < maxSkipSamples {
+			r.Samples = append(r.Samples, ident)
+		}
+	}
+}
+
+// String summarizes the report, e.g. "3 flights skipped (no scheduled
+// time)" or "4 flights skipped (3 no airline, 1 no destination)", or
+// returns "" if no flight was left out
+func (r SkipReport) String() string {
+	if r.Count == 0 {
+		return ""
+	}
+	reasons := make([]string, 0, len(r.Reasons))
+	for reason := range r.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if r.Reasons[reasons[i]] != r.Reasons[reasons[j]] {
+			return r.Reasons[reasons[i]] > r.Reasons[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+	if len(reasons) > 1 {
+		for i, reason := range reasons {
+			reasons[i] = fmt.Sprintf("%d %s", r.Reasons[reason], reason)
+		}
+	}
+	noun := "flights"
+	if r.Count == 1 {
+		noun = "flight"
+	}
+	return fmt.Sprintf("%d %s skipped (%s)", r.Count, noun, strings.Join(reasons, ", "))
+}
+
+// missingFields returns the reasons a strict client leaves the departure
+// out, none if it has everything the board shows
+func (d AeroAPIDeparture) missingFields() []string {
+	var missing []string
+	if _, ok := d.ScheduledTime(); !ok {
+		missing = append(missing, reasonNoTime)
+	}
+	missing = append(missing, missingIdentity(d.Operator, d.OperatorIata, d.FlightNumber, d.Ident)...)
+	if d.Destination == nil || d.Destination.preferredCode() == "" {
+		missing = append(missing, reasonNoDestination)
+	}
+	return missing
+}
+
+// missingFields returns the reasons a strict client leaves the arrival out,
+// none if it has everything the board shows
+func (a AeroAPIArrival) missingFields() []string {
+	var missing []string
+	if _, ok := a.ScheduledTime(); !ok {
+		missing = append(missing, reasonNoTime)
+	}
+	missing = append(missing, missingIdentity(a.Operator, a.OperatorIata, a.FlightNumber, a.Ident)...)
+	if a.Origin == nil || a.Origin.preferredCode() == "" {
+		missing = append(missing, reasonNoOrigin)
+	}
+	return missing
+}
+
+// missingIdentity returns the reasons a flight can't be named: no airline
+// code, or neither a flight number nor an ident
+func missingIdentity(operator, operatorIata, flightNumber, ident string) []string {
+	var missing []string
+	if strings.TrimSpace(operator) == "" && strings.TrimSpace(operatorIata) == "" {
+		missing = append(missing, reasonNoAirline)
+	}
+	if strings.TrimSpace(flightNumber) == "" && strings.TrimSpace(ident) == "" {
+		missing = append(missing, reasonNoFlightNumber)
+	}
+	return missing
+}
+
+// sampleIdent returns the ident a skipped flight is listed under in a
+// SkipReport: its ident, else its FlightAware ID
+func sampleIdent(ident, faFlightID string) string {
+	switch {
+	case strings.TrimSpace(ident) != "":
+		return strings.TrimSpace(ident)
+	case faFlightID != "":
+		return faFlightID
+	}
+	return "(no ident)"
+}
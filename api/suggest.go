@@ -0,0 +1,148 @@
+package api
+
+import (
+	"sort"
+	"strings"
+)
+
+// KnownAirline reports whether code is an IATA or ICAO airline code in the
+// airline table, which lists major carriers only unless data files were loaded
+func KnownAirline(code string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if _, ok := knownAirlines[code]; ok {
+		return true
+	}
+	for _, known := range knownAirlines {
+		if known.IATA == code {
+			return true
+		}
+	}
+	return false
+}
+
+// SuggestAirline returns the IATA code and name of the airline text most
+// likely means, such as "DL" for "Delta" or "UA" for "UNTIED", or false if
+// no airline is close. Names are matched ignoring case, preferring the
+// shortest name that starts with text, then the nearest by edit distance
+func SuggestAirline(text string) (string, string, bool) {
+	text = strings.ToUpper(strings.TrimSpace(text))
+	if text == "" {
+		return "", "", false
+	}
+	if known, ok := knownAirlines[text]; ok {
+		return known.IATA, known.Name, true
+	}
+
+	// Visit airlines in a fixed order so ties always resolve the same way
+	icaos := make([]string, 0, len(knownAirlines))
+	for icao := range knownAirlines {
+		icaos = append(icaos, icao)
+	}
+	sort.Strings(icaos)
+	// A known IATA code means its airline, not one whose name is close to it
+	for _, icao := range icaos {
+		if known := knownAirlines[icao]; known.IATA == text {
+			return known.IATA, known.Name, true
+		}
+	}
+
+	var best airline
+	bestScore := -1
+	for _, icao := range icaos {
+		known := knownAirlines[icao]
+		name := strings.ToUpper(known.Name)
+		score := -1
+		switch {
+		case name == text:
+			score = 0
+		case strings.HasPrefix(strings.ReplaceAll(name, " ", ""), strings.ReplaceAll(text, " ", "")):
+			// Shorter names are the more likely meaning of a prefix
+			score = 1 + len(name) - len(text)
+		default:
+			first, _, _ := strings.Cut(name, " ")
+			if d := editDistance(text, first); d <= maxTypos(text) {
+				score = 100 + d
+			}
+		}
+		if score >= 0 && (bestScore < 0 || score < bestScore) {
+			best, bestScore = known, score
+		}
+	}
+	if bestScore < 0 {
+		return "", "", false
+	}
+	return best.IATA, best.Name, true
+}
+
+// KnownAirport reports whether code is an IATA airport code in the built-in
+// airport and timezone tables, which list major airports only, or the ICAO
+// code of one in the airport table
+func KnownAirport(code string) bool {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	_, icao := airportICAOCodes[code]
+	_, zone := airportTimezones[code]
+	if icao || zone {
+		return true
+	}
+	_, ok := airportICAOCodes[AirportIATA(code)]
+	return ok
+}
+
+// SuggestAirport returns the known airport code closest to code, such as
+// "JFK" for "JKF", or false if none is within a typo of it
+func SuggestAirport(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	seen := make(map[string]bool)
+	var codes []string
+	for _, table := range []map[string]string{airportICAOCodes, airportTimezones} {
+		for known := range table {
+			if !seen[known] {
+				seen[known] = true
+				codes = append(codes, known)
+			}
+		}
+	}
+	sort.Strings(codes)
+
+	best, bestDistance := "", maxTypos(code)+1
+	for _, known := range codes {
+		if d := editDistance(code, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best, best != ""
+}
+
+// maxTypos returns how many edits a word of text's length may be off by
+// and still be suggested: one for short codes, more for longer names
+func maxTypos(text string) int {
+	return max(1, len(text)/4)
+}
+
+// editDistance returns the number of single character insertions, deletions,
+// substitutions and transpositions of adjacent characters turning a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance between the first i runes of a and j of b
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
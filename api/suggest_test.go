@@ -0,0 +1,94 @@
+package api
+
+import "testing"
+
+func TestSuggestAirline(t *testing.T) {
+	tests := []struct {
+		text string
+		iata string // Empty if nothing should be suggested
+		name string
+	}{
+		// Names and prefixes of names, in any case
+		{"Delta", "DL", "Delta Air Lines"},
+		{"LUFTHANSA", "LH", "Lufthansa"},
+		{"british", "BA", "British Airways"},
+		{"southwest", "WN", "Southwest Airlines"},
+		{"jetblu", "B6", "JetBlue Airways"},
+		// Typos of the first word of a name
+		{"UNTIED", "UA", "United Airlines"},
+		{"Amercan", "AA", "American Airlines"},
+		// Codes mean their own airline, not one with a name close to them
+		{"DAL", "DL", "Delta Air Lines"},
+		{"dl", "DL", "Delta Air Lines"},
+		{" ua ", "UA", "United Airlines"},
+		// Nothing close
+		{"XYZZYQ", "", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		iata, name, ok := SuggestAirline(tt.text)
+		if ok != (tt.iata != "") || iata != tt.iata || name != tt.name {
+			t.Errorf("SuggestAirline(%q) = %q, %q, %v, want %q, %q", tt.text, iata, name, ok, tt.iata, tt.name)
+		}
+	}
+}
+
+func TestKnownAirline(t *testing.T) {
+	for code, want := range map[string]bool{"DL": true, "dal": true, " UA ": true, "ZZ": false, "Delta": false, "": false} {
+		if got := KnownAirline(code); got != want {
+			t.Errorf("KnownAirline(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestSuggestAirport(t *testing.T) {
+	tests := []struct {
+		code string
+		want string // Empty if nothing should be suggested
+	}{
+		{"JKF", "JFK"}, // Transposed
+		{"LXA", "LAX"},
+		{"SF0", "SFO"}, // Zero for O
+		{"ORDD", "ORD"},
+		{"jfk", "JFK"},
+		{"ZZZ", ""},
+		{"QQQQQ", ""},
+	}
+	for _, tt := range tests {
+		got, ok := SuggestAirport(tt.code)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("SuggestAirport(%q) = %q, %v, want %q", tt.code, got, ok, tt.want)
+		}
+	}
+}
+
+func TestKnownAirport(t *testing.T) {
+	for code, want := range map[string]bool{"JFK": true, "lhr": true, "KJFK": true, "EGLL": true, "JKF": false, "ZZZZ": false} {
+		if got := KnownAirport(code); got != want {
+			t.Errorf("KnownAirport(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"JFK", "JFK", 0},
+		{"JFK", "JKF", 1}, // A transposition is one edit
+		{"JFK", "JF", 1},
+		{"JFK", "XJFK", 1},
+		{"KITTEN", "SITTING", 3},
+		{"", "ABC", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
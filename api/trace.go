@@ -0,0 +1,91 @@
+package api
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"fids-tui/metrics"
+)
+
+// requestDuration is the time taken by each phase of the requests made to
+// the data sources, served to Prometheus when METRICS_ADDR is set
+var requestDuration = metrics.NewHistogram("fids_api_request_duration_seconds",
+	"Time taken by each phase of requests to flight data sources.", metrics.RequestBuckets, "source", "phase")
+
+// requestTiming breaks down how long one HTTP request took
+type requestTiming struct {
+	DNS     time.Duration // Resolving the host name, zero on a reused connection
+	Connect time.Duration // Opening the TCP connection
+	TLS     time.Duration // TLS handshake, zero over plain HTTP
+	TTFB    time.Duration // From the request being written to the first response byte
+	Total   time.Duration // From starting the request to reading the last byte
+	Reused  bool          // The request went over a kept-alive connection
+}
+
+// requestTimer records the timing of a request through httptrace. Dials can
+// race each other, so the hooks lock before recording
+type requestTimer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wrote        time.Time
+	timing       requestTiming
+}
+
+// traceRequest returns req with a trace attached that times it
+func traceRequest(req *http.Request) (*http.Request, *requestTimer) {
+	t := &requestTimer{start: time.Now()}
+	record := func(f func()) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		f()
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(func() { t.dnsStart = time.Now() }) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { record(func() { t.timing.DNS = time.Since(t.dnsStart) }) },
+		ConnectStart: func(string, string) {
+			record(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone:          func(string, string, error) { record(func() { t.timing.Connect = time.Since(t.connectStart) }) },
+		TLSHandshakeStart:    func() { record(func() { t.tlsStart = time.Now() }) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(func() { t.timing.TLS = time.Since(t.tlsStart) }) },
+		GotConn:              func(info httptrace.GotConnInfo) { record(func() { t.timing.Reused = info.Reused }) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { record(func() { t.wrote = time.Now() }) },
+		GotFirstResponseByte: func() { record(func() { t.timing.TTFB = time.Since(t.wrote) }) },
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+// done completes the timing once the response has been read, or the request
+// failed, logs it at debug level and records it in requestDuration. Phases
+// the request skipped, such as DNS on a reused connection, aren't recorded
+func (t *requestTimer) done(source, path string) requestTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timing.Total = time.Since(t.start)
+	slog.Debug("request timing", "source", source, "path", path,
+		"dns", t.timing.DNS, "connect", t.timing.Connect, "tls", t.timing.TLS,
+		"ttfb", t.timing.TTFB, "total", t.timing.Total, "reused", t.timing.Reused)
+	for _, phase := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"dns", t.timing.DNS}, {"connect", t.timing.Connect}, {"tls", t.timing.TLS},
+		{"ttfb", t.timing.TTFB}, {"total", t.timing.Total},
+	} {
+		if phase.duration > 0 {
+			requestDuration.Observe(phase.duration.Seconds(), source, phase.name)
+		}
+	}
+	return t.timing
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// traceDelay is how long the test servers wait before answering
+const traceDelay = 50 * time.Millisecond
+
+// delayedServer answers every request with body after traceDelay
+func delayedServer(t *testing.T, tls bool, body string) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(traceDelay)
+		io.WriteString(w, body)
+	})
+	server := httptest.NewUnstartedServer(handler)
+	if tls {
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTraceRequest(t *testing.T) {
+	for _, tls := range []bool{false, true} {
+		server := delayedServer(t, tls, "ok")
+		client := server.Client()
+		for i, reused := range []bool{false, true} {
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			req, timer := traceRequest(req)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			timing := timer.done("Test", "/")
+
+			if timing.TTFB < traceDelay || timing.Total < timing.TTFB {
+				t.Errorf("tls %v, request %d: TTFB %s and total %s, want at least the %s delay", tls, i+1, timing.TTFB, timing.Total, traceDelay)
+			}
+			if timing.Reused != reused {
+				t.Errorf("tls %v, request %d: reused %v, want %v", tls, i+1, timing.Reused, reused)
+			}
+			// A kept-alive connection is neither opened nor shaken hands on again
+			if connected := timing.Connect > 0; connected == reused {
+				t.Errorf("tls %v, request %d: connect %s on a reused %v connection", tls, i+1, timing.Connect, reused)
+			}
+			if handshake := timing.TLS > 0; handshake != (tls && !reused) {
+				t.Errorf("tls %v, request %d: handshake %s", tls, i+1, timing.TLS)
+			}
+		}
+	}
+}
+
+// TestRequestDurationMetrics checks that each page a fetch requests is timed
+// into the request duration histogram
+func TestRequestDurationMetrics(t *testing.T) {
+	server := delayedServer(t, false, `{"scheduled_departures": [], "num_pages": 1}`)
+	client := NewFlightAwareClient("test-key", WithBaseURL(server.URL))
+	total := requestDuration.Count(client.Name(), "total")
+	ttfb := requestDuration.Count(client.Name(), "ttfb")
+	dns := requestDuration.Count(client.Name(), "dns")
+
+	if _, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{}); err != nil {
+		t.Fatalf("GetDepartures: %v", err)
+	}
+	if got := requestDuration.Count(client.Name(), "total") - total; got != 1 {
+		t.Errorf("%d totals recorded, want 1", got)
+	}
+	if got := requestDuration.Count(client.Name(), "ttfb") - ttfb; got != 1 {
+		t.Errorf("%d times to first byte recorded, want 1", got)
+	}
+	// The server is dialled by IP address, so no lookup is made or recorded
+	if got := requestDuration.Count(client.Name(), "dns") - dns; got != 0 {
+		t.Errorf("%d DNS lookups recorded, want 0", got)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import "sync/atomic"
+
+// DefaultCostPerQuery is FlightAware's published AeroAPI personal-tier price
+// for one result set of the airport flights endpoints, in US dollars
+const DefaultCostPerQuery = 0.005
+
+// Usage counts the requests made and result pages received by a client
+// It is safe for concurrent use
+type Usage struct {
+	requests atomic.Int64
+	pages    atomic.Int64
+}
+
+// Requests returns the number of requests made, including failed ones
+func (u *Usage) Requests() int64 {
+	if u == nil {
+		return 0
+	}
+	return u.requests.Load()
+}
+
+// Pages returns the number of result pages received, which is what AeroAPI bills for
+func (u *Usage) Pages() int64 {
+	if u == nil {
+		return 0
+	}
+	return u.pages.Load()
+}
+
+// addRequest records a request being made
+func (u *Usage) addRequest() {
+	if u != nil {
+		u.requests.Add(1)
+	}
+}
+
+// addPage records a result page being received
+func (u *Usage) addPage() {
+	if u != nil {
+		u.pages.Add(1)
+	}
+}
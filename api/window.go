@@ -0,0 +1,66 @@
+package api
+
+import "time"
+
+// FetchWindow decides how far ahead flights are fetched. Both the end time
+// sent to a source and the cutoff applied to its results come from one
+// reading of the window's clock, so they always agree
+type FetchWindow struct {
+	// Now is the clock the window is anchored to; time.Now if nil
+	Now func() time.Time
+	// OperationalDay makes the window run to the end of the airport's local
+	// day instead of a number of hours
+	OperationalDay bool
+	// DayEnd is the airport's local time of day its operational day ends,
+	// as an offset from midnight, e.g. 3h for 03:00
+	DayEnd time.Duration
+}
+
+// now reads the window's clock
+func (w FetchWindow) now() time.Time {
+	if w.Now == nil {
+		return time.Now()
+	}
+	return w.Now()
+}
+
+// End returns the end of the window starting at now for airportCode, or false
+// if it has no end. By default the window is the next length of absolute
+// time, unaffected by the airport's or the host's timezone and DST changes;
+// a zero length has no end. In operational day mode it ends at the next
+// DayEnd on the airport's clock, and length is ignored
+func (w FetchWindow) End(now time.Time, airportCode string, length time.Duration) (time.Time, bool) {
+	if w.OperationalDay {
+		return nextClockTime(now, GetAirportTimezone(airportCode), w.DayEnd), true
+	}
+	if length <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(length), true
+}
+
+// nextClockTime returns the first time after now that loc's clock shows
+// offset past midnight. Clock times skipped by a DST change fall as far
+// after the change as they are into the skipped hour, e.g. 02:30 at 03:30
+func nextClockTime(now time.Time, loc *time.Location, offset time.Duration) time.Time {
+	local := now.In(loc)
+	year, month, day := local.Date()
+	next := clockTime(year, month, day, loc, offset)
+	if !next.After(now) {
+		next = clockTime(year, month, day+1, loc, offset)
+	}
+	return next
+}
+
+// clockTime returns the time loc's clock shows offset past midnight on the
+// given day. time.Date may resolve a skipped clock time with the offset from
+// before the change, showing an hour earlier, so that is moved past it
+func clockTime(year int, month time.Month, day int, loc *time.Location, offset time.Duration) time.Time {
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	t := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	shown := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if t.Day() == day && shown < offset.Truncate(time.Minute) {
+		t = t.Add(offset.Truncate(time.Minute) - shown)
+	}
+	return t
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// dstEnd is half past midnight at JFK on the night New York leaves daylight
+// saving time, when its clock runs 01:00 to 02:00 twice
+var dstEnd = time.Date(2026, time.November, 1, 4, 30, 0, 0, time.UTC)
+
+func TestFetchWindowEnd(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	threeAM := 3 * time.Hour
+	tests := []struct {
+		name    string
+		window  FetchWindow
+		now     time.Time
+		airport string
+		length  time.Duration
+		want    time.Time // Zero if the window has no end
+	}{
+		// Hours of absolute time, whatever the clocks on either side show
+		{"hours across the change", FetchWindow{}, dstEnd, "JFK", 6 * time.Hour, dstEnd.Add(6 * time.Hour)},
+		{"hours on a host in Paris", FetchWindow{}, dstEnd.In(paris), "JFK", 6 * time.Hour, dstEnd.Add(6 * time.Hour)},
+		{"no hours, no end", FetchWindow{}, dstEnd, "JFK", 0, time.Time{}},
+
+		// To 03:00 on the airport's clock, which the extra hour puts 7½ hours away
+		{"operational day across the change", FetchWindow{OperationalDay: true, DayEnd: threeAM}, dstEnd, "JFK", 6 * time.Hour,
+			time.Date(2026, time.November, 1, 8, 0, 0, 0, time.UTC)},
+		{"operational day on a host in Paris", FetchWindow{OperationalDay: true, DayEnd: threeAM}, dstEnd.In(paris), "JFK", 0,
+			time.Date(2026, time.November, 1, 8, 0, 0, 0, time.UTC)},
+		{"operational day of another airport", FetchWindow{OperationalDay: true, DayEnd: threeAM}, dstEnd, "LAX", 0,
+			time.Date(2026, time.November, 1, 11, 0, 0, 0, time.UTC)},
+		{"operational day already over today", FetchWindow{OperationalDay: true, DayEnd: threeAM}, dstEnd.Add(5 * time.Hour), "JFK", 0,
+			time.Date(2026, time.November, 2, 8, 0, 0, 0, time.UTC)},
+		// 02:30 is skipped when the clocks go forward, so the day ends at 03:30
+		{"operational day ending in the skipped hour", FetchWindow{OperationalDay: true, DayEnd: 150 * time.Minute},
+			time.Date(2026, time.March, 8, 5, 0, 0, 0, time.UTC), "JFK", 0,
+			time.Date(2026, time.March, 8, 7, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, ok := tt.window.End(tt.now, tt.airport, tt.length)
+			if ok != !tt.want.IsZero() || !end.Equal(tt.want) {
+				t.Errorf("End = %v, %v, want %v", end.UTC(), ok, tt.want)
+			}
+		})
+	}
+}
+
+// TestFetchWindowAgrees checks that the end sent to AeroAPI and the cutoff of
+// the flights kept are the same instant in both modes
+func TestFetchWindowAgrees(t *testing.T) {
+	tests := []struct {
+		name   string
+		window FetchWindow
+		end    time.Time
+	}{
+		{"hours", FetchWindow{}, dstEnd.Add(6 * time.Hour)},
+		{"operational day", FetchWindow{OperationalDay: true, DayEnd: 3 * time.Hour}, time.Date(2026, time.November, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The server ignores the end asked for, so the client must cut
+			// the flights it is sent at the same time
+			var asked string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				asked = r.URL.Query().Get("end")
+				var departures []map[string]any
+				for i, offset := range []time.Duration{-time.Minute, 0, time.Minute} {
+					departures = append(departures, map[string]any{
+						"ident":         fmt.Sprintf("AAL%d", 100+i),
+						"fa_flight_id":  fmt.Sprintf("AAL%d-window", 100+i),
+						"operator_iata": "AA",
+						"flight_number": fmt.Sprint(100 + i),
+						"origin":        map[string]string{"code_iata": "JFK"},
+						"destination":   map[string]string{"code_iata": "LAX", "city": "Los Angeles"},
+						"scheduled_out": tt.end.Add(offset).Format(time.RFC3339),
+						"status":        "Scheduled",
+						"gate_origin":   "B2",
+					})
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]any{"scheduled_departures": departures, "num_pages": 1})
+			}))
+			defer server.Close()
+			client := NewFlightAwareClient("test-key", WithBaseURL(server.URL))
+			client.Window = tt.window
+			client.Window.Now = func() time.Time { return dstEnd }
+
+			result, err := client.GetDepartures(context.Background(), "JFK", FetchOptions{Window: 6 * time.Hour})
+			if err != nil {
+				t.Fatalf("GetDepartures: %v", err)
+			}
+			if want := tt.end.Format(time.RFC3339); asked != want {
+				t.Errorf("asked for flights until %s, want %s", asked, want)
+			}
+			var kept []string
+			for _, f := range result.Flights {
+				kept = append(kept, f.Ident)
+			}
+			if fmt.Sprint(kept) != "[AAL100 AAL101]" {
+				t.Errorf("kept %v, want the flights up to and at the end", kept)
+			}
+		})
+	}
+}
@@ -1,63 +1,473 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
 	APIKey               string
+	FlightAwareBaseURL   string        // Alternate AeroAPI base URL, e.g. a sandbox or mock server
+	FlightAwareTimeout   time.Duration // Timeout for each AeroAPI request
+	Strict               bool          // Leave out and report flights missing required fields rather than filling in placeholders
+	SlowFetchWarning     time.Duration // Fetches taking longer are flagged "API slow" in the status bar; zero never flags them
+	CACertFile           string        // PEM CA bundle trusted in addition to the system roots
+	InsecureSkipVerify   bool          // Disable TLS verification; only set from the command line
 	AirportCode          string
+	DataSource           string
+	FallbackSource       string
+	ADSBFeedURL          string
 	UpdateInterval       time.Duration
-	LookaheadHours       int
+	LookaheadHours       int    // Flights are fetched for this many hours ahead
+	OperationalDay       string // Airport local time the day ends, e.g. "03:00"; fetches run until then instead
 	TotalFlights         int
+	TaxiLookups          int // Taxiing departures looked up on each fetch to learn when they took off
 	FlightsPerPage       int
 	MaxPages             int
 	PageRotationInterval time.Duration
-	CharAnimationSpeed   time.Duration
+	AdaptiveRotation     bool          // Shorten the rotation for mostly empty pages and lengthen it for pages of imminent flights
+	CharAnimationSpeed   time.Duration // How often animations are redrawn: the frame interval, not how long they last
+	BlinkPhase           time.Duration // How long each blink of a changed character lasts
+	BlinkDuration        time.Duration // How long a changed character blinks before it shows
+	PauseUnfocused       bool          // Stop animating while the terminal reports it is unfocused
+	RemarkTemplates      map[string]string
+	Glyphs               string            // Icon set: ascii, unicode or nerdfont
+	StatusGlyphs         map[string]string // Status light overrides by status name
+	Palette              string            // Status light colors: default, or colorblind
+	ForceColor           bool              // Draw in color even if the terminal isn't detected to show it
+	ForceUnicode         bool              // Draw block characters and glyph sets other than ascii without a UTF-8 locale
+	Borders              string
+	LargeHeader          bool
+	HeaderSuffix         string // Branding shown after the airport header
+	FooterText           string // Shown below the board in place of the key help, which moves to '?'
+	ScrollFooter         bool   // Scroll a FooterText too wide for the terminal instead of cutting it short
+	ScrollColumns        string // Columns whose long text scrolls instead of being cut short: destination, remarks
+	StartupRetries       string // Delays between attempts at a board's first fetch, e.g. 10s,30s,60s
+	ShowTimeline         bool   // Show the lookahead window as a bar with the flights marked
+	CancellationStrip    bool   // Show the cancelled flights on a line under the header
+	PageTransitions      bool   // Flip rows out and in when the page changes instead of switching at once
+	Layout               string // wide, or compact for two lines per flight
+	View                 string // flights, gates to group flights by gate, shuttle, or ticker for one line
+	TimeZoneMode         string // airport, utc or local: the timezone flight times are shown in
+	IdleAfter            time.Duration
+	NightUpdateInterval  time.Duration
+	UpdateSchedule       string
+	QuietHours           string        // Daily range without polling or animation, e.g. "22:00-07:00"
+	DirectionSchedule    string        // When the board shows departures or arrivals, e.g. "00:00-12:00=departures,12:00-24:00=arrivals"
+	Tabs                 string        // Board tabs, e.g. "JFK:dep,JFK:arr,EWR:dep"
+	Airports             string        // Nearby airports compared on one screen, e.g. "BWI,DCA"
+	AirportsLayout       string        // How compared airports are shown: sidebyside or interleaved
+	BackgroundInterval   time.Duration // Update interval for tabs that are not shown
+	BoardCacheTTL        time.Duration // How long boards switched away from are kept for reuse
+	HideNoDestination    bool          // Leave out flights whose destination (or origin) is unknown
+	ShowBlocked          string        // Where flights blocked from public tracking show: hide, board or all
+	RulesFile            string        // Rules renaming or hiding flights before they are shown
+	DestinationOnly      string        // Show only departures to this airport code
+	PriorityDestinations string        // Destinations of the shuttle view, in order, e.g. "BOS,DCA,ORD"
+	ShuttleFlights       int           // Flights shown for each destination in the shuttle view
+	TickerWidth          int           // Cells of the ticker view's line, 0 for the terminal's width
+	TickerSwap           time.Duration // How long the ticker shows flights before swapping in the next, 0 to scroll
+	Watch                string        // Flights and destinations kept in the sidebar, e.g. "UA123,DL45,LAX"
+	TrackWatchedFor      time.Duration // How long watched departures are looked up after leaving the board, zero for not at all
+	TrackWatchedEvery    time.Duration // How often a watched departure that left the board is looked up
+	NewBadgeDuration     time.Duration // How long flights added to the schedule are badged NEW
+	PinImminent          bool          // Fill the first page with the next departures, whatever the view's order
+	StaleEstimateAfter   time.Duration // Delayed flights past their time unchanged for longer are marked stale, zero for never
+	RetimedUpdates       int           // Updates a retimed flight's remarks show the time it moved from, zero for never
+	InboundLookups       int           // Inbound aircraft of delayed departures looked up per refresh for the detail panel, zero for none
+	EventLogSize         int           // Number of flight change events kept for the change log
+	EventLogRetention    time.Duration // How long change events are kept
+	NtfyURL              string        // ntfy server for phone alerts
+	NtfyTopic            string        // ntfy topic; alerts are sent to ntfy when set
+	PushoverToken        string        // Pushover application token
+	PushoverUser         string        // Pushover user or group key
+	NotifyWebhookURL     string        // URL that alerts are posted to as JSON
+	NotifyBell           bool          // Ring the terminal bell for alerts
+	NotifyOn             string        // Which changes are alerted, e.g. "cancelled,gate:LAX,flight:UA123"
+	NotifyMaxPerHour     int           // Alerts sent per backend per hour at most
+	MQTTURL              string        // MQTT broker flights and changes are published to, e.g. "mqtt://homeassistant.local:1883"
+	MQTTTopicPrefix      string        // First level of the MQTT topics, e.g. "fids" for fids/JFK/flights
+	MQTTUsername         string        // User name for brokers that require one
+	MQTTPassword         string        // Password for MQTTUsername
+	Sound                bool          // Play a flap sound when an update flips rows
+	SoundCommand         string        // Command playing the flap sound, e.g. "aplay flap.wav"; the terminal bell if empty
+	CostPerQuery         float64       // Estimated price of one AeroAPI result page, in US dollars
+	MaxCallsPerHour      int           // API calls allowed an hour, the board's own fetches first; 0 for no cap
+	MaxRequestsPerMinute int           // AeroAPI requests made a minute at most, spaced out across concurrent fetches; 0 for no limit
+	StateFile            string        // Where state kept between runs is saved, e.g. the daily API spend
+	DataDir              string        // Where airline and airport data downloaded by -update-data is kept
+	ControlSocket        string        // Unix socket scripts control the board through: "on" for the default path, or a path
+	MetricsAddr          string        // Address Prometheus metrics are served on at /metrics, e.g. ":9090"; not served if empty
+	RecordDir            string        // Directory each fetch's flights are saved to as a snapshot, for replaying later; not recorded if empty
+	ReplayDir            string        // Directory of recorded snapshots shown instead of fetching, set by -replay
+	Kiosk                bool          // Ignore the keyboard and mouse until KioskUnlock is typed
+	KioskUnlock          string        // Keys typed in a row that unlock a kiosk for a few minutes
+	LogFile              string        // Where diagnostic logs are written; discarded if empty
+	LogLevel             string        // debug, info, warn or error
+	FileError            error         // Why the config file couldn't be read, nil if it was or doesn't exist
 }
 
-// LoadConfig loads configuration from environment variables and sets defaults
-func LoadConfig() *Config {
-	cfg := &Config{
-		APIKey:               getEnv("FLIGHTAWARE_API_KEY", ""),
-		AirportCode:          getEnv("AIRPORT_CODE", ""),
+// Default returns the default configuration without reading the environment
+func Default() *Config {
+	return &Config{
+		DataSource:           "flightaware",
+		FlightAwareTimeout:   30 * time.Second,
+		SlowFetchWarning:     10 * time.Second,
 		UpdateInterval:       10 * time.Minute,
+		StartupRetries:       "10s,30s,60s",
 		LookaheadHours:       6,
 		TotalFlights:         50,
 		FlightsPerPage:       15,
 		MaxPages:             3,
 		PageRotationInterval: 15 * time.Second,
 		CharAnimationSpeed:   250 * time.Millisecond,
+		BlinkPhase:           100 * time.Millisecond,
+		BlinkDuration:        300 * time.Millisecond,
+		Borders:              "none",
+		Glyphs:               "ascii",
+		Palette:              "default",
+		Layout:               "wide",
+		View:                 "flights",
+		ShowBlocked:          "hide",
+		AirportsLayout:       "sidebyside",
+		TimeZoneMode:         "airport",
+		IdleAfter:            30 * time.Minute,
+		BackgroundInterval:   30 * time.Minute,
+		BoardCacheTTL:        5 * time.Minute,
+		NewBadgeDuration:     time.Hour,
+		TrackWatchedEvery:    5 * time.Minute,
+		RetimedUpdates:       3,
+		ShuttleFlights:       3,
+		EventLogSize:         500,
+		EventLogRetention:    24 * time.Hour,
+		NtfyURL:              "https://ntfy.sh",
+		NotifyOn:             "cancelled",
+		NotifyMaxPerHour:     10,
+		MQTTTopicPrefix:      "fids",
+		CostPerQuery:         0.005,
+		KioskUnlock:          "admin",
+		LogLevel:             "info",
 	}
+}
+
+// LoadConfig loads configuration from environment variables, then the config
+// file for settings they leave out, and sets defaults
+func LoadConfig() *Config {
+	cfg := Default()
+	fileValues = nil
+	if path := ConfigFile(); path != "" {
+		values, err := ReadConfigFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			cfg.FileError = err
+		}
+		fileValues = values
+	}
+	cfg.APIKey = getEnv("FLIGHTAWARE_API_KEY", cfg.APIKey)
+	cfg.FlightAwareBaseURL = getEnv("FLIGHTAWARE_BASE_URL", cfg.FlightAwareBaseURL)
+	cfg.Strict = getEnvBool("STRICT", cfg.Strict)
+	cfg.CACertFile = getEnv("CA_CERT_FILE", cfg.CACertFile)
+	cfg.AirportCode = getEnv("AIRPORT_CODE", cfg.AirportCode)
+	cfg.DataSource = strings.ToLower(getEnv("DATA_SOURCE", cfg.DataSource))
+	cfg.FallbackSource = strings.ToLower(getEnv("FALLBACK_SOURCE", cfg.FallbackSource))
+	cfg.ADSBFeedURL = getEnv("ADSB_FEED_URL", cfg.ADSBFeedURL)
+	cfg.Borders = getEnv("BORDERS", cfg.Borders)
+	cfg.Glyphs = getEnv("GLYPHS", cfg.Glyphs)
+	cfg.Palette = getEnv("PALETTE", cfg.Palette)
+	cfg.ForceColor = getEnvBool("FORCE_COLOR", cfg.ForceColor)
+	cfg.ForceUnicode = getEnvBool("FORCE_UNICODE", cfg.ForceUnicode)
+	cfg.LargeHeader = getEnvBool("LARGE_HEADER", cfg.LargeHeader)
+	cfg.HeaderSuffix = strings.TrimSpace(getEnv("HEADER_SUFFIX", cfg.HeaderSuffix))
+	cfg.FooterText = strings.TrimSpace(getEnv("FOOTER_TEXT", cfg.FooterText))
+	cfg.ScrollFooter = getEnvBool("SCROLL_FOOTER", cfg.ScrollFooter)
+	cfg.ScrollColumns = getEnv("SCROLL_COLUMNS", cfg.ScrollColumns)
+	cfg.StartupRetries = getEnv("STARTUP_RETRIES", cfg.StartupRetries)
+	cfg.ShowTimeline = getEnvBool("SHOW_TIMELINE", cfg.ShowTimeline)
+	cfg.CancellationStrip = getEnvBool("CANCELLATION_STRIP", cfg.CancellationStrip)
+	cfg.PageTransitions = getEnvBool("PAGE_TRANSITIONS", cfg.PageTransitions)
+	cfg.PinImminent = getEnvBool("PIN_IMMINENT_FIRST_PAGE", cfg.PinImminent)
+	cfg.AdaptiveRotation = getEnvBool("ADAPTIVE_ROTATION", cfg.AdaptiveRotation)
+	cfg.PauseUnfocused = getEnvBool("PAUSE_UNFOCUSED", cfg.PauseUnfocused)
+	cfg.Layout = getEnv("LAYOUT", cfg.Layout)
+	cfg.View = getEnv("VIEW", cfg.View)
+	cfg.TimeZoneMode = getEnv("TIME_ZONE_MODE", cfg.TimeZoneMode)
+	cfg.UpdateSchedule = getEnv("UPDATE_SCHEDULE", cfg.UpdateSchedule)
+	cfg.OperationalDay = getEnv("OPERATIONAL_DAY", cfg.OperationalDay)
+	cfg.QuietHours = getEnv("QUIET_HOURS", cfg.QuietHours)
+	cfg.DirectionSchedule = getEnv("DIRECTION_SCHEDULE", cfg.DirectionSchedule)
+	cfg.Tabs = getEnv("TABS", cfg.Tabs)
+	cfg.Airports = getEnv("AIRPORTS", cfg.Airports)
+	cfg.AirportsLayout = strings.ToLower(getEnv("AIRPORTS_LAYOUT", cfg.AirportsLayout))
+	cfg.HideNoDestination = getEnvBool("HIDE_NO_DESTINATION", cfg.HideNoDestination)
+	cfg.ShowBlocked = getEnv("SHOW_BLOCKED", cfg.ShowBlocked)
+	cfg.RulesFile = getEnv("RULES_FILE", cfg.RulesFile)
+	cfg.DestinationOnly = getEnv("DESTINATION_ONLY", cfg.DestinationOnly)
+	cfg.PriorityDestinations = getEnv("PRIORITY_DESTINATIONS", cfg.PriorityDestinations)
+	cfg.Watch = getEnv("WATCH", cfg.Watch)
+	cfg.NtfyURL = getEnv("NTFY_URL", cfg.NtfyURL)
+	cfg.NtfyTopic = getEnv("NTFY_TOPIC", cfg.NtfyTopic)
+	cfg.PushoverToken = getEnv("PUSHOVER_TOKEN", cfg.PushoverToken)
+	cfg.PushoverUser = getEnv("PUSHOVER_USER", cfg.PushoverUser)
+	cfg.NotifyWebhookURL = getEnv("NOTIFY_WEBHOOK_URL", cfg.NotifyWebhookURL)
+	cfg.NotifyBell = getEnvBool("NOTIFY_BELL", cfg.NotifyBell)
+	cfg.NotifyOn = getEnv("NOTIFY_ON", cfg.NotifyOn)
+	cfg.MQTTURL = getEnv("MQTT_URL", cfg.MQTTURL)
+	cfg.MQTTTopicPrefix = strings.Trim(getEnv("MQTT_TOPIC_PREFIX", cfg.MQTTTopicPrefix), "/")
+	cfg.MQTTUsername = getEnv("MQTT_USERNAME", cfg.MQTTUsername)
+	cfg.MQTTPassword = getEnv("MQTT_PASSWORD", cfg.MQTTPassword)
+	cfg.Sound = getEnvBool("SOUND", cfg.Sound)
+	cfg.SoundCommand = getEnv("SOUND_COMMAND", cfg.SoundCommand)
+	cfg.StateFile = getEnv("STATE_FILE", DefaultStateFile())
+	cfg.DataDir = getEnv("DATA_DIR", DefaultDataDir())
+	cfg.ControlSocket = getEnv("CONTROL_SOCKET", cfg.ControlSocket)
+	cfg.MetricsAddr = getEnv("METRICS_ADDR", cfg.MetricsAddr)
+	cfg.RecordDir = getEnv("RECORD_DIR", cfg.RecordDir)
+	cfg.Kiosk = getEnvBool("KIOSK", cfg.Kiosk)
+	cfg.KioskUnlock = getEnv("KIOSK_UNLOCK", cfg.KioskUnlock)
+	cfg.LogFile = getEnv("LOG_FILE", cfg.LogFile)
+	cfg.LogLevel = getEnv("LOG_LEVEL", cfg.LogLevel)
 
 	// Override with environment variables if set
-	if val := os.Getenv("UPDATE_INTERVAL"); val != "" {
+	if val := lookupEnv("UPDATE_INTERVAL"); val != "" {
 		if d, err := time.ParseDuration(val); err == nil {
 			cfg.UpdateInterval = d
 		}
 	}
 
-	if val := os.Getenv("PAGE_ROTATION_INTERVAL"); val != "" {
+	if val := lookupEnv("FLIGHTAWARE_TIMEOUT"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			cfg.FlightAwareTimeout = d
+		}
+	}
+
+	if val := lookupEnv("SLOW_FETCH_WARNING"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.SlowFetchWarning = d
+		}
+	}
+
+	if val := lookupEnv("PAGE_ROTATION_INTERVAL"); val != "" {
 		if d, err := time.ParseDuration(val); err == nil {
 			cfg.PageRotationInterval = d
 		}
 	}
 
-	if val := os.Getenv("MAX_PAGES"); val != "" {
+	if val := lookupEnv("IDLE_AFTER"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			cfg.IdleAfter = d
+		}
+	}
+
+	if val := lookupEnv("NIGHT_UPDATE_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			cfg.NightUpdateInterval = d
+		}
+	}
+
+	if val := lookupEnv("BACKGROUND_UPDATE_INTERVAL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			cfg.BackgroundInterval = d
+		}
+	}
+
+	if val := lookupEnv("BOARD_CACHE_TTL"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			cfg.BoardCacheTTL = d
+		}
+	}
+
+	if val := lookupEnv("NEW_BADGE_DURATION"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.NewBadgeDuration = d
+		}
+	}
+
+	if val := lookupEnv("STALE_ESTIMATE_AFTER"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.StaleEstimateAfter = d
+		}
+	}
+
+	if val := lookupEnv("BLINK_PHASE"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.BlinkPhase = d
+		}
+	}
+
+	if val := lookupEnv("BLINK_DURATION"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.BlinkDuration = d
+		}
+	}
+
+	if val := lookupEnv("SHUTTLE_FLIGHTS"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			cfg.ShuttleFlights = n
+		}
+	}
+
+	if val := lookupEnv("TICKER_WIDTH"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.TickerWidth = n
+		}
+	}
+
+	if val := lookupEnv("TICKER_SWAP"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.TickerSwap = d
+		}
+	}
+
+	if val := lookupEnv("RETIMED_REMARK_UPDATES"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.RetimedUpdates = n
+		}
+	}
+
+	if val := lookupEnv("TRACK_WATCHED_FOR"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+			cfg.TrackWatchedFor = d
+		}
+	}
+
+	if val := lookupEnv("TRACK_WATCHED_EVERY"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil && d > 0 {
+			cfg.TrackWatchedEvery = d
+		}
+	}
+
+	if val := lookupEnv("INBOUND_LOOKUPS"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.InboundLookups = n
+		}
+	}
+
+	if val := lookupEnv("EVENT_LOG_RETENTION"); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			cfg.EventLogRetention = d
+		}
+	}
+
+	if val := lookupEnv("EVENT_LOG_SIZE"); val != "" {
+		if size, err := strconv.Atoi(val); err == nil && size >= 0 {
+			cfg.EventLogSize = size
+		}
+	}
+
+	if val := lookupEnv("LOOKAHEAD_HOURS"); val != "" {
+		if hours, err := strconv.Atoi(val); err == nil && hours >= 0 {
+			cfg.LookaheadHours = hours
+		}
+	}
+
+	if val := lookupEnv("TOTAL_FLIGHTS"); val != "" {
+		if total, err := strconv.Atoi(val); err == nil && total > 0 {
+			cfg.TotalFlights = total
+		}
+	}
+
+	if val := lookupEnv("TAXI_LOOKUPS"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.TaxiLookups = n
+		}
+	}
+
+	if val := lookupEnv("NOTIFY_MAX_PER_HOUR"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.NotifyMaxPerHour = n
+		}
+	}
+
+	if val := lookupEnv("COST_PER_QUERY"); val != "" {
+		if cost, err := strconv.ParseFloat(val, 64); err == nil && cost >= 0 {
+			cfg.CostPerQuery = cost
+		}
+	}
+
+	if val := lookupEnv("MAX_CALLS_PER_HOUR"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.MaxCallsPerHour = n
+		}
+	}
+
+	if val := lookupEnv("MAX_REQUESTS_PER_MINUTE"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			cfg.MaxRequestsPerMinute = n
+		}
+	}
+
+	if val := lookupEnv("MAX_PAGES"); val != "" {
 		if pages, err := strconv.Atoi(val); err == nil && pages > 0 {
 			cfg.MaxPages = pages
 		}
 	}
 
+	if val := lookupEnv("REMARK_TEMPLATES"); val != "" {
+		cfg.RemarkTemplates = parseKeyValueList(val, ";")
+	}
+
+	if val := lookupEnv("STATUS_GLYPHS"); val != "" {
+		cfg.StatusGlyphs = parseKeyValueList(val, ";")
+	}
+
 	return cfg
 }
 
+// DefaultStateFile returns the state file in the user's config directory,
+// or an empty string if there is no such directory
+func DefaultStateFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "fids-tui", "state.json")
+}
+
+// DefaultDataDir returns the fids-tui directory in $XDG_DATA_HOME, which
+// defaults to ~/.local/share, or an empty string if there is no home directory
+func DefaultDataDir() string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "fids-tui")
+}
+
+// parseKeyValueList parses "key=value" pairs separated by sep
+// Entries without "=" are ignored
+func parseKeyValueList(val, sep string) map[string]string {
+	result := make(map[string]string)
+	for _, entry := range strings.Split(val, sep) {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		result[strings.TrimSpace(key)] = value
+	}
+	return result
+}
+
+// getEnvBool gets a boolean environment variable or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := lookupEnv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		return value
 	}
 	return defaultValue
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fileValues holds the settings of the config file LoadConfig read, by
+// environment variable name; the environment overrides them
+var fileValues map[string]string
+
+// lookupEnv returns the environment variable key, or its setting in the
+// config file if the environment doesn't set it
+func lookupEnv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fileValues[key]
+}
+
+// DefaultConfigFile returns the config file in the user's config directory,
+// or an empty string if there is no such directory
+func DefaultConfigFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "fids-tui", "config")
+}
+
+// ConfigFile returns the config file LoadConfig reads: CONFIG_FILE, or the
+// default file
+func ConfigFile() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+	return DefaultConfigFile()
+}
+
+// NeedsSetup reports whether nothing has been configured yet: FlightAware is
+// the data source but there is neither an API key nor a config file, and no
+// recording is being replayed instead
+func NeedsSetup(cfg *Config) bool {
+	if cfg.DataSource != "flightaware" || cfg.APIKey != "" || cfg.ReplayDir != "" {
+		return false
+	}
+	path := ConfigFile()
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// ReadConfigFile reads the settings of a config file, one KEY=value per line
+// named like the environment variables. Blank lines and lines starting with
+// # are ignored, and values may be double-quoted. A missing file is an error
+// matching os.ErrNotExist
+func ReadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		key, value, ok, err := parseConfigLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		if ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// parseConfigLine parses one line of a config file, reporting false for
+// blank lines and comments
+func parseConfigLine(line string) (string, string, bool, error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false, nil
+	}
+	key, value, ok := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", false, fmt.Errorf("expected KEY=value, got %q", line)
+	}
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", "", false, fmt.Errorf("%s: invalid quoted value", key)
+		}
+		value = unquoted
+	}
+	return key, value, true, nil
+}
+
+// formatConfigValue returns value as written to a config file, quoted if
+// it would not read back the same otherwise
+func formatConfigValue(value string) string {
+	if value != strings.TrimSpace(value) || strings.HasPrefix(value, `"`) || strings.ContainsAny(value, "\n\r") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// WriteConfigFile sets values in the config file at path, creating it if
+// needed. Comments and the lines of other settings are kept, and new
+// settings are added at the end. As the file can hold the API key, only its
+// owner can read it. It is replaced atomically so a crash never leaves a
+// partial file
+func WriteConfigFile(path string, values map[string]string) error {
+	var lines []string
+	data, err := os.ReadFile(path)
+	if err == nil {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	} else {
+		lines = []string{"# fids-tui settings, named like the environment variables, which override them"}
+	}
+
+	written := make(map[string]bool, len(values))
+	for i, line := range lines {
+		key, _, ok, _ := parseConfigLine(line)
+		if value, set := values[key]; ok && set {
+			lines[i] = key + "=" + formatConfigValue(value)
+			written[key] = true
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, key+"="+formatConfigValue(values[key]))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+// day is the length of a calendar day in clock time
+const day = 24 * time.Hour
+
+// TimeRange is a daily range of local clock times, which may wrap past midnight
+// Start is inclusive and End is exclusive; equal start and end cover the whole day
+type TimeRange struct {
+	Start time.Duration // Offset from midnight
+	End   time.Duration // Offset from midnight
+}
+
+// ParseTimeRange parses a range like "06:00-23:00" or "23:00-06:00"
+// "24:00" is accepted as an end time meaning midnight
+func ParseTimeRange(value string) (TimeRange, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return TimeRange{}, fmt.Errorf("invalid time range %q (expected HH:MM-HH:MM)", value)
+	}
+	start, err := ParseClock(startStr)
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid time range %q: %w", value, err)
+	}
+	end, err := ParseClock(endStr)
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid time range %q: %w", value, err)
+	}
+	if start == day {
+		return TimeRange{}, fmt.Errorf("invalid time range %q: start cannot be 24:00", value)
+	}
+	return TimeRange{Start: start, End: end % day}, nil
+}
+
+// ParseClock parses a clock time like "06:30" into an offset from midnight
+func ParseClock(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	if hours < 0 || hours > 24 || minutes < 0 || minutes > 59 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
+
+// clockOffset returns the offset of t from midnight in t's location
+func clockOffset(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// Contains reports whether t's local clock time falls within the range
+func (r TimeRange) Contains(t time.Time) bool {
+	offset := clockOffset(t)
+	switch {
+	case r.Start == r.End:
+		return true
+	case r.Start < r.End:
+		return offset >= r.Start && offset < r.End
+	default:
+		// Range wraps past midnight
+		return offset >= r.Start || offset < r.End
+	}
+}
+
+// UntilBoundary returns the time from t until the next start or end of the
+// range. The boundaries are clock times in t's location, so across a daylight
+// saving change the wait is an hour shorter or longer than the clocks suggest
+func (r TimeRange) UntilBoundary(t time.Time) time.Duration {
+	if r.Start == r.End {
+		return day
+	}
+	return min(nextClock(t, r.Start).Sub(t), nextClock(t, r.End).Sub(t))
+}
+
+// nextClock returns the first time after t at which the clocks of t's
+// location read offset from midnight. A clock time skipped when the clocks go
+// forward is taken to be the moment they do, when Contains first sees it passed
+func nextClock(t time.Time, offset time.Duration) time.Time {
+	year, month, date := t.Date()
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	clock := func(date int) time.Time {
+		next := time.Date(year, month, date, hour, minute, 0, 0, t.Location())
+		if next.Hour() != hour || next.Minute() != minute {
+			_, next = next.ZoneBounds()
+		}
+		return next
+	}
+	next := clock(date)
+	if !next.After(t) {
+		next = clock(date + 1)
+	}
+	return next
+}
+
+// String formats the range as HH:MM-HH:MM
+func (r TimeRange) String() string {
+	return formatClock(r.Start) + "-" + formatClock(r.End)
+}
+
+// formatClock formats an offset from midnight as HH:MM
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+// IntervalRule sets the update interval used during a time range
+type IntervalRule struct {
+	Range    TimeRange
+	Interval time.Duration
+}
+
+// IntervalSchedule maps local time ranges to update intervals
+// The first rule whose range contains the time wins
+type IntervalSchedule []IntervalRule
+
+// ParseIntervalSchedule parses a schedule like "06:00-23:00=10m, 23:00-06:00=45m"
+func ParseIntervalSchedule(value string) (IntervalSchedule, error) {
+	var schedule IntervalSchedule
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rangeStr, intervalStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule entry %q (expected HH:MM-HH:MM=interval)", entry)
+		}
+		tr, err := ParseTimeRange(rangeStr)
+		if err != nil {
+			return nil, err
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(intervalStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval in schedule entry %q: %w", entry, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("invalid interval in schedule entry %q: must be positive", entry)
+		}
+		schedule = append(schedule, IntervalRule{Range: tr, Interval: interval})
+	}
+	return schedule, nil
+}
+
+// IntervalAt returns the interval for the local clock time of t, and false if
+// no rule covers it
+func (s IntervalSchedule) IntervalAt(t time.Time) (time.Duration, bool) {
+	for _, rule := range s {
+		if rule.Range.Contains(t) {
+			return rule.Interval, true
+		}
+	}
+	return 0, false
+}
+
+// UntilChange returns the time from t until the next rule boundary, so that a
+// tick scheduled at a longer interval does not overshoot a change of interval
+func (s IntervalSchedule) UntilChange(t time.Time) time.Duration {
+	until := day
+	for _, rule := range s {
+		until = min(until, rule.Range.UntilBoundary(t))
+	}
+	return until
+}
+
+// DirectionRule shows departures or arrivals during a time range
+type DirectionRule struct {
+	Range     TimeRange
+	Direction models.Direction
+}
+
+// DirectionSchedule maps local time ranges to the direction a board shows
+// The first rule whose range contains the time wins
+type DirectionSchedule []DirectionRule
+
+// ParseDirectionSchedule parses a schedule like
+// "00:00-12:00=departures, 12:00-24:00=arrivals"
+func ParseDirectionSchedule(value string) (DirectionSchedule, error) {
+	var schedule DirectionSchedule
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rangeStr, directionStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid schedule entry %q (expected HH:MM-HH:MM=departures or HH:MM-HH:MM=arrivals)", entry)
+		}
+		tr, err := ParseTimeRange(rangeStr)
+		if err != nil {
+			return nil, err
+		}
+		direction, err := ParseDirection(directionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule entry %q: %w", entry, err)
+		}
+		schedule = append(schedule, DirectionRule{Range: tr, Direction: direction})
+	}
+	return schedule, nil
+}
+
+// DirectionAt returns the direction for the local clock time of t, and false
+// if no rule covers it
+func (s DirectionSchedule) DirectionAt(t time.Time) (models.Direction, bool) {
+	for _, rule := range s {
+		if rule.Range.Contains(t) {
+			return rule.Direction, true
+		}
+	}
+	return 0, false
+}
+
+// UntilChange returns the time from t until the next rule boundary
+func (s DirectionSchedule) UntilChange(t time.Time) time.Duration {
+	until := day
+	for _, rule := range s {
+		until = min(until, rule.Range.UntilBoundary(t))
+	}
+	return until
+}
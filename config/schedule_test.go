@@ -0,0 +1,166 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// newYork is the zone of the daylight saving tests: clocks go forward at
+// 02:00 on 8 March 2026 and back at 02:00 on 1 November 2026
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParseIntervalSchedule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string // Rules as range=interval, space separated
+		wantErr string
+	}{
+		{"06:00-23:00=10m, 23:00-06:00=45m", "06:00-23:00=10m0s 23:00-06:00=45m0s", ""},
+		{" 00:00-24:00=5m ,", "00:00-00:00=5m0s", ""},
+		{"", "", ""},
+		{"06:00-23:00", "", "expected HH:MM-HH:MM=interval"},
+		{"6:00-23:00=10m", "", `invalid time "6:00"`},
+		{"06:00-25:00=10m", "", `invalid time "25:00"`},
+		{"24:00-06:00=10m", "", "start cannot be 24:00"},
+		{"06:00-23:00=often", "", "invalid interval"},
+		{"06:00-23:00=0s", "", "must be positive"},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseIntervalSchedule(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseIntervalSchedule(%q) error = %v, want one containing %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseIntervalSchedule(%q): %v", tt.value, err)
+			continue
+		}
+		var rules []string
+		for _, rule := range schedule {
+			rules = append(rules, rule.Range.String()+"="+rule.Interval.String())
+		}
+		if got := strings.Join(rules, " "); got != tt.want {
+			t.Errorf("ParseIntervalSchedule(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIntervalScheduleBoundaries(t *testing.T) {
+	loc := newYork(t)
+	schedule, err := ParseIntervalSchedule("06:00-23:00=10m, 23:00-06:00=45m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(2026, month, day, hour, minute, second, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		interval time.Duration
+		until    time.Duration
+	}{
+		{"daytime", at(time.June, 10, 12, 0, 0), 10 * time.Minute, 11 * time.Hour},
+		{"a second before the change at 23:00", at(time.June, 10, 22, 59, 59), 10 * time.Minute, time.Second},
+		{"exactly 23:00", at(time.June, 10, 23, 0, 0), 45 * time.Minute, 7 * time.Hour},
+		{"before midnight", at(time.June, 10, 23, 30, 0), 45 * time.Minute, 6*time.Hour + 30*time.Minute},
+		{"exactly midnight", at(time.June, 11, 0, 0, 0), 45 * time.Minute, 6 * time.Hour},
+		{"after midnight", at(time.June, 11, 3, 15, 0), 45 * time.Minute, 2*time.Hour + 45*time.Minute},
+		{"a second before the change at 06:00", at(time.June, 11, 5, 59, 59), 45 * time.Minute, time.Second},
+		{"exactly 06:00", at(time.June, 11, 6, 0, 0), 10 * time.Minute, 17 * time.Hour},
+		// The night the clocks go forward is an hour shorter
+		{"night clocks go forward", at(time.March, 7, 23, 0, 0), 45 * time.Minute, 6 * time.Hour},
+		{"after clocks go forward", at(time.March, 8, 3, 0, 0), 45 * time.Minute, 3 * time.Hour},
+		// The night they go back is an hour longer
+		{"night clocks go back", at(time.October, 31, 23, 0, 0), 45 * time.Minute, 8 * time.Hour},
+		{"evening clocks went forward", at(time.March, 8, 22, 0, 0), 10 * time.Minute, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval, ok := schedule.IntervalAt(tt.now)
+			if !ok || interval != tt.interval {
+				t.Errorf("IntervalAt(%v) = %v, %v, want %v", tt.now, interval, ok, tt.interval)
+			}
+			if got := schedule.UntilChange(tt.now); got != tt.until {
+				t.Errorf("UntilChange(%v) = %v, want %v", tt.now, got, tt.until)
+			}
+			// The change lands on the boundary's clock time
+			change := tt.now.Add(schedule.UntilChange(tt.now))
+			if clock := change.Format("15:04:05"); clock != "06:00:00" && clock != "23:00:00" {
+				t.Errorf("next change at %v, want 06:00 or 23:00", change)
+			}
+		})
+	}
+}
+
+func TestTimeRangeSkippedBoundary(t *testing.T) {
+	loc := newYork(t)
+	// 02:30 doesn't happen on 8 March 2026, so the range starts when the
+	// clocks jump from 02:00 EST to 03:00 EDT
+	tr, err := ParseTimeRange("02:30-05:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, time.March, 8, 0, 0, 0, 0, loc)
+	if got, want := tr.UntilBoundary(now), 2*time.Hour; got != want {
+		t.Errorf("UntilBoundary(%v) = %v, want %v", now, got, want)
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	tests := []struct {
+		value string
+		clock string
+		want  bool
+	}{
+		{"06:00-23:00", "06:00", true},
+		{"06:00-23:00", "22:59", true},
+		{"06:00-23:00", "23:00", false},
+		{"06:00-23:00", "05:59", false},
+		{"23:00-06:00", "23:00", true},
+		{"23:00-06:00", "00:00", true},
+		{"23:00-06:00", "05:59", true},
+		{"23:00-06:00", "06:00", false},
+		{"23:00-06:00", "12:00", false},
+		{"12:00-24:00", "23:59", true},
+		{"12:00-24:00", "00:00", false},
+		{"00:00-00:00", "13:37", true},
+	}
+	for _, tt := range tests {
+		tr, err := ParseTimeRange(tt.value)
+		if err != nil {
+			t.Fatalf("ParseTimeRange(%q): %v", tt.value, err)
+		}
+		clock, err := time.Parse("15:04", tt.clock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tr.Contains(clock); got != tt.want {
+			t.Errorf("%s contains %s = %v, want %v", tt.value, tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestDirectionScheduleUntilChange(t *testing.T) {
+	loc := newYork(t)
+	schedule, err := ParseDirectionSchedule("00:00-12:00=departures, 12:00-24:00=arrivals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Noon on the day the clocks go back is 13 hours after midnight
+	now := time.Date(2026, time.November, 1, 0, 0, 0, 0, loc)
+	if got, want := schedule.UntilChange(now), 13*time.Hour; got != want {
+		t.Errorf("UntilChange(%v) = %v, want %v", now, got, want)
+	}
+}
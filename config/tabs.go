@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"fids-tui/models"
+)
+
+// TabSpec describes one board tab: an airport and whether it shows departures or arrivals
+type TabSpec struct {
+	AirportCode string
+	Direction   models.Direction
+	Destination string // Set for a route board: departures to this airport only
+	Merged      string // Set for a board interleaving further airports' flights, comma separated, e.g. "DCA"
+}
+
+// ParseTabSpec parses a tab like "JFK", "JFK:dep" or "jfk:arr", or a route
+// like "JFK-ORD". Without a direction the tab shows departures
+func ParseTabSpec(value string) (TabSpec, error) {
+	if strings.Contains(value, "-") {
+		return ParseRoute(value)
+	}
+	code, dir, hasDir := strings.Cut(strings.TrimSpace(value), ":")
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if err := ValidateAirportCode(code); err != nil {
+		return TabSpec{}, fmt.Errorf("invalid tab %q: %w", value, err)
+	}
+
+	spec := TabSpec{AirportCode: code, Direction: models.Departure}
+	if !hasDir {
+		return spec, nil
+	}
+	direction, err := ParseDirection(dir)
+	if err != nil {
+		return TabSpec{}, fmt.Errorf("invalid tab %q: %w", value, err)
+	}
+	spec.Direction = direction
+	return spec, nil
+}
+
+// ParseDirection parses "dep", "departures", "arr" or "arrivals"
+func ParseDirection(value string) (models.Direction, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "dep", "departures":
+		return models.Departure, nil
+	case "arr", "arrivals":
+		return models.Arrival, nil
+	default:
+		return 0, fmt.Errorf("unknown direction %q (expected dep or arr)", value)
+	}
+}
+
+// ParseRoute parses a route like "JFK-ORD" into a board of the departures
+// from the first airport to the second
+func ParseRoute(value string) (TabSpec, error) {
+	origin, destination, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return TabSpec{}, fmt.Errorf("invalid route %q: expected ORIGIN-DESTINATION, e.g. JFK-ORD", value)
+	}
+	origin = strings.ToUpper(strings.TrimSpace(origin))
+	destination = strings.ToUpper(strings.TrimSpace(destination))
+	for _, code := range []string{origin, destination} {
+		if err := ValidateAirportCode(code); err != nil {
+			return TabSpec{}, fmt.Errorf("invalid route %q: %w", value, err)
+		}
+	}
+	if origin == destination {
+		return TabSpec{}, fmt.Errorf("invalid route %q: origin and destination are the same airport", value)
+	}
+	return TabSpec{AirportCode: origin, Direction: models.Departure, Destination: destination}, nil
+}
+
+// ParseTabs parses a comma separated list of tabs like "JFK:dep,JFK:arr,EWR:dep"
+func ParseTabs(value string) ([]TabSpec, error) {
+	var tabs []TabSpec
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		spec, err := ParseTabSpec(entry)
+		if err != nil {
+			return nil, err
+		}
+		tabs = append(tabs, spec)
+	}
+	return tabs, nil
+}
+
+// ParseAirports parses a comma separated list of at least two different
+// airports to compare, like "BWI,DCA"
+func ParseAirports(value string) ([]string, error) {
+	var codes []string
+	for _, entry := range strings.Split(value, ",") {
+		code := strings.ToUpper(strings.TrimSpace(entry))
+		if code == "" {
+			continue
+		}
+		if err := ValidateAirportCode(code); err != nil {
+			return nil, err
+		}
+		for _, seen := range codes {
+			if seen == code {
+				return nil, fmt.Errorf("airport %s is listed twice", code)
+			}
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) < 2 {
+		return nil, fmt.Errorf("expected at least two airports, e.g. BWI,DCA, got %q", value)
+	}
+	return codes, nil
+}
+
+// ParseDestinations parses the comma separated destinations of the shuttle
+// view, like "BOS,DCA,ORD", keeping their order
+func ParseDestinations(value string) ([]string, error) {
+	var codes []string
+	for _, entry := range strings.Split(value, ",") {
+		code := strings.ToUpper(strings.TrimSpace(entry))
+		if code == "" {
+			continue
+		}
+		if err := ValidateAirportCode(code); err != nil {
+			return nil, err
+		}
+		for _, seen := range codes {
+			if seen == code {
+				return nil, fmt.Errorf("destination %s is listed twice", code)
+			}
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// ParseWatchList parses the comma separated flights and destinations of the
+// watch sidebar, like "UA123,DL 45,LAX", keeping their order. Entries with a
+// digit are flight numbers, returned without spaces; the others are airport
+// codes
+func ParseWatchList(value string) (flights, places []string, err error) {
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(value, ",") {
+		term := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(entry), " ", ""))
+		if term == "" {
+			continue
+		}
+		if seen[term] {
+			return nil, nil, fmt.Errorf("%s is listed twice", term)
+		}
+		seen[term] = true
+		if !strings.ContainsAny(term, "0123456789") {
+			if err := ValidateAirportCode(term); err != nil {
+				return nil, nil, err
+			}
+			places = append(places, term)
+			continue
+		}
+		valid := len(term) >= 3 && len(term) <= 8
+		for _, r := range term {
+			valid = valid && (r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+		}
+		if !valid {
+			return nil, nil, fmt.Errorf("invalid flight number %q (expected an airline code and number, e.g. UA123)", entry)
+		}
+		flights = append(flights, term)
+	}
+	return flights, places, nil
+}
+
+// Airports returns the airports whose flights the board shows: its airport,
+// then any merged with it
+func (t TabSpec) Airports() []string {
+	codes := []string{t.AirportCode}
+	if t.Merged != "" {
+		codes = append(codes, strings.Split(t.Merged, ",")...)
+	}
+	return codes
+}
+
+// ValidateAirportCode checks that code is an uppercase airport code: 3 or 4
+// letters or digits, as IATA codes such as JFK and ICAO codes such as KJFK
+// are
+func ValidateAirportCode(code string) error {
+	if len(code) < 3 || len(code) > 4 {
+		return fmt.Errorf("airport code must be 3 or 4 characters (e.g., JFK, KLAX), got %q", code)
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return fmt.Errorf("airport code must contain only letters and digits (e.g., JFK, KLAX), got %q", code)
+		}
+	}
+	return nil
+}
+
+// NormalizeAirportCode returns an airport code as typed, pasted or given
+// on the command line in the form boards use: without whitespace, such as
+// the newline of pasted text, and in upper case, checked with
+// ValidateAirportCode. Every way of choosing an airport goes through it
+func NormalizeAirportCode(value string) (string, error) {
+	code := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	if err := ValidateAirportCode(code); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// Label returns the short label shown in the tab bar, e.g. "JFK DEP",
+// "JFK→ORD" or "BWI+DCA ARR"
+func (t TabSpec) Label() string {
+	if t.Destination != "" {
+		return t.AirportCode + "→" + t.Destination
+	}
+	airports := strings.Join(t.Airports(), "+")
+	if t.Direction == models.Arrival {
+		return airports + " ARR"
+	}
+	return airports + " DEP"
+}
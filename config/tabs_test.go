@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"fids-tui/models"
+)
+
+func TestNormalizeAirportCode(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"JFK", "JFK", ""},
+		{"lax\n", "LAX", ""},
+		{" JFK ", "JFK", ""},
+		{"\tk l a x\r\n", "KLAX", ""},
+		{"egll", "EGLL", ""},
+		{"2A4", "2A4", ""},
+		{"", "", "must be 3 or 4 characters"},
+		{"JF", "", "must be 3 or 4 characters"},
+		{"KJFKX", "", "must be 3 or 4 characters"},
+		{"JF-K", "", "only letters and digits"},
+		{"JFK!", "", "only letters and digits"},
+		{"ÅLB", "", "only letters and digits"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeAirportCode(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NormalizeAirportCode(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeAirportCode(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+// TestValidateAirportCodeCase checks that only codes already in upper case
+// are valid, as boards use them
+func TestValidateAirportCodeCase(t *testing.T) {
+	for code, valid := range map[string]bool{"JFK": true, "KJFK": true, "jfk": false, "Kjfk": false, " JFK": false} {
+		if err := ValidateAirportCode(code); (err == nil) != valid {
+			t.Errorf("ValidateAirportCode(%q) = %v, want valid %v", code, err, valid)
+		}
+	}
+}
+
+func TestParseRoute(t *testing.T) {
+	for _, tt := range []struct {
+		value   string
+		want    TabSpec
+		wantErr string
+	}{
+		{"JFK-ORD", TabSpec{AirportCode: "JFK", Direction: models.Departure, Destination: "ORD"}, ""},
+		{" jfk - ord ", TabSpec{AirportCode: "JFK", Direction: models.Departure, Destination: "ORD"}, ""},
+		{"KJFK-EGLL", TabSpec{AirportCode: "KJFK", Direction: models.Departure, Destination: "EGLL"}, ""},
+		{"JFK", TabSpec{}, "expected ORIGIN-DESTINATION"},
+		{"JFK-", TabSpec{}, "must be 3 or 4 characters"},
+		{"-ORD", TabSpec{}, "must be 3 or 4 characters"},
+		{"JFK-ORD-LAX", TabSpec{}, `got "ORD-LAX"`},
+		{"JFK-O!D", TabSpec{}, "only letters and digits"},
+		{"JFK-jfk", TabSpec{}, "origin and destination are the same airport"},
+	} {
+		got, err := ParseRoute(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.HasPrefix(err.Error(), "invalid route") {
+				t.Errorf("ParseRoute(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRoute(%q) = %+v, %v; want %+v", tt.value, got, err, tt.want)
+		}
+	}
+
+	// Tabs with a dash are routes
+	if spec, err := ParseTabSpec("bos-sfo"); err != nil || spec.Destination != "SFO" {
+		t.Errorf("ParseTabSpec(bos-sfo) = %+v, %v; want a route to SFO", spec, err)
+	}
+}
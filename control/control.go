@@ -0,0 +1,239 @@
+// Package control lets scripts drive a running board through a local Unix
+// socket. Each request and response is one line of JSON, e.g.
+//
+//	{"command":"set-airport","args":["LAX"]}
+//	{"ok":true}
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timeout bounds how long the client waits for the board to answer
+const Timeout = 5 * time.Second
+
+// maxRequestSize limits the length of one request line
+const maxRequestSize = 4096
+
+// Commands are the requests the board understands, with their arguments
+var Commands = []string{
+	"status",
+	"set-airport <CODE>",
+	"set-mode <departures|arrivals>",
+	"refresh",
+	"pause",
+	"resume",
+	"quit",
+}
+
+// Request is a command sent to the board
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response is the board's answer to a request
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"` // Answer to "status"
+}
+
+// Status describes what the board is showing
+type Status struct {
+	Airport   string    `json:"airport"`
+	Mode      string    `json:"mode"`                  // "departures" or "arrivals"
+	Route     string    `json:"route,omitempty"`       // Destination of a route board
+	Tab       int       `json:"tab"`                   // Index of the tab shown, from 1
+	Tabs      int       `json:"tabs"`                  // Number of tabs
+	Flights   int       `json:"flights"`               // Flights on the board
+	LastFetch time.Time `json:"last_fetch,omitzero"`   // When the flights were fetched
+	Source    string    `json:"source,omitempty"`      // Data source of the flights
+	Error     string    `json:"error,omitempty"`       // Why the last fetch failed, if it did
+	Paused    bool      `json:"rotation_paused"`       // Page rotation is paused with "pause"
+	Quiet     bool      `json:"quiet_hours,omitempty"` // Updates are paused for quiet hours
+	Settled   bool      `json:"settled"`               // Flights are fetched and applied and the board has stopped animating
+}
+
+// Lines formats the status as "name: value" lines for the ctl command
+func (s *Status) Lines() []string {
+	lines := []string{
+		"airport: " + s.Airport,
+		"mode: " + s.Mode,
+	}
+	if s.Route != "" {
+		lines = append(lines, "route: "+s.Airport+"-"+s.Route)
+	}
+	lines = append(lines, fmt.Sprintf("tab: %d/%d", s.Tab, s.Tabs), "flights: "+strconv.Itoa(s.Flights))
+	if s.LastFetch.IsZero() {
+		lines = append(lines, "last fetch: never")
+	} else {
+		lines = append(lines, fmt.Sprintf("last fetch: %s (%s)", s.LastFetch.Format(time.RFC3339), s.Source))
+	}
+	if s.Error != "" {
+		lines = append(lines, "error: "+s.Error)
+	}
+	lines = append(lines, "rotation paused: "+strconv.FormatBool(s.Paused))
+	if s.Quiet {
+		lines = append(lines, "quiet hours: true")
+	}
+	lines = append(lines, "settled: "+strconv.FormatBool(s.Settled))
+	return lines
+}
+
+// ParseRequest builds a request from ctl command line arguments, checking
+// the command and its number of arguments
+func ParseRequest(args []string) (Request, error) {
+	if len(args) == 0 {
+		return Request{}, fmt.Errorf("missing command (expected one of %s)", strings.Join(Commands, ", "))
+	}
+	req := Request{Command: strings.ToLower(args[0]), Args: args[1:]}
+	for _, usage := range Commands {
+		name, params, _ := strings.Cut(usage, " ")
+		if name != req.Command {
+			continue
+		}
+		if want := len(strings.Fields(params)); len(req.Args) != want {
+			return Request{}, fmt.Errorf("usage: %s", usage)
+		}
+		return req, nil
+	}
+	return Request{}, fmt.Errorf("unknown command %q (expected one of %s)", args[0], strings.Join(Commands, ", "))
+}
+
+// Handler answers a request. It is called from the connection's goroutine
+type Handler func(Request) Response
+
+// Server accepts requests on a Unix socket until closed
+type Server struct {
+	listener net.Listener
+	handle   Handler
+	wg       sync.WaitGroup
+}
+
+// Listen creates the socket at path, readable only by the current user, and
+// starts answering requests with handle. A socket left behind by a board that
+// didn't shut down cleanly is replaced, but one still in use is an error
+func Listen(path string, handle Handler) (*Server, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s is in use by another board", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket: %w", err)
+	}
+
+	s := &Server{listener: listener, handle: handle}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close stops accepting requests and removes the socket
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// serve accepts connections until the listener is closed
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("control socket stopped", "error", err)
+			}
+			return
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers each request line on conn until the client hangs up
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, maxRequestSize), maxRequestSize)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else {
+			slog.Info("control request", "command", req.Command, "args", req.Args)
+			resp = s.handle(req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Send sends one request to the board listening at path and returns its answer
+func Send(path string, req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", path, Timeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("no board is listening on %s: %w", path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(Timeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, nil
+}
+
+// SocketPath returns the socket named by a CONTROL_SOCKET value: none for an
+// empty value or "off", DefaultSocket for "on", or else the value as a path
+func SocketPath(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "off", "false", "0":
+		return ""
+	case "on", "true", "1":
+		return DefaultSocket()
+	default:
+		return value
+	}
+}
+
+// DefaultSocket returns the socket path used when CONTROL_SOCKET is "on":
+// fids-tui.sock in $XDG_RUNTIME_DIR, or in the temporary directory if that
+// is unset
+func DefaultSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		return filepath.Join(os.TempDir(), fmt.Sprintf("fids-tui-%d.sock", os.Getuid()))
+	}
+	return filepath.Join(dir, "fids-tui.sock")
+}
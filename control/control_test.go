@@ -0,0 +1,138 @@
+package control
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    Request
+		wantErr string
+	}{
+		{args: []string{"status"}, want: Request{Command: "status", Args: []string{}}},
+		{args: []string{"SET-AIRPORT", "lax"}, want: Request{Command: "set-airport", Args: []string{"lax"}}},
+		{args: []string{"set-mode", "arrivals"}, want: Request{Command: "set-mode", Args: []string{"arrivals"}}},
+		{args: nil, wantErr: "missing command"},
+		{args: []string{"set-airport"}, wantErr: "usage: set-airport <CODE>"},
+		{args: []string{"refresh", "now"}, wantErr: "usage: refresh"},
+		{args: []string{"fly"}, wantErr: `unknown command "fly"`},
+	}
+	for _, tt := range tests {
+		got, err := ParseRequest(tt.args)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseRequest(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRequest(%q) = %+v, %v, want %+v", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+// echo answers each request with its command as the error, so tests can see
+// which request was handled
+func echo(req Request) Response {
+	return Response{OK: true, Error: req.Command + " " + strings.Join(req.Args, " ")}
+}
+
+func TestListenAndSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "fids.sock")
+	server, err := Listen(path, echo)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	resp, err := Send(path, Request{Command: "set-airport", Args: []string{"LAX"}})
+	if err != nil || !resp.OK || resp.Error != "set-airport LAX" {
+		t.Errorf("Send = %+v, %v, want set-airport LAX handled", resp, err)
+	}
+
+	// A second board can't take over the socket
+	if _, err := Listen(path, echo); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("Listen on a socket in use = %v, want an error", err)
+	}
+
+	if err := server.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if _, err := Send(path, Request{Command: "status"}); err == nil || !strings.Contains(err.Error(), "no board is listening") {
+		t.Errorf("Send after Close = %v, want no board listening", err)
+	}
+}
+
+// TestListenReplacesStaleSocket checks that a socket file nothing listens on,
+// as left by a board that was killed, is replaced
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fids.sock")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server, err := Listen(path, echo)
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	defer server.Close()
+	if resp, err := Send(path, Request{Command: "status"}); err != nil || !resp.OK {
+		t.Errorf("Send = %+v, %v, want the request handled", resp, err)
+	}
+}
+
+// TestInvalidRequest checks that a line that isn't a request is answered with
+// an error, and later requests on the connection are still handled
+func TestInvalidRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fids.sock")
+	server, err := Listen(path, echo)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer server.Close()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("status please\n{\"command\":\"pause\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(conn)
+	var lines []string
+	for len(lines) < 2 && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 2 {
+		t.Fatalf("got %d answers, want 2: %v", len(lines), scanner.Err())
+	}
+	if !strings.Contains(lines[0], `"ok":false`) || !strings.Contains(lines[0], "invalid request") {
+		t.Errorf("answer to an invalid line = %s, want an invalid request error", lines[0])
+	}
+	if !strings.Contains(lines[1], `"ok":true`) || !strings.Contains(lines[1], "pause") {
+		t.Errorf("answer to the next request = %s, want it handled", lines[1])
+	}
+}
+
+func TestSocketPath(t *testing.T) {
+	for value, want := range map[string]string{
+		"":            "",
+		"off":         "",
+		"0":           "",
+		"ON":          DefaultSocket(),
+		"/tmp/x.sock": "/tmp/x.sock",
+	} {
+		if got := SocketPath(value); got != want {
+			t.Errorf("SocketPath(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"fids-tui/config"
+	"fids-tui/control"
+)
+
+// runCtl sends one command to a running board through its control socket,
+// e.g. "fids-tui ctl set-airport LAX", and returns the exit code
+func runCtl(args []string) int {
+	flags := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	socket := flags.String("socket", "", "Control socket of the board (overrides CONTROL_SOCKET)")
+	asJSON := flags.Bool("json", false, "Print the board's answer as JSON")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: fids-tui ctl [flags] <command>\n\nCommands:\n  %s\n\nFlags:\n", strings.Join(control.Commands, "\n  "))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	req, err := control.ParseRequest(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	// Without a socket the board's own setting is used, falling back to the
+	// default path so "ctl" works when CONTROL_SOCKET is "on"
+	path := *socket
+	if path == "" {
+		path = control.SocketPath(config.LoadConfig().ControlSocket)
+	}
+	if path == "" {
+		path = control.DefaultSocket()
+	}
+
+	resp, err := control.Send(path, req)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if *asJSON {
+		out, _ := json.MarshalIndent(resp, "", "  ")
+		fmt.Println(string(out))
+	} else if resp.Status != nil {
+		fmt.Println(strings.Join(resp.Status.Lines(), "\n"))
+	}
+	if !resp.OK {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", resp.Error)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/fids"
+	"fids-tui/models"
+)
+
+// doctorAirport is the airport whose departures are requested to check the
+// API can be reached when no airport is configured
+const doctorAirport = "JFK"
+
+// runDoctor prints the data source settings the board would use, resolved
+// from the environment, the config file and the flags, then requests one
+// departure of airport through the configured transport. It returns the exit
+// code: non-zero if the settings are invalid or the API can't be reached
+func runDoctor(w io.Writer, cfg *config.Config, airport string) int {
+	fmt.Fprintf(w, "Data source: %s\n", cfg.DataSource)
+	if cfg.FallbackSource != "" && cfg.FallbackSource != cfg.DataSource {
+		fmt.Fprintf(w, "Fallback source: %s\n", cfg.FallbackSource)
+	}
+	if cfg.DataSource != "flightaware" && cfg.FallbackSource != "flightaware" {
+		return 0
+	}
+
+	// The client is built the way the board builds it, so the base URL and
+	// timeout shown are the ones its requests use
+	check := *cfg
+	check.DataSource = "flightaware"
+	check.FallbackSource = ""
+	check.ADSBFeedURL = ""
+	check.RecordDir = ""
+	check.ReplayDir = ""
+	provider, err := fids.NewProvider(&check, nil)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return 1
+	}
+	client := provider.(*api.FlightAwareClient)
+	fmt.Fprintf(w, "AeroAPI base URL: %s\n", client.BaseURL)
+	fmt.Fprintf(w, "AeroAPI timeout: %s\n", client.Client.Timeout)
+
+	// NewProvider accepted the same settings, so the transport builds
+	transport, _ := api.NewTransport(api.TransportConfig{
+		CACertFile:         cfg.CACertFile,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	})
+	proxy := "none"
+	if req, err := http.NewRequest("GET", client.BaseURL, nil); err == nil {
+		if proxyURL, err := transport.Proxy(req); err != nil {
+			proxy = "invalid: " + err.Error()
+		} else if proxyURL != nil {
+			proxy = proxyURL.Redacted()
+		}
+	}
+	fmt.Fprintf(w, "Proxy: %s\n", proxy)
+	caBundle := "system roots only"
+	if cfg.CACertFile != "" {
+		caBundle = "system roots and " + cfg.CACertFile
+	}
+	fmt.Fprintf(w, "CA bundle: %s\n", caBundle)
+	if cfg.InsecureSkipVerify {
+		fmt.Fprintf(w, "TLS verification: disabled\n")
+	}
+
+	if airport == "" {
+		airport = cfg.AirportCode
+	}
+	if airport == "" {
+		airport = doctorAirport
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), client.Client.Timeout+5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = api.GetFlights(ctx, provider, models.Departure, airport, api.FetchOptions{Limit: 1})
+	if errors.Is(err, api.ErrNoData) {
+		// An answer without flights still reached the API
+		fmt.Fprintf(w, "Connectivity: OK, but no %s departures: %v\n", airport, err)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(w, "Connectivity: failed requesting %s departures: %v\n", airport, err)
+		return 1
+	}
+	fmt.Fprintf(w, "Connectivity: OK, %s departures in %s\n", airport, time.Since(start).Round(time.Millisecond))
+	return 0
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/config"
+)
+
+func TestDoctor(t *testing.T) {
+	departures, err := os.ReadFile(filepath.Join("api", "testdata", "aeroapi", "departures_bgr.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server stands in for a proxy: requests for the unresolvable base
+	// URL only arrive if they go through it
+	var requested []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Host+r.URL.Path)
+		if r.Header.Get("x-apikey") != "good-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(departures)
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", proxy.URL)
+	t.Setenv("NO_PROXY", "")
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		source   string
+		wantCode int
+		want     []string
+		requests int
+	}{
+		{"reachable", "good-key", "flightaware", 0, []string{
+			"Data source: flightaware",
+			"AeroAPI base URL: http://aeroapi.invalid/aeroapi",
+			"AeroAPI timeout: 5s",
+			"Proxy: " + proxy.URL,
+			"CA bundle: system roots only",
+			"Connectivity: OK",
+		}, 1},
+		{"rejected key", "bad-key", "flightaware", 1, []string{
+			"Proxy: " + proxy.URL,
+			"Connectivity: failed requesting BGR departures: ",
+			"FLIGHTAWARE_API_KEY",
+		}, 1},
+		{"missing key", "", "flightaware", 1, []string{"Error: FLIGHTAWARE_API_KEY is required"}, 0},
+		{"other source", "", "opensky", 0, []string{"Data source: opensky"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			cfg := config.Default()
+			cfg.DataSource = tt.source
+			cfg.APIKey = tt.apiKey
+			cfg.FlightAwareBaseURL = "http://aeroapi.invalid/aeroapi/"
+			cfg.FlightAwareTimeout = 5 * time.Second
+
+			var out strings.Builder
+			if code := runDoctor(&out, cfg, "BGR"); code != tt.wantCode {
+				t.Errorf("exit code %d, want %d", code, tt.wantCode)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output doesn't contain %q:\n%s", want, out.String())
+				}
+			}
+			if len(requested) != tt.requests {
+				t.Errorf("proxy got %d requests, want %d: %v", len(requested), tt.requests, requested)
+			}
+			for _, r := range requested {
+				if r != "aeroapi.invalid/aeroapi/airports/BGR/flights/scheduled_departures" {
+					t.Errorf("proxy got a request for %s", r)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package fids
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/notify"
+	"fids-tui/ui"
+)
+
+// notifyTimeout bounds each push or webhook request
+const notifyTimeout = 10 * time.Second
+
+// alertFilter selects the change events that are sent as alerts
+type alertFilter struct {
+	all        bool
+	cancelled  bool
+	delayed    bool
+	retimed    bool            // Scheduled times moved by the airline
+	gates      bool            // Gate changes for any flight
+	baggage    bool            // Baggage claims assigned to arrivals
+	gatePlaces map[string]bool // Gate changes for flights to (or from) these airports
+	flights    map[string]bool // Every change to these flights, without spaces
+}
+
+// parseAlertFilter parses a NOTIFY_ON value: a comma-separated list of
+// "all", "cancelled", "delayed", "retimed", "gate", "baggage",
+// "gate:<airport>" and "flight:<number>"
+func parseAlertFilter(value string) (*alertFilter, error) {
+	f := &alertFilter{gatePlaces: make(map[string]bool), flights: make(map[string]bool)}
+	for _, term := range strings.Split(value, ",") {
+		term = strings.ToUpper(strings.TrimSpace(term))
+		kind, arg, _ := strings.Cut(term, ":")
+		switch {
+		case term == "":
+		case term == "ALL":
+			f.all = true
+		case term == "CANCELLED":
+			f.cancelled = true
+		case term == "DELAYED":
+			f.delayed = true
+		case term == "RETIMED":
+			f.retimed = true
+		case term == "GATE":
+			f.gates = true
+		case term == "BAGGAGE":
+			f.baggage = true
+		case kind == "GATE" && arg != "":
+			f.gatePlaces[strings.TrimSpace(arg)] = true
+		case kind == "FLIGHT" && arg != "":
+			f.flights[compactFlightNumber(arg)] = true
+		default:
+			return nil, fmt.Errorf("unknown alert filter %q (expected all, cancelled, delayed, retimed, gate, baggage, gate:<airport> or flight:<number>)", term)
+		}
+	}
+	return f, nil
+}
+
+// match reports whether an event should be sent as an alert
+func (f *alertFilter) match(e ui.ChangeEvent) bool {
+	switch {
+	case f.all, f.flights[compactFlightNumber(e.FlightNumber)]:
+		return true
+	case e.Kind == ui.ChangeGate:
+		return f.gates || f.gatePlaces[e.Place]
+	case e.Kind == ui.ChangeEstimate:
+		return f.delayed
+	case e.Kind == ui.ChangeRetimed:
+		return f.retimed
+	case e.Kind == ui.ChangeBaggage:
+		return f.baggage && e.New != ""
+	case e.Kind == ui.ChangeStatus && e.New == models.StatusCancelled.String():
+		return f.cancelled
+	case e.Kind == ui.ChangeStatus && e.New == models.StatusDelayed.String():
+		return f.delayed
+	default:
+		return false
+	}
+}
+
+// compactFlightNumber normalizes a flight number for matching, e.g. "ua 123" to "UA123"
+func compactFlightNumber(number string) string {
+	return strings.ToUpper(strings.ReplaceAll(number, " ", ""))
+}
+
+// alertMessage formats an event as an alert
+func alertMessage(e ui.ChangeEvent) notify.Message {
+	body := e.String()
+	if e.Place != "" {
+		body = fmt.Sprintf("%s (%s)", body, e.Place)
+	}
+	return notify.Message{Title: e.Flight() + " " + e.Description(), Body: body}
+}
+
+// newDispatcher builds the configured alert backends, or returns nil when
+// none are configured
+func newDispatcher(cfg *config.Config) (*notify.Dispatcher, error) {
+	transport, err := api.NewTransport(api.TransportConfig{
+		CACertFile:         cfg.CACertFile,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CA_CERT_FILE: %w", err)
+	}
+	client := &http.Client{Transport: transport, Timeout: notifyTimeout}
+
+	var notifiers []notify.Notifier
+	if cfg.NtfyTopic != "" {
+		if err := api.ValidateBaseURL(cfg.NtfyURL); err != nil {
+			return nil, fmt.Errorf("NTFY_URL: %w", err)
+		}
+		notifiers = append(notifiers, &notify.Ntfy{URL: cfg.NtfyURL, Topic: cfg.NtfyTopic, Client: client})
+	}
+	if cfg.PushoverToken != "" || cfg.PushoverUser != "" {
+		if cfg.PushoverToken == "" || cfg.PushoverUser == "" {
+			return nil, fmt.Errorf("PUSHOVER_TOKEN and PUSHOVER_USER must both be set")
+		}
+		notifiers = append(notifiers, &notify.Pushover{Token: cfg.PushoverToken, User: cfg.PushoverUser, Client: client})
+	}
+	if cfg.NotifyWebhookURL != "" {
+		if err := api.ValidateBaseURL(cfg.NotifyWebhookURL); err != nil {
+			return nil, fmt.Errorf("NOTIFY_WEBHOOK_URL: %w", err)
+		}
+		notifiers = append(notifiers, &notify.Webhook{URL: cfg.NotifyWebhookURL, Client: client})
+	}
+	if cfg.NotifyBell {
+		notifiers = append(notifiers, &notify.Bell{Out: os.Stderr})
+	}
+	if len(notifiers) == 0 {
+		return nil, nil
+	}
+	return notify.NewDispatcher(cfg.NotifyMaxPerHour, notifiers...), nil
+}
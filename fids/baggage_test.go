@@ -0,0 +1,87 @@
+package fids
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestBaggageClaimColumn fetches a JFK arrivals board from AeroAPI fixtures
+// before and after UA 523 is given a belt, checking the belt shows in the BAG
+// column and the change is logged
+func TestBaggageClaimColumn(t *testing.T) {
+	var file atomic.Value
+	file.Store("arrivals_claim_1.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/airports/JFK/flights/scheduled_arrivals" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("..", "api", "testdata", "aeroapi", file.Load().(string)))
+		if err != nil {
+			t.Errorf("fixture: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer server.Close()
+	client := api.NewFlightAwareClient("test-key", api.WithBaseURL(server.URL))
+	client.Window.Now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	m := newTestModel(t, client, WithTabs(config.TabSpec{AirportCode: "JFK", Direction: models.Arrival}))
+	m = update(t, m, tea.WindowSizeMsg{Width: 120, Height: 30})
+	// claim returns the BAG column of flight's row
+	claim := func(m BoardModel, flight string) string {
+		t.Helper()
+		lines := strings.Split(ansi.Strip(m.View()), "\n")
+		column := -1
+		for _, line := range lines {
+			if column < 0 {
+				column = strings.Index(line, "BAG")
+				continue
+			}
+			if strings.Contains(line, flight) {
+				return strings.TrimSpace(ansi.Cut(line, column, column+4))
+			}
+		}
+		t.Fatalf("no BAG column with %s:\n%s", flight, strings.Join(lines, "\n"))
+		return ""
+	}
+	if got := claim(m, "UA 523"); got != "" {
+		t.Errorf("UA 523 claim %q before a belt is assigned", got)
+	}
+	if got := claim(m, "AA 100"); got != "5" {
+		t.Errorf("AA 100 claim %q, want 5", got)
+	}
+
+	file.Store("arrivals_claim_2.json")
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	m = settleModel(t, m)
+	if got := claim(m, "UA 523"); got != "7" {
+		t.Errorf("UA 523 claim %q once assigned, want 7", got)
+	}
+	var logged []string
+	for _, event := range m.events.list(time.Now()) {
+		if event.Kind == ui.ChangeBaggage {
+			logged = append(logged, event.FlightNumber+" "+event.Description())
+		}
+	}
+	if len(logged) != 1 || !strings.HasPrefix(logged[0], "UA 523 ") || !strings.Contains(logged[0], "7") {
+		t.Errorf("baggage changes logged %q, want UA 523 given belt 7", logged)
+	}
+}
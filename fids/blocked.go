@@ -0,0 +1,58 @@
+package fids
+
+import (
+	"fmt"
+	"strings"
+
+	"fids-tui/models"
+)
+
+// blockedMode is where flights blocked from public tracking by their
+// operator are shown, as set by SHOW_BLOCKED
+type blockedMode int
+
+const (
+	blockedHidden blockedMode = iota // Left off the board
+	blockedBoard                     // Shown on the board without their identity, but not sent anywhere
+	blockedAll                       // Also sent with alerts and over MQTT
+)
+
+// parseBlockedMode parses a SHOW_BLOCKED config value (hide, board or all)
+func parseBlockedMode(value string) (blockedMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "hide", "false":
+		return blockedHidden, nil
+	case "board", "true":
+		return blockedBoard, nil
+	case "all":
+		return blockedAll, nil
+	default:
+		return blockedHidden, fmt.Errorf("unknown value %q (expected hide, board or all)", value)
+	}
+}
+
+// hideBlocked drops flights blocked from public tracking, copying the kept
+// ones like hideNoRoute
+func hideBlocked(flights []models.Flight) []models.Flight {
+	kept := make([]models.Flight, 0, len(flights))
+	for _, flight := range flights {
+		if !flight.Blocked {
+			kept = append(kept, flight)
+		}
+	}
+	return kept
+}
+
+// shared reports whether a flight, blocked or not, may be sent beyond the
+// board: blocked flights are only with SHOW_BLOCKED=all
+func (m BoardModel) shared(blocked bool) bool {
+	return !blocked || m.blocked == blockedAll
+}
+
+// sharedFlights returns the flights that may be sent beyond the board
+func (m BoardModel) sharedFlights(flights []models.Flight) []models.Flight {
+	if m.blocked == blockedAll {
+		return flights
+	}
+	return hideBlocked(append([]models.Flight(nil), flights...))
+}
@@ -0,0 +1,89 @@
+package fids
+
+import (
+	"fmt"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/ui"
+)
+
+// boardCacheSize caps the number of boards kept for reuse
+const boardCacheSize = 8
+
+// boardCache keeps recently shown boards so that switching back to an airport
+// shows its rows instantly instead of refetching and animating from scratch
+type boardCache struct {
+	ttl     time.Duration
+	entries map[config.TabSpec]cachedBoard
+}
+
+// cachedBoard is a board stored in the cache
+type cachedBoard struct {
+	board    *ui.Board
+	key      string // Data source and filters the board was fetched with
+	storedAt time.Time
+}
+
+// newBoardCache creates a cache keeping boards for ttl; a zero ttl disables it
+func newBoardCache(ttl time.Duration) *boardCache {
+	return &boardCache{
+		ttl:     ttl,
+		entries: make(map[config.TabSpec]cachedBoard),
+	}
+}
+
+// put stores the board shown for spec, evicting the least recently stored
+// entry when full. Taking a board removes it, so this is the least recently
+// used one
+func (c *boardCache) put(spec config.TabSpec, board *ui.Board, key string, now time.Time) {
+	if c.ttl <= 0 || board == nil || board.FlightCount() == 0 {
+		return
+	}
+	c.prune(now)
+	if _, ok := c.entries[spec]; !ok && len(c.entries) >= boardCacheSize {
+		var oldest config.TabSpec
+		var oldestAt time.Time
+		for s, entry := range c.entries {
+			if oldestAt.IsZero() || entry.storedAt.Before(oldestAt) {
+				oldest, oldestAt = s, entry.storedAt
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[spec] = cachedBoard{board: board, key: key, storedAt: now}
+}
+
+// take removes and returns the board cached for spec if it is still fresh and
+// was fetched with the same data source and filters
+func (c *boardCache) take(spec config.TabSpec, key string, now time.Time) (*ui.Board, bool) {
+	entry, ok := c.entries[spec]
+	if !ok {
+		return nil, false
+	}
+	delete(c.entries, spec)
+	if entry.key != key || now.Sub(entry.storedAt) > c.ttl {
+		return nil, false
+	}
+	return entry.board, true
+}
+
+// len returns the number of boards cached
+func (c *boardCache) len() int {
+	return len(c.entries)
+}
+
+// prune drops entries older than the ttl
+func (c *boardCache) prune(now time.Time) {
+	for spec, entry := range c.entries {
+		if now.Sub(entry.storedAt) > c.ttl {
+			delete(c.entries, spec)
+		}
+	}
+}
+
+// cacheKey identifies the data source and filters boards are fetched with, so
+// cached boards are not reused once either changes
+func (m BoardModel) cacheKey() string {
+	return fmt.Sprintf("%s|%dh|%t|%s", m.provider.Name(), m.lookahead, m.cfg.HideNoDestination, m.cfg.RulesFile)
+}
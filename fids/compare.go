@@ -0,0 +1,246 @@
+package fids
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Ways of comparing nearby airports given in AIRPORTS_LAYOUT
+const (
+	compareSideBySide  = "sidebyside"  // A board per airport, next to each other
+	compareInterleaved = "interleaved" // One board with the flights of every airport
+)
+
+// paneGap is the number of columns between side by side boards
+const paneGap = 1
+
+// compareSpecs returns the boards comparing the airports of AIRPORTS: one
+// per airport shown side by side, or a single board interleaving their
+// flights, and whether the boards are side by side
+func compareSpecs(airports, layout string) ([]config.TabSpec, bool, error) {
+	codes, err := config.ParseAirports(airports)
+	if err != nil {
+		return nil, false, fmt.Errorf("AIRPORTS: %w", err)
+	}
+	switch layout {
+	case "", compareSideBySide, "side-by-side":
+		specs := make([]config.TabSpec, len(codes))
+		for i, code := range codes {
+			specs[i] = config.TabSpec{AirportCode: code, Direction: models.Departure}
+		}
+		return specs, true, nil
+	case compareInterleaved:
+		spec := config.TabSpec{AirportCode: codes[0], Direction: models.Departure, Merged: strings.Join(codes[1:], ",")}
+		return []config.TabSpec{spec}, false, nil
+	default:
+		return nil, false, fmt.Errorf("AIRPORTS_LAYOUT: unknown layout %q (expected sidebyside or interleaved)", layout)
+	}
+}
+
+// fetchAirports fetches the flights of every airport of an interleaved board
+// at once and merges them, each tagged with the airport it was fetched for.
+// An airport whose fetch fails is left out and reported in failed, so the
+// others still update; err is set only if every airport failed
+func fetchAirports(ctx context.Context, provider api.FlightDataProvider, spec config.TabSpec, opts api.FetchOptions) (result api.FetchResult, failed map[string]error, err error) {
+	codes := spec.Airports()
+	results := make([]api.FetchResult, len(codes))
+	errs := make([]error, len(codes))
+	var wg sync.WaitGroup
+	for i, code := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = api.GetFlights(ctx, provider, spec.Direction, code, opts)
+		}()
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if errs[i] != nil {
+			if failed == nil {
+				failed = make(map[string]error)
+			}
+			failed[code] = errs[i]
+			continue
+		}
+		part := results[i]
+		result.Flights = append(result.Flights, tagAirport(part.Flights, code, spec.Direction)...)
+		result.Pages += part.Pages
+		result.Total += part.Total
+		result.Simulated = result.Simulated || part.Simulated
+		result.Skipped.Merge(part.Skipped)
+		if result.Source == "" {
+			result.Source = part.Source
+		}
+		if part.WindowLimit > 0 && (result.WindowLimit == 0 || part.WindowLimit < result.WindowLimit) {
+			result.WindowLimit = part.WindowLimit
+		}
+	}
+	if len(failed) == len(codes) {
+		return api.FetchResult{}, nil, fmt.Errorf("%s: %w", codes[0], errs[0])
+	}
+	sort.SliceStable(result.Flights, func(i, j int) bool {
+		return result.Flights[i].ScheduledTime().Before(result.Flights[j].ScheduledTime())
+	})
+	return result, failed, nil
+}
+
+// tagAirport records in flights the airport they were fetched for, as the
+// origin of departures or the destination of arrivals, which is where an
+// interleaved board looks for it
+func tagAirport(flights []models.Flight, code string, direction models.Direction) []models.Flight {
+	for i := range flights {
+		if direction == models.Arrival {
+			flights[i].DestinationCode = code
+		} else {
+			flights[i].OriginCode = code
+		}
+	}
+	return flights
+}
+
+// withFailedAirports returns the flights of an interleaved board's fetch,
+// adding the flights last fetched for any airport that failed this time so
+// one airport's outage doesn't empty its part of the board. The flights of
+// the airports that succeeded are kept for the next failure
+func (t *tab) withFailedAirports(msg FlightsMsg) []models.Flight {
+	codes := t.spec.Airports()
+	if len(codes) < 2 {
+		return msg.Flights
+	}
+	fetched := make(map[string][]models.Flight, len(codes))
+	for _, flight := range msg.Flights {
+		code := ui.FlightAirport(&flight)
+		fetched[code] = append(fetched[code], flight)
+	}
+	if t.airportFlights == nil {
+		t.airportFlights = make(map[string][]models.Flight, len(codes))
+	}
+	flights := msg.Flights
+	for _, code := range codes {
+		if _, failed := msg.Failed[code]; failed {
+			flights = append(flights, t.airportFlights[code]...)
+		} else {
+			t.airportFlights[code] = fetched[code]
+		}
+	}
+	return flights
+}
+
+// showAirportErrors notes in the status bar which airports of an
+// interleaved board failed to update, e.g. "DCA: request timed out"
+func (m BoardModel) showAirportErrors(t *tab, failed map[string]error, now time.Time) {
+	if len(failed) == 0 {
+		return
+	}
+	notes := make([]string, 0, len(failed))
+	for _, code := range t.spec.Airports() {
+		if err, ok := failed[code]; ok {
+			notes = append(notes, code+": "+err.Error())
+		}
+	}
+	t.board.Toast = strings.Join(notes, "; ")
+	t.board.ToastUntil = now.Add(toastDuration)
+}
+
+// shown reports whether a tab's board is on screen: every tab is when the
+// boards are side by side, else only the active one
+func (m BoardModel) shown(t *tab) bool {
+	return m.sideBySide || t == m.current()
+}
+
+// boardWidth returns the terminal width each board can use: a share of the
+// terminal when the boards are side by side
+func (m BoardModel) boardWidth() int {
+	if !m.sideBySide || len(m.tabs) < 2 || m.termWidth <= 0 {
+		return m.termWidth
+	}
+	return max(1, (m.termWidth-paneGap*(len(m.tabs)-1))/len(m.tabs))
+}
+
+// paneAt returns the index of the side by side board at column x
+func (m BoardModel) paneAt(x int) int {
+	return min(len(m.tabs)-1, max(0, x/(m.boardWidth()+paneGap)))
+}
+
+// refreshPanes fetches every side by side board at once, so they all show
+// the same moment, and schedules their next fetch together on the first
+// board's tick at the shortest of their intervals
+func (m BoardModel) refreshPanes() tea.Cmd {
+	now := time.Now()
+	var interval time.Duration
+	cmds := make([]tea.Cmd, 0, len(m.tabs)+1)
+	for i, t := range m.tabs {
+		t.tickSeq++
+		cmds = append(cmds, m.startFetch(t, now))
+		if d := m.updateInterval(t); i == 0 || d < interval {
+			interval = d
+		}
+	}
+	for _, t := range m.tabs {
+		t.board.NextUpdate = now.Add(interval)
+	}
+	lead := m.tabs[0]
+	return tea.Batch(append(cmds, tickAPI(lead.id, lead.tickSeq, interval))...)
+}
+
+// renderPanes renders the boards next to each other, each as it would be
+// alone in a terminal of its share of the width
+func (m BoardModel) renderPanes() string {
+	width := m.boardWidth()
+	panes := make([]string, 0, 2*len(m.tabs)-1)
+	for i, t := range m.tabs {
+		if i > 0 {
+			panes = append(panes, strings.Repeat(" ", paneGap))
+		}
+		var pane string
+		switch {
+		case t.loading && t.board.FlightCount() == 0 && m.quietPaused:
+			pane = t.board.PausedBanner()
+		case t.loading && t.board.FlightCount() == 0:
+			pane = loadingText(t, time.Now())
+		default:
+			pane = t.board.Render()
+		}
+		panes = append(panes, lipgloss.NewStyle().Width(width).MaxWidth(width).Render(pane))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, panes...)
+}
+
+// shownBoards returns the boards on screen, which rotate and animate
+func (m BoardModel) shownBoards() []*ui.Board {
+	if !m.sideBySide {
+		return []*ui.Board{m.Board()}
+	}
+	boards := make([]*ui.Board, len(m.tabs))
+	for i, t := range m.tabs {
+		boards[i] = t.board
+	}
+	return boards
+}
+
+// pageDelay returns how long the pages on screen stay up before rotating:
+// the longest any shown board asks for, so side by side boards rotate
+// together without cutting short an important page
+func (m BoardModel) pageDelay() time.Duration {
+	var delay time.Duration
+	for _, board := range m.shownBoards() {
+		delay = max(delay, board.NextPageDelay())
+	}
+	if delay <= 0 {
+		return m.cfg.PageRotationInterval
+	}
+	return delay
+}
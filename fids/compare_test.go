@@ -0,0 +1,73 @@
+package fids
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+)
+
+// TestFetchAirportsRateLimited fetches an interleaved board's airports at
+// once through a rate limited client, checking their requests are spaced
+// out and an airport that fails doesn't hold up the others
+func TestFetchAirportsRateLimited(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("..", "api", "testdata", "aeroapi", "departures_bgr.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "/IAD/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	const interval = 50 * time.Millisecond
+	client := api.NewFlightAwareClient("test-key", api.WithBaseURL(server.URL))
+	client.Window.Now = func() time.Time { return time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC) }
+	client.RateLimit = api.NewRateLimiter(int(time.Minute / interval))
+
+	spec := config.TabSpec{AirportCode: "BWI", Direction: models.Departure, Merged: "DCA,IAD"}
+	result, failed, err := fetchAirports(context.Background(), client, spec, api.FetchOptions{Window: 6 * time.Hour})
+	if err != nil {
+		t.Fatalf("fetchAirports: %v", err)
+	}
+	if len(failed) != 1 || failed["IAD"] == nil {
+		t.Errorf("failed = %v, want IAD alone", failed)
+	}
+	origins := map[string]int{}
+	for _, f := range result.Flights {
+		origins[f.OriginCode]++
+	}
+	if len(origins) != 2 || origins["BWI"] != 6 || origins["DCA"] != 6 {
+		t.Errorf("flights by airport = %v, want 6 each from BWI and DCA", origins)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	if len(times) != 3 {
+		t.Fatalf("%d requests, want 3", len(times))
+	}
+	for i := 1; i < len(times); i++ {
+		// A little slack for the clock the server reads after the limiter's
+		if gap := times[i].Sub(times[i-1]); gap < interval-5*time.Millisecond {
+			t.Errorf("request %d made %s after the one before, want %s apart", i+1, gap, interval)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package fids
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/control"
+	"fids-tui/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ControlMsg carries a request from the control socket into the event loop,
+// which answers it on reply
+type ControlMsg struct {
+	Request control.Request
+	reply   chan control.Response
+}
+
+// ControlHandler answers control socket requests by sending them to the
+// program with send, usually tea.Program.Send, and waiting for the board's
+// answer
+func ControlHandler(send func(tea.Msg)) control.Handler {
+	return func(req control.Request) control.Response {
+		reply := make(chan control.Response, 1)
+		send(ControlMsg{Request: req, reply: reply})
+		select {
+		case resp := <-reply:
+			return resp
+		case <-time.After(control.Timeout):
+			return control.Response{Error: "the board did not answer"}
+		}
+	}
+}
+
+// control carries out a control socket request, returning the answer and
+// the commands it starts
+func (m *BoardModel) control(req control.Request) (control.Response, tea.Cmd) {
+	arg := func() string {
+		if len(req.Args) == 0 {
+			return ""
+		}
+		return req.Args[0]
+	}
+	switch req.Command {
+	case "status":
+		return control.Response{OK: true, Status: m.controlStatus()}, nil
+	case "set-airport":
+		code, err := config.NormalizeAirportCode(arg())
+		if err != nil {
+			return control.Response{Error: err.Error()}, nil
+		}
+		spec := config.TabSpec{AirportCode: code, Direction: m.current().spec.Direction}
+		return control.Response{OK: true}, m.switchBoard(spec)
+	case "set-mode":
+		direction, err := config.ParseDirection(arg())
+		if err != nil {
+			return control.Response{Error: err.Error()}, nil
+		}
+		t := m.current()
+		if t.spec.Destination != "" && direction == models.Arrival {
+			return control.Response{Error: "route boards only show departures"}, nil
+		}
+		spec := t.spec
+		spec.Direction = direction
+		m.overrideDirectionSchedule(t, time.Now())
+		return control.Response{OK: true}, m.switchBoard(spec)
+	case "refresh":
+		return control.Response{OK: true}, m.refreshNow()
+	case "pause", "resume":
+		m.rotationHeld = req.Command == "pause"
+		return control.Response{OK: true}, nil
+	case "quit":
+		return control.Response{OK: true}, tea.Quit
+	default:
+		return control.Response{Error: fmt.Sprintf("unknown command %q", req.Command)}, nil
+	}
+}
+
+// controlStatus describes the active board for the "status" request
+func (m BoardModel) controlStatus() *control.Status {
+	t := m.current()
+	status := &control.Status{
+		Airport:   t.spec.AirportCode,
+		Mode:      strings.ToLower(t.spec.Direction.String()),
+		Route:     t.spec.Destination,
+		Tab:       m.active + 1,
+		Tabs:      len(m.tabs),
+		Flights:   t.board.FlightCount(),
+		LastFetch: t.board.Provenance.FetchedAt,
+		Source:    t.board.Provenance.Source,
+		Paused:    m.rotationHeld,
+		Quiet:     m.quietPaused,
+		Settled:   m.settled(),
+	}
+	if t.err != nil {
+		status.Error = t.err.Error()
+	}
+	return status
+}
@@ -0,0 +1,112 @@
+package fids
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"fids-tui/control"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// controlStep is what the event loop of TestControlSocket did with one
+// message sent to it
+type controlStep struct {
+	msg     tea.Msg
+	cmd     tea.Cmd
+	airport string // Airport of the board shown afterwards
+	tickSeq int    // Tick chain of the board shown afterwards
+}
+
+// TestControlSocket drives a board through a real control socket, checking
+// the answers sent back and the messages the handler sends the program
+func TestControlSocket(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(5, time.Now())})
+
+	// Stands in for the program: each message sent is applied in turn
+	msgs := make(chan tea.Msg)
+	steps := make(chan controlStep, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case msg := <-msgs:
+				model, cmd := m.Update(msg)
+				m = model.(BoardModel)
+				steps <- controlStep{msg: msg, cmd: cmd, airport: m.current().spec.AirportCode, tickSeq: m.current().tickSeq}
+			case <-done:
+				return
+			}
+		}
+	}()
+	send := func(msg tea.Msg) {
+		select {
+		case msgs <- msg:
+		case <-done:
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "fids.sock")
+	server, err := control.Listen(path, ControlHandler(send))
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer server.Close()
+
+	// request sends req through the socket, returning the answer and what
+	// the board did with the message it was sent
+	request := func(req control.Request) (control.Response, controlStep) {
+		t.Helper()
+		resp, err := control.Send(path, req)
+		if err != nil {
+			t.Fatalf("Send %s: %v", req.Command, err)
+		}
+		var step controlStep
+		select {
+		case step = <-steps:
+		case <-time.After(control.Timeout):
+			t.Fatalf("%s sent the board no message", req.Command)
+		}
+		msg, ok := step.msg.(ControlMsg)
+		if !ok || !reflect.DeepEqual(msg.Request, req) {
+			t.Errorf("%s sent the board %#v, want a ControlMsg of the request", req.Command, step.msg)
+		}
+		return resp, step
+	}
+
+	resp, _ := request(control.Request{Command: "status"})
+	if !resp.OK || resp.Status == nil {
+		t.Fatalf("status = %+v, want a status", resp)
+	}
+	if s := resp.Status; s.Airport != "JFK" || s.Mode != "departures" || s.Flights != 5 || s.Source == "" || s.Tabs != 1 {
+		t.Errorf("status = %+v, want 5 JFK departures on 1 tab with their source", s)
+	}
+
+	resp, step := request(control.Request{Command: "set-airport", Args: []string{" lax\n"}})
+	if !resp.OK || step.airport != "LAX" || step.cmd == nil {
+		t.Errorf("set-airport = %+v, showing %s with command %v, want LAX fetched", resp, step.airport, step.cmd != nil)
+	}
+	resp, step = request(control.Request{Command: "set-airport", Args: []string{"L@X"}})
+	if resp.OK || resp.Error == "" || step.airport != "LAX" {
+		t.Errorf("set-airport of an invalid code = %+v, showing %s, want an error and LAX kept", resp, step.airport)
+	}
+
+	before := step.tickSeq
+	resp, step = request(control.Request{Command: "refresh"})
+	if !resp.OK || step.cmd == nil || step.tickSeq == before {
+		t.Errorf("refresh = %+v, tick chain %d after %d, want a new fetch", resp, step.tickSeq, before)
+	}
+
+	resp, step = request(control.Request{Command: "fly"})
+	if resp.OK || resp.Error != `unknown command "fly"` {
+		t.Errorf("unknown command = %+v, want an error", resp)
+	}
+
+	resp, step = request(control.Request{Command: "quit"})
+	if !resp.OK || !quits(step.cmd) {
+		t.Errorf("quit = %+v, quits %v", resp, quits(step.cmd))
+	}
+}
@@ -0,0 +1,19 @@
+package fids
+
+// customProcessors run on every fetch after the built-in filters and the
+// rules file. When building from source, site-specific processing that
+// doesn't belong upstream can be registered here, for example:
+//
+//	var customProcessors = []FlightProcessor{
+//		func(flights []models.Flight) []models.Flight {
+//			for i := range flights {
+//				if strings.HasPrefix(flights[i].Ident, "N") {
+//					flights[i].AirlineCode = "CLUB"
+//				}
+//			}
+//			return flights
+//		},
+//	}
+//
+// Programs embedding the board can pass processors to WithProcessors instead
+var customProcessors = []FlightProcessor{}
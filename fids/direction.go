@@ -0,0 +1,65 @@
+package fids
+
+import (
+	"log/slog"
+	"time"
+
+	"fids-tui/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scheduledDirection returns the direction DIRECTION_SCHEDULE wants the tab
+// to show at now in its airport's timezone, or false if the schedule doesn't
+// apply: it is unset or doesn't cover now, the tab is a route board, or the
+// tab's direction was toggled by hand since the last boundary
+func (m BoardModel) scheduledDirection(t *tab, now time.Time) (models.Direction, bool) {
+	if len(m.directions) == 0 || t.spec.Destination != "" || now.Before(t.directionOverride) {
+		return 0, false
+	}
+	return m.directions.DirectionAt(now.In(t.board.AirportTZ))
+}
+
+// followDirectionSchedule switches the active tab between departures and
+// arrivals when the schedule says so, flipping the whole board to announce
+// the switch. The new board is fetched straight away
+func (m *BoardModel) followDirectionSchedule(now time.Time) tea.Cmd {
+	t := m.current()
+	direction, ok := m.scheduledDirection(t, now)
+	if !ok || direction == t.spec.Direction {
+		return nil
+	}
+	slog.Info("switching board for the direction schedule", "airport", t.spec.AirportCode, "direction", direction)
+	spec := t.spec
+	spec.Direction = direction
+	cmd := m.switchBoard(spec)
+	t.board.Flip()
+	return cmd
+}
+
+// toggleDirection switches the active tab between departures and arrivals.
+// The choice overrides the direction schedule on the tab until its next
+// boundary
+func (m *BoardModel) toggleDirection() tea.Cmd {
+	t := m.current()
+	if t.spec.Destination != "" {
+		return nil // Route boards only show departures
+	}
+	spec := t.spec
+	if spec.Direction == models.Arrival {
+		spec.Direction = models.Departure
+	} else {
+		spec.Direction = models.Arrival
+	}
+	m.overrideDirectionSchedule(t, time.Now())
+	return m.switchBoard(spec)
+}
+
+// overrideDirectionSchedule stops the direction schedule from switching t's
+// board until its next boundary after now. Other tabs still follow it
+func (m *BoardModel) overrideDirectionSchedule(t *tab, now time.Time) {
+	if len(m.directions) == 0 {
+		return
+	}
+	t.directionOverride = now.Add(m.directions.UntilChange(now.In(t.board.AirportTZ)))
+}
@@ -0,0 +1,73 @@
+package fids
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/models"
+)
+
+// TestDirectionSchedule follows a schedule showing arrivals for the two
+// hours around now and departures the rest of the day, on two departures
+// tabs: the API tick switches the shown board, a direction chosen by hand
+// holds on its tab until the next boundary, and the other tab still follows
+// the schedule
+func TestDirectionSchedule(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().In(newYork)
+	from, to := now.Add(-time.Hour).Format("15:04"), now.Add(time.Hour).Format("15:04")
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.DirectionSchedule = fmt.Sprintf("%s-%s=arrivals,%s-%s=departures", from, to, to, from)
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, now)}, WithConfig(cfg), WithTabs(
+		config.TabSpec{AirportCode: "JFK", Direction: models.Departure},
+		config.TabSpec{AirportCode: "BOS", Direction: models.Departure},
+	))
+	tick := func(m BoardModel, at time.Time) (BoardModel, bool) {
+		t.Helper()
+		model, cmd := m.Update(TickAPIMsg{Tab: m.current().id, Time: at, seq: m.current().tickSeq})
+		return model.(BoardModel), cmd != nil
+	}
+	direction := func(m BoardModel) models.Direction { return m.current().spec.Direction }
+
+	// The tick in the arrivals hours switches the board and fetches it
+	m, fetched := tick(m, now)
+	if direction(m) != models.Arrival || !fetched {
+		t.Fatalf("board shows %s in the arrivals hours, fetched %v", direction(m), fetched)
+	}
+	if m.Board().Direction != models.Arrival {
+		t.Errorf("board switched to arrivals shows %s", m.Board().Direction)
+	}
+
+	// Toggled back by hand, the tab keeps departures until the boundary
+	m = press(t, m, "d")
+	if direction(m) != models.Departure {
+		t.Fatalf("toggled board shows %s, want departures", direction(m))
+	}
+	if m, _ = tick(m, now.Add(30*time.Minute)); direction(m) != models.Departure {
+		t.Errorf("schedule switched a board toggled by hand to %s before its boundary", direction(m))
+	}
+	if want := now.Add(time.Hour).Truncate(time.Minute); !m.current().directionOverride.Truncate(time.Minute).Equal(want) {
+		t.Errorf("toggle overrides the schedule until %s, want the boundary at %s", m.current().directionOverride, want)
+	}
+
+	// The other tab isn't overridden
+	m = press(t, m, "tab")
+	if m, _ = tick(m, now.Add(30*time.Minute)); direction(m) != models.Arrival {
+		t.Errorf("second tab shows %s in the arrivals hours, want arrivals", direction(m))
+	}
+
+	// Back on the first tab, the override lapses with the boundary: the next
+	// arrivals hours switch it again
+	m = press(t, m, "tab")
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, now.Hour(), now.Minute(), 0, 0, newYork)
+	if m, _ = tick(m, tomorrow); direction(m) != models.Arrival {
+		t.Errorf("first tab shows %s in the next arrivals hours, want arrivals", direction(m))
+	}
+}
@@ -0,0 +1,15 @@
+// Package fids provides the departures board as an embeddable bubbletea model
+//
+// The model fetches flights from a data provider on the configured schedule,
+// rotates pages and animates the split-flap characters. It can be run as its
+// own program:
+//
+//	board, err := fids.New(fids.WithAirport("JFK"), fids.WithProvider(provider))
+//	if err != nil {
+//		return err
+//	}
+//	_, err = tea.NewProgram(board, tea.WithAltScreen()).Run()
+//
+// or embedded in a larger application by forwarding messages to its Update
+// method and placing its View in the parent's layout.
+package fids
@@ -0,0 +1,62 @@
+package fids
+
+import (
+	"log/slog"
+	"time"
+
+	"fids-tui/ui"
+)
+
+// eventLog is a bounded ring buffer of flight change events
+type eventLog struct {
+	events    []ui.ChangeEvent // Ring storage, oldest at start
+	start     int
+	count     int
+	retention time.Duration // Events older than this are dropped; zero keeps them
+}
+
+// newEventLog creates a log holding at most size events
+func newEventLog(size int, retention time.Duration) *eventLog {
+	if size < 0 {
+		size = 0
+	}
+	return &eventLog{events: make([]ui.ChangeEvent, size), retention: retention}
+}
+
+// add appends events, overwriting the oldest once the log is full, and
+// mirrors them to the diagnostic log
+func (l *eventLog) add(events ...ui.ChangeEvent) {
+	for _, e := range events {
+		slog.Info("flight changed", "airport", e.AirportCode, "flight", e.Flight(), "change", e.Description())
+		if len(l.events) == 0 {
+			continue
+		}
+		l.events[(l.start+l.count)%len(l.events)] = e
+		if l.count < len(l.events) {
+			l.count++
+		} else {
+			l.start = (l.start + 1) % len(l.events)
+		}
+	}
+}
+
+// prune drops events past the retention period from the front, clearing
+// their slots so the strings they hold can be freed
+func (l *eventLog) prune(now time.Time) {
+	for l.retention > 0 && l.count > 0 && now.Sub(l.events[l.start].Time) > l.retention {
+		l.events[l.start] = ui.ChangeEvent{}
+		l.start = (l.start + 1) % len(l.events)
+		l.count--
+	}
+}
+
+// list returns the retained events, oldest first
+func (l *eventLog) list(now time.Time) []ui.ChangeEvent {
+	l.prune(now)
+
+	result := make([]ui.ChangeEvent, 0, l.count)
+	for i := 0; i < l.count; i++ {
+		result = append(result, l.events[(l.start+i)%len(l.events)])
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package fids
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fids-tui/payload"
+)
+
+// exportColumns are the header of a CSV export, one column per field of a
+// flight followed by where the flights came from
+var exportColumns = []string{
+	"airport", "direction", "flight_number", "airline_code", "status", "remarks",
+	"origin_code", "origin_city", "destination_code", "destination_city", "gate", "baggage_claim",
+	"scheduled", "estimated", "source", "fetched_at", "simulated",
+}
+
+// Export fetches the active board's flights a single time and returns them
+// for scripts, as JSON in the form of the MQTT flights message or as CSV with
+// a row per flight. Both name the source and fetch time of the flights and
+// whether they are simulated. Nothing is published or alerted on
+func (m BoardModel) Export(format string) (string, error) {
+	if format != "json" && format != "csv" {
+		return "", fmt.Errorf("unknown export format %q (expected json or csv)", format)
+	}
+	t := m.current()
+	msg := fetchFlights(m.provider, t.id, t.spec, m.lookahead, m.cfg.MaxPages)().(FlightsMsg)
+	m.recordSpend()
+	if msg.Err != nil {
+		return "", msg.Err
+	}
+	t.board.UpdateFlights(m.pipeline.apply(t.withFailedAirports(msg)))
+	t.board.Provenance = msg.Source
+
+	board := m.boardMessage(t)
+	if format == "json" {
+		out, err := json.MarshalIndent(board, "", "  ")
+		return string(out), err
+	}
+	return exportCSV(board)
+}
+
+// exportCSV formats the flights of board as CSV with a header row
+func exportCSV(board payload.Board) (string, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	w.Write(exportColumns)
+	for _, f := range board.Flights {
+		scheduled, estimated := f.ScheduledDeparture, f.EstimatedDeparture
+		if f.Direction == "arrival" {
+			scheduled, estimated = f.ScheduledArrival, f.EstimatedArrival
+		}
+		w.Write([]string{
+			board.Airport, f.Direction, f.FlightNumber, f.AirlineCode, f.Status, f.Remarks,
+			f.OriginCode, f.OriginCity, f.DestinationCode, f.DestinationCity, f.Gate, f.BaggageClaim,
+			formatExportTime(scheduled), formatExportTime(estimated),
+			board.Source, formatExportTime(&board.FetchedAt), strconv.FormatBool(board.Simulated),
+		})
+	}
+	w.Flush()
+	return out.String(), w.Error()
+}
+
+// formatExportTime formats t in RFC 3339, or as an empty cell if it is unset
+func formatExportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
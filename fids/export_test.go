@@ -0,0 +1,83 @@
+package fids
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/payload"
+)
+
+func TestExportJSON(t *testing.T) {
+	m := newTestModel(t, api.NewDemoProvider())
+	out, err := m.Export("json")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	var board payload.Board
+	if err := json.Unmarshal([]byte(out), &board); err != nil {
+		t.Fatalf("export isn't JSON: %v\n%s", err, out)
+	}
+	if board.Airport != "JFK" || board.Source != "Demo" || !board.Simulated || board.FetchedAt.IsZero() {
+		t.Errorf("export is for %s from %q at %v, simulated %v, want JFK from simulated Demo data", board.Airport, board.Source, board.FetchedAt, board.Simulated)
+	}
+	if len(board.Flights) == 0 {
+		t.Error("export has no flights")
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	now := time.Now()
+	flights := modelFlights(3, now)
+	estimate := flights[1].ScheduledDeparture.Add(25 * time.Minute)
+	flights[1].EstimatedDeparture = &estimate
+	m := newTestModel(t, &fakeProvider{flights: flights})
+	out, err := m.Export("csv")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("export isn't CSV: %v\n%s", err, out)
+	}
+	if len(rows) != 4 || strings.Join(rows[0], ",") != strings.Join(exportColumns, ",") {
+		t.Fatalf("export has %d rows, header %v, want a header and 3 flights", len(rows), rows[0])
+	}
+	cell := func(row int, column string) string {
+		for i, name := range exportColumns {
+			if name == column {
+				return rows[row][i]
+			}
+		}
+		t.Fatalf("no column %s", column)
+		return ""
+	}
+	for i := range flights {
+		row := i + 1
+		if got := cell(row, "flight_number"); got != flights[i].FlightNumber {
+			t.Errorf("row %d is %s, want %s", row, got, flights[i].FlightNumber)
+		}
+		if got, want := cell(row, "scheduled"), flights[i].ScheduledDeparture.Format(time.RFC3339); got != want {
+			t.Errorf("row %d scheduled %s, want %s", row, got, want)
+		}
+		if cell(row, "source") != "Fake" || cell(row, "simulated") != "false" || cell(row, "fetched_at") == "" {
+			t.Errorf("row %d provenance %s at %s, simulated %s, want live Fake data", row, cell(row, "source"), cell(row, "fetched_at"), cell(row, "simulated"))
+		}
+	}
+	if got, want := cell(2, "estimated"), estimate.Format(time.RFC3339); got != want {
+		t.Errorf("delayed flight estimated %q, want %q", got, want)
+	}
+	if got := cell(1, "estimated"); got != "" {
+		t.Errorf("flight on time estimated %q, want an empty cell", got)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(1, time.Now())})
+	if _, err := m.Export("xml"); err == nil || !strings.Contains(err.Error(), "expected json or csv") {
+		t.Errorf("Export(xml) error = %v, want one naming the formats", err)
+	}
+}
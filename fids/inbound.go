@@ -0,0 +1,140 @@
+package fids
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InboundMsg carries the inbound flight looked up for a delayed departure
+type InboundMsg struct {
+	ID     string // Source's ID of the inbound flight
+	Flight models.Flight
+	Err    error
+}
+
+// inboundLookups looks up the aircraft bringing in a delayed departure when
+// it is selected for the detail panel. Each lookup is an API call, so they
+// are limited to a few per refresh, cached, and cancelled when the panel
+// closes before the answer arrives
+type inboundLookups struct {
+	perRefresh  int                   // Lookups allowed per refresh, zero for none
+	left        int                   // Lookups left until the next refresh
+	ttl         time.Duration         // How long a looked up flight is kept before it is looked up again
+	shown       map[string]ui.Inbound // Looked up inbound aircraft by flight ID, shared with every board
+	lookedUp    map[string]time.Time  // When each flight of shown was looked up
+	pending     string                // ID being looked up, empty if none
+	cancel      context.CancelFunc    // Cancels the pending lookup
+	unsupported bool                  // The data source can't look up single flights
+}
+
+// newInboundLookups allows perRefresh lookups per refresh, keeping their
+// results for ttl
+func newInboundLookups(perRefresh int, ttl time.Duration) *inboundLookups {
+	return &inboundLookups{
+		perRefresh: perRefresh,
+		left:       perRefresh,
+		ttl:        ttl,
+		shown:      make(map[string]ui.Inbound),
+		lookedUp:   make(map[string]time.Time),
+	}
+}
+
+// refreshed restores the lookups allowed once the boards have refreshed and
+// drops the inbound flights looked up too long ago
+func (l *inboundLookups) refreshed(now time.Time) {
+	l.left = l.perRefresh
+	for id, at := range l.lookedUp {
+		if now.Sub(at) > l.ttl {
+			delete(l.shown, id)
+			delete(l.lookedUp, id)
+		}
+	}
+}
+
+// wantsInbound returns the ID of the inbound flight of the departure whose
+// detail panel board shows, if it is delayed, or "" if there is none
+func wantsInbound(board *ui.Board) string {
+	if board.Selected == nil || board.Selected.Flight == nil {
+		return ""
+	}
+	flight := board.Selected.Flight
+	if flight.Direction == models.Arrival || flight.Status != models.StatusDelayed {
+		return ""
+	}
+	return flight.InboundID
+}
+
+// followSelection starts looking up the inbound flight of the delayed
+// departure selected on the active board, unless it is cached or the
+// lookups of this refresh are spent, and cancels a lookup whose detail
+// panel has since closed
+func (m BoardModel) followSelection() tea.Cmd {
+	l := m.inbound
+	id := wantsInbound(m.Board())
+	if l.pending != "" && l.pending != id {
+		slog.Debug("inbound lookup cancelled", "id", l.pending)
+		l.cancel()
+		l.pending, l.cancel = "", nil
+	}
+	if id == "" || id == l.pending || l.unsupported || l.perRefresh <= 0 {
+		return nil
+	}
+	if _, ok := l.shown[id]; ok {
+		return nil
+	}
+	if l.left <= 0 {
+		slog.Debug("inbound lookups spent until the next refresh", "id", id, "per_refresh", l.perRefresh)
+		return nil
+	}
+	if !m.budget.Allow(api.CallInbound, 1) {
+		return nil
+	}
+	l.left--
+	ctx, cancel := context.WithCancel(context.Background())
+	l.pending, l.cancel = id, cancel
+	return lookupInbound(ctx, m.provider, id)
+}
+
+// inboundFound stores the inbound flight of msg for the detail panels,
+// unless its lookup was cancelled
+func (m BoardModel) inboundFound(msg InboundMsg) {
+	l := m.inbound
+	if msg.ID != l.pending || errors.Is(msg.Err, context.Canceled) {
+		return
+	}
+	l.cancel()
+	l.pending, l.cancel = "", nil
+	m.recordSpend()
+	switch {
+	case errors.Is(msg.Err, api.ErrNoFlightLookup):
+		slog.Info("inbound aircraft not looked up", "reason", msg.Err)
+		l.unsupported = true
+	case msg.Err != nil:
+		slog.Warn("inbound lookup failed", "id", msg.ID, "error", msg.Err)
+	default:
+		inbound := ui.Inbound{Origin: msg.Flight.OriginCode, Lands: msg.Flight.ScheduledArrival}
+		if inbound.Origin == "" {
+			inbound.Origin = msg.Flight.OriginCity
+		}
+		if msg.Flight.EstimatedArrival != nil {
+			inbound.Lands = *msg.Flight.EstimatedArrival
+		}
+		l.shown[msg.ID] = inbound
+		l.lookedUp[msg.ID] = time.Now()
+	}
+}
+
+func lookupInbound(ctx context.Context, provider api.FlightDataProvider, id string) tea.Cmd {
+	return func() tea.Msg {
+		flight, err := api.LookupFlight(ctx, provider, id)
+		return InboundMsg{ID: id, Flight: flight, Err: err}
+	}
+}
@@ -0,0 +1,154 @@
+package fids
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// inboundProvider serves delayed departures, each with an inbound flight
+// from BOS it looks up, counting the lookups. Lookups of held wait until
+// they are cancelled
+type inboundProvider struct {
+	fakeProvider
+	held    string
+	mu      sync.Mutex
+	lookups map[string]int
+}
+
+func newInboundProvider(n int) *inboundProvider {
+	flights := modelFlights(n, time.Now())
+	for i := range flights {
+		flights[i].Status = models.StatusDelayed
+		flights[i].InboundID = fmt.Sprintf("AAL%d-inbound", 900+i)
+	}
+	return &inboundProvider{fakeProvider: fakeProvider{flights: flights}, lookups: make(map[string]int)}
+}
+
+func (p *inboundProvider) GetFlight(ctx context.Context, id string) (models.Flight, error) {
+	p.mu.Lock()
+	p.lookups[id]++
+	p.mu.Unlock()
+	if id == p.held {
+		<-ctx.Done()
+		return models.Flight{}, ctx.Err()
+	}
+	return models.Flight{ID: id, OriginCode: "BOS", ScheduledArrival: time.Now().Add(time.Hour)}, nil
+}
+
+// total returns the lookups served
+func (p *inboundProvider) total() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, count := range p.lookups {
+		n += count
+	}
+	return n
+}
+
+// selectInbound selects row i of m's board for the detail panel and applies
+// the answer of the inbound lookup it starts, reporting whether it started one
+func selectInbound(t *testing.T, m BoardModel, i int) bool {
+	t.Helper()
+	m.Board().ClearSelection() // Selecting the selected row again would close the panel
+	m.Board().Select(i)
+	cmd := m.followSelection()
+	if cmd == nil {
+		return false
+	}
+	update(t, m, cmd())
+	return true
+}
+
+// TestInboundLookupBudget checks that only INBOUND_LOOKUPS lookups are made
+// per refresh, and that flights looked up are kept until the TTL runs out
+func TestInboundLookupBudget(t *testing.T) {
+	cfg := lookaheadConfig(6)
+	cfg.InboundLookups = 2
+	provider := newInboundProvider(4)
+	m := newTestModel(t, provider, WithConfig(cfg))
+	ids := func(i int) string { return provider.flights[i].InboundID }
+
+	for i, want := range []bool{true, true, false} {
+		if started := selectInbound(t, m, i); started != want {
+			t.Errorf("selecting flight %d looked up its inbound flight %v, want %v", i, started, want)
+		}
+	}
+	if provider.total() != 2 || m.inbound.shown[ids(0)].Origin != "BOS" {
+		t.Fatalf("%d lookups with 2 a refresh, inbound %+v", provider.total(), m.inbound.shown)
+	}
+
+	// A refresh allows more, but those already looked up are kept
+	now := time.Now()
+	m.inbound.refreshed(now)
+	if selectInbound(t, m, 0) {
+		t.Error("cached inbound flight looked up again")
+	}
+	if !selectInbound(t, m, 2) || provider.lookups[ids(2)] != 1 {
+		t.Error("no lookup once a refresh allowed more")
+	}
+
+	// Once the TTL has run out they are looked up again
+	m.inbound.refreshed(now.Add(cfg.UpdateInterval - time.Second))
+	if selectInbound(t, m, 0) {
+		t.Error("inbound flight looked up again within its TTL")
+	}
+	m.inbound.refreshed(now.Add(cfg.UpdateInterval + time.Second))
+	if _, ok := m.inbound.shown[ids(0)]; ok {
+		t.Error("inbound flight kept past its TTL")
+	}
+	if !selectInbound(t, m, 0) || provider.lookups[ids(0)] != 2 {
+		t.Errorf("inbound flight looked up %d times after its TTL, want 2", provider.lookups[ids(0)])
+	}
+}
+
+// TestInboundLookupCancelled checks that a lookup still under way when the
+// selection moves on is cancelled, its answer ignored, and the new
+// selection looked up instead
+func TestInboundLookupCancelled(t *testing.T) {
+	cfg := lookaheadConfig(6)
+	cfg.InboundLookups = 5
+	provider := newInboundProvider(3)
+	provider.held = provider.flights[0].InboundID
+	m := newTestModel(t, provider, WithConfig(cfg))
+
+	m.Board().Select(0)
+	lookup := m.followSelection()
+	if lookup == nil {
+		t.Fatal("selecting a delayed departure looked nothing up")
+	}
+	answer := make(chan InboundMsg)
+	go func() { answer <- lookup().(InboundMsg) }()
+
+	// Selecting another flight cancels the held lookup and starts its own
+	if !selectInbound(t, m, 1) {
+		t.Fatal("no lookup for the new selection")
+	}
+	msg := <-answer
+	if msg.Err != context.Canceled {
+		t.Fatalf("superseded lookup returned %v, want it cancelled", msg.Err)
+	}
+	m = update(t, m, msg)
+	if _, ok := m.inbound.shown[provider.held]; ok || m.inbound.shown[provider.flights[1].InboundID].Origin != "BOS" {
+		t.Errorf("inbound flights %+v after a cancelled lookup", m.inbound.shown)
+	}
+
+	// Closing the panel cancels too, and a late answer is dropped
+	provider.held = provider.flights[2].InboundID
+	m.Board().Select(2)
+	lookup = m.followSelection()
+	go func() { answer <- lookup().(InboundMsg) }()
+	m = press(t, m, "esc")
+	m = update(t, m, <-answer)
+	if m.inbound.pending != "" || len(m.inbound.shown) != 1 {
+		t.Errorf("closing the panel left lookup %q pending, %d inbound flights", m.inbound.pending, len(m.inbound.shown))
+	}
+	if selectInbound(t, m, 1) {
+		t.Error("inbound flight looked up again after a cancelled lookup")
+	}
+}
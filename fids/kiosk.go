@@ -0,0 +1,78 @@
+package fids
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// kioskUnlockDuration is how long typing the unlock sequence restores the keys
+const kioskUnlockDuration = 5 * time.Minute
+
+// kioskLock keeps the keyboard and mouse inert on unattended displays, so a
+// bumped keyboard can't change what the board shows. Typing the unlock
+// sequence restores them for a while
+type kioskLock struct {
+	sequence string    // Keys typed in a row that unlock the board
+	typed    string    // The last keys typed while locked, at most as many as the sequence
+	unlocked bool      // The sequence has been typed and the keys work until until
+	until    time.Time // When the keys lock again
+}
+
+// newKioskLock creates a locked keyboard that unlocks when sequence is typed
+func newKioskLock(sequence string) *kioskLock {
+	return &kioskLock{sequence: sequence}
+}
+
+// locked reports whether input is ignored at now. A nil lock never is
+func (k *kioskLock) locked(now time.Time) bool {
+	return k != nil && (!k.unlocked || !now.Before(k.until))
+}
+
+// key records a key pressed while locked and reports whether it completed
+// the unlock sequence. Keys that aren't characters, like the arrows, start
+// the sequence over
+func (k *kioskLock) key(key string, now time.Time) bool {
+	if utf8.RuneCountInString(key) != 1 {
+		k.typed = ""
+		return false
+	}
+	k.typed += key
+	if n := utf8.RuneCountInString(k.typed) - utf8.RuneCountInString(k.sequence); n > 0 {
+		_, size := utf8.DecodeRuneInString(k.typed)
+		k.typed = k.typed[size:]
+	}
+	if k.sequence == "" || !strings.HasSuffix(k.typed, k.sequence) {
+		return false
+	}
+	k.typed = ""
+	k.unlocked = true
+	k.until = now.Add(kioskUnlockDuration)
+	slog.Info("kiosk unlocked", "until", k.until)
+	return true
+}
+
+// relock locks the keys again once the unlock has run out, reporting
+// whether it just did
+func (k *kioskLock) relock(now time.Time) bool {
+	if k == nil || !k.unlocked || now.Before(k.until) {
+		return false
+	}
+	k.unlocked = false
+	slog.Info("kiosk locked")
+	return true
+}
+
+// lockKiosk puts the board back the way it runs unattended when the keys
+// lock again: overlays closed, page entry ended and the selection cleared,
+// so the pages rotate on schedule
+func (m *BoardModel) lockKiosk() {
+	for m.overlays.Len() > 0 {
+		m.overlays.Pop()
+	}
+	m.stopPageEntry()
+	m.Board().ClearSelection()
+	m.rotationPause = time.Time{}
+	m.fitBoards()
+}
@@ -0,0 +1,55 @@
+package fids
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Bounds of the lookahead window chosen with '+' and '-', in hours
+const (
+	minLookahead = 1
+	maxLookahead = 24
+)
+
+// adjustLookahead grows or shrinks the lookahead window by delta hours,
+// within 1 to 24, remembers it in the state file and refetches the boards
+// shown with the new window. The flights on screen stay until the fetch
+// arrives; the other tabs refetch when they are next shown. Nothing is
+// fetched when the window is already at its bound. A window without an
+// end (LOOKAHEAD_HOURS=0) shrinks to 24 hours
+func (m *BoardModel) adjustLookahead(delta int) tea.Cmd {
+	if m.cfg.OperationalDay != "" {
+		board := m.Board()
+		board.Toast = "the lookahead window is set by OPERATIONAL_DAY"
+		board.ToastUntil = time.Now().Add(toastDuration)
+		return nil
+	}
+	hours := min(maxLookahead, max(minLookahead, m.lookahead+delta))
+	if m.lookahead <= 0 {
+		// Without an end the window can only shrink
+		hours = m.lookahead
+		if delta < 0 {
+			hours = maxLookahead
+		}
+	}
+	if hours == m.lookahead {
+		return nil
+	}
+	m.lookahead = hours
+	for _, t := range m.tabs {
+		t.board.Lookahead = time.Duration(hours) * time.Hour
+		t.fetched = false
+	}
+	if m.cfg.StateFile != "" {
+		err := updateState(m.cfg.StateFile, func(state *persistentState) {
+			state.Lookahead = &savedLookahead{Hours: hours, Configured: m.cfg.LookaheadHours}
+		})
+		if err != nil {
+			slog.Warn("failed to save lookahead window", "error", err)
+		}
+	}
+	slog.Info("lookahead window changed", "hours", hours)
+	return m.refreshNow()
+}
@@ -0,0 +1,128 @@
+package fids
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fids-tui/config"
+)
+
+// lookaheadConfig returns the test configuration fetching hours ahead, with
+// a budget counting each fetch of the board as one call
+func lookaheadConfig(hours int) *config.Config {
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.LookaheadHours = hours
+	cfg.MaxPages = 1
+	cfg.MaxCallsPerHour = 1000
+	return cfg
+}
+
+// adjust presses key and returns the board once the fetch it started, if
+// any, has returned, with the number of fetches started
+func adjust(t *testing.T, m BoardModel, key string) (BoardModel, int) {
+	t.Helper()
+	used := m.budget.Used()
+	model, cmd := m.Update(keyMsg(key))
+	m = model.(BoardModel)
+	fetches := m.budget.Used() - used
+	if (cmd == nil) != (fetches == 0) {
+		t.Errorf("'%s' started %d fetches, returning command %v", key, fetches, cmd != nil)
+	}
+	if m.current().inFlight {
+		m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	}
+	return m, fetches
+}
+
+func TestAdjustLookahead(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		configured int
+		keys       string
+		want       []int // Window after each key, in hours
+		fetches    []int // Fetches started by each key
+	}{
+		{"grow and shrink", 6, "+=-", []int{7, 8, 7}, []int{1, 1, 1}},
+		{"at the top", 24, "+-+", []int{24, 23, 24}, []int{0, 1, 1}},
+		{"at the bottom", 1, "--+", []int{1, 1, 2}, []int{0, 0, 1}},
+		{"clamped from above", 30, "+-", []int{24, 23}, []int{1, 1}},
+		{"without an end", 0, "+-", []int{0, 24}, []int{0, 1}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())}, WithConfig(lookaheadConfig(tt.configured)))
+			for i, key := range tt.keys {
+				var fetches int
+				m, fetches = adjust(t, m, string(key))
+				if m.lookahead != tt.want[i] || fetches != tt.fetches[i] {
+					t.Errorf("after '%c': %dh with %d fetches, want %dh with %d", key, m.lookahead, fetches, tt.want[i], tt.fetches[i])
+				}
+				if want := time.Duration(tt.want[i]) * time.Hour; tt.want[i] > 0 && m.Board().Lookahead != want {
+					t.Errorf("after '%c': board looks %s ahead, want %s", key, m.Board().Lookahead, want)
+				}
+			}
+		})
+	}
+}
+
+// TestAdjustLookaheadOperationalDay checks that with OPERATIONAL_DAY set the
+// keys say the window is fixed, and change and fetch nothing
+func TestAdjustLookaheadOperationalDay(t *testing.T) {
+	cfg := lookaheadConfig(6)
+	cfg.OperationalDay = "03:00"
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())}, WithConfig(cfg))
+	for _, key := range []string{"+", "-"} {
+		var fetches int
+		m, fetches = adjust(t, m, key)
+		if m.lookahead != 6 || fetches != 0 {
+			t.Errorf("'%s' with OPERATIONAL_DAY: %dh with %d fetches", key, m.lookahead, fetches)
+		}
+		if want := "the lookahead window is set by OPERATIONAL_DAY"; m.Board().Toast != want || !m.Board().ToastUntil.After(time.Now()) {
+			t.Errorf("'%s' with OPERATIONAL_DAY: toast %q, want %q", key, m.Board().Toast, want)
+		}
+	}
+}
+
+// TestLookaheadPersisted checks that the window chosen is saved in the state
+// file and chosen again on the next run, unless LOOKAHEAD_HOURS has changed
+func TestLookaheadPersisted(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	cfg := lookaheadConfig(6)
+	cfg.StateFile = stateFile
+	provider := &fakeProvider{flights: modelFlights(3, time.Now())}
+	m := newTestModel(t, provider, WithConfig(cfg))
+	m, _ = adjust(t, m, "+")
+	adjust(t, m, "+")
+	state, err := loadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved := state.Lookahead; saved == nil || *saved != (savedLookahead{Hours: 8, Configured: 6}) {
+		t.Fatalf("state file has lookahead %+v, want 8h chosen from 6h", saved)
+	}
+
+	// At a bound, nothing changes and the file is left alone
+	cfgTop := lookaheadConfig(24)
+	cfgTop.StateFile = filepath.Join(t.TempDir(), "state.json")
+	top := newTestModel(t, provider, WithConfig(cfgTop))
+	adjust(t, top, "+")
+	if state, _ := loadState(cfgTop.StateFile); state.Lookahead != nil {
+		t.Errorf("state file saved lookahead %+v at the bound", state.Lookahead)
+	}
+
+	for _, tt := range []struct {
+		configured int
+		want       int
+	}{
+		{6, 8},   // The window chosen last run
+		{12, 12}, // LOOKAHEAD_HOURS changed since, so it wins
+	} {
+		next := lookaheadConfig(tt.configured)
+		next.StateFile = stateFile
+		if m := newTestModel(t, provider, WithConfig(next)); m.lookahead != tt.want {
+			t.Errorf("restarted with LOOKAHEAD_HOURS=%d: %dh, want %dh", tt.configured, m.lookahead, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package fids
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// memoryStats publishes the sizes of the model's bounded collections under
+// "fids" in expvar, for programs that serve /debug/vars
+var memoryStats = expvar.NewMap("fids")
+
+// sweep drops cached boards, change events, seen flights and gate histories
+// that have expired by now, so a board left running for weeks holds no more
+// than its caps, and reports what is kept. Boards prune seen flights and gate
+// histories on each update too, but only while their fetches succeed
+func (m BoardModel) sweep(now time.Time) {
+	m.cache.prune(now)
+	m.events.prune(now)
+	m.seen.Prune(now)
+	for _, t := range m.tabs {
+		t.board.Gates.Prune(now)
+	}
+
+	sizes := []struct {
+		name  string
+		value int
+	}{
+		{"cached_boards", m.cache.len()},
+		{"change_events", m.events.count},
+		{"seen_flights", m.seen.Len()},
+		{"tabs", len(m.tabs)},
+	}
+	attrs := make([]any, 0, 2*len(sizes)+2)
+	for _, size := range sizes {
+		v := new(expvar.Int)
+		v.Set(int64(size.value))
+		memoryStats.Set(size.name, v)
+		attrs = append(attrs, size.name, size.value)
+	}
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		// Reading heap statistics briefly stops the world, so only when logged
+		var heap runtime.MemStats
+		runtime.ReadMemStats(&heap)
+		attrs = append(attrs, "heap_bytes", heap.HeapAlloc)
+		slog.Debug("memory", attrs...)
+	}
+}
@@ -0,0 +1,97 @@
+package fids
+
+import (
+	"context"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FlightsMsg carries the result of a flight data fetch for a tab
+type FlightsMsg struct {
+	Tab     int
+	Flights []models.Flight
+	Pages   int              // Result pages the provider fetched
+	Total   int              // Flights the provider found before capping the list
+	Source  ui.Provenance    // Where the flights came from
+	Window  time.Duration    // Shorter window the source limited the fetch to, zero if it wasn't
+	Elapsed time.Duration    // How long the fetch took, every page included
+	Skipped api.SkipReport   // Flights a strict client left out for missing fields
+	Failed  map[string]error // Airports of an interleaved board whose fetch failed while others succeeded
+	Err     error
+	spec    config.TabSpec // Board the flights were fetched for
+}
+
+// TickAPIMsg triggers a flight data fetch for a tab
+type TickAPIMsg struct {
+	Tab  int
+	Time time.Time
+	seq  int // Tick chain the tick belongs to
+}
+
+// ReplayMsg steps the active board through the recording being replayed:
+// Step snapshots forward, or back if negative
+type ReplayMsg struct {
+	Step int
+}
+
+// TickPageRotationMsg advances the board to the next page
+type TickPageRotationMsg time.Time
+
+// TickAnimationMsg advances the character animations
+type TickAnimationMsg time.Time
+
+// TickClockMsg redraws the time-dependent parts of the view, such as the
+// status bar countdown and the idle clock, while nothing is animating
+type TickClockMsg time.Time
+
+// Commands
+
+func tickAPI(tab, seq int, duration time.Duration) tea.Cmd {
+	return tea.Tick(duration, func(t time.Time) tea.Msg {
+		return TickAPIMsg{Tab: tab, Time: t, seq: seq}
+	})
+}
+
+func tickPageRotation(duration time.Duration) tea.Cmd {
+	return tea.Tick(duration, func(t time.Time) tea.Msg {
+		return TickPageRotationMsg(t)
+	})
+}
+
+func tickAnimation(duration time.Duration) tea.Cmd {
+	return tea.Tick(duration, func(t time.Time) tea.Msg {
+		return TickAnimationMsg(t)
+	})
+}
+
+func tickClock() tea.Cmd {
+	return tea.Tick(clockInterval, func(t time.Time) tea.Msg {
+		return TickClockMsg(t)
+	})
+}
+
+func fetchFlights(provider api.FlightDataProvider, tab int, spec config.TabSpec, hours int, maxPages int) tea.Cmd {
+	return func() tea.Msg {
+		// The board keeps delayed flights still at the gate, scheduled before now
+		opts := api.FetchOptions{Window: time.Duration(hours) * time.Hour, MaxPages: maxPages, IncludePast: true}
+		started := time.Now()
+		var result api.FetchResult
+		var failed map[string]error
+		var err error
+		if spec.Merged != "" {
+			result, failed, err = fetchAirports(context.Background(), provider, spec, opts)
+		} else if spec.Destination != "" {
+			result, err = api.GetRoute(context.Background(), provider, spec.AirportCode, spec.Destination, opts)
+		} else {
+			result, err = api.GetFlights(context.Background(), provider, spec.Direction, spec.AirportCode, opts)
+		}
+		source := ui.Provenance{Source: result.Source, FetchedAt: time.Now(), Simulated: result.Simulated, RecordedAt: result.RecordedAt}
+		return FlightsMsg{Tab: tab, Flights: result.Flights, Pages: result.Pages, Total: result.Total, Source: source, Window: result.WindowLimit, Elapsed: time.Since(started), Skipped: result.Skipped, Failed: failed, Err: err, spec: spec}
+	}
+}
@@ -0,0 +1,1394 @@
+package fids
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/mqtt"
+	"fids-tui/notify"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// navigationPause is how long page rotation stays paused after manual navigation
+const navigationPause = 60 * time.Second
+
+// idleWakeDuration is how long a keypress shows the empty board instead of the idle clock
+const idleWakeDuration = time.Minute
+
+// BoardModel is a bubbletea model that displays live flight boards, one per tab
+// Create one with New and run it directly or embed it in another model
+type BoardModel struct {
+	tabs           []*tab
+	active         int // Index of the tab being shown
+	nextTabID      int
+	sideBySide     bool // The tabs are nearby airports' boards, shown next to each other
+	provider       api.FlightDataProvider
+	replay         *api.ReplayProvider // The provider when replaying a recording, else nil
+	cfg            *config.Config
+	remarks        *ui.RemarkTemplates
+	glyphs         *ui.GlyphSet
+	palette        *ui.Palette
+	display        *ui.Display // Chosen for the terminal with WithDisplay, nil for the configured glyphs in full color
+	borders        ui.BorderMode
+	layout         ui.LayoutMode
+	view           ui.ViewMode
+	scrollColumns  map[ui.ColumnID]bool // Columns whose long text scrolls
+	startupRetries []time.Duration      // Delays between attempts at a board's first fetch
+	timeZone       ui.TimeZoneMode
+	specs          []config.TabSpec // Tabs requested with WithAirport or WithTabs
+	cache          *boardCache      // Boards recently switched away from
+	inbound        *inboundLookups  // Inbound aircraft of delayed departures, looked up for the detail panel
+	tracking       *watchTracking   // Watched departures followed after they left the board
+	overlays       ui.ScreenStack   // Prompts and panels shown over the board
+	pageEntry      bool             // Typing a page number to jump to
+	pageInput      string           // Page number typed so far
+	rotationPause  time.Time        // Page rotation is paused until this time
+	rotationHeld   bool             // Page rotation is paused from the control socket
+	idleWake       time.Time        // The idle clock is suppressed until this time
+	schedule       config.IntervalSchedule
+	termWidth      int
+	termHeight     int
+	animating      bool      // Whether an animation tick is scheduled
+	blurred        bool      // The terminal is unfocused and animations are paused
+	lastClock      time.Time // When the clock last ticked, to notice time spent suspended
+	events         *eventLog
+	alerts         *alertFilter             // Changes sent to the notifier
+	notifier       *notify.Dispatcher       // Phone, webhook and bell alerts; nil if none are configured
+	sound          *notify.Sound            // Flap sound played when rows flip; nil if sound is off
+	mqtt           *mqtt.Publisher          // Publishes flights and changes to an MQTT broker; nil unless MQTT_URL is set
+	kiosk          *kioskLock               // Keeps input inert on unattended displays; nil unless KIOSK is set
+	footer         *ui.Marquee              // FOOTER_TEXT, shown in place of the key help; nil unless set
+	ticker         *ui.Ticker               // The line of the ticker view, nil for the other views
+	spend          *spendTracker            // Estimated API cost of the session and the day
+	budget         *api.Budget              // Caps the API calls an hour, nil for no cap
+	quietHours     *config.TimeRange        // Daily range without updates, nil if unset
+	quietPaused    bool                     // Updates are paused for quiet hours
+	quietWake      time.Time                // Quiet hours are suspended until this time
+	directions     config.DirectionSchedule // When the active board shows departures or arrivals
+	destination    string                   // Departures are shown only to this airport, if set
+	shuttles       []string                 // Destinations of the shuttle view, in order
+	watchFlights   []string                 // Flights listed in the watch sidebar, without spaces
+	watchPlaces    []string                 // Destinations listed in the watch sidebar
+	lookahead      int                      // Hours of flights fetched, changed with '+' and '-'
+	service        *serviceNotifier         // systemd readiness and watchdog notifications
+	seen           *ui.SeenFlights          // Flights seen on any board, for NEW badges
+	gates          *ui.GateHistory          // Earlier gates of flights on any board
+	processors     []FlightProcessor        // Processors added with WithProcessors
+	pipeline       pipeline                 // Applied to every fetch before it is shown
+	blocked        blockedMode              // Where flights blocked from public tracking are shown
+	warnings       []string                 // Problems in the configuration that don't stop the board
+}
+
+// clockInterval is how often the countdown and idle clock are redrawn
+const clockInterval = time.Second
+
+// toastDuration is how long a transient fetch error is shown in the status bar
+const toastDuration = 60 * time.Second
+
+// errorBannerAfter is the number of transient fetch errors in a row after
+// which they are shown in the banner like persistent errors
+const errorBannerAfter = 3
+
+// maxPageDigits limits how many digits can be typed when jumping to a page
+const maxPageDigits = 3
+
+// Option configures a BoardModel
+type Option func(*BoardModel)
+
+// WithConfig sets the configuration (defaults to config.Default())
+func WithConfig(cfg *config.Config) Option {
+	return func(m *BoardModel) {
+		m.cfg = cfg
+	}
+}
+
+// WithAirport shows the departures board of a single airport instead of the
+// tabs from the configuration
+func WithAirport(airportCode string) Option {
+	return func(m *BoardModel) {
+		code := strings.ToUpper(strings.TrimSpace(airportCode))
+		m.specs = []config.TabSpec{{AirportCode: code, Direction: models.Departure}}
+	}
+}
+
+// WithTabs sets the boards shown as tabs instead of the tabs from the configuration
+func WithTabs(specs ...config.TabSpec) Option {
+	return func(m *BoardModel) {
+		m.specs = specs
+	}
+}
+
+// WithProcessors adds processors applied to every fetch after the built-in
+// ones and customProcessors
+func WithProcessors(processors ...FlightProcessor) Option {
+	return func(m *BoardModel) {
+		m.processors = append(m.processors, processors...)
+	}
+}
+
+// WithDisplay draws the boards as chosen for the terminal by
+// ui.ChooseDisplay (defaults to the configured glyphs in full color)
+func WithDisplay(display ui.Display) Option {
+	return func(m *BoardModel) {
+		m.display = &display
+	}
+}
+
+// WithProvider sets the flight data provider (defaults to the provider built
+// from the configuration by NewProvider)
+func WithProvider(provider api.FlightDataProvider) Option {
+	return func(m *BoardModel) {
+		m.provider = provider
+	}
+}
+
+// New creates a board model, validating the configuration
+// Without WithAirport or WithTabs the boards compare cfg.Airports, or else
+// the tabs come from cfg.Tabs, or else a single departures board for
+// cfg.AirportCode
+func New(opts ...Option) (BoardModel, error) {
+	m := BoardModel{
+		cfg:       config.Default(),
+		animating: true, // Init starts the animation ticker
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	specs := m.specs
+	if len(specs) == 0 && m.cfg.Airports != "" {
+		var err error
+		specs, m.sideBySide, err = compareSpecs(m.cfg.Airports, m.cfg.AirportsLayout)
+		if err != nil {
+			return BoardModel{}, err
+		}
+	}
+	if len(specs) == 0 && m.cfg.Tabs != "" {
+		var err error
+		specs, err = config.ParseTabs(m.cfg.Tabs)
+		if err != nil {
+			return BoardModel{}, fmt.Errorf("TABS: %w", err)
+		}
+	}
+	if len(specs) == 0 && m.cfg.AirportCode != "" {
+		code := strings.ToUpper(strings.TrimSpace(m.cfg.AirportCode))
+		specs = []config.TabSpec{{AirportCode: code, Direction: models.Departure}}
+	}
+	if len(specs) == 0 {
+		return BoardModel{}, fmt.Errorf("airport code required")
+	}
+	for _, spec := range specs {
+		for _, code := range spec.Airports() {
+			if err := config.ValidateAirportCode(code); err != nil {
+				return BoardModel{}, err
+			}
+		}
+		if spec.Destination != "" {
+			if err := config.ValidateAirportCode(spec.Destination); err != nil {
+				return BoardModel{}, err
+			}
+		}
+	}
+
+	// Load the rules file now so mistakes are reported before the board starts
+	var rules rules
+	var err error
+	if m.cfg.RulesFile != "" {
+		if rules, err = loadRules(m.cfg.RulesFile); err != nil {
+			return BoardModel{}, fmt.Errorf("RULES_FILE: %w", err)
+		}
+	}
+	m.blocked, err = parseBlockedMode(m.cfg.ShowBlocked)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("SHOW_BLOCKED: %w", err)
+	}
+	m.pipeline = newPipeline(m.cfg, m.blocked, rules, m.processors)
+
+	usage := &api.Usage{}
+	if m.provider == nil {
+		provider, err := NewProvider(m.cfg, usage)
+		if err != nil {
+			return BoardModel{}, err
+		}
+		m.provider = provider
+	}
+	m.replay, _ = m.provider.(*api.ReplayProvider)
+	m.spend = newSpendTracker(usage, m.cfg.CostPerQuery, m.cfg.StateFile, time.Now())
+	m.budget = api.NewBudget(m.cfg.MaxCallsPerHour)
+
+	// The configured destination filter wins over the one last chosen with 'f'
+	if strings.TrimSpace(m.cfg.DestinationOnly) != "" {
+		if m.destination, err = config.NormalizeAirportCode(m.cfg.DestinationOnly); err != nil {
+			return BoardModel{}, fmt.Errorf("DESTINATION_ONLY: %w", err)
+		}
+	}
+
+	// The last run's filter and the flights it saw carry over
+	var saved persistentState
+	if m.cfg.StateFile != "" {
+		if state, err := loadState(m.cfg.StateFile); err == nil {
+			saved = state
+		}
+	}
+	if m.destination == "" {
+		m.destination = saved.DestinationOnly
+	}
+	m.lookahead = saved.Lookahead.hours(m.cfg.LookaheadHours)
+	m.seen = ui.NewSeenFlights(saved.SeenFlights, time.Now())
+	m.gates = ui.NewGateHistory()
+
+	// Compile remark templates once so mistakes are reported before the board starts
+	m.remarks, err = ui.ParseRemarkTemplates(m.cfg.RemarkTemplates)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("REMARK_TEMPLATES: %w", err)
+	}
+
+	glyphs := m.cfg.Glyphs
+	if m.display != nil {
+		glyphs = m.display.Glyphs
+	}
+	m.glyphs, err = ui.ParseGlyphSet(glyphs, m.cfg.StatusGlyphs)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("GLYPHS: %w", err)
+	}
+	if m.display != nil && m.display.ASCIIShades {
+		m.glyphs = m.glyphs.WithASCIIShades()
+	}
+
+	m.palette, err = ui.ParsePalette(m.cfg.Palette)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("PALETTE: %w", err)
+	}
+
+	m.borders, err = ui.ParseBorderMode(m.cfg.Borders)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("BORDERS: %w", err)
+	}
+
+	m.layout, err = ui.ParseLayoutMode(m.cfg.Layout)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("LAYOUT: %w", err)
+	}
+
+	m.view, err = ui.ParseViewMode(m.cfg.View)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("VIEW: %w", err)
+	}
+
+	m.scrollColumns, err = ui.ParseScrollColumns(m.cfg.ScrollColumns)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("SCROLL_COLUMNS: %w", err)
+	}
+
+	m.startupRetries, err = api.ParseRetryDelays(m.cfg.StartupRetries)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("STARTUP_RETRIES: %w", err)
+	}
+
+	m.shuttles, err = config.ParseDestinations(m.cfg.PriorityDestinations)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("PRIORITY_DESTINATIONS: %w", err)
+	}
+	if m.view == ui.ViewShuttle && len(m.shuttles) == 0 {
+		return BoardModel{}, fmt.Errorf("VIEW: the shuttle view needs PRIORITY_DESTINATIONS, e.g. BOS,DCA,ORD")
+	}
+	m.watchFlights, m.watchPlaces, err = config.ParseWatchList(m.cfg.Watch)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("WATCH: %w", err)
+	}
+
+	m.timeZone, err = ui.ParseTimeZoneMode(m.cfg.TimeZoneMode)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("TIME_ZONE_MODE: %w", err)
+	}
+
+	m.schedule, err = config.ParseIntervalSchedule(m.cfg.UpdateSchedule)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("UPDATE_SCHEDULE: %w", err)
+	}
+
+	if m.cfg.QuietHours != "" {
+		quiet, err := config.ParseTimeRange(m.cfg.QuietHours)
+		if err != nil {
+			return BoardModel{}, fmt.Errorf("QUIET_HOURS: %w", err)
+		}
+		m.quietHours = &quiet
+	}
+
+	m.directions, err = config.ParseDirectionSchedule(m.cfg.DirectionSchedule)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("DIRECTION_SCHEDULE: %w", err)
+	}
+
+	m.cache = newBoardCache(m.cfg.BoardCacheTTL)
+	m.inbound = newInboundLookups(m.cfg.InboundLookups, m.cfg.UpdateInterval)
+	m.tracking = newWatchTracking(m.cfg.TrackWatchedFor, m.cfg.TrackWatchedEvery)
+	m.events = newEventLog(m.cfg.EventLogSize, m.cfg.EventLogRetention)
+
+	m.alerts, err = parseAlertFilter(m.cfg.NotifyOn)
+	if err != nil {
+		return BoardModel{}, fmt.Errorf("NOTIFY_ON: %w", err)
+	}
+	m.notifier, err = newDispatcher(m.cfg)
+	if err != nil {
+		return BoardModel{}, err
+	}
+	if m.cfg.Sound {
+		m.sound, err = notify.NewSound(m.cfg.SoundCommand, os.Stderr)
+		if err != nil {
+			return BoardModel{}, fmt.Errorf("SOUND_COMMAND: %w", err)
+		}
+	}
+	if m.cfg.Kiosk {
+		m.kiosk = newKioskLock(m.cfg.KioskUnlock)
+	}
+	if m.cfg.FooterText != "" {
+		m.footer = ui.NewMarquee(m.cfg.FooterText, m.cfg.ScrollFooter)
+	}
+	m.ticker = m.newTicker()
+	m.mqtt, err = newMQTTPublisher(m.cfg)
+	if err != nil {
+		return BoardModel{}, err
+	}
+	m.service = newServiceNotifier()
+	m.warnings = m.checkCodes(specs, rules)
+	m.logWarnings()
+
+	for _, spec := range specs {
+		m.tabs = append(m.tabs, m.newTab(spec))
+	}
+	// The first board starts in the direction the schedule wants
+	first := m.current()
+	if direction, ok := m.scheduledDirection(first, time.Now()); ok && direction != first.spec.Direction {
+		first.spec.Direction = direction
+		m.loadBoard(first)
+	}
+	m.fitBoards()
+	m.registerBudget()
+	if now := time.Now(); m.inQuietHours(now) {
+		// Starting during quiet hours makes no API calls until they end
+		m.pauseForQuietHours(now)
+	}
+
+	return m, nil
+}
+
+// Board returns the board of the active tab
+func (m BoardModel) Board() *ui.Board {
+	return m.current().board
+}
+
+// AirportCode returns the airport of the active tab
+func (m BoardModel) AirportCode() string {
+	return m.current().spec.AirportCode
+}
+
+func (m BoardModel) Init() tea.Cmd {
+	// Only the first tab is fetched up front; others are fetched when first
+	// shown. Side by side boards are all shown at once
+	var refresh tea.Cmd
+	switch {
+	case m.quietPaused:
+	case m.sideBySide:
+		refresh = m.refreshPanes()
+	default:
+		refresh = m.refresh(m.current())
+	}
+	return tea.Batch(
+		refresh,
+		tickPageRotation(m.pageDelay()),
+		tickAnimation(m.cfg.CharAnimationSpeed),
+		tickClock(),
+	)
+}
+
+func (m BoardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// A locked kiosk only listens for the unlock sequence
+		if now := time.Now(); m.kiosk.locked(now) {
+			if m.kiosk.key(msg.String(), now) {
+				// The help text is back, so the boards have fewer lines
+				m.fitBoards()
+			}
+			return m, nil
+		}
+		// Global keys work the same in every mode and overlay
+		if cmd, ok := m.globalKey(msg); ok {
+			return m, cmd
+		}
+		switch overlay := m.overlays.Top().(type) {
+		case *promptOverlay:
+			return m.updateInput(overlay, msg)
+		case *logOverlay:
+			return m.updateLogView(overlay, msg)
+		case *destinationOverlay:
+			return m.updateDestinationInput(overlay, msg)
+		case *airlineOverlay:
+			return m.updateAirlineList(overlay, msg)
+		case *opsOverlay:
+			return m.updateOpsView(overlay, msg)
+		case *helpOverlay:
+			return m.updateHelpView(msg)
+		}
+		if m.pageEntry {
+			return m.updatePageEntry(msg)
+		} else {
+			// Normal mode
+			switch msg.String() {
+			case "q":
+				return m, tea.Quit
+			}
+			// During quiet hours any key just resumes updates for a while
+			if m.quietPaused {
+				m.quietWake = time.Now().Add(quietWakeDuration)
+				return m, m.resumeAfterQuietHours()
+			}
+			// While the idle clock is showing, any key just reveals the board
+			if m.isIdle() {
+				m.idleWake = time.Now().Add(idleWakeDuration)
+				return m, nil
+			}
+			board := m.Board()
+			switch msg.String() {
+			case "a":
+				// Prompt for an airport code
+				m.overlays.Push(&promptOverlay{direction: m.current().spec.Direction, styles: board.Styles})
+				return m, nil
+			case "tab":
+				return m, m.activate((m.active + 1) % len(m.tabs))
+			case "shift+tab":
+				return m, m.activate((m.active - 1 + len(m.tabs)) % len(m.tabs))
+			case "x":
+				return m, m.closeTab()
+			case "c":
+				// Toggle between the wide and compact layouts
+				return m, m.toggleLayout()
+			case "v":
+				// Cycle through the timetable, gate and shuttle views
+				return m, m.toggleView()
+			case "f":
+				// Prompt for a destination to show departures to
+				m.overlays.Push(&destinationOverlay{styles: board.Styles})
+				return m, nil
+			case "A":
+				// Choose the airlines shown
+				m.overlays.Push(newAirlineOverlay(board))
+				return m, nil
+			case "d":
+				// Toggle between departures and arrivals
+				return m, m.toggleDirection()
+			case "z":
+				// Cycle times between airport-local, UTC and viewer-local
+				return m, m.cycleTimeZone()
+			case "L":
+				// Show the change log
+				m.overlays.Push(&logOverlay{events: m.events, styles: board.Styles})
+				return m, nil
+			case "O":
+				// Show how each airline is running
+				m.overlays.Push(&opsOverlay{board: board, styles: board.Styles})
+				return m, nil
+			case "?":
+				// List the keys, which a custom footer leaves out
+				m.overlays.Push(&helpOverlay{keys: m.keyHelp(), styles: board.Styles})
+				return m, nil
+			case "<":
+				return m, replayStep(-1)
+			case ">":
+				return m, replayStep(1)
+			case "right":
+				return m, m.navigatePage(1)
+			case "left":
+				return m, m.navigatePage(-1)
+			case "+", "=":
+				return m, m.adjustLookahead(1)
+			case "-":
+				return m, m.adjustLookahead(-1)
+			case "esc":
+				board.ClearSelection()
+				board.Hint = ""
+				return m, m.followSelection()
+			case "g":
+				// Start typing a page number
+				m.startPageEntry("")
+				return m, nil
+			case "home":
+				board.GoToPage(1)
+				m.rotationPause = time.Now().Add(navigationPause)
+				return m, nil
+			case "G", "end":
+				board.GoToPage(board.TotalPages)
+				m.rotationPause = time.Now().Add(navigationPause)
+				return m, nil
+			case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				if len(m.tabs) > 1 {
+					// With several tabs, number keys select a tab
+					index, _ := strconv.Atoi(msg.String())
+					return m, m.activate(index - 1)
+				}
+				// Typing a digit starts a page jump with that digit
+				m.startPageEntry(msg.String())
+				return m, nil
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.termWidth, m.termHeight = msg.Width, msg.Height
+		for _, t := range m.tabs {
+			t.board.SetTerminalSize(m.boardWidth(), msg.Height)
+		}
+		m.fitBoards()
+		return m, nil
+
+	case tea.MouseMsg:
+		if m.kiosk.locked(time.Now()) {
+			return m, nil
+		}
+		if log, ok := m.overlays.Top().(*logOverlay); ok {
+			// The wheel scrolls the change log
+			switch msg.Button {
+			case tea.MouseButtonWheelDown:
+				return m.updateLogView(log, tea.KeyMsg{Type: tea.KeyDown})
+			case tea.MouseButtonWheelUp:
+				return m.updateLogView(log, tea.KeyMsg{Type: tea.KeyUp})
+			}
+			return m, nil
+		}
+		if m.overlays.Len() > 0 {
+			// Other overlays ignore the mouse
+			return m, nil
+		}
+		var activated tea.Cmd
+		if m.sideBySide && msg.Action == tea.MouseActionPress && msg.Y >= m.tabBarHeight() {
+			// The keys go to the board clicked or scrolled
+			activated = m.activate(m.paneAt(msg.X))
+		}
+		board := m.Board()
+		y := msg.Y - m.tabBarHeight()
+		var cmd tea.Cmd
+		switch {
+		case msg.Button == tea.MouseButtonWheelDown:
+			cmd = m.navigatePage(1)
+		case msg.Button == tea.MouseButtonWheelUp:
+			cmd = m.navigatePage(-1)
+		case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+			if y < 0 {
+				// Click on the tab bar
+				return m, m.activate(ui.TabAt(m.tabLabels(), msg.X, board.Styles))
+			}
+			if index, ok := board.RowAt(y); ok {
+				board.Select(index)
+				m.rotationPause = time.Now().Add(navigationPause)
+				cmd = m.followSelection()
+			} else if board.IsPageInfoLine(y) {
+				cmd = m.navigatePage(1)
+			}
+		}
+		return m, tea.Batch(activated, cmd)
+
+	case ReplayMsg:
+		return m, m.stepReplay(msg)
+
+	case FlightsMsg:
+		t := m.tabByID(msg.Tab)
+		if t == nil || t.spec != msg.spec {
+			// The tab was closed or switched to another board while fetching
+			return m, nil
+		}
+		if t.board.Transitioning() {
+			// Apply the update once the page has flipped, not mid-flip
+			return m, tea.Tick(ui.TransitionTick, func(time.Time) tea.Msg { return msg })
+		}
+		t.loading = false
+		refetch := m.fetchReturned(t)
+		t.unsettled = true
+		// Startup has finished once the first fetch is applied, whether it
+		// brought flights or an error the board shows
+		m.service.Ready()
+		m.recordSpend()
+		m.flagSlowFetch(t, msg)
+		if msg.Err != nil {
+			t.err = msg.Err
+			t.failures++
+			m.showFetchError(t, msg.Err, time.Now())
+			if retry := m.retryStartup(t, time.Now()); retry != nil {
+				refetch = tea.Batch(refetch, retry)
+			}
+		} else {
+			t.err = nil
+			t.failures = 0
+			t.startup = nil
+			t.board.Error = ""
+			t.board.Toast = ""
+			if m.shown(t) {
+				m.inbound.refreshed(time.Now())
+			}
+			// Keep the reader's place unless the page is about to rotate anyway
+			t.board.KeepPage = !m.shown(t) || !m.rotating()
+			m.showAirportErrors(t, msg.Failed, time.Now())
+			m.suggestNearby(t, len(msg.Flights))
+			var summary ui.UpdateSummary
+			if t.rebuild {
+				t.rebuild = false
+				summary = t.board.Rebuild(m.pipeline.apply(t.withFailedAirports(msg)))
+			} else {
+				summary = t.board.UpdateFlights(m.pipeline.apply(t.withFailedAirports(msg)))
+			}
+			m.tracking.observe(t.board, m.watchFlights, time.Now())
+			m.events.add(summary.Events...)
+			m.sendAlerts(summary.Events, msg.Source.Source)
+			t.board.FetchedPages = msg.Pages
+			t.board.FlightsKept = len(msg.Flights)
+			t.board.FlightsFound = msg.Total
+			t.board.Provenance = msg.Source
+			t.board.WindowLimit = msg.Window
+			t.board.Skipped = msg.Skipped.String()
+			m.publishUpdate(t, summary.Events)
+			m.saveSeenFlights()
+			if m.shown(t) && summary.Changed+summary.Added > 0 {
+				m.sound.Play(time.Now())
+			}
+			if m.shown(t) && summary.Any() {
+				return m, tea.Batch(refetch, m.startAnimation())
+			}
+		}
+		m.noteSettled()
+		return m, refetch
+
+	case TickAPIMsg:
+		// Fetch flights for the tab unless its schedule has been restarted since
+		t := m.tabByID(msg.Tab)
+		if t == nil || msg.seq != t.tickSeq || m.quietPaused {
+			return m, nil
+		}
+		m.sweep(msg.Time)
+		if m.sideBySide {
+			return m, m.refreshPanes()
+		}
+		if t == m.current() {
+			// Switching direction fetches the new board instead
+			if cmd := m.followDirectionSchedule(msg.Time); cmd != nil {
+				return m, cmd
+			}
+		}
+		return m, m.refresh(t)
+
+	case TickPageRotationMsg:
+		// Rotate to next page unless the user is navigating or has a flight selected
+		if m.rotating() {
+			for _, board := range m.shownBoards() {
+				board.NextPage()
+			}
+			return m, tea.Batch(tickPageRotation(m.pageDelay()), m.startAnimation())
+		}
+		return m, tickPageRotation(m.pageDelay())
+
+	case TickAnimationMsg:
+		// Update character animations, stopping the ticker once they settle
+		m.service.Watchdog(time.Time(msg))
+		if m.blurred {
+			m.animating = false
+			return m, nil
+		}
+		animating := false
+		for _, board := range m.shownBoards() {
+			board.Tick()
+			animating = animating || board.IsAnimating()
+		}
+		if m.footer != nil {
+			// A footer too wide for the terminal scrolls on the same ticks
+			m.footer.Step(m.termWidth)
+		}
+		if m.ticker != nil {
+			m.ticker.Step(m.Board().TickerItems(), m.termWidth)
+		}
+		if !animating {
+			// Scrolling text keeps the ticker going, but the boards have settled
+			m.noteSettled()
+		}
+		if !animating && !m.scrolling() {
+			m.animating = false
+			return m, nil
+		}
+		return m, tickAnimation(m.animationInterval())
+
+	case ControlMsg:
+		resp, cmd := m.control(msg.Request)
+		msg.reply <- resp
+		return m, cmd
+
+	case TickClockMsg:
+		// Receiving the message redraws the view; quiet hours start and end here.
+		// The clock keeps ticking when animations settle, so it keeps the
+		// watchdog fed too
+		m.service.Watchdog(time.Time(msg))
+		var resumed tea.Cmd
+		if away := m.noteClock(time.Time(msg)); away > 0 {
+			resumed = m.awake(time.Time(msg), away)
+		}
+		if m.kiosk.relock(time.Time(msg)) {
+			m.lockKiosk()
+		}
+		if m.ticker != nil {
+			m.ticker.SwapAt(time.Time(msg), m.Board().TickerItems(), m.termWidth)
+		}
+		var animate tea.Cmd
+		for _, t := range m.tabs {
+			if t.board.RefreshRemarks(time.Time(msg)) && m.shown(t) {
+				animate = m.startAnimation()
+			}
+		}
+		if animate == nil && m.scrolling() {
+			// The ticker stops once nothing scrolls, so text that starts to,
+			// such as a footer on a narrowed terminal, restarts it here
+			animate = m.startAnimation()
+		}
+		// Lookups of detail panels closed some other way are cancelled here
+		return m, tea.Batch(tickClock(), m.checkQuietHours(time.Time(msg)), animate, resumed, m.followSelection(), m.pollTracked(time.Time(msg)))
+
+	case tea.ResumeMsg:
+		// Back from ctrl+z, unless a late clock tick has caught up already
+		now := time.Now()
+		if away := m.noteClock(now); away > 0 {
+			return m, m.awake(now, away)
+		}
+		return m, nil
+
+	case tea.FocusMsg:
+		return m, m.focusChanged(true)
+
+	case tea.BlurMsg:
+		return m, m.focusChanged(false)
+
+	case InboundMsg:
+		m.inboundFound(msg)
+		return m, nil
+
+	case TrackMsg:
+		m.trackFound(msg)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// globalKey handles keys that work regardless of mode, before they reach an
+// overlay, page entry or the board, and reports whether msg was one of them
+// ctrl+c quits, ctrl+z suspends and ctrl+r refreshes the active board
+func (m *BoardModel) globalKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit, true
+	case "ctrl+z":
+		return tea.Suspend, true
+	case "ctrl+r":
+		return m.refreshNow(), true
+	}
+	return nil, false
+}
+
+// refreshNow fetches the active board straight away, resuming updates for a
+// while during quiet hours and the pacing of a replay stepped through by hand
+func (m *BoardModel) refreshNow() tea.Cmd {
+	if m.replay != nil {
+		m.replay.Resume()
+	}
+	if m.quietPaused {
+		m.quietWake = time.Now().Add(quietWakeDuration)
+		return m.resumeAfterQuietHours()
+	}
+	if m.sideBySide {
+		return m.refreshPanes()
+	}
+	return m.refresh(m.current())
+}
+
+// updateInput handles keys while an airport code is being typed
+// Enter shows the airport on the current tab, ctrl+t opens it in a new tab
+// and tab toggles between departures and arrivals
+func (m BoardModel) updateInput(prompt *promptOverlay, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "ctrl+t":
+		m.overlays.Pop()
+		code, err := config.NormalizeAirportCode(prompt.input)
+		if err != nil {
+			// Invalid code, exit input mode
+			return m, nil
+		}
+		return m, m.showAirport(code, prompt.direction, msg.String() == "ctrl+t")
+	case "tab", "shift+tab":
+		if prompt.direction == models.Arrival {
+			prompt.direction = models.Departure
+		} else {
+			prompt.direction = models.Arrival
+		}
+		return m, nil
+	case "esc":
+		// Cancel input mode
+		m.overlays.Pop()
+		return m, nil
+	case "backspace":
+		if len(prompt.input) > 0 {
+			prompt.input = prompt.input[:len(prompt.input)-1]
+		}
+		return m, nil
+	default:
+		if msg.Type != tea.KeyRunes {
+			return m, nil
+		}
+		if code, ok := pastedAirportCode(msg); ok {
+			// Pasted text, such as "lax\n", shows its airport straight away
+			m.overlays.Pop()
+			return m, m.showAirport(code, prompt.direction, false)
+		}
+		prompt.input = typeAirportCode(prompt.input, msg.Runes)
+		return m, nil
+	}
+}
+
+// pastedAirportCode returns the airport code pasted, or typed in one go, if
+// the text is a whole one such as " lax\n"
+func pastedAirportCode(msg tea.KeyMsg) (string, bool) {
+	if !msg.Paste && len(msg.Runes) <= 1 {
+		return "", false
+	}
+	code, err := config.NormalizeAirportCode(string(msg.Runes))
+	return code, err == nil
+}
+
+// typeAirportCode adds the letters and digits of runes to the airport code
+// typed so far in upper case, while it is shorter than the longest code
+func typeAirportCode(input string, runes []rune) string {
+	for _, r := range runes {
+		if len(input) < 4 && ((r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')) {
+			input += strings.ToUpper(string(r))
+		}
+	}
+	return input
+}
+
+// showAirport shows the board of an airport code chosen at the prompt in
+// direction, on the current tab or a new one
+func (m *BoardModel) showAirport(code string, direction models.Direction, newTab bool) tea.Cmd {
+	spec := config.TabSpec{AirportCode: code, Direction: direction}
+	if newTab {
+		return m.addTab(spec)
+	}
+	if spec.Direction != m.current().spec.Direction {
+		m.overrideDirectionSchedule(m.current(), time.Now())
+	}
+	return m.switchBoard(spec)
+}
+
+// updateDestinationInput handles keys while the destination filter prompt is shown
+func (m BoardModel) updateDestinationInput(prompt *destinationOverlay, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.overlays.Pop()
+		if prompt.input == "" {
+			// No code shows every destination again
+			return m, m.setDestination("")
+		}
+		code, err := config.NormalizeAirportCode(prompt.input)
+		if err != nil {
+			return m, nil
+		}
+		return m, m.setDestination(code)
+	case "esc":
+		m.overlays.Pop()
+	case "backspace":
+		if len(prompt.input) > 0 {
+			prompt.input = prompt.input[:len(prompt.input)-1]
+		}
+	default:
+		if msg.Type != tea.KeyRunes {
+			return m, nil
+		}
+		if code, ok := pastedAirportCode(msg); ok {
+			// Pasted text, such as "lax\n", filters straight away
+			m.overlays.Pop()
+			return m, m.setDestination(code)
+		}
+		prompt.input = typeAirportCode(prompt.input, msg.Runes)
+	}
+	return m, nil
+}
+
+// updateAirlineList handles keys while the airline list is shown
+func (m BoardModel) updateAirlineList(list *airlineOverlay, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "enter", "esc":
+		m.overlays.Pop()
+		m.Board().SetHiddenAirlines(list.list.Unchecked())
+		return m, m.startAnimation()
+	case "x":
+		m.overlays.Pop()
+		m.Board().SetHiddenAirlines(nil)
+		return m, m.setDestination("")
+	default:
+		list.list.HandleKey(msg.String())
+	}
+	return m, nil
+}
+
+// saveSeenFlights saves the flights seen so far to the state file, so they
+// aren't badged NEW after a restart
+func (m BoardModel) saveSeenFlights() {
+	if m.cfg.StateFile == "" {
+		return
+	}
+	err := updateState(m.cfg.StateFile, func(state *persistentState) {
+		state.SeenFlights = m.seen.Snapshot()
+	})
+	if err != nil {
+		slog.Warn("failed to save seen flights", "error", err)
+	}
+}
+
+// setDestination shows every departures board only to code, or unfiltered if
+// code is empty, and remembers the choice in the state file. Route boards
+// keep showing their route
+func (m *BoardModel) setDestination(code string) tea.Cmd {
+	m.destination = code
+	for _, t := range m.tabs {
+		t.board.SetDestinationOnly(m.destinationFor(t.spec))
+	}
+	if m.cfg.StateFile != "" {
+		err := updateState(m.cfg.StateFile, func(state *persistentState) {
+			state.DestinationOnly = code
+		})
+		if err != nil {
+			slog.Warn("failed to save destination filter", "error", err)
+		}
+	}
+	return m.startAnimation()
+}
+
+// updateLogView handles keys while the change log is shown
+func (m BoardModel) updateLogView(log *logOverlay, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	total := len(log.events.list(time.Now()))
+	page := logRows(m.termHeight)
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "L", "esc":
+		m.overlays.Pop()
+	case "down", "j":
+		log.offset++
+	case "up", "k":
+		log.offset--
+	case "pgdown", " ":
+		log.offset += page
+	case "pgup":
+		log.offset -= page
+	case "home":
+		log.offset = 0
+	}
+	log.offset = max(0, min(log.offset, total-page))
+	return m, nil
+}
+
+// updateHelpView handles keys while the key list is shown
+func (m BoardModel) updateHelpView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "?", "esc":
+		m.overlays.Pop()
+	}
+	return m, nil
+}
+
+// updateOpsView handles keys while the operations summary is shown
+func (m BoardModel) updateOpsView(ops *opsOverlay, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	total := len(ops.board.AirlineStats())
+	page := opsRows(m.termHeight)
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "O", "esc":
+		m.overlays.Pop()
+	case "down", "j":
+		ops.offset++
+	case "up", "k":
+		ops.offset--
+	case "pgdown", " ":
+		ops.offset += page
+	case "pgup":
+		ops.offset -= page
+	case "home":
+		ops.offset = 0
+	}
+	ops.offset = max(0, min(ops.offset, total-page))
+	return m, nil
+}
+
+// showFetchError shows why a tab's fetch failed. Errors that usually clear
+// up by themselves, like timeouts and server errors, are a quiet note in the
+// status bar until the next successful fetch or toastDuration; persistent
+// errors, and transient ones that keep happening, get the banner
+func (m BoardModel) showFetchError(t *tab, err error, now time.Time) {
+	if api.IsTransient(err) && t.failures < errorBannerAfter {
+		t.board.Toast = err.Error()
+		t.board.ToastUntil = now.Add(toastDuration)
+		return
+	}
+	t.board.Error = err.Error()
+	t.board.Toast = ""
+}
+
+// switchBoard shows a different airport or direction on the current tab
+// The board being replaced is cached so switching back to it is instant
+func (m *BoardModel) switchBoard(spec config.TabSpec) tea.Cmd {
+	t := m.current()
+	if t.spec == spec {
+		return nil
+	}
+	m.stopPageEntry()
+	m.stashBoard(t)
+	t.spec = spec
+	t.err = nil
+	m.loadBoard(t)
+	return tea.Batch(m.refresh(t), m.startAnimation())
+}
+
+// recordSpend updates the API spend totals and shows them, and the calls
+// of the hourly budget used, on every board
+func (m BoardModel) recordSpend() {
+	m.spend.record(time.Now())
+	status := m.spend.status()
+	budget := m.budget.Status()
+	for _, t := range m.tabs {
+		t.board.APISpend = status
+		t.board.APIBudget = budget
+	}
+}
+
+// UsageSummary describes the API requests made and their estimated cost, for
+// this session and for the current UTC day
+func (m BoardModel) UsageSummary() string {
+	return m.spend.summary()
+}
+
+// Close tells the service manager the board is stopping and stops the
+// provider's background work, such as polling an ADS-B receiver. Call it once
+// the program has exited
+func (m BoardModel) Close() {
+	m.service.Close()
+	m.mqtt.Close()
+	if closer, ok := m.provider.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// rotating reports whether the current board's pages are rotating, which they
+// don't while the user is navigating, has a flight selected or has a screen
+// open over the board, or while paused from the control socket
+func (m BoardModel) rotating() bool {
+	return time.Now().After(m.rotationPause) && !m.rotationHeld && m.Board().Selected == nil && !m.pageEntry && m.overlays.Len() == 0 && !m.quietPaused
+}
+
+// flagSlowFetch shows in the status bar how long the fetch of msg took if
+// it was slower than SLOW_FETCH_WARNING, and clears the warning otherwise
+func (m BoardModel) flagSlowFetch(t *tab, msg FlightsMsg) {
+	t.board.SlowFetch = 0
+	if m.cfg.SlowFetchWarning > 0 && msg.Elapsed > m.cfg.SlowFetchWarning {
+		slog.Warn("slow fetch", "airport", t.spec.AirportCode, "direction", t.spec.Direction, "elapsed", msg.Elapsed, "pages", msg.Pages)
+		t.board.SlowFetch = msg.Elapsed
+	}
+}
+
+// sendAlerts queues an alert for each event matching the alert filter,
+// attributed to the data source that reported it
+func (m BoardModel) sendAlerts(events []ui.ChangeEvent, source string) {
+	if !m.notifier.Enabled() {
+		return
+	}
+	for _, e := range events {
+		if m.shared(e.Blocked) && m.alerts.match(e) {
+			msg := alertMessage(e)
+			msg.Source = source
+			m.notifier.Send(msg)
+		}
+	}
+}
+
+// toggleLayout switches every board between the wide and compact layouts
+func (m *BoardModel) toggleLayout() tea.Cmd {
+	if m.layout == ui.LayoutCompact {
+		m.layout = ui.LayoutWide
+	} else {
+		m.layout = ui.LayoutCompact
+	}
+	for _, t := range m.tabs {
+		t.board.SetLayoutMode(m.layout)
+	}
+	return m.startAnimation()
+}
+
+// toggleView switches every board to the next view: the timetable, the gate
+// view, then the shuttle view when PRIORITY_DESTINATIONS names destinations
+func (m *BoardModel) toggleView() tea.Cmd {
+	switch {
+	case m.view == ui.ViewFlights:
+		m.view = ui.ViewGates
+	case m.view == ui.ViewGates && len(m.shuttles) > 0:
+		m.view = ui.ViewShuttle
+	default:
+		m.view = ui.ViewFlights
+	}
+	for _, t := range m.tabs {
+		t.board.SetViewMode(m.view)
+	}
+	return m.startAnimation()
+}
+
+// cycleTimeZone switches every board to the next timezone for flight times
+func (m *BoardModel) cycleTimeZone() tea.Cmd {
+	m.timeZone = m.timeZone.Next()
+	for _, t := range m.tabs {
+		t.board.SetTimeZoneMode(m.timeZone)
+	}
+	return m.startAnimation()
+}
+
+// startAnimation schedules an animation tick unless one is already pending
+func (m *BoardModel) startAnimation() tea.Cmd {
+	if m.animating {
+		return nil
+	}
+	if m.blurred {
+		// The ticker starts again once the terminal is focused
+		return nil
+	}
+	m.animating = true
+	return tickAnimation(m.animationInterval())
+}
+
+// scrolling reports whether text on screen scrolls, needing animation ticks
+// after the boards have settled: the footer, or long destinations or remarks
+func (m BoardModel) scrolling() bool {
+	if m.ticker != nil {
+		return m.ticker.Scrolling(m.Board().TickerItems(), m.termWidth)
+	}
+	if m.footer != nil && m.footer.Scrolling(m.termWidth) {
+		return true
+	}
+	if m.isIdle() {
+		return false // The idle clock shows in place of the rows
+	}
+	for _, board := range m.shownBoards() {
+		if board.Scrolling() {
+			return true
+		}
+	}
+	return false
+}
+
+// animationInterval returns how often animations are ticked, faster during
+// a page transition so it ends within a second
+func (m BoardModel) animationInterval() time.Duration {
+	if m.Board().Transitioning() {
+		return min(m.cfg.CharAnimationSpeed, ui.TransitionTick)
+	}
+	return m.cfg.CharAnimationSpeed
+}
+
+// startPageEntry enters page number entry with the given initial digits
+func (m *BoardModel) startPageEntry(digits string) {
+	m.pageEntry = true
+	m.pageInput = digits
+	m.Board().SetPageInput(true, m.pageInput)
+}
+
+// stopPageEntry leaves page number entry
+func (m *BoardModel) stopPageEntry() {
+	m.pageEntry = false
+	m.pageInput = ""
+	m.Board().SetPageInput(false, "")
+}
+
+// updatePageEntry handles keys while a page number is being typed
+// Enter jumps to the typed page (or the first page if nothing was typed)
+func (m BoardModel) updatePageEntry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		page := 1
+		if m.pageInput != "" {
+			page, _ = strconv.Atoi(m.pageInput)
+		}
+		m.stopPageEntry()
+		m.Board().GoToPage(page)
+		m.rotationPause = time.Now().Add(navigationPause)
+	case "esc":
+		m.stopPageEntry()
+	case "backspace":
+		if len(m.pageInput) > 0 {
+			m.pageInput = m.pageInput[:len(m.pageInput)-1]
+		}
+		m.Board().SetPageInput(true, m.pageInput)
+	default:
+		keyStr := msg.String()
+		if len(keyStr) == 1 && keyStr[0] >= '0' && keyStr[0] <= '9' && len(m.pageInput) < maxPageDigits {
+			m.pageInput += keyStr
+		}
+		m.Board().SetPageInput(true, m.pageInput)
+	}
+	return m, nil
+}
+
+// isIdle reports whether the idle clock should be shown instead of the board
+// It isn't while the last fetch came back without data, so the board says
+// the data is unavailable rather than that nothing is scheduled
+func (m BoardModel) isIdle() bool {
+	now := time.Now()
+	if m.cfg.IdleAfter <= 0 || now.Before(m.idleWake) || errors.Is(m.current().err, api.ErrNoData) {
+		return false
+	}
+	return m.Board().EmptyFor(now) > m.cfg.IdleAfter
+}
+
+// updateInterval returns the delay until a tab's next API fetch while it is
+// shown: the provider's own interval if it has one, the night interval while
+// its board is idle, otherwise the interval from the update schedule for the
+// current time in the airport timezone
+func (m BoardModel) updateInterval(t *tab) time.Duration {
+	if d := api.SuggestedInterval(m.provider); d > 0 {
+		return d
+	}
+	now := time.Now()
+	if m.cfg.NightUpdateInterval > 0 && m.cfg.IdleAfter > 0 && t.board.EmptyFor(now) > m.cfg.IdleAfter {
+		return m.cfg.NightUpdateInterval
+	}
+	if len(m.schedule) == 0 && len(m.directions) == 0 {
+		return m.cfg.UpdateInterval
+	}
+
+	local := now.In(t.board.AirportTZ)
+	interval := m.cfg.UpdateInterval
+	if d, ok := m.schedule.IntervalAt(local); ok {
+		interval = d
+	}
+	// Don't sleep past a change of interval or of the direction shown
+	if len(m.schedule) > 0 {
+		interval = min(interval, m.schedule.UntilChange(local))
+	}
+	if len(m.directions) > 0 {
+		interval = min(interval, m.directions.UntilChange(local))
+	}
+	return interval
+}
+
+// navigatePage moves delta pages and pauses automatic rotation
+func (m *BoardModel) navigatePage(delta int) tea.Cmd {
+	if delta > 0 {
+		m.Board().NextPage()
+	} else {
+		m.Board().PrevPage()
+	}
+	m.rotationPause = time.Now().Add(navigationPause)
+	return m.startAnimation()
+}
+
+func (m BoardModel) View() string {
+	// Prompts and panels are layered over the board screen
+	return m.overlays.Render(m.boardScreen(), m.termWidth, m.termHeight)
+}
+
+// boardScreen renders the active board with its tab bar and help text
+func (m BoardModel) boardScreen() string {
+	board := m.Board()
+	if m.ticker != nil {
+		// The ticker is a single line, with no tab bar, help or idle clock
+		return m.tickerLine()
+	}
+	if m.current().loading && board.FlightCount() == 0 && m.quietPaused {
+		return m.withTabBar(board.PausedBanner() + "\n")
+	}
+	if m.current().loading && board.FlightCount() == 0 {
+		return m.withTabBar(loadingText(m.current(), time.Now()) + "\n")
+	}
+	if m.isIdle() {
+		return m.withTabBar(board.RenderIdleClock(time.Now()))
+	}
+	view := board.Render()
+	if m.sideBySide {
+		view = m.renderPanes()
+	}
+	if m.footer != nil {
+		// The custom footer is meant for the audience, kiosk or not
+		return m.withTabBar(view) + "\n" + board.Styles.Footer.Render(m.footer.Render(m.termWidth))
+	}
+	if m.kiosk.locked(time.Now()) {
+		// Nobody is meant to use the keys, so there is no help to show
+		return m.withTabBar(view)
+	}
+	// Add help text at the bottom
+	return m.withTabBar(view) + "\n" + m.helpText()
+}
+
+// keyHelp returns the main keys, each with what it does, as listed in the
+// help text and the '?' overlay
+func (m BoardModel) keyHelp() []string {
+	keys := []string{"'a' to change airport"}
+	if len(m.tabs) > 1 {
+		keys = append(keys, "tab/1-9 to switch board", "'x' to close board")
+	}
+	if m.replay != nil {
+		keys = append(keys, "</> to step through the recording")
+	}
+	return append(keys, "←/→ to change page", "'f' to filter by destination", "'A' for airlines",
+		"'L' for change log", "'O' for airline stats", "'q' to quit")
+}
+
+// helpText returns the key help shown below the board
+func (m BoardModel) helpText() string {
+	return "Press " + strings.Join(m.keyHelp(), " | ")
+}
+
+// reservedLines returns the terminal lines taken around the board by the tab
+// bar and the help text, which wraps on narrow terminals, or the footer,
+// which is kept to one line
+func (m BoardModel) reservedLines() int {
+	help := 1
+	if m.footer == nil && m.kiosk.locked(time.Now()) {
+		help = 0
+	} else if m.footer == nil && m.termWidth > 0 {
+		help = max(1, (lipgloss.Width(m.helpText())+m.termWidth-1)/m.termWidth)
+	}
+	return m.tabBarHeight() + help
+}
+
+// fitBoards tells every board how many terminal lines it can't use, so pages
+// hold only the flights that fit
+func (m BoardModel) fitBoards() {
+	for _, t := range m.tabs {
+		t.board.SetReservedLines(m.reservedLines())
+		if m.sideBySide && t.board.TermWidth != m.boardWidth() {
+			// Opening or closing a board changes every board's share
+			t.board.SetTerminalSize(m.boardWidth(), m.termHeight)
+		}
+	}
+}
+
+// withTabBar prefixes view with the tab bar when there is more than one tab
+func (m BoardModel) withTabBar(view string) string {
+	if len(m.tabs) <= 1 {
+		return view
+	}
+	return ui.RenderTabBar(m.tabLabels(), m.active, m.Board().Styles) + "\n" + view
+}
@@ -0,0 +1,758 @@
+package fids
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestMain discards the board's logs, which would otherwise go to stderr
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// fakeProvider answers every fetch with a copy of its flights
+type fakeProvider struct {
+	flights []models.Flight
+}
+
+func (p *fakeProvider) Name() string { return "Fake" }
+
+func (p *fakeProvider) GetDepartures(ctx context.Context, airportCode string, opts api.FetchOptions) (api.FetchResult, error) {
+	flights := append([]models.Flight(nil), p.flights...)
+	return api.FetchResult{Flights: flights, Pages: 1, Total: len(flights)}, nil
+}
+
+func (p *fakeProvider) GetArrivals(ctx context.Context, airportCode string, opts api.FetchOptions) (api.FetchResult, error) {
+	return p.GetDepartures(ctx, airportCode, opts)
+}
+
+// modelFlights returns n departures from JFK to LAX, hourly from an hour
+// after now
+func modelFlights(n int, now time.Time) []models.Flight {
+	flights := make([]models.Flight, n)
+	for i := range flights {
+		flights[i] = models.Flight{
+			ID:                 fmt.Sprintf("AAL%d-test", 100+i),
+			FlightNumber:       fmt.Sprintf("AA %d", 100+i),
+			AirlineCode:        "AA",
+			Direction:          models.Departure,
+			OriginCode:         "JFK",
+			DestinationCode:    "LAX",
+			DestinationCity:    "Los Angeles",
+			ScheduledDeparture: now.Add(time.Duration(i+1) * time.Hour).Truncate(time.Minute),
+			Gate:               "B2",
+			Status:             models.StatusOnTime,
+		}
+	}
+	return flights
+}
+
+// newTestModel returns a JFK departures board of the provider's flights on
+// an 80 by 24 terminal, without blinking animations, once the first fetch
+// has been applied and has settled
+func newTestModel(t *testing.T, provider api.FlightDataProvider, opts ...Option) BoardModel {
+	t.Helper()
+	m := newLoadingModel(t, provider, opts...)
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	return settleModel(t, m)
+}
+
+// newLoadingModel returns the board of newTestModel before its first fetch
+// has returned
+func newLoadingModel(t *testing.T, provider api.FlightDataProvider, opts ...Option) BoardModel {
+	t.Helper()
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	opts = append([]Option{WithConfig(cfg), WithAirport("JFK"), WithProvider(provider)}, opts...)
+	m, err := New(opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(m.Close)
+	return update(t, m, tea.WindowSizeMsg{Width: 80, Height: 24})
+}
+
+// update applies msg to m, dropping the commands it returns
+func update(t *testing.T, m BoardModel, msg tea.Msg) BoardModel {
+	t.Helper()
+	model, _ := m.Update(msg)
+	return model.(BoardModel)
+}
+
+// press applies a key press to m, dropping the commands it returns
+func press(t *testing.T, m BoardModel, key string) BoardModel {
+	t.Helper()
+	return update(t, m, keyMsg(key))
+}
+
+// keyMsg returns the key message bubbletea sends for key, such as "a",
+// "esc" or "ctrl+c"
+func keyMsg(key string) tea.KeyMsg {
+	for keyType, name := range map[tea.KeyType]string{
+		tea.KeyEnter: "enter", tea.KeyEsc: "esc", tea.KeyTab: "tab", tea.KeyCtrlC: "ctrl+c",
+		tea.KeyCtrlR: "ctrl+r", tea.KeyBackspace: "backspace", tea.KeyLeft: "left", tea.KeyRight: "right",
+	} {
+		if key == name {
+			return tea.KeyMsg{Type: keyType}
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// settleModel ticks m's animations until its boards stop animating
+func settleModel(t *testing.T, m BoardModel) BoardModel {
+	t.Helper()
+	for range 100 {
+		if !m.Board().IsAnimating() {
+			return m
+		}
+		m = update(t, m, TickAnimationMsg(time.Now()))
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("board still animating after 100 ticks")
+	return m
+}
+
+// sameSecond runs f until it starts and ends within one second of the clock,
+// so the frames it compares show the same time
+func sameSecond(t *testing.T, f func()) {
+	t.Helper()
+	for range 3 {
+		start := time.Now().Truncate(time.Second)
+		f()
+		if time.Now().Truncate(time.Second).Equal(start) {
+			return
+		}
+	}
+	t.Fatal("the clock kept ticking over a second")
+}
+
+func TestOverlaysRestoreFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		open  string
+		close []string
+	}{
+		{"airport prompt", "a", []string{"esc"}},
+		{"destination prompt", "f", []string{"esc"}},
+		{"airline list", "A", []string{"esc"}},
+		{"change log", "L", []string{"L"}},
+		{"operations summary", "O", []string{"O"}},
+		{"key help", "?", []string{"?"}},
+		// Typing into the prompt doesn't change the board beneath it
+		{"typed and cancelled prompt", "a", []string{"L", "A", "X", "esc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, &fakeProvider{flights: modelFlights(5, time.Now())})
+			sameSecond(t, func() {
+				before := m.View()
+				opened := press(t, m, tt.open)
+				if opened.overlays.Len() != 1 {
+					t.Fatalf("%q opened %d overlays, want 1", tt.open, opened.overlays.Len())
+				}
+				if over := opened.View(); over == before {
+					t.Errorf("%q left the frame as it was", tt.open)
+				}
+				closed := opened
+				for _, key := range tt.close {
+					closed = press(t, closed, key)
+				}
+				if closed.overlays.Len() != 0 {
+					t.Fatalf("%v left %d overlays open", tt.close, closed.overlays.Len())
+				}
+				if after := closed.View(); after != before {
+					t.Errorf("frame after closing differs:\n%s\nwant\n%s", after, before)
+				}
+			})
+		})
+	}
+}
+
+func TestWindowSizeFitsColumns(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+	columns := func(m BoardModel) string {
+		var names []string
+		for _, col := range m.Board().Layout.Columns() {
+			names = append(names, col.Name)
+		}
+		return strings.Join(names, " ")
+	}
+	for _, tt := range []struct {
+		width   int
+		columns string
+	}{
+		{60, "S FLIGHT TIME DESTINATION GATE"},
+		{120, "S FLIGHT TIME DESTINATION GATE REMARKS"},
+		{40, "S FLIGHT TIME DEST"},
+	} {
+		m = update(t, m, tea.WindowSizeMsg{Width: tt.width, Height: 24})
+		if got := columns(m); got != tt.columns {
+			t.Errorf("columns on a %d column terminal %s, want %s", tt.width, got, tt.columns)
+		}
+	}
+}
+
+// quits reports whether cmd quits the program
+func quits(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestCtrlCQuitsEveryMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    []string // Keys pressed before ctrl+c
+		overlays int      // Overlays open once they are
+	}{
+		{"normal mode", nil, 0},
+		{"airport input", []string{"a"}, 1},
+		{"airport input with letters typed", []string{"a", "L", "A"}, 1},
+		{"destination filter", []string{"f"}, 1},
+		{"page entry", []string{"g", "1"}, 0},
+		{"airline list", []string{"A"}, 1},
+		{"change log", []string{"L"}, 1},
+		{"operations summary", []string{"O"}, 1},
+		{"key help", []string{"?"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+			for _, key := range tt.setup {
+				m = press(t, m, key)
+			}
+			if m.overlays.Len() != tt.overlays || m.pageEntry != (tt.name == "page entry") {
+				t.Fatalf("%v opened %d overlays, page entry %v", tt.setup, m.overlays.Len(), m.pageEntry)
+			}
+			if _, cmd := m.Update(keyMsg("ctrl+c")); !quits(cmd) {
+				t.Error("ctrl+c didn't quit")
+			}
+		})
+	}
+}
+
+func TestQTypedInAirportInput(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+	m = press(t, m, "a")
+	model, cmd := m.Update(keyMsg("q"))
+	if quits(cmd) {
+		t.Fatal("q quit from the airport input")
+	}
+	m = model.(BoardModel)
+	prompt, ok := m.overlays.Top().(*promptOverlay)
+	if !ok || prompt.input != "Q" {
+		t.Errorf("prompt after typing q: %+v, want input Q", m.overlays.Top())
+	}
+}
+
+func TestProviderRefreshInterval(t *testing.T) {
+	cfg := config.Default()
+	cfg.APIKey = "test-key"
+	tests := []struct {
+		name     string
+		source   string
+		fallback string
+		want     time.Duration
+	}{
+		{"demo", "demo", "", 20 * time.Second},
+		{"FlightAware", "flightaware", "", cfg.UpdateInterval},
+		{"FlightAware falling back to the demo", "flightaware", "demo", cfg.UpdateInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceCfg := *cfg
+			sourceCfg.DataSource = tt.source
+			sourceCfg.FallbackSource = tt.fallback
+			provider, err := NewProvider(&sourceCfg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := New(WithConfig(&sourceCfg), WithAirport("JFK"), WithProvider(provider))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer m.Close()
+			// The refresh isn't run, so nothing is fetched
+			now := time.Now()
+			m.refresh(m.current())
+			if got := m.Board().NextUpdate.Sub(now).Round(time.Second); got != tt.want {
+				t.Errorf("next update in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchErrorPresentation(t *testing.T) {
+	unavailable := &api.APIError{Source: "FlightAware", StatusCode: 503, Message: "FlightAware API error (status 503)"}
+	unauthorized := &api.APIError{Source: "FlightAware", StatusCode: 401, Message: "FlightAware API error (status 401)"}
+	failed := func(m BoardModel, err error) FlightsMsg {
+		return FlightsMsg{Tab: m.current().id, Err: err, spec: m.current().spec}
+	}
+	shown := func(m BoardModel) string {
+		board := m.Board()
+		return fmt.Sprintf("toast %q, banner %q", board.Toast, board.Error)
+	}
+
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+	// Transient errors are a toast until they keep happening
+	for i := 1; i < errorBannerAfter; i++ {
+		m = update(t, m, failed(m, unavailable))
+		if want := fmt.Sprintf("toast %q, banner %q", unavailable.Message, ""); shown(m) != want {
+			t.Fatalf("after %d transient errors: %s, want %s", i, shown(m), want)
+		}
+	}
+	m = update(t, m, failed(m, unavailable))
+	if want := fmt.Sprintf("toast %q, banner %q", "", unavailable.Message); shown(m) != want {
+		t.Errorf("after %d transient errors: %s, want %s", errorBannerAfter, shown(m), want)
+	}
+
+	// A successful fetch clears both and starts the count again
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	if want := `toast "", banner ""`; shown(m) != want {
+		t.Errorf("after a successful fetch: %s, want %s", shown(m), want)
+	}
+	m = update(t, m, failed(m, unavailable))
+	if !strings.HasPrefix(shown(m), "toast \"FlightAware") {
+		t.Errorf("transient error after a success: %s, want a toast", shown(m))
+	}
+
+	// Persistent errors get the banner straight away, and replace the toast
+	m = update(t, m, failed(m, unauthorized))
+	if want := fmt.Sprintf("toast %q, banner %q", "", unauthorized.Message); shown(m) != want {
+		t.Errorf("persistent error: %s, want %s", shown(m), want)
+	}
+}
+
+// slowProvider answers from its flights after a delay, like a slow API
+type slowProvider struct {
+	fakeProvider
+	delay time.Duration
+}
+
+func (p *slowProvider) GetDepartures(ctx context.Context, airportCode string, opts api.FetchOptions) (api.FetchResult, error) {
+	time.Sleep(p.delay)
+	return p.fakeProvider.GetDepartures(ctx, airportCode, opts)
+}
+
+func (p *slowProvider) GetArrivals(ctx context.Context, airportCode string, opts api.FetchOptions) (api.FetchResult, error) {
+	return p.GetDepartures(ctx, airportCode, opts)
+}
+
+// TestSlowFetchWarning fetches through a provider with an injected delay,
+// checking a fetch over SLOW_FETCH_WARNING is flagged and the next one under
+// it clears the flag
+func TestSlowFetchWarning(t *testing.T) {
+	const threshold = 40 * time.Millisecond
+	provider := &slowProvider{fakeProvider: fakeProvider{flights: modelFlights(3, time.Now())}}
+	m := newTestModel(t, provider)
+	m.cfg.SlowFetchWarning = threshold
+	fetch := func() BoardModel {
+		return update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	}
+
+	for _, tt := range []struct {
+		delay   time.Duration
+		flagged bool
+	}{
+		{2 * threshold, true},
+		{0, false},
+		// Flagging is off with a zero threshold
+		{2 * threshold, false},
+	} {
+		if !tt.flagged && tt.delay > 0 {
+			m.cfg.SlowFetchWarning = 0
+		}
+		provider.delay = tt.delay
+		m = fetch()
+		slow := m.Board().SlowFetch
+		if flagged := slow > 0; flagged != tt.flagged || (flagged && slow < tt.delay) {
+			t.Errorf("fetch taking %s flagged as taking %s, want flagged %v", tt.delay, slow, tt.flagged)
+		}
+	}
+}
+
+// TestBlinkTiming checks that BLINK_PHASE and BLINK_DURATION reach the board
+func TestBlinkTiming(t *testing.T) {
+	cfg := config.Default()
+	cfg.BlinkPhase = 250 * time.Millisecond
+	cfg.BlinkDuration = time.Second
+	m, err := New(WithConfig(cfg), WithAirport("JFK"), WithProvider(&fakeProvider{}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer m.Close()
+	want := ui.AnimationTiming{Phase: 250 * time.Millisecond, Duration: time.Second}
+	if got := m.Board().Animation; got != want {
+		t.Errorf("board animation = %+v, want %+v", got, want)
+	}
+}
+
+// TestBudgetFavorsShownBoard checks that with MAX_CALLS_PER_HOUR only enough
+// for the board on screen, a background tab's fetch is deferred while the
+// board's goes ahead, and the status bar shows the budget
+func TestBudgetFavorsShownBoard(t *testing.T) {
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.MaxPages = 1
+	cfg.MaxCallsPerHour = 6 // Fetches of the board shown every 10 minutes
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())}, WithConfig(cfg),
+		WithTabs(config.TabSpec{AirportCode: "JFK"}, config.TabSpec{AirportCode: "LAX"}))
+
+	if cmd := m.startFetch(m.tabs[1], time.Now()); cmd != nil {
+		t.Error("background tab fetched with the budget kept for the board shown")
+	}
+	cmd := m.startFetch(m.current(), time.Now())
+	if cmd == nil {
+		t.Fatal("board shown was deferred")
+	}
+	m = update(t, m, cmd())
+	if got, want := m.Board().APIBudget, "API 1/6 calls/h, 1 deferred"; got != want {
+		t.Errorf("status bar budget = %q, want %q", got, want)
+	}
+}
+
+// TestTickerView checks that the ticker view is a single line of the
+// terminal's width, scrolled a cell on each animation tick while its flights
+// don't fit, and swapped on the clock's ticks with TICKER_SWAP set
+func TestTickerView(t *testing.T) {
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.View = "ticker"
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(5, time.Now())}, WithConfig(cfg))
+
+	line := m.View()
+	if strings.Contains(line, "\n") || ansi.StringWidth(line) != 80 {
+		t.Fatalf("ticker view %q isn't one line of 80 cells", line)
+	}
+	model, cmd := m.Update(TickAnimationMsg(time.Now()))
+	m = model.(BoardModel)
+	if cmd == nil {
+		t.Error("animation ticks stopped while the ticker scrolls")
+	}
+	if got, want := ansi.Strip(m.View()), ansi.Strip(line)[1:]; !strings.HasPrefix(got, want) {
+		t.Errorf("after a tick the ticker shows %q, want it a cell along", got)
+	}
+
+	cfg.TickerSwap = time.Minute
+	m = newTestModel(t, &fakeProvider{flights: modelFlights(5, time.Now())}, WithConfig(cfg))
+	first := ansi.Strip(m.View())
+	now := time.Now()
+	m = update(t, m, TickClockMsg(now))
+	m = update(t, m, TickAnimationMsg(now))
+	if got := ansi.Strip(m.View()); got != first {
+		t.Errorf("swapping ticker moved on a tick before TICKER_SWAP: %q", got)
+	}
+	m = update(t, m, TickClockMsg(now.Add(time.Minute)))
+	if got := ansi.Strip(m.View()); got == first || strings.Contains(got, "AA 100") {
+		t.Errorf("swapping ticker still shows %q after TICKER_SWAP", got)
+	}
+}
+
+// TestPastedAirportCode checks that codes pasted at the airport prompt, as
+// bracketed paste or as many runes at once, are sanitized and shown
+// straight away when whole, and that typing fills the prompt a character at
+// a time up to the longest code
+func TestPastedAirportCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   tea.KeyMsg
+		shown string // Airport shown straight away, or "" to stay at the prompt
+		input string // Then the prompt's input
+	}{
+		{"pasted with a newline", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("lax\n"), Paste: true}, "LAX", ""},
+		{"pasted with spaces", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" KLAX "), Paste: true}, "KLAX", ""},
+		{"many runes without bracketed paste", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ord")}, "ORD", ""},
+		{"pasted part of a code", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("la"), Paste: true}, "", "LA"},
+		{"pasted too long", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("los angeles"), Paste: true}, "", "LOSA"},
+		{"typed digit", keyMsg("2"), "", "2"},
+		{"typed punctuation", keyMsg("-"), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+			m = press(t, m, "a")
+			m = update(t, m, tt.msg)
+			if tt.shown != "" {
+				if m.overlays.Len() != 0 || m.current().spec.AirportCode != tt.shown {
+					t.Errorf("board shows %s with %d overlays, want %s shown", m.current().spec.AirportCode, m.overlays.Len(), tt.shown)
+				}
+				return
+			}
+			prompt, ok := m.overlays.Top().(*promptOverlay)
+			if !ok || prompt.input != tt.input || m.current().spec.AirportCode != "JFK" {
+				t.Errorf("prompt %+v on %s, want input %q still at JFK", m.overlays.Top(), m.current().spec.AirportCode, tt.input)
+			}
+		})
+	}
+}
+
+// TestDestinationInput checks that the destination prompt sanitizes its
+// codes as the airport prompt does
+func TestDestinationInput(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+	m = press(t, m, "f")
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" lax\n"), Paste: true})
+	if m.overlays.Len() != 0 || m.destination != "LAX" {
+		t.Errorf("pasting lax filtered on %q with %d overlays, want LAX", m.destination, m.overlays.Len())
+	}
+
+	// Typed codes go through the same sanitizer, up to four characters
+	m = press(t, m, "f")
+	for _, key := range []string{"k", "-", "s", "f", "o", "x"} {
+		m = press(t, m, key)
+	}
+	if prompt, ok := m.overlays.Top().(*destinationOverlay); !ok || prompt.input != "KSFO" {
+		t.Fatalf("destination prompt %+v, want input KSFO", m.overlays.Top())
+	}
+	m = press(t, m, "enter")
+	if m.destination != "KSFO" {
+		t.Errorf("destination %q after enter, want KSFO", m.destination)
+	}
+
+	// A code too short leaves the filter as it was, and no code clears it
+	m = press(t, m, "f")
+	m = press(t, m, "l")
+	m = press(t, m, "enter")
+	if m.destination != "KSFO" {
+		t.Errorf("destination %q after entering L, want KSFO kept", m.destination)
+	}
+	m = press(t, m, "f")
+	m = press(t, m, "enter")
+	if m.destination != "" {
+		t.Errorf("destination %q after entering no code, want none", m.destination)
+	}
+}
+
+// TestDestinationRestored checks that the destination filter chosen with 'f'
+// is saved in the state file and restored on the next run, unless
+// DESTINATION_ONLY is set, and that route tabs keep their route
+func TestDestinationRestored(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	flights := modelFlights(4, time.Now())
+	flights[1].DestinationCode = "BOS"
+	flights[2].DestinationCode = "SFO"
+	flights[3].DestinationCode = "BOS"
+	provider := &fakeProvider{flights: flights}
+	newModel := func(destination string) BoardModel {
+		cfg := config.Default()
+		cfg.BlinkPhase = 0
+		cfg.BlinkDuration = 0
+		cfg.StateFile = stateFile
+		cfg.DestinationOnly = destination
+		return newTestModel(t, provider, WithConfig(cfg), WithTabs(
+			config.TabSpec{AirportCode: "JFK", Direction: models.Departure},
+			config.TabSpec{AirportCode: "JFK", Destination: "ORD", Direction: models.Departure},
+		))
+	}
+
+	m := newModel("")
+	m = press(t, m, "f")
+	for _, key := range []string{"b", "o", "s", "enter"} {
+		m = press(t, m, key)
+	}
+	state, err := loadState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.DestinationOnly != "BOS" {
+		t.Fatalf("state file has destination %q, want BOS", state.DestinationOnly)
+	}
+
+	for _, tt := range []struct {
+		configured string
+		want       string
+		count      int
+	}{
+		{"", "BOS", 2},    // The filter chosen last run
+		{"sfo", "SFO", 1}, // DESTINATION_ONLY wins
+	} {
+		m := newModel(tt.configured)
+		if m.destination != tt.want || m.Board().DestinationOnly != tt.want || m.Board().FlightCount() != tt.count {
+			t.Errorf("restarted with DESTINATION_ONLY=%q: filtered to %q showing %d flights, want %d to %s",
+				tt.configured, m.Board().DestinationOnly, m.Board().FlightCount(), tt.count, tt.want)
+		}
+		if route := m.tabs[1].board.DestinationOnly; route != "ORD" {
+			t.Errorf("restarted with DESTINATION_ONLY=%q: route tab filtered to %q, want ORD", tt.configured, route)
+		}
+	}
+	if state, _ := loadState(stateFile); state.DestinationOnly != "BOS" {
+		t.Errorf("DESTINATION_ONLY replaced the saved filter with %q", state.DestinationOnly)
+	}
+}
+
+// TestNoDataNotIdle checks that a board left empty by a fetch without data
+// says the data is unavailable, where one with nothing scheduled goes idle
+func TestNoDataNotIdle(t *testing.T) {
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.IdleAfter = time.Millisecond
+	m := newTestModel(t, &fakeProvider{}, WithConfig(cfg))
+	time.Sleep(2 * cfg.IdleAfter)
+	if !m.isIdle() {
+		t.Fatal("board with nothing scheduled isn't idle")
+	}
+
+	err := fmt.Errorf("%w: FlightAware sent no scheduled_departures", api.ErrNoData)
+	m = update(t, m, FlightsMsg{Tab: m.current().id, Err: err, spec: m.current().spec})
+	if m.isIdle() {
+		t.Error("board idle after a fetch without data")
+	}
+	m.Board().NextUpdate = time.Now().Add(time.Minute) // Shows the status bar
+	if screen := ansi.Strip(m.View()); !strings.Contains(screen, "data unavailable") || strings.Contains(screen, "NO SCHEDULED") {
+		t.Errorf("board without data doesn't say so:\n%s", screen)
+	}
+
+	// The next fetch with its list brings the idle clock back
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	if !m.isIdle() {
+		t.Error("board not idle again once a fetch came back with nothing scheduled")
+	}
+}
+
+// TestKioskLock drives a kiosk's keyboard: keys do nothing while it is
+// locked, only the whole unlock sequence typed in a row restores them, and
+// they lock again once the unlock runs out
+func TestKioskLock(t *testing.T) {
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.Kiosk = true
+	cfg.KioskUnlock = "admin"
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(30, time.Now())}, WithConfig(cfg))
+	locked := func(m BoardModel) bool { return m.kiosk.locked(time.Now()) }
+	if !locked(m) || strings.Contains(ansi.Strip(m.View()), "Press ") {
+		t.Fatal("kiosk starts unlocked, or shows the key help")
+	}
+
+	// Keys that would change the board are inert
+	for _, key := range []string{"right", "a", "f", "L", "enter"} {
+		m = press(t, m, key)
+	}
+	if m.overlays.Len() != 0 || m.Board().CurrentPage != 0 {
+		t.Fatalf("keys acted on a locked kiosk: %d overlays, page %d", m.overlays.Len(), m.Board().CurrentPage)
+	}
+
+	// Partial and mistyped sequences leave it locked, even when the right
+	// keys follow a key that isn't a character
+	for _, keys := range [][]string{
+		{"a", "d", "m"},
+		{"a", "d", "x", "m", "i", "n"},
+		{"a", "d", "m", "left", "i", "n"},
+		{"A", "D", "M", "I", "N"},
+	} {
+		for _, key := range keys {
+			m = press(t, m, key)
+		}
+		if !locked(m) {
+			t.Fatalf("kiosk unlocked by %q", keys)
+		}
+		m = press(t, m, "esc")
+	}
+
+	// The sequence unlocks it, even after a false start
+	for _, key := range []string{"a", "a", "d", "m", "i", "n"} {
+		m = press(t, m, key)
+	}
+	if locked(m) || !strings.Contains(ansi.Strip(m.View()), "Press ") {
+		t.Fatal("kiosk still locked after typing the sequence")
+	}
+	m = press(t, m, "right")
+	m = press(t, m, "a")
+	if m.overlays.Len() != 1 || m.Board().CurrentPage != 1 {
+		t.Fatalf("keys inert once unlocked: %d overlays, page %d", m.overlays.Len(), m.Board().CurrentPage)
+	}
+
+	// Still unlocked just before the unlock runs out, locked again after,
+	// with the prompt closed
+	m = update(t, m, TickClockMsg(m.kiosk.until.Add(-time.Second)))
+	if m.overlays.Len() != 1 {
+		t.Error("prompt closed before the unlock ran out")
+	}
+	m = update(t, m, TickClockMsg(m.kiosk.until))
+	if m.kiosk.unlocked || m.overlays.Len() != 0 {
+		t.Errorf("kiosk not locked again after %s: %d overlays", kioskUnlockDuration, m.overlays.Len())
+	}
+	m = press(t, m, "a")
+	if m.overlays.Len() != 0 {
+		t.Error("keys act once the kiosk has locked again")
+	}
+}
+
+// TestRefreshKeepsPlace checks that a refresh adding earlier flights keeps
+// the reader's flights in view while they page or select, and leaves a
+// rotating board's page where it is, as it moves on anyway
+func TestRefreshKeepsPlace(t *testing.T) {
+	now := time.Now()
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.FlightsPerPage = 3
+	earlier := modelFlights(3, now)
+	for i := range earlier {
+		earlier[i].ID = fmt.Sprintf("DAL%d-test", 200+i)
+		earlier[i].FlightNumber = fmt.Sprintf("DL %d", 200+i)
+		earlier[i].ScheduledDeparture = now.Add(time.Duration(10+i) * time.Minute).Truncate(time.Minute)
+	}
+	var provider *fakeProvider
+	newBoard := func() BoardModel {
+		t.Helper()
+		provider = &fakeProvider{flights: modelFlights(9, now)}
+		return newTestModel(t, provider, WithConfig(cfg))
+	}
+	refresh := func(m BoardModel) BoardModel {
+		t.Helper()
+		provider.flights = append(append([]models.Flight(nil), earlier...), modelFlights(9, now)...)
+		m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+		return settleModel(t, m)
+	}
+	firstOnPage := func(m BoardModel) string {
+		return m.Board().GetCurrentPageFlights()[0].Flight.FlightNumber
+	}
+
+	// Turned to the second page by hand
+	m := newBoard()
+	m = press(t, m, "right")
+	m = refresh(m)
+	if m.Board().CurrentPage != 2 || firstOnPage(m) != "AA 103" {
+		t.Errorf("paged board on page %d starting with %s after a refresh, want AA 103 kept in view", m.Board().CurrentPage, firstOnPage(m))
+	}
+
+	// With a flight selected
+	m = newBoard()
+	m.Board().CurrentPage = 1
+	m.Board().Select(4)
+	m = refresh(m)
+	if selected := m.Board().Selected; selected == nil || selected.Flight.FlightNumber != "AA 104" || m.Board().CurrentPage != 2 {
+		t.Errorf("selection lost or out of view after a refresh, on page %d", m.Board().CurrentPage)
+	}
+
+	// Rotating on its own
+	m = newBoard()
+	m.Board().CurrentPage = 1
+	m = refresh(m)
+	if m.Board().CurrentPage != 1 || firstOnPage(m) != "AA 100" {
+		t.Errorf("rotating board on page %d starting with %s after a refresh, want page 1 left alone", m.Board().CurrentPage, firstOnPage(m))
+	}
+}
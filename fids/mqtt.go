@@ -0,0 +1,113 @@
+package fids
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/mqtt"
+	"fids-tui/payload"
+	"fids-tui/ui"
+)
+
+// eventTypes name change kinds in event topics, e.g. fids/JFK/events/gate
+var eventTypes = map[ui.ChangeKind]string{
+	ui.ChangeGate:     "gate",
+	ui.ChangeStatus:   "status",
+	ui.ChangeEstimate: "estimate",
+	ui.ChangeBaggage:  "baggage",
+	ui.ChangeRetimed:  "retimed",
+}
+
+// newMQTTPublisher connects to the configured broker in the background, or
+// returns nil when MQTT_URL is unset
+func newMQTTPublisher(cfg *config.Config) (*mqtt.Publisher, error) {
+	if cfg.MQTTURL == "" {
+		return nil, nil
+	}
+	if err := mqtt.ValidateTopic(cfg.MQTTTopicPrefix); err != nil {
+		return nil, fmt.Errorf("MQTT_TOPIC_PREFIX: %w", err)
+	}
+	publisher, err := mqtt.NewPublisher(mqtt.Config{URL: cfg.MQTTURL, Username: cfg.MQTTUsername, Password: cfg.MQTTPassword})
+	if err != nil {
+		return nil, fmt.Errorf("MQTT_URL: %w", err)
+	}
+	return publisher, nil
+}
+
+// flightsTopic returns the topic a board's flights are published to:
+// <prefix>/<airport>/flights for departures, /arrivals for arrivals and
+// /routes/<destination> for route boards, so boards don't overwrite each
+// other's retained message
+func (m BoardModel) flightsTopic(spec config.TabSpec) string {
+	base := m.cfg.MQTTTopicPrefix + "/" + spec.AirportCode
+	switch {
+	case spec.Destination != "":
+		return base + "/routes/" + spec.Destination
+	case spec.Direction == models.Arrival:
+		return base + "/arrivals"
+	default:
+		return base + "/flights"
+	}
+}
+
+// boardMessage returns the message describing the flights of t's board and
+// where they came from, as published over MQTT and exported with -export
+func (m BoardModel) boardMessage(t *tab) payload.Board {
+	provenance := t.board.Provenance
+	return payload.Board{
+		SchemaVersion: payload.SchemaVersion,
+		Airport:       t.spec.AirportCode,
+		Direction:     payload.DirectionName(t.spec.Direction),
+		Destination:   t.spec.Destination,
+		Source:        provenance.Source,
+		FetchedAt:     provenance.FetchedAt,
+		Simulated:     provenance.Simulated,
+		Flights:       payload.NewFlights(m.sharedFlights(t.board.Flights())),
+	}
+}
+
+// publishUpdate publishes the flights of t's board as a retained message,
+// and each change event on its own, after a successful fetch
+func (m BoardModel) publishUpdate(t *tab, events []ui.ChangeEvent) {
+	if m.mqtt == nil {
+		return
+	}
+	provenance := t.board.Provenance
+	message, err := json.Marshal(m.boardMessage(t))
+	if err != nil {
+		slog.Warn("failed to encode flights for MQTT", "error", err)
+		return
+	}
+	m.mqtt.Publish(mqtt.Message{Topic: m.flightsTopic(t.spec), Payload: message, Retain: true})
+
+	for _, e := range events {
+		if !m.shared(e.Blocked) {
+			continue
+		}
+		kind := eventTypes[e.Kind]
+		message, err := json.Marshal(payload.Event{
+			SchemaVersion: payload.SchemaVersion,
+			Time:          e.Time,
+			Airport:       e.AirportCode,
+			Flight:        e.FlightNumber,
+			FlightID:      e.FlightID,
+			Scheduled:     e.Scheduled,
+			Day:           e.Day,
+			Place:         e.Place,
+			Type:          kind,
+			Old:           e.Old,
+			New:           e.New,
+			Description:   e.Description(),
+			Source:        provenance.Source,
+		})
+		if err != nil {
+			continue
+		}
+		topic := strings.Join([]string{m.cfg.MQTTTopicPrefix, e.AirportCode, "events", kind}, "/")
+		m.mqtt.Publish(mqtt.Message{Topic: topic, Payload: message})
+	}
+}
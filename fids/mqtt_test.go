@@ -0,0 +1,72 @@
+package fids
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/mqtt/mqtttest"
+	"fids-tui/payload"
+)
+
+// TestPublishUpdate fetches through a board publishing to a mock broker,
+// checking the retained flights message of each fetch and its change events
+func TestPublishUpdate(t *testing.T) {
+	broker, err := mqtttest.NewBroker()
+	if err != nil {
+		t.Fatalf("NewBroker: %v", err)
+	}
+	defer broker.Close()
+	next := func() mqtttest.Message {
+		t.Helper()
+		msg, err := broker.Next(5 * time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg
+	}
+
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.MQTTURL = broker.URL
+	cfg.MQTTTopicPrefix = "home/fids"
+	provider := &fakeProvider{flights: modelFlights(3, time.Now())}
+	m := newTestModel(t, provider, WithConfig(cfg))
+
+	msg := next()
+	var board payload.Board
+	if err := json.Unmarshal(msg.Payload, &board); err != nil {
+		t.Fatalf("flights message: %v", err)
+	}
+	if msg.Topic != "home/fids/JFK/flights" || !msg.Retain || board.Airport != "JFK" || board.Direction != "departure" || len(board.Flights) != 3 {
+		t.Errorf("flights message on %s retained %v = %+v, want the 3 JFK departures retained", msg.Topic, msg.Retain, board)
+	}
+
+	// A gate change publishes the flights again, then the change
+	provider.flights[1].Gate = "C7"
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	if msg := next(); msg.Topic != "home/fids/JFK/flights" || !msg.Retain {
+		t.Errorf("message after the change on %s retained %v, want the flights", msg.Topic, msg.Retain)
+	}
+	msg = next()
+	var event payload.Event
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		t.Fatalf("event message: %v", err)
+	}
+	if msg.Topic != "home/fids/JFK/events/gate" || msg.Retain || event.Type != "gate" || event.Flight != "AA 101" || event.Old != "B2" || event.New != "C7" {
+		t.Errorf("event on %s retained %v = %+v, want AA 101 moved from B2 to C7", msg.Topic, msg.Retain, event)
+	}
+	if _, err := broker.Next(100 * time.Millisecond); err == nil {
+		t.Error("published more than the flights and the gate change")
+	}
+}
+
+// TestMQTTInert checks that without MQTT_URL the board has no publisher
+func TestMQTTInert(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{flights: modelFlights(3, time.Now())})
+	if m.mqtt != nil {
+		t.Error("board without MQTT_URL has a publisher")
+	}
+}
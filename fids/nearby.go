@@ -0,0 +1,50 @@
+package fids
+
+import (
+	"fmt"
+	"strings"
+
+	"fids-tui/api"
+)
+
+// emptyFetchesForHint is how many fetches in a row must find no flights
+// before nearby airports are suggested
+const emptyFetchesForHint = 3
+
+// nearbySuggestions is the number of nearby airports suggested at most
+const nearbySuggestions = 3
+
+// suggestNearby counts the fetches of t's board that found no flights and,
+// on the third in a row, suggests airports nearby with scheduled service in
+// the board's hint line, once per run of empty fetches. Route and
+// interleaved boards are empty for other reasons and never get the hint
+func (m BoardModel) suggestNearby(t *tab, flights int) {
+	if flights > 0 {
+		t.empty, t.hintShown = 0, false
+		t.board.Hint = ""
+		return
+	}
+	t.empty++
+	if t.empty < emptyFetchesForHint || t.hintShown || t.spec.Destination != "" || t.spec.Merged != "" {
+		return
+	}
+	t.hintShown = true
+	t.board.Hint = nearbyHint(t.spec.AirportCode, t.board.Direction.String(), api.NearbyServiceAirports(t.spec.AirportCode, nearbySuggestions))
+}
+
+// nearbyHint returns the hint suggesting nearby airports, e.g. "No scheduled
+// departures at FRG — try ISP (17mi) or JFK (21mi)", or "" if none are near
+func nearbyHint(code, direction string, nearby []api.NearbyAirport) string {
+	if len(nearby) == 0 {
+		return ""
+	}
+	names := make([]string, len(nearby))
+	for i, airport := range nearby {
+		names[i] = fmt.Sprintf("%s (%dmi)", airport.Code, airport.Miles)
+	}
+	try := names[0]
+	if len(names) > 1 {
+		try = strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+	}
+	return fmt.Sprintf("No scheduled %s at %s — try %s", strings.ToLower(direction), code, try)
+}
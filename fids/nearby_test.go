@@ -0,0 +1,69 @@
+package fids
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestNearbyHint fetches an empty board of a field without scheduled
+// service, checking that the hint suggesting airports nearby shows on the
+// third empty fetch in a row only, stays dismissed, and goes with flights
+func TestNearbyHint(t *testing.T) {
+	provider := &fakeProvider{}
+	m := newTestModel(t, provider, WithAirport("FRG")) // The first empty fetch
+	fetch := func(m BoardModel) BoardModel {
+		t.Helper()
+		return update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	}
+	shown := func(m BoardModel) bool {
+		return strings.Contains(ansi.Strip(m.View()), "No scheduled departures at FRG")
+	}
+	const want = "No scheduled departures at FRG — try ISP (17mi), JFK (20mi) or LGA (24mi)"
+
+	m = fetch(m)
+	if m.Board().Hint != "" || shown(m) {
+		t.Fatalf("hint after two empty fetches: %q", m.Board().Hint)
+	}
+	m = fetch(m)
+	if m.Board().Hint != want || !shown(m) {
+		t.Fatalf("hint after three empty fetches = %q, want %q", m.Board().Hint, want)
+	}
+
+	// Dismissed, it stays so however long the board stays empty
+	m = press(t, m, "esc")
+	for i := range 4 {
+		if m = fetch(m); m.Board().Hint != "" || shown(m) {
+			t.Fatalf("dismissed hint back after %d more empty fetches", i+1)
+		}
+	}
+
+	// Flights clear the count, so the hint only comes back after three more
+	// empty fetches
+	provider.flights = modelFlights(2, time.Now())
+	m = fetch(m)
+	provider.flights = nil
+	for i := 1; i <= 3; i++ {
+		m = fetch(m)
+		if got := m.Board().Hint != ""; got != (i == 3) {
+			t.Errorf("hint %v after %d empty fetches following flights", got, i)
+		}
+	}
+
+	// Never shown alongside flights
+	provider.flights = modelFlights(2, time.Now())
+	if m = fetch(m); m.Board().Hint != "" || shown(m) {
+		t.Errorf("hint %q shown with flights on the board", m.Board().Hint)
+	}
+
+	// Airports with service of their own get no hint
+	m = newTestModel(t, &fakeProvider{})
+	for range 3 {
+		m = fetch(m)
+	}
+	if m.Board().Hint != "" {
+		t.Errorf("empty JFK board hints %q", m.Board().Hint)
+	}
+}
@@ -0,0 +1,134 @@
+package fids
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// promptOverlay asks for an airport code to show
+type promptOverlay struct {
+	input     string           // Letters and digits typed so far
+	direction models.Direction // Board the code will be shown on
+	styles    *ui.SplitFlapStyles
+}
+
+// RenderOver draws the prompt in a modal over the board
+func (p *promptOverlay) RenderOver(base string, width, height int) string {
+	prompt := fmt.Sprintf("Enter airport code (3-4 characters): %s_ [%s]\n\n(tab: departures/arrivals, enter: show, ctrl+t: new tab, esc: cancel)",
+		p.input, strings.ToUpper(p.direction.String()))
+	box := p.styles.Modal.Render(p.styles.Background.Render(p.styles.Text.Render(prompt)))
+	return ui.PlaceModal(base, box, width, height, p.styles)
+}
+
+// destinationOverlay asks for the destination to show departures to
+type destinationOverlay struct {
+	input  string // Letters and digits typed so far
+	styles *ui.SplitFlapStyles
+}
+
+// RenderOver draws the prompt in a modal over the board
+func (d *destinationOverlay) RenderOver(base string, width, height int) string {
+	prompt := fmt.Sprintf("Show departures to (3-4 characters): %s_\n\n(enter: filter, enter with no code: show all, esc: cancel)", d.input)
+	box := d.styles.Modal.Render(d.styles.Background.Render(d.styles.Text.Render(prompt)))
+	return ui.PlaceModal(base, box, width, height, d.styles)
+}
+
+// airlineOverlay lists the airlines on the board to choose which are shown
+type airlineOverlay struct {
+	list   *ui.Checklist
+	styles *ui.SplitFlapStyles
+}
+
+// RenderOver draws the airline list in a modal over the board
+func (a *airlineOverlay) RenderOver(base string, width, height int) string {
+	help := "↑/↓: move | space: show/hide | enter or Esc: apply | 'x': clear all filters"
+	box := a.styles.Modal.Render(a.list.Render(logRows(height), help, a.styles))
+	return ui.PlaceModal(base, box, width, height, a.styles)
+}
+
+// newAirlineOverlay lists the airlines of board with their flight counts,
+// checking those currently shown
+func newAirlineOverlay(board *ui.Board) *airlineOverlay {
+	airlines := board.Airlines()
+	items := make([]ui.ChecklistItem, 0, len(airlines))
+	for _, airline := range airlines {
+		label := airline.Key
+		if airline.Name != "" && airline.Name != airline.Key {
+			label = fmt.Sprintf("%-3s %s", airline.Key, airline.Name)
+		}
+		items = append(items, ui.ChecklistItem{
+			Key:     airline.Key,
+			Label:   fmt.Sprintf("%s (%d)", label, airline.Count),
+			Checked: !board.AirlineHidden(airline.Key),
+		})
+	}
+	return &airlineOverlay{list: ui.NewChecklist("AIRLINES", items), styles: board.Styles}
+}
+
+// helpOverlay lists the keys in a modal over the board, for when a custom
+// footer takes the place of the key help
+type helpOverlay struct {
+	keys   []string
+	styles *ui.SplitFlapStyles
+}
+
+// RenderOver draws the key list in a modal over the board
+func (h *helpOverlay) RenderOver(base string, width, height int) string {
+	lines := []string{h.styles.AirportLabel.Render("KEYS")}
+	for _, key := range h.keys {
+		lines = append(lines, h.styles.Text.Render(key))
+	}
+	lines = append(lines, h.styles.PageInfo.Render("'?' or Esc to close"))
+	box := h.styles.Modal.Render(h.styles.Background.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	return ui.PlaceModal(base, box, width, height, h.styles)
+}
+
+// logOverlay shows the change log in a modal over the board
+type logOverlay struct {
+	events *eventLog
+	offset int // Number of newest events scrolled past
+	styles *ui.SplitFlapStyles
+}
+
+// RenderOver draws the change log in a modal over the board
+func (l *logOverlay) RenderOver(base string, width, height int) string {
+	box := l.styles.Modal.Render(ui.RenderEventLog(l.events.list(time.Now()), l.offset, logRows(height), l.styles))
+	return ui.PlaceModal(base, box, width, height, l.styles)
+}
+
+// opsOverlay shows how each airline's flights are running in a modal over
+// the board, recounted from its latest flights whenever it is drawn
+type opsOverlay struct {
+	board  *ui.Board
+	offset int // Number of busiest airlines scrolled past
+	styles *ui.SplitFlapStyles
+}
+
+// RenderOver draws the operations summary in a modal over the board
+func (o *opsOverlay) RenderOver(base string, width, height int) string {
+	box := o.styles.Modal.Render(ui.RenderAirlineStats(o.board.AirlineStats(), o.offset, opsRows(height), o.styles))
+	return ui.PlaceModal(base, box, width, height, o.styles)
+}
+
+// opsRows returns the number of airlines shown at once in the operations
+// summary for a terminal of the given height
+func opsRows(height int) int {
+	// The column header takes a line more than the change log
+	return max(1, logRows(height)-1)
+}
+
+// logRows returns the number of events shown at once in the change log for a
+// terminal of the given height
+func logRows(height int) int {
+	if height <= 0 {
+		return 20
+	}
+	// Leave room for the modal border, title, footer and padding
+	return max(1, height-10)
+}
@@ -0,0 +1,108 @@
+package fids
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/mqtt/mqtttest"
+	"fids-tui/payload"
+	"fids-tui/ui"
+)
+
+var updateFixtures = flag.Bool("update", false, "rewrite the message fixtures in payload/testdata")
+
+// fixtureNow is the clock the message fixtures were made against
+var fixtureNow = time.Date(2031, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// checkFixture compares a message against its fixture in payload/testdata,
+// where the payload tests validate it against the schema, rewriting it with
+// -update
+func checkFixture(t *testing.T, name string, message []byte) {
+	t.Helper()
+	var out any
+	if err := json.Unmarshal(message, &out); err != nil {
+		t.Fatalf("%s isn't JSON: %v", name, err)
+	}
+	indented, _ := json.MarshalIndent(out, "", "  ")
+	indented = append(indented, '\n')
+	path := filepath.Join("..", "payload", "testdata", name+".json")
+	if *updateFixtures {
+		if err := os.WriteFile(path, indented, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if string(indented) != string(want) {
+		t.Errorf("%s differs from %s:\n%s\nwant\n%s", name, path, indented, want)
+	}
+}
+
+// fixtureFlights returns departures in each of the states a message names
+func fixtureFlights() []models.Flight {
+	flights := modelFlights(4, fixtureNow)
+	estimate := flights[1].ScheduledDeparture.Add(25 * time.Minute)
+	flights[1].Status, flights[1].Remarks, flights[1].EstimatedDeparture = models.StatusDelayed, models.RemarksDelayed, &estimate
+	flights[2].Status, flights[2].Remarks = models.StatusCancelled, models.RemarksCancelled
+	out := flights[3].ScheduledDeparture
+	flights[3].Status, flights[3].Remarks, flights[3].ActualOut = models.StatusTaxiingLeftGate, models.RemarksTaxiingLeftGate, &out
+	flights[3].Ident, flights[3].AirlineName = "AAL103", "American Airlines"
+	return flights
+}
+
+// TestMessageFixtures checks what each producer sends against the fixtures
+// the payload tests validate: the MQTT flights and event messages, and the
+// -export JSON
+func TestMessageFixtures(t *testing.T) {
+	broker, err := mqtttest.NewBroker()
+	if err != nil {
+		t.Fatalf("NewBroker: %v", err)
+	}
+	defer broker.Close()
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.MQTTURL = broker.URL
+	m := newTestModel(t, &fakeProvider{flights: fixtureFlights()}, WithConfig(cfg))
+	// The retained message of the first fetch
+	if _, err := broker.Next(5 * time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	tab := m.current()
+	tab.board.Provenance = ui.Provenance{Source: "FlightAware", FetchedAt: fixtureNow}
+	gate := ui.ChangeEvent{
+		Time: fixtureNow, AirportCode: "JFK", FlightNumber: "AA 101", FlightID: "AAL101-test",
+		Scheduled: fixtureFlights()[1].ScheduledDeparture, Place: "LAX", Kind: ui.ChangeGate, Old: "B2", New: "C7",
+	}
+	m.publishUpdate(tab, []ui.ChangeEvent{gate})
+	for _, name := range []string{"mqtt_board", "mqtt_event"} {
+		msg, err := broker.Next(5 * time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFixture(t, name, msg.Payload)
+	}
+
+	export, err := m.Export("json")
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	// The export is fetched when it runs
+	var board payload.Board
+	if err := json.Unmarshal([]byte(export), &board); err != nil {
+		t.Fatalf("export isn't a board message: %v", err)
+	}
+	board.Source, board.FetchedAt = "FlightAware", fixtureNow
+	normalized, _ := json.Marshal(board)
+	checkFixture(t, "export", normalized)
+}
@@ -0,0 +1,58 @@
+package fids
+
+import (
+	"fids-tui/config"
+	"fids-tui/models"
+)
+
+// FlightProcessor transforms the flights of a fetch before they are shown,
+// returning the flights to keep. Processors may change and reorder the
+// flights they are given, which are never the provider's own slice
+type FlightProcessor func([]models.Flight) []models.Flight
+
+// pipeline applies processors in order to every fetch
+type pipeline []FlightProcessor
+
+// apply runs each processor on a copy of flights
+func (p pipeline) apply(flights []models.Flight) []models.Flight {
+	flights = append([]models.Flight(nil), flights...)
+	for _, process := range p {
+		flights = process(flights)
+	}
+	return flights
+}
+
+// newPipeline builds the processors cfg asks for: the built-in filters, then
+// the rules of RULES_FILE, then customProcessors and extra in order. Filters
+// that change without a fetch, like the destination and airline filters, and
+// the order of the view are applied by the board instead
+func newPipeline(cfg *config.Config, blocked blockedMode, rules rules, extra []FlightProcessor) pipeline {
+	var p pipeline
+	if blocked == blockedHidden {
+		p = append(p, hideBlocked)
+	}
+	if cfg.HideNoDestination {
+		p = append(p, hideNoRoute)
+	}
+	if len(rules) > 0 {
+		p = append(p, rules.process)
+	}
+	p = append(p, customProcessors...)
+	p = append(p, extra...)
+	return p
+}
+
+// hideNoRoute drops flights without a known destination (origin on arrivals
+// boards), such as positioning flights. Blocked flights, whose route is
+// withheld, are left to SHOW_BLOCKED. The kept flights are copied to a new
+// slice, so the filter is safe on flights shared with other boards wherever
+// it is called
+func hideNoRoute(flights []models.Flight) []models.Flight {
+	kept := make([]models.Flight, 0, len(flights))
+	for _, flight := range flights {
+		if flight.HasRoute() || flight.Blocked {
+			kept = append(kept, flight)
+		}
+	}
+	return kept
+}
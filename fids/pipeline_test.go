@@ -0,0 +1,56 @@
+package fids
+
+import (
+	"reflect"
+	"testing"
+
+	"fids-tui/models"
+)
+
+// routeFlights returns departures with and without a destination, and a
+// blocked one whose route is withheld
+func routeFlights() []models.Flight {
+	return []models.Flight{
+		{FlightNumber: "AA 100", DestinationCode: "LAX"},
+		{FlightNumber: "N512GA"},
+		{FlightNumber: "UA 523", Direction: models.Arrival, OriginCode: "ORD"},
+		{FlightNumber: "N77FR", Direction: models.Arrival},
+		{FlightNumber: "DL 2207", DestinationCity: "Springfield"},
+		{FlightNumber: "XX 1", Blocked: true},
+	}
+}
+
+func TestHideNoRoute(t *testing.T) {
+	flights := routeFlights()
+	kept := hideNoRoute(flights)
+
+	var numbers []string
+	for _, flight := range kept {
+		numbers = append(numbers, flight.FlightNumber)
+	}
+	if want := []string{"AA 100", "UA 523", "DL 2207", "XX 1"}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("kept %v, want %v", numbers, want)
+	}
+	// The flights may be shown on other boards, so they are left as they were
+	if !reflect.DeepEqual(flights, routeFlights()) {
+		t.Errorf("hideNoRoute changed its input to %v", flights)
+	}
+}
+
+func TestPipelineLeavesFetchAlone(t *testing.T) {
+	flights := routeFlights()
+	reverse := func(flights []models.Flight) []models.Flight {
+		for i, j := 0, len(flights)-1; i < j; i, j = i+1, j-1 {
+			flights[i], flights[j] = flights[j], flights[i]
+		}
+		return flights
+	}
+	p := pipeline{hideBlocked, hideNoRoute, reverse}
+	got := p.apply(flights)
+	if len(got) != 3 || got[0].FlightNumber != "DL 2207" {
+		t.Errorf("pipeline gave %v", got)
+	}
+	if !reflect.DeepEqual(flights, routeFlights()) {
+		t.Errorf("pipeline changed the fetched flights to %v", flights)
+	}
+}
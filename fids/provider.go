@@ -0,0 +1,107 @@
+package fids
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+)
+
+// NewProvider builds the configured data provider, wrapping it with the
+// fallback provider when a fallback source is configured, with the ADS-B
+// provider when a local receiver feed is configured and with a recorder when
+// a record directory is. A replay directory replaces them all with its
+// snapshots. FlightAware requests are counted in usage, which may be nil
+func NewProvider(cfg *config.Config, usage *api.Usage) (api.FlightDataProvider, error) {
+	if cfg.ReplayDir != "" {
+		return api.NewReplayProvider(cfg.ReplayDir)
+	}
+	transport, err := api.NewTransport(api.TransportConfig{
+		CACertFile:         cfg.CACertFile,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CA_CERT_FILE: %w", err)
+	}
+
+	var window api.FetchWindow
+	if cfg.OperationalDay != "" {
+		window.OperationalDay = true
+		window.DayEnd, err = config.ParseClock(cfg.OperationalDay)
+		if err != nil {
+			return nil, fmt.Errorf("OPERATIONAL_DAY: %w", err)
+		}
+	}
+
+	provider, err := newSourceProvider(cfg.DataSource, cfg, transport, usage, window)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.FallbackSource != "" && cfg.FallbackSource != cfg.DataSource {
+		secondary, err := newSourceProvider(cfg.FallbackSource, cfg, transport, usage, window)
+		if err != nil {
+			return nil, err
+		}
+		provider = api.NewFallbackProvider(provider, secondary)
+	}
+	if cfg.ADSBFeedURL != "" {
+		provider = api.NewADSBProvider(provider, cfg.ADSBFeedURL)
+	}
+	if cfg.RecordDir != "" {
+		provider, err = api.NewRecorder(provider, cfg.RecordDir)
+		if err != nil {
+			return nil, fmt.Errorf("RECORD_DIR: %w", err)
+		}
+	}
+	return provider, nil
+}
+
+// newSourceProvider builds the provider for a single data source name, sending
+// its requests through transport and fetching flights for window
+func newSourceProvider(source string, cfg *config.Config, transport http.RoundTripper, usage *api.Usage, window api.FetchWindow) (api.FlightDataProvider, error) {
+	switch source {
+	case "flightaware":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("FLIGHTAWARE_API_KEY is required; set it in the environment or the config file, or run fids-tui -setup")
+		}
+		opts := []api.FlightAwareOption{
+			api.WithHTTPClient(&http.Client{Transport: transport}),
+			api.WithUsage(usage),
+		}
+		if cfg.FlightAwareBaseURL != "" {
+			if err := api.ValidateBaseURL(cfg.FlightAwareBaseURL); err != nil {
+				return nil, fmt.Errorf("FLIGHTAWARE_BASE_URL: %w", err)
+			}
+			opts = append(opts, api.WithBaseURL(cfg.FlightAwareBaseURL))
+		}
+		timeout := cfg.FlightAwareTimeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		opts = append(opts, api.WithTimeout(timeout))
+		client := api.NewFlightAwareClient(cfg.APIKey, opts...)
+		client.TargetFlights = cfg.TotalFlights
+		client.Window = window
+		client.Strict = cfg.Strict
+		client.TaxiLookups = cfg.TaxiLookups
+		client.RateLimit = api.NewRateLimiter(cfg.MaxRequestsPerMinute)
+		slog.Debug("using FlightAware", "base_url", client.BaseURL, "timeout", client.Client.Timeout)
+		return client, nil
+	case "opensky":
+		client := api.NewOpenSkyClient()
+		client.Client.Transport = transport
+		client.MaxFlights = cfg.TotalFlights
+		client.Window = window
+		return client, nil
+	case "demo":
+		demo := api.NewDemoProvider()
+		demo.MaxFlights = cfg.TotalFlights
+		demo.Window = window
+		return demo, nil
+	default:
+		return nil, fmt.Errorf("unknown data source %q (expected flightaware, opensky or demo)", source)
+	}
+}
@@ -0,0 +1,66 @@
+package fids
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quietWakeDuration is how long a keypress resumes updates during quiet hours
+const quietWakeDuration = 10 * time.Minute
+
+// inQuietHours reports whether updates should be paused at now: during the
+// configured quiet hours in the active airport's timezone, unless a keypress
+// has woken the board
+func (m BoardModel) inQuietHours(now time.Time) bool {
+	if m.quietHours == nil || now.Before(m.quietWake) {
+		return false
+	}
+	return m.quietHours.Contains(now.In(m.Board().AirportTZ))
+}
+
+// pauseForQuietHours stops fetching for every tab and shows when updates resume
+// Pending API ticks are invalidated; rotation and new animations are skipped
+// while paused
+func (m *BoardModel) pauseForQuietHours(now time.Time) {
+	m.quietPaused = true
+	until := now.Add(m.quietHours.UntilBoundary(now.In(m.Board().AirportTZ)))
+	for _, t := range m.tabs {
+		t.tickSeq++
+		t.board.NextUpdate = time.Time{}
+		t.board.PausedUntil = until
+	}
+}
+
+// resumeAfterQuietHours restarts updates, refreshing the active tab at once so
+// the board is current when people arrive
+func (m *BoardModel) resumeAfterQuietHours() tea.Cmd {
+	m.quietPaused = false
+	if m.sideBySide {
+		for _, t := range m.tabs {
+			t.board.PausedUntil = time.Time{}
+		}
+		return tea.Batch(m.refreshPanes(), m.startAnimation())
+	}
+	cmds := []tea.Cmd{m.refresh(m.current()), m.startAnimation()}
+	for _, t := range m.tabs {
+		t.board.PausedUntil = time.Time{}
+		if t != m.current() && t.fetched && m.tabInterval(t) > 0 {
+			cmds = append(cmds, m.resume(t))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// checkQuietHours pauses or resumes updates when a quiet hours boundary or
+// the end of a keypress wake has passed
+func (m *BoardModel) checkQuietHours(now time.Time) tea.Cmd {
+	quiet := m.inQuietHours(now)
+	switch {
+	case quiet && !m.quietPaused:
+		m.pauseForQuietHours(now)
+	case !quiet && m.quietPaused:
+		return m.resumeAfterQuietHours()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package fids
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// replayStep returns the command stepping a replay delta snapshots, back
+// through the recording for a negative delta
+func replayStep(delta int) tea.Cmd {
+	return func() tea.Msg { return ReplayMsg{Step: delta} }
+}
+
+// stepReplay moves the active board msg.Step snapshots through the recording
+// being replayed, pausing its pacing, and fetches the snapshot it lands on.
+// That one is applied with the rows rebuilt rather than flipped from the
+// snapshot shown before, which may be later
+func (m BoardModel) stepReplay(msg ReplayMsg) tea.Cmd {
+	if m.replay == nil {
+		return nil
+	}
+	t := m.current()
+	stepped := false
+	for _, code := range t.spec.Airports() {
+		if m.replay.Step(code, t.spec.Destination, t.spec.Direction, msg.Step) {
+			stepped = true
+		}
+	}
+	if !stepped {
+		t.board.Toast = "this board was not recorded"
+		t.board.ToastUntil = time.Now().Add(toastDuration)
+		return nil
+	}
+	t.rebuild = true
+	return m.refresh(t)
+}
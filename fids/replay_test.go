@@ -0,0 +1,133 @@
+package fids
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/models"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestReplayScrubbing checks that '<' and '>' step a replayed board through
+// its snapshots, rebuilt without flipping, with the capture time in the
+// header, and that ctrl+r resumes the replay's pacing
+func TestReplayScrubbing(t *testing.T) {
+	// Record AA 100 at gate B2, then at C7, then at D4
+	dir := filepath.Join(t.TempDir(), "session")
+	now := time.Now()
+	provider := &fakeProvider{flights: modelFlights(3, now)}
+	recorder, err := api.NewRecorder(provider, dir)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 15, 4, 0, 0, time.UTC)
+	captured := start
+	recorder.Now = func() time.Time { return captured }
+	for i, gate := range []string{"B2", "C7", "D4"} {
+		captured = start.Add(time.Duration(i) * time.Minute)
+		provider.flights[0].Gate = gate
+		if _, err := recorder.GetDepartures(context.Background(), "JFK", api.FetchOptions{}); err != nil {
+			t.Fatalf("GetDepartures: %v", err)
+		}
+	}
+	replay, err := api.NewReplayProvider(dir)
+	if err != nil {
+		t.Fatalf("NewReplayProvider: %v", err)
+	}
+
+	m := newTestModel(t, replay)
+	fetch := func() {
+		m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	}
+	// shows checks the board shows AA 100 at gate, as recorded at minute
+	shows := func(gate string, minute int) {
+		t.Helper()
+		screen := ansi.Strip(m.View())
+		line := ""
+		for _, l := range strings.Split(screen, "\n") {
+			if strings.Contains(l, "AA 100") {
+				line = l
+			}
+		}
+		if !strings.Contains(line, gate) {
+			t.Errorf("AA 100 isn't at gate %s:\n%s", gate, screen)
+		}
+		if want := "recorded " + start.Add(time.Duration(minute)*time.Minute).In(m.Board().AirportTZ).Format("Jan 2 15:04:05"); !strings.Contains(screen, want) {
+			t.Errorf("header doesn't show %q:\n%s", want, screen)
+		}
+	}
+	shows("B2", 0)
+	fetch()
+	m = settleModel(t, m)
+	fetch()
+	m = settleModel(t, m)
+	shows("D4", 2)
+
+	// Stepping back rebuilds the rows as recorded, without flipping however
+	// long flaps take
+	m.Board().Animation.Duration = time.Hour
+	for _, minute := range []int{1, 0} {
+		_, cmd := m.Update(keyMsg("<"))
+		if cmd == nil {
+			t.Fatal("'<' did nothing")
+		}
+		m = update(t, m, cmd())
+		fetch()
+		if m.Board().IsAnimating() {
+			t.Errorf("board animates after stepping back to minute %d", minute)
+		}
+		shows([]string{"B2", "C7"}[minute], minute)
+	}
+
+	// The pacing stays paused on the snapshot stepped to
+	fetch()
+	shows("B2", 0)
+
+	// Stepping forward rebuilds too
+	m = update(t, m, ReplayMsg{Step: 1})
+	fetch()
+	if m.Board().IsAnimating() {
+		t.Error("board animates after stepping forward")
+	}
+	shows("C7", 1)
+
+	// ctrl+r resumes the replay from there
+	m = press(t, m, "ctrl+r")
+	if replay.Paused() {
+		t.Error("replay still paused after ctrl+r")
+	}
+	fetch()
+	if gate := m.Board().Flights()[0].Gate; gate != "D4" {
+		t.Errorf("AA 100 at gate %s after resuming, want D4", gate)
+	}
+}
+
+// TestReplayUnrecordedBoard checks that stepping a board that wasn't recorded
+// says so
+func TestReplayUnrecordedBoard(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := api.NewRecorder(&fakeProvider{flights: modelFlights(3, time.Now())}, dir)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	if _, err := recorder.GetArrivals(context.Background(), "JFK", api.FetchOptions{}); err != nil {
+		t.Fatalf("GetArrivals: %v", err)
+	}
+	replay, err := api.NewReplayProvider(dir)
+	if err != nil {
+		t.Fatalf("NewReplayProvider: %v", err)
+	}
+	m := newTestModel(t, replay)
+	if m.current().spec.Direction != models.Departure {
+		t.Fatal("test board isn't of departures")
+	}
+	m = update(t, m, ReplayMsg{Step: -1})
+	if got := m.Board().Toast; got != "this board was not recorded" {
+		t.Errorf("toast = %q, want the board wasn't recorded", got)
+	}
+}
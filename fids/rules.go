@@ -0,0 +1,160 @@
+package fids
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"fids-tui/models"
+)
+
+// ruleFields are the flight fields rules can match and set, by name
+var ruleFields = map[string]func(*models.Flight) *string{
+	"flight":           func(f *models.Flight) *string { return &f.FlightNumber },
+	"ident":            func(f *models.Flight) *string { return &f.Ident },
+	"airline":          func(f *models.Flight) *string { return &f.AirlineCode },
+	"airline_name":     func(f *models.Flight) *string { return &f.AirlineName },
+	"destination":      func(f *models.Flight) *string { return &f.DestinationCode },
+	"destination_city": func(f *models.Flight) *string { return &f.DestinationCity },
+	"origin":           func(f *models.Flight) *string { return &f.OriginCode },
+	"origin_city":      func(f *models.Flight) *string { return &f.OriginCity },
+	"gate":             func(f *models.Flight) *string { return &f.Gate },
+}
+
+// rule changes or hides the flights whose field matches a pattern
+type rule struct {
+	field   string // Name of the field matched
+	match   func(*models.Flight) *string
+	pattern string                       // Upper case glob, as in path.Match
+	hide    bool                         // Leave matching flights off the board
+	set     func(*models.Flight) *string // Field set to value, if not hiding
+	value   string
+}
+
+// rules is a rules file, applied top to bottom to each flight
+type rules []rule
+
+// loadRules reads a rules file. Each line is a rule of the form
+//
+//	FIELD PATTERN hide
+//	FIELD PATTERN set FIELD VALUE
+//
+// where PATTERN is a glob matched case-insensitively against the field, and
+// VALUE is the rest of the line. Blank lines and lines starting with # are
+// ignored
+func loadRules(filename string) (rules, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	defer file.Close()
+
+	var parsed rules
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		r, err := parseRule(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+		parsed = append(parsed, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	return parsed, nil
+}
+
+// parseRule parses one line of a rules file
+func parseRule(text string) (rule, error) {
+	words := strings.Fields(text)
+	if len(words) < 3 {
+		return rule{}, fmt.Errorf("invalid rule %q (expected FIELD PATTERN hide or FIELD PATTERN set FIELD VALUE)", text)
+	}
+
+	r := rule{field: strings.ToLower(words[0])}
+	var ok bool
+	if r.match, ok = ruleFields[r.field]; !ok {
+		return rule{}, fmt.Errorf("unknown field %q (expected one of %s)", words[0], ruleFieldNames())
+	}
+	r.pattern = strings.ToUpper(words[1])
+	if _, err := path.Match(r.pattern, ""); err != nil {
+		return rule{}, fmt.Errorf("invalid pattern %q: %w", words[1], err)
+	}
+
+	switch action := strings.ToLower(words[2]); action {
+	case "hide":
+		if len(words) > 3 {
+			return rule{}, fmt.Errorf("invalid rule %q: hide takes no arguments", text)
+		}
+		r.hide = true
+	case "set":
+		if len(words) < 5 {
+			return rule{}, fmt.Errorf("invalid rule %q: set needs a field and a value", text)
+		}
+		if r.set, ok = ruleFields[strings.ToLower(words[3])]; !ok {
+			return rule{}, fmt.Errorf("unknown field %q (expected one of %s)", words[3], ruleFieldNames())
+		}
+		// The value is the rest of the line, so it may contain spaces
+		r.value = afterWords(text, 4)
+	default:
+		return rule{}, fmt.Errorf("unknown action %q (expected hide or set)", words[2])
+	}
+	return r, nil
+}
+
+// afterWords returns text after its first n words, without surrounding space
+func afterWords(text string, n int) string {
+	for range n {
+		text = strings.TrimLeft(text, " \t")
+		if i := strings.IndexAny(text, " \t"); i >= 0 {
+			text = text[i:]
+		} else {
+			text = ""
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// process applies the rules to copies of flights, dropping hidden ones
+func (rs rules) process(flights []models.Flight) []models.Flight {
+	kept := make([]models.Flight, 0, len(flights))
+	for _, flight := range flights {
+		if rs.apply(&flight) {
+			kept = append(kept, flight)
+		}
+	}
+	return kept
+}
+
+// apply applies each rule matching flight in turn, reporting whether the
+// flight is still shown. Later rules see the changes of earlier ones
+func (rs rules) apply(flight *models.Flight) bool {
+	for _, r := range rs {
+		matched, _ := path.Match(r.pattern, strings.ToUpper(strings.TrimSpace(*r.match(flight))))
+		if !matched {
+			continue
+		}
+		if r.hide {
+			return false
+		}
+		*r.set(flight) = r.value
+	}
+	return true
+}
+
+// ruleFieldNames lists the field names for error messages
+func ruleFieldNames() string {
+	names := make([]string, 0, len(ruleFields))
+	for name := range ruleFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,101 @@
+package fids
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// writeRules writes text as a rules file, returning its path
+func writeRules(t *testing.T, text string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "rules.txt")
+	if err := os.WriteFile(filename, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadRules(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		text    string
+		rules   int
+		wantErr string // Error after the file name, or "" if the file loads
+	}{
+		{"hide", "flight AA?100 hide", 1, ""},
+		{"hide glob", "airline A* hide", 1, ""},
+		{"set with spaces", "destination LAX set destination_city Los Angeles Intl", 1, ""},
+		{"case-insensitive names", "Gate b* SET Gate C", 1, ""},
+		{"comments and blanks", "# Codeshares\n\n  # indented\nairline AA hide\n", 1, ""},
+		{"several", "airline UA hide\ngate B2 set gate B3\n", 2, ""},
+		{"empty", "", 0, ""},
+		{"unknown field", "terminal 4 hide", 0, `:1: unknown field "terminal" (expected one of`},
+		{"unknown field set", "gate B2 set terminal 4", 0, `:1: unknown field "terminal"`},
+		{"malformed line numbered", "# Rules\nairline AA hide\n\ngate\n", 0, `:4: invalid rule "gate"`},
+		{"unknown action", "gate B2 move", 0, `:1: unknown action "move"`},
+		{"pattern with a space", "flight AA 100 hide", 0, `:1: unknown action "100"`},
+		{"hide with arguments", "gate B2 hide now", 0, `:1: invalid rule "gate B2 hide now": hide takes no arguments`},
+		{"set without a value", "gate B2 set gate", 0, `:1: invalid rule "gate B2 set gate": set needs a field and a value`},
+		{"bad pattern", "gate [B hide", 0, `:1: invalid pattern "[B"`},
+	} {
+		filename := writeRules(t, tt.text)
+		parsed, err := loadRules(filename)
+		if tt.wantErr != "" {
+			if err == nil || !strings.HasPrefix(err.Error(), filename+tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q after the file name", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || len(parsed) != tt.rules {
+			t.Errorf("%s: %d rules, %v; want %d", tt.name, len(parsed), err, tt.rules)
+		}
+	}
+
+	if _, err := loadRules(filepath.Join(t.TempDir(), "missing.txt")); err == nil || !strings.HasPrefix(err.Error(), "failed to read rules file") {
+		t.Errorf("missing file: error = %v", err)
+	}
+}
+
+func TestRulesProcess(t *testing.T) {
+	flights := modelFlights(4, time.Now())
+	flights[1].AirlineCode, flights[1].FlightNumber = "UA", "UA 200"
+	flights[2].DestinationCode, flights[2].DestinationCity = "SFO", "San Francisco"
+	flights[3].Gate = "C7"
+	given := append([]models.Flight(nil), flights...)
+
+	for _, tt := range []struct {
+		name string
+		text string
+		want []string // Flight, destination city and gate of the flights kept
+	}{
+		{"no rules", "", []string{"AA 100 Los Angeles B2", "UA 200 Los Angeles B2", "AA 102 San Francisco B2", "AA 103 Los Angeles C7"}},
+		{"hide an airline", "airline ua hide", []string{"AA 100 Los Angeles B2", "AA 102 San Francisco B2", "AA 103 Los Angeles C7"}},
+		{"hide by glob", "flight AA?10[02] hide\ngate C* hide", []string{"UA 200 Los Angeles B2"}},
+		{"rewrite", "destination LAX set destination_city Los Angeles Intl", []string{"AA 100 Los Angeles Intl B2", "UA 200 Los Angeles Intl B2", "AA 102 San Francisco B2", "AA 103 Los Angeles Intl C7"}},
+		{"later rules see earlier changes", "gate B2 set gate B9\ngate B9 hide\ngate C7 set gate B2", []string{"AA 103 Los Angeles B2"}},
+		{"hide wins over a later rewrite", "destination SFO hide\ndestination SFO set gate Z1", []string{"AA 100 Los Angeles B2", "UA 200 Los Angeles B2", "AA 103 Los Angeles C7"}},
+		{"everything hidden", "flight * hide", nil},
+	} {
+		parsed, err := loadRules(writeRules(t, tt.text))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, flight := range parsed.process(flights) {
+			got = append(got, flight.FlightNumber+" "+flight.DestinationCity+" "+flight.Gate)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: kept\n%s\nwant\n%s", tt.name, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+		for i := range flights {
+			if flights[i] != given[i] {
+				t.Fatalf("%s: process changed the flights it was given", tt.name)
+			}
+		}
+	}
+}
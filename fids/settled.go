@@ -0,0 +1,41 @@
+package fids
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// settled reports whether the boards on screen have settled: each has had
+// its first fetch applied, no fetch of theirs is under way and none is
+// animating. Tools capturing the screen wait for this through the control
+// socket instead of sleeping
+func (m BoardModel) settled() bool {
+	for _, t := range m.tabs {
+		if !m.shown(t) {
+			continue
+		}
+		if t.loading || t.inFlight || t.board.IsAnimating() {
+			return false
+		}
+	}
+	return true
+}
+
+// noteSettled logs "board settled" when the boards on screen settle after a
+// fetch, once per refresh cycle: a page rotating or a key pressed animates
+// the board without a fetch and logs nothing
+func (m BoardModel) noteSettled() {
+	if !m.settled() {
+		return
+	}
+	var airports []string
+	for _, t := range m.tabs {
+		if m.shown(t) && t.unsettled {
+			t.unsettled = false
+			airports = append(airports, t.spec.AirportCode)
+		}
+	}
+	if len(airports) > 0 {
+		slog.Info("board settled", "airports", strings.Join(airports, ","))
+	}
+}
@@ -0,0 +1,202 @@
+package fids
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/models"
+)
+
+// settledRecorder counts the "board settled" logs and the airports of each
+type settledRecorder struct {
+	mu       sync.Mutex
+	airports []string
+}
+
+func (r *settledRecorder) Enabled(context.Context, slog.Level) bool { return true }
+func (r *settledRecorder) WithAttrs([]slog.Attr) slog.Handler       { return r }
+func (r *settledRecorder) WithGroup(string) slog.Handler            { return r }
+
+func (r *settledRecorder) Handle(_ context.Context, record slog.Record) error {
+	if record.Message != "board settled" {
+		return nil
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "airports" {
+			r.mu.Lock()
+			r.airports = append(r.airports, attr.Value.String())
+			r.mu.Unlock()
+		}
+		return true
+	})
+	return nil
+}
+
+func (r *settledRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.airports)
+}
+
+// recordSettled sends the logs to a settledRecorder until the test ends
+func recordSettled(t *testing.T) *settledRecorder {
+	recorder := &settledRecorder{}
+	previous := slog.Default()
+	slog.SetDefault(slog.New(recorder))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return recorder
+}
+
+// TestSettledOncePerRefresh checks that the board logs "board settled" once
+// per fetch applied, only after its flaps stop, and not for a page turned
+// without a fetch
+func TestSettledOncePerRefresh(t *testing.T) {
+	recorder := recordSettled(t)
+	now := time.Now()
+	provider := &fakeProvider{flights: modelFlights(30, now)}
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.PageTransitions = true
+	m := newTestModel(t, provider, WithConfig(cfg))
+	if got := recorder.count(); got != 1 {
+		t.Fatalf("%d settled logs after the first fetch, want 1", got)
+	}
+	if recorder.airports[0] != "JFK" {
+		t.Errorf("settled log airports = %q, want JFK", recorder.airports[0])
+	}
+
+	// A fetch that changes flights logs once the flaps have stopped, however
+	// many ticks that takes
+	provider.flights[0].Gate = "C7"
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	if !m.Board().IsAnimating() {
+		t.Fatal("the gate change didn't animate")
+	}
+	for range 100 {
+		if !m.Board().IsAnimating() {
+			break
+		}
+		if got := recorder.count(); got != 1 {
+			t.Fatalf("%d settled logs while the board animates, want 1", got)
+		}
+		m = update(t, m, TickAnimationMsg(time.Now()))
+		time.Sleep(time.Millisecond)
+	}
+	m = settleModel(t, m)
+	if got := recorder.count(); got != 2 {
+		t.Fatalf("%d settled logs after the second fetch settled, want 2", got)
+	}
+
+	// Further ticks of a settled board don't log again
+	m = update(t, m, TickAnimationMsg(time.Now()))
+	if got := recorder.count(); got != 2 {
+		t.Errorf("%d settled logs after another tick, want 2", got)
+	}
+
+	// Turning the page flips the rows without a fetch
+	if m.Board().TotalPages < 2 {
+		t.Fatalf("board has %d pages, want several", m.Board().TotalPages)
+	}
+	m = press(t, m, "right")
+	if !m.Board().IsAnimating() {
+		t.Fatal("turning the page didn't animate")
+	}
+	m = settleModel(t, m)
+	if got := recorder.count(); got != 2 {
+		t.Errorf("%d settled logs after turning the page, want 2", got)
+	}
+
+	// A fetch that changes nothing settles at once, still once
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	m = settleModel(t, m)
+	if got := recorder.count(); got != 3 {
+		t.Errorf("%d settled logs after an unchanged fetch, want 3", got)
+	}
+}
+
+// TestOnceSettles checks that Once returns the ticker line only after the
+// fetch has been applied and the board has stopped animating
+func TestOnceSettles(t *testing.T) {
+	cfg := config.Default()
+	cfg.View = "ticker"
+	cfg.CharAnimationSpeed = time.Millisecond
+	flights := modelFlights(3, time.Now())
+	flights[0].Status = models.StatusDelayed
+	m, err := New(WithConfig(cfg), WithAirport("JFK"), WithProvider(&fakeProvider{flights: flights}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer m.Close()
+
+	line, err := m.Once()
+	if err != nil {
+		t.Fatalf("Once: %v", err)
+	}
+	if m.Board().IsAnimating() {
+		t.Error("board still animating after Once returned")
+	}
+	if rows := len(m.Board().Rows()); rows != 3 {
+		t.Errorf("board has %d rows after Once, want 3", rows)
+	}
+	for _, want := range []string{"AA 100", "AA 102"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Once = %q, doesn't show %s", line, want)
+		}
+	}
+}
+
+// TestReadyOnFirstFetch checks that READY=1 is sent once the first fetch is
+// applied, not when the empty board is first drawn, and only once
+func TestReadyOnFirstFetch(t *testing.T) {
+	dir, err := os.MkdirTemp("", "notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", socket)
+
+	// receive returns the next notification, or "" if none comes soon
+	receive := func() string {
+		buf := make([]byte, 64)
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return ""
+		}
+		return string(buf[:n])
+	}
+
+	m, err := New(WithConfig(config.Default()), WithAirport("JFK"), WithProvider(&fakeProvider{flights: modelFlights(3, time.Now())}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer m.Close()
+	m.View()
+	if got := receive(); got != "" {
+		t.Fatalf("notification %q before the first fetch, want none", got)
+	}
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	if got := receive(); got != "READY=1" {
+		t.Fatalf("notification %q after the first fetch, want READY=1", got)
+	}
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	m.View()
+	if got := receive(); got != "" {
+		t.Errorf("notification %q after the second fetch, want none", got)
+	}
+}
@@ -0,0 +1,75 @@
+//go:build soak
+
+package fids
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/ui"
+)
+
+// soakInterval is the time between the fetches of the soak test, the demo
+// provider's refresh interval
+const soakInterval = 20 * time.Second
+
+// TestSoakWeek runs a week of API ticks through Update against the demo
+// provider, its clock moved on by each tick, and checks the heap and the
+// model's collections stop growing once the first day has filled them. Run
+// with go test -tags soak -run Soak ./fids/
+func TestSoakWeek(t *testing.T) {
+	clock := time.Now().Truncate(time.Minute)
+	demo := api.NewDemoProvider()
+	demo.Window.Now = func() time.Time { return clock }
+	m := newTestModel(t, demo)
+	// Changes take effect on the next animation tick rather than animating
+	m.Board().Animation = ui.AnimationTiming{}
+
+	tick := func() {
+		clock = clock.Add(soakInterval)
+		tab := m.current()
+		m = update(t, m, TickAPIMsg{Tab: tab.id, Time: clock, seq: tab.tickSeq})
+		m = update(t, m, fetchFlights(m.provider, tab.id, tab.spec, m.lookahead, 1)())
+		for range 10 {
+			if !m.Board().IsAnimating() {
+				break
+			}
+			m = update(t, m, TickAnimationMsg(time.Now()))
+		}
+		m.View()
+	}
+	heap := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+
+	day := int(24 * time.Hour / soakInterval)
+	for range day {
+		tick()
+	}
+	base := heap()
+	for d := 2; d <= 7; d++ {
+		for range day {
+			tick()
+		}
+		used := heap()
+		t.Logf("day %d: heap %d KiB (day 1 %d KiB), %d seen flights, %d change events", d, used>>10, base>>10, m.seen.Len(), m.events.count)
+		// Allow for the allocator's slack, not for anything kept per tick
+		if used > base+base/10+64<<10 {
+			t.Errorf("heap grew from %d KiB after a day to %d KiB after %d days", base>>10, used>>10, d)
+		}
+	}
+	if n := m.events.count; n > len(m.events.events) {
+		t.Errorf("%d change events kept, more than the log's %d slots", n, len(m.events.events))
+	}
+	if got, limit := m.seen.Len(), 2*int(ui.SeenFlightTTL/(10*time.Minute)); got > limit {
+		t.Errorf("%d seen flights, more than %d in %v of demo flights", got, limit, ui.SeenFlightTTL)
+	}
+	if got := m.Board().FlightCount(); got == 0 {
+		t.Error("the board is empty after a week")
+	}
+}
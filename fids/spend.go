@@ -0,0 +1,142 @@
+package fids
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fids-tui/api"
+)
+
+// spendTracker estimates what the API requests of this session and of the
+// current UTC day have cost, keeping the daily total in the state file
+type spendTracker struct {
+	usage        *api.Usage
+	costPerQuery float64
+	statePath    string // Empty to keep the daily total in memory only
+
+	day             string // UTC day the daily total is for
+	earlierRequests int64  // Requests made today by earlier runs
+	earlierPages    int64
+	startRequests   int64 // Session counts when the day started
+	startPages      int64
+	savedPages      int64 // Daily pages last written to the state file
+}
+
+// newSpendTracker creates a tracker, continuing today's total from the state file
+func newSpendTracker(usage *api.Usage, costPerQuery float64, statePath string, now time.Time) *spendTracker {
+	s := &spendTracker{usage: usage, costPerQuery: costPerQuery, statePath: statePath, day: utcDay(now)}
+	if statePath == "" {
+		return s
+	}
+	state, err := loadState(statePath)
+	if err != nil {
+		slog.Warn("ignoring state file", "error", err)
+		return s
+	}
+	if state.Spend.Date == s.day {
+		s.earlierRequests = state.Spend.Requests
+		s.earlierPages = state.Spend.Pages
+		s.savedPages = state.Spend.Pages
+	}
+	return s
+}
+
+// utcDay returns the UTC date of t, which is when the daily total resets
+func utcDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// rollover starts a new daily total once midnight UTC has passed
+func (s *spendTracker) rollover(now time.Time) {
+	if day := utcDay(now); day != s.day {
+		s.day = day
+		s.earlierRequests, s.earlierPages, s.savedPages = 0, 0, 0
+		s.startRequests, s.startPages = s.usage.Requests(), s.usage.Pages()
+	}
+}
+
+// today returns the requests and result pages of the current UTC day
+func (s *spendTracker) today() (int64, int64) {
+	return s.earlierRequests + s.usage.Requests() - s.startRequests,
+		s.earlierPages + s.usage.Pages() - s.startPages
+}
+
+// cost returns the estimated cost of a number of result pages
+func (s *spendTracker) cost(pages int64) float64 {
+	return float64(pages) * s.costPerQuery
+}
+
+// record updates the daily total and writes it to the state file if it changed
+func (s *spendTracker) record(now time.Time) {
+	s.rollover(now)
+	requests, pages := s.today()
+	if s.statePath == "" || pages == s.savedPages {
+		return
+	}
+	err := updateState(s.statePath, func(state *persistentState) {
+		state.Spend = dailySpend{Date: s.day, Requests: requests, Pages: pages}
+	})
+	if err != nil {
+		slog.Warn("failed to save API spend", "error", err)
+		return
+	}
+	s.savedPages = pages
+}
+
+// status returns the running totals for the status bar, e.g.
+// "API ≈ $0.03 (today $0.41)", or an empty string before any request
+func (s *spendTracker) status() string {
+	_, todayPages := s.today()
+	if s.usage.Requests() == 0 && todayPages == 0 {
+		return ""
+	}
+	return fmt.Sprintf("API ≈ $%.2f (today $%.2f)", s.cost(s.usage.Pages()), s.cost(todayPages))
+}
+
+// summary describes the session and daily usage for printing on exit
+func (s *spendTracker) summary() string {
+	todayRequests, todayPages := s.today()
+	return fmt.Sprintf("API usage this session: %d requests, %d result pages, ≈ $%.3f\n"+
+		"API usage today (UTC %s): %d requests, %d result pages, ≈ $%.3f\n"+
+		"Estimates use $%.4f per result page (COST_PER_QUERY)",
+		s.usage.Requests(), s.usage.Pages(), s.cost(s.usage.Pages()),
+		s.day, todayRequests, todayPages, s.cost(todayPages),
+		s.costPerQuery)
+}
+
+// Priorities of the kinds of API call in the hourly budget: the boards on
+// screen come first, then tabs refreshing in the background, then lookups
+// that only add detail
+const (
+	priorityBoard = iota
+	priorityBackground
+	priorityInbound
+	priorityTracking
+)
+
+// registerBudget tells the hourly budget how many calls each kind is
+// expected to make, so that less important calls leave room for the
+// boards'. Opening, closing or switching tabs changes the counts
+func (m BoardModel) registerBudget() {
+	shown, background := 0, 0
+	for _, t := range m.tabs {
+		if m.shown(t) {
+			shown += fetchCalls(t, m.cfg.MaxPages)
+		} else {
+			background += fetchCalls(t, m.cfg.MaxPages)
+		}
+	}
+	m.budget.Register(api.CallBoard, priorityBoard, m.cfg.UpdateInterval, shown)
+	m.budget.Register(api.CallBackground, priorityBackground, m.cfg.BackgroundInterval, background)
+	m.budget.Register(api.CallInbound, priorityInbound, m.cfg.UpdateInterval, m.cfg.InboundLookups)
+	if m.tracking.enabled() {
+		m.budget.Register(api.CallTracking, priorityTracking, m.cfg.TrackWatchedEvery, len(m.watchFlights))
+	}
+}
+
+// fetchCalls returns the most API calls a fetch of t's board makes: a
+// result page at most MAX_PAGES times for each of its airports
+func fetchCalls(t *tab, maxPages int) int {
+	return maxPages * len(t.spec.Airports())
+}
@@ -0,0 +1,99 @@
+package fids
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"fids-tui/ui"
+)
+
+// persistentState is kept in the state file between runs
+type persistentState struct {
+	Spend           dailySpend               `json:"spend"`
+	DestinationOnly string                   `json:"destination_only,omitempty"` // Destination filter last chosen with 'f'
+	SeenFlights     map[string]ui.SeenFlight `json:"seen_flights,omitempty"`     // Flights seen by ID, so restarts don't badge them NEW again
+	Lookahead       *savedLookahead          `json:"lookahead,omitempty"`        // Lookahead window last chosen with '+' and '-'
+}
+
+// savedLookahead is a lookahead window chosen with '+' and '-', with the
+// LOOKAHEAD_HOURS it was chosen from
+type savedLookahead struct {
+	Hours      int `json:"hours"`
+	Configured int `json:"configured"`
+}
+
+// hours returns the lookahead window to start with: the one last chosen,
+// unless LOOKAHEAD_HOURS has changed since, as the new setting wins
+func (s *savedLookahead) hours(configured int) int {
+	if s == nil || s.Configured != configured || s.Hours < minLookahead || s.Hours > maxLookahead {
+		return configured
+	}
+	return s.Hours
+}
+
+// dailySpend is the API usage of one UTC day across runs
+type dailySpend struct {
+	Date     string `json:"date"` // UTC day, e.g. 2024-05-01
+	Requests int64  `json:"requests"`
+	Pages    int64  `json:"pages"`
+}
+
+// loadState reads the state file, returning the zero state if it does not exist
+func loadState(path string) (persistentState, error) {
+	var state persistentState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// updateState applies change to the state file, keeping the rest of its
+// contents. An unreadable file is replaced
+func updateState(path string, change func(*persistentState)) error {
+	state, err := loadState(path)
+	if err != nil {
+		slog.Warn("replacing unreadable state file", "error", err)
+	}
+	change(&state)
+	return saveState(path, state)
+}
+
+// saveState writes the state file, replacing it atomically so a crash never
+// leaves a partial file
+func saveState(path string, state persistentState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package fids
+
+import (
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// suspendGap is how much later than due a clock tick must arrive for the
+// board to treat the gap as time spent suspended, with ctrl+z or with the
+// computer asleep
+const suspendGap = 5 * time.Second
+
+// noteClock records the clock tick at now, returning how long the board was
+// away if the tick arrived more than suspendGap late, else zero. Wall clock
+// readings are compared, since the monotonic clock stops while asleep
+func (m *BoardModel) noteClock(now time.Time) time.Duration {
+	last := m.lastClock
+	m.lastClock = now
+	if last.IsZero() {
+		return 0
+	}
+	if away := now.Round(0).Sub(last.Round(0)); away > clockInterval+suspendGap {
+		return away
+	}
+	return 0
+}
+
+// awake catches the board up after away spent suspended or asleep, when the
+// timers fired late and the rows may have stopped mid-flip. Animations are
+// finished at once, time-dependent remarks are recomputed, and the boards
+// shown are fetched if their interval passed meanwhile, or else have their
+// countdown restarted from their last fetch
+func (m *BoardModel) awake(now time.Time, away time.Duration) tea.Cmd {
+	slog.Info("board resumed", "away", away.Round(time.Second))
+	m.lastClock = now
+	for _, board := range m.shownBoards() {
+		// Every animation is older than its duration now, so one tick ends it
+		board.Tick()
+	}
+	for _, t := range m.tabs {
+		t.board.RefreshRemarks(now)
+	}
+	cmds := []tea.Cmd{m.startAnimation()}
+	switch {
+	case m.quietPaused:
+		// Quiet hours end on the next clock tick if they are over
+	case m.sideBySide:
+		if lead := m.tabs[0]; now.Sub(lead.lastFetch) >= m.tabInterval(lead) {
+			cmds = append(cmds, m.refreshPanes())
+		}
+	default:
+		cmds = append(cmds, m.resume(m.current()))
+	}
+	return tea.Batch(cmds...)
+}
+
+// focusChanged pauses the animation ticker while the terminal reports it has
+// lost focus, if PAUSE_UNFOCUSED is set, and catches the animations up
+// once it is focused again
+func (m *BoardModel) focusChanged(focused bool) tea.Cmd {
+	if !m.cfg.PauseUnfocused || m.blurred == !focused {
+		return nil
+	}
+	m.blurred = !focused
+	if m.blurred {
+		slog.Debug("terminal unfocused, animations paused")
+		return nil
+	}
+	for _, board := range m.shownBoards() {
+		board.Tick()
+	}
+	return m.startAnimation()
+}
@@ -0,0 +1,81 @@
+package fids
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAwakeAfterSuspend feeds the clock ticks of a board suspended mid-flip,
+// checking that a tick arriving late by more than suspendGap finishes the
+// animation at once and refetches a board whose interval passed meanwhile
+func TestAwakeAfterSuspend(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		since   time.Duration // Time since the last fetch when the board resumes
+		fetches int
+	}{
+		{"interval passed", 11 * time.Minute, 1},
+		{"interval still running", 5 * time.Minute, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := lookaheadConfig(6)
+			cfg.BlinkDuration = 20 * time.Millisecond
+			provider := &fakeProvider{flights: modelFlights(3, time.Now())}
+			m := newTestModel(t, provider, WithConfig(cfg))
+			interval := m.tabInterval(m.current())
+
+			start := time.Now()
+			m = update(t, m, TickClockMsg(start))
+			m = update(t, m, TickClockMsg(start.Add(clockInterval)))
+			provider.flights[0].Gate = "C7"
+			m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+			if !m.Board().IsAnimating() {
+				t.Fatal("gate change doesn't animate")
+			}
+
+			// Suspended: the animation's time runs out while no tick arrives
+			time.Sleep(2 * cfg.BlinkDuration)
+			m.current().fetched = true // As by the scheduled fetches the test applies itself
+			m.current().lastFetch = time.Now().Add(-tt.since)
+			used := m.budget.Used()
+			m = update(t, m, TickClockMsg(start.Add(2*clockInterval+suspendGap+time.Hour)))
+			if m.Board().IsAnimating() {
+				t.Error("animation still under way after resuming")
+			}
+			if fetches := m.budget.Used() - used; fetches != tt.fetches {
+				t.Errorf("%d fetches on resuming %s after the last, want %d", fetches, tt.since, tt.fetches)
+			}
+			if tt.fetches == 0 {
+				if want := m.current().lastFetch.Add(interval); m.Board().NextUpdate.Sub(want).Abs() > time.Second {
+					t.Errorf("next update at %s, want %s after the last fetch", m.Board().NextUpdate, interval)
+				}
+			}
+		})
+	}
+}
+
+// TestNoteClock checks which gaps between clock ticks count as time away
+func TestNoteClock(t *testing.T) {
+	m := newTestModel(t, &fakeProvider{})
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	if away := m.noteClock(start); away != 0 {
+		t.Errorf("first tick away %s", away)
+	}
+	at := start
+	for _, tt := range []struct {
+		gap  time.Duration
+		away bool
+	}{
+		{clockInterval, false},
+		{clockInterval + suspendGap, false}, // Late, but not by more than suspendGap
+		{clockInterval + suspendGap + time.Millisecond, true},
+		{clockInterval, false},
+		{8 * time.Hour, true},
+	} {
+		at = at.Add(tt.gap)
+		away := m.noteClock(at)
+		if (away > 0) != tt.away || (tt.away && away != tt.gap) {
+			t.Errorf("tick %s after the last: away %s, want away %v", tt.gap, away, tt.away)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package fids
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tab is one board of the tabbed interface with its own flights, pagination
+// and fetch cadence
+type tab struct {
+	id        int
+	spec      config.TabSpec
+	board     *ui.Board
+	loading   bool
+	err       error
+	failures  int       // Fetches failed in a row
+	fetched   bool      // Data has been requested at least once
+	lastFetch time.Time // When data was last requested
+	tickSeq   int       // Identifies the current API tick chain; older ticks are ignored
+	empty     int       // Fetches in a row that found no flights
+	hintShown bool      // Whether the nearby airports hint was shown for this run of empty fetches
+	inFlight  bool      // A fetch is under way
+	refetch   bool      // A fetch came due while one was under way; fetch again when it returns
+	coalesced int       // Fetches folded into a refetch because one was under way
+	unsettled bool      // A fetch was applied since the board last settled
+	rebuild   bool      // The next fetch applied rebuilds the rows, after stepping a replay
+	// directionOverride is when the direction schedule applies to the tab
+	// again, after its direction was chosen by hand
+	directionOverride time.Time
+	// airportFlights holds the last flights fetched for each airport of an
+	// interleaved board, shown in place of an airport whose fetch fails
+	airportFlights map[string][]models.Flight
+	// startup paces the fetches of a board with no flights yet until one
+	// succeeds; nil once one has, or for a board reopened from the cache
+	startup *api.RetrySchedule
+}
+
+// newTab creates a tab for spec, reusing a cached board when one is available
+func (m *BoardModel) newTab(spec config.TabSpec) *tab {
+	m.nextTabID++
+	t := &tab{id: m.nextTabID, spec: spec}
+	m.loadBoard(t)
+	return t
+}
+
+// newBoard creates an empty board for spec using the model's display settings
+func (m BoardModel) newBoard(spec config.TabSpec) *ui.Board {
+	board := ui.NewBoard(spec.AirportCode, api.GetAirportTimezone(spec.AirportCode), m.cfg.FlightsPerPage)
+	board.SetDirection(spec.Direction)
+	board.SetLayoutMode(m.layout)
+	board.SetShuttles(m.shuttles, m.cfg.ShuttleFlights)
+	board.SetWatch(m.watchFlights, m.watchPlaces)
+	board.SetViewMode(m.view)
+	board.SetTimeZoneMode(m.timeZone)
+	board.SetDestinationOnly(m.destinationFor(spec))
+	board.SetRemarkTemplates(m.remarks)
+	if m.display != nil {
+		board.SetStyles(m.display.Styles)
+	}
+	board.SetGlyphs(m.glyphs)
+	board.SetPalette(m.palette)
+	board.Seen = m.seen
+	board.Gates = m.gates
+	board.Inbound = m.inbound.shown
+	board.Tracking = m.tracking.shown
+	board.NewBadgeFor = m.cfg.NewBadgeDuration
+	board.StaleAfter = m.cfg.StaleEstimateAfter
+	board.RetimedFor = m.cfg.RetimedUpdates
+	board.PinImminent = m.cfg.PinImminent
+	board.RotateEvery = m.cfg.PageRotationInterval
+	board.AdaptiveRotate = m.cfg.AdaptiveRotation
+	board.SetBorders(m.borders)
+	board.LargeHeader = m.cfg.LargeHeader
+	board.HeaderSuffix = m.cfg.HeaderSuffix
+	board.SetScrollColumns(m.scrollColumns)
+	board.Timeline = m.cfg.ShowTimeline
+	board.CancelStrip = m.cfg.CancellationStrip
+	board.PageTransitions = m.cfg.PageTransitions
+	board.Animation = ui.AnimationTiming{Phase: m.cfg.BlinkPhase, Duration: m.cfg.BlinkDuration}
+	if spec.Merged != "" {
+		board.SetMergedAirports(spec.Airports(), api.GetAirportTimezone)
+	}
+	if m.cfg.OperationalDay == "" {
+		// The operational day ends at a time of day, so its bar runs to the last flight
+		board.Lookahead = time.Duration(m.lookahead) * time.Hour
+	}
+	board.SetTerminalSize(m.boardWidth(), m.termHeight)
+	return board
+}
+
+// loadBoard gives a tab the cached board for its spec, or a new empty board
+// A cached board is shown immediately while fresh data is fetched
+func (m BoardModel) loadBoard(t *tab) {
+	t.airportFlights = nil
+	t.empty, t.hintShown = 0, false
+	t.inFlight, t.refetch = false, false // A fetch for the previous board is ignored when it returns
+	board, ok := m.cache.take(t.spec, m.cacheKey(), time.Now())
+	if !ok {
+		t.board = m.newBoard(t.spec)
+		t.loading = true
+		t.fetched = false
+		t.startup = api.NewRetrySchedule(m.startupRetries)
+		return
+	}
+	board.SetTerminalSize(m.boardWidth(), m.termHeight)
+	board.SetLayoutMode(m.layout)
+	board.SetViewMode(m.view)
+	board.SetTimeZoneMode(m.timeZone)
+	board.SetDestinationOnly(m.destinationFor(t.spec))
+	board.ClearSelection()
+	board.Provenance.Cached = true
+	t.board = board
+	t.startup = nil
+	t.loading = false
+	t.fetched = false // Refresh as soon as the tab is shown
+}
+
+// destinationFor returns the destination filter of a board for spec: its
+// route's destination, or the filter chosen with 'f'
+func (m BoardModel) destinationFor(spec config.TabSpec) string {
+	if spec.Destination != "" {
+		return spec.Destination
+	}
+	return m.destination
+}
+
+// stashBoard keeps a tab's board in the cache before the tab stops showing it
+func (m BoardModel) stashBoard(t *tab) {
+	t.board.SetPageInput(false, "")
+	m.cache.put(t.spec, t.board, m.cacheKey(), time.Now())
+}
+
+// current returns the active tab
+func (m BoardModel) current() *tab {
+	return m.tabs[m.active]
+}
+
+// tabByID returns the tab with the given id, or nil if it has been closed
+func (m BoardModel) tabByID(id int) *tab {
+	for _, t := range m.tabs {
+		if t.id == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// tabLabels returns the labels shown in the tab bar
+func (m BoardModel) tabLabels() []string {
+	labels := make([]string, 0, len(m.tabs))
+	for _, t := range m.tabs {
+		labels = append(labels, t.spec.Label())
+	}
+	return labels
+}
+
+// tabBarHeight returns the number of lines above the board used by the tab bar
+func (m BoardModel) tabBarHeight() int {
+	if len(m.tabs) > 1 {
+		return 1
+	}
+	return 0
+}
+
+// activate switches to the tab at index, fetching its data if it is new or stale
+func (m *BoardModel) activate(index int) tea.Cmd {
+	if index < 0 || index >= len(m.tabs) || index == m.active {
+		return nil
+	}
+	m.stopPageEntry()
+	m.active = index
+	m.rotationPause = time.Time{}
+	m.registerBudget()
+	if m.sideBySide {
+		// Every board is shown and refreshed together; the keys move to this one
+		return nil
+	}
+	return tea.Batch(m.resume(m.current()), m.startAnimation())
+}
+
+// addTab opens a tab for spec, or switches to an existing tab showing the same board
+func (m *BoardModel) addTab(spec config.TabSpec) tea.Cmd {
+	for i, t := range m.tabs {
+		if t.spec == spec {
+			return m.activate(i)
+		}
+	}
+	m.tabs = append(m.tabs, m.newTab(spec))
+	m.fitBoards()
+	m.registerBudget()
+	return m.activate(len(m.tabs) - 1)
+}
+
+// closeTab closes the active tab, keeping at least one open, and shows the next tab
+func (m *BoardModel) closeTab() tea.Cmd {
+	if len(m.tabs) <= 1 {
+		return nil
+	}
+	m.stopPageEntry()
+	m.stashBoard(m.current())
+	m.tabs = append(m.tabs[:m.active:m.active], m.tabs[m.active+1:]...)
+	if m.active >= len(m.tabs) {
+		m.active = len(m.tabs) - 1
+	}
+	m.fitBoards()
+	m.registerBudget()
+	m.rotationPause = time.Time{}
+	if m.sideBySide {
+		return m.startAnimation()
+	}
+	return tea.Batch(m.resume(m.current()), m.startAnimation())
+}
+
+// tabInterval returns the delay between fetches for a tab: the normal
+// interval while it is shown, and the slower background interval otherwise
+func (m BoardModel) tabInterval(t *tab) time.Duration {
+	if m.shown(t) {
+		return m.updateInterval(t)
+	}
+	return m.cfg.BackgroundInterval
+}
+
+// startFetch fetches a tab's flights, unless a fetch is already under way,
+// in which case the tab fetches again as soon as that one returns. However
+// slow the API, a tab never has more than one request running
+func (m BoardModel) startFetch(t *tab, now time.Time) tea.Cmd {
+	t.fetched = true
+	if t.inFlight {
+		t.refetch = true
+		t.coalesced++
+		slog.Debug("fetch still under way, fetching again when it returns",
+			"airport", t.spec.AirportCode, "direction", t.spec.Direction, "coalesced", t.coalesced)
+		return nil
+	}
+	kind := api.CallBackground
+	if m.shown(t) {
+		kind = api.CallBoard
+	}
+	if !m.budget.Allow(kind, fetchCalls(t, m.cfg.MaxPages)) {
+		return nil // Deferred to the tab's next fetch
+	}
+	t.inFlight = true
+	t.lastFetch = now
+	return fetchFlights(m.provider, t.id, t.spec, m.lookahead, m.cfg.MaxPages)
+}
+
+// fetchReturned marks a tab's fetch as over, returning the fetch that came
+// due meanwhile, if any
+func (m BoardModel) fetchReturned(t *tab) tea.Cmd {
+	t.inFlight = false
+	if !t.refetch {
+		return nil
+	}
+	t.refetch = false
+	return m.startFetch(t, time.Now())
+}
+
+// refresh fetches a tab's flights now and schedules its next fetch
+func (m BoardModel) refresh(t *tab) tea.Cmd {
+	now := time.Now()
+	t.tickSeq++
+
+	fetch := m.startFetch(t, now)
+	interval := m.tabInterval(t)
+	if interval <= 0 {
+		// Background refreshes are disabled; the tab refreshes when shown again
+		t.board.NextUpdate = time.Time{}
+		return fetch
+	}
+	t.board.NextUpdate = now.Add(interval)
+	return tea.Batch(fetch, tickAPI(t.id, t.tickSeq, interval))
+}
+
+// retryStartup keeps the loading screen up after a board's first fetches
+// fail, and while the startup delays last, tries again sooner than the
+// normal interval would, so a board started before the network comes up
+// fills in soon after it does. Tabs not shown wait for their usual fetch
+func (m BoardModel) retryStartup(t *tab, now time.Time) tea.Cmd {
+	if t.startup == nil {
+		return nil
+	}
+	t.loading = t.board.FlightCount() == 0
+	delay, ok := t.startup.Failed()
+	if !ok || !m.shown(t) {
+		return nil
+	}
+	if next := t.board.NextUpdate; !next.IsZero() && next.Before(now.Add(delay)) {
+		return nil // The normal fetch comes sooner
+	}
+	slog.Info("first fetch failed, retrying", "airport", t.spec.AirportCode, "direction", t.spec.Direction,
+		"attempt", t.startup.Attempts(), "retry_in", delay)
+	t.tickSeq++
+	t.board.NextUpdate = now.Add(delay)
+	return tickAPI(t.id, t.tickSeq, delay)
+}
+
+// loadingText is shown in place of a board with no flights yet: once its
+// first fetch has failed, how many attempts have and when the next is due
+func loadingText(t *tab, now time.Time) string {
+	if t.startup == nil || !t.startup.Retrying() {
+		return "Loading flights..."
+	}
+	text := fmt.Sprintf("Loading flights... attempt %d failed", t.startup.Attempts())
+	if t.err != nil {
+		text += ": " + t.err.Error()
+	}
+	switch next := t.board.NextUpdate; {
+	case t.inFlight:
+		text += "\nRetrying now..."
+	case !next.IsZero():
+		wait := max(0, next.Sub(now).Round(time.Second))
+		text += fmt.Sprintf("\nRetrying at %s (in %s)", next.In(t.board.AirportTZ).Format("15:04:05"), wait)
+	}
+	return text
+}
+
+// resume restarts a tab's fetch schedule at its normal interval, fetching
+// immediately on first activation or if its data is stale
+func (m BoardModel) resume(t *tab) tea.Cmd {
+	interval := m.tabInterval(t)
+	if !t.fetched || time.Since(t.lastFetch) >= interval {
+		return m.refresh(t)
+	}
+	t.tickSeq++
+	remaining := time.Until(t.lastFetch.Add(interval))
+	t.board.NextUpdate = time.Now().Add(remaining)
+	return tickAPI(t.id, t.tickSeq, remaining)
+}
@@ -0,0 +1,166 @@
+package fids
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestStartupRetries fails a board's first fetches, checking that each is
+// retried after the next of the startup delays, then at the normal interval,
+// and that the loading screen counts the attempts and says when the next is
+func TestStartupRetries(t *testing.T) {
+	m := newLoadingModel(t, &fakeProvider{})
+	failure := errors.New("network is unreachable")
+	failed := FlightsMsg{Tab: m.current().id, Err: failure, spec: m.current().spec}
+	if got := loadingText(m.current(), time.Now()); got != "Loading flights..." {
+		t.Errorf("before the first fetch returned: %q", got)
+	}
+
+	for i, delay := range []time.Duration{10 * time.Second, 30 * time.Second, 60 * time.Second} {
+		if i > 0 {
+			m = retry(t, m)
+		}
+		before := time.Now()
+		model, cmd := m.Update(failed)
+		m = model.(BoardModel)
+		next := m.Board().NextUpdate
+		if cmd == nil || next.Before(before.Add(delay)) || next.After(time.Now().Add(delay)) {
+			t.Fatalf("attempt %d: retry at %s, want %s on", i+1, next.Sub(before).Round(time.Second), delay)
+		}
+		want := fmt.Sprintf("Loading flights... attempt %d failed: %v\nRetrying at %s (in %s)",
+			i+1, failure, next.In(m.Board().AirportTZ).Format("15:04:05"), delay)
+		if got := loadingText(m.current(), next.Add(-delay)); got != want {
+			t.Errorf("attempt %d: loading text %q, want %q", i+1, got, want)
+		}
+		if view := ansi.Strip(m.View()); !strings.Contains(view, fmt.Sprintf("attempt %d failed", i+1)) {
+			t.Errorf("attempt %d: the loading screen doesn't count it:\n%s", i+1, view)
+		}
+	}
+
+	// The delays have run out: the next fetch is the usual one
+	m = retry(t, m)
+	last := m.Board().NextUpdate
+	m = update(t, m, failed)
+	if !m.current().loading || m.Board().NextUpdate != last {
+		t.Errorf("after the startup delays: retry at %s, want the normal schedule", m.Board().NextUpdate)
+	}
+	if got := loadingText(m.current(), time.Now()); !strings.HasPrefix(got, "Loading flights... attempt 4 failed") {
+		t.Errorf("after the startup delays: loading text %q", got)
+	}
+
+	// A fetch that succeeds ends the schedule
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)())
+	if m.current().startup != nil || m.current().loading {
+		t.Error("startup retries still on after a successful fetch")
+	}
+}
+
+// retry applies the tick of m's retry of its first fetch, checking the
+// loading screen says the retry is under way
+func retry(t *testing.T, m BoardModel) BoardModel {
+	t.Helper()
+	m = update(t, m, TickAPIMsg{Tab: m.current().id, Time: time.Now(), seq: m.current().tickSeq})
+	if got := loadingText(m.current(), time.Now()); !m.current().inFlight || !strings.HasSuffix(got, "\nRetrying now...") {
+		t.Fatalf("while retrying: loading text %q", got)
+	}
+	return m
+}
+
+// TestStartupRetriesDisabled checks that with STARTUP_RETRIES=none a failed
+// first fetch waits for the normal interval
+func TestStartupRetriesDisabled(t *testing.T) {
+	cfg := config.Default()
+	cfg.StartupRetries = "none"
+	m := newLoadingModel(t, &fakeProvider{}, WithConfig(cfg))
+	model, cmd := m.Update(FlightsMsg{Tab: m.current().id, Err: errors.New("timeout"), spec: m.current().spec})
+	m = model.(BoardModel)
+	if cmd != nil || !m.Board().NextUpdate.IsZero() {
+		t.Errorf("first fetch retried at %s with no startup delays", m.Board().NextUpdate)
+	}
+	if got := loadingText(m.current(), time.Now()); got != "Loading flights... attempt 1 failed: timeout" {
+		t.Errorf("loading text %q", got)
+	}
+}
+
+// countingProvider is a slow provider counting the fetches it serves and
+// how many it served at once at most
+type countingProvider struct {
+	slowProvider
+	mu         sync.Mutex
+	calls      int
+	running    int
+	maxRunning int
+}
+
+func (p *countingProvider) GetDepartures(ctx context.Context, airportCode string, opts api.FetchOptions) (api.FetchResult, error) {
+	p.mu.Lock()
+	p.calls++
+	p.running++
+	p.maxRunning = max(p.maxRunning, p.running)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+	return p.slowProvider.GetDepartures(ctx, airportCode, opts)
+}
+
+func (p *countingProvider) GetArrivals(ctx context.Context, airportCode string, opts api.FetchOptions) (api.FetchResult, error) {
+	return p.GetDepartures(ctx, airportCode, opts)
+}
+
+// TestCoalescedFetches ticks a board's fetch schedule while a slow fetch is
+// under way, checking that no second fetch starts alongside it and that the
+// ticks missed meanwhile make one fetch once it returns
+func TestCoalescedFetches(t *testing.T) {
+	provider := &countingProvider{slowProvider: slowProvider{fakeProvider: fakeProvider{flights: modelFlights(3, time.Now())}, delay: 50 * time.Millisecond}}
+	m := newTestModel(t, provider, WithConfig(lookaheadConfig(6)))
+	tick := func(m BoardModel) BoardModel {
+		return update(t, m, TickAPIMsg{Tab: m.current().id, Time: time.Now(), seq: m.current().tickSeq})
+	}
+	used, served := m.budget.Used(), provider.calls
+
+	// The first tick starts a fetch, run here as bubbletea would
+	m = tick(m)
+	if !m.current().inFlight || m.budget.Used() != used+1 {
+		t.Fatalf("tick started %d fetches, want 1", m.budget.Used()-used)
+	}
+	returned := make(chan tea.Msg)
+	fetch := fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, 1)
+	go func() { returned <- fetch() }()
+
+	// The next ticks come due while it's slow to return
+	for range 3 {
+		m = tick(m)
+	}
+	if m.budget.Used() != used+1 || m.current().coalesced != 3 || !m.current().refetch {
+		t.Errorf("ticks during a fetch started %d more, with %d coalesced", m.budget.Used()-used-1, m.current().coalesced)
+	}
+
+	// Its return starts the one fetch owed
+	model, refetch := m.Update(<-returned)
+	m = model.(BoardModel)
+	if refetch == nil || !m.current().inFlight || m.current().refetch || m.budget.Used() != used+2 {
+		t.Fatalf("fetch returning after coalesced ticks started %d fetches, want 1", m.budget.Used()-used-1)
+	}
+	model, next := m.Update(refetch())
+	m = model.(BoardModel)
+	if next != nil || m.current().inFlight || m.budget.Used() != used+2 {
+		t.Errorf("refetch returning started another fetch, %d in all", m.budget.Used()-used)
+	}
+	if provider.calls-served != 2 || provider.maxRunning != 1 {
+		t.Errorf("provider served %d fetches, %d at once at most; want 2, one at a time", provider.calls-served, provider.maxRunning)
+	}
+}
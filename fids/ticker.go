@@ -0,0 +1,67 @@
+package fids
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/ui"
+)
+
+// tickerEmpty is the ticker's line when the board has no flights and no
+// error to show instead
+const tickerEmpty = "No flights"
+
+// tickerLine renders the ticker view: the active board's flights on a
+// single line, or while it has none, why
+func (m BoardModel) tickerLine() string {
+	t := m.current()
+	empty := tickerEmpty
+	switch {
+	case t.loading && t.board.FlightCount() == 0 && m.quietPaused:
+		empty = strings.TrimSpace(t.board.PausedBanner())
+	case t.loading && t.board.FlightCount() == 0:
+		empty = loadingText(t, time.Now())
+	case t.board.Error != "":
+		empty = t.board.Error
+	}
+	// Extra lines, as of the loading text, would break the single line
+	empty = strings.Join(strings.Fields(empty), " ")
+	return m.ticker.Render(t.board.TickerItems(), m.termWidth, empty)
+}
+
+// Inline reports whether the board draws inline rather than taking over
+// the terminal, as the single line of the ticker view does
+func (m BoardModel) Inline() bool {
+	return m.ticker != nil
+}
+
+// Once fetches the active board's flights a single time and returns its
+// ticker line, every flight or TICKER_WIDTH cells of them, for scripts. It
+// returns once the fetch has been applied and the flaps have settled, so the
+// line is the one the board would show. Nothing is published or alerted on
+func (m BoardModel) Once() (string, error) {
+	if m.ticker == nil {
+		return "", fmt.Errorf("-once needs the ticker view (-view ticker)")
+	}
+	t := m.current()
+	msg := fetchFlights(m.provider, t.id, t.spec, m.lookahead, m.cfg.MaxPages)().(FlightsMsg)
+	m.recordSpend()
+	if msg.Err != nil {
+		return "", msg.Err
+	}
+	t.board.UpdateFlights(m.pipeline.apply(t.withFailedAirports(msg)))
+	for t.board.IsAnimating() {
+		t.board.Tick()
+		time.Sleep(m.animationInterval())
+	}
+	return m.ticker.Render(t.board.TickerItems(), 0, tickerEmpty), nil
+}
+
+// newTicker returns the ticker of the ticker view, or nil for other views
+func (m BoardModel) newTicker() *ui.Ticker {
+	if m.view != ui.ViewTicker {
+		return nil
+	}
+	return &ui.Ticker{Width: m.cfg.TickerWidth, Swap: m.cfg.TickerSwap}
+}
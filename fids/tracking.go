@@ -0,0 +1,189 @@
+package fids
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/models"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trackEndedFor is how long the sidebar keeps showing a watched flight that
+// arrived or was cancelled after leaving the board
+const trackEndedFor = 30 * time.Minute
+
+// TrackMsg carries a watched flight looked up after it left the board
+type TrackMsg struct {
+	Number string // Watched flight number, without spaces
+	ID     string // Source's ID of the flight
+	Flight models.Flight
+	Err    error
+}
+
+// trackedFlight is a watched departure followed after it left the board
+type trackedFlight struct {
+	id      string
+	since   time.Time // When it left the board
+	due     time.Time // When it is next looked up
+	ended   time.Time // When it was found arrived or cancelled, zero until then
+	pending bool      // Whether a lookup is on its way
+}
+
+// watchTracking follows watched departures once they leave the board, as
+// they do on departing, looking each one up by its ID every so often until
+// it arrives, is cancelled or has been followed for as long as allowed.
+// Each lookup is an API call drawing on the hourly budget
+type watchTracking struct {
+	every       time.Duration             // How often each flight is looked up
+	maxFor      time.Duration             // How long a flight is followed, zero for none
+	shown       map[string]ui.Tracked     // Followed flights by number, shared with every board
+	flights     map[string]*trackedFlight // Followed flights by number
+	last        map[string]models.Flight  // Each watched flight as last on a board, by airport and number
+	unsupported bool                      // The data source can't look up single flights
+}
+
+// newWatchTracking follows flights for maxFor after they leave the board,
+// looking them up every every
+func newWatchTracking(maxFor, every time.Duration) *watchTracking {
+	return &watchTracking{
+		every:   every,
+		maxFor:  maxFor,
+		shown:   make(map[string]ui.Tracked),
+		flights: make(map[string]*trackedFlight),
+		last:    make(map[string]models.Flight),
+	}
+}
+
+// enabled reports whether watched flights are followed at all
+func (w *watchTracking) enabled() bool {
+	return w.maxFor > 0 && w.every > 0 && !w.unsupported
+}
+
+// observe notes the watched flights of numbers on a departures board just
+// updated, starting to follow those that have left it after leaving the
+// gate. A flight back on the board is no longer followed
+func (w *watchTracking) observe(board *ui.Board, numbers []string, now time.Time) {
+	if !w.enabled() || board.Direction != models.Departure {
+		return
+	}
+	for _, number := range numbers {
+		key := board.AirportCode + " " + number
+		if flight := board.WatchedFlight(number, now); flight != nil {
+			w.last[key] = *flight
+			w.stop(number)
+			continue
+		}
+		last, ok := w.last[key]
+		if !ok {
+			continue
+		}
+		delete(w.last, key)
+		if _, tracked := w.flights[number]; tracked || last.ID == "" || !leftGate(&last, now) {
+			continue
+		}
+		slog.Info("following watched flight after it left the board", "flight", number, "id", last.ID, "for", w.maxFor)
+		w.flights[number] = &trackedFlight{id: last.ID, since: now, due: now}
+		w.shown[number] = ui.Tracked{State: ui.TrackDeparted, Flight: last}
+	}
+}
+
+// leftGate reports whether flight has left the gate by now, or should have,
+// rather than dropping off the board for some other reason
+func leftGate(flight *models.Flight, now time.Time) bool {
+	switch flight.Status {
+	case models.StatusCancelled:
+		return false
+	case models.StatusDeparted, models.StatusTaxiingLeftGate, models.StatusTaxiingDelayed:
+		return true
+	}
+	return flight.ActualOut != nil || !flight.EffectiveDeparture().After(now)
+}
+
+// stop stops following the flight number
+func (w *watchTracking) stop(number string) {
+	delete(w.flights, number)
+	delete(w.shown, number)
+}
+
+// trackState returns how far flight, as looked up, has got
+func trackState(flight *models.Flight) ui.TrackState {
+	switch {
+	case flight.Status == models.StatusCancelled:
+		return ui.TrackCancelled
+	case flight.Status == models.StatusArrived:
+		return ui.TrackArrived
+	case flight.ActualOff != nil:
+		return ui.TrackEnroute
+	}
+	return ui.TrackDeparted
+}
+
+// pollTracked looks up the followed flights that are due, as the budget
+// allows, and stops following those that have ended or been followed for
+// long enough
+func (m BoardModel) pollTracked(now time.Time) tea.Cmd {
+	w := m.tracking
+	var cmds []tea.Cmd
+	for number, f := range w.flights {
+		if now.Sub(f.since) >= w.maxFor || !f.ended.IsZero() && now.Sub(f.ended) >= trackEndedFor {
+			w.stop(number)
+			continue
+		}
+		if f.pending || !f.ended.IsZero() || now.Before(f.due) {
+			continue
+		}
+		f.due = now.Add(w.every)
+		if !m.budget.Allow(api.CallTracking, 1) {
+			continue
+		}
+		f.pending = true
+		cmds = append(cmds, lookupTracked(m.provider, number, f.id))
+	}
+	return tea.Batch(cmds...)
+}
+
+// trackFound updates the followed flight of msg for the sidebar, ending the
+// lookups once it has arrived or been cancelled
+func (m BoardModel) trackFound(msg TrackMsg) {
+	w := m.tracking
+	f, ok := w.flights[msg.Number]
+	if !ok || f.id != msg.ID {
+		return
+	}
+	f.pending = false
+	m.recordSpend()
+	switch {
+	case errors.Is(msg.Err, api.ErrNoFlightLookup):
+		slog.Info("watched flights not followed after leaving the board", "reason", msg.Err)
+		w.unsupported = true
+		clear(w.flights)
+		clear(w.shown)
+	case msg.Err != nil:
+		slog.Warn("watched flight lookup failed", "flight", msg.Number, "id", msg.ID, "error", msg.Err)
+	default:
+		tracked := w.shown[msg.Number]
+		tracked.State = trackState(&msg.Flight)
+		if !msg.Flight.ScheduledArrival.IsZero() {
+			tracked.Flight.ScheduledArrival = msg.Flight.ScheduledArrival
+		}
+		tracked.Flight.EstimatedArrival = msg.Flight.EstimatedArrival
+		tracked.Flight.ActualOut, tracked.Flight.ActualOff = msg.Flight.ActualOut, msg.Flight.ActualOff
+		w.shown[msg.Number] = tracked
+		if tracked.State.Done() {
+			slog.Info("watched flight no longer followed", "flight", msg.Number, "state", tracked.State)
+			f.ended = time.Now()
+		}
+	}
+}
+
+func lookupTracked(provider api.FlightDataProvider, number, id string) tea.Cmd {
+	return func() tea.Msg {
+		flight, err := api.LookupFlight(context.Background(), provider, id)
+		return TrackMsg{Number: number, ID: id, Flight: flight, Err: err}
+	}
+}
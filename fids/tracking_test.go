@@ -0,0 +1,251 @@
+package fids
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/ui"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// trackingFixtures serves the AeroAPI answers of one step of a watched
+// flight's fixture sequence in testdata/tracking: the JFK departures and
+// the lookup of UA123 by its ID
+type trackingFixtures struct {
+	mu         sync.Mutex
+	departures string // File of the departures, e.g. "departures_1_scheduled.json"
+	flight     string // File of the flight lookup
+	lookups    int    // Lookups of the flight served
+}
+
+// set moves the sequence on to the departures and flight lookup of files,
+// leaving either as it was if ""
+func (f *trackingFixtures) set(departures, flight string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if departures != "" {
+		f.departures = departures
+	}
+	if flight != "" {
+		f.flight = flight
+	}
+}
+
+// served returns how many lookups of the flight were served
+func (f *trackingFixtures) served() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lookups
+}
+
+// trackingModel returns a board of JFK departures watching UA123, followed
+// for follow after it leaves the board, fetched from a FlightAware client
+// serving fixtures with their first departures
+func trackingModel(t *testing.T, follow time.Duration, opts ...func(*config.Config)) (BoardModel, *trackingFixtures) {
+	t.Helper()
+	fixtures := &trackingFixtures{departures: "departures_1_scheduled.json"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixtures.mu.Lock()
+		var file string
+		switch r.URL.Path {
+		case "/airports/JFK/flights/scheduled_departures":
+			file = fixtures.departures
+		case "/flights/UAL123-1767182400-schedule-0000":
+			file = fixtures.flight
+			fixtures.lookups++
+		}
+		fixtures.mu.Unlock()
+		if file == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", "tracking", file))
+		if err != nil {
+			t.Errorf("fixture for %s: %v", r.URL.Path, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	client := api.NewFlightAwareClient("test-key", api.WithBaseURL(server.URL))
+	client.Window.Now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	cfg := config.Default()
+	cfg.BlinkPhase = 0
+	cfg.BlinkDuration = 0
+	cfg.Watch = "UA123"
+	cfg.TrackWatchedFor = follow
+	cfg.TrackWatchedEvery = 5 * time.Minute
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return newTestModel(t, client, WithConfig(cfg)), fixtures
+}
+
+// fetchTracking applies a fetch of the board to m
+func fetchTracking(t *testing.T, m BoardModel) BoardModel {
+	t.Helper()
+	m = update(t, m, fetchFlights(m.provider, m.current().id, m.current().spec, m.lookahead, m.cfg.MaxPages)())
+	return settleModel(t, m)
+}
+
+// pollTracking applies the lookups of followed flights due at at to m,
+// reporting whether there were any
+func pollTracking(t *testing.T, m BoardModel, at time.Time) (BoardModel, bool) {
+	t.Helper()
+	cmd := m.pollTracked(at)
+	if cmd == nil {
+		return m, false
+	}
+	return update(t, m, cmd()), true
+}
+
+// trackedLine returns the tracking line of UA123 under the board's header,
+// as a board too narrow for the sidebar shows it, or "" if it isn't followed
+func trackedLine(m BoardModel) string {
+	for _, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "UA123 ") {
+			return line
+		}
+	}
+	return ""
+}
+
+// TestTrackingSequence steps a watched flight through its fixture sequence:
+// scheduled and taxiing on the board, then off it and looked up as departed,
+// enroute and arrived, after which the lookups stop
+func TestTrackingSequence(t *testing.T) {
+	m, fixtures := trackingModel(t, 6*time.Hour)
+	zone := m.Board().AirportTZ
+
+	// Scheduled, then taxiing: on the board, so not followed
+	if m.Board().WatchedFlight("UA123", time.Now()) == nil {
+		t.Fatal("UA123 isn't on the board")
+	}
+	fixtures.set("departures_2_taxiing.json", "")
+	m = fetchTracking(t, m)
+	if line := trackedLine(m); line != "" {
+		t.Errorf("taxiing flight on the board followed: %q", line)
+	}
+	if _, polled := pollTracking(t, m, time.Now()); polled {
+		t.Error("flight looked up while still on the board")
+	}
+
+	// Off the board after leaving the gate: followed, departed
+	fixtures.set("departures_3_gone.json", "flight_1_departed.json")
+	m = fetchTracking(t, m)
+	if line := trackedLine(m); line != "UA123 departed to ORD" {
+		t.Errorf("flight off the board reads %q, want departed to ORD", line)
+	}
+	start := time.Now()
+	m, polled := pollTracking(t, m, start)
+	if !polled || fixtures.served() != 1 {
+		t.Fatalf("followed flight looked up %d times, want once", fixtures.served())
+	}
+	if want := "UA123 departed, ETA ORD " + clock(zone, 14, 45); trackedLine(m) != want {
+		t.Errorf("departed flight reads %q, want %q", trackedLine(m), want)
+	}
+
+	// Looked up again only every TRACK_WATCHED_EVERY
+	fixtures.set("", "flight_2_enroute.json")
+	if _, polled := pollTracking(t, m, start.Add(time.Minute)); polled {
+		t.Error("flight looked up again before TRACK_WATCHED_EVERY")
+	}
+	m, _ = pollTracking(t, m, start.Add(5*time.Minute))
+	if want := "UA123 enroute, ETA ORD " + clock(zone, 14, 42); trackedLine(m) != want {
+		t.Errorf("airborne flight reads %q, want %q", trackedLine(m), want)
+	}
+
+	// Arrived: the lookups stop, and the last state stays up a while
+	fixtures.set("", "flight_3_arrived.json")
+	m, _ = pollTracking(t, m, start.Add(10*time.Minute))
+	if want := "UA123 arrived ORD " + clock(zone, 14, 39); trackedLine(m) != want {
+		t.Errorf("arrived flight reads %q, want %q", trackedLine(m), want)
+	}
+	for _, after := range []time.Duration{15 * time.Minute, 20 * time.Minute} {
+		if _, polled := pollTracking(t, m, start.Add(after)); polled {
+			t.Errorf("arrived flight looked up %s on", after)
+		}
+	}
+	if fixtures.served() != 3 {
+		t.Errorf("flight looked up %d times, want 3", fixtures.served())
+	}
+	m, _ = pollTracking(t, m, time.Now().Add(trackEndedFor))
+	if line := trackedLine(m); line != "" {
+		t.Errorf("arrived flight still reads %q after %s", line, trackEndedFor)
+	}
+}
+
+// TestTrackingCancelled checks that a flight found cancelled after leaving
+// the board reads so and is looked up no more
+func TestTrackingCancelled(t *testing.T) {
+	m, fixtures := trackingModel(t, 6*time.Hour)
+	fixtures.set("departures_2_taxiing.json", "")
+	m = fetchTracking(t, m)
+	fixtures.set("departures_3_gone.json", "flight_3_cancelled.json")
+	m = fetchTracking(t, m)
+	start := time.Now()
+	m, _ = pollTracking(t, m, start)
+	if line := trackedLine(m); line != "UA123 cancelled" {
+		t.Errorf("cancelled flight reads %q, want UA123 cancelled", line)
+	}
+	if _, polled := pollTracking(t, m, start.Add(time.Hour)); polled || fixtures.served() != 1 {
+		t.Errorf("cancelled flight looked up %d times, want once", fixtures.served())
+	}
+}
+
+// TestTrackingMaxDuration checks that a flight is followed no longer than
+// TRACK_WATCHED_FOR, however far it has got
+func TestTrackingMaxDuration(t *testing.T) {
+	m, fixtures := trackingModel(t, 20*time.Minute)
+	fixtures.set("departures_2_taxiing.json", "")
+	m = fetchTracking(t, m)
+	fixtures.set("departures_3_gone.json", "flight_2_enroute.json")
+	m = fetchTracking(t, m)
+	start := time.Now()
+	m, _ = pollTracking(t, m, start)
+	if state := m.tracking.shown["UA123"].State; state != ui.TrackEnroute {
+		t.Fatalf("flight %s, want enroute", state)
+	}
+	m, polled := pollTracking(t, m, start.Add(20*time.Minute))
+	if polled || trackedLine(m) != "" {
+		t.Errorf("flight still followed after TRACK_WATCHED_FOR: %q", trackedLine(m))
+	}
+}
+
+// TestTrackingBudget checks that lookups of followed flights are deferred
+// while the hourly budget only has room for the board's own fetches
+func TestTrackingBudget(t *testing.T) {
+	m, fixtures := trackingModel(t, 6*time.Hour, func(cfg *config.Config) {
+		cfg.MaxPages = 1
+		cfg.MaxCallsPerHour = 6 // A fetch of the board every 10 minutes
+	})
+	fixtures.set("departures_2_taxiing.json", "flight_1_departed.json")
+	m = fetchTracking(t, m)
+	fixtures.set("departures_3_gone.json", "")
+	m = fetchTracking(t, m)
+	deferred := m.budget.Deferred()
+	if _, polled := pollTracking(t, m, time.Now()); polled || fixtures.served() != 0 {
+		t.Errorf("flight looked up %d times with no room in the budget", fixtures.served())
+	}
+	if m.budget.Deferred() != deferred+1 {
+		t.Errorf("%d calls deferred, want the lookup deferred", m.budget.Deferred()-deferred)
+	}
+}
+
+// clock returns hour:minute UTC on the fixtures' day as the board shows it
+// in zone
+func clock(zone *time.Location, hour, minute int) string {
+	return time.Date(2026, 1, 1, hour, minute, 0, 0, time.UTC).In(zone).Format("15:04")
+}
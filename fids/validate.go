@@ -0,0 +1,146 @@
+package fids
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode"
+
+	"fids-tui/api"
+	"fids-tui/config"
+)
+
+// checkCodes cross-checks the configured airport and airline codes against
+// the built-in tables, returning a warning with a suggestion for each code
+// they don't list. The tables only cover major airports and airlines, so
+// unknown codes are reported but never rejected
+func (m BoardModel) checkCodes(specs []config.TabSpec, rules rules) []string {
+	var warnings []string
+	warn := func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Boards of airports without a known timezone show UTC times
+	airport := func(setting, code string, board bool) {
+		if api.KnownAirport(code) {
+			return
+		}
+		warning := fmt.Sprintf("%s: '%s' is not in the built-in airport list", setting, code)
+		if suggestion, ok := api.SuggestAirport(code); ok {
+			warning = fmt.Sprintf("%s: '%s' is not a known airport — did you mean '%s'?", setting, code, suggestion)
+		}
+		if board {
+			warning += "; its times are shown in UTC"
+		}
+		warn("%s", warning)
+	}
+	for _, spec := range specs {
+		for _, code := range spec.Airports() {
+			airport("airport", code, true)
+		}
+		if spec.Destination != "" {
+			airport("route", spec.Destination, false)
+		}
+	}
+	if m.cfg.DestinationOnly != "" {
+		airport("DESTINATION_ONLY", m.destination, false)
+	}
+	for _, code := range m.shuttles {
+		airport("PRIORITY_DESTINATIONS", code, false)
+	}
+	for _, code := range m.watchPlaces {
+		airport("WATCH", code, false)
+	}
+	for _, place := range sortedKeys(m.alerts.gatePlaces) {
+		airport("NOTIFY_ON", place, false)
+	}
+
+	for _, flight := range sortedKeys(m.alerts.flights) {
+		if text, ok := flightAirline(flight); ok {
+			if warning := airlineWarning(text); warning != "" {
+				warn("NOTIFY_ON: flight '%s': %s", flight, warning)
+			}
+		}
+	}
+	for _, flight := range m.watchFlights {
+		if text, ok := flightAirline(flight); ok {
+			if warning := airlineWarning(text); warning != "" {
+				warn("WATCH: flight '%s': %s", flight, warning)
+			}
+		}
+	}
+	for _, r := range rules {
+		// Only literal patterns name a single airline
+		if r.field == "airline" && !strings.ContainsAny(r.pattern, "*?[") {
+			if warning := airlineWarning(r.pattern); warning != "" {
+				warn("RULES_FILE: %s", warning)
+			}
+		}
+	}
+	return warnings
+}
+
+// airlineWarning describes what is wrong with an airline code that isn't in
+// the built-in airline table, suggesting the airline a name most likely
+// means, or returns an empty string for known codes
+func airlineWarning(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if api.KnownAirline(code) {
+		return ""
+	}
+	if len(code) <= 3 {
+		// Plausibly a code the table doesn't list
+		return fmt.Sprintf("'%s' is not in the built-in airline list", code)
+	}
+	if iata, name, ok := api.SuggestAirline(code); ok {
+		return fmt.Sprintf("'%s' is not an IATA code — did you mean '%s' (%s)?", code, iata, name)
+	}
+	return fmt.Sprintf("'%s' is not an IATA airline code", code)
+}
+
+// flightAirline returns the airline part of a flight number like "UA123" or
+// "DELTA123", or false if it starts with a known code or has too few letters
+// to check, like the "B6" of "B6123"
+func flightAirline(flight string) (string, bool) {
+	letters := strings.IndexFunc(flight, func(r rune) bool { return !unicode.IsLetter(r) })
+	if letters < 0 {
+		letters = len(flight)
+	}
+	if letters > 3 {
+		return flight[:letters], true // A name rather than a code
+	}
+	for _, n := range []int{2, 3} {
+		if len(flight) >= n && api.KnownAirline(flight[:n]) {
+			return "", false
+		}
+	}
+	if letters < 2 {
+		return "", false
+	}
+	return flight[:letters], true
+}
+
+// logWarnings logs the configuration warnings found when the board was built
+func (m BoardModel) logWarnings() {
+	for _, warning := range m.warnings {
+		slog.Warn("configuration check", "warning", warning)
+	}
+}
+
+// Warnings returns the problems found in the configuration that don't stop
+// the board from starting, such as airport or airline codes missing from the
+// built-in tables, for printing before the board takes over the terminal
+func (m BoardModel) Warnings() []string {
+	return m.warnings
+}
+
+// sortedKeys returns the keys of set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package fids
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fids-tui/config"
+)
+
+func TestAirlineWarning(t *testing.T) {
+	tests := []struct {
+		code string
+		want string // Empty for codes that are fine
+	}{
+		{"DL", ""},
+		{"dal", ""},
+		{"ZZ", "'ZZ' is not in the built-in airline list"}, // Plausibly a code
+		{"DELTA", "'DELTA' is not an IATA code — did you mean 'DL' (Delta Air Lines)?"},
+		{"Untied", "'UNTIED' is not an IATA code — did you mean 'UA' (United Airlines)?"},
+		{"XYZZYQ", "'XYZZYQ' is not an IATA airline code"},
+	}
+	for _, tt := range tests {
+		if got := airlineWarning(tt.code); got != tt.want {
+			t.Errorf("airlineWarning(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFlightAirline(t *testing.T) {
+	tests := []struct {
+		flight string
+		want   string // Empty if there is nothing to check
+	}{
+		{"UA123", ""},   // Known code
+		{"B6123", ""},   // Known code with a digit
+		{"DAL45", ""},   // Known ICAO code
+		{"ZZ123", "ZZ"}, // Unknown code
+		{"DELTA123", "DELTA"},
+		{"X1", ""}, // Too short to check
+		{"123", ""},
+	}
+	for _, tt := range tests {
+		got, ok := flightAirline(tt.flight)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("flightAirline(%q) = %q, %v, want %q", tt.flight, got, ok, tt.want)
+		}
+	}
+}
+
+func TestConfigWarnings(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.txt")
+	if err := os.WriteFile(rulesFile, []byte("airline DELTA hide\nairline D* hide\nairline AA hide\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.DestinationOnly = "LXA"
+	cfg.Watch = "ZY123,UA45,BOS"
+	cfg.NotifyOn = "cancelled,flight:ZZ9"
+	cfg.RulesFile = rulesFile
+	m, err := New(WithConfig(cfg), WithAirport("JKF"), WithProvider(&fakeProvider{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	want := []string{
+		"airport: 'JKF' is not a known airport — did you mean 'JFK'?; its times are shown in UTC",
+		"DESTINATION_ONLY: 'LXA' is not a known airport — did you mean 'LAX'?",
+		"NOTIFY_ON: flight 'ZZ9': 'ZZ' is not in the built-in airline list",
+		"WATCH: flight 'ZY123': 'ZY' is not in the built-in airline list",
+		// Glob patterns may match several airlines, so only DELTA is checked
+		"RULES_FILE: 'DELTA' is not an IATA code — did you mean 'DL' (Delta Air Lines)?",
+	}
+	if got := m.Warnings(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("warnings:\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
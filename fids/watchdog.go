@@ -0,0 +1,85 @@
+package fids
+
+import (
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// watchdogPetInterval is the shortest time between two watchdog messages, so
+// fast animation ticks don't flood the service manager
+const watchdogPetInterval = time.Second
+
+// serviceNotifier reports readiness and liveness to systemd through the
+// socket named by NOTIFY_SOCKET, like sd_notify(3). Without the variable, as
+// when not run as a notify service, every method does nothing
+type serviceNotifier struct {
+	mu      sync.Mutex
+	conn    *net.UnixConn
+	ready   bool
+	lastPet time.Time
+}
+
+// newServiceNotifier connects to the socket in NOTIFY_SOCKET, if it is set
+func newServiceNotifier() *serviceNotifier {
+	n := &serviceNotifier{}
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return n
+	}
+	// Names starting with '@' are in the abstract namespace, which net
+	// handles itself
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		slog.Warn("failed to connect to service manager", "socket", socket, "error", err)
+		return n
+	}
+	n.conn = conn
+	return n
+}
+
+// Ready tells the service manager that startup finished; only the first call
+// sends anything
+func (n *serviceNotifier) Ready() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.ready {
+		return
+	}
+	n.ready = true
+	n.send("READY=1")
+}
+
+// Watchdog tells the service manager the event loop is still running
+func (n *serviceNotifier) Watchdog(now time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if now.Sub(n.lastPet) < watchdogPetInterval {
+		return
+	}
+	n.lastPet = now
+	n.send("WATCHDOG=1")
+}
+
+// Close tells the service manager the program is stopping and closes the socket
+func (n *serviceNotifier) Close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.send("STOPPING=1")
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+}
+
+// send writes state to the socket; callers hold mu
+func (n *serviceNotifier) send(state string) {
+	if n.conn == nil {
+		return
+	}
+	if _, err := n.conn.Write([]byte(state)); err != nil {
+		slog.Warn("failed to notify service manager", "state", state, "error", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"fids-tui/api"
+	"fids-tui/config"
+	"fids-tui/fids"
+	"fids-tui/models"
+	"fids-tui/setup"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runSetupScreen shows the setup screen, saving its settings to the config
+// file, and returns the exit code: zero once they are saved. airportCode,
+// from the -airport flag, is suggested as the default airport
+func runSetupScreen(cfg *config.Config, airportCode string) int {
+	path := config.ConfigFile()
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Error: no config directory for the settings; set CONFIG_FILE\n")
+		return 1
+	}
+	suggested := *cfg
+	if airportCode != "" {
+		suggested.AirportCode = strings.ToUpper(strings.TrimSpace(airportCode))
+	}
+
+	final, err := tea.NewProgram(setup.New(path, &suggested, checkAPIKey(cfg)), tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running setup: %v\n", err)
+		return 1
+	}
+	if screen, ok := final.(setup.Model); !ok || !screen.Saved() {
+		fmt.Fprintf(os.Stderr, "Setup cancelled; run fids-tui -setup to try again\n")
+		return 1
+	}
+	fmt.Printf("Settings saved to %s\n", path)
+	return 0
+}
+
+// checkAPIKey returns a validator fetching one departure of the airport from
+// FlightAware with the key, through the transport and base URL of cfg
+func checkAPIKey(cfg *config.Config) setup.Validator {
+	return func(ctx context.Context, apiKey, airport string) error {
+		check := *cfg
+		check.APIKey = apiKey
+		check.DataSource = "flightaware"
+		check.FallbackSource = ""
+		check.ADSBFeedURL = ""
+		provider, err := fids.NewProvider(&check, nil)
+		if err != nil {
+			return err
+		}
+		_, err = api.GetFlights(ctx, provider, models.Departure, airport, api.FetchOptions{Limit: 1})
+		return err
+	}
+}
+
+// isTerminal reports whether file is a terminal someone can answer
+// questions on, rather than a pipe or /dev/null as under systemd
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
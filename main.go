@@ -1,265 +1,341 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"fids-tui/api"
 	"fids-tui/config"
-	"fids-tui/models"
-	"fids-tui/ui"
+	"fids-tui/control"
+	"fids-tui/fids"
+	"fids-tui/metrics"
+	"fids-tui/payload"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type model struct {
-	board        *ui.Board
-	apiClient    *api.FlightAwareClient
-	cfg          *config.Config
-	airportCode  string
-	loading      bool
-	err          error
-	inputMode    bool
-	airportInput string
-}
-
-type errMsg struct {
-	err error
-}
-
-type flightsMsg struct {
-	flights []models.Flight
-	err     error
-}
-
-// Initialization
-func initialModel(airportCode string, cfg *config.Config) model {
-	apiClient := api.NewFlightAwareClient(cfg.APIKey)
-	airportTZ := api.GetAirportTimezone(airportCode)
-	board := ui.NewBoard(airportCode, airportTZ, cfg.FlightsPerPage)
-
-	return model{
-		board:        board,
-		apiClient:    apiClient,
-		cfg:          cfg,
-		airportCode:  airportCode,
-		loading:      true,
-		inputMode:    false,
-		airportInput: "",
+func main() {
+	// "fids-tui ctl ..." is the client of a running board's control socket
+	if len(os.Args) > 1 && os.Args[1] == "ctl" {
+		os.Exit(runCtl(os.Args[2:]))
 	}
-}
 
-func (m model) Init() tea.Cmd {
-	return tea.Batch(
-		fetchFlights(m.apiClient, m.airportCode, m.cfg.LookaheadHours, m.cfg.MaxPages),
-		tickAPI(m.cfg.UpdateInterval),
-		tickPageRotation(m.cfg.PageRotationInterval),
-		tickAnimation(m.cfg.CharAnimationSpeed),
-	)
-}
+	// Parse command line arguments
+	var airportCode string
+	var baseURL string
+	var insecureSkipVerify bool
+	var view string
+	var stats bool
+	var destination string
+	var route string
+	var airports string
+	var airportsLayout string
+	var kiosk bool
+	var strict bool
+	var updateData bool
+	var doctor bool
+	var runSetup bool
+	var printSchema bool
+	var forceColor bool
+	var forceUnicode bool
+	var once bool
+	var export string
+	var record string
+	var replay string
+	flag.StringVar(&airportCode, "airport", "", "Airport code (e.g., JFK, LAX)")
+	flag.StringVar(&baseURL, "base-url", "", "FlightAware AeroAPI base URL (overrides FLIGHTAWARE_BASE_URL)")
+	flag.StringVar(&view, "view", "", "Board view: flights, gates, shuttle or ticker (overrides VIEW)")
+	flag.BoolVar(&once, "once", false, "Print the ticker line once and exit (with -view ticker)")
+	flag.StringVar(&export, "export", "", "Print the board's flights once as json or csv, with their source, and exit")
+	flag.StringVar(&record, "record", "", "Save the flights of every fetch to this directory for -replay (overrides RECORD_DIR)")
+	flag.StringVar(&replay, "replay", "", "Show the flights recorded in this directory by -record instead of fetching; < and > step through them")
+	flag.BoolVar(&stats, "stats", false, "Print API usage and estimated cost on exit")
+	flag.StringVar(&destination, "destination", "", "Show only departures to this airport code (overrides DESTINATION_ONLY)")
+	flag.StringVar(&route, "route", "", "Show only the departures of a route, e.g. JFK-ORD")
+	flag.StringVar(&airports, "airports", "", "Compare nearby airports on one screen, e.g. BWI,DCA (overrides AIRPORTS)")
+	flag.StringVar(&airportsLayout, "airports-layout", "", "How -airports are compared: sidebyside or interleaved (overrides AIRPORTS_LAYOUT)")
+	flag.BoolVar(&kiosk, "kiosk", false, "Ignore the keyboard and mouse until the unlock sequence is typed (overrides KIOSK)")
+	flag.BoolVar(&strict, "strict", false, "Leave out and report flights missing required fields (overrides STRICT)")
+	flag.BoolVar(&forceColor, "force-color", false, "Draw in color even if the terminal isn't detected to support it (overrides FORCE_COLOR)")
+	flag.BoolVar(&forceUnicode, "force-unicode", false, "Draw block characters and symbols even without a UTF-8 locale (overrides FORCE_UNICODE)")
+	flag.BoolVar(&runSetup, "setup", false, "Ask for the API key, default airport and display preferences, and save them to the config file")
+	flag.BoolVar(&printSchema, "print-schema", false, "Print the JSON Schema of the MQTT and webhook messages and exit")
+	flag.BoolVar(&updateData, "update-data", false, "Download fresh airline and airport data to DATA_DIR and exit")
+	flag.BoolVar(&doctor, "doctor", false, "Print the resolved data source settings, check the API can be reached through the proxy and CA settings, and exit")
+	flag.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Disable TLS certificate verification for API requests (unsafe)")
+	flag.Parse()
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		if m.inputMode {
-			// Handle input mode
-			switch msg.String() {
-			case "enter":
-				// Validate and change airport
-				newCode := strings.ToUpper(strings.TrimSpace(m.airportInput))
-				if len(newCode) == 3 {
-					// Validate airport code
-					valid := true
-					for _, r := range newCode {
-						if r < 'A' || r > 'Z' {
-							valid = false
-							break
-						}
-					}
-					if valid {
-						m.airportCode = newCode
-						m.airportInput = ""
-						m.inputMode = false
-						m.loading = true
-						m.board.Error = ""
-						airportTZ := api.GetAirportTimezone(m.airportCode)
-						m.board.SetAirport(m.airportCode, airportTZ)
-						m.board.SetFlightsPerPage(m.cfg.FlightsPerPage)
-						return m, fetchFlights(m.apiClient, m.airportCode, m.cfg.LookaheadHours, m.cfg.MaxPages)
-					}
-				}
-				// Invalid code, exit input mode
-				m.airportInput = ""
-				m.inputMode = false
-				return m, nil
-			case "esc":
-				// Cancel input mode
-				m.airportInput = ""
-				m.inputMode = false
-				return m, nil
-			case "backspace":
-				if len(m.airportInput) > 0 {
-					m.airportInput = m.airportInput[:len(m.airportInput)-1]
-				}
-				return m, nil
-			default:
-				// Add character if it's a letter and we have space
-				if len(m.airportInput) < 3 {
-					keyStr := msg.String()
-					if len(keyStr) == 1 {
-						r := rune(keyStr[0])
-						if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') {
-							m.airportInput += strings.ToUpper(keyStr)
-						}
-					}
-				}
-				return m, nil
-			}
-		} else {
-			// Normal mode
-			switch msg.String() {
-			case "ctrl+c", "q":
-				return m, tea.Quit
-			case "a":
-				// Enter airport input mode
-				m.inputMode = true
-				m.airportInput = ""
-				return m, nil
-			}
+	if printSchema {
+		schema, err := payload.Schema()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Println(string(schema))
+		return
+	}
 
-	case errMsg:
-		m.err = msg.err
-		m.board.Error = msg.err.Error()
-		m.loading = false
-		return m, nil
-
-	case flightsMsg:
-		m.loading = false
-		if msg.err != nil {
-			m.err = msg.err
-			m.board.Error = msg.err.Error()
-		} else {
-			m.board.Error = ""
-			m.board.UpdateFlights(msg.flights)
+	// Load configuration; flags override it
+	applyFlags := func(cfg *config.Config) {
+		if baseURL != "" {
+			cfg.FlightAwareBaseURL = baseURL
+		}
+		if view != "" {
+			cfg.View = view
+		}
+		if destination != "" {
+			cfg.DestinationOnly = destination
+		}
+		if airports != "" {
+			cfg.Airports = airports
+		}
+		if airportsLayout != "" {
+			cfg.AirportsLayout = strings.ToLower(airportsLayout)
+		}
+		if kiosk {
+			cfg.Kiosk = true
+		}
+		if strict {
+			cfg.Strict = true
+		}
+		if record != "" {
+			cfg.RecordDir = record
+		}
+		if replay != "" {
+			cfg.ReplayDir = replay
 		}
-		return m, nil
+		if forceColor {
+			cfg.ForceColor = true
+		}
+		if forceUnicode {
+			cfg.ForceUnicode = true
+		}
+		// Escape hatch for broken TLS interception; never read from the environment
+		if insecureSkipVerify {
+			cfg.InsecureSkipVerify = true
+		}
+	}
+	cfg := config.LoadConfig()
+	applyFlags(cfg)
 
-	case tickAPIMsg:
-		// Fetch flights on API tick
-		return m, tea.Batch(
-			fetchFlights(m.apiClient, m.airportCode, m.cfg.LookaheadHours, m.cfg.MaxPages),
-			tickAPI(m.cfg.UpdateInterval),
-		)
+	// Logs go to a file since the terminal is taken over by the board
+	logFile, err := setupLogging(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeLog(logFile)
 
-	case tickPageRotationMsg:
-		// Rotate to next page
-		m.board.NextPage()
-		return m, tickPageRotation(m.cfg.PageRotationInterval)
+	if cfg.FileError != nil {
+		fmt.Fprintf(os.Stderr, "Warning: config file ignored: %v\n", cfg.FileError)
+	}
+	if insecureSkipVerify {
+		fmt.Fprintf(os.Stderr, "WARNING: TLS certificate verification is disabled (-insecure-skip-verify)\n")
+	}
 
-	case tickAnimationMsg:
-		// Update character animations
-		m.board.Tick()
-		return m, tickAnimation(m.cfg.CharAnimationSpeed)
+	if updateData {
+		os.Exit(runUpdateData(cfg))
+	}
+	if doctor {
+		os.Exit(runDoctor(os.Stdout, cfg, airportCode))
 	}
 
-	return m, nil
-}
+	// The first run asks for the settings the board needs, as does -setup
+	if runSetup || (config.NeedsSetup(cfg) && isTerminal(os.Stdin)) {
+		if code := runSetupScreen(cfg, airportCode); code != 0 {
+			os.Exit(code)
+		}
+		cfg = config.LoadConfig()
+		applyFlags(cfg)
+	}
 
-func (m model) View() string {
-	if m.inputMode {
-		// Show input prompt
-		prompt := fmt.Sprintf("Enter airport code (3 letters): %s_", m.airportInput)
-		return fmt.Sprintf("%s\n\n%s", prompt, m.board.Render())
+	// Downloaded airline and airport data is newer than the built-in tables
+	if cfg.DataDir != "" {
+		if err := api.LoadData(cfg.DataDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: using built-in airline and airport data: %v\n", err)
+		}
 	}
-	if m.loading && len(m.board.Flights) == 0 {
-		return "Loading flights...\n"
+
+	// The route and airport flags show a single board; otherwise the boards
+	// compare AIRPORTS, or tabs come from TABS, or a single board for
+	// AIRPORT_CODE
+	var opts []fids.Option
+	opts = append(opts, fids.WithConfig(cfg))
+	if route != "" {
+		spec, err := config.ParseRoute(route)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, fids.WithTabs(spec))
+		airportCode = ""
+	} else if airportCode == "" && cfg.Tabs == "" && cfg.Airports == "" {
+		airportCode = cfg.AirportCode
+		if airportCode == "" {
+			fmt.Fprintf(os.Stderr, "Error: Airport code required. Use -airport flag or set AIRPORT_CODE, AIRPORTS or TABS in the environment or the config file.\n")
+			os.Exit(1)
+		}
 	}
-	view := m.board.Render()
-	if !m.inputMode {
-		// Add help text at the bottom
-		help := "\nPress 'a' to change airport | 'q' to quit"
-		view += help
+	if airportCode != "" {
+		// Validate and normalize airport code (uppercase, 3 or 4 characters)
+		airportCode, err = config.NormalizeAirportCode(airportCode)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, fids.WithAirport(airportCode))
 	}
-	return view
-}
 
-// Commands
-type tickAPIMsg time.Time
-type tickPageRotationMsg time.Time
-type tickAnimationMsg time.Time
+	// Terminals without UTF-8 or colors get a board they can show
+	display := chooseDisplay(cfg)
+	opts = append(opts, fids.WithDisplay(display))
 
-func tickAPI(duration time.Duration) tea.Cmd {
-	return tea.Tick(duration, func(t time.Time) tea.Msg {
-		return tickAPIMsg(t)
-	})
-}
-
-func tickPageRotation(duration time.Duration) tea.Cmd {
-	return tea.Tick(duration, func(t time.Time) tea.Msg {
-		return tickPageRotationMsg(t)
-	})
-}
+	// Build the board model (validates the data source and display settings)
+	board, err := fids.New(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-func tickAnimation(duration time.Duration) tea.Cmd {
-	return tea.Tick(duration, func(t time.Time) tea.Msg {
-		return tickAnimationMsg(t)
-	})
-}
+	// Warnings stay in the terminal's scrollback once the board exits
+	for _, warning := range board.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
 
-func fetchFlights(client *api.FlightAwareClient, airportCode string, hours int, maxPages int) tea.Cmd {
-	return func() tea.Msg {
-		flights, err := client.GetDepartures(airportCode, hours, maxPages)
-		return flightsMsg{flights: flights, err: err}
+	if once {
+		line, err := board.Once()
+		board.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			closeLog(logFile)
+			os.Exit(1)
+		}
+		fmt.Println(line)
+		return
+	}
+	if export != "" {
+		out, err := board.Export(strings.ToLower(export))
+		board.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			closeLog(logFile)
+			os.Exit(1)
+		}
+		fmt.Println(strings.TrimRight(out, "\n"))
+		return
 	}
-}
 
-func main() {
-	// Parse command line arguments
-	var airportCode string
-	flag.StringVar(&airportCode, "airport", "", "Airport code (e.g., JFK, LAX)")
-	flag.Parse()
+	// Initialize and run the program. The ticker's single line is drawn in
+	// place, leaving the rest of the terminal alone
+	var programOpts []tea.ProgramOption
+	if !board.Inline() {
+		programOpts = append(programOpts, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+	if cfg.PauseUnfocused {
+		// Terminals that support it report focus changes, pausing the animations
+		programOpts = append(programOpts, tea.WithReportFocus())
+	}
+	p := tea.NewProgram(board, programOpts...)
 
-	// Load configuration
-	cfg := config.LoadConfig()
+	// Scripts control the board through the socket; requests become messages
+	// handled by the event loop
+	var server *control.Server
+	if path := control.SocketPath(cfg.ControlSocket); path != "" {
+		server, err = control.Listen(path, fids.ControlHandler(p.Send))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: CONTROL_SOCKET: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	var metricsServer *metrics.Server
+	if cfg.MetricsAddr != "" {
+		metricsServer, err = metrics.Listen(cfg.MetricsAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: METRICS_ADDR: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	// Get airport code from command line, env var, or config
-	if airportCode == "" {
-		airportCode = cfg.AirportCode
+	// SIGTERM, as sent by systemd, quits the program like 'q' so the cleanup
+	// below still runs
+	final, err := p.Run()
+	if server != nil {
+		server.Close()
 	}
-	if airportCode == "" {
-		fmt.Fprintf(os.Stderr, "Error: Airport code required. Use -airport flag or set AIRPORT_CODE environment variable.\n")
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+	board.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
+		closeLog(logFile)
 		os.Exit(1)
 	}
+	if stats {
+		if board, ok := final.(fids.BoardModel); ok {
+			fmt.Println(board.UsageSummary())
+		}
+	}
+}
 
-	// Validate and normalize airport code (uppercase, 3 letters)
-	airportCode = strings.ToUpper(strings.TrimSpace(airportCode))
-	if len(airportCode) != 3 {
-		fmt.Fprintf(os.Stderr, "Error: Airport code must be 3 letters (e.g., JFK, LAX). Got: %s\n", airportCode)
-		os.Exit(1)
+// runUpdateData downloads fresh airline and airport data to the data
+// directory and returns the exit code
+func runUpdateData(cfg *config.Config) int {
+	if cfg.DataDir == "" {
+		fmt.Fprintf(os.Stderr, "Error: no data directory; set DATA_DIR\n")
+		return 1
 	}
+	transport, err := api.NewTransport(api.TransportConfig{
+		CACertFile:         cfg.CACertFile,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: CA_CERT_FILE: %v\n", err)
+		return 1
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	update, err := api.UpdateData(ctx, &http.Client{Transport: transport}, cfg.DataDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: data not updated: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Updated %d airlines and %d airports in %s\n", update.Airlines, update.Airports, cfg.DataDir)
+	return 0
+}
 
-	// Validate airport code contains only letters
-	for _, r := range airportCode {
-		if r < 'A' || r > 'Z' {
-			fmt.Fprintf(os.Stderr, "Error: Airport code must contain only letters (e.g., JFK, LAX). Got: %s\n", airportCode)
-			os.Exit(1)
-		}
+// closeLog flushes the log file to disk and closes it
+func closeLog(file *os.File) {
+	if file == nil {
+		return
 	}
+	file.Sync()
+	file.Close()
+}
 
-	// Validate API key
-	if cfg.APIKey == "" {
-		fmt.Fprintf(os.Stderr, "Error: FLIGHTAWARE_API_KEY environment variable is required.\n")
-		os.Exit(1)
+// setupLogging installs the default logger writing to LOG_FILE at LOG_LEVEL,
+// or discarding logs when no file is configured
+func setupLogging(cfg *config.Config) (*os.File, error) {
+	if cfg.LogFile == "" {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return nil, nil
 	}
 
-	// Initialize and run the program
-	p := tea.NewProgram(initialModel(airportCode, cfg), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
-		os.Exit(1)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return nil, fmt.Errorf("LOG_LEVEL: %w", err)
+	}
+
+	file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(file, &slog.HandlerOptions{Level: level})))
+	return file, nil
 }
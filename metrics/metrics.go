@@ -0,0 +1,194 @@
+// Package metrics exposes the board's measurements to Prometheus in its text
+// exposition format. Only histograms are kept, which is all the board
+// records, so there is no dependency on the Prometheus client library
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RequestBuckets are the upper bounds, in seconds, of the buckets request
+// durations are counted in: from a fast cached answer to a timeout
+var RequestBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// registry holds every histogram created, in the order they were created
+var registry struct {
+	mu         sync.Mutex
+	histograms []*Histogram
+}
+
+// Histogram counts observations in buckets by their label values, as a
+// Prometheus histogram
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64
+	labels  []string
+
+	mu     sync.Mutex
+	series map[string]*series // By label values joined with "\xff"
+}
+
+// series is the counts of one set of label values
+type series struct {
+	values []string
+	counts []uint64 // Observations at most each bucket's bound, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a histogram with the given bucket bounds, in
+// ascending order, and label names, and registers it to be written by
+// WriteText and served by Handler
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, buckets: buckets, labels: labels, series: make(map[string]*series)}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.histograms = append(registry.histograms, h)
+	return h
+}
+
+// Observe counts value under values, one for each of the histogram's labels
+func (h *Histogram) Observe(value float64, values ...string) {
+	if len(values) != len(h.labels) {
+		panic(fmt.Sprintf("metrics: %s observed with %d label values, want %d", h.name, len(values), len(h.labels)))
+	}
+	key := strings.Join(values, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &series{values: slices.Clone(values), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i, _ := slices.BinarySearch(h.buckets, value); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += value
+}
+
+// Count returns how many values have been observed under values
+func (h *Histogram) Count(values ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[strings.Join(values, "\xff")]; ok {
+		return s.count
+	}
+	return 0
+}
+
+// write writes the histogram in the text exposition format, its series
+// sorted by label values so the output is stable
+func (h *Histogram) write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range keys {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", h.name, h.labelSet(s.values, formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket%s %d\n", h.name, h.labelSet(s.values, "+Inf"), s.count)
+		fmt.Fprintf(&b, "%s_sum%s %s\n", h.name, h.labelSet(s.values, ""), formatFloat(s.sum))
+		fmt.Fprintf(&b, "%s_count%s %d\n", h.name, h.labelSet(s.values, ""), s.count)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelSet formats values as {name="value",...}, with le last if not empty
+func (h *Histogram) labelSet(values []string, le string) string {
+	var pairs []string
+	for i, name := range h.labels {
+		pairs = append(pairs, name+"="+strconv.Quote(values[i]))
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatFloat formats a bound or sum the way Prometheus does
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// WriteText writes every histogram created in the text exposition format
+func WriteText(w io.Writer) error {
+	registry.mu.Lock()
+	histograms := slices.Clone(registry.histograms)
+	registry.mu.Unlock()
+	for _, h := range histograms {
+		if err := h.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves every histogram created to a Prometheus scrape
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteText(w)
+	})
+}
+
+// Server serves the metrics at /metrics until closed
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// Listen starts serving the metrics at /metrics on addr, such as ":9090"
+func Listen(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	s := &Server{listener: listener, server: &http.Server{Handler: mux}}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("metrics server stopped", "error", err)
+		}
+	}()
+	return s, nil
+}
+
+// Addr returns the address the metrics are served on
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops serving the metrics
+func (s *Server) Close() error {
+	return s.server.Close()
+}
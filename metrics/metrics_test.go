@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHistogramText(t *testing.T) {
+	h := NewHistogram("test_duration_seconds", "Time taken by tests.", []float64{0.1, 1}, "source", "phase")
+	h.Observe(0.05, "B", "total")
+	h.Observe(0.1, "B", "total") // A value on a bound is counted in its bucket
+	h.Observe(0.5, "B", "total")
+	h.Observe(3, "B", "total")
+	h.Observe(0.2, "A", "dns")
+
+	var b strings.Builder
+	if err := h.write(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP test_duration_seconds Time taken by tests.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{source="A",phase="dns",le="0.1"} 0
+test_duration_seconds_bucket{source="A",phase="dns",le="1"} 1
+test_duration_seconds_bucket{source="A",phase="dns",le="+Inf"} 1
+test_duration_seconds_sum{source="A",phase="dns"} 0.2
+test_duration_seconds_count{source="A",phase="dns"} 1
+test_duration_seconds_bucket{source="B",phase="total",le="0.1"} 2
+test_duration_seconds_bucket{source="B",phase="total",le="1"} 3
+test_duration_seconds_bucket{source="B",phase="total",le="+Inf"} 4
+test_duration_seconds_sum{source="B",phase="total"} 3.65
+test_duration_seconds_count{source="B",phase="total"} 4
+`
+	if b.String() != want {
+		t.Errorf("histogram text =\n%s\nwant\n%s", b.String(), want)
+	}
+	if got := h.Count("B", "total"); got != 4 {
+		t.Errorf("Count = %d, want 4", got)
+	}
+	if got := h.Count("C", "total"); got != 0 {
+		t.Errorf("Count of unseen labels = %d, want 0", got)
+	}
+}
+
+func TestListen(t *testing.T) {
+	h := NewHistogram("test_served_seconds", "Served by TestListen.", RequestBuckets)
+	h.Observe(0.3)
+
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer server.Close()
+	resp, err := http.Get("http://" + server.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the text exposition format", resp.Header.Get("Content-Type"))
+	}
+	for _, line := range []string{
+		"# TYPE test_served_seconds histogram",
+		`test_served_seconds_bucket{le="0.25"} 0`,
+		`test_served_seconds_bucket{le="0.5"} 1`,
+		"test_served_seconds_count 1",
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("scrape doesn't have %q:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Field identifies a flight field compared by DiffFlights
+type Field int
+
+const (
+	FieldGate Field = iota
+	FieldStatus
+	FieldEstimate
+	FieldRemarks
+	FieldBaggageClaim // Arrivals only
+	FieldScheduled    // Scheduled time, when the airline retimes the flight
+)
+
+// RetimeThreshold is how far a flight's scheduled time must move for it to
+// count as retimed; smaller moves are the source rounding or correcting it
+const RetimeThreshold = 5 * time.Minute
+
+// FieldChange records a field whose value differs between two versions of a
+// flight. Old and New are the display values, with estimates and scheduled
+// times as HH:MM UTC; an unknown estimate is empty
+type FieldChange struct {
+	Field Field
+	Old   string
+	New   string
+}
+
+// FlightChange pairs a flight in the old list with the same flight in the new
+// list, by index into each
+type FlightChange struct {
+	Old    int
+	New    int
+	Fields []FieldChange // Empty when nothing shown on the board changed
+}
+
+// Field returns the change to field, if it changed
+func (c FlightChange) Field(field Field) (FieldChange, bool) {
+	for _, fc := range c.Fields {
+		if fc.Field == field {
+			return fc, true
+		}
+	}
+	return FieldChange{}, false
+}
+
+// FlightDiff describes how a flight list changed between two refreshes
+type FlightDiff struct {
+	Added   []int          // Indexes into the new list of flights not in the old one
+	Removed []int          // Indexes into the old list of flights not in the new one
+	Matched []FlightChange // Flights in both lists, in new list order
+}
+
+// Changed returns the matched flights that have field changes
+func (d FlightDiff) Changed() []FlightChange {
+	var changed []FlightChange
+	for _, c := range d.Matched {
+		if len(c.Fields) > 0 {
+			changed = append(changed, c)
+		}
+	}
+	return changed
+}
+
+// DiffFlights compares two flight lists. Flights are matched by ID when both
+// have one; otherwise, such as for sources without IDs, by flight number, in
+// list order when a number appears more than once. Flights with different IDs
+// are never matched, so a flight number reused the next day is a new flight.
+// Differences that don't change what the board shows, like surrounding spaces
+// or an estimate in another timezone, are not changes
+func DiffFlights(old, new []Flight) FlightDiff {
+	var diff FlightDiff
+	oldMatched := make([]bool, len(old))
+	newMatch := make([]int, len(new))
+	for i := range newMatch {
+		newMatch[i] = -1
+	}
+
+	// Match flights with IDs first, so they can't be taken by number
+	byID := make(map[string]int)
+	for i := range old {
+		if old[i].ID != "" {
+			if _, dup := byID[old[i].ID]; !dup {
+				byID[old[i].ID] = i
+			}
+		}
+	}
+	for i := range new {
+		if new[i].ID == "" {
+			continue
+		}
+		if j, ok := byID[new[i].ID]; ok && !oldMatched[j] {
+			oldMatched[j] = true
+			newMatch[i] = j
+		}
+	}
+
+	// Then by flight number, in order, for whatever is left
+	byNumber := make(map[string][]int)
+	for i := range old {
+		if !oldMatched[i] {
+			key := strings.TrimSpace(old[i].FlightNumber)
+			byNumber[key] = append(byNumber[key], i)
+		}
+	}
+	for i := range new {
+		if newMatch[i] >= 0 {
+			continue
+		}
+		key := strings.TrimSpace(new[i].FlightNumber)
+		candidates := byNumber[key]
+		for k, j := range candidates {
+			if old[j].ID != "" && new[i].ID != "" {
+				continue // Different flights that share a number
+			}
+			oldMatched[j] = true
+			newMatch[i] = j
+			byNumber[key] = append(candidates[:k:k], candidates[k+1:]...)
+			break
+		}
+	}
+
+	for i, j := range newMatch {
+		if j < 0 {
+			diff.Added = append(diff.Added, i)
+			continue
+		}
+		diff.Matched = append(diff.Matched, FlightChange{Old: j, New: i, Fields: diffFields(&old[j], &new[i])})
+	}
+	for j, matched := range oldMatched {
+		if !matched {
+			diff.Removed = append(diff.Removed, j)
+		}
+	}
+	return diff
+}
+
+// diffFields returns the fields that differ between two versions of a flight
+func diffFields(old, new *Flight) []FieldChange {
+	var fields []FieldChange
+	if oldGate, newGate := strings.TrimSpace(old.Gate), strings.TrimSpace(new.Gate); oldGate != newGate {
+		fields = append(fields, FieldChange{Field: FieldGate, Old: oldGate, New: newGate})
+	}
+	if old.Status != new.Status {
+		fields = append(fields, FieldChange{Field: FieldStatus, Old: old.Status.String(), New: new.Status.String()})
+	}
+	if oldSched, newSched := old.ScheduledTime(), new.ScheduledTime(); retimed(oldSched, newSched) {
+		fields = append(fields, FieldChange{Field: FieldScheduled, Old: formatClock(&oldSched), New: formatClock(&newSched)})
+	}
+	if oldEst, newEst := old.EstimatedTime(), new.EstimatedTime(); !sameMinute(oldEst, newEst) {
+		fields = append(fields, FieldChange{Field: FieldEstimate, Old: formatClock(oldEst), New: formatClock(newEst)})
+	}
+	if new.Direction == Arrival {
+		if oldClaim, newClaim := strings.TrimSpace(old.BaggageClaim), strings.TrimSpace(new.BaggageClaim); oldClaim != newClaim {
+			fields = append(fields, FieldChange{Field: FieldBaggageClaim, Old: oldClaim, New: newClaim})
+		}
+	}
+	if oldRemarks, newRemarks := strings.TrimSpace(string(old.Remarks)), strings.TrimSpace(string(new.Remarks)); oldRemarks != newRemarks {
+		fields = append(fields, FieldChange{Field: FieldRemarks, Old: oldRemarks, New: newRemarks})
+	}
+	return fields
+}
+
+// retimed reports whether a scheduled time moved by more than
+// RetimeThreshold. Unknown times never count as moved
+func retimed(old, new time.Time) bool {
+	if old.IsZero() || new.IsZero() {
+		return false
+	}
+	d := new.Sub(old)
+	return d > RetimeThreshold || d < -RetimeThreshold
+}
+
+// sameMinute reports whether two optional times fall in the same minute,
+// wherever they are
+func sameMinute(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Truncate(time.Minute).Equal(b.Truncate(time.Minute))
+}
+
+// formatClock formats t as HH:MM UTC, or returns an empty string if t is nil
+func formatClock(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format("15:04")
+}
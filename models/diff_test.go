@@ -0,0 +1,194 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// diffNow is the scheduled time of the first flight in the diff tests
+var diffNow = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// diffFlight returns a departure on time at gate B2, offset minutes after
+// diffNow, with the given number and ID
+func diffFlight(number, id string, offset int) Flight {
+	return Flight{
+		ID:                 id,
+		FlightNumber:       number,
+		DestinationCode:    "LAX",
+		Gate:               "B2",
+		Status:             StatusOnTime,
+		Remarks:            RemarksOnTime,
+		ScheduledDeparture: diffNow.Add(time.Duration(offset) * time.Minute),
+	}
+}
+
+// describe summarizes a diff, e.g. "+1 -0 0>0[gate B2>C7] 1>2[]"
+func describe(diff FlightDiff) string {
+	parts := []string{fmt.Sprintf("+%v -%v", indexes(diff.Added), indexes(diff.Removed))}
+	for _, c := range diff.Matched {
+		var fields []string
+		for _, f := range c.Fields {
+			fields = append(fields, fmt.Sprintf("%s %s>%s", fieldNames[f.Field], f.Old, f.New))
+		}
+		parts = append(parts, fmt.Sprintf("%d>%d[%s]", c.Old, c.New, strings.Join(fields, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// indexes formats a list of indexes as "1,3", or "" if there are none
+func indexes(list []int) string {
+	var s []string
+	for _, i := range list {
+		s = append(s, fmt.Sprint(i))
+	}
+	return strings.Join(s, ",")
+}
+
+var fieldNames = map[Field]string{
+	FieldGate:         "gate",
+	FieldStatus:       "status",
+	FieldEstimate:     "eta",
+	FieldRemarks:      "remarks",
+	FieldBaggageClaim: "claim",
+	FieldScheduled:    "scheduled",
+}
+
+func TestDiffFlights(t *testing.T) {
+	at := func(minutes int) *time.Time {
+		t := diffNow.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+	tests := []struct {
+		name string
+		old  []Flight
+		new  func([]Flight) []Flight // Changes a copy of old
+		want string
+	}{
+		{"identical", nil, func(f []Flight) []Flight { return f }, "+ - 0>0[] 1>1[] 2>2[]"},
+		{"added at the end", nil, func(f []Flight) []Flight {
+			return append(f, diffFlight("DL 5", "DAL5-1", 90))
+		}, "+3 - 0>0[] 1>1[] 2>2[]"},
+		{"removed from the front", nil, func(f []Flight) []Flight { return f[1:] }, "+ -0 1>0[] 2>1[]"},
+		{"added and removed", nil, func(f []Flight) []Flight {
+			return []Flight{f[0], f[2], diffFlight("DL 5", "DAL5-1", 90)}
+		}, "+2 -1 0>0[] 2>1[]"},
+		{"reordered", nil, func(f []Flight) []Flight { return []Flight{f[2], f[0], f[1]} }, "+ - 2>0[] 0>1[] 1>2[]"},
+
+		// Each field shown on the board is recorded on its own
+		{"gate", nil, func(f []Flight) []Flight {
+			f[0].Gate = "C7"
+			return f
+		}, "+ - 0>0[gate B2>C7] 1>1[] 2>2[]"},
+		{"status", nil, func(f []Flight) []Flight {
+			f[1].Status = StatusCancelled
+			return f
+		}, "+ - 0>0[] 1>1[status On Time>Cancelled] 2>2[]"},
+		{"estimate", nil, func(f []Flight) []Flight {
+			f[2].EstimatedDeparture = at(95)
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[eta >13:35]"},
+		{"estimate moved", nil, func(f []Flight) []Flight {
+			f[0].EstimatedDeparture = at(20)
+			return f
+		}, "+ - 0>0[eta 12:10>12:20] 1>1[] 2>2[]"},
+		{"remarks", nil, func(f []Flight) []Flight {
+			f[0].Remarks = "Now Boarding"
+			return f
+		}, "+ - 0>0[remarks On Time>Now Boarding] 1>1[] 2>2[]"},
+		{"several fields in order", nil, func(f []Flight) []Flight {
+			f[1].Gate = "D1"
+			f[1].Status = StatusDelayed
+			f[1].EstimatedDeparture = at(75)
+			f[1].Remarks = RemarksDelayed
+			return f
+		}, "+ - 0>0[] 1>1[gate B2>D1, status On Time>Delayed, eta >13:15, remarks On Time>Delayed] 2>2[]"},
+		{"retimed", nil, func(f []Flight) []Flight {
+			f[2].ScheduledDeparture = diffNow.Add(2 * time.Hour)
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[scheduled 13:00>14:00]"},
+		{"retimed by the threshold", nil, func(f []Flight) []Flight {
+			f[2].ScheduledDeparture = f[2].ScheduledDeparture.Add(RetimeThreshold)
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+		{"baggage claim of an arrival", arrivals(), func(f []Flight) []Flight {
+			f[0].BaggageClaim = "4"
+			return f
+		}, "+ - 0>0[claim 3>4]"},
+		{"baggage claim of a departure", nil, func(f []Flight) []Flight {
+			f[0].BaggageClaim = "4"
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+
+		// Changes the board wouldn't show are not changes
+		{"surrounding spaces", nil, func(f []Flight) []Flight {
+			f[0].Gate = " B2 "
+			f[0].Remarks = RemarksOnTime + "  "
+			f[1].FlightNumber = "AA 101 "
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+		{"estimate in another timezone", nil, func(f []Flight) []Flight {
+			f[0].EstimatedDeparture = ptr(at(10).In(time.FixedZone("EST", -5*3600)))
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+		{"estimate seconds", nil, func(f []Flight) []Flight {
+			f[0].EstimatedDeparture = ptr(at(10).Add(30 * time.Second))
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+		{"schedule corrected by a minute", nil, func(f []Flight) []Flight {
+			f[0].ScheduledDeparture = f[0].ScheduledDeparture.Add(time.Minute)
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+
+		// Sources without IDs are matched by flight number, in order
+		{"no IDs", withoutIDs(), func(f []Flight) []Flight {
+			f[1].Gate = "C7"
+			return f
+		}, "+ - 0>0[] 1>1[gate B2>C7] 2>2[]"},
+		{"no IDs, number on two days", []Flight{diffFlight("AA 100", "", 0), diffFlight("AA 100", "", 24*60)}, func(f []Flight) []Flight {
+			f[1].Gate = "C7"
+			return f
+		}, "+ - 0>0[] 1>1[gate B2>C7]"},
+		{"ID lost between refreshes", nil, func(f []Flight) []Flight {
+			f[0].ID = ""
+			return f
+		}, "+ - 0>0[] 1>1[] 2>2[]"},
+		{"number reused by another flight", nil, func(f []Flight) []Flight {
+			f[0].ID = "AAL100-2"
+			return f
+		}, "+0 -0 1>1[] 2>2[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := tt.old
+			if old == nil {
+				old = []Flight{diffFlight("AA 100", "AAL100-1", 0), diffFlight("AA 101", "AAL101-1", 30), diffFlight("AA 102", "AAL102-1", 60)}
+				old[0].EstimatedDeparture = at(10)
+			}
+			new := tt.new(append([]Flight(nil), old...))
+			if got := describe(DiffFlights(old, new)); got != tt.want {
+				t.Errorf("DiffFlights = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// withoutIDs returns the flights of the diff tests as sources without IDs
+// report them
+func withoutIDs() []Flight {
+	return []Flight{diffFlight("AA 100", "", 0), diffFlight("AA 101", "", 30), diffFlight("AA 102", "", 60)}
+}
+
+// arrivals returns an arrival of the diff tests at baggage claim 3
+func arrivals() []Flight {
+	flight := diffFlight("AA 200", "AAL200-1", 0)
+	flight.Direction = Arrival
+	flight.ScheduledArrival, flight.ScheduledDeparture = flight.ScheduledDeparture, time.Time{}
+	flight.BaggageClaim = "3"
+	return []Flight{flight}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
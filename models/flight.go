@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // FlightStatus represents the status of a flight
 type FlightStatus int
@@ -11,6 +15,9 @@ const (
 	StatusTaxiingLeftGate
 	StatusTaxiingDelayed
 	StatusCancelled
+	StatusUnknown // Data source has no status information
+	StatusDeparted
+	StatusArrived
 )
 
 // String returns the string representation of the flight status
@@ -26,11 +33,47 @@ func (s FlightStatus) String() string {
 		return "Taxiing / Delayed"
 	case StatusCancelled:
 		return "Cancelled"
+	case StatusDeparted:
+		return "Departed"
+	case StatusArrived:
+		return "Arrived"
 	default:
 		return "Unknown"
 	}
 }
 
+// statusKeys are the JSON names of the flight statuses, indexed by status
+var statusKeys = [...]string{
+	StatusOnTime:          "on_time",
+	StatusDelayed:         "delayed",
+	StatusTaxiingLeftGate: "taxiing",
+	StatusTaxiingDelayed:  "taxiing_delayed",
+	StatusCancelled:       "cancelled",
+	StatusUnknown:         "unknown",
+	StatusDeparted:        "departed",
+	StatusArrived:         "arrived",
+}
+
+// MarshalText encodes the status as a stable key such as "on_time", so JSON
+// output doesn't depend on the order of the constants
+func (s FlightStatus) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(statusKeys) {
+		return []byte("unknown"), nil
+	}
+	return []byte(statusKeys[s]), nil
+}
+
+// UnmarshalText decodes a status key written by MarshalText
+func (s *FlightStatus) UnmarshalText(text []byte) error {
+	for status, key := range statusKeys {
+		if key == string(text) {
+			*s = FlightStatus(status)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown flight status %q", text)
+}
+
 // Remarks represents the remarks/status message for a flight
 type Remarks string
 
@@ -40,20 +83,99 @@ const (
 	RemarksTaxiingLeftGate Remarks = "Taxiing / Left Gate"
 	RemarksTaxiingDelayed  Remarks = "Taxiing / Delayed"
 	RemarksCancelled       Remarks = "Cancelled"
+	RemarksDeparted        Remarks = "Departed"
+	RemarksArrived         Remarks = "Arrived"
 )
 
-// Flight represents a flight departure
+// Direction distinguishes departure boards from arrival boards
+type Direction int
+
+const (
+	Departure Direction = iota
+	Arrival
+)
+
+// String returns the board title for the direction
+func (d Direction) String() string {
+	if d == Arrival {
+		return "Arrivals"
+	}
+	return "Departures"
+}
+
+// MarshalText encodes the direction as "departure" or "arrival"
+func (d Direction) MarshalText() ([]byte, error) {
+	if d == Arrival {
+		return []byte("arrival"), nil
+	}
+	return []byte("departure"), nil
+}
+
+// UnmarshalText decodes a direction written by MarshalText
+func (d *Direction) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "departure":
+		*d = Departure
+	case "arrival":
+		*d = Arrival
+	default:
+		return fmt.Errorf("unknown direction %q", text)
+	}
+	return nil
+}
+
+// Flight represents a flight departure or arrival
+// Times are kept as the source reported them and converted only for display.
+// It marshals to JSON with snake_case keys, leaving out unknown values
 type Flight struct {
-	Status             FlightStatus
-	AirlineCode        string // 2-letter IATA code
-	AirlineName        string // Full airline name/operator code
-	FlightNumber       string // Full flight number with airline code prefix
-	DestinationCode    string
-	DestinationCity    string
-	Gate               string
-	Remarks            Remarks
-	ScheduledDeparture time.Time
-	EstimatedDeparture *time.Time // Estimated departure time (for delayed flights)
+	ID                 string       `json:"id,omitempty"`         // Source's unique flight ID (AeroAPI fa_flight_id), empty if it has none
+	InboundID          string       `json:"inbound_id,omitempty"` // Source's ID of the flight bringing the aircraft in, for departures; empty if unknown
+	Direction          Direction    `json:"direction"`
+	Status             FlightStatus `json:"status"`
+	Ident              string       `json:"ident,omitempty"`        // ICAO flight ident/callsign (e.g., "UAL123")
+	AirlineCode        string       `json:"airline_code,omitempty"` // 2-letter IATA code
+	AirlineName        string       `json:"airline_name,omitempty"` // Full airline name/operator code
+	FlightNumber       string       `json:"flight_number"`          // Full flight number with airline code prefix
+	DestinationCode    string       `json:"destination_code,omitempty"`
+	DestinationCity    string       `json:"destination_city,omitempty"`
+	OriginCode         string       `json:"origin_code,omitempty"`
+	OriginCity         string       `json:"origin_city,omitempty"`
+	Gate               string       `json:"gate,omitempty"`          // Departure gate, or arrival gate for arrivals
+	BaggageClaim       string       `json:"baggage_claim,omitempty"` // Baggage claim belt, for arrivals only; often assigned once landed
+	Remarks            Remarks      `json:"remarks,omitempty"`
+	ScheduledDeparture time.Time    `json:"scheduled_departure,omitzero"`
+	EstimatedDeparture *time.Time   `json:"estimated_departure,omitempty"` // Estimated departure time (for delayed flights)
+	ActualOut          *time.Time   `json:"actual_out,omitempty"`          // Time the flight left the gate (for taxiing and departed flights)
+	ActualOff          *time.Time   `json:"actual_off,omitempty"`          // Wheels-up time, reported by the source or observed by a receiver
+	ScheduledArrival   time.Time    `json:"scheduled_arrival,omitzero"`    // Scheduled arrival time (for arrivals)
+	EstimatedArrival   *time.Time   `json:"estimated_arrival,omitempty"`   // Estimated arrival time (for arrivals)
+	Blocked            bool         `json:"blocked,omitempty"`             // Blocked from public tracking by its operator, so only its times and status are known
+}
+
+// ScheduledTime returns the scheduled time shown on the board: the departure
+// time for departures and the arrival time for arrivals
+func (f *Flight) ScheduledTime() time.Time {
+	if f.Direction == Arrival {
+		return f.ScheduledArrival
+	}
+	return f.ScheduledDeparture
+}
+
+// EstimatedTime returns the estimate matching ScheduledTime, or nil if unknown
+func (f *Flight) EstimatedTime() *time.Time {
+	if f.Direction == Arrival {
+		return f.EstimatedArrival
+	}
+	return f.EstimatedDeparture
+}
+
+// EffectiveDeparture returns the time the flight is expected to leave: the
+// estimated departure if known, otherwise the scheduled one
+func (f *Flight) EffectiveDeparture() time.Time {
+	if f.EstimatedDeparture != nil {
+		return *f.EstimatedDeparture
+	}
+	return f.ScheduledDeparture
 }
 
 // GetStatusColor returns the color code for the status light
@@ -69,15 +191,35 @@ func (f *Flight) GetStatusColor() string {
 		return "orange" // Orange for delayed taxiing
 	case StatusCancelled:
 		return "red"
+	case StatusDeparted, StatusArrived:
+		return "blue"
 	default:
 		return "white"
 	}
 }
 
 // GetDestination returns formatted destination string (code + city)
+// Returns an empty string if the destination is unknown
 func (f *Flight) GetDestination() string {
-	if f.DestinationCity != "" {
-		return f.DestinationCode + " " + f.DestinationCity
+	return formatAirport(f.DestinationCode, f.DestinationCity)
+}
+
+// GetOrigin returns formatted origin string (code + city)
+// Returns an empty string if the origin is unknown
+func (f *Flight) GetOrigin() string {
+	return formatAirport(f.OriginCode, f.OriginCity)
+}
+
+// HasRoute reports whether the airport at the other end of the flight is known:
+// the destination for departures and the origin for arrivals
+func (f *Flight) HasRoute() bool {
+	if f.Direction == Arrival {
+		return f.GetOrigin() != ""
 	}
-	return f.DestinationCode
+	return f.GetDestination() != ""
+}
+
+// formatAirport joins an airport code and city, omitting whichever is missing
+func formatAirport(code, city string) string {
+	return strings.TrimSpace(strings.TrimSpace(code) + " " + strings.TrimSpace(city))
 }
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// OnTimeMargin is how late a flight may be expected and still count as on
+// time, as airlines count punctuality
+const OnTimeMargin = 15 * time.Minute
+
+// AirlineStats is how one airline's flights are running
+type AirlineStats struct {
+	Key        string // IATA code, or the airline's name when the code is unknown
+	Name       string
+	Flights    int
+	OnTime     int // Flights not cancelled and less than OnTimeMargin late
+	Cancelled  int
+	Estimated  int           // Flights not cancelled with an estimate, which the delay is averaged over
+	TotalDelay time.Duration // Delay of the estimated flights added up, early ones counting as none
+}
+
+// OnTimePercent returns the share of the flights not cancelled that are on
+// time, or false if all of them are cancelled
+func (s AirlineStats) OnTimePercent() (float64, bool) {
+	flown := s.Flights - s.Cancelled
+	if flown <= 0 {
+		return 0, false
+	}
+	return 100 * float64(s.OnTime) / float64(flown), true
+}
+
+// AverageDelay returns the average delay of the flights with an estimate,
+// or false if none has one
+func (s AirlineStats) AverageDelay() (time.Duration, bool) {
+	if s.Estimated == 0 {
+		return 0, false
+	}
+	return s.TotalDelay / time.Duration(s.Estimated), true
+}
+
+// SummarizeAirlines returns how each airline's flights are running, busiest
+// first. A flight's delay is its estimate less its scheduled time; a flight
+// without an estimate is on time unless its status says it is delayed
+func SummarizeAirlines(flights []Flight) []AirlineStats {
+	index := make(map[string]int)
+	var stats []AirlineStats
+	for i := range flights {
+		f := &flights[i]
+		key := strings.TrimSpace(f.AirlineCode)
+		if key == "" {
+			key = strings.TrimSpace(f.AirlineName)
+		}
+		j, ok := index[key]
+		if !ok {
+			j = len(stats)
+			index[key] = j
+			stats = append(stats, AirlineStats{Key: key, Name: f.AirlineName})
+		}
+		s := &stats[j]
+		s.Flights++
+		if f.Status == StatusCancelled {
+			s.Cancelled++
+			continue
+		}
+		late := f.Status == StatusDelayed || f.Status == StatusTaxiingDelayed
+		if estimate, scheduled := f.EstimatedTime(), f.ScheduledTime(); estimate != nil && !scheduled.IsZero() {
+			delay := max(0, estimate.Sub(scheduled))
+			s.Estimated++
+			s.TotalDelay += delay
+			late = delay >= OnTimeMargin
+		}
+		if !late {
+			s.OnTime++
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Flights != stats[j].Flights {
+			return stats[i].Flights > stats[j].Flights
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// statsFlight returns a departure of airline at diffNow with the given
+// status, expected late minutes after it, or without an estimate if late is
+// negative
+func statsFlight(airline string, status FlightStatus, late int) Flight {
+	f := diffFlight(airline+" 1", "", 0)
+	f.AirlineCode = airline
+	f.AirlineName = airline + " Airways"
+	f.Status = status
+	if late >= 0 {
+		estimate := f.ScheduledDeparture.Add(time.Duration(late) * time.Minute)
+		f.EstimatedDeparture = &estimate
+	}
+	return f
+}
+
+func TestSummarizeAirlines(t *testing.T) {
+	flights := []Flight{
+		statsFlight("AA", StatusOnTime, 0),
+		statsFlight("AA", StatusDelayed, 40),
+		statsFlight("AA", StatusOnTime, 14), // Within the margin
+		statsFlight("AA", StatusCancelled, 90),
+		statsFlight("DL", StatusOnTime, -1),          // No estimate: on time by its status
+		statsFlight("DL", StatusTaxiingDelayed, -1),  // No estimate: late by its status
+		statsFlight("DL", StatusTaxiingLeftGate, 15), // At the margin: late
+		statsFlight("UA", StatusOnTime, 0),           // Early, below
+		statsFlight("B6", StatusDelayed, 20),         // As few flights as UA, before it by key
+		statsFlight("", StatusCancelled, -1),         // No code: keyed by its name
+	}
+	flights[9].AirlineName = "Charter"
+	early := flights[7].ScheduledDeparture.Add(-10 * time.Minute)
+	flights[7].EstimatedDeparture = &early // Early counts as no delay
+
+	want := []AirlineStats{
+		{Key: "AA", Name: "AA Airways", Flights: 4, OnTime: 2, Cancelled: 1, Estimated: 3, TotalDelay: 54 * time.Minute},
+		{Key: "DL", Name: "DL Airways", Flights: 3, OnTime: 1, Estimated: 1, TotalDelay: 15 * time.Minute},
+		{Key: "B6", Name: "B6 Airways", Flights: 1, Estimated: 1, TotalDelay: 20 * time.Minute},
+		{Key: "Charter", Name: "Charter", Flights: 1, Cancelled: 1},
+		{Key: "UA", Name: "UA Airways", Flights: 1, OnTime: 1, Estimated: 1},
+	}
+	got := SummarizeAirlines(flights)
+	if len(got) != len(want) {
+		t.Fatalf("%d airlines, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("airline %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := SummarizeAirlines(nil); len(got) != 0 {
+		t.Errorf("SummarizeAirlines(nil) = %+v, want none", got)
+	}
+}
+
+func TestOnTimePercent(t *testing.T) {
+	for _, tt := range []struct {
+		stats  AirlineStats
+		want   float64
+		wantOK bool
+	}{
+		{AirlineStats{Flights: 4, OnTime: 2, Cancelled: 1}, 100 * 2.0 / 3, true},
+		{AirlineStats{Flights: 2, OnTime: 2}, 100, true},
+		{AirlineStats{Flights: 2}, 0, true},
+		{AirlineStats{Flights: 2, Cancelled: 2}, 0, false}, // Every flight cancelled
+		{AirlineStats{}, 0, false},
+	} {
+		if got, ok := tt.stats.OnTimePercent(); got != tt.want || ok != tt.wantOK {
+			t.Errorf("%+v: OnTimePercent = %v, %v; want %v, %v", tt.stats, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestAverageDelay(t *testing.T) {
+	for _, tt := range []struct {
+		stats  AirlineStats
+		want   time.Duration
+		wantOK bool
+	}{
+		{AirlineStats{Flights: 3, Estimated: 3, TotalDelay: 54 * time.Minute}, 18 * time.Minute, true},
+		{AirlineStats{Flights: 1, Estimated: 1}, 0, true},
+		{AirlineStats{Flights: 3, Cancelled: 1}, 0, false}, // No estimates
+		{AirlineStats{}, 0, false},
+	} {
+		if got, ok := tt.stats.AverageDelay(); got != tt.want || ok != tt.wantOK {
+			t.Errorf("%+v: AverageDelay = %v, %v; want %v, %v", tt.stats, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
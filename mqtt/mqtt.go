@@ -0,0 +1,314 @@
+// Package mqtt publishes messages to an MQTT broker, for home automation
+// dashboards that follow the board. It speaks just enough of MQTT 3.1.1 to
+// publish at QoS 0: connect, publish, ping and disconnect. The connection is
+// kept in the background and re-established when lost, so a broker that is
+// down or slow never holds up the board
+package mqtt
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// queueSize is the number of messages waiting to be published before
+	// new ones are dropped
+	queueSize = 64
+	// keepAlive is the keep alive interval sent to the broker; a ping is
+	// sent when nothing else has been for half of it
+	keepAlive = 60 * time.Second
+	// dialTimeout bounds connecting to the broker and its CONNACK
+	dialTimeout = 10 * time.Second
+	// minRetry and maxRetry bound the backoff between connection attempts
+	minRetry = time.Second
+	maxRetry = 2 * time.Minute
+)
+
+// Packet types of the fixed header
+const (
+	packetConnect    = 0x10
+	packetConnack    = 0x20
+	packetPublish    = 0x30
+	packetPingreq    = 0xc0
+	packetDisconnect = 0xe0
+)
+
+// Config describes the broker to publish to
+type Config struct {
+	URL      string // mqtt://host:1883 or mqtts://host:8883; credentials may be given in the URL
+	Username string
+	Password string
+	ClientID string // Defaults to "fids-tui-<pid>"
+}
+
+// Message is one message to publish
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool // Kept by the broker for clients that subscribe later
+}
+
+// Publisher publishes messages from a background goroutine, connecting and
+// reconnecting to the broker as needed. A nil Publisher drops every message
+type Publisher struct {
+	cfg     Config
+	address string
+	tls     bool
+	queue   chan Message
+	done    chan struct{}
+	once    sync.Once
+	wg      sync.WaitGroup
+}
+
+// NewPublisher checks cfg and starts connecting to the broker in the
+// background. Messages are queued until the connection is up
+func NewPublisher(cfg Config) (*Publisher, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", cfg.URL, err)
+	}
+	p := &Publisher{cfg: cfg, queue: make(chan Message, queueSize), done: make(chan struct{})}
+	port := "1883"
+	switch u.Scheme {
+	case "mqtt", "tcp":
+	case "mqtts", "ssl", "tls":
+		p.tls = true
+		port = "8883"
+	default:
+		return nil, fmt.Errorf("invalid URL %q: scheme must be mqtt or mqtts", cfg.URL)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid URL %q: missing host", cfg.URL)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	p.address = net.JoinHostPort(u.Hostname(), port)
+	if u.User != nil && p.cfg.Username == "" {
+		p.cfg.Username = u.User.Username()
+		p.cfg.Password, _ = u.User.Password()
+	}
+	if p.cfg.ClientID == "" {
+		p.cfg.ClientID = fmt.Sprintf("fids-tui-%d", os.Getpid())
+	}
+
+	p.wg.Add(1)
+	go p.run()
+	return p, nil
+}
+
+// Publish queues msg without waiting for it to be sent. Messages are dropped
+// when the queue is full, such as while the broker is unreachable
+func (p *Publisher) Publish(msg Message) {
+	if p == nil {
+		return
+	}
+	select {
+	case p.queue <- msg:
+	default:
+		slog.Warn("MQTT queue full, dropping message", "topic", msg.Topic)
+	}
+}
+
+// Close disconnects from the broker, dropping messages not yet sent
+func (p *Publisher) Close() {
+	if p == nil {
+		return
+	}
+	p.once.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+// run keeps a connection to the broker until the publisher is closed,
+// waiting longer between attempts while the broker stays unreachable
+func (p *Publisher) run() {
+	defer p.wg.Done()
+	retry := minRetry
+	for {
+		conn, err := p.connect()
+		if err == nil {
+			slog.Info("MQTT connected", "broker", p.address)
+			retry = minRetry
+			err = p.serve(conn)
+			conn.Close()
+			if err == nil {
+				return
+			}
+		}
+		slog.Warn("MQTT connection failed, retrying", "broker", p.address, "error", err, "retry_in", retry)
+		select {
+		case <-p.done:
+			return
+		case <-time.After(retry):
+		}
+		retry = min(retry*2, maxRetry)
+	}
+}
+
+// connect opens a connection to the broker and waits for it to accept
+func (p *Publisher) connect() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	if p.tls {
+		host, _, _ := net.SplitHostPort(p.address)
+		conn, err = tls.DialWithDialer(dialer, "tcp", p.address, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", p.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(dialTimeout))
+	if _, err := conn.Write(connectPacket(p.cfg)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT: %w", err)
+	}
+	var ack [4]byte
+	if _, err := io.ReadFull(conn, ack[:]); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+	if ack[0] != packetConnack || ack[1] != 2 {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected reply %#x to CONNECT", ack[0])
+	}
+	if ack[3] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("broker refused the connection: %s", refusal(ack[3]))
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// serve publishes queued messages on conn and keeps it alive, until the
+// connection fails or the publisher is closed, when it returns nil
+func (p *Publisher) serve(conn net.Conn) error {
+	// The broker only sends PINGRESP to a publisher, so reading just
+	// notices the connection closing
+	lost := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, bufio.NewReader(conn))
+		if err == nil {
+			err = io.EOF
+		}
+		lost <- err
+	}()
+
+	ping := time.NewTicker(keepAlive / 2)
+	defer ping.Stop()
+	for {
+		var out []byte
+		select {
+		case <-p.done:
+			conn.SetWriteDeadline(time.Now().Add(time.Second))
+			conn.Write([]byte{packetDisconnect, 0})
+			return nil
+		case err := <-lost:
+			return fmt.Errorf("connection lost: %w", err)
+		case msg := <-p.queue:
+			out = publishPacket(msg)
+		case <-ping.C:
+			out = []byte{packetPingreq, 0}
+		}
+		conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+		if _, err := conn.Write(out); err != nil {
+			return err
+		}
+	}
+}
+
+// connectPacket encodes a CONNECT packet for a clean session
+func connectPacket(cfg Config) []byte {
+	flags := byte(0x02) // Clean session
+	var payload []byte
+	payload = appendString(payload, cfg.ClientID)
+	if cfg.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, cfg.Username)
+		if cfg.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, cfg.Password)
+		}
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags) // Protocol level 4 is MQTT 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(keepAlive/time.Second))
+	return packet(packetConnect, append(body, payload...))
+}
+
+// publishPacket encodes a QoS 0 PUBLISH packet
+func publishPacket(msg Message) []byte {
+	header := byte(packetPublish)
+	if msg.Retain {
+		header |= 0x01
+	}
+	return packet(header, append(appendString(nil, msg.Topic), msg.Payload...))
+}
+
+// packet prefixes body with the fixed header: the packet type and flags,
+// then the body's length in MQTT's variable length encoding
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	n := len(body)
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		out = append(out, digit)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+// appendString appends s as a length-prefixed MQTT string
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// refusal describes a CONNACK return code
+func refusal(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client ID rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad username or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("return code %d", code)
+	}
+}
+
+// ValidateTopic checks that topic can be published to: not empty and free
+// of the wildcards and NUL characters MQTT reserves
+func ValidateTopic(topic string) error {
+	if topic == "" {
+		return errors.New("topic is empty")
+	}
+	if strings.ContainsAny(topic, "+#\x00") {
+		return fmt.Errorf("topic %q contains a wildcard or NUL character", topic)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package mqtt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/mqtt/mqtttest"
+)
+
+// newBroker starts a mock broker, closed when the test ends
+func newBroker(t *testing.T) *mqtttest.Broker {
+	t.Helper()
+	broker, err := mqtttest.NewBroker()
+	if err != nil {
+		t.Fatalf("NewBroker: %v", err)
+	}
+	t.Cleanup(broker.Close)
+	return broker
+}
+
+// next returns the next message the broker receives, failing the test if
+// none arrives
+func next(t *testing.T, broker *mqtttest.Broker) mqtttest.Message {
+	t.Helper()
+	msg, err := broker.Next(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestPublish(t *testing.T) {
+	broker := newBroker(t)
+	url := strings.Replace(broker.URL, "mqtt://", "mqtt://board:secret@", 1)
+	publisher, err := NewPublisher(Config{URL: url, ClientID: "test-board"})
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	defer publisher.Close()
+
+	// Messages published before the connection is up are queued
+	publisher.Publish(Message{Topic: "fids/JFK/flights", Payload: []byte(`{"flights":[]}`), Retain: true})
+	long := bytes.Repeat([]byte("x"), 300) // Its length takes two bytes to encode
+	publisher.Publish(Message{Topic: "fids/JFK/events/gate", Payload: long})
+
+	if msg := next(t, broker); msg.Topic != "fids/JFK/flights" || string(msg.Payload) != `{"flights":[]}` || !msg.Retain {
+		t.Errorf("first message = %+v, want the retained flights", msg)
+	}
+	if msg := next(t, broker); msg.Topic != "fids/JFK/events/gate" || !bytes.Equal(msg.Payload, long) || msg.Retain {
+		t.Errorf("second message = %s %d bytes retained %v, want the gate event", msg.Topic, len(msg.Payload), msg.Retain)
+	}
+
+	connects := broker.Connects()
+	want := mqtttest.Connect{ClientID: "test-board", Username: "board", Password: "secret", KeepAlive: keepAlive, CleanSession: true}
+	if len(connects) != 1 || connects[0] != want {
+		t.Errorf("connects = %+v, want %+v", connects, want)
+	}
+}
+
+// TestReconnect drops the connection, checking the publisher connects again
+// and publishes on the new connection
+func TestReconnect(t *testing.T) {
+	broker := newBroker(t)
+	publisher, err := NewPublisher(Config{URL: broker.URL})
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	defer publisher.Close()
+
+	publisher.Publish(Message{Topic: "fids/JFK/flights", Payload: []byte("1")})
+	next(t, broker)
+	broker.Drop()
+
+	// The first retry is a second later
+	deadline := time.Now().Add(5 * time.Second)
+	for len(broker.Connects()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("publisher didn't connect again")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	publisher.Publish(Message{Topic: "fids/JFK/flights", Payload: []byte("2")})
+	if msg := next(t, broker); string(msg.Payload) != "2" {
+		t.Errorf("message after reconnecting = %q, want 2", msg.Payload)
+	}
+}
+
+// TestRefused checks that a broker refusing the connection is retried
+// without publishing, and published to once it accepts
+func TestRefused(t *testing.T) {
+	broker := newBroker(t)
+	broker.Refuse(4)
+	publisher, err := NewPublisher(Config{URL: broker.URL, Username: "board", Password: "wrong"})
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	defer publisher.Close()
+	publisher.Publish(Message{Topic: "fids/JFK/flights", Payload: []byte("1")})
+
+	if _, err := broker.Next(200 * time.Millisecond); err == nil {
+		t.Fatal("published to a broker that refused the connection")
+	}
+	broker.Refuse(0)
+	if msg := next(t, broker); string(msg.Payload) != "1" {
+		t.Errorf("message once accepted = %q, want 1", msg.Payload)
+	}
+	if n := len(broker.Connects()); n < 2 {
+		t.Errorf("connected %d times, want a retry", n)
+	}
+}
+
+// TestClose checks that closing disconnects from the broker and that a nil
+// publisher, as used when MQTT isn't configured, does nothing
+func TestClose(t *testing.T) {
+	broker := newBroker(t)
+	publisher, err := NewPublisher(Config{URL: broker.URL})
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	publisher.Publish(Message{Topic: "fids/JFK/flights"})
+	next(t, broker)
+	publisher.Close()
+	publisher.Close()
+
+	var none *Publisher
+	none.Publish(Message{Topic: "fids/JFK/flights"})
+	none.Close()
+	if _, err := broker.Next(100 * time.Millisecond); err == nil {
+		t.Error("published after closing")
+	}
+}
+
+func TestNewPublisherURL(t *testing.T) {
+	for _, url := range []string{"http://broker:1883", "mqtt://", "mqtt://broker:1883/%zz"} {
+		if _, err := NewPublisher(Config{URL: url}); err == nil {
+			t.Errorf("NewPublisher(%q) accepted an invalid URL", url)
+		}
+	}
+	publisher, err := NewPublisher(Config{URL: "mqtts://broker.example"})
+	if err != nil {
+		t.Fatalf("NewPublisher: %v", err)
+	}
+	defer publisher.Close()
+	if publisher.address != "broker.example:8883" || !publisher.tls {
+		t.Errorf("mqtts URL gives %s over TLS %v, want port 8883 over TLS", publisher.address, publisher.tls)
+	}
+}
+
+func TestValidateTopic(t *testing.T) {
+	for topic, valid := range map[string]bool{
+		"fids":        true,
+		"home/fids":   true,
+		"":            false,
+		"fids/+":      false,
+		"fids/#":      false,
+		"fids\x00bad": false,
+	} {
+		if err := ValidateTopic(topic); (err == nil) != valid {
+			t.Errorf("ValidateTopic(%q) = %v, want valid %v", topic, err, valid)
+		}
+	}
+}
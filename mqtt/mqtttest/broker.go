@@ -0,0 +1,245 @@
+// Package mqtttest provides a broker for testing MQTT publishers. It accepts
+// the packets a QoS 0 publisher sends and records what was published
+package mqtttest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Connect is a CONNECT packet received by the broker
+type Connect struct {
+	ClientID     string
+	Username     string
+	Password     string
+	KeepAlive    time.Duration
+	CleanSession bool
+}
+
+// Message is a PUBLISH packet received by the broker
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// Broker is an MQTT broker listening on a local port. It answers CONNECT
+// with its return code and PINGREQ with PINGRESP, and records each CONNECT
+// and PUBLISH
+type Broker struct {
+	URL string // mqtt://127.0.0.1:<port>
+
+	listener net.Listener
+	messages chan Message
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	refuse   byte
+	connects []Connect
+	conns    map[net.Conn]bool
+}
+
+// NewBroker starts a broker on a free local port
+func NewBroker() (*Broker, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &Broker{
+		URL:      "mqtt://" + listener.Addr().String(),
+		listener: listener,
+		messages: make(chan Message, 256),
+		conns:    make(map[net.Conn]bool),
+	}
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// Close stops the broker and closes every connection to it
+func (b *Broker) Close() {
+	b.listener.Close()
+	b.Drop()
+	b.wg.Wait()
+}
+
+// Refuse makes the broker answer later connections with CONNACK return
+// code code, such as 4 for a bad user name or password; 0 accepts them
+func (b *Broker) Refuse(code byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refuse = code
+}
+
+// Drop closes the connections open to the broker, as a broker restarting
+// would, but keeps listening for new ones
+func (b *Broker) Drop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.conns {
+		conn.Close()
+	}
+}
+
+// Connects returns the CONNECT packets received, accepted or not
+func (b *Broker) Connects() []Connect {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Connect(nil), b.connects...)
+}
+
+// Next returns the next message published, waiting up to timeout for it
+func (b *Broker) Next(timeout time.Duration) (Message, error) {
+	select {
+	case msg := <-b.messages:
+		return msg, nil
+	case <-time.After(timeout):
+		return Message{}, fmt.Errorf("nothing published in %s", timeout)
+	}
+}
+
+// accept serves connections until the listener is closed
+func (b *Broker) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns[conn] = true
+		b.mu.Unlock()
+		b.wg.Add(1)
+		go b.serve(conn)
+	}
+}
+
+// serve reads packets from conn until it closes or sends DISCONNECT
+func (b *Broker) serve(conn net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mu.Lock()
+		delete(b.conns, conn)
+		b.mu.Unlock()
+		conn.Close()
+	}()
+	r := bufio.NewReader(conn)
+	connected := false
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		switch kind := header & 0xf0; {
+		case kind == 0x10 && !connected:
+			c, err := parseConnect(body)
+			if err != nil {
+				return
+			}
+			b.mu.Lock()
+			b.connects = append(b.connects, c)
+			code := b.refuse
+			b.mu.Unlock()
+			if _, err := conn.Write([]byte{0x20, 2, 0, code}); err != nil || code != 0 {
+				return
+			}
+			connected = true
+		case kind == 0x30 && connected:
+			msg, err := parsePublish(header, body)
+			if err != nil {
+				return
+			}
+			b.messages <- msg
+		case kind == 0xc0 && connected:
+			conn.Write([]byte{0xd0, 0})
+		default:
+			// DISCONNECT, or a packet out of turn
+			return
+		}
+	}
+}
+
+// readPacket reads a packet's fixed header and its body
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		if i == 4 {
+			return 0, nil, errors.New("remaining length longer than 4 bytes")
+		}
+		length += int(digit&0x7f) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// readString reads a length-prefixed string from the front of b
+func readString(b []byte) (string, []byte, error) {
+	if len(b) < 2 {
+		return "", nil, errors.New("short string")
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return "", nil, errors.New("short string")
+	}
+	return string(b[2 : 2+n]), b[2+n:], nil
+}
+
+// parseConnect decodes the body of a CONNECT packet of MQTT 3.1.1
+func parseConnect(body []byte) (Connect, error) {
+	name, rest, err := readString(body)
+	if err != nil || name != "MQTT" || len(rest) < 4 || rest[0] != 4 {
+		return Connect{}, errors.New("not an MQTT 3.1.1 CONNECT")
+	}
+	flags := rest[1]
+	c := Connect{
+		KeepAlive:    time.Duration(binary.BigEndian.Uint16(rest[2:4])) * time.Second,
+		CleanSession: flags&0x02 != 0,
+	}
+	if c.ClientID, rest, err = readString(rest[4:]); err != nil {
+		return Connect{}, err
+	}
+	if flags&0x80 != 0 {
+		if c.Username, rest, err = readString(rest); err != nil {
+			return Connect{}, err
+		}
+	}
+	if flags&0x40 != 0 {
+		if c.Password, _, err = readString(rest); err != nil {
+			return Connect{}, err
+		}
+	}
+	return c, nil
+}
+
+// parsePublish decodes a QoS 0 PUBLISH packet
+func parsePublish(header byte, body []byte) (Message, error) {
+	if header&0x06 != 0 {
+		return Message{}, errors.New("PUBLISH above QoS 0")
+	}
+	topic, payload, err := readString(body)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Topic: topic, Payload: payload, Retain: header&0x01 != 0}, nil
+}
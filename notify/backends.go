@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"fids-tui/payload"
+)
+
+// pushoverURL is the Pushover message API endpoint
+const pushoverURL = "https://api.pushover.net/1/messages.json"
+
+// Ntfy publishes alerts to an ntfy topic, on ntfy.sh or a self-hosted server
+type Ntfy struct {
+	URL    string // Server URL, e.g. https://ntfy.sh
+	Topic  string
+	Client *http.Client
+}
+
+// Name returns the display name of the notifier
+func (n *Ntfy) Name() string {
+	return "ntfy"
+}
+
+// Notify publishes msg to the topic
+func (n *Ntfy) Notify(msg Message) error {
+	req, err := http.NewRequest("POST", strings.TrimRight(n.URL, "/")+"/"+url.PathEscape(n.Topic), strings.NewReader(msg.Body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Title", msg.Title)
+	return send(n.Client, req)
+}
+
+// Pushover sends alerts through the Pushover service
+type Pushover struct {
+	Token  string // Application token
+	User   string // User or group key
+	Client *http.Client
+}
+
+// Name returns the display name of the notifier
+func (p *Pushover) Name() string {
+	return "Pushover"
+}
+
+// Notify sends msg to the user
+func (p *Pushover) Notify(msg Message) error {
+	form := url.Values{
+		"token":   {p.Token},
+		"user":    {p.User},
+		"title":   {msg.Title},
+		"message": {msg.Body},
+	}
+	req, err := http.NewRequest("POST", pushoverURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return send(p.Client, req)
+}
+
+// Webhook posts alerts as JSON payload.Alert messages to a URL
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name returns the display name of the notifier
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+// Notify posts msg to the webhook URL
+func (w *Webhook) Notify(msg Message) error {
+	body, err := json.Marshal(payload.Alert{
+		SchemaVersion: payload.SchemaVersion,
+		Title:         msg.Title,
+		Message:       msg.Body,
+		Source:        msg.Source,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return send(w.Client, req)
+}
+
+// Bell rings the terminal bell for each alert
+type Bell struct {
+	Out io.Writer // The terminal, usually os.Stderr
+}
+
+// Name returns the display name of the notifier
+func (b *Bell) Name() string {
+	return "bell"
+}
+
+// Notify rings the bell
+func (b *Bell) Notify(Message) error {
+	_, err := io.WriteString(b.Out, "\a")
+	return err
+}
+
+// send performs req and treats any non-2xx status as an error
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateFixtures = flag.Bool("update", false, "rewrite the message fixtures in payload/testdata")
+
+// TestWebhookPayload checks the alert a webhook posts against its fixture in
+// payload/testdata, where the payload tests validate it against the schema
+func TestWebhookPayload(t *testing.T) {
+	var body []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, Client: server.Client()}
+	if err := webhook.Notify(Message{Title: "AA 101 gate change", Body: "AA 101 to LAX: gate B2→C7", Source: "FlightAware"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	var out any
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("alert isn't JSON: %v", err)
+	}
+	indented, _ := json.MarshalIndent(out, "", "  ")
+	indented = append(indented, '\n')
+	path := filepath.Join("..", "payload", "testdata", "webhook_alert.json")
+	if *updateFixtures {
+		if err := os.WriteFile(path, indented, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if string(indented) != string(want) {
+		t.Errorf("alert differs from %s:\n%s\nwant\n%s", path, indented, want)
+	}
+}
+
+func TestWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer server.Close()
+	webhook := &Webhook{URL: server.URL, Client: server.Client()}
+	if err := webhook.Notify(Message{Title: "test"}); err == nil || err.Error() != "status 404: no such hook" {
+		t.Errorf("Notify = %v, want the status and body", err)
+	}
+}
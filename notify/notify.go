@@ -0,0 +1,116 @@
+// Package notify delivers flight alerts to phones, webhooks and the terminal
+// bell. Delivery happens in the background so a slow or unreachable service
+// never holds up the board; failures are only logged
+package notify
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Message is a single alert
+type Message struct {
+	Title  string
+	Body   string
+	Source string // Data source the alert was raised from, if known
+}
+
+// Notifier delivers alerts to one destination
+type Notifier interface {
+	Name() string
+	Notify(msg Message) error
+}
+
+// queueSize is the number of alerts waiting for each notifier before new
+// ones are dropped
+const queueSize = 32
+
+// Dispatcher sends alerts to several notifiers, each with its own queue and
+// rate limit
+type Dispatcher struct {
+	backends []*backend
+}
+
+// backend queues alerts for one notifier
+type backend struct {
+	notifier Notifier
+	queue    chan Message
+	limiter  *rateLimiter
+}
+
+// NewDispatcher starts delivering to notifiers, allowing each at most
+// maxPerHour alerts per hour; zero or less means no limit
+func NewDispatcher(maxPerHour int, notifiers ...Notifier) *Dispatcher {
+	d := &Dispatcher{}
+	for _, n := range notifiers {
+		b := &backend{
+			notifier: n,
+			queue:    make(chan Message, queueSize),
+			limiter:  newRateLimiter(maxPerHour, time.Hour),
+		}
+		d.backends = append(d.backends, b)
+		go b.run()
+	}
+	return d
+}
+
+// Send queues msg for every notifier without waiting for delivery
+// A nil Dispatcher drops the message
+func (d *Dispatcher) Send(msg Message) {
+	if d == nil {
+		return
+	}
+	for _, b := range d.backends {
+		select {
+		case b.queue <- msg:
+		default:
+			slog.Warn("notification queue full, dropping alert", "notifier", b.notifier.Name(), "title", msg.Title)
+		}
+	}
+}
+
+// Enabled reports whether any notifier is configured
+func (d *Dispatcher) Enabled() bool {
+	return d != nil && len(d.backends) > 0
+}
+
+// run delivers queued alerts until the process exits
+func (b *backend) run() {
+	for msg := range b.queue {
+		if !b.limiter.allow(time.Now()) {
+			slog.Info("notification rate limit reached, dropping alert", "notifier", b.notifier.Name(), "title", msg.Title)
+			continue
+		}
+		if err := b.notifier.Notify(msg); err != nil {
+			slog.Warn("notification failed", "notifier", b.notifier.Name(), "error", err)
+		}
+	}
+}
+
+// rateLimiter allows at most max events within a sliding window
+type rateLimiter struct {
+	max    int
+	window time.Duration
+	sent   []time.Time // Times of allowed events within the window, oldest first
+}
+
+// newRateLimiter creates a limiter; a max of zero or less allows everything
+func newRateLimiter(max int, window time.Duration) *rateLimiter {
+	return &rateLimiter{max: max, window: window}
+}
+
+// allow reports whether an event at now fits in the limit, and records it if so
+func (r *rateLimiter) allow(now time.Time) bool {
+	if r.max <= 0 {
+		return true
+	}
+	cutoff := now.Add(-r.window)
+	for len(r.sent) > 0 && !r.sent[0].After(cutoff) {
+		r.sent = r.sent[1:]
+	}
+	if len(r.sent) >= r.max {
+		return false
+	}
+	r.sent = append(r.sent, now)
+	return true
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// soundInterval is the shortest time between two flap sounds, so an update
+// that flips many rows makes one sound rather than one per row
+const soundInterval = time.Second
+
+// Sound plays a short flap sound when rows flip on the board, either by
+// ringing the terminal bell or by running a command such as "aplay flap.wav".
+// A nil Sound is silent
+type Sound struct {
+	out     io.Writer // The terminal, when ringing the bell
+	command []string  // Program and arguments to run, nil to ring the bell
+	mu      sync.Mutex
+	limiter *rateLimiter
+	playing atomic.Bool // A sound command is still running
+}
+
+// NewSound creates a sound that rings the bell on out, or runs command if it
+// is set. The command is split on spaces and run directly, never through a
+// shell, so quotes, pipes and variables in it are not interpreted
+func NewSound(command string, out io.Writer) (*Sound, error) {
+	s := &Sound{out: out, limiter: newRateLimiter(1, soundInterval)}
+	if strings.TrimSpace(command) == "" {
+		return s, nil
+	}
+	if i := strings.IndexFunc(command, unicode.IsControl); i >= 0 {
+		return nil, fmt.Errorf("command contains a control character at position %d", i)
+	}
+	s.command = strings.Fields(command)
+	path, err := exec.LookPath(s.command[0])
+	if err != nil {
+		return nil, fmt.Errorf("command not found: %w", err)
+	}
+	s.command[0] = path
+	return s, nil
+}
+
+// Play plays the sound without waiting for it, unless one was played less
+// than a second before now or the last command is still running
+func (s *Sound) Play(now time.Time) {
+	if s == nil || s.playing.Load() {
+		return
+	}
+	s.mu.Lock()
+	allowed := s.limiter.allow(now)
+	s.mu.Unlock()
+	if !allowed {
+		return
+	}
+
+	if s.command == nil {
+		io.WriteString(s.out, "\a")
+		return
+	}
+	cmd := exec.Command(s.command[0], s.command[1:]...)
+	if err := cmd.Start(); err != nil {
+		slog.Warn("sound command failed", "error", err)
+		return
+	}
+	s.playing.Store(true)
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			slog.Warn("sound command failed", "error", err)
+		}
+		s.playing.Store(false)
+	}()
+}
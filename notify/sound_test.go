@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSoundRateLimit plays the bell at times around the sound interval,
+// checking only plays a second or more after the last one ring
+func TestSoundRateLimit(t *testing.T) {
+	var out bytes.Buffer
+	s, err := NewSound("", &out)
+	if err != nil {
+		t.Fatalf("NewSound: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		at    time.Duration
+		rings bool
+	}{
+		{0, true},
+		{time.Millisecond, false}, // The rest of the rows of one update
+		{999 * time.Millisecond, false},
+		{time.Second, true},
+		{1500 * time.Millisecond, false},
+		{5 * time.Second, true},
+	} {
+		before := out.Len()
+		s.Play(start.Add(tt.at))
+		if rang := out.Len() > before; rang != tt.rings {
+			t.Errorf("play at +%s rang %v, want %v", tt.at, rang, tt.rings)
+		}
+	}
+	if got := out.String(); got != strings.Repeat("\a", 3) {
+		t.Errorf("bell written %q, want three rings", got)
+	}
+
+	var silent *Sound
+	silent.Play(start) // A nil Sound is silent, and doesn't panic
+}
+
+// TestSoundCommandFailure checks that a sound command that fails doesn't stop
+// the next play from running the command
+func TestSoundCommandFailure(t *testing.T) {
+	dir := t.TempDir()
+	played := filepath.Join(dir, "sounds", "played")
+	// Fails until its parent directory exists
+	s, err := NewSound("mkdir "+played, nil)
+	if err != nil {
+		t.Skipf("no mkdir to run: %v", err)
+	}
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	s.Play(start)
+	waitPlayed(t, s)
+	if _, err := os.Stat(played); err == nil {
+		t.Fatal("sound command succeeded, want it to fail first")
+	}
+
+	if err := os.Mkdir(filepath.Dir(played), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s.Play(start.Add(500 * time.Millisecond))
+	waitPlayed(t, s)
+	if _, err := os.Stat(played); err == nil {
+		t.Error("sound command run again within a second")
+	}
+	s.Play(start.Add(time.Second))
+	waitPlayed(t, s)
+	if _, err := os.Stat(played); err != nil {
+		t.Errorf("sound command not run after a failed one: %v", err)
+	}
+}
+
+// waitPlayed waits for s's sound command to finish
+func waitPlayed(t *testing.T, s *Sound) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); s.playing.Load(); {
+		if time.Now().After(deadline) {
+			t.Fatal("sound command still running after 5s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,151 @@
+// Package payload defines the JSON messages the board sends to other
+// programs: the flights and change events published over MQTT and the
+// alerts posted to webhooks. They are kept apart from models.Flight so a
+// change to the board's own types can't silently change what integrations
+// receive; any change to these types must bump SchemaVersion.
+//
+// Every message carries "schema_version", and Schema returns a JSON Schema
+// describing the messages, as printed by fids-tui -print-schema.
+package payload
+
+import (
+	"time"
+
+	"fids-tui/models"
+)
+
+// SchemaVersion is the version of the messages, sent as schema_version.
+// It changes whenever a field is removed or its meaning changes
+const SchemaVersion = 1
+
+// Board is the retained message describing a board's flights
+type Board struct {
+	SchemaVersion int       `json:"schema_version"`
+	Airport       string    `json:"airport"`
+	Direction     string    `json:"direction" enum:"departure,arrival"`
+	Destination   string    `json:"destination,omitempty"` // Destination of a route board
+	Source        string    `json:"source"`
+	FetchedAt     time.Time `json:"fetched_at"`
+	Simulated     bool      `json:"simulated,omitempty"`
+	Flights       []Flight  `json:"flights"`
+}
+
+// Event is the message for one change to a flight
+type Event struct {
+	SchemaVersion int       `json:"schema_version"`
+	Time          time.Time `json:"time"`
+	Airport       string    `json:"airport"`
+	Flight        string    `json:"flight"`
+	FlightID      string    `json:"flight_id,omitempty"` // Source's unique flight ID, empty if it has none
+	Scheduled     time.Time `json:"scheduled,omitzero"`  // Scheduled time of the flight
+	Day           int       `json:"day,omitempty"`       // Days after the first flight of the same number on the board, when it is flown on consecutive days
+	Place         string    `json:"place,omitempty"`     // Destination, or origin for arrivals
+	Type          string    `json:"type" enum:"gate,status,estimate,baggage,retimed"`
+	Old           string    `json:"old,omitempty"`
+	New           string    `json:"new,omitempty"`
+	Description   string    `json:"description"` // e.g. "gate B12→B20"
+	Source        string    `json:"source,omitempty"`
+}
+
+// Alert is the body of an alert posted to a webhook
+type Alert struct {
+	SchemaVersion int    `json:"schema_version"`
+	Title         string `json:"title"`
+	Message       string `json:"message"`
+	Source        string `json:"source,omitempty"` // Data source the alert was raised from
+}
+
+// Flight is one flight of a Board message. Times are as the source
+// reported them, in RFC 3339
+type Flight struct {
+	ID                 string     `json:"id,omitempty"` // Source's unique flight ID, empty if it has none
+	Direction          string     `json:"direction" enum:"departure,arrival"`
+	Status             string     `json:"status" enum:"on_time,delayed,taxiing,taxiing_delayed,cancelled,unknown,departed,arrived"`
+	Ident              string     `json:"ident,omitempty"`        // ICAO flight ident/callsign (e.g., "UAL123")
+	AirlineCode        string     `json:"airline_code,omitempty"` // 2-letter IATA code
+	AirlineName        string     `json:"airline_name,omitempty"`
+	FlightNumber       string     `json:"flight_number"` // Full flight number with airline code prefix
+	DestinationCode    string     `json:"destination_code,omitempty"`
+	DestinationCity    string     `json:"destination_city,omitempty"`
+	OriginCode         string     `json:"origin_code,omitempty"`
+	OriginCity         string     `json:"origin_city,omitempty"`
+	Gate               string     `json:"gate,omitempty"`          // Departure gate, or arrival gate for arrivals
+	BaggageClaim       string     `json:"baggage_claim,omitempty"` // Baggage claim belt, for arrivals only
+	Remarks            string     `json:"remarks,omitempty"`
+	ScheduledDeparture *time.Time `json:"scheduled_departure,omitempty"`
+	EstimatedDeparture *time.Time `json:"estimated_departure,omitempty"`
+	ActualOut          *time.Time `json:"actual_out,omitempty"` // Time the flight left the gate
+	ActualOff          *time.Time `json:"actual_off,omitempty"` // Wheels-up time
+	ScheduledArrival   *time.Time `json:"scheduled_arrival,omitempty"`
+	EstimatedArrival   *time.Time `json:"estimated_arrival,omitempty"`
+	Blocked            bool       `json:"blocked,omitempty"` // Blocked from public tracking, so only its times and status are sent
+}
+
+// statuses are the names of the flight statuses in messages. Statuses
+// added to the model are sent as "unknown" until they are added here
+var statuses = map[models.FlightStatus]string{
+	models.StatusOnTime:          "on_time",
+	models.StatusDelayed:         "delayed",
+	models.StatusTaxiingLeftGate: "taxiing",
+	models.StatusTaxiingDelayed:  "taxiing_delayed",
+	models.StatusCancelled:       "cancelled",
+	models.StatusUnknown:         "unknown",
+	models.StatusDeparted:        "departed",
+	models.StatusArrived:         "arrived",
+}
+
+// DirectionName returns the name of a direction in messages
+func DirectionName(direction models.Direction) string {
+	if direction == models.Arrival {
+		return "arrival"
+	}
+	return "departure"
+}
+
+// NewFlights converts the board's flights to their message form
+func NewFlights(flights []models.Flight) []Flight {
+	out := make([]Flight, len(flights))
+	for i := range flights {
+		out[i] = NewFlight(&flights[i])
+	}
+	return out
+}
+
+// NewFlight converts one of the board's flights to its message form
+func NewFlight(f *models.Flight) Flight {
+	status, ok := statuses[f.Status]
+	if !ok {
+		status = "unknown"
+	}
+	return Flight{
+		ID:                 f.ID,
+		Direction:          DirectionName(f.Direction),
+		Status:             status,
+		Ident:              f.Ident,
+		AirlineCode:        f.AirlineCode,
+		AirlineName:        f.AirlineName,
+		FlightNumber:       f.FlightNumber,
+		DestinationCode:    f.DestinationCode,
+		DestinationCity:    f.DestinationCity,
+		OriginCode:         f.OriginCode,
+		OriginCity:         f.OriginCity,
+		Gate:               f.Gate,
+		BaggageClaim:       f.BaggageClaim,
+		Remarks:            string(f.Remarks),
+		ScheduledDeparture: timeOrNil(f.ScheduledDeparture),
+		EstimatedDeparture: f.EstimatedDeparture,
+		ActualOut:          f.ActualOut,
+		ActualOff:          f.ActualOff,
+		ScheduledArrival:   timeOrNil(f.ScheduledArrival),
+		EstimatedArrival:   f.EstimatedArrival,
+		Blocked:            f.Blocked,
+	}
+}
+
+// timeOrNil returns a pointer to t, or nil if t is zero so it is left out
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,130 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// messages are the top-level messages described by Schema, by definition name
+var messages = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"board", reflect.TypeFor[Board]()},
+	{"event", reflect.TypeFor[Event]()},
+	{"alert", reflect.TypeFor[Alert]()},
+}
+
+// timeType is marshaled as an RFC 3339 string
+var timeType = reflect.TypeFor[time.Time]()
+
+// Schema returns a JSON Schema (draft 2020-12) of the messages, generated
+// from their types so it can't drift from what is sent. A message is valid
+// if it matches one of the definitions
+func Schema() ([]byte, error) {
+	defs := make(map[string]any)
+	refs := make([]any, 0, len(messages))
+	for _, message := range messages {
+		if err := define(defs, message.name, message.typ); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref(message.name))
+	}
+	schema := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "fids-tui messages",
+		"description": fmt.Sprintf("Messages published over MQTT and posted to webhooks, schema_version %d", SchemaVersion),
+		"oneOf":       refs,
+		"$defs":       defs,
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// define adds the schema of struct type t to defs under name, along with
+// the structs it contains
+func define(defs map[string]any, name string, t reflect.Type) error {
+	if _, ok := defs[name]; ok {
+		return nil
+	}
+	properties := make(map[string]any)
+	var required []string
+	defs[name] = map[string]any{} // Placeholder against recursive types
+	for i := range t.NumField() {
+		field := t.Field(i)
+		key, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		property, err := propertySchema(defs, field)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		properties[key] = property
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			required = append(required, key)
+		}
+	}
+	defs[name] = map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	return nil
+}
+
+// propertySchema returns the schema of one struct field's value
+func propertySchema(defs map[string]any, field reflect.StructField) (map[string]any, error) {
+	if field.Name == "SchemaVersion" {
+		return map[string]any{"type": "integer", "const": SchemaVersion}, nil
+	}
+	schema, err := typeSchema(defs, field.Type)
+	if err != nil {
+		return nil, err
+	}
+	if enum := field.Tag.Get("enum"); enum != "" {
+		schema["enum"] = strings.Split(enum, ",")
+	}
+	return schema, nil
+}
+
+// typeSchema returns the schema of values of type t
+func typeSchema(defs map[string]any, t reflect.Type) (map[string]any, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Slice:
+		items, err := typeSchema(defs, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Struct:
+		name := strings.ToLower(t.Name())
+		if err := define(defs, name, t); err != nil {
+			return nil, err
+		}
+		return ref(name), nil
+	default:
+		return nil, fmt.Errorf("no schema for %s", t)
+	}
+}
+
+// ref returns a reference to the definition called name
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
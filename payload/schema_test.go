@@ -0,0 +1,179 @@
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validator checks documents against the subset of JSON Schema that Schema
+// emits. It is stricter than the schema in one way: properties the schema
+// doesn't describe are errors, so a field added to a producer but not to
+// these types is caught
+type validator struct {
+	defs map[string]any
+}
+
+func newValidator(t *testing.T) (*validator, map[string]any) {
+	t.Helper()
+	raw, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("Schema isn't JSON: %v", err)
+	}
+	return &validator{defs: schema["$defs"].(map[string]any)}, schema
+}
+
+// validate checks value against schema, naming the failing place by path
+func (v *validator) validate(schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		return v.validate(v.defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any), value, path)
+	}
+	if options, ok := schema["oneOf"].([]any); ok {
+		var matched []int
+		for i, option := range options {
+			if v.validate(option.(map[string]any), value, path) == nil {
+				matched = append(matched, i)
+			}
+		}
+		if len(matched) != 1 {
+			return fmt.Errorf("%s matches %d of the messages, want 1", path, len(matched))
+		}
+		return nil
+	}
+	if want, ok := schema["const"]; ok && value != want {
+		return fmt.Errorf("%s is %v, want %v", path, value, want)
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return fmt.Errorf("%s is %v, want one of %v", path, value, enum)
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s isn't an object", path)
+		}
+		properties := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, key := range required {
+			if _, ok := object[key.(string)]; !ok {
+				return fmt.Errorf("%s is missing %s", path, key)
+			}
+		}
+		for key, item := range object {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s has %s, which the schema doesn't describe", path, key)
+			}
+			if err := v.validate(property, item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s isn't an array", path)
+		}
+		for i, item := range items {
+			if err := v.validate(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s isn't a string", path)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return fmt.Errorf("%s isn't a date-time: %v", path, err)
+			}
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s isn't an integer", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s isn't a number", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s isn't a boolean", path)
+		}
+	}
+	return nil
+}
+
+// TestFixturesMatchSchema validates every producer's fixture in testdata,
+// written by the producers' own tests, against the schema
+func TestFixturesMatchSchema(t *testing.T) {
+	v, schema := newValidator(t)
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One fixture for each producer: MQTT flights and events, -export and webhooks
+	if len(fixtures) != 4 {
+		t.Errorf("%d fixtures in testdata, want 4: %v", len(fixtures), fixtures)
+	}
+	for _, path := range fixtures {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var message any
+		if err := json.Unmarshal(raw, &message); err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+		if err := v.validate(schema, message, filepath.Base(path)); err != nil {
+			t.Errorf("%s doesn't match the schema: %v", path, err)
+		}
+	}
+}
+
+// TestSchemaRejects checks the validator catches the ways a message can
+// break its contract
+func TestSchemaRejects(t *testing.T) {
+	v, schema := newValidator(t)
+	raw, err := os.ReadFile(filepath.Join("testdata", "mqtt_board.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		change func(board map[string]any)
+	}{
+		{"missing required field", func(b map[string]any) { delete(b, "airport") }},
+		{"old schema version", func(b map[string]any) { b["schema_version"] = 0 }},
+		{"unknown direction", func(b map[string]any) { b["direction"] = "both" }},
+		{"unknown status", func(b map[string]any) { flight(b)["status"] = "boarding" }},
+		{"time not RFC 3339", func(b map[string]any) { flight(b)["scheduled_departure"] = "13:00" }},
+		{"wrong type", func(b map[string]any) { b["simulated"] = "yes" }},
+		{"undescribed field", func(b map[string]any) { flight(b)["terminal"] = "4" }},
+	}
+	for _, tt := range tests {
+		var board map[string]any
+		if err := json.Unmarshal(raw, &board); err != nil {
+			t.Fatal(err)
+		}
+		tt.change(board)
+		if err := v.validate(schema, board, "board"); err == nil {
+			t.Errorf("%s: message accepted", tt.name)
+		}
+	}
+}
+
+// flight returns the first flight of a board message
+func flight(board map[string]any) map[string]any {
+	return board["flights"].([]any)[0].(map[string]any)
+}
@@ -0,0 +1,272 @@
+// Package setup is the first-run screen of fids-tui: it asks for the
+// FlightAware API key, a default airport and a few display preferences,
+// checks the key and writes them to the config file
+package setup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/config"
+	"fids-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// checkTimeout bounds the request checking the API key
+const checkTimeout = 20 * time.Second
+
+// Validator checks that an API key works, for example by fetching a
+// departure of airport with it
+type Validator func(ctx context.Context, apiKey, airport string) error
+
+// choice is a setting picked from a few options with the arrow keys
+type choice struct {
+	label    string
+	key      string // Setting the option is written to
+	options  []string
+	selected int
+}
+
+// cycle moves the selection by step, wrapping around
+func (c *choice) cycle(step int) {
+	c.selected = (c.selected + step + len(c.options)) % len(c.options)
+}
+
+// render draws the label and the options, marking the selected one
+func (c *choice) render(focused bool, styles *ui.SplitFlapStyles) string {
+	options := make([]string, len(c.options))
+	for i, option := range c.options {
+		if i == c.selected {
+			options[i] = "[" + option + "]"
+		} else {
+			options[i] = " " + option + " "
+		}
+	}
+	line := fmt.Sprintf("  %-16s %s", c.label, strings.Join(options, " "))
+	if focused {
+		return styles.Selected.Render("> " + line[2:])
+	}
+	return styles.Text.Render(line)
+}
+
+// checkedMsg reports the result of checking the API key
+type checkedMsg struct {
+	err error
+}
+
+// Model is the setup screen, a bubbletea model that quits once the settings
+// are saved or the user cancels
+type Model struct {
+	path     string // Config file written
+	apiKey   *ui.TextField
+	airport  *ui.TextField
+	choices  []*choice
+	focus    int // Index of the focused item: the two fields, then the choices
+	validate Validator
+	checking bool   // The API key is being checked
+	err      string // Why the last attempt to save failed
+	saved    bool
+	styles   *ui.SplitFlapStyles
+	width    int
+	height   int
+}
+
+// New creates the setup screen writing to the config file at path, filled
+// in with the settings of cfg
+func New(path string, cfg *config.Config, validate Validator) Model {
+	upper := func(r rune) (rune, bool) {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		return r, r >= 'A' && r <= 'Z'
+	}
+	keyChar := func(r rune) (rune, bool) {
+		return r, r > ' ' && r < 0x7f
+	}
+	m := Model{
+		path:     path,
+		apiKey:   &ui.TextField{Label: "API key", Value: cfg.APIKey, Masked: true, MaxLen: 128, Accept: keyChar},
+		airport:  &ui.TextField{Label: "Airport", Value: cfg.AirportCode, MaxLen: 3, Accept: upper},
+		validate: validate,
+		styles:   ui.NewSplitFlapStyles(),
+		choices: []*choice{
+			{label: "Icons", key: "GLYPHS", options: []string{"ascii", "unicode", "nerdfont"}},
+			{label: "Colors", key: "PALETTE", options: []string{"default", "colorblind"}},
+			{label: "Borders", key: "BORDERS", options: []string{"none", "frame", "full"}},
+		},
+	}
+	m.choices[0].selected = max(0, indexOf(m.choices[0].options, cfg.Glyphs))
+	m.choices[1].selected = max(0, indexOf(m.choices[1].options, cfg.Palette))
+	m.choices[2].selected = max(0, indexOf(m.choices[2].options, cfg.Borders))
+	return m
+}
+
+// indexOf returns the index of value in options, ignoring case, or -1
+func indexOf(options []string, value string) int {
+	for i, option := range options {
+		if strings.EqualFold(option, strings.TrimSpace(value)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Saved reports whether the settings were checked and written, rather than
+// the setup being cancelled
+func (m Model) Saved() bool {
+	return m.saved
+}
+
+// items returns the number of focusable items
+func (m Model) items() int {
+	return 2 + len(m.choices)
+}
+
+// Init starts with nothing to do
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles keys and the result of checking the API key
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case checkedMsg:
+		m.checking = false
+		if msg.err != nil {
+			m.err = msg.err.Error()
+			return m, nil
+		}
+		if err := config.WriteConfigFile(m.path, m.values()); err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		m.saved = true
+		return m, tea.Quit
+	case tea.KeyMsg:
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+// updateKey moves the focus, edits the focused item or saves
+func (m Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" || (msg.String() == "esc" && !m.checking) {
+		return m, tea.Quit
+	}
+	if m.checking {
+		return m, nil
+	}
+	switch msg.String() {
+	case "tab", "down":
+		m.focus = (m.focus + 1) % m.items()
+		return m, nil
+	case "shift+tab", "up":
+		m.focus = (m.focus - 1 + m.items()) % m.items()
+		return m, nil
+	case "enter":
+		if m.focus < m.items()-1 {
+			m.focus++
+			return m, nil
+		}
+		return m.save()
+	}
+
+	switch m.focus {
+	case 0:
+		m.apiKey.HandleKey(msg.String(), typed(msg))
+	case 1:
+		m.airport.HandleKey(msg.String(), typed(msg))
+	default:
+		c := m.choices[m.focus-2]
+		switch msg.String() {
+		case "left":
+			c.cycle(-1)
+		case "right", " ":
+			c.cycle(1)
+		}
+	}
+	return m, nil
+}
+
+// typed returns the characters a key message types, if any
+func typed(msg tea.KeyMsg) []rune {
+	switch msg.Type {
+	case tea.KeyRunes:
+		return msg.Runes
+	case tea.KeySpace:
+		return []rune{' '}
+	}
+	return nil
+}
+
+// save checks the settings, then the API key in the background
+func (m Model) save() (tea.Model, tea.Cmd) {
+	apiKey := strings.TrimSpace(m.apiKey.Value)
+	if apiKey == "" {
+		m.err, m.focus = "Enter your FlightAware AeroAPI key", 0
+		return m, nil
+	}
+	airport, err := config.NormalizeAirportCode(m.airport.Value)
+	if err != nil {
+		m.err, m.focus = err.Error(), 1
+		return m, nil
+	}
+	m.err = ""
+	m.checking = true
+	validate := m.validate
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		return checkedMsg{err: validate(ctx, apiKey, airport)}
+	}
+}
+
+// values returns the settings written to the config file
+func (m Model) values() map[string]string {
+	values := map[string]string{
+		"FLIGHTAWARE_API_KEY": strings.TrimSpace(m.apiKey.Value),
+		"AIRPORT_CODE":        m.airport.Value,
+	}
+	for _, c := range m.choices {
+		values[c.key] = c.options[c.selected]
+	}
+	return values
+}
+
+// View draws the setup form in the middle of the terminal
+func (m Model) View() string {
+	lines := []string{
+		m.styles.AirportLabel.Render("FIDS-TUI SETUP"),
+		m.styles.Text.Render("Flight data comes from FlightAware AeroAPI. Get a key at"),
+		m.styles.Text.Render("https://www.flightaware.com/aeroapi/portal, then paste it below."),
+		"",
+		m.apiKey.Render(m.focus == 0, m.styles),
+		m.airport.Render(m.focus == 1, m.styles),
+	}
+	for i, c := range m.choices {
+		lines = append(lines, c.render(m.focus == i+2, m.styles))
+	}
+	lines = append(lines, "")
+	switch {
+	case m.checking:
+		lines = append(lines, m.styles.Text.Render("Checking the key with one request..."))
+	case m.err != "":
+		lines = append(lines, m.styles.Error.Render(m.err))
+	default:
+		lines = append(lines, m.styles.PageInfo.Render("Settings are saved to "+m.path))
+	}
+	lines = append(lines, m.styles.PageInfo.Render("tab/↑/↓: move | ←/→: choose | enter: next, save on the last line | esc: quit"))
+
+	box := m.styles.Modal.Render(m.styles.Background.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
+	if m.width <= 0 || m.height <= 0 {
+		return box
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
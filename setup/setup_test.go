@@ -0,0 +1,166 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fids-tui/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// keyMsg returns the key message bubbletea sends for key, such as "a",
+// "enter" or "left"
+func keyMsg(key string) tea.KeyMsg {
+	for keyType, name := range map[tea.KeyType]string{
+		tea.KeyEnter: "enter", tea.KeyEsc: "esc", tea.KeyTab: "tab", tea.KeyCtrlC: "ctrl+c",
+		tea.KeyBackspace: "backspace", tea.KeyLeft: "left", tea.KeyRight: "right", tea.KeyDown: "down",
+	} {
+		if key == name {
+			return tea.KeyMsg{Type: keyType}
+		}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// press sends keys to m in turn, returning it and the command of the last
+func press(m Model, keys ...string) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var model tea.Model
+		model, cmd = m.Update(keyMsg(key))
+		m = model.(Model)
+	}
+	return m, cmd
+}
+
+// quits reports whether cmd quits the program
+func quits(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+// TestSetupSaves fills in the form, checking the key is checked against the
+// airport chosen and the settings are written to the config file, keeping
+// those already there
+func TestSetupSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("# Mine\nCITY_NAMES=true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var checked []string
+	validate := func(ctx context.Context, apiKey, airport string) error {
+		checked = append(checked, apiKey, airport)
+		return nil
+	}
+	m := New(path, config.Default(), validate)
+	m, _ = press(m, "k", "e", "y", "x", "backspace", "1", "tab", "j", "f", "k", "1", "enter")
+	if view := ansi.Strip(m.View()); strings.Contains(view, "key1") {
+		t.Errorf("API key shown unmasked:\n%s", view)
+	}
+	m, _ = press(m, "right", "right", "down", "left", "down", " ")
+	m, cmd := press(m, "enter")
+	if cmd == nil || !strings.Contains(ansi.Strip(m.View()), "Checking the key") {
+		t.Fatalf("saving didn't check the key:\n%s", ansi.Strip(m.View()))
+	}
+	if m, quit := press(m, "esc"); quits(quit) || !m.checking {
+		t.Error("esc left the setup while the key was being checked")
+	}
+
+	model, cmd := m.Update(cmd())
+	m = model.(Model)
+	if strings.Join(checked, " ") != "key1 JFK" {
+		t.Errorf("checked %q, want the key with JFK", checked)
+	}
+	if !m.Saved() || !quits(cmd) {
+		t.Fatalf("setup not saved and quit once the key checked out: %s", m.err)
+	}
+	values, err := config.ReadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"FLIGHTAWARE_API_KEY": "key1",
+		"AIRPORT_CODE":        "JFK",
+		"GLYPHS":              "nerdfont",
+		"PALETTE":             "colorblind",
+		"BORDERS":             "frame",
+		"CITY_NAMES":          "true",
+	}
+	for key, value := range want {
+		if values[key] != value {
+			t.Errorf("%s = %q, want %q", key, values[key], value)
+		}
+	}
+}
+
+// TestSetupErrors checks the form isn't saved without a key, with a bad
+// airport or a key that doesn't work, and that esc leaves it unsaved
+func TestSetupErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	rejected := func(ctx context.Context, apiKey, airport string) error {
+		return errors.New("FlightAware rejected the API key")
+	}
+	cfg := config.Default()
+	cfg.AirportCode = ""
+	m := New(path, cfg, rejected)
+
+	m, _ = press(m, "tab", "tab", "tab", "tab", "enter")
+	if m.focus != 0 || !strings.Contains(ansi.Strip(m.View()), "Enter your FlightAware AeroAPI key") {
+		t.Errorf("saved without a key: focus %d, error %q", m.focus, m.err)
+	}
+	m, _ = press(m, "k", "tab", "j", "f", "down", "down", "down", "enter")
+	if m.focus != 1 || m.err == "" {
+		t.Errorf("saved with airport JF: focus %d, error %q", m.focus, m.err)
+	}
+
+	m, _ = press(m, "k", "down", "down", "down")
+	m, cmd := press(m, "enter")
+	if cmd == nil {
+		t.Fatal("key not checked")
+	}
+	model, cmd := m.Update(cmd())
+	m = model.(Model)
+	if m.Saved() || quits(cmd) || !strings.Contains(ansi.Strip(m.View()), "FlightAware rejected the API key") {
+		t.Errorf("rejected key: saved %v, error %q", m.Saved(), m.err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("config file written for a rejected key: %v", err)
+	}
+
+	m, cmd = press(m, "esc")
+	if m.Saved() || !quits(cmd) {
+		t.Error("esc didn't leave the setup unsaved")
+	}
+}
+
+func TestSetupChoices(t *testing.T) {
+	cfg := config.Default()
+	cfg.Glyphs = "Unicode"
+	cfg.Palette = "unknown"
+	cfg.Borders = "full"
+	m := New("config", cfg, nil)
+	values := m.values()
+	for key, want := range map[string]string{"GLYPHS": "unicode", "PALETTE": "default", "BORDERS": "full"} {
+		if values[key] != want {
+			t.Errorf("%s starts as %q, want %q", key, values[key], want)
+		}
+	}
+	// Choices wrap round both ways
+	m, _ = press(m, "tab", "tab", "right", "right", "right")
+	m, _ = press(m, "tab", "tab", "left", "left", "left", "left")
+	if got := m.values()["GLYPHS"]; got != "unicode" {
+		t.Errorf("GLYPHS %q after going right round, want unicode", got)
+	}
+	if got := m.values()["BORDERS"]; got != "frame" {
+		t.Errorf("BORDERS %q after going left round, want frame", got)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+
+	"fids-tui/config"
+	"fids-tui/ui"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// chooseDisplay detects what the terminal supports and picks how the board
+// draws on it, rendering styles in the chosen color profile from then on
+func chooseDisplay(cfg *config.Config) ui.Display {
+	term := detectTerminal(termenv.NewOutput(os.Stdout), os.Getenv)
+	display := ui.ChooseDisplay(term, cfg.Glyphs, cfg.ForceColor, cfg.ForceUnicode)
+	lipgloss.SetColorProfile(display.Profile)
+	slog.Info("terminal detected", "term", os.Getenv("TERM"), "colors", term.Profile.Name(), "utf8", term.UTF8)
+	slog.Info("display chosen", "glyphs", display.Glyphs, "ascii_shades", display.ASCIIShades,
+		"styles", display.Styles, "colors", display.Profile.Name())
+	if !strings.EqualFold(display.Glyphs, cfg.Glyphs) && cfg.Glyphs != "" {
+		fmt.Fprintf(os.Stderr, "Warning: GLYPHS=%s needs a UTF-8 locale; using ascii (-force-unicode to override)\n", cfg.Glyphs)
+	}
+	return display
+}
+
+// detectTerminal detects what the terminal out writes to supports, going by
+// its environment: TERM, COLORTERM and NO_COLOR for its colors and the
+// locale for UTF-8
+func detectTerminal(out *termenv.Output, getenv func(string) string) ui.Terminal {
+	return ui.Terminal{
+		Profile: out.EnvColorProfile(),
+		UTF8:    utf8Locale(getenv),
+	}
+}
+
+// utf8Locale reports whether the locale is UTF-8, going by the first of
+// LC_ALL, LC_CTYPE and LANG that is set, as the C library does. Without
+// any, only Windows, whose consoles take UTF-8 regardless, counts as UTF-8
+func utf8Locale(getenv func(string) string) bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToLower(getenv(name)); value != "" {
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return runtime.GOOS == "windows"
+}
@@ -0,0 +1,73 @@
+//go:build !windows
+
+package main
+
+import (
+	"testing"
+
+	"fids-tui/ui"
+
+	"github.com/muesli/termenv"
+)
+
+// testEnviron is a terminal's environment, as termenv reads it
+type testEnviron map[string]string
+
+func (e testEnviron) Environ() []string {
+	var environ []string
+	for name, value := range e {
+		environ = append(environ, name+"="+value)
+	}
+	return environ
+}
+
+func (e testEnviron) Getenv(name string) string {
+	return e[name]
+}
+
+// TestChooseDisplay runs the display choice over terminals told apart by
+// TERM, COLORTERM, NO_COLOR and the locale, with and without the flags that
+// force color and Unicode
+func TestChooseDisplay(t *testing.T) {
+	const utf8 = "en_US.UTF-8"
+	for _, tt := range []struct {
+		name         string
+		env          testEnviron
+		forceColor   bool
+		forceUnicode bool
+		want         ui.Display
+	}{
+		{"256 colors", testEnviron{"TERM": "xterm-256color", "LANG": utf8},
+			false, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+		{"true color", testEnviron{"TERM": "xterm-256color", "COLORTERM": "truecolor", "LANG": utf8},
+			false, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.TrueColor}},
+		{"16 colors", testEnviron{"TERM": "xterm", "LANG": utf8},
+			false, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesBasic, Profile: termenv.ANSI}},
+		{"no colors", testEnviron{"TERM": "dumb", "LANG": utf8},
+			false, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesMono, Profile: termenv.ANSI}},
+		{"NO_COLOR", testEnviron{"TERM": "xterm-256color", "COLORTERM": "truecolor", "NO_COLOR": "1", "LANG": utf8},
+			false, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesMono, Profile: termenv.ANSI}},
+		{"NO_COLOR forced", testEnviron{"TERM": "xterm-256color", "NO_COLOR": "1", "LANG": utf8},
+			true, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+		{"16 colors forced", testEnviron{"TERM": "xterm", "LANG": utf8},
+			true, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+		{"true color forced", testEnviron{"TERM": "xterm-kitty", "LANG": utf8},
+			true, false, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.TrueColor}},
+		{"no UTF-8", testEnviron{"TERM": "xterm-256color", "LANG": "C"},
+			false, false, ui.Display{Glyphs: "ascii", ASCIIShades: true, Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+		{"LC_ALL over LANG", testEnviron{"TERM": "xterm-256color", "LC_ALL": "POSIX", "LANG": utf8},
+			false, false, ui.Display{Glyphs: "ascii", ASCIIShades: true, Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+		{"no UTF-8 forced", testEnviron{"TERM": "xterm-256color", "LANG": "C"},
+			false, true, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+		{"no UTF-8 nor colors", testEnviron{"TERM": "vt100"},
+			false, false, ui.Display{Glyphs: "ascii", ASCIIShades: true, Styles: ui.StylesMono, Profile: termenv.ANSI}},
+		{"everything forced", testEnviron{"TERM": "vt100"},
+			true, true, ui.Display{Glyphs: "unicode", Styles: ui.StylesColor, Profile: termenv.ANSI256}},
+	} {
+		out := termenv.NewOutput(nil, termenv.WithEnvironment(tt.env), termenv.WithTTY(true))
+		term := detectTerminal(out, tt.env.Getenv)
+		if got := ui.ChooseDisplay(term, "unicode", tt.forceColor, tt.forceUnicode); got != tt.want {
+			t.Errorf("%s: ChooseDisplay = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
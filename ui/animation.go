@@ -14,6 +14,18 @@ const (
 	CharStateComplete
 )
 
+// AnimationTiming sets how a changed character animates: it blinks between
+// a solid and a light block, switching every Phase, until Duration has
+// passed and the new character shows. How smoothly that is drawn depends
+// on how often Tick is called, which is the frame rate, not the length
+type AnimationTiming struct {
+	Phase    time.Duration // How long each blink phase lasts, zero for a steady block
+	Duration time.Duration // How long a changed character animates
+}
+
+// DefaultAnimationTiming is the board's original flap: three quick phases
+var DefaultAnimationTiming = AnimationTiming{Phase: 100 * time.Millisecond, Duration: 300 * time.Millisecond}
+
 // CharAnimation tracks the animation state for a character position
 type CharAnimation struct {
 	OldChar      rune
@@ -30,55 +42,42 @@ type AnimatedText struct {
 	NewText      string
 	Chars        []*CharAnimation
 	MaxLength    int
+	Timing       AnimationTiming
+	Blocks       [2]rune // Solid and light blocks blinked between
 	mu           sync.Mutex
 }
 
-// NewAnimatedText creates a new animated text with the given max length
+// NewAnimatedText creates a new animated text with the given max length,
+// animating with DefaultAnimationTiming between block characters
 func NewAnimatedText(maxLength int) *AnimatedText {
 	return &AnimatedText{
 		Chars:     make([]*CharAnimation, maxLength),
 		MaxLength: maxLength,
+		Timing:    DefaultAnimationTiming,
+		Blocks:    [2]rune{'█', '░'},
 	}
 }
 
 // Update sets new text and initiates animations for changed characters
 func (at *AnimatedText) Update(newText string) {
+	at.update(newText, time.Now())
+}
+
+// update sets new text, starting the animations of changed characters at now
+func (at *AnimatedText) update(newText string, now time.Time) {
 	at.mu.Lock()
 	defer at.mu.Unlock()
 
 	// Pad or truncate to max length
-	if len(newText) > at.MaxLength {
-		newText = newText[:at.MaxLength]
-	} else {
-		for len(newText) < at.MaxLength {
-			newText += " "
-		}
-	}
+	newRunes := fitRunes(newText, at.MaxLength)
+	oldRunes := fitRunes(at.OldText, at.MaxLength)
 
-	oldText := at.OldText
-	if len(oldText) > at.MaxLength {
-		oldText = oldText[:at.MaxLength]
-	} else {
-		for len(oldText) < at.MaxLength {
-			oldText += " "
-		}
-	}
-
-	at.NewText = newText
+	at.NewText = string(newRunes)
 
 	// Initialize or update character animations
 	for i := 0; i < at.MaxLength; i++ {
-		var oldChar, newChar rune
-		if i < len(oldText) {
-			oldChar = rune(oldText[i])
-		} else {
-			oldChar = ' '
-		}
-		if i < len(newText) {
-			newChar = rune(newText[i])
-		} else {
-			newChar = ' '
-		}
+		oldChar := oldRunes[i]
+		newChar := newRunes[i]
 
 		if at.Chars[i] == nil {
 			at.Chars[i] = &CharAnimation{
@@ -94,15 +93,32 @@ func (at *AnimatedText) Update(newText string) {
 			at.Chars[i].NewChar = newChar
 			at.Chars[i].State = CharStateBlinking
 			at.Chars[i].BlinkPhase = 0
-			at.Chars[i].StartTime = time.Now()
+			at.Chars[i].StartTime = now
 		}
 	}
 
-	at.OldText = newText
+	at.OldText = at.NewText
+}
+
+// fitRunes pads with spaces or truncates text to exactly length runes
+func fitRunes(text string, length int) []rune {
+	runes := []rune(text)
+	if len(runes) > length {
+		return runes[:length]
+	}
+	for len(runes) < length {
+		runes = append(runes, ' ')
+	}
+	return runes
 }
 
 // Tick updates animation states (call this periodically)
 func (at *AnimatedText) Tick() {
+	at.tick(time.Now())
+}
+
+// tick updates animation states as of now
+func (at *AnimatedText) tick(now time.Time) {
 	at.mu.Lock()
 	defer at.mu.Unlock()
 
@@ -113,12 +129,15 @@ func (at *AnimatedText) Tick() {
 
 		char.mu.Lock()
 		if char.State == CharStateBlinking {
-			// Toggle blink phase every ~100ms
-			elapsed := time.Since(char.StartTime)
-			char.BlinkPhase = int(elapsed.Milliseconds() / 100) % 2
+			// Toggle blink phase every Timing.Phase
+			elapsed := now.Sub(char.StartTime)
+			char.BlinkPhase = 0
+			if at.Timing.Phase > 0 {
+				char.BlinkPhase = int(elapsed/at.Timing.Phase) % 2
+			}
 
-			// After ~300ms, complete the animation
-			if elapsed > 300*time.Millisecond {
+			// After Timing.Duration, complete the animation
+			if elapsed > at.Timing.Duration {
 				char.State = CharStateComplete
 			}
 		} else if char.State == CharStateComplete {
@@ -129,6 +148,19 @@ func (at *AnimatedText) Tick() {
 	}
 }
 
+// settle ends the animations under way, showing the new characters at once
+func (at *AnimatedText) settle() {
+	at.mu.Lock()
+	defer at.mu.Unlock()
+	for _, char := range at.Chars {
+		if char != nil {
+			char.mu.Lock()
+			char.State = CharStateStable
+			char.mu.Unlock()
+		}
+	}
+}
+
 // Render returns the current display string with animations applied
 func (at *AnimatedText) Render() string {
 	at.mu.Lock()
@@ -146,9 +178,9 @@ func (at *AnimatedText) Render() string {
 		case CharStateBlinking:
 			// Show blinking cursor during animation
 			if char.BlinkPhase == 0 {
-				result[i] = '█' // Solid block
+				result[i] = at.Blocks[0] // Solid block
 			} else {
-				result[i] = '░' // Light block
+				result[i] = at.Blocks[1] // Light block
 			}
 		case CharStateComplete, CharStateStable:
 			result[i] = char.NewChar
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAnimationDuration checks that a one second flap lasts a second under a
+// fake clock, whatever the frame rate it is ticked at
+func TestAnimationDuration(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, frame := range []time.Duration{10 * time.Millisecond, 50 * time.Millisecond, 250 * time.Millisecond} {
+		text := NewAnimatedText(3)
+		text.Timing = AnimationTiming{Phase: 100 * time.Millisecond, Duration: time.Second}
+		text.update("NEW", start)
+
+		var shown time.Duration
+		for elapsed := frame; elapsed <= 2*time.Second; elapsed += frame {
+			text.tick(start.Add(elapsed))
+			if text.Render() == "NEW" {
+				shown = elapsed
+				break
+			}
+			if strings.ContainsAny(text.Render(), "NEW") {
+				t.Fatalf("frame %s: %q at %s, want blocks until the flap ends", frame, text.Render(), elapsed)
+			}
+		}
+		if shown <= time.Second || shown > time.Second+frame {
+			t.Errorf("frame %s: new text shown after %s, want within a frame after 1s", frame, shown)
+		}
+	}
+}
+
+// TestBlinkPhase checks that the blocks alternate every phase, and stay
+// solid with no phase
+func TestBlinkPhase(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		phase time.Duration
+		want  []string // At 50ms, 150ms, 250ms and 350ms
+	}{
+		{100 * time.Millisecond, []string{"██", "░░", "██", "░░"}},
+		{200 * time.Millisecond, []string{"██", "██", "░░", "░░"}},
+		{0, []string{"██", "██", "██", "██"}},
+	} {
+		text := NewAnimatedText(2)
+		text.Timing = AnimationTiming{Phase: tt.phase, Duration: time.Second}
+		text.update("AB", start)
+		for i, want := range tt.want {
+			at := time.Duration(50+100*i) * time.Millisecond
+			text.tick(start.Add(at))
+			if got := text.Render(); got != want {
+				t.Errorf("phase %s: %q at %s, want %q", tt.phase, got, at, want)
+			}
+		}
+	}
+}
+
+// TestBoardAnimationTiming checks that the board's rows animate with its
+// Animation rather than the default
+func TestBoardAnimationTiming(t *testing.T) {
+	for _, tt := range []struct {
+		duration  time.Duration
+		animating bool
+	}{
+		{0, false},
+		{time.Hour, true},
+	} {
+		board := newTestBoard(5)
+		board.Animation = AnimationTiming{Duration: tt.duration}
+		board.UpdateFlights(testFlights(3, time.Now()))
+		time.Sleep(time.Millisecond)
+		board.Tick()
+		board.Tick()
+		if got := board.IsAnimating(); got != tt.animating {
+			t.Errorf("duration %s: animating = %v after two ticks, want %v", tt.duration, got, tt.animating)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// bigGlyphRows is the number of terminal rows used by each big glyph
+const bigGlyphRows = 3
+
+// bigGlyphWidth is the number of terminal columns used by each big glyph
+const bigGlyphWidth = 3
+
+// bigGlyphBitmaps holds 3x5 pixel bitmaps for the big font, one string per
+// pixel row with '#' for set pixels. Pairs of pixel rows are drawn as a single
+// terminal row using half-block characters
+var bigGlyphBitmaps = map[rune][5]string{
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {".##", "#..", "#..", "#..", ".##"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {".##", "#..", "#.#", "#.#", ".##"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", ".#."},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {".#.", "#.#", "#.#", "#.#", ".#."},
+	'P': {"##.", "#.#", "##.", "#..", "#.."},
+	'Q': {".#.", "#.#", "#.#", "##.", ".##"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {".##", "#..", ".#.", "..#", "##."},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"##.", "..#", ".#.", "#..", "###"},
+	'3': {"##.", "..#", ".#.", "..#", "##."},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "##.", "..#", "##."},
+	'6': {".##", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "##."},
+	'-': {"...", "...", "###", "...", "..."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	' ': {"...", "...", "...", "...", "..."},
+}
+
+// bigGlyphAliases maps characters without their own glyph to a similar one
+var bigGlyphAliases = map[rune]rune{
+	'—': '-',
+	'–': '-',
+}
+
+// RenderBigText renders text in the big block font, returning one string per
+// terminal row. Glyphs are separated by a single blank column and characters
+// without a glyph are rendered as spaces
+func RenderBigText(text string) []string {
+	rows := make([]strings.Builder, bigGlyphRows)
+
+	for i, r := range []rune(text) {
+		bitmap := bigGlyph(r)
+		for row := 0; row < bigGlyphRows; row++ {
+			if i > 0 {
+				rows[row].WriteByte(' ')
+			}
+			top := bitmap[row*2]
+			bottom := "..."
+			if row*2+1 < len(bitmap) {
+				bottom = bitmap[row*2+1]
+			}
+			for col := 0; col < bigGlyphWidth; col++ {
+				rows[row].WriteRune(halfBlock(top[col] == '#', bottom[col] == '#'))
+			}
+		}
+	}
+
+	result := make([]string, bigGlyphRows)
+	for i := range rows {
+		result[i] = rows[i].String()
+	}
+	return result
+}
+
+// BigTextWidth returns the display width of text rendered with RenderBigText
+func BigTextWidth(text string) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return n*bigGlyphWidth + (n - 1)
+}
+
+// bigGlyph returns the bitmap for a character, falling back to a blank glyph
+func bigGlyph(r rune) [5]string {
+	r = unicode.ToUpper(r)
+	if alias, ok := bigGlyphAliases[r]; ok {
+		r = alias
+	}
+	if bitmap, ok := bigGlyphBitmaps[r]; ok {
+		return bitmap
+	}
+	return bigGlyphBitmaps[' ']
+}
+
+// halfBlock returns the block character drawing the given top and bottom pixels
+func halfBlock(top, bottom bool) rune {
+	switch {
+	case top && bottom:
+		return '█'
+	case top:
+		return '▀'
+	case bottom:
+		return '▄'
+	default:
+		return ' '
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderBigText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", []string{"", "", ""}},
+		{"letter and digit", "A1", []string{
+			"▄▀▄ ▄█ ",
+			"█▀█  █ ",
+			"▀ ▀ ▀▀▀",
+		}},
+		{"lowercase is drawn in capitals", "a1", []string{
+			"▄▀▄ ▄█ ",
+			"█▀█  █ ",
+			"▀ ▀ ▀▀▀",
+		}},
+		{"dash and its aliases", "-—–", []string{
+			"           ",
+			"▀▀▀ ▀▀▀ ▀▀▀",
+			"           ",
+		}},
+		{"clock", "12:05", []string{
+			"▄█  ▀▀▄  ▄  █▀█ █▀▀",
+			" █  ▄▀   ▄  █ █ ▀▀▄",
+			"▀▀▀ ▀▀▀     ▀▀▀ ▀▀ ",
+		}},
+		{"characters without a glyph are blank", "Ä?", []string{
+			"       ",
+			"       ",
+			"       ",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderBigText(tt.text)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("RenderBigText(%q) =\n%s\nwant\n%s", tt.text, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestBigTextWidth(t *testing.T) {
+	for _, text := range []string{"", "J", "JFK", "DEPARTURES — JFK", "23:59", "x?y"} {
+		want := BigTextWidth(text)
+		for i, row := range RenderBigText(text) {
+			if got := ansi.StringWidth(row); got != want {
+				t.Errorf("row %d of %q is %d wide, BigTextWidth says %d", i, text, got, want)
+			}
+		}
+	}
+}
+
+func TestBigGlyphBitmaps(t *testing.T) {
+	for r, bitmap := range bigGlyphBitmaps {
+		for i, row := range bitmap {
+			if len(row) != bigGlyphWidth || strings.Trim(row, "#.") != "" {
+				t.Errorf("glyph %q row %d is %q, want %d of '#' and '.'", r, i, row, bigGlyphWidth)
+			}
+		}
+	}
+	for _, r := range "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-" {
+		if _, ok := bigGlyphBitmaps[r]; !ok {
+			t.Errorf("no glyph for %q", r)
+		}
+	}
+}
@@ -3,98 +3,435 @@ package ui
 import (
 	"fids-tui/models"
 	"fmt"
+	"log/slog"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // Board manages the flight board display
 type Board struct {
-	Flights        []*FlightRow
-	CurrentPage    int
-	TotalPages     int
-	AirportCode    string
-	AirportTZ      *time.Location
-	FlightsPerPage int
-	Error          string
-	Styles         *SplitFlapStyles
+	mu              sync.Mutex                 // Held while the flight list is updated, animated or rendered
+	list            atomic.Pointer[flightList] // Current flights, replaced whole on each update
+	changedAt       map[string]time.Time       // When the data of each flight last changed, by seenKey
+	retimed         map[string]retime          // Flights whose scheduled time moved in a recent update, by seenKey
+	mergedAirports  []string                   // Airports whose flights are interleaved on the board, nil for one airport
+	airportZones    map[string]*time.Location  // Timezone of each of mergedAirports
+	Inbound         map[string]Inbound         // Inbound aircraft looked up for the detail panel, by the departure's InboundID
+	Tracking        map[string]Tracked         // Watched flights followed after they left the board, by number without spaces
+	sidebarRows     map[string]*FlightRow      // Rows of the watch sidebar, by seenKey
+	givenRemarks    map[string]models.Remarks  // Remarks flights came with, by seenKey, shown when Remarks is nil
+	cancellations   *Marquee                   // The cancellations strip, nil unless CancelStrip is set
+	ScrollColumns   map[ColumnID]bool          // Columns whose text scrolls when too long for them
+	CurrentPage     int
+	TotalPages      int
+	AirportCode     string
+	AirportTZ       *time.Location
+	Direction       models.Direction // Whether the board shows departures or arrivals
+	FlightsPerPage  int
+	Error           string // Shown prominently above the table
+	Toast           string // Shown quietly in the status bar until ToastUntil, for errors that usually clear up
+	ToastUntil      time.Time
+	Hint            string // Shown quietly under the header while the board has no flights, until cleared
+	Styles          *SplitFlapStyles
+	StyleSet        StyleSet         // Set Styles came from
+	Remarks         *RemarkTemplates // Remarks of each status, nil to show the remarks flights come with
+	Glyphs          *GlyphSet        // Icons such as the status lights
+	Seen            *SeenFlights     // Flights seen so far, for the NEW badge
+	Gates           *GateHistory     // Earlier gates of flights, for the detail panel
+	NewBadgeFor     time.Duration
+	remarksExpire   time.Time // When the first remark shown changes by itself, like a NEW badge ending, zero if none do
+	Layout          Layout
+	LayoutMode      LayoutMode
+	ViewMode        ViewMode        // Timetable or gate view
+	TimeZone        TimeZoneMode    // Timezone flight times are shown in
+	DestinationOnly string          // Departures are shown only to this airport code, if set
+	hiddenAirlines  map[string]bool // Airlines left off the board, by AirlineKey
+	allFlights      []models.Flight // Every flight of the last update, before the destination and airline filters
+	Selected        *FlightRow      // Row selected for the detail panel, if any
+	nextRow         *FlightRow      // Row of the next flight to depart, highlighted; nil if none
+	nextDeparts     time.Time       // When the flight of nextRow departs and the highlight moves on
+	StaleAfter      time.Duration   // Delayed flights past their time and unchanged for longer are marked stale, zero for never
+	RetimedFor      int             // Updates a retimed flight shows its old time in its remarks for, zero for none
+	PageInput       string          // Page number being typed, shown in the page info line
+	PageEntry       bool            // Whether a page number is being typed
+	PinImminent     bool            // Fill the first page with the next flights to depart, whatever the view's order
+	Shuttles        []string        // Destinations of the shuttle view in order, origins on arrivals boards
+	ShuttleDepth    int             // Flights shown for each destination in the shuttle view
+	WatchFlights    []string        // Flights listed in the watch sidebar, by number without spaces
+	WatchPlaces     []string        // Destinations whose next flights are listed in the watch sidebar
+	RotateEvery     time.Duration   // How long each page is shown before rotating
+	AdaptiveRotate  bool            // Show pages for longer or shorter than RotateEvery by their content
+	pageOrder       []*FlightRow    // Rows in the order they are paged in if not board order, else nil
+	watchPinned     bool            // Whether the first page leads with the watched flights, for want of room for the sidebar
+	Borders         BorderMode
+	LargeHeader     bool            // Render the airport title in the big block font
+	HeaderSuffix    string          // Branding shown after the airport title, cut short to fit
+	Timeline        bool            // Show the lookahead window as a bar under the header
+	CancelStrip     bool            // Show the cancelled flights on a line under the header
+	PageTransitions bool            // Flip the rows out and the next page in when the page changes
+	Animation       AnimationTiming // How changed characters of the rows animate
+	transition      *pageTransition // Page change being animated, nil if none
+	Lookahead       time.Duration   // Length of the lookahead window, zero if it has no end
+	WindowLimit     time.Duration   // Shorter window the source limited the last fetch to, such as its plan limit
+	SlowFetch       time.Duration   // How long the last fetch took if it was slow, zero if it wasn't
+	Skipped         string          // Flights the source sent without required fields, e.g. "3 flights skipped (no scheduled time)"
+	TermWidth       int             // Terminal size, zero until known
+	TermHeight      int
+	ReservedLines   int           // Terminal lines the caller uses outside the board
+	fittedPerPage   int           // Flights per page that fit the terminal when fewer than configured, else zero
+	frameDropped    bool          // The frame is left off a terminal too short for it and a flight row
+	emptySince      time.Time     // When the flight list became empty, zero if it has flights
+	NextUpdate      time.Time     // When the next data refresh is due, shown in the status bar
+	PausedUntil     time.Time     // Updates are paused until this time, zero if they are not
+	LastUpdate      UpdateSummary // Changes made by the most recent UpdateFlights
+	updated         bool          // Whether UpdateFlights has been called
+	FetchedPages    int           // Result pages fetched for the last update, if known
+	FlightsKept     int           // Flights kept under the source's flight cap
+	FlightsFound    int           // Flights the source found, more than FlightsKept when some were dropped
+	APISpend        string        // Estimated API cost, shown in the status bar if set
+	APIBudget       string        // Calls of the hourly API budget used, shown in the status bar if set
+	Provenance      Provenance    // Where the flights on the board came from
+	KeepPage        bool          // Keep the first flight on the page in view across updates
+	warnedTooWide   bool          // Whether the table not fitting the terminal has been logged
+	flashUntil      time.Time
 }
 
+// flightList is the board's flights as of one update. A list is never
+// modified once stored, so it can be read while the next one is being built
+type flightList struct {
+	rows    []*FlightRow    // Rows in board order, animated by the UI
+	flights []models.Flight // The flights the rows were built from, in board order
+}
+
+// Rows returns the flight rows in board order, which callers must not modify
+func (b *Board) Rows() []*FlightRow {
+	if list := b.list.Load(); list != nil {
+		return list.rows
+	}
+	return nil
+}
+
+// Flights returns the flights on the board in board order. Unlike the rows,
+// the returned flights are never changed by later updates, so they can be
+// read from other goroutines
+func (b *Board) Flights() []models.Flight {
+	if list := b.list.Load(); list != nil {
+		return list.flights
+	}
+	return nil
+}
+
+// FlightCount returns the number of flights on the board
+func (b *Board) FlightCount() int {
+	return len(b.Rows())
+}
+
+// setRows replaces the flight list with rows
+func (b *Board) setRows(rows []*FlightRow) {
+	b.finishTransition()
+	flights := make([]models.Flight, len(rows))
+	for i, row := range rows {
+		flights[i] = *row.Flight
+	}
+	b.list.Store(&flightList{rows: rows, flights: flights})
+	for _, row := range rows {
+		row.changedAt = b.changedAt[seenKey(row.Flight)]
+	}
+	b.findNextFlight(time.Now())
+	b.markStale(time.Now())
+	b.markGates(time.Now())
+	b.updateCancellations()
+	b.orderPages(time.Now())
+	b.syncSidebar(time.Now())
+}
+
+// findNextFlight highlights the flight departing soonest after now, by its
+// estimated time if known. Flights cancelled or already away from the gate
+// are left out, as are arrivals
+func (b *Board) findNextFlight(now time.Time) {
+	var next *FlightRow
+	var departs time.Time
+	for _, row := range b.Rows() {
+		if t, ok := departsAfter(row.Flight, now); ok && (next == nil || t.Before(departs)) {
+			next, departs = row, t
+		}
+	}
+	if b.nextRow != nil {
+		b.nextRow.next = false
+	}
+	if next != nil {
+		next.next = true
+	}
+	b.nextRow, b.nextDeparts = next, departs
+}
+
+// departsAfter returns when flight is expected to leave if it is a departure
+// still to leave after now: by its estimated time if known, and neither
+// cancelled nor already away from the gate
+func departsAfter(flight *models.Flight, now time.Time) (time.Time, bool) {
+	if flight == nil || flight.Direction != models.Departure {
+		return time.Time{}, false
+	}
+	switch flight.Status {
+	case models.StatusCancelled, models.StatusDeparted, models.StatusTaxiingLeftGate, models.StatusTaxiingDelayed:
+		return time.Time{}, false
+	}
+	t := flight.EffectiveDeparture()
+	return t, t.After(now)
+}
+
+// moveNextFlight moves the highlight on once the highlighted flight's time
+// has passed, without waiting for an update. The flight leaves the pinned
+// first page then too
+func (b *Board) moveNextFlight(now time.Time) {
+	if b.nextRow != nil && !now.Before(b.nextDeparts) {
+		b.findNextFlight(now)
+		if b.PinImminent || b.watching() {
+			b.updatePagination()
+		}
+	}
+}
+
+// BorderMode controls the frame drawn around the board
+type BorderMode int
+
+const (
+	BordersNone  BorderMode = iota // Clean look without any frame
+	BordersFrame                   // Border around the board and a rule under the header
+	BordersFull                    // Frame plus vertical separators between columns
+)
+
+// ParseBorderMode parses a BORDERS config value (none, frame or full)
+func ParseBorderMode(value string) (BorderMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return BordersNone, nil
+	case "frame":
+		return BordersFrame, nil
+	case "full":
+		return BordersFull, nil
+	default:
+		return BordersNone, fmt.Errorf("unknown border mode %q (expected none, frame or full)", value)
+	}
+}
+
+// UpdateSummary counts how the rows of the board changed in an update
+type UpdateSummary struct {
+	Added     int           // Flights that were not on the board before
+	Changed   int           // Flights with at least one changed cell
+	Unchanged int           // Flights whose cells are identical
+	Removed   int           // Flights no longer on the board
+	Events    []ChangeEvent // Gate, status and estimate changes to existing flights
+}
+
+// Any reports whether the update changed anything on the board
+func (s UpdateSummary) Any() bool {
+	return s.Added > 0 || s.Changed > 0 || s.Removed > 0
+}
+
+// String describes the changes, e.g. "2 new, 1 changed"
+func (s UpdateSummary) String() string {
+	if !s.Any() {
+		return "no changes"
+	}
+	var parts []string
+	if s.Added > 0 {
+		parts = append(parts, fmt.Sprintf("%d new", s.Added))
+	}
+	if s.Changed > 0 {
+		parts = append(parts, fmt.Sprintf("%d changed", s.Changed))
+	}
+	if s.Removed > 0 {
+		parts = append(parts, fmt.Sprintf("%d gone", s.Removed))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pageInfoFlash is how long the page info line stays highlighted after a jump
+const pageInfoFlash = time.Second
+
 // NewBoard creates a new flight board
 func NewBoard(airportCode string, airportTZ *time.Location, flightsPerPage int) *Board {
 	return &Board{
-		Flights:        make([]*FlightRow, 0),
 		CurrentPage:    0,
 		TotalPages:     1,
 		AirportCode:    airportCode,
 		AirportTZ:      airportTZ,
 		FlightsPerPage: flightsPerPage,
 		Styles:         NewSplitFlapStyles(),
+		Remarks:        DefaultRemarkTemplates(),
+		Glyphs:         DefaultGlyphSet(),
+		Seen:           NewSeenFlights(nil, time.Now()),
+		Gates:          NewGateHistory(),
+		NewBadgeFor:    time.Hour,
+		RetimedFor:     3,
+		ShuttleDepth:   3,
+		Animation:      DefaultAnimationTiming,
+		Layout:         ViewFor(ViewFlights).Layout(LayoutWide, models.Departure),
 	}
 }
 
 // UpdateFlights updates the flight list and creates/updates flight rows
-func (b *Board) UpdateFlights(flights []models.Flight) {
-	// Convert departure times to airport local time
+// Only cells whose text changed are animated; the returned summary counts the
+// rows that were added, changed, left as they were or removed
+func (b *Board) UpdateFlights(flights []models.Flight) UpdateSummary {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var summary UpdateSummary
+	// The board keeps its own copy, so the caller's flights are never changed
+	// and can be shown on other boards in other zones. Times stay as the
+	// source reported them and are converted for display
+	flights = append([]models.Flight(nil), flights...)
+	b.trackRetimes(b.allFlights, flights)
+	b.trackGates(b.allFlights, flights, time.Now())
+
+	// Flights first seen after the first update are new if they fall within
+	// the times the last update already covered; later ones have only just
+	// come into the lookahead window
+	seenAt := time.Now()
+	covered := latestScheduled(b.allFlights)
+	b.Seen.Prune(seenAt)
 	for i := range flights {
-		if b.AirportTZ != nil {
-			flights[i].ScheduledDeparture = flights[i].ScheduledDeparture.In(b.AirportTZ)
-			// Convert estimated departure time to local time if present
-			if flights[i].EstimatedDeparture != nil {
-				localEst := flights[i].EstimatedDeparture.In(b.AirportTZ)
-				flights[i].EstimatedDeparture = &localEst
-				// Update remarks for delayed flights with estimated time
-				if flights[i].Status == models.StatusDelayed {
-					estTimeStr := localEst.Format("15:04")
-					flights[i].Remarks = models.Remarks(fmt.Sprintf("Delayed EST: %s", estTimeStr))
-				}
-			}
-		}
+		isNew := b.updated && !flights[i].ScheduledTime().After(covered)
+		b.Seen.see(seenKey(&flights[i]), seenAt, isNew)
+	}
+
+	b.remarksExpire = time.Time{}
+	b.givenRemarks = make(map[string]models.Remarks, len(flights))
+	for i := range flights {
+		b.givenRemarks[seenKey(&flights[i])] = flights[i].Remarks
+	}
+	for i := range flights {
+		// Generate remarks text from the status templates
+		flights[i].Remarks = b.renderRemarks(&flights[i], b.zoneFor(&flights[i]), seenAt)
 	}
 
-	// Sort flights by departure time ascending
-	sort.Slice(flights, func(i, j int) bool {
-		return flights[i].ScheduledDeparture.Before(flights[j].ScheduledDeparture)
+	// Sort flights in the order of the view, by time for the timetable
+	view := ViewFor(b.ViewMode)
+	sort.SliceStable(flights, func(i, j int) bool {
+		return view.Less(&flights[i], &flights[j])
 	})
 
-	// Create a map of existing flights by flight number
-	existingMap := make(map[string]*FlightRow)
-	for _, row := range b.Flights {
+	// Keep every flight so changing the destination filter needs no fetch
+	b.trackChanges(b.allFlights, flights, seenAt)
+	b.allFlights = flights
+	flights = b.filtered(flights)
+
+	// Compare with the flights already on the board
+	current := b.Rows()
+	oldRows := make([]*FlightRow, 0, len(current))
+	oldFlights := make([]models.Flight, 0, len(current))
+	for _, row := range current {
 		if row.Flight != nil {
-			key := row.Flight.FlightNumber
-			existingMap[key] = row
+			oldRows = append(oldRows, row)
+			oldFlights = append(oldFlights, *row.Flight)
 		}
 	}
+	diff := models.DiffFlights(oldFlights, flights)
+	days := dayOffsets(flights, b.zoneFor)
 
 	// Update or create flight rows
-	newRows := make([]*FlightRow, 0, len(flights))
-	for i := range flights {
-		flight := &flights[i]
-		key := flight.FlightNumber
-
-		if existingRow, exists := existingMap[key]; exists {
-			// Update existing row (triggers animation)
-			existingRow.Update(flight)
-			newRows = append(newRows, existingRow)
+	now := time.Now()
+	if b.AirportTZ != nil {
+		now = now.In(b.AirportTZ)
+	}
+	rows := make([]*FlightRow, len(flights))
+	for _, change := range diff.Matched {
+		// Record what changed, then update the row (animates changed cells only)
+		row := oldRows[change.Old]
+		flight := &flights[change.New]
+		summary.Events = append(summary.Events, changeEvents(b.airportOf(flight), days[change.New], change, row.Flight, flight, now)...)
+		row.zone = b.zoneFor(flight)
+		row.day = days[change.New]
+		if row.Update(flight) {
+			summary.Changed++
 		} else {
-			// Create new row
-			row := NewFlightRow(flight)
-			newRows = append(newRows, row)
+			summary.Unchanged++
+		}
+		rows[change.New] = row
+	}
+	for _, i := range diff.Added {
+		rows[i] = NewFlightRow(&flights[i], b.Layout, b.zoneFor(&flights[i]), b.Glyphs, b.Animation)
+		if days[i] > 0 {
+			rows[i].day = days[i]
+			rows[i].Update(&flights[i])
 		}
+		summary.Added++
 	}
+	summary.Removed = len(diff.Removed)
 
-	b.Flights = newRows
+	anchor := b.firstOnPage()
+	b.setRows(rows)
 	b.updatePagination()
+	if b.KeepPage {
+		b.showRow(anchor)
+	}
+	b.LastUpdate = summary
+	b.updated = true
+
+	// Track how long the board has been empty for the idle clock
+	if len(rows) == 0 {
+		if b.emptySince.IsZero() {
+			b.emptySince = time.Now()
+		}
+	} else {
+		b.emptySince = time.Time{}
+	}
+
+	// Drop the selection if the selected flight is no longer on the board
+	if b.Selected != nil && b.indexOf(b.Selected) < 0 {
+		b.Selected = nil
+	}
+
+	return summary
+}
+
+// Rebuild replaces the board's flights as if they were its first, building
+// every row afresh and showing it at once. A replay stepping back in time
+// uses it, so rows don't flip from a later state or mark changes that were
+// yet to happen
+func (b *Board) Rebuild(flights []models.Flight) UpdateSummary {
+	b.mu.Lock()
+	b.list.Store(&flightList{})
+	b.allFlights = nil
+	b.changedAt = nil
+	b.retimed = nil
+	b.sidebarRows = nil
+	b.Gates = NewGateHistory()
+	b.updated = false
+	b.mu.Unlock()
+
+	summary := b.UpdateFlights(flights)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, row := range b.Rows() {
+		row.settle()
+	}
+	for _, row := range b.sidebarRows {
+		row.settle()
+	}
+	return summary
 }
 
 // updatePagination updates pagination info
 func (b *Board) updatePagination() {
-	flightsPerPage := b.FlightsPerPage
-	if flightsPerPage <= 0 {
-		flightsPerPage = 10 // Default fallback
+	b.fitToHeight()
+	flightsPerPage := b.perPage()
+	totalFlights := b.FlightCount()
+	b.orderPages(time.Now())
+	if b.shuttleMode() {
+		// Pages hold destinations, whether or not they have flights
+		totalFlights = len(b.pageOrder)
 	}
-	totalFlights := len(b.Flights)
 
 	if totalFlights == 0 {
 		b.TotalPages = 1
@@ -111,41 +448,96 @@ func (b *Board) updatePagination() {
 	}
 }
 
+// firstOnPage returns the first flight row on the current page, or nil if
+// the page is empty
+func (b *Board) firstOnPage() *FlightRow {
+	rows := b.pagedRows()
+	index := b.CurrentPage * b.perPage()
+	if index < 0 || index >= len(rows) {
+		return nil
+	}
+	return rows[index]
+}
+
+// showRow moves to the page holding row, leaving the page alone if the row
+// is no longer on the board
+func (b *Board) showRow(row *FlightRow) {
+	if row == nil {
+		return
+	}
+	for i, r := range b.pagedRows() {
+		if r == row {
+			b.CurrentPage = i / b.perPage()
+			return
+		}
+	}
+}
+
 // NextPage moves to the next page
 func (b *Board) NextPage() {
 	b.updatePagination()
+	b.startTransition()
 	b.CurrentPage = (b.CurrentPage + 1) % b.TotalPages
 }
 
+// PrevPage moves to the previous page
+func (b *Board) PrevPage() {
+	b.updatePagination()
+	b.startTransition()
+	b.CurrentPage = (b.CurrentPage - 1 + b.TotalPages) % b.TotalPages
+}
+
+// GoToPage moves to the given 1-based page, clamped to the available pages
+func (b *Board) GoToPage(page int) {
+	b.updatePagination()
+	if page < 1 {
+		page = 1
+	}
+	if page > b.TotalPages {
+		page = b.TotalPages
+	}
+	b.CurrentPage = page - 1
+	b.FlashPageInfo()
+}
+
+// SetPageInput shows or hides the page number being typed in the page info line
+func (b *Board) SetPageInput(active bool, input string) {
+	b.PageEntry = active
+	b.PageInput = input
+}
+
+// FlashPageInfo briefly highlights the page info line
+func (b *Board) FlashPageInfo() {
+	b.flashUntil = time.Now().Add(pageInfoFlash)
+}
+
 // GetCurrentPageFlights returns flights for the current page
 // Always returns exactly flightsPerPage rows, filling with empty rows if needed
 func (b *Board) GetCurrentPageFlights() []*FlightRow {
-	flightsPerPage := b.FlightsPerPage
-	if flightsPerPage <= 0 {
-		flightsPerPage = 10 // Default fallback
-	}
+	flightsPerPage := b.perPage()
 	start := b.CurrentPage * flightsPerPage
 	end := start + flightsPerPage
 
 	result := make([]*FlightRow, flightsPerPage)
 
 	// Copy actual flights
+	rows := b.pagedRows()
 	actualEnd := end
-	if start >= len(b.Flights) {
+	if start >= len(rows) {
 		actualEnd = start
-	} else if end > len(b.Flights) {
-		actualEnd = len(b.Flights)
+	} else if end > len(rows) {
+		actualEnd = len(rows)
 	}
 
 	copyCount := 0
-	if start < len(b.Flights) {
+	if start < len(rows) {
 		copyCount = actualEnd - start
-		copy(result, b.Flights[start:actualEnd])
+		copy(result, rows[start:actualEnd])
 	}
 
 	// Fill remaining slots with empty rows
 	for i := copyCount; i < flightsPerPage; i++ {
-		result[i] = NewFlightRow(nil)
+		result[i] = NewFlightRow(nil, b.Layout, b.displayZone(), b.Glyphs, b.Animation)
 	}
 
 	return result
@@ -153,52 +545,508 @@ func (b *Board) GetCurrentPageFlights() []*FlightRow {
 
 // Tick updates all flight row animations
 func (b *Board) Tick() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	// Update all flights
-	for _, row := range b.Flights {
+	for _, row := range b.Rows() {
 		row.Tick()
 	}
+	for _, row := range b.sidebarRows {
+		row.Tick()
+	}
+	b.tickTransition(time.Now())
+	b.moveNextFlight(time.Now())
+	if b.cancellations != nil {
+		b.cancellations.Step(b.tableWidth())
+	}
+}
+
+// IsAnimating returns true if any flight row is currently animating, or a
+// page transition is under way
+func (b *Board) IsAnimating() bool {
+	if b.transition != nil {
+		return true
+	}
+	for _, row := range b.Rows() {
+		if row.IsAnimating() {
+			return true
+		}
+	}
+	for _, row := range b.sidebarRows {
+		if row.IsAnimating() {
+			return true
+		}
+	}
+	return false
 }
 
 // Render renders the entire board
 func (b *Board) Render() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Airport header, error message and table header
+	sections := b.renderTop()
+
+	// Flight rows for current page (always shows flightsPerPage rows)
+	pageFlights := b.pageRows()
+	if b.shuttleMode() {
+		sections = append(sections, b.renderShuttles(pageFlights)...)
+		pageFlights = nil
+	}
+	for _, row := range pageFlights {
+		if row != nil {
+			styles := b.Styles
+			if row.Flight != nil && row == b.Selected {
+				styles = b.Styles.selectedVariant()
+			}
+			rowStr := row.Render(styles)
+			sections = append(sections, rowStr)
+		}
+	}
+
+	// Page info
+	pageInfo := b.renderPageInfo()
+	sections = append(sections, pageInfo)
+
+	// Status bar, cut to the table's width so a long one doesn't widen
+	// every line of the board
+	if status := b.renderStatusBar(time.Now()); status != "" {
+		sections = append(sections, ansi.Truncate(status, b.tableWidth(), "…"))
+	}
+
+	// Detail panel for the selected flight
+	if b.Selected != nil {
+		sections = append(sections, b.renderDetail(b.Selected, time.Now()))
+	}
+
+	// Combine all sections
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	if b.sidebarShown() {
+		content = b.joinSidebar(content, time.Now())
+	}
+	return b.truncateToTerminal(b.frameStyle().Render(content))
+}
+
+// truncateToTerminal cuts lines wider than the terminal, marking the cut with
+// "…", so they never wrap and corrupt the screen
+func (b *Board) truncateToTerminal(view string) string {
+	if b.TermWidth <= 0 || lipgloss.Width(view) <= b.TermWidth {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if ansi.StringWidth(line) > b.TermWidth {
+			lines[i] = ansi.Truncate(line, b.TermWidth, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// frameStyle returns the outer board style, including the border when enabled
+func (b *Board) frameStyle() lipgloss.Style {
+	style := b.Styles.Background
+	if !b.framed() {
+		return style
+	}
+	// Size the frame from the table width so every line fills it exactly
+	return style.
+		Width(b.Width() + style.GetHorizontalPadding()).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(b.Styles.BorderLine.GetForeground()).
+		BorderBackground(style.GetBackground())
+}
+
+// framed reports whether the board is drawn in its frame
+func (b *Board) framed() bool {
+	return b.Borders != BordersNone && !b.frameDropped
+}
+
+// RenderedWidth returns the total display width of the rendered board,
+// including padding and any frame
+func (b *Board) RenderedWidth() int {
+	style := b.frameStyle()
+	return b.Width() + style.GetHorizontalPadding() + style.GetHorizontalBorderSize()
+}
+
+// SetBorders sets the frame mode
+func (b *Board) SetBorders(mode BorderMode) {
+	b.Borders = mode
+	if mode == BordersFull {
+		b.Styles.Separator = boxColumnSeparator
+	} else {
+		b.Styles.Separator = columnSeparator
+	}
+	b.refit()
+	b.updatePagination()
+}
+
+// EmptyFor returns how long the flight list has been empty as of now, or zero
+// if it has flights or no update has been applied yet
+func (b *Board) EmptyFor(now time.Time) time.Duration {
+	if b.emptySince.IsZero() {
+		return 0
+	}
+	return now.Sub(b.emptySince)
+}
+
+// RenderIdleClock renders the large clock shown while there are no departures
+func (b *Board) RenderIdleClock(now time.Time) string {
+	if b.AirportTZ != nil {
+		now = now.In(b.AirportTZ)
+	}
+	width := b.Width()
+
+	var sections []string
+	sections = append(sections, b.Styles.AirportLabel.Render(b.airportLabel()))
+	clock := now.Format("15:04")
+	if b.bigTextFits(clock) {
+		clock = lipgloss.JoinVertical(lipgloss.Left, RenderBigText(clock)...)
+	}
+	sections = append(sections, b.Styles.Header.UnsetUnderline().Render(clock))
+	sections = append(sections, b.Styles.PageInfo.Render("NO SCHEDULED "+strings.ToUpper(b.Direction.String())))
+	if hint := b.hint(); hint != "" {
+		sections = append(sections, hint)
+	}
+	sections = append(sections, b.trackingLines(now)...)
+
+	for i, section := range sections {
+		sections[i] = lipgloss.PlaceHorizontal(width, lipgloss.Center, section)
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return b.frameStyle().Render(content)
+}
+
+// hint renders Hint with how to dismiss it, or returns "" if there is no
+// hint or the board has flights
+func (b *Board) hint() string {
+	if b.Hint == "" || b.FlightCount() > 0 {
+		return ""
+	}
+	return b.Styles.PageInfo.Render(b.Hint + " (esc to dismiss)")
+}
+
+// renderTop renders the sections above the flight rows
+func (b *Board) renderTop() []string {
 	var sections []string
 
 	// Airport header
 	airportHeader := b.renderAirportHeader()
 	sections = append(sections, airportHeader)
 
+	// Where the flights fall in the lookahead window. Mono terminals can't
+	// tell the page's part of the bar from the rest, so it is left out
+	if b.Timeline && b.StyleSet != StylesMono {
+		if timeline := b.renderTimeline(time.Now()); timeline != "" {
+			sections = append(sections, timeline)
+		}
+	}
+
+	// Cancelled flights, wherever they are on the board
+	if strip, ok := b.renderCancellations(); ok {
+		sections = append(sections, strip)
+	}
+
 	// Error message if any
 	if b.Error != "" {
 		errorMsg := b.Styles.Error.Render("ERROR: " + b.Error)
 		sections = append(sections, errorMsg)
 	}
+	if hint := b.hint(); hint != "" {
+		sections = append(sections, hint)
+	}
+
+	// Watched flights that have left the board, when there is no sidebar
+	if !b.sidebarShown() {
+		sections = append(sections, b.trackingLines(time.Now())...)
+	}
 
 	// Table header
 	header := b.renderHeader()
 	sections = append(sections, header)
 
-	// Flight rows for current page (always shows flightsPerPage rows)
-	pageFlights := b.GetCurrentPageFlights()
-	for _, row := range pageFlights {
-		if row != nil {
-			rowStr := row.Render(b.Styles)
-			sections = append(sections, rowStr)
+	// Rule under the header when framed
+	if b.framed() {
+		rule := horizontalRule(b.Layout.Lines[0], b.Styles.Separator)
+		if padding := b.tableWidth() - ansi.StringWidth(rule); padding > 0 {
+			rule += strings.Repeat("─", padding)
 		}
+		sections = append(sections, b.Styles.BorderLine.Render(rule))
 	}
 
-	// Page info
-	pageInfo := b.renderPageInfo()
-	sections = append(sections, pageInfo)
+	return sections
+}
 
-	// Combine all sections
-	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
-	return b.Styles.Background.Render(content)
+// rowsOffset returns the line of the rendered board on which the first flight row appears
+func (b *Board) rowsOffset() int {
+	top := lipgloss.JoinVertical(lipgloss.Left, b.renderTop()...)
+	frame := b.frameStyle()
+	return frame.GetBorderTopSize() + frame.GetPaddingTop() + lipgloss.Height(top)
+}
+
+// RowAt maps a line of the rendered board to the index in Rows of the row
+// shown on that line, returning false if the line is not a populated flight
+// row. Rows of the shuttle view, between their headings, aren't mapped
+func (b *Board) RowAt(y int) (int, bool) {
+	line := y - b.rowsOffset()
+	if line < 0 || b.shuttleMode() {
+		return 0, false
+	}
+	slot := line / b.Layout.LinesPerFlight()
+	if slot >= b.perPage() {
+		return 0, false
+	}
+	index := b.CurrentPage*b.perPage() + slot
+	if index >= b.FlightCount() {
+		return 0, false
+	}
+	if b.pageOrder != nil {
+		return b.indexOf(b.pageOrder[index]), true
+	}
+	return index, true
+}
+
+// IsPageInfoLine reports whether a line of the rendered board is the page info line
+func (b *Board) IsPageInfoLine(y int) bool {
+	if b.TotalPages <= 1 && !b.PageEntry {
+		return false
+	}
+	// The page info follows the rows after its top margin
+	line := b.rowsOffset() + b.pageLines() + b.Styles.PageInfo.GetMarginTop()
+	return y == line
+}
+
+// Select selects the row at index in Rows, or clears the selection if the
+// row is already selected or index is out of range
+func (b *Board) Select(index int) {
+	rows := b.Rows()
+	if index < 0 || index >= len(rows) || rows[index] == b.Selected {
+		b.Selected = nil
+		return
+	}
+	b.Selected = rows[index]
+}
+
+// ClearSelection clears the selected row
+func (b *Board) ClearSelection() {
+	b.Selected = nil
+}
+
+// indexOf returns the index of row in Rows, or -1 if it is not on the board
+func (b *Board) indexOf(row *FlightRow) int {
+	for i, r := range b.Rows() {
+		if r == row {
+			return i
+		}
+	}
+	return -1
+}
+
+// perPage returns the number of flight rows per page: the configured number,
+// or fewer when the terminal is too short to show them all. The shuttle view
+// fills whole destination sections, blank rows included
+func (b *Board) perPage() int {
+	rows := b.configuredPerPage()
+	if b.fittedPerPage > 0 && b.fittedPerPage < rows {
+		rows = b.fittedPerPage
+	}
+	if b.shuttleMode() {
+		// Each destination's flights, under a heading of their own
+		return b.shuttlesPerPage(rows) * b.ShuttleDepth
+	}
+	return rows
+}
+
+// configuredPerPage returns the configured number of flight rows per page
+func (b *Board) configuredPerPage() int {
+	if b.FlightsPerPage <= 0 {
+		return 10 // Default fallback
+	}
+	return b.FlightsPerPage
+}
+
+// rowsThatFit returns how many flights fit the terminal height alongside the
+// rest of the board and the caller's reserved lines, or zero if the height
+// is unknown. At least one flight is always shown
+func (b *Board) rowsThatFit() int {
+	if b.TermHeight <= 0 {
+		return 0
+	}
+	return max(1, b.linesForRows()/b.Layout.LinesPerFlight())
+}
+
+// linesForRows returns the terminal lines left for flight rows by the rest of
+// the board and the caller's reserved lines
+func (b *Board) linesForRows() int {
+	frame := b.frameStyle()
+	// Page info with its margin, the status bar and the bottom of the frame
+	below := b.Styles.PageInfo.GetMarginTop() + 1 + 1 + frame.GetPaddingBottom() + frame.GetBorderBottomSize()
+	return b.TermHeight - b.ReservedLines - b.rowsOffset() - below
+}
+
+// fitToHeight limits the flights per page to what fits the terminal,
+// logging when the limit changes. A terminal too short for the frame and a
+// flight row besides gets the board without its frame
+func (b *Board) fitToHeight() {
+	dropped := b.frameDropped
+	b.frameDropped = false
+	if b.Borders != BordersNone && b.TermHeight > 0 && b.linesForRows() < b.Layout.LinesPerFlight() {
+		b.frameDropped = true
+	}
+	if b.frameDropped != dropped {
+		slog.Info("terminal too short for the board's frame", "dropped", b.frameDropped, "terminal_height", b.TermHeight)
+	}
+
+	fitted := b.rowsThatFit()
+	if fitted >= b.configuredPerPage() {
+		fitted = 0
+	}
+	if fitted == b.fittedPerPage {
+		return
+	}
+	if fitted > 0 {
+		slog.Info("terminal too short for every flight on a page, showing fewer",
+			"flights_per_page", fitted, "configured", b.configuredPerPage(), "terminal_height", b.TermHeight)
+	} else {
+		slog.Info("terminal fits every flight on a page again", "flights_per_page", b.configuredPerPage())
+	}
+	b.fittedPerPage = fitted
+}
+
+// SetReservedLines sets the number of terminal lines the caller shows
+// outside the board, such as help text, which flight rows can't use
+func (b *Board) SetReservedLines(lines int) {
+	if b.ReservedLines == lines {
+		return
+	}
+	b.ReservedLines = lines
+	b.updatePagination()
+}
+
+// renderDetail renders the detail panel for the flight of the selected row
+func (b *Board) renderDetail(row *FlightRow, now time.Time) string {
+	flight := row.Flight
+	if flight == nil {
+		return ""
+	}
+
+	timeFormat := "15:04"
+	zone := b.zoneFor(flight)
+	route := fmt.Sprintf("%-8s %s", "TO", airportOrPlaceholder(flight.GetDestination()))
+	if flight.Direction == models.Arrival {
+		route = fmt.Sprintf("%-8s %s", "FROM", airportOrPlaceholder(flight.GetOrigin()))
+	}
+	if flight.Blocked {
+		return b.Styles.Detail.Render(lipgloss.JoinVertical(lipgloss.Left,
+			fmt.Sprintf("%-8s %s", "FLIGHT", blockedLabel),
+			fmt.Sprintf("%-8s %s", "SCHED", flight.ScheduledTime().In(zone).Format(timeFormat)),
+			"Its operator blocks it from public tracking, so nothing else is shown"))
+	}
+	lines := []string{
+		fmt.Sprintf("%-8s %s", "FLIGHT", flight.FlightNumber),
+		fmt.Sprintf("%-8s %s", "AIRLINE", flight.AirlineName),
+		route,
+		fmt.Sprintf("%-8s %s", "SCHED", flight.ScheduledTime().In(zone).Format(timeFormat)),
+	}
+	if flight.Ident != "" {
+		lines[0] += " (" + flight.Ident + ")"
+	}
+	if est := flight.EstimatedTime(); est != nil {
+		lines = append(lines, fmt.Sprintf("%-8s %s", "EST", est.In(zone).Format(timeFormat)))
+	}
+	if flight.ActualOut != nil {
+		lines = append(lines, fmt.Sprintf("%-8s %s", "OUT", flight.ActualOut.In(zone).Format(timeFormat)))
+	}
+	if flight.ActualOff != nil {
+		lines = append(lines, fmt.Sprintf("%-8s %s", "OFF", flight.ActualOff.In(zone).Format(timeFormat)))
+	}
+	if inbound := b.inboundLine(flight, zone); inbound != "" {
+		lines = append(lines, inbound)
+	}
+	lines = append(lines, b.gateLine(flight, zone))
+	if flight.Direction == models.Arrival {
+		claim := flight.BaggageClaim
+		if claim == "" {
+			claim = "-"
+		}
+		lines = append(lines, fmt.Sprintf("%-8s %s", "BAGGAGE", claim))
+	}
+	lines = append(lines, fmt.Sprintf("%-8s %s", "STATUS", flight.Status))
+	if changed := row.LastChangedAt(); !changed.IsZero() {
+		line := fmt.Sprintf("%-8s %s ago", "UPDATED", formatElapsed(now.Sub(changed)))
+		if row.stale {
+			line += ", estimate stale"
+		}
+		lines = append(lines, line)
+	}
+
+	return b.Styles.Detail.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
 }
 
 // renderAirportHeader renders the airport code header
 func (b *Board) renderAirportHeader() string {
-	label := fmt.Sprintf("DEPARTURES - %s", b.AirportCode)
-	return b.Styles.AirportLabel.Render(label)
+	label := fmt.Sprintf("%s - %s", ViewFor(b.ViewMode).Title(b.Direction), b.airportLabel())
+	if b.filteringDestination() {
+		label += fmt.Sprintf(" → %s (%d)", b.DestinationOnly, b.FlightCount())
+	}
+	if summary := b.airlineSummary(); summary != "" {
+		label += " · " + summary
+	}
+	if window := b.lookahead(); window > 0 {
+		label += " · next " + formatWindow(window)
+	}
+	if recorded := b.Provenance.RecordedAt; !recorded.IsZero() {
+		label += " · recorded " + recorded.In(b.displayZone()).Format("Jan 2 15:04:05")
+	}
+	if b.LargeHeader && b.bigTextFits(label) {
+		big := lipgloss.JoinVertical(lipgloss.Left, RenderBigText(label)...)
+		return b.Styles.AirportLabel.Render(b.withSuffix(big))
+	}
+	return b.Styles.AirportLabel.Render(b.withSuffix(label))
+}
+
+// headerSuffixGap separates the airport title from the header suffix
+const headerSuffixGap = "  "
+
+// withSuffix places the header suffix after the airport title, level with
+// its last line, cut short to the width left beside it
+func (b *Board) withSuffix(title string) string {
+	room := b.availableWidth() - lipgloss.Width(title) - len(headerSuffixGap)
+	if b.HeaderSuffix == "" || room < 2 {
+		return title
+	}
+	suffix := b.Styles.HeaderSuffix.Render(ansi.Truncate(b.HeaderSuffix, room, "…"))
+	return lipgloss.JoinHorizontal(lipgloss.Bottom, title, headerSuffixGap, suffix)
+}
+
+// availableWidth returns the width available for board content: the table
+// width, further limited by the terminal width when it is known
+func (b *Board) availableWidth() int {
+	width := b.tableWidth()
+	if b.TermWidth > 0 {
+		frame := b.frameStyle()
+		termContent := b.TermWidth - frame.GetHorizontalPadding() - frame.GetHorizontalBorderSize()
+		if termContent < width {
+			width = termContent
+		}
+	}
+	return width
+}
+
+// bigTextFits reports whether text can be drawn in the big font: it fits
+// the available width and the terminal shows block characters
+func (b *Board) bigTextFits(text string) bool {
+	return !b.Glyphs.asciiShades && BigTextWidth(text) <= b.availableWidth()
+}
+
+// SetTerminalSize records the terminal dimensions
+// The layout is narrowed to fit, dropping optional columns as needed
+func (b *Board) SetTerminalSize(width, height int) {
+	b.TermWidth = width
+	b.TermHeight = height
+	b.refit()
+	b.updatePagination()
 }
 
 // SetAirport updates the airport code and timezone
@@ -209,6 +1057,392 @@ func (b *Board) SetAirport(airportCode string, airportTZ *time.Location) {
 	b.CurrentPage = 0
 }
 
+// SetDirection switches the board between departures and arrivals
+// Rows are rebuilt on the next update since the columns change
+func (b *Board) SetDirection(direction models.Direction) {
+	if b.Direction == direction {
+		return
+	}
+	b.Direction = direction
+	b.applyLayout()
+}
+
+// SetLayoutMode switches between the wide and compact layouts, keeping the
+// current flights
+func (b *Board) SetLayoutMode(mode LayoutMode) {
+	if b.LayoutMode == mode {
+		return
+	}
+	b.LayoutMode = mode
+	b.applyLayout()
+}
+
+// SetViewMode switches between the timetable, gate and shuttle views,
+// keeping the current flights
+func (b *Board) SetViewMode(mode ViewMode) {
+	if b.ViewMode == mode {
+		return
+	}
+	shuttle := b.ViewMode == ViewShuttle || mode == ViewShuttle
+	b.ViewMode = mode
+	if shuttle {
+		// The shuttle view shows a selection of the flights
+		b.refilter()
+	}
+	b.applyLayout()
+}
+
+// SetDestinationOnly shows only departures to the airport code, or every
+// flight if code is empty. The filter applies to the flights of the last
+// update straight away
+func (b *Board) SetDestinationOnly(code string) {
+	if b.DestinationOnly == code {
+		return
+	}
+	b.DestinationOnly = code
+	b.refilter()
+}
+
+// SetHiddenAirlines leaves the airlines with the given AirlineKey values off
+// the board, showing every other airline including ones that appear later. The
+// filter applies to the flights of the last update straight away; the same
+// airlines as before leave the rows as they are
+func (b *Board) SetHiddenAirlines(keys []string) {
+	if len(keys) == len(b.hiddenAirlines) && !slices.ContainsFunc(keys, func(key string) bool { return !b.hiddenAirlines[key] }) {
+		return
+	}
+	b.hiddenAirlines = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		b.hiddenAirlines[key] = true
+	}
+	b.refilter()
+}
+
+// AirlineHidden reports whether the airline with key is left off the board
+func (b *Board) AirlineHidden(key string) bool {
+	return b.hiddenAirlines[key]
+}
+
+// refilter rebuilds the rows from the flights of the last update after a
+// filter changed
+func (b *Board) refilter() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	flights := append([]models.Flight(nil), b.filtered(b.allFlights)...)
+	rows := make([]*FlightRow, len(flights))
+	for i := range flights {
+		zone := b.zoneFor(&flights[i])
+		flights[i].Remarks = b.renderRemarks(&flights[i], zone, time.Now())
+		rows[i] = NewFlightRow(&flights[i], b.Layout, zone, b.Glyphs, b.Animation)
+	}
+	b.setRows(rows)
+	b.Selected = nil
+	b.CurrentPage = 0
+	b.updatePagination()
+}
+
+// filteringDestination reports whether the board shows only departures to
+// one airport
+func (b *Board) filteringDestination() bool {
+	return b.DestinationOnly != "" && b.Direction == models.Departure
+}
+
+// filtered returns the flights the destination and airline filters keep,
+// and of those only the shuttle view's when it is shown
+func (b *Board) filtered(flights []models.Flight) []models.Flight {
+	if !b.filteringDestination() && len(b.hiddenAirlines) == 0 {
+		return b.shuttled(flights)
+	}
+	var kept []models.Flight
+	for _, flight := range flights {
+		if b.filteringDestination() && strings.TrimSpace(flight.DestinationCode) != b.DestinationOnly {
+			continue
+		}
+		if b.hiddenAirlines[AirlineKey(&flight)] {
+			continue
+		}
+		kept = append(kept, flight)
+	}
+	return b.shuttled(kept)
+}
+
+// AirlineCount is an airline in a board's flight list and its number of flights
+type AirlineCount struct {
+	Key   string // AirlineKey of its flights
+	Name  string
+	Count int
+}
+
+// Airlines returns the airlines of the last update with their flight counts,
+// including hidden ones, busiest first
+func (b *Board) Airlines() []AirlineCount {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	index := make(map[string]int)
+	var airlines []AirlineCount
+	for i := range b.allFlights {
+		key := AirlineKey(&b.allFlights[i])
+		j, ok := index[key]
+		if !ok {
+			j = len(airlines)
+			index[key] = j
+			airlines = append(airlines, AirlineCount{Key: key, Name: b.allFlights[i].AirlineName})
+		}
+		airlines[j].Count++
+	}
+	sort.SliceStable(airlines, func(i, j int) bool {
+		if airlines[i].Count != airlines[j].Count {
+			return airlines[i].Count > airlines[j].Count
+		}
+		return airlines[i].Key < airlines[j].Key
+	})
+	return airlines
+}
+
+// AirlineKey identifies a flight's airline for the airline filter: its IATA
+// code, or its name when the code is unknown
+func AirlineKey(flight *models.Flight) string {
+	if code := strings.TrimSpace(flight.AirlineCode); code != "" {
+		return code
+	}
+	return strings.TrimSpace(flight.AirlineName)
+}
+
+// airlineSummary describes the airline filter for the header, e.g. "UA, DL"
+// or "5/12 AIRLINES", or returns an empty string when no airline is hidden
+func (b *Board) airlineSummary() string {
+	if len(b.hiddenAirlines) == 0 {
+		return ""
+	}
+	var shown []string
+	total := 0
+	seen := make(map[string]bool)
+	for i := range b.allFlights {
+		key := AirlineKey(&b.allFlights[i])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		total++
+		if !b.hiddenAirlines[key] {
+			shown = append(shown, key)
+		}
+	}
+	if len(shown) > 0 && len(shown) <= 3 {
+		return strings.Join(shown, ", ")
+	}
+	return fmt.Sprintf("%d/%d AIRLINES", len(shown), total)
+}
+
+// SetTimeZoneMode switches the timezone flight times are shown in, flipping
+// the time cells and remarks that change
+func (b *Board) SetTimeZoneMode(mode TimeZoneMode) {
+	if b.TimeZone == mode {
+		return
+	}
+	b.TimeZone = mode
+	if !b.fittedLayout().equal(b.Layout) {
+		// The TIME header changed width, so the rows are rebuilt
+		b.applyLayout()
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rows := b.Rows()
+	flights := make([]models.Flight, len(rows))
+	for i, row := range rows {
+		flights[i] = *row.Flight
+	}
+	// A flight's day may change with the zone its date is taken in
+	days := dayOffsets(flights, b.zoneFor)
+	for i, row := range rows {
+		zone := b.zoneFor(&flights[i])
+		flights[i].Remarks = b.renderRemarks(&flights[i], zone, time.Now())
+		row.day = days[i]
+		row.SetZone(zone, &flights[i])
+	}
+	b.Layout = b.fittedLayout()
+	b.setRows(rows)
+}
+
+// fittedLayout returns the layout for the current view, layout mode and
+// direction, squeezed to make room for the watch sidebar if it can be, else
+// narrowed to fit the terminal
+func (b *Board) fittedLayout() Layout {
+	layout := b.baseLayout()
+	if beside, ok := b.sidebarFit(layout); ok {
+		return beside
+	}
+	return layout.fit(b.contentWidth(), b.Styles.Separator)
+}
+
+// baseLayout returns the layout for the current view, layout mode and
+// direction at its full width
+func (b *Board) baseLayout() Layout {
+	layout := ViewFor(b.ViewMode).Layout(b.LayoutMode, b.Direction)
+	layout = layout.withName(ColTime, b.TimeZone.timeColumnName(time.Now()))
+	layout = layout.withMinWidth(ColStatus, b.Glyphs.statusWidth())
+	if b.mergedAirports != nil {
+		layout = layout.withColumnAfter(ColStatus, airportColumn)
+	}
+	return layout.withScroll(b.ScrollColumns)
+}
+
+// displayZone returns the timezone flight times are shown in
+func (b *Board) displayZone() *time.Location {
+	return b.TimeZone.location(b.AirportTZ)
+}
+
+// contentWidth returns the terminal width left for the table inside the
+// padding and frame, or zero if the terminal size is unknown
+func (b *Board) contentWidth() int {
+	if b.TermWidth <= 0 {
+		return 0
+	}
+	width := b.TermWidth - b.Styles.Background.GetHorizontalPadding()
+	if b.Borders != BordersNone {
+		width -= 2 // Left and right border
+	}
+	return max(1, width)
+}
+
+// refit narrows or widens the layout after the terminal or frame changed size
+func (b *Board) refit() {
+	if !b.fittedLayout().equal(b.Layout) {
+		b.applyLayout()
+	}
+}
+
+// applyLayout rebuilds the layout and the rows for the current view, layout
+// mode and direction
+func (b *Board) applyLayout() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	view := ViewFor(b.ViewMode)
+	b.Layout = b.fittedLayout()
+	if width := b.contentWidth(); width > 0 && b.Layout.Width(b.Styles.Separator) > width && !b.warnedTooWide {
+		slog.Warn("board is wider than the terminal, truncating lines",
+			"table_width", b.Layout.Width(b.Styles.Separator), "terminal_width", b.TermWidth)
+		b.warnedTooWide = true
+	}
+	// Rows are rebuilt from copies of their flights, with remarks for the
+	// current timezone
+	current := b.Rows()
+	kept := make([]*FlightRow, 0, len(current))
+	for _, row := range current {
+		if row.Flight != nil && row.Flight.Direction == b.Direction {
+			kept = append(kept, row)
+		}
+	}
+	flights := make([]models.Flight, len(kept))
+	for i, row := range kept {
+		flights[i] = *row.Flight
+	}
+	days := dayOffsets(flights, b.zoneFor)
+	rows := make([]*FlightRow, 0, len(kept))
+	var selected *FlightRow
+	for i, row := range kept {
+		zone := b.zoneFor(&flights[i])
+		flights[i].Remarks = b.renderRemarks(&flights[i], zone, time.Now())
+		rebuilt := NewFlightRow(&flights[i], b.Layout, zone, b.Glyphs, b.Animation)
+		if days[i] > 0 {
+			rebuilt.day = days[i]
+			rebuilt.Update(&flights[i])
+		}
+		if row == b.Selected {
+			selected = rebuilt
+		}
+		rows = append(rows, rebuilt)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return view.Less(rows[i].Flight, rows[j].Flight)
+	})
+	b.setRows(rows)
+	b.Selected = selected
+	b.updatePagination()
+}
+
+// Flip replays the animation of every row flipping in, as when the board is
+// first filled, to draw attention to a change of the whole board
+func (b *Board) Flip() {
+	b.applyLayout()
+}
+
+// SetRemarkTemplates replaces the templates used to generate remarks, or
+// with nil, shows the remarks the flights come with instead
+func (b *Board) SetRemarkTemplates(templates *RemarkTemplates) {
+	b.Remarks = templates
+}
+
+// renderRemarks renders the remarks of flight from the status templates, or
+// those it came with if there are none, or the time it was retimed from,
+// with the NEW badge appended while it lasts, as they stand at now
+func (b *Board) renderRemarks(flight *models.Flight, zone *time.Location, now time.Time) models.Remarks {
+	base := b.givenRemarks[seenKey(flight)]
+	if b.Remarks != nil {
+		base = b.Remarks.Render(flight, zone, now)
+	}
+	remarks := b.retimedRemarks(flight, zone, base)
+	b.expireRemarksAt(taxiTimeChanges(flight, now))
+	until, ok := b.Seen.badgeUntil(seenKey(flight), b.NewBadgeFor)
+	if !ok || !now.Before(until) {
+		return remarks
+	}
+	b.expireRemarksAt(until)
+	if remarks == "" {
+		return newBadge
+	}
+	return remarks + " " + newBadge
+}
+
+// expireRemarksAt notes that a remark shown changes at t, unless t is zero
+func (b *Board) expireRemarksAt(t time.Time) {
+	if !t.IsZero() && (b.remarksExpire.IsZero() || t.Before(b.remarksExpire)) {
+		b.remarksExpire = t
+	}
+}
+
+// RefreshRemarks renders again the remarks that have changed by now, such as
+// NEW badges that have ended and taxi times that have ticked over, reporting
+// whether any row changed and needs animating
+func (b *Board) RefreshRemarks(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// The clock's tick redraws the board, showing the highlight and stale
+	// marks where they moved
+	b.moveNextFlight(now)
+	b.markStale(now)
+	b.markGates(now)
+	if b.remarksExpire.IsZero() || now.Before(b.remarksExpire) {
+		return false
+	}
+
+	b.remarksExpire = time.Time{}
+	rows := b.Rows()
+	changed := false
+	for _, row := range rows {
+		flight := *row.Flight
+		flight.Remarks = b.renderRemarks(&flight, b.zoneFor(&flight), now)
+		if flight.Remarks != row.Flight.Remarks {
+			row.Update(&flight)
+			changed = true
+		}
+	}
+	if changed {
+		b.setRows(rows)
+	}
+	return changed
+}
+
+// SetGlyphs replaces the icons drawn on the board, widening the status
+// column for double-width glyphs
+func (b *Board) SetGlyphs(glyphs *GlyphSet) {
+	b.Glyphs = glyphs
+	b.applyLayout()
+}
+
 // SetFlightsPerPage updates the flights per page setting
 func (b *Board) SetFlightsPerPage(flightsPerPage int) {
 	b.FlightsPerPage = flightsPerPage
@@ -216,27 +1450,154 @@ func (b *Board) SetFlightsPerPage(flightsPerPage int) {
 
 // renderHeader renders the table header
 func (b *Board) renderHeader() string {
-	status := b.Styles.Header.Render("S")
-	flightNum := b.Styles.Header.Render(fmt.Sprintf("%-8s", "FLIGHT"))
-	time := b.Styles.Header.Render(fmt.Sprintf("%-8s", "TIME"))
-	destination := b.Styles.Header.Render(fmt.Sprintf("%-20s", "DESTINATION"))
-	gate := b.Styles.Header.Render(fmt.Sprintf("%-6s", "GATE"))
-	remarks := b.Styles.Header.Render(fmt.Sprintf("%-20s", "REMARKS"))
+	lines := make([][]string, 0, len(b.Layout.Lines))
+	for _, columns := range b.Layout.Lines {
+		cells := make([]string, 0, len(columns))
+		for _, col := range columns {
+			cells = append(cells, b.Styles.Header.Render(PadCell(col.Name, col.Width, col.Align)))
+		}
+		lines = append(lines, cells)
+	}
+	return b.Layout.joinLines(lines, b.Styles)
+}
+
+// Width returns the display width of the flight table, and of the watch
+// sidebar beside it when it is shown
+func (b *Board) Width() int {
+	if b.sidebarShown() {
+		return b.tableWidth() + sidebarGap + sidebarWidth
+	}
+	return b.tableWidth()
+}
+
+// tableWidth returns the display width of the flight table alone
+func (b *Board) tableWidth() int {
+	return b.Layout.Width(b.Styles.Separator)
+}
+
+// renderStatusBar renders the status line below the page info
+func (b *Board) renderStatusBar(now time.Time) string {
+	if !b.PausedUntil.IsZero() {
+		return b.Styles.StatusBar.Render(b.PausedBanner())
+	}
+	source := b.renderProvenance()
+	if b.NextUpdate.IsZero() {
+		return source
+	}
+	remaining := b.NextUpdate.Sub(now).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	status := fmt.Sprintf("Next update in %s", remaining)
+	if b.Toast != "" && now.Before(b.ToastUntil) {
+		status = "Update failed, retrying: " + b.Toast + " | " + status
+	}
+	if b.updated {
+		status += " | Last update: " + b.LastUpdate.String()
+	}
+	if b.FetchedPages > 0 {
+		status += fmt.Sprintf(" | %d API %s", b.FetchedPages, plural(b.FetchedPages, "page", "pages"))
+	}
+	if b.WindowLimit > 0 {
+		status += fmt.Sprintf(" | showing next %s — plan limit", formatWindow(b.WindowLimit))
+	}
+	if b.Skipped != "" {
+		status += " | " + b.Skipped
+	}
+	if b.SlowFetch > 0 {
+		status += fmt.Sprintf(" | API slow: %.1fs", b.SlowFetch.Seconds())
+	}
+	if b.APISpend != "" {
+		status += " | " + b.APISpend
+	}
+	if b.APIBudget != "" {
+		status += " | " + b.APIBudget
+	}
+	if perPage := b.perPage(); perPage < b.configuredPerPage() {
+		status += fmt.Sprintf(" | %d/%d per page", perPage, b.configuredPerPage())
+	}
+	if source != "" {
+		return source + b.Styles.StatusBar.Render(" | "+status)
+	}
+	return b.Styles.StatusBar.Render(status)
+}
+
+// lookahead returns the length of the window the board's flights were
+// fetched for: the lookahead window, unless the source limited it
+func (b *Board) lookahead() time.Duration {
+	if b.WindowLimit > 0 && (b.Lookahead <= 0 || b.WindowLimit < b.Lookahead) {
+		return b.WindowLimit
+	}
+	return b.Lookahead
+}
+
+// formatWindow formats a window length as "24h", or "90m" if it isn't a
+// whole number of hours
+func formatWindow(d time.Duration) string {
+	if d%time.Hour != 0 {
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+	return fmt.Sprintf("%dh", int(d/time.Hour))
+}
+
+// renderProvenance renders the source of the board's flights, as a badge for
+// simulated data so it can't be mistaken for a live feed
+func (b *Board) renderProvenance() string {
+	if b.Provenance.Source == "" {
+		return ""
+	}
+	if b.Provenance.Simulated {
+		return b.Styles.Badge.Render(b.Provenance.Badge())
+	}
+	return b.Styles.StatusBar.Render(b.Provenance.Label(b.AirportTZ))
+}
 
-	return fmt.Sprintf("%s %s %s %s %s %s", status, flightNum, time, destination, gate, remarks)
+// PausedBanner returns the notice shown while updates are paused, e.g.
+// "Updates paused until 07:00"
+func (b *Board) PausedBanner() string {
+	until := b.PausedUntil
+	if b.AirportTZ != nil {
+		until = until.In(b.AirportTZ)
+	}
+	return "Updates paused until " + until.Format("15:04") + " | Press any key to resume"
+}
+
+// plural returns singular when n is 1 and plural otherwise
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// cappedNote returns the notice that the source found more flights than it
+// kept, e.g. "showing first 50 of 78 departures", or "" if none were dropped
+func (b *Board) cappedNote() string {
+	if b.FlightsFound <= b.FlightsKept {
+		return ""
+	}
+	return fmt.Sprintf("showing first %d of %d %s", b.FlightsKept, b.FlightsFound, strings.ToLower(b.Direction.String()))
 }
 
 // renderPageInfo renders pagination information
 func (b *Board) renderPageInfo() string {
+	if b.PageEntry {
+		prompt := fmt.Sprintf("Go to page (1-%d): %s_", b.TotalPages, b.PageInput)
+		return b.Styles.PageInfo.Render(prompt)
+	}
+	capped := b.cappedNote()
 	if b.TotalPages <= 1 {
+		if capped != "" {
+			return b.Styles.PageInfo.Render(capped)
+		}
 		return ""
 	}
 
-	totalFlights := len(b.Flights)
-	flightsPerPage := b.FlightsPerPage
-	if flightsPerPage <= 0 {
-		flightsPerPage = 10 // Default fallback
+	if b.shuttleMode() {
+		return b.Styles.PageInfo.Render(b.shuttlePageInfo())
 	}
+	totalFlights := b.FlightCount()
+	flightsPerPage := b.perPage()
 	start := b.CurrentPage*flightsPerPage + 1
 	end := (b.CurrentPage + 1) * flightsPerPage
 	if end > totalFlights {
@@ -244,5 +1605,17 @@ func (b *Board) renderPageInfo() string {
 	}
 	info := fmt.Sprintf("Page %d/%d (%d-%d of %d)",
 		b.CurrentPage+1, b.TotalPages, start, end, totalFlights)
+	switch {
+	case b.pageOrder != nil && b.CurrentPage == 0 && b.watchPinned:
+		info = fmt.Sprintf("WATCHED FLIGHTS (%d-%d of %d)", start, end, totalFlights)
+	case b.pageOrder != nil && b.CurrentPage == 0:
+		info = fmt.Sprintf("NEXT DEPARTURES (%d-%d of %d)", start, end, totalFlights)
+	}
+	if capped != "" {
+		info += " | " + capped
+	}
+	if time.Now().Before(b.flashUntil) {
+		return b.Styles.PageInfo.Reverse(true).Render(info)
+	}
 	return b.Styles.PageInfo.Render(info)
 }
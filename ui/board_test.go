@@ -0,0 +1,904 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"fids-tui/models"
+)
+
+// testFlights returns n on-time departures to LAX, an hour apart from an hour after now
+func testFlights(n int, now time.Time) []models.Flight {
+	flights := make([]models.Flight, n)
+	for i := range flights {
+		flights[i] = models.Flight{
+			ID:                 fmt.Sprintf("AAL%d-test", 100+i),
+			FlightNumber:       fmt.Sprintf("AA %d", 100+i),
+			AirlineCode:        "AA",
+			DestinationCode:    "LAX",
+			Gate:               "B2",
+			Status:             models.StatusOnTime,
+			ScheduledDeparture: now.Add(time.Duration(i+1) * time.Hour).Truncate(time.Minute),
+		}
+	}
+	return flights
+}
+
+// newTestBoard returns a board at JFK showing perPage flights a page, whose
+// changes take effect on the next tick rather than animating
+func newTestBoard(perPage int) *Board {
+	board := NewBoard("JFK", time.UTC, perPage)
+	board.Animation = AnimationTiming{}
+	return board
+}
+
+// settle ticks the board until nothing on it animates
+func settle(t *testing.T, board *Board) {
+	t.Helper()
+	for range 100 {
+		if !board.IsAnimating() {
+			return
+		}
+		board.Tick()
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("board still animating after 100 ticks")
+}
+
+// renderedLines returns the lines of the rendered board without styling
+func renderedLines(board *Board) []string {
+	return strings.Split(ansi.Strip(board.Render()), "\n")
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares the rendered board view with testdata/name.golden, or
+// rewrites the file with -update. Each line ends in "|" there so the
+// trailing padding is kept and visible
+func checkGolden(t *testing.T, name, view string) {
+	t.Helper()
+	lines := strings.Split(ansi.Strip(view), "\n")
+	got := strings.Join(lines, "|\n") + "|\n"
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("view differs from %s:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// lineOf returns the first rendered line from start containing text, or -1
+func lineOf(lines []string, start int, text string) int {
+	for i := start; i < len(lines); i++ {
+		if strings.Contains(lines[i], text) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestRowAt(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		setup func(b *Board, flights []models.Flight) []models.Flight
+	}{
+		{"plain", nil},
+		{"error banner", func(b *Board, flights []models.Flight) []models.Flight {
+			b.Error = "FlightAware API error (status 503)"
+			return flights
+		}},
+		{"banner wrapped across lines", func(b *Board, flights []models.Flight) []models.Flight {
+			b.Error = "first line\nsecond line"
+			return flights
+		}},
+		{"timeline", func(b *Board, flights []models.Flight) []models.Flight {
+			b.Timeline = true
+			return flights
+		}},
+		{"cancellation strip", func(b *Board, flights []models.Flight) []models.Flight {
+			b.CancelStrip = true
+			flights[4].Status = models.StatusCancelled
+			return flights
+		}},
+		{"banner, timeline and strip", func(b *Board, flights []models.Flight) []models.Flight {
+			b.Error = "FlightAware API error (status 503)"
+			b.Timeline = true
+			b.CancelStrip = true
+			return flights
+		}},
+		{"borders", func(b *Board, flights []models.Flight) []models.Flight {
+			b.SetBorders(BordersFull)
+			b.Error = "FlightAware API error (status 503)"
+			return flights
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newTestBoard(3)
+			flights := testFlights(5, now)
+			if tt.setup != nil {
+				flights = tt.setup(board, flights)
+			}
+			board.UpdateFlights(flights)
+			settle(t, board)
+
+			for page := range 2 {
+				lines := renderedLines(board)
+				// The cancellation strip names flights too, so look under the header
+				header := lineOf(lines, 0, "FLIGHT")
+				for i, row := range board.GetCurrentPageFlights() {
+					if row.Flight == nil {
+						continue
+					}
+					y := lineOf(lines, header, row.Flight.FlightNumber+" ")
+					if y < 0 {
+						t.Fatalf("page %d: %s not rendered", page+1, row.Flight.FlightNumber)
+					}
+					index, ok := board.RowAt(y)
+					if want := page*3 + i; !ok || index != want {
+						t.Errorf("page %d: RowAt(%d) on %s = %d, %v, want %d", page+1, y, row.Flight.FlightNumber, index, ok, want)
+					}
+				}
+
+				if _, ok := board.RowAt(header); ok {
+					t.Errorf("page %d: RowAt on the header line %d found a row", page+1, header)
+				}
+				info := lineOf(lines, header, fmt.Sprintf("Page %d/2", page+1))
+				if !board.IsPageInfoLine(info) {
+					t.Errorf("page %d: IsPageInfoLine(%d) false on %q", page+1, info, lines[info])
+				}
+				if board.IsPageInfoLine(info - 1) {
+					t.Errorf("page %d: IsPageInfoLine(%d) true above the page info", page+1, info-1)
+				}
+				board.NextPage()
+				settle(t, board)
+			}
+		})
+	}
+}
+
+func TestRowAtEmptySlots(t *testing.T) {
+	board := newTestBoard(3)
+	board.UpdateFlights(testFlights(4, time.Now()))
+	settle(t, board)
+	board.NextPage()
+	settle(t, board)
+
+	lines := renderedLines(board)
+	y := lineOf(lines, 0, "AA 103 ")
+	if index, ok := board.RowAt(y); !ok || index != 3 {
+		t.Fatalf("RowAt(%d) = %d, %v, want 3", y, index, ok)
+	}
+	// The slots under the last flight of the page are blank
+	for _, below := range []int{y + 1, y + 2} {
+		if index, ok := board.RowAt(below); ok {
+			t.Errorf("RowAt(%d) = %d on an empty slot", below, index)
+		}
+	}
+	if _, ok := board.RowAt(-1); ok {
+		t.Error("RowAt(-1) found a row")
+	}
+}
+
+func TestUpdateFlightsIdentical(t *testing.T) {
+	board := newTestBoard(5)
+	now := time.Now()
+	summary := board.UpdateFlights(testFlights(4, now))
+	if summary.Added != 4 || !board.IsAnimating() {
+		t.Fatalf("first update: added %d, animating %v, want 4 added and animating", summary.Added, board.IsAnimating())
+	}
+	settle(t, board)
+
+	// The same data again, as a fresh fetch would return it
+	summary = board.UpdateFlights(testFlights(4, now))
+	if summary.Any() || summary.Unchanged != 4 || len(summary.Events) != 0 {
+		t.Errorf("identical update = %+v, want 4 unchanged and nothing else", summary)
+	}
+	if board.IsAnimating() {
+		t.Error("identical update started an animation")
+	}
+
+	flights := testFlights(4, now)
+	flights[2].Gate = "C7"
+	summary = board.UpdateFlights(flights)
+	if summary.Changed != 1 || summary.Unchanged != 3 {
+		t.Errorf("gate change = %+v, want 1 changed and 3 unchanged", summary)
+	}
+	if !board.IsAnimating() {
+		t.Error("changed gate isn't animating")
+	}
+}
+
+// TestEqualTimesKeepOrder is a regression test for rows with the same time
+// swapping places, and flipping, on every refresh
+func TestEqualTimesKeepOrder(t *testing.T) {
+	now := time.Now()
+	departure := now.Add(time.Hour).Truncate(time.Minute)
+	flights := func(order ...int) []models.Flight {
+		all := []models.Flight{
+			{ID: "UAL20", FlightNumber: "UA 20", AirlineCode: "UA", DestinationCode: "SFO", ScheduledDeparture: departure},
+			{ID: "AAL300", FlightNumber: "AA 300", AirlineCode: "AA", DestinationCode: "MIA", ScheduledDeparture: departure},
+			{ID: "AAL31", FlightNumber: "AA 31", AirlineCode: "AA", DestinationCode: "LAX", ScheduledDeparture: departure},
+			{ID: "DAL5", FlightNumber: "DL 5", AirlineCode: "DL", DestinationCode: "ATL", ScheduledDeparture: departure},
+			{ID: "B6100", FlightNumber: "B6 100", AirlineCode: "B6", DestinationCode: "BOS", ScheduledDeparture: departure.Add(-time.Minute)},
+		}
+		picked := make([]models.Flight, len(order))
+		for i, j := range order {
+			picked[i] = all[j]
+		}
+		return picked
+	}
+	boardOrder := func(board *Board) []string {
+		var numbers []string
+		for _, row := range board.Rows() {
+			numbers = append(numbers, row.Flight.FlightNumber)
+		}
+		return numbers
+	}
+	// By time, then airline, then flight number
+	want := []string{"B6 100", "AA 300", "AA 31", "DL 5", "UA 20"}
+
+	board := newTestBoard(5)
+	board.UpdateFlights(flights(0, 1, 2, 3, 4))
+	settle(t, board)
+	if got := boardOrder(board); !slices.Equal(got, want) {
+		t.Fatalf("order %v, want %v", got, want)
+	}
+	for _, order := range [][]int{{4, 3, 2, 1, 0}, {2, 0, 4, 1, 3}, {1, 2, 3, 4, 0}} {
+		summary := board.UpdateFlights(flights(order...))
+		if got := boardOrder(board); !slices.Equal(got, want) {
+			t.Errorf("order %v after refreshing in order %v, want %v", got, order, want)
+		}
+		if summary.Any() || board.IsAnimating() {
+			t.Errorf("refresh in order %v = %+v, animating %v, want nothing changed", order, summary, board.IsAnimating())
+		}
+	}
+}
+
+// goldenNow is the time the flights of the golden tests are scheduled from
+var goldenNow = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func TestPartialLastPageGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(b *Board)
+	}{
+		{"partial_page_80", func(b *Board) { b.SetTerminalSize(80, 30) }},
+		{"partial_page_60", func(b *Board) { b.SetTerminalSize(60, 30) }},
+		{"partial_page_borders", func(b *Board) {
+			b.SetTerminalSize(100, 30)
+			b.SetBorders(BordersFull)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newTestBoard(3)
+			tt.setup(board)
+			board.UpdateFlights(testFlights(4, goldenNow))
+			settle(t, board)
+			board.NextPage()
+			settle(t, board)
+			view := board.Render()
+			checkGolden(t, tt.name, view)
+
+			// Every line, down the empty rows, fills the board's width
+			for i, line := range strings.Split(view, "\n") {
+				if got := ansi.StringWidth(line); got != board.RenderedWidth() {
+					t.Errorf("line %d is %d wide, want %d", i, got, board.RenderedWidth())
+				}
+			}
+		})
+	}
+}
+
+// TestTerminalWidths checks optional columns are dropped in order as the
+// terminal narrows, and that no line is ever wider than the terminal
+func TestTerminalWidths(t *testing.T) {
+	tests := []struct {
+		width     int
+		borders   BorderMode
+		columns   string
+		truncated bool
+	}{
+		{120, BordersNone, "S FLIGHT TIME DESTINATION GATE REMARKS", false},
+		{120, BordersFull, "S FLIGHT TIME DESTINATION GATE REMARKS", false},
+		{80, BordersNone, "S FLIGHT TIME DESTINATION GATE REMARKS", false},
+		// The frame takes the room of the remarks, then of the gate too
+		{80, BordersFull, "S FLIGHT TIME DESTINATION GATE", false},
+		{60, BordersNone, "S FLIGHT TIME DESTINATION GATE", false},
+		{60, BordersFull, "S FLIGHT TIME DESTINATION", false},
+		// Airports are shown by code once every optional column is gone
+		{40, BordersNone, "S FLIGHT TIME DEST", false},
+		// Narrower still, lines are cut with a marker rather than wrapped
+		{20, BordersNone, "S FLIGHT TIME DEST", true},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("%d columns", tt.width)
+		if tt.borders == BordersFull {
+			name += " with borders"
+		}
+		t.Run(name, func(t *testing.T) {
+			board := newTestBoard(3)
+			board.SetBorders(tt.borders)
+			board.SetTerminalSize(tt.width, 24)
+			flights := testFlights(3, time.Now())
+			flights[0].DestinationCity = "Dallas/Fort Worth International"
+			board.UpdateFlights(flights)
+			settle(t, board)
+
+			var names []string
+			for _, col := range board.Layout.Columns() {
+				names = append(names, col.Name)
+			}
+			if got := strings.Join(names, " "); got != tt.columns {
+				t.Errorf("columns %s, want %s", got, tt.columns)
+			}
+			view := board.Render()
+			for i, line := range strings.Split(view, "\n") {
+				if got := ansi.StringWidth(line); got > tt.width {
+					t.Errorf("line %d is %d wide on a %d column terminal: %q", i, got, tt.width, ansi.Strip(line))
+				}
+			}
+			if got := strings.Contains(ansi.Strip(view), "…"); got != tt.truncated {
+				t.Errorf("lines cut with …: %v, want %v", got, tt.truncated)
+			}
+			if board.warnedTooWide != tt.truncated {
+				t.Errorf("warned the board is too wide: %v, want %v", board.warnedTooWide, tt.truncated)
+			}
+		})
+	}
+}
+
+// TestConcurrentUpdateAndRender updates, ticks and renders the board from
+// separate goroutines, as the refresh and the program's view do. Run with
+// -race; each snapshot read must be one whole update, never a mix of two
+func TestConcurrentUpdateAndRender(t *testing.T) {
+	board := newTestBoard(5)
+	now := time.Now()
+	lists := [][]models.Flight{testFlights(3, now), testFlights(8, now)}
+	for i := range lists[1] {
+		lists[1][i].Gate = "C7"
+	}
+	board.UpdateFlights(lists[0])
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := range 200 {
+			board.UpdateFlights(lists[i%2])
+			board.Tick()
+		}
+	}()
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if board.Render() == "" {
+					t.Error("rendered an empty board")
+				}
+				flights := board.Flights()
+				want := "B2"
+				if len(flights) == len(lists[1]) {
+					want = "C7"
+				} else if len(flights) != len(lists[0]) {
+					t.Errorf("snapshot of %d flights, want %d or %d", len(flights), len(lists[0]), len(lists[1]))
+					continue
+				}
+				for _, f := range flights {
+					if f.Gate != want {
+						t.Errorf("snapshot of %d flights has %s at gate %s, want %s", len(flights), f.FlightNumber, f.Gate, want)
+						break
+					}
+				}
+				if n := board.FlightCount(); n != len(lists[0]) && n != len(lists[1]) {
+					t.Errorf("FlightCount = %d, want %d or %d", n, len(lists[0]), len(lists[1]))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestFetchErrorGolden checks a transient fetch error is shown in the status
+// bar without moving the rows, and a persistent one in the banner
+func TestFetchErrorGolden(t *testing.T) {
+	const message = "FlightAware API error (status 503): Service Unavailable"
+	tests := []struct {
+		name  string
+		setup func(b *Board)
+	}{
+		{"fetch_error_toast", func(b *Board) {
+			b.Toast = message
+			b.ToastUntil = time.Now().Add(time.Minute)
+		}},
+		{"fetch_error_banner", func(b *Board) { b.Error = message }},
+	}
+	render := func(setup func(b *Board)) (*Board, string) {
+		board := newTestBoard(3)
+		board.SetTerminalSize(100, 30)
+		board.UpdateFlights(testFlights(3, goldenNow))
+		settle(t, board)
+		// Far enough ahead that rendering can't tick the countdown over
+		board.NextUpdate = time.Now().Add(time.Hour)
+		if setup != nil {
+			setup(board)
+		}
+		return board, board.Render()
+	}
+	_, plain := render(nil)
+	plainLines := strings.Split(ansi.Strip(plain), "\n")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board, view := render(tt.setup)
+			checkGolden(t, tt.name, view)
+
+			lines := strings.Split(ansi.Strip(view), "\n")
+			shift := 0
+			if board.Error != "" {
+				shift = 1 // The banner's line
+			}
+			for i := range board.FlightCount() {
+				number := fmt.Sprintf("AA %d", 100+i)
+				if got, want := lineOf(lines, 0, number), lineOf(plainLines, 0, number); got != want+shift {
+					t.Errorf("%s is on line %d, %d without the error", number, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestWindowLimitAnnotation checks that a window shortened by the source is
+// shown in the header and status bar, and a lookahead within it is not
+func TestWindowLimitAnnotation(t *testing.T) {
+	tests := []struct {
+		name       string
+		lookahead  time.Duration
+		limit      time.Duration
+		wantHeader string
+		wantStatus string // Annotation in the status bar, if any
+	}{
+		{"shortened", 48 * time.Hour, 24 * time.Hour, "next 24h", "showing next 24h — plan limit"},
+		{"shortened to minutes", 2 * time.Hour, 90 * time.Minute, "next 90m", "showing next 90m — plan limit"},
+		{"no end", 0, 24 * time.Hour, "next 24h", "showing next 24h — plan limit"},
+		{"not limited", 6 * time.Hour, 0, "next 6h", ""},
+	}
+	now := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newTestBoard(5)
+			board.Lookahead = tt.lookahead
+			board.WindowLimit = tt.limit
+			board.NextUpdate = now.Add(time.Minute)
+			if header := ansi.Strip(board.renderAirportHeader()); !strings.Contains(header, "· "+tt.wantHeader) {
+				t.Errorf("header %q, want %q", header, tt.wantHeader)
+			}
+			status := ansi.Strip(board.renderStatusBar(now))
+			if tt.wantStatus == "" {
+				if strings.Contains(status, "plan limit") {
+					t.Errorf("status bar %q shows a plan limit", status)
+				}
+			} else if !strings.Contains(status, "| "+tt.wantStatus) {
+				t.Errorf("status bar %q, want %q", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSlowFetchStatus(t *testing.T) {
+	now := time.Now()
+	board := newTestBoard(5)
+	board.NextUpdate = now.Add(time.Minute)
+	if status := ansi.Strip(board.renderStatusBar(now)); strings.Contains(status, "API slow") {
+		t.Errorf("status bar %q flags a slow fetch before any was", status)
+	}
+	board.SlowFetch = 12*time.Second + 380*time.Millisecond
+	if status := ansi.Strip(board.renderStatusBar(now)); !strings.HasSuffix(status, "| API slow: 12.4s") {
+		t.Errorf("status bar %q, want \"API slow: 12.4s\"", status)
+	}
+}
+
+// TestRebuild checks that rebuilding a board for an earlier replay snapshot
+// shows it at once, with no changes marked against the later one
+func TestRebuild(t *testing.T) {
+	now := time.Now()
+	board := newTestBoard(5)
+	board.Animation = AnimationTiming{Duration: time.Hour}
+	board.Rebuild(testFlights(3, now))
+	if board.IsAnimating() {
+		t.Error("first rebuild animates")
+	}
+
+	later := testFlights(3, now)
+	later[0].Gate = "C7"
+	if summary := board.UpdateFlights(later); len(summary.Events) != 1 {
+		t.Fatalf("update made %d events, want the gate change", len(summary.Events))
+	}
+	if !board.IsAnimating() {
+		t.Fatal("gate change doesn't animate")
+	}
+
+	summary := board.Rebuild(testFlights(3, now))
+	if board.IsAnimating() {
+		t.Error("board animates after stepping back")
+	}
+	if len(summary.Events) != 0 || summary.Changed != 0 || summary.Added != 3 {
+		t.Errorf("rebuild summary = %+v, want 3 rows added and no events", summary)
+	}
+	if changes := board.Gates.Changes(seenKey(board.Rows()[0].Flight)); len(changes) != 0 {
+		t.Errorf("gate changes after stepping back = %v, want none", changes)
+	}
+	lines := renderedLines(board)
+	if line := lineOf(lines, 0, "AA 100"); line < 0 || !strings.Contains(lines[line], "B2") || strings.Contains(lines[line], "C7") {
+		t.Errorf("AA 100 isn't shown at gate B2 after stepping back:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestRecordedHeader(t *testing.T) {
+	board := newTestBoard(5)
+	board.UpdateFlights(testFlights(3, time.Now()))
+	if header := ansi.Strip(board.renderAirportHeader()); strings.Contains(header, "recorded") {
+		t.Errorf("live header %q shows a recording time", header)
+	}
+	board.Provenance = Provenance{Source: "Replay", Simulated: true, RecordedAt: time.Date(2026, 3, 2, 15, 4, 5, 0, time.UTC)}
+	if header := ansi.Strip(board.renderAirportHeader()); !strings.Contains(header, "recorded Mar 2 15:04:05") {
+		t.Errorf("replay header %q doesn't show when the snapshot was recorded", header)
+	}
+}
+
+// TestSharedFlightsZones gives two boards in different zones the same
+// flights, checking each shows the times in its own zone and neither changes
+// the flights it was given
+func TestSharedFlightsZones(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip(err)
+	}
+	now := time.Now().UTC()
+	flights := testFlights(3, now)
+	late := flights[1].ScheduledDeparture.Add(40 * time.Minute)
+	flights[1].Status = models.StatusDelayed
+	flights[1].EstimatedDeparture = &late
+	given := slices.Clone(flights)
+
+	utc := newTestBoard(10)
+	local := NewBoard("CCU", kolkata, 10)
+	local.Animation = AnimationTiming{}
+	for _, board := range []*Board{utc, local} {
+		board.UpdateFlights(flights)
+		settle(t, board)
+	}
+
+	for _, board := range []*Board{utc, local} {
+		lines := renderedLines(board)
+		for _, flight := range given {
+			i := lineOf(lines, 0, flight.FlightNumber)
+			if i < 0 {
+				t.Fatalf("%s board doesn't show %s", board.AirportTZ, flight.FlightNumber)
+			}
+			if want := flight.ScheduledDeparture.In(board.AirportTZ).Format("15:04"); !strings.Contains(lines[i], want) {
+				t.Errorf("%s board shows %s without %s: %q", board.AirportTZ, flight.FlightNumber, want, lines[i])
+			}
+		}
+	}
+	if !reflect.DeepEqual(flights, given) || late.Location() != time.UTC || !late.Equal(given[1].ScheduledDeparture.Add(40*time.Minute)) {
+		t.Errorf("boards changed the flights they were given: %+v", flights)
+	}
+	for _, flight := range flights {
+		if flight.ScheduledDeparture.Location() != time.UTC {
+			t.Errorf("%s departs in %s, want UTC as given", flight.FlightNumber, flight.ScheduledDeparture.Location())
+		}
+	}
+}
+
+// TestCappedNote checks that a board kept fewer flights than its source
+// found says so on the page info line, its own on a single page
+func TestCappedNote(t *testing.T) {
+	now := time.Now()
+	for _, tt := range []struct {
+		name    string
+		perPage int
+		found   int
+		want    string
+	}{
+		{"several pages", 10, 30, "Page 1/2 (1-10 of 20) | showing first 20 of 30 departures"},
+		{"one page", 25, 30, "showing first 20 of 30 departures"},
+		{"nothing dropped", 10, 20, "Page 1/2 (1-10 of 20)"},
+	} {
+		board := newTestBoard(tt.perPage)
+		board.UpdateFlights(testFlights(20, now))
+		board.FlightsKept, board.FlightsFound = 20, tt.found
+		settle(t, board)
+		lines := renderedLines(board)
+		i := lineOf(lines, 0, "of 20")
+		if tt.perPage > 20 {
+			i = lineOf(lines, 0, "showing first")
+		}
+		if i < 0 {
+			t.Errorf("%s: no page info:\n%s", tt.name, strings.Join(lines, "\n"))
+		} else if got := strings.TrimSpace(lines[i]); got != tt.want {
+			t.Errorf("%s: page info %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestTerminalHeights renders a board of more flights than fit on terminals
+// down to 10 rows, checking it never overflows the lines left to it and
+// always keeps the status bar at the bottom, with only the frame below it
+func TestTerminalHeights(t *testing.T) {
+	for _, borders := range []BorderMode{BordersNone, BordersFull} {
+		for _, height := range []int{40, 24, 18, 14, 12, 11, 10} {
+			board := newTestBoard(20)
+			board.SetBorders(borders)
+			board.SetTerminalSize(100, height)
+			board.SetReservedLines(1) // The caller's help text
+			board.UpdateFlights(testFlights(30, time.Now()))
+			board.NextUpdate = time.Now().Add(5 * time.Minute)
+			settle(t, board)
+
+			lines := renderedLines(board)
+			if len(lines) > height-1 {
+				t.Errorf("%d rows, borders %v: board is %d lines, want at most %d:\n%s", height, borders, len(lines), height-1, strings.Join(lines, "\n"))
+			}
+			status := lineOf(lines, 0, "Next update in")
+			if status < 0 {
+				t.Errorf("%d rows, borders %v: no status bar", height, borders)
+				continue
+			}
+			for _, line := range lines[status+1:] {
+				if strings.Trim(line, " │╰╯─") != "" {
+					t.Errorf("%d rows, borders %v: %q below the status bar", height, borders, line)
+				}
+			}
+			if rows := len(board.GetCurrentPageFlights()); rows < 1 || lineOf(lines, 0, "AA 100") < 0 {
+				t.Errorf("%d rows, borders %v: %d flights shown", height, borders, rows)
+			}
+		}
+	}
+}
+
+// TestPinImminent pages a board pinning imminent flights: the first page
+// holds the next flights to depart by their estimates, leaving out those
+// cancelled or away from the gate, and the later pages the rest in board
+// order, each flight on one page only
+func TestPinImminent(t *testing.T) {
+	now := time.Now()
+	flights := testFlights(8, now)
+	late := flights[0].ScheduledDeparture.Add(3*time.Hour + 30*time.Minute)
+	flights[0].Status = models.StatusDelayed // Leaving between AA 103 and AA 104
+	flights[0].EstimatedDeparture = &late
+	flights[1].Status = models.StatusCancelled
+	flights[2].Status = models.StatusTaxiingLeftGate
+
+	pages := func(board *Board) [][]string {
+		var pages [][]string
+		for page := range board.TotalPages {
+			board.CurrentPage = page
+			var numbers []string
+			for _, row := range board.GetCurrentPageFlights() {
+				if row.Flight != nil {
+					numbers = append(numbers, row.Flight.FlightNumber)
+				}
+			}
+			pages = append(pages, numbers)
+		}
+		board.CurrentPage = 0
+		return pages
+	}
+	for _, tt := range []struct {
+		name string
+		pin  bool
+		want [][]string
+	}{
+		{"pinned", true, [][]string{{"AA 103", "AA 100", "AA 104"}, {"AA 101", "AA 102", "AA 105"}, {"AA 106", "AA 107"}}},
+		{"board order", false, [][]string{{"AA 100", "AA 101", "AA 102"}, {"AA 103", "AA 104", "AA 105"}, {"AA 106", "AA 107"}}},
+	} {
+		board := newTestBoard(3)
+		board.PinImminent = tt.pin
+		board.UpdateFlights(flights)
+		settle(t, board)
+		if got := pages(board); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: pages %q, want %q", tt.name, got, tt.want)
+		}
+
+		// Rows are rendered, and map back to their flights, in page order
+		lines := renderedLines(board)
+		first := tt.want[0][0]
+		if row := lineOf(lines, 0, first); row < 0 || lineOf(lines, 0, tt.want[0][1]) < row {
+			t.Errorf("%s: first page doesn't start with %s:\n%s", tt.name, first, strings.Join(lines, "\n"))
+		}
+		if index, ok := board.RowAt(board.rowsOffset()); !ok || board.Rows()[index].Flight.FlightNumber != first {
+			t.Errorf("%s: first row maps to row %d, want %s", tt.name, index, first)
+		}
+	}
+
+	// A board whose flights fit one page keeps its order
+	board := newTestBoard(10)
+	board.PinImminent = true
+	board.UpdateFlights(flights)
+	settle(t, board)
+	if got := pages(board); len(got) != 1 || got[0][0] != "AA 100" {
+		t.Errorf("one page of flights %q, want them in board order", got)
+	}
+}
+
+// TestKeepPage refreshes a board turned to its second page with earlier
+// flights added, checking that KeepPage follows the first flight on the page
+// to wherever it moved, and that the selection stays on its flight
+func TestKeepPage(t *testing.T) {
+	now := time.Now()
+	flights := testFlights(9, now)
+	earlier := testFlights(3, now)
+	for i := range earlier {
+		earlier[i].FlightNumber = fmt.Sprintf("DL %d", 200+i)
+		earlier[i].ID = fmt.Sprintf("DAL%d-test", 200+i)
+		earlier[i].ScheduledDeparture = now.Add(time.Duration(10+i) * time.Minute).Truncate(time.Minute)
+	}
+	refreshed := append(earlier, flights...)
+	refreshed[7].Gate = "C7" // The selected flight changes gate
+
+	for _, tt := range []struct {
+		keep bool
+		page int
+		want string // First flight on the page after the refresh
+	}{
+		{true, 2, "AA 103"},
+		{false, 1, "AA 100"},
+	} {
+		board := newTestBoard(3)
+		board.KeepPage = tt.keep
+		board.UpdateFlights(flights)
+		settle(t, board)
+		board.CurrentPage = 1
+		board.Select(4)
+		selected := board.Selected
+
+		board.UpdateFlights(refreshed)
+		settle(t, board)
+		if first := board.GetCurrentPageFlights()[0].Flight.FlightNumber; board.CurrentPage != tt.page || first != tt.want {
+			t.Errorf("KeepPage %v: page %d starting with %s, want page %d starting with %s", tt.keep, board.CurrentPage, first, tt.page, tt.want)
+		}
+		if board.Selected != selected || board.Selected.Flight.Gate != "C7" {
+			t.Errorf("KeepPage %v: selection moved off AA 104 through a refresh", tt.keep)
+		}
+		if lines := renderedLines(board); lineOf(lines, 0, tt.want) < 0 {
+			t.Errorf("KeepPage %v: %s isn't shown:\n%s", tt.keep, tt.want, strings.Join(lines, "\n"))
+		}
+	}
+
+	// A page whose first flight has gone is left alone, as is the selection
+	// of a flight that has gone dropped
+	board := newTestBoard(3)
+	board.KeepPage = true
+	board.UpdateFlights(flights)
+	settle(t, board)
+	board.CurrentPage = 1
+	board.Select(3)
+	board.UpdateFlights(append(flights[:3:3], flights[4:]...))
+	if board.CurrentPage != 1 || board.Selected != nil {
+		t.Errorf("first flight on the page gone: page %d, selected %v; want page 1 and no selection", board.CurrentPage, board.Selected != nil)
+	}
+}
+
+// TestShuttleGolden renders both pages of a shuttle board of three
+// destinations, two flights deep: the next flights still to leave under
+// each one, blank slots under a destination with one, and a note under one
+// with none
+func TestShuttleGolden(t *testing.T) {
+	flights := testFlights(7, goldenNow)
+	places := []string{"BOS", "BOS", "DCA", "BOS", "LAX", "BOS", "LAX"}
+	cities := map[string]string{"BOS": "Boston", "DCA": "Washington", "LAX": "Los Angeles"}
+	for i := range flights {
+		flights[i].DestinationCode = places[i]
+		flights[i].DestinationCity = cities[places[i]]
+	}
+	flights[0].Status = models.StatusTaxiingLeftGate // Gone from its section
+	board := newTestBoard(8)
+	board.SetTerminalSize(80, 30)
+	board.SetViewMode(ViewShuttle)
+	board.SetShuttles([]string{"BOS", "DCA", "ORD"}, 2)
+	board.UpdateFlights(flights)
+	settle(t, board)
+
+	if board.TotalPages != 2 {
+		t.Fatalf("%d pages, want 2 of two destinations", board.TotalPages)
+	}
+	checkGolden(t, "shuttle_page_1", board.Render())
+	board.NextPage()
+	settle(t, board)
+	checkGolden(t, "shuttle_page_2", board.Render())
+}
+
+// TestDestinationOnly filters a board to BOS, checking only its departures to
+// BOS are shown and counted in the header, from the first page, through the
+// next update, and that arrivals boards and no code show every flight
+func TestDestinationOnly(t *testing.T) {
+	flights := testFlights(6, time.Now())
+	for i, code := range []string{"LAX", "BOS", " BOS ", "SFO", "LAX", "BOS"} {
+		flights[i].DestinationCode = code
+	}
+	board := newTestBoard(2)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	board.NextPage()
+	board.Select(0)
+
+	board.SetDestinationOnly("BOS")
+	settle(t, board)
+	shown := func() []string {
+		var numbers []string
+		for _, row := range board.Rows() {
+			numbers = append(numbers, row.Flight.FlightNumber)
+		}
+		return numbers
+	}
+	want := []string{"AA 101", "AA 102", "AA 105"}
+	if got := shown(); !slices.Equal(got, want) {
+		t.Errorf("filtered to BOS: %q, want %q", got, want)
+	}
+	if board.CurrentPage != 0 || board.Selected != nil {
+		t.Errorf("filtered board on page %d with a selection, want the first page", board.CurrentPage+1)
+	}
+	if lines := renderedLines(board); lineOf(lines, 0, "→ BOS (3)") < 0 {
+		t.Errorf("header doesn't give the filter and its count:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// The filter holds through updates, and applies to departures only
+	flights[3].DestinationCode = "BOS"
+	board.UpdateFlights(flights)
+	settle(t, board)
+	if got := shown(); !slices.Equal(got, []string{"AA 101", "AA 102", "AA 103", "AA 105"}) {
+		t.Errorf("filtered to BOS after an update: %q", got)
+	}
+	arrivals := append([]models.Flight(nil), flights...)
+	for i := range arrivals {
+		arrivals[i].Direction = models.Arrival
+		arrivals[i].ScheduledArrival = arrivals[i].ScheduledDeparture
+	}
+	board.SetDirection(models.Arrival)
+	board.UpdateFlights(arrivals)
+	settle(t, board)
+	if got := board.FlightCount(); got != len(arrivals) {
+		t.Errorf("arrivals board filtered to BOS shows %d flights, want all %d", got, len(arrivals))
+	}
+	if lines := renderedLines(board); lineOf(lines, 0, "→ BOS") >= 0 {
+		t.Error("arrivals board header gives the destination filter")
+	}
+
+	board.SetDirection(models.Departure)
+	board.UpdateFlights(flights)
+	board.SetDestinationOnly("")
+	if got := board.FlightCount(); got != len(flights) {
+		t.Errorf("unfiltered board shows %d flights, want all %d", got, len(flights))
+	}
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+
+	"fids-tui/models"
+)
+
+const (
+	// cancelledLabel starts the cancellations strip
+	cancelledLabel = "CANCELLED: "
+	// cancelledGap separates the flights of the cancellations strip
+	cancelledGap = " • "
+)
+
+// cancellationsText returns the text of the cancellations strip for the
+// flights of the board, e.g. "CANCELLED: DL456 ATL 14:35 • UA789 ORD
+// 16:10", or "" if none of them is cancelled
+func (b *Board) cancellationsText(flights []models.Flight) string {
+	var items []string
+	for i := range flights {
+		if flights[i].Status == models.StatusCancelled {
+			items = append(items, b.flightLabel(&flights[i]))
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return cancelledLabel + strings.Join(items, cancelledGap)
+}
+
+// updateCancellations sets the cancellations strip from the flights on the
+// board, scrolling it from its start again only if its text changed
+func (b *Board) updateCancellations() {
+	if !b.CancelStrip {
+		b.cancellations = nil
+		return
+	}
+	text := b.cancellationsText(b.Flights())
+	if b.cancellations == nil || b.cancellations.Text != text {
+		b.cancellations = NewMarquee(text, true)
+	}
+}
+
+// renderCancellations renders the cancellations strip, scrolling when the
+// flights don't fit the width of the table, or false if it is off. Without
+// cancellations it is a blank line, so the rows don't move when one comes
+func (b *Board) renderCancellations() (string, bool) {
+	if !b.CancelStrip {
+		return "", false
+	}
+	width := b.tableWidth()
+	if b.cancellations == nil || b.cancellations.Text == "" {
+		return strings.Repeat(" ", width), true
+	}
+	return b.Styles.Error.Render(PadCell(b.cancellations.Render(width), width, AlignLeft)), true
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"fids-tui/models"
+)
+
+// TestCancellationStrip renders the strip as flights are cancelled: a blank
+// line keeping the rows in place while none are, the cancelled flights in
+// the error color once one is, and scrolling a cell a step, from its start
+// again only when the cancellations change, once they don't fit the table
+func TestCancellationStrip(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	flights := testFlights(8, goldenNow)
+	board := newTestBoard(10)
+	board.CancelStrip = true
+	board.SetTerminalSize(80, 40)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	strip := func() string {
+		lines := renderedLines(board)
+		return lines[lineOf(lines, 0, "FLIGHT")-1]
+	}
+	if got := strip(); strings.TrimSpace(got) != "" {
+		t.Errorf("strip without cancellations %q, want a blank line", got)
+	}
+	first := lineOf(renderedLines(board), 0, "AA 100")
+
+	flights[1].Status = models.StatusCancelled
+	board.UpdateFlights(flights)
+	settle(t, board)
+	want := "CANCELLED: AA 101 LAX 14:00"
+	if got := strip(); !strings.Contains(got, want) {
+		t.Errorf("strip %q, want %q", got, want)
+	}
+	if !strings.Contains(board.Render(), board.Styles.Error.Render(PadCell(want, board.tableWidth(), AlignLeft))) {
+		t.Error("strip not in the error color")
+	}
+	if got := lineOf(renderedLines(board), 0, "AA 100"); got != first {
+		t.Errorf("first row moved from line %d to %d with a cancellation", first, got)
+	}
+
+	// Too many to fit scroll once the start has been held
+	for _, i := range []int{2, 3, 5} {
+		flights[i].Status = models.StatusCancelled
+	}
+	board.UpdateFlights(flights)
+	settle(t, board)
+	start := "CANCELLED: AA 101 LAX 14:00 • AA 102 LAX 15:00 • AA 103 LAX 16:00 •"
+	if !board.Scrolling() || !strings.Contains(strip(), start) {
+		t.Fatalf("strip %q of cancellations too long for the table, scrolling %v", strip(), board.Scrolling())
+	}
+	// Settling has already held it for some steps
+	for range marqueeHold - board.cancellations.held + 3 {
+		board.Tick()
+	}
+	if got := strip(); !strings.Contains(got, start[3:]) || strings.Contains(got, "CANCELLED") {
+		t.Errorf("strip after its hold %q, want it three cells along", got)
+	}
+
+	// The same cancellations keep their place; another starts them again
+	board.UpdateFlights(flights)
+	if got := strip(); !strings.Contains(got, start[3:]) || strings.Contains(got, "CANCELLED") {
+		t.Errorf("strip scrolled back to %q with the cancellations unchanged", got)
+	}
+	flights[6].Status = models.StatusCancelled
+	board.UpdateFlights(flights)
+	if got := strip(); !strings.Contains(got, start) {
+		t.Errorf("strip %q with a new cancellation, want it from its start", got)
+	}
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"time"
+
+	"fids-tui/models"
+)
+
+// trackChanges records when the data of each flight last changed, comparing
+// the flights of an update with those of the last one. Flights not on the
+// last update changed at now, as did those whose gate, status, estimate or
+// baggage claim differ; the rest keep the time they had, however many
+// identical refreshes come in. Remarks are left out, because they also
+// change by themselves, like a NEW badge ending
+func (b *Board) trackChanges(old, flights []models.Flight, now time.Time) {
+	changedAt := make(map[string]time.Time, len(flights))
+	diff := models.DiffFlights(old, flights)
+	for _, change := range diff.Matched {
+		at, ok := b.changedAt[seenKey(&old[change.Old])]
+		if !ok || dataChanged(change) {
+			at = now
+		}
+		changedAt[seenKey(&flights[change.New])] = at
+	}
+	for _, i := range diff.Added {
+		changedAt[seenKey(&flights[i])] = now
+	}
+	b.changedAt = changedAt
+}
+
+// dataChanged reports whether a field the source reports changed
+func dataChanged(change models.FlightChange) bool {
+	for _, field := range change.Fields {
+		if field.Field != models.FieldRemarks {
+			return true
+		}
+	}
+	return false
+}
+
+// staleEstimate reports whether row shows a delayed flight whose estimate
+// looks stuck: it is past its scheduled time and nothing about it has
+// changed for longer than StaleAfter
+func (b *Board) staleEstimate(row *FlightRow, now time.Time) bool {
+	flight := row.Flight
+	if b.StaleAfter <= 0 || flight == nil || flight.Status != models.StatusDelayed || flight.EstimatedTime() == nil {
+		return false
+	}
+	return flight.ScheduledTime().Before(now) && now.Sub(row.changedAt) > b.StaleAfter
+}
+
+// markStale flags the rows whose estimate looks stuck at now
+func (b *Board) markStale(now time.Time) {
+	for _, row := range b.Rows() {
+		row.stale = b.staleEstimate(row, now)
+	}
+}
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// TestTrackChanges updates the change times of flights from one update to
+// the next: new flights and those whose data changed change then, the rest
+// keep their time, whatever their remarks do
+func TestTrackChanges(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	flights := testFlights(5, start)
+	board := newTestBoard(10)
+	board.trackChanges(nil, flights, start)
+
+	later := append([]models.Flight(nil), flights[:4]...) // AA 104 leaves the board
+	later[0].Remarks = "NEW"
+	later[1].Gate = "C7"
+	later[2].Status = models.StatusDelayed
+	estimate := later[3].ScheduledDeparture.Add(10 * time.Minute)
+	later[3].EstimatedDeparture = &estimate
+	later = append(later, testFlights(6, start)[5])
+	then := start.Add(time.Hour)
+	board.trackChanges(flights, later, then)
+
+	want := []time.Time{start, then, then, then, then}
+	for i := range later {
+		if got := board.changedAt[seenKey(&later[i])]; !got.Equal(want[i]) {
+			t.Errorf("%s changed at %s, want %s", later[i].FlightNumber, got.Format("15:04"), want[i].Format("15:04"))
+		}
+	}
+	if _, ok := board.changedAt[seenKey(&flights[4])]; ok {
+		t.Error("flight gone from the board still has a change time")
+	}
+
+	// Identical refreshes keep the time
+	board.trackChanges(later, later, then.Add(time.Hour))
+	if got := board.changedAt[seenKey(&later[0])]; !got.Equal(start) {
+		t.Errorf("unchanged flight changed at %s after a refresh, want %s", got.Format("15:04"), start.Format("15:04"))
+	}
+}
+
+func TestStaleEstimate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	delayed := func(scheduled time.Duration) *models.Flight {
+		flight := testFlights(1, now)[0]
+		flight.ScheduledDeparture = now.Add(scheduled)
+		estimate := now.Add(20 * time.Minute)
+		flight.EstimatedDeparture = &estimate
+		flight.Status = models.StatusDelayed
+		return &flight
+	}
+	noEstimate := delayed(-time.Hour)
+	noEstimate.EstimatedDeparture = nil
+	onTime := delayed(-time.Hour)
+	onTime.Status = models.StatusOnTime
+	for _, tt := range []struct {
+		name       string
+		flight     *models.Flight
+		unchanged  time.Duration
+		staleAfter time.Duration
+		want       bool
+	}{
+		{"stuck", delayed(-time.Hour), 31 * time.Minute, 30 * time.Minute, true},
+		{"changed in time", delayed(-time.Hour), 30 * time.Minute, 30 * time.Minute, false},
+		{"not yet due", delayed(time.Minute), time.Hour, 30 * time.Minute, false},
+		{"no estimate", noEstimate, time.Hour, 30 * time.Minute, false},
+		{"on time", onTime, time.Hour, 30 * time.Minute, false},
+		{"never stale", delayed(-time.Hour), time.Hour, 0, false},
+	} {
+		board := newTestBoard(10)
+		board.StaleAfter = tt.staleAfter
+		row := &FlightRow{Flight: tt.flight, changedAt: now.Add(-tt.unchanged)}
+		if got := board.staleEstimate(row, now); got != tt.want {
+			t.Errorf("%s: staleEstimate = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestStaleRow checks that a delayed flight unchanged for longer than
+// StaleAfter is marked stale on the board through identical refreshes, and
+// that a change to it clears the mark
+func TestStaleRow(t *testing.T) {
+	now := time.Now()
+	flights := testFlights(2, now.Add(-2*time.Hour))
+	estimate := now.Add(20 * time.Minute)
+	flights[0].Status = models.StatusDelayed
+	flights[0].EstimatedDeparture = &estimate
+	board := newTestBoard(10)
+	board.StaleAfter = 30 * time.Minute
+	board.UpdateFlights(flights)
+	settle(t, board)
+	stale := func() bool {
+		lines := renderedLines(board)
+		return strings.Contains(lines[lineOf(lines, 0, "AA 100")], board.Glyphs.Stale())
+	}
+	if stale() {
+		t.Fatal("flight just updated marked stale")
+	}
+
+	board.changedAt[seenKey(&flights[0])] = now.Add(-time.Hour)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	if !stale() {
+		t.Error("delayed flight unchanged for an hour not marked stale")
+	}
+	later := estimate.Add(15 * time.Minute)
+	flights[0].EstimatedDeparture = &later
+	board.UpdateFlights(flights)
+	settle(t, board)
+	if stale() {
+		t.Error("flight still marked stale once its estimate changed")
+	}
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChecklistItem is one entry of a Checklist
+type ChecklistItem struct {
+	Key     string // Identifies the item to the caller
+	Label   string // Text shown after the checkbox
+	Checked bool
+}
+
+// Checklist is a scrollable list of items that can be checked and unchecked
+// from the keyboard
+type Checklist struct {
+	Title  string
+	Items  []ChecklistItem
+	cursor int // Index of the highlighted item
+	offset int // Index of the first item shown
+}
+
+// NewChecklist creates a checklist with the cursor on its first item
+func NewChecklist(title string, items []ChecklistItem) *Checklist {
+	return &Checklist{Title: title, Items: items}
+}
+
+// HandleKey moves the cursor or toggles the highlighted item, reporting
+// whether the key was one the list handles
+func (c *Checklist) HandleKey(key string) bool {
+	switch key {
+	case "up", "k":
+		c.cursor = max(0, c.cursor-1)
+	case "down", "j":
+		c.cursor = min(len(c.Items)-1, c.cursor+1)
+	case "home":
+		c.cursor = 0
+	case "end":
+		c.cursor = len(c.Items) - 1
+	case " ":
+		if c.cursor < len(c.Items) {
+			c.Items[c.cursor].Checked = !c.Items[c.cursor].Checked
+		}
+	default:
+		return false
+	}
+	c.cursor = max(0, c.cursor)
+	return true
+}
+
+// Unchecked returns the keys of the items not checked, in list order
+func (c *Checklist) Unchecked() []string {
+	var keys []string
+	for _, item := range c.Items {
+		if !item.Checked {
+			keys = append(keys, item.Key)
+		}
+	}
+	return keys
+}
+
+// Render draws the title, at most height items scrolled to keep the cursor
+// in view, and a footer with the given key help
+func (c *Checklist) Render(height int, help string, styles *SplitFlapStyles) string {
+	height = max(1, height)
+	if c.cursor < c.offset {
+		c.offset = c.cursor
+	}
+	if c.cursor >= c.offset+height {
+		c.offset = c.cursor - height + 1
+	}
+
+	lines := []string{styles.AirportLabel.Render(c.Title)}
+	if len(c.Items) == 0 {
+		lines = append(lines, styles.Text.Render("Nothing to choose from yet"))
+	}
+	for i := c.offset; i < len(c.Items) && i < c.offset+height; i++ {
+		item := c.Items[i]
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("  %s %s", box, item.Label)
+		if i == c.cursor {
+			lines = append(lines, styles.Selected.Render("> "+line[2:]))
+		} else {
+			lines = append(lines, styles.Text.Render(line))
+		}
+	}
+
+	lines = append(lines, styles.PageInfo.Render(help))
+	return styles.Background.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// checklistLines renders list height items high, returning its lines without
+// styling or padding
+func checklistLines(list *Checklist, height int) []string {
+	view := list.Render(height, "space toggle · esc done", NewSplitFlapStyles())
+	var lines []string
+	for _, line := range strings.Split(ansi.Strip(view), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// TestChecklistRender moves through a list longer than its height, checking
+// it scrolls to keep the cursor in view and shows the checked items
+func TestChecklistRender(t *testing.T) {
+	items := []ChecklistItem{
+		{Key: "AA", Label: "AA American Airlines", Checked: true},
+		{Key: "B6", Label: "B6 JetBlue Airways", Checked: true},
+		{Key: "DL", Label: "DL Delta Air Lines", Checked: true},
+		{Key: "UA", Label: "UA United Airlines", Checked: true},
+		{Key: "WN", Label: "WN Southwest Airlines", Checked: true},
+	}
+	list := NewChecklist("AIRLINES", items)
+	for _, tt := range []struct {
+		keys []string
+		want []string
+	}{
+		{nil, []string{"AIRLINES", "> [x] AA American Airlines", "[x] B6 JetBlue Airways", "[x] DL Delta Air Lines", "space toggle · esc done"}},
+		{[]string{"down", "j", " "}, []string{"AIRLINES", "[x] AA American Airlines", "[x] B6 JetBlue Airways", "> [ ] DL Delta Air Lines", "space toggle · esc done"}},
+		{[]string{"down"}, []string{"AIRLINES", "[x] B6 JetBlue Airways", "[ ] DL Delta Air Lines", "> [x] UA United Airlines", "space toggle · esc done"}},
+		{[]string{"end", "down", " "}, []string{"AIRLINES", "[ ] DL Delta Air Lines", "[x] UA United Airlines", "> [ ] WN Southwest Airlines", "space toggle · esc done"}},
+		{[]string{"up", "k"}, []string{"AIRLINES", "> [ ] DL Delta Air Lines", "[x] UA United Airlines", "[ ] WN Southwest Airlines", "space toggle · esc done"}},
+		{[]string{"home", "up"}, []string{"AIRLINES", "> [x] AA American Airlines", "[x] B6 JetBlue Airways", "[ ] DL Delta Air Lines", "space toggle · esc done"}},
+	} {
+		for _, key := range tt.keys {
+			if !list.HandleKey(key) {
+				t.Errorf("key %q not handled", key)
+			}
+		}
+		if got := checklistLines(list, 3); !slices.Equal(got, tt.want) {
+			t.Errorf("after %q:\n%s\nwant:\n%s", tt.keys, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+	if list.HandleKey("x") {
+		t.Error("key x handled")
+	}
+	if got := list.Unchecked(); !slices.Equal(got, []string{"DL", "WN"}) {
+		t.Errorf("Unchecked = %q, want DL and WN", got)
+	}
+
+	// A list with room for every item, and one with none
+	if got := checklistLines(list, 10); len(got) != len(items)+2 {
+		t.Errorf("tall list has %d lines, want every item:\n%s", len(got), strings.Join(got, "\n"))
+	}
+	empty := NewChecklist("AIRLINES", nil)
+	empty.HandleKey("down")
+	empty.HandleKey(" ")
+	want := []string{"AIRLINES", "Nothing to choose from yet", "space toggle · esc done"}
+	if got := checklistLines(empty, 3); !slices.Equal(got, want) {
+		t.Errorf("empty list:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
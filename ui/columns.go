@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"strings"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ColumnID identifies a board column
+type ColumnID int
+
+const (
+	ColStatus ColumnID = iota
+	ColFlight
+	ColTime
+	ColDestination
+	ColGate
+	ColRemarks
+	ColOrigin
+	ColDestinationCode // Destination airport code only, for narrow terminals
+	ColOriginCode      // Origin airport code only, for narrow terminals
+	ColBaggage         // Baggage claim, on arrivals boards
+	ColAirport         // Airport the flight is listed at, on boards merging several airports
+)
+
+// Alignment is the horizontal alignment of a column's content
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+)
+
+// Column describes a single column of the board table
+type Column struct {
+	ID     ColumnID
+	Name   string // Header label
+	Width  int    // Display width in terminal cells
+	Align  Alignment
+	Scroll bool // Scroll text too long for the column instead of cutting it short
+}
+
+// columnSeparator is placed between adjacent columns
+const columnSeparator = " "
+
+// boxColumnSeparator separates columns when vertical separators are enabled
+const boxColumnSeparator = " │ "
+
+// DefaultColumns is the standard departures table layout
+var DefaultColumns = []Column{
+	{ID: ColStatus, Name: "S", Width: 1},
+	{ID: ColFlight, Name: "FLIGHT", Width: 8}, // Full flight number with airline code
+	{ID: ColTime, Name: "TIME", Width: 8},     // HH:MM format
+	{ID: ColDestination, Name: "DESTINATION", Width: 20},
+	{ID: ColGate, Name: "GATE", Width: 6},
+	{ID: ColRemarks, Name: "REMARKS", Width: 20},
+}
+
+// ArrivalColumns is the standard arrivals table layout
+var ArrivalColumns = []Column{
+	{ID: ColStatus, Name: "S", Width: 1},
+	{ID: ColFlight, Name: "FLIGHT", Width: 8},
+	{ID: ColTime, Name: "TIME", Width: 8},
+	{ID: ColOrigin, Name: "ORIGIN", Width: 20},
+	{ID: ColGate, Name: "GATE", Width: 6},
+	{ID: ColBaggage, Name: "BAG", Width: 4},
+	{ID: ColRemarks, Name: "REMARKS", Width: 20},
+}
+
+// ColumnsFor returns the standard table layout for a departures or arrivals board
+func ColumnsFor(direction models.Direction) []Column {
+	if direction == models.Arrival {
+		return ArrivalColumns
+	}
+	return DefaultColumns
+}
+
+// TableWidth returns the display width of a table with the given columns and separator
+func TableWidth(columns []Column, separator string) int {
+	if len(columns) == 0 {
+		return 0
+	}
+	width := ansi.StringWidth(separator) * (len(columns) - 1)
+	for _, col := range columns {
+		width += col.Width
+	}
+	return width
+}
+
+// PadCell truncates or pads text to exactly width display cells using the given alignment
+func PadCell(text string, width int, align Alignment) string {
+	text = ansi.Truncate(text, width, "")
+	padding := width - ansi.StringWidth(text)
+	if padding <= 0 {
+		return text
+	}
+	if align == AlignRight {
+		return strings.Repeat(" ", padding) + text
+	}
+	return text + strings.Repeat(" ", padding)
+}
+
+// joinCells joins rendered cells with the styled column separator
+func joinCells(cells []string, styles *SplitFlapStyles) string {
+	separator := styles.Separator
+	if strings.TrimSpace(separator) != "" {
+		separator = styles.BorderLine.Render(separator)
+	}
+	return strings.Join(cells, separator)
+}
+
+// horizontalRule returns a rule spanning the table, with junctions under column separators
+func horizontalRule(columns []Column, separator string) string {
+	segments := make([]string, 0, len(columns))
+	for _, col := range columns {
+		segments = append(segments, strings.Repeat("─", col.Width))
+	}
+	junction := strings.Repeat("─", ansi.StringWidth(separator))
+	if strings.Contains(separator, "│") {
+		junction = strings.ReplaceAll(strings.ReplaceAll(separator, " ", "─"), "│", "┼")
+	}
+	return strings.Join(segments, junction)
+}
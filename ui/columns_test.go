@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"fids-tui/models"
+)
+
+// TestColumnWidths checks the header, populated rows and empty rows all
+// render to the table width
+func TestColumnWidths(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(b *Board)
+	}{
+		{"wide departures", nil},
+		{"wide arrivals", func(b *Board) { b.SetDirection(models.Arrival) }},
+		{"compact", func(b *Board) { b.SetLayoutMode(LayoutCompact) }},
+		{"compact arrivals", func(b *Board) {
+			b.SetDirection(models.Arrival)
+			b.SetLayoutMode(LayoutCompact)
+		}},
+		{"gates view", func(b *Board) { b.SetViewMode(ViewGates) }},
+		{"full borders", func(b *Board) { b.SetBorders(BordersFull) }},
+		{"compact with full borders", func(b *Board) {
+			b.SetLayoutMode(LayoutCompact)
+			b.SetBorders(BordersFull)
+		}},
+		{"wide emoji glyphs", func(b *Board) {
+			glyphs, err := ParseGlyphSet("unicode", map[string]string{"on_time": "✅", "delayed": "⏰"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.SetGlyphs(glyphs)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newTestBoard(3)
+			if tt.setup != nil {
+				tt.setup(board)
+			}
+			now := time.Now()
+			flights := testFlights(4, now)
+			// Text longer than its columns is cut to fit
+			flights[0].DestinationCity = "Dallas/Fort Worth International"
+			flights[0].Gate = "B22A-REMOTE"
+			flights[1].Status = models.StatusDelayed
+			estimate := flights[1].ScheduledDeparture.Add(40 * time.Minute)
+			flights[1].EstimatedDeparture = &estimate
+			for i := range flights {
+				flights[i].OriginCode = "ORD"
+				flights[i].ScheduledArrival = flights[i].ScheduledDeparture
+			}
+			board.UpdateFlights(flights)
+			settle(t, board)
+
+			width := board.tableWidth()
+			for i, line := range strings.Split(board.renderHeader(), "\n") {
+				if got := ansi.StringWidth(line); got != width {
+					t.Errorf("header line %d is %d wide, want %d: %q", i, got, width, ansi.Strip(line))
+				}
+			}
+			// The second page has one flight and two empty rows
+			for page := range 2 {
+				for r, row := range board.pageRows() {
+					kind := "flight"
+					if row.Flight == nil {
+						kind = "empty"
+					}
+					for i, line := range strings.Split(row.Render(board.Styles), "\n") {
+						if got := ansi.StringWidth(line); got != width {
+							t.Errorf("page %d %s row %d line %d is %d wide, want %d: %q", page+1, kind, r, i, got, width, ansi.Strip(line))
+						}
+					}
+				}
+				board.NextPage()
+				settle(t, board)
+			}
+		})
+	}
+}
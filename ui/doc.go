@@ -0,0 +1,12 @@
+// Package ui renders the split-flap departures board as a string, and can
+// be used on its own with flights from any source: it depends only on the
+// models package. Build the flights, hand them to a board with NewBoard and
+// UpdateFlights, tick the animation on a timer of your own while
+// IsAnimating reports true and draw what Render returns. The Board example
+// is a complete program doing so.
+//
+// Times stay as the flights give them and are shown in the airport's
+// timezone. The remarks come from status templates unless they are set to
+// nil; the fids package wraps the board in a bubbletea model that fetches
+// the flights too.
+package ui
@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChangeKind identifies what changed about a flight
+type ChangeKind int
+
+const (
+	ChangeGate ChangeKind = iota
+	ChangeStatus
+	ChangeEstimate
+	ChangeBaggage // Baggage claim of an arrival
+	ChangeRetimed // Scheduled time moved by the airline, rather than a delay
+)
+
+// ChangeEvent records a change to a flight seen between two updates
+type ChangeEvent struct {
+	Time         time.Time // When the change was seen, in the airport timezone
+	AirportCode  string
+	FlightNumber string
+	FlightID     string    // Source's unique ID of the flight, if it has one
+	Scheduled    time.Time // Scheduled time of the flight
+	Day          int       // Days after the first flight of the same number on the board, 0 for the first
+	Place        string    // Destination airport code, or origin for arrivals
+	Kind         ChangeKind
+	Old          string
+	New          string
+	Blocked      bool // The flight is blocked from public tracking, so has no number or place
+}
+
+// Description describes the change, e.g. "gate B12→B20", "delayed to 16:40"
+// or "retimed 14:20→14:50"
+func (e ChangeEvent) Description() string {
+	switch e.Kind {
+	case ChangeGate:
+		if e.Old == "" {
+			return "gate " + e.New
+		}
+		if e.New == "" {
+			return "gate " + e.Old + " removed"
+		}
+		return "gate " + e.Old + "→" + e.New
+	case ChangeEstimate:
+		return "delayed to " + e.New
+	case ChangeRetimed:
+		return "retimed " + e.Old + "→" + e.New
+	case ChangeBaggage:
+		if e.Old == "" {
+			return "baggage at claim " + e.New
+		}
+		if e.New == "" {
+			return "baggage claim " + e.Old + " removed"
+		}
+		return "baggage claim " + e.Old + "→" + e.New
+	default:
+		return strings.ToLower(e.New)
+	}
+}
+
+// Flight names the event's flight: its number, with the day marker for a
+// later flight of the same number, e.g. "UA 123 +1"
+func (e ChangeEvent) Flight() string {
+	if e.Blocked {
+		return blockedLabel
+	}
+	if marker := dayMarker(e.Day); marker != "" {
+		return e.FlightNumber + " " + marker
+	}
+	return e.FlightNumber
+}
+
+// String formats the event as a log line, e.g. "14:05 JFK UA 123 gate B12→B20"
+func (e ChangeEvent) String() string {
+	return fmt.Sprintf("%s %s %s %s", e.Time.Format("15:04"), e.AirportCode, e.Flight(), e.Description())
+}
+
+// changeEvents returns the events for a flight's field changes between two
+// versions of it, scheduled day days after the first flight of its number
+func changeEvents(airportCode string, day int, change models.FlightChange, old, new *models.Flight, now time.Time) []ChangeEvent {
+	var events []ChangeEvent
+	place := new.DestinationCode
+	if new.Direction == models.Arrival {
+		place = new.OriginCode
+	}
+	event := func(kind ChangeKind, oldValue, newValue string) {
+		events = append(events, ChangeEvent{
+			Time:         now,
+			AirportCode:  airportCode,
+			FlightNumber: new.FlightNumber,
+			FlightID:     new.ID,
+			Scheduled:    new.ScheduledTime(),
+			Day:          day,
+			Place:        place,
+			Kind:         kind,
+			Old:          oldValue,
+			New:          newValue,
+			Blocked:      new.Blocked,
+		})
+	}
+
+	if gate, ok := change.Field(models.FieldGate); ok {
+		event(ChangeGate, gate.Old, gate.New)
+	}
+	if claim, ok := change.Field(models.FieldBaggageClaim); ok {
+		event(ChangeBaggage, claim.Old, claim.New)
+	}
+
+	// Times are logged in the airport's time, like the event itself. A
+	// retimed flight is logged as such, then as delayed only if it is also
+	// late for its new time
+	if _, ok := change.Field(models.FieldScheduled); ok {
+		oldSched, newSched := old.ScheduledTime(), new.ScheduledTime()
+		event(ChangeRetimed, formatOptionalTime(&oldSched, now.Location()), formatOptionalTime(&newSched, now.Location()))
+	}
+	oldEst := formatOptionalTime(old.EstimatedTime(), now.Location())
+	newEst := formatOptionalTime(new.EstimatedTime(), now.Location())
+	status, statusChanged := change.Field(models.FieldStatus)
+	_, estimateChanged := change.Field(models.FieldEstimate)
+	switch {
+	case statusChanged && new.Status == models.StatusDelayed && newEst != "":
+		event(ChangeEstimate, oldEst, newEst)
+	case statusChanged:
+		event(ChangeStatus, status.Old, status.New)
+	case new.Status == models.StatusDelayed && estimateChanged && newEst != "":
+		event(ChangeEstimate, oldEst, newEst)
+	}
+
+	return events
+}
+
+// formatOptionalTime formats t as HH:MM in loc, or returns an empty string if
+// t is nil
+func formatOptionalTime(t *time.Time, loc *time.Location) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(loc).Format("15:04")
+}
+
+// RenderEventLog renders change events newest first, showing height lines
+// starting offset events from the newest
+func RenderEventLog(events []ChangeEvent, offset, height int, styles *SplitFlapStyles) string {
+	lines := []string{styles.AirportLabel.Render("CHANGE LOG")}
+	if len(events) == 0 {
+		lines = append(lines, styles.Text.Render("No changes recorded yet"))
+	}
+
+	shown := 0
+	for i := len(events) - 1 - offset; i >= 0 && shown < height; i-- {
+		lines = append(lines, styles.Text.Render(events[i].String()))
+		shown++
+	}
+
+	footer := fmt.Sprintf("%d events | ↑/↓ to scroll | 'L' or Esc to close", len(events))
+	lines = append(lines, styles.PageInfo.Render(footer))
+	return styles.Background.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
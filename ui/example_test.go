@@ -0,0 +1,50 @@
+package ui_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+	"fids-tui/ui"
+)
+
+// Renders a board of flights from a source of your own, with no fetching:
+// the flights are built by hand, the animation ticked on a timer until it
+// settles and the board printed. A program of its own would tick on each
+// frame of its display and print Render as it is
+func ExampleBoard() {
+	board := ui.NewBoard("CLB", time.UTC, 8)
+	board.SetTerminalSize(80, 24)
+	board.SetRemarkTemplates(nil) // Show the flights' own remarks
+	board.UpdateFlights([]models.Flight{{
+		FlightNumber:       "CL 12",
+		DestinationCode:    "OXF",
+		DestinationCity:    "Oxford",
+		ScheduledDeparture: time.Date(2026, time.May, 1, 14, 30, 0, 0, time.UTC),
+		Gate:               "3",
+		Status:             models.StatusOnTime,
+		Remarks:            "Boarding at hangar",
+	}, {
+		FlightNumber:       "CL 14",
+		DestinationCode:    "CBG",
+		DestinationCity:    "Cambridge",
+		ScheduledDeparture: time.Date(2026, time.May, 1, 15, 10, 0, 0, time.UTC),
+		Gate:               "1",
+		Status:             models.StatusCancelled,
+		Remarks:            "Weather",
+	}})
+	for board.IsAnimating() {
+		board.Tick()
+		time.Sleep(50 * time.Millisecond)
+	}
+	for _, line := range strings.Split(board.Render(), "\n") {
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	// Output:
+	// DEPARTURES - CLB
+	//
+	//   S FLIGHT   TIME     DESTINATION          GATE   REMARKS
+	//   * CL 12    14:30    OXF Oxford           3      Boarding at hangar
+	//   X CL 14    15:10    CBG Cambridge        1      Weather
+}
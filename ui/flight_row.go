@@ -1,158 +1,239 @@
 package ui
 
 import (
+	"strings"
+	"time"
+
 	"fids-tui/models"
-	"fmt"
 )
 
 // FlightRow represents an animated flight row
 type FlightRow struct {
-	Flight          *models.Flight
-	StatusAnim      *AnimatedText
-	FlightNumAnim   *AnimatedText
-	TimeAnim        *AnimatedText
-	DestinationAnim *AnimatedText
-	GateAnim        *AnimatedText
-	RemarksAnim     *AnimatedText
+	Flight *models.Flight
+	layout Layout
+	zone   *time.Location // Timezone times are shown in
+	glyphs *GlyphSet      // Icons for the status column
+	cells  map[ColumnID]CellText
+	values map[ColumnID]string // Cell text last applied to each animation
+	next   bool                // The flight departs next, so its time is highlighted
+	// changedAt is when the flight's data last changed, or when the board
+	// first had it; refreshes bringing the same data leave it alone
+	changedAt time.Time
+	stale     bool // The flight is delayed and its estimate hasn't moved in a while
+	gateChurn bool // The flight's gate changed more than once in the last hour
+	day       int  // Days after the first flight of the same number on the board, marked after the time
+}
+
+// LastChangedAt returns when the data of the row's flight last changed, or
+// was first loaded, zero for rows without a flight
+func (fr *FlightRow) LastChangedAt() time.Time {
+	return fr.changedAt
 }
 
-// NewFlightRow creates a new flight row with animations
-func NewFlightRow(flight *models.Flight) *FlightRow {
+// NewFlightRow creates a new flight row with animations sized to the columns of
+// the layout and timed by timing, showing times in zone and status lights
+// from glyphs
+func NewFlightRow(flight *models.Flight, layout Layout, zone *time.Location, glyphs *GlyphSet, timing AnimationTiming) *FlightRow {
+	columns := layout.Columns()
 	row := &FlightRow{
-		Flight:          flight,
-		StatusAnim:      NewAnimatedText(1),
-		FlightNumAnim:   NewAnimatedText(8), // Full flight number with airline code
-		TimeAnim:        NewAnimatedText(8), // HH:MM format
-		DestinationAnim: NewAnimatedText(20),
-		GateAnim:        NewAnimatedText(6),
-		RemarksAnim:     NewAnimatedText(20),
+		Flight: flight,
+		layout: layout,
+		zone:   zone,
+		glyphs: glyphs,
+		cells:  make(map[ColumnID]CellText, len(columns)),
+		values: make(map[ColumnID]string, len(columns)),
+	}
+	for _, col := range columns {
+		flap := NewAnimatedText(col.Width)
+		flap.Timing = timing
+		flap.Blocks = glyphs.flapBlocks()
+		row.cells[col.ID] = flap
+		if col.Scroll {
+			row.cells[col.ID] = &ScrollingText{AnimatedText: flap}
+		}
 	}
 
 	// Initialize animated text with flight data if available
 	if flight != nil {
-		statusChar := getStatusChar(flight.Status)
-		row.StatusAnim.Update(statusChar)
-
-		flightNum := truncate(flight.FlightNumber, 8)
-		row.FlightNumAnim.Update(flightNum)
-
-		timeStr := flight.ScheduledDeparture.Format("15:04")
-		row.TimeAnim.Update(timeStr)
-
-		dest := truncate(flight.GetDestination(), 20)
-		row.DestinationAnim.Update(dest)
-
-		gate := truncate(flight.Gate, 6)
-		if gate == "" {
-			gate = "     "
-		}
-		row.GateAnim.Update(gate)
-
-		remarks := truncate(string(flight.Remarks), 20)
-		row.RemarksAnim.Update(remarks)
+		row.Update(flight)
 	}
 
 	return row
 }
 
-// Update updates the flight data and triggers animations
-func (fr *FlightRow) Update(flight *models.Flight) {
+// Update updates the flight data and triggers animations for cells whose
+// text changed, reporting whether any cell changed
+func (fr *FlightRow) Update(flight *models.Flight) bool {
 	fr.Flight = flight
 
-	// Update status indicator
-	statusChar := getStatusChar(flight.Status)
-	fr.StatusAnim.Update(statusChar)
+	changed := false
+	for _, col := range fr.layout.Columns() {
+		value := cellValue(col.ID, flight, fr.zone, fr.glyphs)
+		if marker := dayMarker(fr.day); col.ID == ColTime && marker != "" {
+			value += " " + marker
+		}
+		text := PadCell(value, col.Width, col.Align)
+		if col.Scroll {
+			text = value // Kept whole to scroll through
+		}
+		if prev, ok := fr.values[col.ID]; ok && prev == text {
+			continue
+		}
+		fr.values[col.ID] = text
+		fr.cells[col.ID].Update(text)
+		changed = true
+	}
+	return changed
+}
 
-	// Update flight number (already includes airline code prefix, e.g., "BA114")
-	flightNum := truncate(flight.FlightNumber, 8)
-	fr.FlightNumAnim.Update(flightNum)
+// cellValue returns the unpadded text shown for a flight in the given column,
+// with times in zone and status lights from glyphs
+func cellValue(id ColumnID, flight *models.Flight, zone *time.Location, glyphs *GlyphSet) string {
+	if flight.Blocked {
+		if value, ok := blockedValue(id); ok {
+			return value
+		}
+	}
+	switch id {
+	case ColStatus:
+		return glyphs.Status(flight.Status)
+	case ColFlight:
+		// Already includes airline code prefix, e.g., "BA 114"
+		return flight.FlightNumber
+	case ColTime:
+		// Departure or arrival time (HH:MM format)
+		return flight.ScheduledTime().In(zone).Format("15:04")
+	case ColDestination:
+		return airportOrPlaceholder(flight.GetDestination())
+	case ColOrigin:
+		return airportOrPlaceholder(flight.GetOrigin())
+	case ColDestinationCode:
+		return airportOrPlaceholder(strings.TrimSpace(flight.DestinationCode))
+	case ColOriginCode:
+		return airportOrPlaceholder(strings.TrimSpace(flight.OriginCode))
+	case ColGate:
+		return flight.Gate
+	case ColBaggage:
+		return flight.BaggageClaim
+	case ColRemarks:
+		return string(flight.Remarks)
+	case ColAirport:
+		return FlightAirport(flight)
+	default:
+		return ""
+	}
+}
 
-	// Update departure time (HH:MM format)
-	timeStr := flight.ScheduledDeparture.Format("15:04")
-	fr.TimeAnim.Update(timeStr)
+// blockedLabel is shown in place of the route of a flight blocked from
+// public tracking
+const blockedLabel = "— BLOCKED —"
+
+// blockedValue returns what a blocked flight shows in column id, or false
+// for the columns it shows as usual: its time, status and remarks. Nothing
+// else is known of it
+func blockedValue(id ColumnID) (string, bool) {
+	switch id {
+	case ColStatus, ColTime, ColRemarks, ColAirport:
+		return "", false
+	case ColDestination, ColOrigin:
+		return blockedLabel, true
+	case ColFlight, ColDestinationCode, ColOriginCode:
+		return missingAirport, true
+	default:
+		return "", true
+	}
+}
 
-	// Update destination
-	dest := truncate(flight.GetDestination(), 20)
-	fr.DestinationAnim.Update(dest)
+// missingAirport is shown in place of an unknown destination or origin
+const missingAirport = "——"
 
-	// Update gate
-	gate := truncate(flight.Gate, 6)
-	if gate == "" {
-		gate = "     "
+// airportOrPlaceholder returns airport, or the placeholder if it is unknown
+func airportOrPlaceholder(airport string) string {
+	if airport == "" {
+		return missingAirport
 	}
-	fr.GateAnim.Update(gate)
+	return airport
+}
 
-	// Update remarks
-	remarks := truncate(string(flight.Remarks), 20)
-	fr.RemarksAnim.Update(remarks)
+// SetZone shows the row's times in zone, animating the cells that change
+func (fr *FlightRow) SetZone(zone *time.Location, flight *models.Flight) {
+	fr.zone = zone
+	fr.Update(flight)
 }
 
 // Tick updates all animations
 func (fr *FlightRow) Tick() {
-	fr.StatusAnim.Tick()
-	fr.FlightNumAnim.Tick()
-	fr.TimeAnim.Tick()
-	fr.DestinationAnim.Tick()
-	fr.GateAnim.Tick()
-	fr.RemarksAnim.Tick()
+	for _, cell := range fr.cells {
+		cell.Tick()
+	}
 }
 
-// Render renders the flight row with split-flap styling
-func (fr *FlightRow) Render(styles *SplitFlapStyles) string {
-	if fr.Flight == nil {
-		// Empty row (68 characters to match row width)
-		return styles.Text.Render("                                                                    ")
+// settle shows the row's new text at once, without animating
+func (fr *FlightRow) settle() {
+	for _, cell := range fr.cells {
+		switch cell := cell.(type) {
+		case *AnimatedText:
+			cell.settle()
+		case *ScrollingText:
+			cell.AnimatedText.settle()
+		}
 	}
-
-	statusColor := fr.Flight.GetStatusColor()
-
-	// Render status with color
-	statusText := fr.StatusAnim.Render()
-	statusStyle := styles.StatusLight(statusColor)
-	statusRendered := statusStyle.Render(statusText)
-
-	// Render other fields
-	flightNum := styles.Text.Render(fr.FlightNumAnim.Render())
-	timeStr := styles.Text.Render(fr.TimeAnim.Render())
-	destination := styles.Text.Render(fr.DestinationAnim.Render())
-	gate := styles.Text.Render(fr.GateAnim.Render())
-	remarks := styles.Text.Render(fr.RemarksAnim.Render())
-
-	// Combine with proper spacing
-	return fmt.Sprintf("%s %s %s %s %s %s",
-		statusRendered,
-		flightNum,
-		timeStr,
-		destination,
-		gate,
-		remarks,
-	)
 }
 
-// getStatusChar returns a character icon for the status
-// Uses simple ASCII-compatible characters that work in all terminals
-// The character will be colored by the StatusLight style
-func getStatusChar(status models.FlightStatus) string {
-	switch status {
-	case models.StatusOnTime:
-		return "*" // Asterisk - green (on time)
-	case models.StatusDelayed:
-		return "!" // Exclamation - orange (delayed)
-	case models.StatusTaxiingLeftGate:
-		return ">" // Greater than - yellow (taxiing)
-	case models.StatusTaxiingDelayed:
-		return ">" // Greater than - orange (taxiing delayed)
-	case models.StatusCancelled:
-		return "X" // X - red (cancelled)
-	default:
-		return " " // Space for unknown status
+// IsAnimating returns true if any cell is currently animating
+func (fr *FlightRow) IsAnimating() bool {
+	for _, cell := range fr.cells {
+		if cell.IsAnimating() {
+			return true
+		}
 	}
+	return false
 }
 
-// truncate truncates a string to the specified length
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// Render renders the flight row with split-flap styling, one terminal line per
+// layout line. Empty rows render their blank cells, so they always span the
+// same column widths as populated rows
+func (fr *FlightRow) Render(styles *SplitFlapStyles) string {
+	lines := make([][]string, 0, len(fr.layout.Lines))
+	for _, columns := range fr.layout.Lines {
+		cells := make([]string, 0, len(columns))
+		for _, col := range columns {
+			// Animations count runes, so a double-width glyph would
+			// overflow its cell without being cut back to the column width
+			text := PadCell(fr.cells[col.ID].Render(), col.Width, col.Align)
+			if col.ID == ColStatus && fr.Flight != nil {
+				// Render status with color
+				statusStyle := styles.StatusLight(fr.Flight.GetStatusColor())
+				cells = append(cells, statusStyle.Render(text))
+				continue
+			}
+			if col.ID == ColTime && fr.stale {
+				// Mark the time with the stale glyph after it, keeping the width
+				text = PadCell(strings.TrimRight(text, " ")+" "+fr.glyphs.Stale(), col.Width, col.Align)
+				if fr.next {
+					cells = append(cells, styles.NextFlight.Render(text))
+				} else {
+					cells = append(cells, styles.Stale.Render(text))
+				}
+				continue
+			}
+			if col.ID == ColTime && fr.next {
+				cells = append(cells, styles.NextFlight.Render(text))
+				continue
+			}
+			if col.ID == ColGate && fr.gateChurn {
+				// Stays marked while the gate is unsettled, not just as it flips
+				cells = append(cells, styles.GateChanged.Render(text))
+				continue
+			}
+			if fr.Flight != nil && fr.Flight.Blocked {
+				cells = append(cells, styles.Blocked.Render(text))
+				continue
+			}
+			cells = append(cells, styles.Text.Render(text))
+		}
+		lines = append(lines, cells)
 	}
-	return s[:maxLen]
+
+	return fr.layout.joinLines(lines, styles)
 }
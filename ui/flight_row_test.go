@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+func TestCellValueMissingAirports(t *testing.T) {
+	glyphs := DefaultGlyphSet()
+	tests := []struct {
+		name   string
+		flight models.Flight
+		column ColumnID
+		want   string
+	}{
+		{"destination known", models.Flight{DestinationCode: "LAX", DestinationCity: "Los Angeles"}, ColDestination, "LAX Los Angeles"},
+		{"no destination", models.Flight{}, ColDestination, missingAirport},
+		{"no destination code", models.Flight{}, ColDestinationCode, missingAirport},
+		{"blank destination code", models.Flight{DestinationCode: "  "}, ColDestinationCode, missingAirport},
+		{"destination city only", models.Flight{DestinationCity: "Springfield"}, ColDestination, "Springfield"},
+		{"code of a destination known by city only", models.Flight{DestinationCity: "Springfield"}, ColDestinationCode, missingAirport},
+		{"destination code only", models.Flight{DestinationCode: "BOS"}, ColDestination, "BOS"},
+		{"no origin", models.Flight{Direction: models.Arrival}, ColOrigin, missingAirport},
+		{"no origin code", models.Flight{Direction: models.Arrival}, ColOriginCode, missingAirport},
+		{"origin ICAO code", models.Flight{Direction: models.Arrival, OriginCode: "CYYC", OriginCity: "Calgary"}, ColOrigin, "CYYC Calgary"},
+		{"blocked", models.Flight{Blocked: true}, ColDestination, blockedLabel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cellValue(tt.column, &tt.flight, time.UTC, glyphs); got != tt.want {
+				t.Errorf("cellValue = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fids-tui/models"
+)
+
+const (
+	// gateHistoryDepth is how many earlier gates are kept for each flight
+	gateHistoryDepth = 3
+	// GateHistoryRetention is how long the gate history of a flight is kept
+	// after it was last on a board, so switching to another board and back
+	// keeps it
+	GateHistoryRetention = time.Hour
+	// gateChurnWindow is the span a gate changing more than once in marks
+	// the gate as unsettled
+	gateChurnWindow = time.Hour
+)
+
+// GateChange is a gate a flight had before, and when it moved from it
+type GateChange struct {
+	Gate string
+	At   time.Time
+}
+
+// gateRecord is the gate history of one flight
+type gateRecord struct {
+	changes  []GateChange // Earlier gates, oldest first
+	lastSeen time.Time    // When the flight was last on a board
+}
+
+// GateHistory remembers the earlier gates of flights, taken from the
+// changes between updates. Boards can share one history, so a flight's
+// history outlives switching to another board and back
+type GateHistory struct {
+	mu      sync.Mutex
+	flights map[string]*gateRecord
+}
+
+// NewGateHistory creates an empty gate history
+func NewGateHistory() *GateHistory {
+	return &GateHistory{flights: make(map[string]*gateRecord)}
+}
+
+// moved records that the flight key moved from gate at a time, keeping
+// the last gateHistoryDepth gates
+func (h *GateHistory) moved(key, gate string, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.record(key, at)
+	r.changes = append(r.changes, GateChange{Gate: gate, At: at})
+	if len(r.changes) > gateHistoryDepth {
+		r.changes = r.changes[len(r.changes)-gateHistoryDepth:]
+	}
+}
+
+// record returns the history of flight key, starting one if there is none,
+// and notes the flight as on a board at now
+func (h *GateHistory) record(key string, now time.Time) *gateRecord {
+	r, ok := h.flights[key]
+	if !ok {
+		r = &gateRecord{}
+		h.flights[key] = r
+	}
+	r.lastSeen = now
+	return r
+}
+
+// seen notes the flights of keys as on a board at now, and forgets the
+// flights that haven't been for GateHistoryRetention
+func (h *GateHistory) seen(keys []string, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range keys {
+		if r, ok := h.flights[key]; ok {
+			r.lastSeen = now
+		}
+	}
+	h.prune(now)
+}
+
+// Prune forgets the flights that haven't been on a board for
+// GateHistoryRetention before now
+func (h *GateHistory) Prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.prune(now)
+}
+
+// prune is Prune with the lock held
+func (h *GateHistory) prune(now time.Time) {
+	for key, r := range h.flights {
+		if now.Sub(r.lastSeen) >= GateHistoryRetention {
+			delete(h.flights, key)
+		}
+	}
+}
+
+// rekey moves the history of flight key from to key to, for a flight whose
+// key changed with its scheduled time
+func (h *GateHistory) rekey(from, to string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r, ok := h.flights[from]; ok && from != to {
+		h.flights[to] = r
+		delete(h.flights, from)
+	}
+}
+
+// Changes returns the earlier gates of flight key, most recent first
+func (h *GateHistory) Changes(key string) []GateChange {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.flights[key]
+	if !ok {
+		return nil
+	}
+	changes := make([]GateChange, len(r.changes))
+	for i, change := range r.changes {
+		changes[len(changes)-1-i] = change
+	}
+	return changes
+}
+
+// churning reports whether the gate of flight key changed more than once
+// in the gateChurnWindow before now
+func (h *GateHistory) churning(key string, now time.Time) bool {
+	changes := 0
+	for _, change := range h.Changes(key) {
+		if now.Sub(change.At) < gateChurnWindow {
+			changes++
+		}
+	}
+	return changes > 1
+}
+
+// trackGates records the gates flights moved from between the flights of
+// the last update and those of this one. Only a gate that was set counts;
+// one assigned for the first time has no earlier gate to show
+func (b *Board) trackGates(old, flights []models.Flight, now time.Time) {
+	diff := models.DiffFlights(old, flights)
+	for _, change := range diff.Matched {
+		from, to := seenKey(&old[change.Old]), seenKey(&flights[change.New])
+		b.Gates.rekey(from, to)
+		if field, ok := change.Field(models.FieldGate); ok && strings.TrimSpace(field.Old) != "" {
+			b.Gates.moved(to, field.Old, now)
+		}
+	}
+	keys := make([]string, len(flights))
+	for i := range flights {
+		keys[i] = seenKey(&flights[i])
+	}
+	b.Gates.seen(keys, now)
+}
+
+// markGates flags the rows whose gate keeps changing at now
+func (b *Board) markGates(now time.Time) {
+	for _, row := range b.Rows() {
+		row.gateChurn = row.Flight != nil && b.Gates.churning(seenKey(row.Flight), now)
+	}
+}
+
+// gateLine returns the gate line of the detail panel, with the gates the
+// flight had before, e.g. "GATE     C3 (was B7 at 13:42)"
+func (b *Board) gateLine(flight *models.Flight, zone *time.Location) string {
+	gate := flight.Gate
+	if gate == "" {
+		gate = "-"
+	}
+	changes := b.Gates.Changes(seenKey(flight))
+	if len(changes) == 0 {
+		return fmt.Sprintf("%-8s %s", "GATE", gate)
+	}
+	earlier := make([]string, len(changes))
+	for i, change := range changes {
+		earlier[i] = fmt.Sprintf("%s at %s", change.Gate, change.At.In(zone).Format("15:04"))
+	}
+	return fmt.Sprintf("%-8s %s (was %s)", "GATE", gate, strings.Join(earlier, ", "))
+}
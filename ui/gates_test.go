@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestGateHistory(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := NewGateHistory()
+	for i, gate := range []string{"A1", "A2", "A3", "A4"} {
+		h.moved("AA100", gate, start.Add(time.Duration(i)*time.Minute))
+	}
+	var gates []string
+	for _, change := range h.Changes("AA100") {
+		gates = append(gates, change.Gate)
+	}
+	if want := []string{"A4", "A3", "A2"}; !slices.Equal(gates, want) {
+		t.Errorf("Changes = %q, want the last %d most recent first, %q", gates, gateHistoryDepth, want)
+	}
+	if !h.churning("AA100", start.Add(time.Hour)) || h.churning("AA100", start.Add(time.Hour+2*time.Minute)) {
+		t.Error("gate churning outside the hour after its changes, or not within it")
+	}
+
+	// The history follows a flight whose key changed, and lasts as long as
+	// the flight is seen
+	h.rekey("AA100", "AA100-late")
+	if h.Changes("AA100") != nil || len(h.Changes("AA100-late")) != gateHistoryDepth {
+		t.Error("history not moved to the flight's new key")
+	}
+	seen := start.Add(10 * time.Minute)
+	h.seen([]string{"AA100-late"}, seen)
+	h.Prune(seen.Add(GateHistoryRetention - time.Second))
+	if h.Changes("AA100-late") == nil {
+		t.Error("history forgotten before its retention")
+	}
+	h.Prune(seen.Add(GateHistoryRetention))
+	if h.Changes("AA100-late") != nil {
+		t.Error("history kept past its retention")
+	}
+}
+
+// TestGateRender moves AA 100 from gate B2 to B4 and then B9, checking the
+// detail panel lists the earlier gates, and that the gate is marked as
+// unsettled only once it has changed twice
+func TestGateRender(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	flights := testFlights(3, time.Now())
+	board := newTestBoard(10)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	var gate Column
+	for _, col := range board.Layout.Columns() {
+		if col.ID == ColGate {
+			gate = col
+		}
+	}
+	marked := func(name string) bool {
+		return strings.Contains(board.Render(), board.Styles.GateChanged.Render(PadCell(name, gate.Width, gate.Align)))
+	}
+	// at returns when the nth gate change was recorded, oldest first
+	at := func(n int) string {
+		changes := board.Gates.Changes(seenKey(&flights[0]))
+		return changes[len(changes)-1-n].At.In(time.UTC).Format("15:04")
+	}
+
+	for _, tt := range []struct {
+		gate   string
+		detail func() string
+		marked bool
+	}{
+		{"B4", func() string { return "GATE     B4 (was B2 at " + at(0) + ")" }, false},
+		{"B9", func() string { return "GATE     B9 (was B4 at " + at(1) + ", B2 at " + at(0) + ")" }, true},
+	} {
+		flights[0].Gate = tt.gate
+		board.UpdateFlights(flights)
+		settle(t, board)
+		board.Select(0)
+		if lines := strings.Split(ansi.Strip(board.Render()), "\n"); lineOf(lines, 0, tt.detail()) < 0 {
+			t.Errorf("gate %s: no detail line %q:\n%s", tt.gate, tt.detail(), strings.Join(lines, "\n"))
+		}
+		board.Select(0)
+		if got := marked(tt.gate); got != tt.marked {
+			t.Errorf("gate %s: marked %v, want %v", tt.gate, got, tt.marked)
+		}
+	}
+	if marked("B2") {
+		t.Error("flight whose gate never changed marked")
+	}
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// glyphPresets are the built-in icon sets selectable with GLYPHS. ascii works
+// on any console; unicode needs a font with common symbols and nerdfont one
+// patched with Nerd Font icons. Every status has its own glyph in each set,
+// so statuses sharing a color, like the two delays, still look different
+var glyphPresets = map[string]map[models.FlightStatus]string{
+	"ascii": {
+		models.StatusOnTime:          "*",
+		models.StatusDelayed:         "!",
+		models.StatusTaxiingLeftGate: ">",
+		models.StatusTaxiingDelayed:  "}",
+		models.StatusCancelled:       "X",
+		models.StatusDeparted:        "^",
+		models.StatusArrived:         "v",
+		models.StatusUnknown:         " ",
+	},
+	"unicode": {
+		models.StatusOnTime:          "●",
+		models.StatusDelayed:         "◆",
+		models.StatusTaxiingLeftGate: "»",
+		models.StatusTaxiingDelayed:  "≫",
+		models.StatusCancelled:       "✖",
+		models.StatusDeparted:        "✈",
+		models.StatusArrived:         "▼",
+		models.StatusUnknown:         " ",
+	},
+	"nerdfont": {
+		models.StatusOnTime:          "\uf058",     // nf-fa-check_circle
+		models.StatusDelayed:         "\uf017",     // nf-fa-clock_o
+		models.StatusTaxiingLeftGate: "\uf072",     // nf-fa-plane
+		models.StatusTaxiingDelayed:  "\uf071",     // nf-fa-warning
+		models.StatusCancelled:       "\uf057",     // nf-fa-times_circle
+		models.StatusDeparted:        "\U000f05a5", // nf-md-airplane_takeoff
+		models.StatusArrived:         "\U000f05d4", // nf-md-airplane_landing
+		models.StatusUnknown:         " ",
+	},
+}
+
+// staleGlyphs mark delayed flights whose estimate looks stuck, by preset
+var staleGlyphs = map[string]string{
+	"ascii":    "?",
+	"unicode":  "•",
+	"nerdfont": "\uf252", // nf-fa-hourglass_half
+}
+
+// GlyphSet holds the icons drawn on the board. Status lights are colored by
+// the StatusLight style, so the glyph only needs to be recognizable; other
+// icons the board draws belong in the set too, so one preset styles them all
+type GlyphSet struct {
+	status      map[models.FlightStatus]string
+	stale       string // Marks a stale estimate after the time
+	asciiShades bool   // Draw the flaps and timeline in ASCII instead of block characters
+}
+
+// DefaultGlyphSet returns the ASCII icons, which work in every terminal
+func DefaultGlyphSet() *GlyphSet {
+	gs, err := ParseGlyphSet("ascii", nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default glyph set: %v", err))
+	}
+	return gs
+}
+
+// ParseGlyphSet returns the icons of a GLYPHS preset (ascii, unicode or
+// nerdfont) with status glyphs overridden by status name, using the names of
+// REMARK_TEMPLATES
+func ParseGlyphSet(preset string, overrides map[string]string) (*GlyphSet, error) {
+	name := strings.ToLower(strings.TrimSpace(preset))
+	if name == "" {
+		name = "ascii"
+	}
+	base, ok := glyphPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown glyph set %q (expected %s)", preset, glyphPresetNames())
+	}
+
+	gs := &GlyphSet{status: make(map[models.FlightStatus]string, len(base)), stale: staleGlyphs[name]}
+	for status, glyph := range base {
+		gs.status[status] = glyph
+	}
+	for key, glyph := range overrides {
+		status, ok := remarkStatusKeys[strings.ToLower(strings.TrimSpace(key))]
+		if !ok {
+			return nil, fmt.Errorf("unknown status %q (expected one of %s)", key, remarkStatusNames())
+		}
+		gs.status[status] = glyph
+	}
+	return gs, nil
+}
+
+// Status returns the status light glyph for status
+func (gs *GlyphSet) Status(status models.FlightStatus) string {
+	if glyph, ok := gs.status[status]; ok {
+		return glyph
+	}
+	return gs.status[models.StatusUnknown]
+}
+
+// Stale returns the glyph marking a stale estimate
+func (gs *GlyphSet) Stale() string {
+	return gs.stale
+}
+
+// WithASCIIShades returns a copy of the set drawing the flap animation and
+// the timeline in ASCII, for terminals that can't show block characters
+func (gs *GlyphSet) WithASCIIShades() *GlyphSet {
+	ascii := *gs
+	ascii.asciiShades = true
+	return &ascii
+}
+
+// flapBlocks returns the solid and light blocks a changing character
+// blinks between
+func (gs *GlyphSet) flapBlocks() [2]rune {
+	if gs.asciiShades {
+		return [2]rune{'#', '.'}
+	}
+	return [2]rune{'█', '░'}
+}
+
+// timelineCells returns the cells of the timeline: the empty track,
+// flights on other pages, the track under the current page and flights on
+// it
+func (gs *GlyphSet) timelineCells() (track, flight, page, pageFlight string) {
+	if gs.asciiShades {
+		return ".", "=", ":", "#"
+	}
+	return timelineTrack, timelineFlight, timelinePage, timelinePageFlight
+}
+
+// statusWidth returns the display width of the widest status glyph, so
+// double-width symbols like emoji get a column wide enough to keep the
+// table aligned
+func (gs *GlyphSet) statusWidth() int {
+	width := 1
+	for _, glyph := range gs.status {
+		width = max(width, ansi.StringWidth(glyph))
+	}
+	return width
+}
+
+// glyphPresetNames lists the preset names for error messages
+func glyphPresetNames() string {
+	names := make([]string, 0, len(glyphPresets))
+	for name := range glyphPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"fids-tui/models"
+)
+
+// TestGlyphPresetsKeepRowWidth renders a flight of every status with each
+// preset and checks the rows keep the header's width, with the flight column
+// starting at the same place whatever the width of the status glyph
+func TestGlyphPresetsKeepRowWidth(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides map[string]string
+	}{
+		{"ascii", "ascii", nil},
+		{"unicode", "unicode", nil},
+		{"nerdfont", "nerdfont", nil},
+		{"double-width override", "unicode", map[string]string{"delayed": "⏰", "Cancelled": "❌"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			glyphs, err := ParseGlyphSet(tt.preset, tt.overrides)
+			if err != nil {
+				t.Fatal(err)
+			}
+			statuses := []models.FlightStatus{
+				models.StatusOnTime, models.StatusDelayed, models.StatusTaxiingLeftGate, models.StatusTaxiingDelayed,
+				models.StatusCancelled, models.StatusUnknown, models.StatusDeparted, models.StatusArrived,
+			}
+			board := newTestBoard(len(statuses))
+			board.SetGlyphs(glyphs)
+			board.SetTerminalSize(80, 24)
+			flights := testFlights(len(statuses), time.Now())
+			for i, status := range statuses {
+				flights[i].Status = status
+			}
+			board.UpdateFlights(flights)
+			settle(t, board)
+
+			lines := renderedLines(board)
+			header := lineOf(lines, 0, "FLIGHT")
+			if header < 0 || header+len(statuses) >= len(lines) {
+				t.Fatalf("no header above %d rows:\n%s", len(statuses), strings.Join(lines, "\n"))
+			}
+			width := ansi.StringWidth(lines[header])
+			flightAt := ansi.StringWidth(lines[header][:strings.Index(lines[header], "FLIGHT")])
+			for i, status := range statuses {
+				line := lines[header+1+i]
+				if got := ansi.StringWidth(line); got != width {
+					t.Errorf("%v row is %d wide, header %d: %q", status, got, width, line)
+				}
+				at := strings.Index(line, flights[i].FlightNumber)
+				if at < 0 {
+					t.Errorf("%v row doesn't show %s: %q", status, flights[i].FlightNumber, line)
+					continue
+				}
+				if got := ansi.StringWidth(line[:at]); got != flightAt {
+					t.Errorf("%v row has its flight at column %d, header at %d: %q", status, got, flightAt, line)
+				}
+				if glyph := glyphs.Status(status); !strings.Contains(line[:at], glyph) {
+					t.Errorf("%v row doesn't start with %q: %q", status, glyph, line)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGlyphSet(t *testing.T) {
+	if _, err := ParseGlyphSet("emoji", nil); err == nil || !strings.Contains(err.Error(), "ascii, nerdfont, unicode") {
+		t.Errorf("unknown preset error = %v, want one listing the presets", err)
+	}
+	if _, err := ParseGlyphSet("ascii", map[string]string{"boarding": "B"}); err == nil {
+		t.Error("unknown status accepted")
+	}
+	glyphs, err := ParseGlyphSet(" Unicode ", map[string]string{"departed": "D"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := glyphs.Status(models.StatusDeparted); got != "D" {
+		t.Errorf("overridden departed glyph %q, want D", got)
+	}
+	if got := glyphs.Status(models.StatusOnTime); got != "●" {
+		t.Errorf("on time glyph %q, want the preset's ●", got)
+	}
+	if got := DefaultGlyphSet().Status(models.StatusCancelled); got != "X" {
+		t.Errorf("default cancelled glyph %q, want X", got)
+	}
+}
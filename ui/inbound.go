@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fids-tui/models"
+)
+
+// Inbound is the flight bringing a departure's aircraft in, as looked up
+// for the detail panel. Delays usually cascade from it
+type Inbound struct {
+	Origin string    // Airport the aircraft is coming from
+	Lands  time.Time // When it lands, or landed; zero if unknown
+}
+
+// inboundLine returns the detail panel line about the inbound aircraft of
+// flight, e.g. "A/C      inbound from ORD, lands 15:10", with a warning
+// when it lands after the flight is scheduled to leave. It returns "" for
+// arrivals and until the inbound flight has been looked up
+func (b *Board) inboundLine(flight *models.Flight, zone *time.Location) string {
+	if flight.Direction == models.Arrival || flight.InboundID == "" {
+		return ""
+	}
+	inbound, ok := b.Inbound[flight.InboundID]
+	if !ok {
+		return ""
+	}
+	line := fmt.Sprintf("%-8s inbound from %s", "A/C", airportOrPlaceholder(inbound.Origin))
+	if inbound.Lands.IsZero() {
+		return line
+	}
+	line += ", lands " + inbound.Lands.In(zone).Format("15:04")
+	if inbound.Lands.After(flight.ScheduledDeparture) {
+		line += " " + b.Styles.Error.Render("after the scheduled departure")
+	}
+	return line
+}
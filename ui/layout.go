@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// LayoutMode selects how much space each flight takes on the board
+type LayoutMode int
+
+const (
+	LayoutWide    LayoutMode = iota // One line per flight with every column
+	LayoutCompact                   // Two lines per flight for narrow screens
+)
+
+// ParseLayoutMode parses a LAYOUT config value (wide or compact)
+func ParseLayoutMode(value string) (LayoutMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "wide":
+		return LayoutWide, nil
+	case "compact":
+		return LayoutCompact, nil
+	default:
+		return LayoutWide, fmt.Errorf("unknown layout %q (expected wide or compact)", value)
+	}
+}
+
+// compactIndent is the indent of the second line of a flight in the compact layout
+const compactIndent = 2
+
+// Layout describes how a flight is laid out: one or more lines of columns,
+// with every line after the first indented
+type Layout struct {
+	Lines  [][]Column
+	Indent int
+}
+
+// NewLayout returns the layout for a mode and board direction
+func NewLayout(mode LayoutMode, direction models.Direction) Layout {
+	if mode != LayoutCompact {
+		return Layout{Lines: [][]Column{ColumnsFor(direction)}}
+	}
+
+	place := Column{ID: ColDestination, Name: "DESTINATION", Width: 16}
+	first := []Column{
+		{ID: ColStatus, Name: "S", Width: 1},
+		{ID: ColFlight, Name: "FLIGHT", Width: 8},
+		{ID: ColTime, Name: "TIME", Width: 5},
+		{ID: ColGate, Name: "GATE", Width: 6},
+	}
+	if direction == models.Arrival {
+		place = Column{ID: ColOrigin, Name: "ORIGIN", Width: 16}
+		first = append(first, Column{ID: ColBaggage, Name: "BAG", Width: 4})
+	}
+	return Layout{
+		Lines: [][]Column{
+			first,
+			{
+				place,
+				{ID: ColRemarks, Name: "REMARKS", Width: 18},
+			},
+		},
+		Indent: compactIndent,
+	}
+}
+
+// narrowingSteps make a layout narrower, least important information first:
+// the baggage claim and remarks are dropped, then the gate, then airports are
+// shown by code only
+var narrowingSteps = []func(Layout) Layout{
+	func(l Layout) Layout { return l.without(ColBaggage) },
+	func(l Layout) Layout { return l.without(ColRemarks) },
+	func(l Layout) Layout { return l.without(ColGate) },
+	Layout.codesOnly,
+}
+
+// fit narrows the layout until it is at most width wide, applying as many
+// narrowing steps as needed. A width of zero or less leaves it unchanged
+func (l Layout) fit(width int, separator string) Layout {
+	if width <= 0 {
+		return l
+	}
+	for _, step := range narrowingSteps {
+		if l.Width(separator) <= width {
+			break
+		}
+		l = step(l)
+	}
+	return l
+}
+
+// flexibleWidths are the narrowest the free text columns may be squeezed to,
+// to make room beside the table without dropping any column
+var flexibleWidths = map[ColumnID]int{
+	ColDestination: 12,
+	ColOrigin:      12,
+	ColRemarks:     12,
+}
+
+// squeeze narrows the flexible columns until the layout is at most width
+// wide, taking a cell at a time from whichever has the most to spare, and
+// reports whether it fits. The other columns keep their width
+func (l Layout) squeeze(width int, separator string) (Layout, bool) {
+	lines := make([][]Column, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = append([]Column(nil), line...)
+	}
+	l = Layout{Lines: lines, Indent: l.Indent}
+	for i, line := range l.Lines {
+		for l.indent(i)+TableWidth(line, separator) > width {
+			widest, spare := -1, 0
+			for j, col := range line {
+				if least, ok := flexibleWidths[col.ID]; ok && col.Width-least > spare {
+					widest, spare = j, col.Width-least
+				}
+			}
+			if widest < 0 {
+				return l, false
+			}
+			line[widest].Width--
+		}
+	}
+	return l, true
+}
+
+// without returns a copy of the layout without the column id, dropping
+// continuation lines left empty
+func (l Layout) without(id ColumnID) Layout {
+	lines := make([][]Column, 0, len(l.Lines))
+	for i, line := range l.Lines {
+		var kept []Column
+		for _, col := range line {
+			if col.ID != id {
+				kept = append(kept, col)
+			}
+		}
+		if len(kept) > 0 || i == 0 {
+			lines = append(lines, kept)
+		}
+	}
+	return Layout{Lines: lines, Indent: l.Indent}
+}
+
+// withName returns a copy of the layout with the header of column id set to
+// name, widening the column if the name doesn't fit
+func (l Layout) withName(id ColumnID, name string) Layout {
+	lines := make([][]Column, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = make([]Column, len(line))
+		for j, col := range line {
+			if col.ID == id {
+				col.Name = name
+				col.Width = max(col.Width, ansi.StringWidth(name))
+			}
+			lines[i][j] = col
+		}
+	}
+	return Layout{Lines: lines, Indent: l.Indent}
+}
+
+// withMinWidth returns a copy of the layout with column id at least width wide
+func (l Layout) withMinWidth(id ColumnID, width int) Layout {
+	lines := make([][]Column, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = make([]Column, len(line))
+		for j, col := range line {
+			if col.ID == id {
+				col.Width = max(col.Width, width)
+			}
+			lines[i][j] = col
+		}
+	}
+	return Layout{Lines: lines, Indent: l.Indent}
+}
+
+// withScroll returns a copy of the layout with the columns in scroll
+// scrolling their long text
+func (l Layout) withScroll(scroll map[ColumnID]bool) Layout {
+	lines := make([][]Column, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = make([]Column, len(line))
+		for j, col := range line {
+			col.Scroll = scroll[col.ID]
+			lines[i][j] = col
+		}
+	}
+	return Layout{Lines: lines, Indent: l.Indent}
+}
+
+// withColumnAfter returns a copy of the layout with col inserted after
+// column id, or at the start of the first line if there is no such column
+func (l Layout) withColumnAfter(id ColumnID, col Column) Layout {
+	lines := make([][]Column, len(l.Lines))
+	inserted := false
+	for i, line := range l.Lines {
+		lines[i] = make([]Column, 0, len(line)+1)
+		for _, existing := range line {
+			lines[i] = append(lines[i], existing)
+			if existing.ID == id {
+				lines[i] = append(lines[i], col)
+				inserted = true
+			}
+		}
+	}
+	if !inserted && len(lines) > 0 {
+		lines[0] = append([]Column{col}, lines[0]...)
+	}
+	return Layout{Lines: lines, Indent: l.Indent}
+}
+
+// codesOnly returns a copy of the layout showing airports by code instead of
+// code and city
+func (l Layout) codesOnly() Layout {
+	lines := make([][]Column, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = make([]Column, len(line))
+		for j, col := range line {
+			switch col.ID {
+			case ColDestination:
+				col = Column{ID: ColDestinationCode, Name: "DEST", Width: 4}
+			case ColOrigin:
+				col = Column{ID: ColOriginCode, Name: "ORIG", Width: 4}
+			}
+			lines[i][j] = col
+		}
+	}
+	return Layout{Lines: lines, Indent: l.Indent}
+}
+
+// equal reports whether two layouts have the same lines of columns
+func (l Layout) equal(other Layout) bool {
+	if l.Indent != other.Indent || len(l.Lines) != len(other.Lines) {
+		return false
+	}
+	for i := range l.Lines {
+		if len(l.Lines[i]) != len(other.Lines[i]) {
+			return false
+		}
+		for j := range l.Lines[i] {
+			if l.Lines[i][j] != other.Lines[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Columns returns every column of the layout in display order
+func (l Layout) Columns() []Column {
+	var columns []Column
+	for _, line := range l.Lines {
+		columns = append(columns, line...)
+	}
+	return columns
+}
+
+// LinesPerFlight returns the number of terminal lines used by each flight
+func (l Layout) LinesPerFlight() int {
+	return max(1, len(l.Lines))
+}
+
+// Width returns the display width of the widest line with the given separator
+func (l Layout) Width(separator string) int {
+	width := 0
+	for i, line := range l.Lines {
+		width = max(width, l.indent(i)+TableWidth(line, separator))
+	}
+	return width
+}
+
+// indent returns the indent of the line at index i
+func (l Layout) indent(i int) int {
+	if i == 0 {
+		return 0
+	}
+	return l.Indent
+}
+
+// joinLines renders each line of cells, indenting continuation lines and
+// padding every line to the layout width so the background is filled evenly
+func (l Layout) joinLines(lines [][]string, styles *SplitFlapStyles) string {
+	width := l.Width(styles.Separator)
+	rendered := make([]string, 0, len(lines))
+	for i, cells := range lines {
+		line := joinCells(cells, styles)
+		if indent := l.indent(i); indent > 0 {
+			line = styles.Text.Render(strings.Repeat(" ", indent)) + line
+		}
+		if padding := width - l.indent(i) - TableWidth(l.Lines[i], styles.Separator); padding > 0 {
+			line += styles.Text.Render(strings.Repeat(" ", padding))
+		}
+		rendered = append(rendered, line)
+	}
+	return strings.Join(rendered, "\n")
+}
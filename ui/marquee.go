@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"github.com/charmbracelet/x/ansi"
+)
+
+// marqueeGap separates the end of scrolling text from its start coming
+// round again
+const marqueeGap = "   •   "
+
+// marqueeHold is the number of steps scrolling text holds still with its
+// start shown, each time round
+const marqueeHold = 8
+
+// Marquee is a line of text shown in a given width. Text that doesn't fit
+// is cut short, or if Scroll is set, scrolls along a cell each step
+type Marquee struct {
+	Text   string
+	Scroll bool
+	offset int // Cells of the text scrolled past
+	held   int // Steps held still at the start of the text so far
+}
+
+// NewMarquee creates a marquee for text, scrolling if scroll is set
+func NewMarquee(text string, scroll bool) *Marquee {
+	return &Marquee{Text: text, Scroll: scroll}
+}
+
+// Scrolling reports whether the marquee scrolls when shown in width cells,
+// and so needs stepping
+func (q *Marquee) Scrolling(width int) bool {
+	return q.Scroll && width > 0 && ansi.StringWidth(q.Text) > width
+}
+
+// Step scrolls the text a cell along if it doesn't fit width, holding it
+// still for a while each time its start comes round
+func (q *Marquee) Step(width int) {
+	if !q.Scrolling(width) {
+		q.offset, q.held = 0, 0
+		return
+	}
+	if q.offset == 0 && q.held < marqueeHold {
+		q.held++
+		return
+	}
+	q.held = 0
+	q.offset = (q.offset + 1) % (ansi.StringWidth(q.Text) + ansi.StringWidth(marqueeGap))
+}
+
+// Render returns the text as shown in width cells: whole if it fits, else
+// the part scrolled to, or cut short with an ellipsis when not scrolling.
+// Scrolled text is padded to exactly width cells, so a wide character
+// cut by either edge doesn't shift what follows
+func (q *Marquee) Render(width int) string {
+	switch {
+	case width <= 0 || ansi.StringWidth(q.Text) <= width:
+		return q.Text
+	case !q.Scroll:
+		return ansi.Truncate(q.Text, width, "…")
+	}
+	loop := q.Text + marqueeGap + q.Text
+	return PadCell(ansi.Cut(loop, q.offset, q.offset+width), width, AlignLeft)
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestMarqueeRender(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		text  string
+		wrap  bool
+		width int
+		want  string
+	}{
+		{"fits", "Gate change", true, 12, "Gate change"},
+		{"no width", "Gate change to B22", false, 0, "Gate change to B22"},
+		{"cut short", "Gate change to B22", false, 10, "Gate chan…"},
+		{"scrolling at its start", "Gate change to B22", true, 10, "Gate chang"},
+	} {
+		if got := NewMarquee(tt.text, tt.wrap).Render(tt.width); got != tt.want {
+			t.Errorf("%s: Render(%d) = %q, want %q", tt.name, tt.width, got, tt.want)
+		}
+	}
+}
+
+// TestMarqueeStep steps scrolling text round once, checking what shows at
+// each step: held at its start, a cell along each step after, the gap
+// between its end and start, then held again
+func TestMarqueeStep(t *testing.T) {
+	q := NewMarquee("ABCDEFGHIJ", true)
+	loop := ansi.StringWidth(q.Text + marqueeGap)
+	want := map[int]string{
+		0:                        "ABCDEF",
+		marqueeHold:              "ABCDEF", // Still held
+		marqueeHold + 1:          "BCDEFG",
+		marqueeHold + 5:          "FGHIJ ",
+		marqueeHold + 10:         "   •  ",
+		marqueeHold + 13:         "•   AB",
+		marqueeHold + loop - 1:   " ABCDE",
+		marqueeHold + loop:       "ABCDEF", // Round again, and held
+		2*marqueeHold + loop:     "ABCDEF",
+		2*marqueeHold + loop + 1: "BCDEFG",
+	}
+	for step := range 2*marqueeHold + loop + 2 {
+		if want, ok := want[step]; ok {
+			if got := q.Render(6); got != want {
+				t.Errorf("step %d: Render = %q, want %q", step, got, want)
+			}
+		}
+		q.Step(6)
+	}
+
+	// Text that fits stops scrolling and starts again from the top
+	q.Step(10)
+	if q.offset != 0 || q.held != 0 || q.Scrolling(10) {
+		t.Errorf("text that fits still scrolling: offset %d, held %d", q.offset, q.held)
+	}
+	if NewMarquee("ABCDEFGHIJ", false).Scrolling(6) {
+		t.Error("marquee not set to scroll scrolling")
+	}
+}
+
+// TestMarqueeWide checks scrolling text cut through a wide character is
+// still padded to the width
+func TestMarqueeWide(t *testing.T) {
+	q := NewMarquee("成田 Narita", true)
+	for step := range marqueeHold + 12 {
+		if got := ansi.StringWidth(q.Render(6)); got != 6 {
+			t.Errorf("step %d: Render = %q, %d cells wide, want 6", step, q.Render(6), got)
+		}
+		q.Step(6)
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+// airportColumn tags each flight of a board merging several airports
+var airportColumn = Column{ID: ColAirport, Name: "AIRPORT", Width: 7}
+
+// SetMergedAirports interleaves the flights of several airports on the
+// board, codes naming them in the order the header lists them. Each flight
+// is tagged with its airport in an AIRPORT column and, in airport time,
+// shown in that airport's timezone as given by zone. Fewer than two codes
+// show a single airport again
+func (b *Board) SetMergedAirports(codes []string, zone func(code string) *time.Location) {
+	b.mergedAirports, b.airportZones = nil, nil
+	if len(codes) >= 2 {
+		b.mergedAirports = append([]string(nil), codes...)
+		b.airportZones = make(map[string]*time.Location, len(codes))
+		for _, code := range codes {
+			b.airportZones[code] = zone(code)
+		}
+	}
+	b.applyLayout()
+}
+
+// FlightAirport returns the airport a flight is listed at: the origin of a
+// departure or the destination of an arrival
+func FlightAirport(flight *models.Flight) string {
+	if flight.Direction == models.Arrival {
+		return strings.TrimSpace(flight.DestinationCode)
+	}
+	return strings.TrimSpace(flight.OriginCode)
+}
+
+// airportOf returns the airport a flight on the board belongs to: its own
+// on a board merging airports, else the board's
+func (b *Board) airportOf(flight *models.Flight) string {
+	if b.mergedAirports != nil {
+		if code := FlightAirport(flight); code != "" {
+			return code
+		}
+	}
+	return b.AirportCode
+}
+
+// zoneFor returns the timezone the times of flight are shown in. In airport
+// time on a board merging airports, that is the timezone of the flight's
+// own airport, so BWI and DEN flights each show their local time
+func (b *Board) zoneFor(flight *models.Flight) *time.Location {
+	if b.TimeZone == TimeAirport && b.airportZones != nil {
+		if zone := b.airportZones[FlightAirport(flight)]; zone != nil {
+			return zone
+		}
+	}
+	return b.displayZone()
+}
+
+// airportLabel returns the airports the header names, e.g. "BWI · DCA"
+func (b *Board) airportLabel() string {
+	if b.mergedAirports != nil {
+		return strings.Join(b.mergedAirports, " · ")
+	}
+	return b.AirportCode
+}
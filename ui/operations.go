@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// AirlineStats returns how each airline of the last update is running,
+// including hidden ones, busiest first
+func (b *Board) AirlineStats() []models.AirlineStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return models.SummarizeAirlines(b.allFlights)
+}
+
+// operationsColumns are the columns of the operations summary
+var operationsColumns = []Column{
+	{Name: "AIRLINE", Width: 24},
+	{Name: "FLIGHTS", Width: 7, Align: AlignRight},
+	{Name: "ON TIME", Width: 7, Align: AlignRight},
+	{Name: "AVG DELAY", Width: 9, Align: AlignRight},
+	{Name: "CXL", Width: 4, Align: AlignRight},
+}
+
+// RenderAirlineStats renders the operations summary, one airline a line,
+// showing height airlines starting offset from the busiest. Figures with
+// nothing to go on, such as the delay of flights without estimates, show
+// as dashes
+func RenderAirlineStats(stats []models.AirlineStats, offset, height int, styles *SplitFlapStyles) string {
+	row := func(cells ...string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			column := operationsColumns[i]
+			padded[i] = PadCell(ansi.Truncate(cell, column.Width, "…"), column.Width, column.Align)
+		}
+		return strings.Join(padded, "  ")
+	}
+	names := make([]string, len(operationsColumns))
+	for i, column := range operationsColumns {
+		names[i] = column.Name
+	}
+
+	lines := []string{styles.AirportLabel.Render("OPERATIONS"), styles.Header.Render(row(names...))}
+	if len(stats) == 0 {
+		lines = append(lines, styles.Text.Render("No flights yet"))
+	}
+	for i := offset; i < len(stats) && i < offset+height; i++ {
+		s := stats[i]
+		airline := s.Key
+		if airline == "" {
+			airline = "—"
+		}
+		if s.Name != "" && s.Name != s.Key {
+			airline = fmt.Sprintf("%-3s %s", airline, s.Name)
+		}
+		onTime, delay := "—", "—"
+		if percent, ok := s.OnTimePercent(); ok {
+			onTime = fmt.Sprintf("%.0f%%", percent)
+		}
+		if average, ok := s.AverageDelay(); ok {
+			delay = fmt.Sprintf("%d min", int(average.Round(time.Minute).Minutes()))
+		}
+		lines = append(lines, styles.Text.Render(row(airline, fmt.Sprint(s.Flights), onTime, delay, fmt.Sprint(s.Cancelled))))
+	}
+
+	footer := fmt.Sprintf("%d airlines | ↑/↓ to scroll | 'O' or Esc to close", len(stats))
+	lines = append(lines, styles.PageInfo.Render(footer))
+	return styles.Background.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Overlay is a screen drawn over the frame beneath it, such as a prompt or
+// a modal panel
+type Overlay interface {
+	// RenderOver draws the overlay over base, a frame of the given size
+	RenderOver(base string, width, height int) string
+}
+
+// ScreenStack layers overlays over a base screen. The most recently pushed
+// overlay is drawn last and receives input first. Overlays never modify the
+// base, so popping one shows the frame beneath it exactly as before
+type ScreenStack struct {
+	overlays []Overlay
+}
+
+// Push adds an overlay on top of the stack
+func (s *ScreenStack) Push(overlay Overlay) {
+	s.overlays = append(s.overlays, overlay)
+}
+
+// Pop removes and returns the top overlay, or nil if the stack is empty
+func (s *ScreenStack) Pop() Overlay {
+	if len(s.overlays) == 0 {
+		return nil
+	}
+	top := s.overlays[len(s.overlays)-1]
+	s.overlays = s.overlays[:len(s.overlays)-1]
+	return top
+}
+
+// Top returns the top overlay, or nil if the stack is empty
+func (s *ScreenStack) Top() Overlay {
+	if len(s.overlays) == 0 {
+		return nil
+	}
+	return s.overlays[len(s.overlays)-1]
+}
+
+// Len returns the number of overlays on the stack
+func (s *ScreenStack) Len() int {
+	return len(s.overlays)
+}
+
+// Render draws every overlay over base in stack order
+func (s *ScreenStack) Render(base string, width, height int) string {
+	frame := base
+	for _, overlay := range s.overlays {
+		frame = overlay.RenderOver(frame, width, height)
+	}
+	return frame
+}
+
+// PlaceModal draws box centered over a dimmed copy of base. The frame is at
+// least width by height, growing to fit base and box when they are larger
+func PlaceModal(base, box string, width, height int, styles *SplitFlapStyles) string {
+	width = max(width, lipgloss.Width(base), lipgloss.Width(box))
+	height = max(height, lipgloss.Height(base), lipgloss.Height(box))
+
+	// The background keeps its text but loses its colors so the box stands out
+	plain := strings.Split(ansi.Strip(base), "\n")
+	for len(plain) < height {
+		plain = append(plain, "")
+	}
+	lines := make([]string, len(plain))
+	for i, line := range plain {
+		plain[i] = padRight(line, width)
+		lines[i] = styles.Dimmed.Render(plain[i])
+	}
+
+	boxLines := strings.Split(box, "\n")
+	boxWidth := lipgloss.Width(box)
+	top := (height - len(boxLines)) / 2
+	left := (width - boxWidth) / 2
+	for i, boxLine := range boxLines {
+		row := top + i
+		lines[row] = styles.Dimmed.Render(ansi.Cut(plain[row], 0, left)) +
+			padRight(boxLine, boxWidth) +
+			styles.Dimmed.Render(ansi.Cut(plain[row], left+boxWidth, width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// padRight pads s with spaces to the given display width
+func padRight(s string, width int) string {
+	if padding := width - ansi.StringWidth(s); padding > 0 {
+		return s + strings.Repeat(" ", padding)
+	}
+	return s
+}
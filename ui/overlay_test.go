@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// boxOverlay draws a labelled box in a modal over the frame beneath it
+type boxOverlay struct {
+	label  string
+	styles *SplitFlapStyles
+}
+
+func (o *boxOverlay) RenderOver(base string, width, height int) string {
+	return PlaceModal(base, "["+o.label+"]", width, height, o.styles)
+}
+
+func TestScreenStack(t *testing.T) {
+	var stack ScreenStack
+	if stack.Top() != nil || stack.Pop() != nil || stack.Len() != 0 {
+		t.Fatal("empty stack has an overlay")
+	}
+	first := &boxOverlay{label: "first", styles: NewSplitFlapStyles()}
+	second := &boxOverlay{label: "second", styles: NewSplitFlapStyles()}
+	stack.Push(first)
+	stack.Push(second)
+	if stack.Len() != 2 || stack.Top() != second {
+		t.Fatalf("Len = %d and Top = %v after two pushes, want 2 and the second", stack.Len(), stack.Top())
+	}
+	if got := stack.Pop(); got != second {
+		t.Errorf("Pop = %v, want the second overlay", got)
+	}
+	if got := stack.Pop(); got != first {
+		t.Errorf("Pop = %v, want the first overlay", got)
+	}
+	if stack.Len() != 0 {
+		t.Errorf("Len = %d after popping both, want 0", stack.Len())
+	}
+}
+
+// TestScreenStackRestoresFrame checks that popping an overlay gives back the
+// frame drawn before it was pushed, byte for byte
+func TestScreenStackRestoresFrame(t *testing.T) {
+	board := newTestBoard(5)
+	board.UpdateFlights(testFlights(3, time.Now()))
+	settle(t, board)
+	base := board.Render()
+
+	var stack ScreenStack
+	frames := []string{stack.Render(base, 80, 24)}
+	for _, label := range []string{"help", "log", "prompt"} {
+		stack.Push(&boxOverlay{label: label, styles: board.Styles})
+		frame := stack.Render(base, 80, 24)
+		if frame == frames[len(frames)-1] {
+			t.Errorf("pushing %s left the frame as it was", label)
+		}
+		if !strings.Contains(ansi.Strip(frame), "["+label+"]") {
+			t.Errorf("frame with %s doesn't show it:\n%s", label, ansi.Strip(frame))
+		}
+		frames = append(frames, frame)
+	}
+	for len(frames) > 1 {
+		frames = frames[:len(frames)-1]
+		stack.Pop()
+		if got := stack.Render(base, 80, 24); got != frames[len(frames)-1] {
+			t.Errorf("frame with %d overlays after popping differs:\n%s\nwant\n%s", stack.Len(), got, frames[len(frames)-1])
+		}
+	}
+	if got := stack.Render(base, 80, 24); got != base {
+		t.Errorf("empty stack changed the base frame")
+	}
+}
+
+func TestPlaceModal(t *testing.T) {
+	base := "top\nmiddle line\nbottom"
+	frame := PlaceModal(base, "BOX", 11, 5, NewSplitFlapStyles())
+	want := []string{
+		"top        ",
+		"middle line",
+		"bottBOX    ", // The background shows either side of the box
+		"           ",
+		"           ",
+	}
+	if got := strings.Split(ansi.Strip(frame), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("PlaceModal =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A box larger than the frame grows it
+	frame = PlaceModal("x", "wide box\nof two lines", 4, 1, NewSplitFlapStyles())
+	if lines := strings.Split(ansi.Strip(frame), "\n"); len(lines) != 2 || ansi.StringWidth(lines[0]) != 12 {
+		t.Errorf("PlaceModal of a large box =\n%s\nwant 2 lines 12 wide", ansi.Strip(frame))
+	}
+}
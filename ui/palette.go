@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palettes are the built-in status light colors selectable with PALETTE, by
+// the color names of models.Flight.GetStatusColor. colorblind uses the
+// Okabe-Ito colors, which stay apart for the common forms of color
+// blindness: sky blue for on time where the default has green, orange for
+// delays and vermillion for cancellations
+var palettes = map[string]map[string]lipgloss.Color{
+	"default": {
+		"green":  "#00ff00",
+		"yellow": "#ffff00",
+		"orange": "#ff8800",
+		"red":    "#ff0000",
+		"blue":   "#00aaff",
+	},
+	"colorblind": {
+		"green":  "#56b4e9", // Sky blue
+		"yellow": "#f0e442",
+		"orange": "#e69f00",
+		"red":    "#d55e00", // Vermillion
+		"blue":   "#cc79a7", // Reddish purple, as the sky blue is taken
+	},
+}
+
+// unknownColor is the color of statuses no palette names
+const unknownColor = lipgloss.Color("#ffffff")
+
+// Palette holds the colors of the status lights
+type Palette struct {
+	colors map[string]lipgloss.Color
+}
+
+// DefaultPalette returns the board's original colors
+func DefaultPalette() *Palette {
+	return &Palette{colors: palettes["default"]}
+}
+
+// ParsePalette returns the colors of a PALETTE preset: default or colorblind
+func ParsePalette(preset string) (*Palette, error) {
+	name := strings.ToLower(strings.TrimSpace(preset))
+	if name == "" {
+		name = "default"
+	}
+	colors, ok := palettes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown palette %q (expected %s)", preset, palettePresetNames())
+	}
+	return &Palette{colors: colors}, nil
+}
+
+// Color returns the color of the status light named color, white for names
+// the palette doesn't have
+func (p *Palette) Color(color string) lipgloss.Color {
+	if c, ok := p.colors[color]; ok {
+		return c
+	}
+	return unknownColor
+}
+
+// StatusLight returns the style of a status light of the named color
+func (p *Palette) StatusLight(color string) lipgloss.Style {
+	return lipgloss.NewStyle().
+		Foreground(p.Color(color)).
+		Bold(true)
+}
+
+// SetPalette colors the status lights with palette, unless the board has
+// the mono style set
+func (b *Board) SetPalette(palette *Palette) {
+	if b.StyleSet == StylesMono {
+		return
+	}
+	b.Styles.StatusLight = palette.StatusLight
+}
+
+// palettePresetNames lists the preset names for error messages
+func palettePresetNames() string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
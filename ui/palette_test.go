@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestPaletteStatusLights checks that in every palette and glyph preset each
+// status has a light of its own: statuses sharing a color, like the two
+// delays, differ by their glyph, and every color the palette names is apart
+// from the others and from the color of unknown statuses
+func TestPaletteStatusLights(t *testing.T) {
+	for name := range palettes {
+		palette, err := ParsePalette(name)
+		if err != nil {
+			t.Fatalf("ParsePalette(%q): %v", name, err)
+		}
+		colors := map[lipgloss.Color]string{unknownColor: "unknown"}
+		for color := range palette.colors {
+			c := palette.Color(color)
+			if other, ok := colors[c]; ok {
+				t.Errorf("%s palette: %s and %s are both %s", name, color, other, c)
+			}
+			colors[c] = color
+			if got := palette.StatusLight(color).GetForeground(); got != c {
+				t.Errorf("%s palette: %s light is %v, want %s", name, color, got, c)
+			}
+		}
+
+		for preset := range glyphPresets {
+			glyphs, err := ParseGlyphSet(preset, nil)
+			if err != nil {
+				t.Fatalf("ParseGlyphSet(%q): %v", preset, err)
+			}
+			lights := make(map[string]models.FlightStatus)
+			for status := range glyphPresets[preset] {
+				if status == models.StatusUnknown {
+					continue
+				}
+				flight := models.Flight{Status: status}
+				color := palette.Color(flight.GetStatusColor())
+				if color == unknownColor {
+					t.Errorf("%s palette: %s has no color", name, status)
+				}
+				light := glyphs.Status(status) + " " + string(color)
+				if other, ok := lights[light]; ok {
+					t.Errorf("%s palette, %s glyphs: %s and %s both show %q", name, preset, status, other, light)
+				}
+				lights[light] = status
+			}
+		}
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	for _, tt := range []struct {
+		preset  string
+		want    string // Color of an on time flight
+		wantErr string
+	}{
+		{"", "#00ff00", ""},
+		{"default", "#00ff00", ""},
+		{" ColorBlind ", "#56b4e9", ""},
+		{"rainbow", "", `unknown palette "rainbow" (expected colorblind, default)`},
+		{"color-blind", "", `unknown palette "color-blind"`},
+	} {
+		palette, err := ParsePalette(tt.preset)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParsePalette(%q) error = %v, want %q", tt.preset, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || string(palette.Color("green")) != tt.want {
+			t.Errorf("ParsePalette(%q) = %v, %v; want green %s", tt.preset, palette, err, tt.want)
+		}
+		if got := palette.Color("white"); got != unknownColor {
+			t.Errorf("ParsePalette(%q): white is %s, want the unknown color", tt.preset, got)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"slices"
+	"time"
+)
+
+// orderPages works out the order rows are paged in. With PinImminent on a
+// departures board, the first page holds the next flights to depart, soonest
+// first, whatever the view's order; the other pages hold the remaining rows
+// in board order, so no flight appears twice. Watched flights the sidebar
+// has no room for lead the first page, ahead of the imminent ones. The
+// shuttle view pages by destination. Otherwise pages follow the board order
+func (b *Board) orderPages(now time.Time) {
+	b.pageOrder = nil
+	b.watchPinned = false
+	if b.shuttleMode() {
+		b.orderShuttles()
+		return
+	}
+	rows := b.Rows()
+	watched := b.watching() && !b.sidebarShown()
+	if !b.PinImminent && !watched || len(rows) <= b.perPage() {
+		return
+	}
+
+	var first []*FlightRow
+	if watched {
+		first = b.watchedRows(now)
+		b.watchPinned = len(first) > 0
+	}
+	pinned := make(map[*FlightRow]bool, len(rows))
+	for _, row := range first {
+		pinned[row] = true
+	}
+	if b.PinImminent {
+		imminent := make([]*FlightRow, 0, len(rows))
+		departs := make(map[*FlightRow]time.Time, len(rows))
+		for _, row := range rows {
+			if t, ok := departsAfter(row.Flight, now); ok && !pinned[row] {
+				imminent = append(imminent, row)
+				departs[row] = t
+			}
+		}
+		slices.SortStableFunc(imminent, func(a, b *FlightRow) int {
+			return departs[a].Compare(departs[b])
+		})
+		first = append(first, imminent...)
+	}
+	if len(first) == 0 {
+		return
+	}
+	first = first[:min(len(first), b.perPage())]
+
+	pinned = make(map[*FlightRow]bool, len(first))
+	for _, row := range first {
+		pinned[row] = true
+	}
+	order := make([]*FlightRow, 0, len(rows))
+	order = append(order, first...)
+	for _, row := range rows {
+		if !pinned[row] {
+			order = append(order, row)
+		}
+	}
+	b.pageOrder = order
+}
+
+// pagedRows returns the rows in the order they are paged in
+func (b *Board) pagedRows() []*FlightRow {
+	if b.pageOrder != nil {
+		return b.pageOrder
+	}
+	return b.Rows()
+}
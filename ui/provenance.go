@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// Provenance describes where the flights on a board came from
+type Provenance struct {
+	Source    string    // Name of the data source, e.g. "FlightAware"
+	FetchedAt time.Time // When the flights were fetched
+	Cached    bool      // Loaded from the board cache rather than fetched for this board
+	Simulated bool      // Generated or replayed data rather than a live feed
+	// RecordedAt is when replayed flights were recorded, shown in the header;
+	// zero for live data
+	RecordedAt time.Time
+}
+
+// Label returns the source and fetch time shown in the status bar, e.g.
+// "FlightAware • 14:32", with the time in loc if it is set
+func (p Provenance) Label(loc *time.Location) string {
+	label := p.Source
+	if !p.FetchedAt.IsZero() {
+		fetched := p.FetchedAt
+		if loc != nil {
+			fetched = fetched.In(loc)
+		}
+		label += " • " + fetched.Format("15:04")
+	}
+	if p.Cached {
+		label += " (cached)"
+	}
+	return label
+}
+
+// Badge returns the text marking simulated data, e.g. "DEMO DATA"
+func (p Provenance) Badge() string {
+	return strings.ToUpper(p.Source) + " DATA"
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestProvenanceStatusBar(t *testing.T) {
+	fetched := time.Date(2026, time.January, 1, 19, 32, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		provenance Provenance
+		want       string
+		badge      bool
+	}{
+		{"live", Provenance{Source: "FlightAware", FetchedAt: fetched}, "FlightAware • 14:32", false},
+		{"cached", Provenance{Source: "OpenSky", FetchedAt: fetched, Cached: true}, "OpenSky • 14:32 (cached)", false},
+		{"demo", Provenance{Source: "Demo", FetchedAt: fetched, Simulated: true}, "DEMO DATA", true},
+		{"replay", Provenance{Source: "Replay", FetchedAt: fetched, Simulated: true}, "REPLAY DATA", true},
+		{"unknown source", Provenance{}, "", false},
+	}
+
+	// Colors are on so the badge can be told apart from the plain label
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := NewBoard("JFK", newYork, 5)
+			board.Provenance = tt.provenance
+			bar := board.renderStatusBar(fetched)
+			if got := strings.TrimSpace(ansi.Strip(bar)); got != tt.want {
+				t.Errorf("status bar %q, want %q", got, tt.want)
+			}
+			// The badge has a background of its own; the label is plain text
+			if got := strings.Contains(bar, "[48;5;"); got != tt.badge {
+				t.Errorf("status bar has a background: %v, want %v: %q", got, tt.badge, bar)
+			}
+			if tt.badge && strings.Contains(ansi.Strip(bar), "•") {
+				t.Errorf("simulated data shows a fetch time like a live feed: %q", ansi.Strip(bar))
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"fids-tui/models"
+)
+
+// remarkStatusKeys maps the status names used in REMARK_TEMPLATES to statuses
+var remarkStatusKeys = map[string]models.FlightStatus{
+	"on_time":         models.StatusOnTime,
+	"delayed":         models.StatusDelayed,
+	"taxiing":         models.StatusTaxiingLeftGate,
+	"taxiing_delayed": models.StatusTaxiingDelayed,
+	"cancelled":       models.StatusCancelled,
+	"departed":        models.StatusDeparted,
+	"arrived":         models.StatusArrived,
+	"unknown":         models.StatusUnknown,
+}
+
+// defaultRemarkTemplates reproduces the built-in remarks for each status
+var defaultRemarkTemplates = map[models.FlightStatus]string{
+	models.StatusOnTime:          "On Time",
+	models.StatusDelayed:         `Delayed{{if .HasEst}} EST: {{.Est "15:04"}}{{end}}`,
+	models.StatusTaxiingLeftGate: `Taxiing{{if .HasOut}} {{.Taxi}}{{else}} / Left Gate{{end}}`,
+	models.StatusTaxiingDelayed:  `Taxiing{{if .HasOut}} {{.Taxi}}{{end}} / Delayed`,
+	models.StatusCancelled:       "Cancelled",
+	models.StatusDeparted:        `Departed{{if .HasOff}} {{.Off "15:04"}}{{end}}`,
+	models.StatusArrived:         "Arrived",
+	models.StatusUnknown:         "",
+}
+
+// RemarkData is the value templates are executed against
+// Flight fields are available directly (e.g., {{.Gate}}), and times are
+// formatted in the airport timezone with Go layouts (e.g., {{.Est "15:04"}})
+type RemarkData struct {
+	*models.Flight
+	tz  *time.Location
+	now time.Time
+}
+
+// HasEst reports whether the flight has an estimated departure (or arrival) time
+func (d RemarkData) HasEst() bool {
+	return d.EstimatedTime() != nil
+}
+
+// HasOut reports whether the flight has left the gate at a known time
+func (d RemarkData) HasOut() bool {
+	return d.ActualOut != nil
+}
+
+// HasOff reports whether the flight has an observed wheels-up time
+func (d RemarkData) HasOff() bool {
+	return d.ActualOff != nil
+}
+
+// Sched formats the scheduled departure (or arrival) time
+func (d RemarkData) Sched(layout string) string {
+	scheduled := d.ScheduledTime()
+	return d.format(&scheduled, layout)
+}
+
+// Est formats the estimated departure (or arrival) time, or returns an empty string if unknown
+func (d RemarkData) Est(layout string) string {
+	return d.format(d.EstimatedTime(), layout)
+}
+
+// Out formats the time the flight left the gate, or returns an empty string if unknown
+func (d RemarkData) Out(layout string) string {
+	return d.format(d.ActualOut, layout)
+}
+
+// Taxi returns how long ago the flight left the gate, e.g. "12m" or "1h05m",
+// or an empty string if unknown
+func (d RemarkData) Taxi() string {
+	if d.ActualOut == nil {
+		return ""
+	}
+	return formatElapsed(taxiTime(d.Flight, d.now))
+}
+
+// Off formats the observed wheels-up time, or returns an empty string if unknown
+func (d RemarkData) Off(layout string) string {
+	return d.format(d.ActualOff, layout)
+}
+
+func (d RemarkData) format(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+	if d.tz != nil {
+		return t.In(d.tz).Format(layout)
+	}
+	return t.Format(layout)
+}
+
+// RemarkTemplates renders the remarks text shown for each flight status
+type RemarkTemplates struct {
+	templates map[models.FlightStatus]*template.Template
+}
+
+// DefaultRemarkTemplates returns the built-in remark templates
+func DefaultRemarkTemplates() *RemarkTemplates {
+	rt, err := ParseRemarkTemplates(nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default remark templates: %v", err))
+	}
+	return rt
+}
+
+// ParseRemarkTemplates compiles remark templates keyed by status name
+// (on_time, delayed, taxiing, taxiing_delayed, cancelled, departed,
+// arrived, unknown),
+// using the defaults for any status not overridden
+func ParseRemarkTemplates(overrides map[string]string) (*RemarkTemplates, error) {
+	sources := make(map[models.FlightStatus]string, len(defaultRemarkTemplates))
+	for status, text := range defaultRemarkTemplates {
+		sources[status] = text
+	}
+
+	for key, text := range overrides {
+		status, ok := remarkStatusKeys[strings.ToLower(strings.TrimSpace(key))]
+		if !ok {
+			return nil, fmt.Errorf("unknown remark template status %q (expected one of %s)", key, remarkStatusNames())
+		}
+		sources[status] = text
+	}
+
+	rt := &RemarkTemplates{templates: make(map[models.FlightStatus]*template.Template, len(sources))}
+	for status, text := range sources {
+		tmpl, err := template.New(status.String()).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remark template for %s: %w", status, err)
+		}
+		rt.templates[status] = tmpl
+	}
+
+	// Execute every template against sample flights with every field known
+	// and with none known, so that references to unknown fields in either
+	// branch of an {{if}}, or to times a flight may not have, are reported at
+	// startup rather than on the board
+	for status, tmpl := range rt.templates {
+		for _, sample := range remarkSamples() {
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, RemarkData{Flight: &sample.flight, tz: time.UTC, now: remarkSampleTime}); err != nil {
+				return nil, fmt.Errorf("invalid remark template for %s (executing for %s): %w", status, sample.desc, err)
+			}
+		}
+	}
+
+	return rt, nil
+}
+
+// remarkSampleTime is the time the sample flights are scheduled for
+var remarkSampleTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// remarkSample is a flight templates are checked against, and how an error
+// describes it
+type remarkSample struct {
+	desc   string
+	flight models.Flight
+}
+
+// remarkSamples returns a departure and an arrival with every field set, and
+// ones with only their schedule
+func remarkSamples() []remarkSample {
+	at := remarkSampleTime
+	later := at.Add(25 * time.Minute)
+	full := models.Flight{
+		ID:                 "AAL100-1704067200-schedule-0001",
+		InboundID:          "AAL99-1704045600-schedule-0001",
+		Ident:              "AAL100",
+		AirlineCode:        "AA",
+		AirlineName:        "American Airlines",
+		FlightNumber:       "AA 100",
+		DestinationCode:    "LHR",
+		DestinationCity:    "London",
+		OriginCode:         "JFK",
+		OriginCity:         "New York",
+		Gate:               "B22",
+		BaggageClaim:       "4",
+		Remarks:            models.RemarksDelayed,
+		ScheduledDeparture: at,
+		EstimatedDeparture: &later,
+		ActualOut:          &later,
+		ActualOff:          &later,
+		ScheduledArrival:   at.Add(7 * time.Hour),
+		EstimatedArrival:   &later,
+	}
+	fullArrival := full
+	fullArrival.Direction = models.Arrival
+	return []remarkSample{
+		{"a departure with every field known", full},
+		{"an arrival with every field known", fullArrival},
+		{"a departure with only its schedule known", models.Flight{FlightNumber: "AA 100", ScheduledDeparture: at}},
+		{"an arrival with only its schedule known", models.Flight{FlightNumber: "AA 100", Direction: models.Arrival, ScheduledArrival: at}},
+	}
+}
+
+// Render returns the remarks for a flight at now, formatting times in tz
+func (rt *RemarkTemplates) Render(flight *models.Flight, tz *time.Location, now time.Time) models.Remarks {
+	tmpl, ok := rt.templates[flight.Status]
+	if !ok {
+		return models.Remarks(flight.Status.String())
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, RemarkData{Flight: flight, tz: tz, now: now}); err != nil {
+		// Fall back to the plain status rather than showing a broken remark
+		return models.Remarks(flight.Status.String())
+	}
+	return models.Remarks(sb.String())
+}
+
+// taxiTime returns how long a flight that left the gate at a known time has
+// been taxiing at now, in whole minutes
+func taxiTime(flight *models.Flight, now time.Time) time.Duration {
+	return max(0, now.Sub(*flight.ActualOut).Truncate(time.Minute))
+}
+
+// taxiTimeChanges returns when the taxi time of flight next ticks over, or
+// the zero time if the flight isn't taxiing or the time isn't known
+func taxiTimeChanges(flight *models.Flight, now time.Time) time.Time {
+	taxiing := flight.Status == models.StatusTaxiingLeftGate || flight.Status == models.StatusTaxiingDelayed
+	if !taxiing || flight.ActualOut == nil {
+		return time.Time{}
+	}
+	return flight.ActualOut.Add(taxiTime(flight, now) + time.Minute)
+}
+
+// formatElapsed formats a whole-minute duration as "12m" or "1h05m"
+func formatElapsed(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// remarkStatusNames returns the accepted status keys for error messages
+func remarkStatusNames() string {
+	names := make([]string, 0, len(remarkStatusKeys))
+	for name := range remarkStatusKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// TestDefaultRemarkTemplates checks the default templates reproduce the
+// remarks the board showed before they could be configured
+func TestDefaultRemarkTemplates(t *testing.T) {
+	tz, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduled := time.Date(2026, time.March, 2, 19, 0, 0, 0, time.UTC) // 14:00 in New York
+	estimate := scheduled.Add(47 * time.Minute)
+	out := scheduled.Add(-5 * time.Minute)
+	off := scheduled.Add(12 * time.Minute)
+	now := scheduled.Add(13*time.Minute + 30*time.Second)
+
+	tests := []struct {
+		name   string
+		flight models.Flight
+		want   models.Remarks
+	}{
+		{"on time", models.Flight{Status: models.StatusOnTime}, models.RemarksOnTime},
+		{"delayed", models.Flight{Status: models.StatusDelayed}, models.RemarksDelayed},
+		{"delayed with estimate", models.Flight{Status: models.StatusDelayed, EstimatedDeparture: &estimate}, "Delayed EST: 14:47"},
+		{"delayed arrival with estimate", models.Flight{Status: models.StatusDelayed, Direction: models.Arrival, EstimatedArrival: &estimate}, "Delayed EST: 14:47"},
+		{"taxiing", models.Flight{Status: models.StatusTaxiingLeftGate}, models.RemarksTaxiingLeftGate},
+		{"taxiing with gate time", models.Flight{Status: models.StatusTaxiingLeftGate, ActualOut: &out}, "Taxiing 18m"},
+		{"taxiing delayed", models.Flight{Status: models.StatusTaxiingDelayed}, models.RemarksTaxiingDelayed},
+		{"taxiing delayed with gate time", models.Flight{Status: models.StatusTaxiingDelayed, ActualOut: &out}, "Taxiing 18m / Delayed"},
+		{"cancelled", models.Flight{Status: models.StatusCancelled}, models.RemarksCancelled},
+		{"departed", models.Flight{Status: models.StatusDeparted}, models.RemarksDeparted},
+		{"departed with wheels up", models.Flight{Status: models.StatusDeparted, ActualOff: &off}, "Departed 14:12"},
+		{"arrived", models.Flight{Status: models.StatusArrived}, models.RemarksArrived},
+		{"unknown", models.Flight{Status: models.StatusUnknown}, ""},
+	}
+	templates := DefaultRemarkTemplates()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.flight.ScheduledDeparture = scheduled
+			if got := templates.Render(&tt.flight, tz, now); got != tt.want {
+				t.Errorf("remarks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRemarkTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		wantErr   string // Empty when the templates are valid
+	}{
+		{"defaults", nil, ""},
+		{"override", map[string]string{"Delayed ": `WIELKIE OPÓŹNIENIE{{if .HasEst}} {{.Est "15:04"}}{{end}}`}, ""},
+		{"guarded time", map[string]string{"delayed": `{{if .EstimatedDeparture}}{{.EstimatedDeparture.Format "15:04"}}{{end}}`}, ""},
+		{"unknown status", map[string]string{"boarding": "Boarding"}, `unknown remark template status "boarding"`},
+		{"bad syntax", map[string]string{"delayed": "{{if .HasEst}}"}, "invalid remark template for Delayed"},
+		{"unknown field", map[string]string{"on_time": "{{.Terminal}}"}, "can't evaluate field Terminal"},
+		// Only reached for flights without an estimate
+		{"unknown field in else", map[string]string{"delayed": `{{if .HasEst}}{{.Est "15:04"}}{{else}}{{.Reason}}{{end}}`}, "can't evaluate field Reason"},
+		// Only reached for arrivals
+		{"unknown field for arrivals", map[string]string{"arrived": `{{if .EstimatedArrival}}{{.Belt}}{{end}}`}, "can't evaluate field Belt"},
+		{"unguarded time", map[string]string{"delayed": `{{.EstimatedDeparture.Format "15:04"}}`}, "only its schedule known"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRemarkTemplates(tt.overrides)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("no error, want one containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("error %q, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestTaxiTimeCounts checks a taxiing flight's time since it left the gate
+// counts up on the clock tick
+func TestTaxiTimeCounts(t *testing.T) {
+	now := time.Now()
+	out := now.Add(-12 * time.Minute)
+	flights := testFlights(1, now)
+	flights[0].Status = models.StatusTaxiingLeftGate
+	flights[0].ActualOut = &out
+	board := newTestBoard(3)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	if got := board.Rows()[0].Flight.Remarks; got != "Taxiing 12m" {
+		t.Fatalf("remarks %q, want Taxiing 12m", got)
+	}
+	if board.RefreshRemarks(now.Add(30 * time.Second)) {
+		t.Error("remarks changed within the minute")
+	}
+	if !board.RefreshRemarks(now.Add(time.Minute)) {
+		t.Error("remarks unchanged a minute later")
+	}
+	if got := board.Rows()[0].Flight.Remarks; got != "Taxiing 13m" {
+		t.Errorf("remarks a minute later %q, want Taxiing 13m", got)
+	}
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+// dayOffsets returns, for each of flights, how many days after the first
+// flight of the same number on the board it is scheduled, by the dates in
+// the zone zoneOf gives. With a lookahead past midnight a daily flight can
+// appear twice, today's and tomorrow's; the later one is marked so the two
+// rows don't look the same
+func dayOffsets(flights []models.Flight, zoneOf func(*models.Flight) *time.Location) []int {
+	offsets := make([]int, len(flights))
+	first := make(map[string]time.Time, len(flights))
+	days := make([]time.Time, len(flights))
+	for i := range flights {
+		scheduled := flights[i].ScheduledTime()
+		if scheduled.IsZero() || flights[i].Blocked {
+			continue // Blocked flights have no number to repeat
+		}
+		local := scheduled.In(zoneOf(&flights[i]))
+		days[i] = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+		number := strings.TrimSpace(flights[i].FlightNumber)
+		if earliest, ok := first[number]; !ok || days[i].Before(earliest) {
+			first[number] = days[i]
+		}
+	}
+	for i := range flights {
+		if days[i].IsZero() {
+			continue
+		}
+		earliest := first[strings.TrimSpace(flights[i].FlightNumber)]
+		offsets[i] = int(days[i].Sub(earliest) / (24 * time.Hour))
+	}
+	return offsets
+}
+
+// dayMarker returns the marker shown after the time of a flight scheduled
+// offset days after the first flight of its number, e.g. "+1", or "" for
+// the first
+func dayMarker(offset int) string {
+	if offset <= 0 {
+		return ""
+	}
+	return "+" + strconv.Itoa(offset)
+}
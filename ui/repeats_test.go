@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// midnightFlights returns the flights of testdata/midnight.json: a JFK
+// evening into the next night, with AA 10 leaving at 23:55 on both days,
+// listed out of order
+func midnightFlights(t *testing.T) []models.Flight {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "midnight.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flights []models.Flight
+	if err := json.Unmarshal(data, &flights); err != nil {
+		t.Fatal(err)
+	}
+	return flights
+}
+
+func TestDayOffsets(t *testing.T) {
+	flights := midnightFlights(t)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		zone *time.Location
+		want []int
+	}{
+		// Only tomorrow's AA 10 is a repeat; UA 9, first seen after
+		// midnight, and the blocked flights, which have no number, aren't
+		{newYork, []int{1, 0, 0, 0, 0, 0, 0}},
+		// The same in UTC, where the whole evening falls on the next day
+		{time.UTC, []int{1, 0, 0, 0, 0, 0, 0}},
+	} {
+		got := dayOffsets(flights, func(*models.Flight) *time.Location { return tt.zone })
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: dayOffsets = %v, want %v", tt.zone, got, tt.want)
+		}
+	}
+	if got := dayOffsets(nil, nil); len(got) != 0 {
+		t.Errorf("dayOffsets(nil) = %v, want none", got)
+	}
+}
+
+// TestDayMarkers renders the midnight fixture, checking only the second
+// AA 10 has its time marked, however the board is redrawn
+func TestDayMarkers(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := NewBoard("JFK", newYork, 10)
+	board.Animation = AnimationTiming{}
+	board.UpdateFlights(midnightFlights(t))
+	settle(t, board)
+
+	check := func(when, want string) {
+		t.Helper()
+		var marked []string
+		for _, line := range renderedLines(board) {
+			if strings.Contains(line, "+1") {
+				marked = append(marked, strings.Join(strings.Fields(line), " "))
+			}
+		}
+		if len(marked) != 1 || !strings.Contains(marked[0], "AA 10 "+want+" +1") {
+			t.Errorf("%s: marked rows %q, want AA 10 at %s +1", when, marked, want)
+		}
+	}
+	check("airport time", "23:55")
+	// Rows rebuilt for a narrower layout keep their markers
+	board.SetTerminalSize(60, 40)
+	settle(t, board)
+	check("narrow", "23:55")
+	board.SetTimeZoneMode(TimeUTC)
+	settle(t, board)
+	check("UTC", "04:55")
+}
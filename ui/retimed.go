@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"time"
+
+	"fids-tui/models"
+)
+
+// retime records a flight whose scheduled time moved, shown in its remarks
+// for a few updates so the old time isn't lost
+type retime struct {
+	from    time.Time // Scheduled time before the change
+	updates int       // Updates left showing the remark, this one included
+}
+
+// trackRetimes notes the flights whose scheduled time moved between the
+// flights of the last update and those of this one, and counts down those
+// retimed earlier. A flight without an ID is remembered by its number and
+// scheduled time, so its NEW badge moves to its new time with it
+func (b *Board) trackRetimes(old, flights []models.Flight) {
+	retimed := make(map[string]retime)
+	diff := models.DiffFlights(old, flights)
+	for _, change := range diff.Matched {
+		from, to := seenKey(&old[change.Old]), seenKey(&flights[change.New])
+		if _, ok := change.Field(models.FieldScheduled); ok {
+			if b.RetimedFor > 0 {
+				retimed[to] = retime{from: old[change.Old].ScheduledTime(), updates: b.RetimedFor}
+			}
+			b.Seen.rekey(from, to)
+			continue
+		}
+		if r, ok := b.retimed[from]; ok && r.updates > 1 {
+			r.updates--
+			retimed[to] = r
+		}
+	}
+	b.retimed = retimed
+}
+
+// retimedRemarks returns the remarks of a retimed flight, e.g. "Retimed from
+// 14:20", with its estimate if it is also delayed, e.g. "Retimed from 14:20
+// EST 15:10". Flights that aren't retimed, or whose status says more, such
+// as cancelled or departed, keep the remarks of their status
+func (b *Board) retimedRemarks(flight *models.Flight, zone *time.Location, remarks models.Remarks) models.Remarks {
+	r, ok := b.retimed[seenKey(flight)]
+	if !ok {
+		return remarks
+	}
+	text := "Retimed from " + r.from.In(zone).Format("15:04")
+	switch flight.Status {
+	case models.StatusOnTime, models.StatusUnknown:
+		return models.Remarks(text)
+	case models.StatusDelayed:
+		if est := flight.EstimatedTime(); est != nil {
+			return models.Remarks(text + " EST " + est.In(zone).Format("15:04"))
+		}
+		return models.Remarks(text + " / Delayed")
+	default:
+		return remarks
+	}
+}
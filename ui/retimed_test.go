@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// TestRetimedRemarks retimes flights half an hour later, some of them also
+// delayed past their new time, checking their remarks give the old time with
+// the delay when both apply, for RetimedFor updates, and that the change log
+// records the retime and then the delay
+func TestRetimedRemarks(t *testing.T) {
+	now := time.Now()
+	flights := testFlights(5, now)
+	board := newTestBoard(10)
+	board.RetimedFor = 2
+	board.UpdateFlights(flights)
+	settle(t, board)
+
+	clock := func(t time.Time) string { return t.In(time.UTC).Format("15:04") }
+	retimed := append([]models.Flight(nil), flights...)
+	for i := range 4 {
+		retimed[i].ScheduledDeparture = flights[i].ScheduledDeparture.Add(30 * time.Minute)
+	}
+	late := retimed[0].ScheduledDeparture.Add(20 * time.Minute)
+	retimed[0].Status = models.StatusDelayed // Retimed, and late for the new time too
+	retimed[0].EstimatedDeparture = &late
+	retimed[1].Status = models.StatusDelayed // Retimed and late, by no estimate
+	retimed[3].Status = models.StatusCancelled
+	want := []string{
+		"Retimed from " + clock(flights[0].ScheduledDeparture) + " EST " + clock(late),
+		"Retimed from " + clock(flights[1].ScheduledDeparture) + " / Delayed",
+		"Retimed from " + clock(flights[2].ScheduledDeparture),
+		"", // Cancelled says more than the retime
+		"", // Not retimed
+	}
+	remarks := func() []string {
+		var remarks []string
+		for _, row := range board.Rows() {
+			remarks = append(remarks, string(row.Flight.Remarks))
+		}
+		return remarks
+	}
+
+	summary := board.UpdateFlights(retimed)
+	for update := 1; update <= 3; update++ {
+		got := remarks()
+		for i := range want {
+			if retime := update <= board.RetimedFor && want[i] != ""; retime && got[i] != want[i] {
+				t.Errorf("update %d: %s remarks %q, want %q", update, retimed[i].FlightNumber, got[i], want[i])
+			} else if !retime && strings.HasPrefix(got[i], "Retimed") {
+				t.Errorf("update %d: %s remarks %q, want those of its status", update, retimed[i].FlightNumber, got[i])
+			}
+		}
+		board.UpdateFlights(retimed)
+	}
+
+	// Logged as retimed, then as delayed to the estimate if there is one
+	var logged []string
+	for _, event := range summary.Events {
+		if event.FlightNumber == "AA 100" {
+			logged = append(logged, event.Description())
+		}
+	}
+	wantLogged := []string{
+		"retimed " + clock(flights[0].ScheduledDeparture) + "→" + clock(retimed[0].ScheduledDeparture),
+		"delayed to " + clock(late),
+	}
+	if strings.Join(logged, ", ") != strings.Join(wantLogged, ", ") {
+		t.Errorf("AA 100 changes logged %q, want %q", logged, wantLogged)
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"time"
+
+	"fids-tui/models"
+)
+
+// Adaptive page rotation: how much shorter or longer than the base interval
+// a page may be shown
+const (
+	minRotationScale = 0.5              // A page of empty rows is shown for half the base interval
+	maxRotationScale = 2.0              // Pages of imminent flights linger at most twice as long
+	imminentBoost    = 0.25             // Each imminent flight adds a quarter of the base interval
+	imminentWithin   = 30 * time.Minute // Flights leaving or arriving this soon are imminent
+)
+
+// NextPageDelay returns how long the current page should stay up before
+// rotating to the next. It is RotateEvery unless AdaptiveRotate is
+// set, when pages that are mostly empty rows move on sooner and pages with
+// flights due within 30 minutes linger, up to twice as long
+func (b *Board) NextPageDelay() time.Duration {
+	return b.pageDelay(time.Now())
+}
+
+// pageDelay is NextPageDelay as of now
+func (b *Board) pageDelay(now time.Time) time.Duration {
+	base := b.RotateEvery
+	if !b.AdaptiveRotate || base <= 0 {
+		return base
+	}
+	page := b.GetCurrentPageFlights()
+	if len(page) == 0 {
+		return base
+	}
+	filled, imminent := 0, 0
+	for _, row := range page {
+		if row.Flight == nil {
+			continue
+		}
+		filled++
+		if isImminent(row.Flight, now) {
+			imminent++
+		}
+	}
+	// Empty rows scale the page down towards minRotationScale; a full page
+	// keeps the base interval before imminent flights add to it
+	fill := float64(filled) / float64(len(page))
+	scale := minRotationScale + (1-minRotationScale)*fill
+	scale = min(maxRotationScale, scale+imminentBoost*float64(imminent))
+	return time.Duration(float64(base) * scale)
+}
+
+// isImminent reports whether flight departs, or arrives on an arrivals
+// board, within imminentWithin of now
+func isImminent(flight *models.Flight, now time.Time) bool {
+	if flight.Direction == models.Arrival {
+		switch flight.Status {
+		case models.StatusCancelled, models.StatusArrived:
+			return false
+		}
+		t := flight.ScheduledArrival
+		if flight.EstimatedArrival != nil {
+			t = *flight.EstimatedArrival
+		}
+		return t.After(now) && t.Sub(now) <= imminentWithin
+	}
+	t, ok := departsAfter(flight, now)
+	return ok && t.Sub(now) <= imminentWithin
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+// TestPageDelay checks how long pages stay up with adaptive rotation: the
+// base interval for a full page, less for one of empty rows and more for
+// one of imminent flights, within half and twice the base interval
+func TestPageDelay(t *testing.T) {
+	now := time.Now()
+	// flights returns soon flights leaving within the half hour, then later
+	// ones leaving in an hour or more
+	flights := func(soon, later int) []models.Flight {
+		flights := testFlights(soon+later, now)
+		for i := range soon {
+			flights[i].ScheduledDeparture = now.Add(time.Duration(5+5*i) * time.Minute)
+		}
+		return flights
+	}
+	for _, tt := range []struct {
+		name     string
+		flights  []models.Flight
+		page     int
+		adaptive bool
+		want     time.Duration
+	}{
+		{"fixed", flights(4, 0), 0, false, 20 * time.Second},
+		{"full page", flights(0, 4), 0, true, 20 * time.Second},
+		{"half empty", flights(0, 2), 0, true, 15 * time.Second},
+		{"one flight", flights(0, 1), 0, true, 12500 * time.Millisecond},
+		{"no flights", nil, 0, true, 10 * time.Second},
+		{"one imminent", flights(1, 3), 0, true, 25 * time.Second},
+		{"two imminent", flights(2, 2), 0, true, 30 * time.Second},
+		{"imminent and empty", flights(2, 0), 0, true, 25 * time.Second},
+		{"all imminent", flights(4, 0), 0, true, 40 * time.Second},
+		{"at most twice as long", flights(6, 0), 0, true, 40 * time.Second},
+		{"later page", flights(4, 2), 1, true, 15 * time.Second},
+	} {
+		board := newTestBoard(4)
+		board.RotateEvery = 20 * time.Second
+		board.AdaptiveRotate = tt.adaptive
+		board.UpdateFlights(tt.flights)
+		settle(t, board)
+		board.CurrentPage = tt.page
+		if got := board.pageDelay(now); got != tt.want {
+			t.Errorf("%s: page shown for %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// Without an interval pages don't rotate, however adaptive
+	board := newTestBoard(4)
+	board.AdaptiveRotate = true
+	board.UpdateFlights(flights(2, 0))
+	if got := board.NextPageDelay(); got != 0 {
+		t.Errorf("page without a rotation interval shown for %s, want 0", got)
+	}
+}
+
+func TestIsImminent(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		t := now.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+	for _, tt := range []struct {
+		name   string
+		flight models.Flight
+		want   bool
+	}{
+		{"leaving soon", models.Flight{ScheduledDeparture: *at(10)}, true},
+		{"leaving in half an hour", models.Flight{ScheduledDeparture: *at(30)}, true},
+		{"leaving later", models.Flight{ScheduledDeparture: *at(31)}, false},
+		{"due already", models.Flight{ScheduledDeparture: *at(-5)}, false},
+		{"delayed out of the half hour", models.Flight{ScheduledDeparture: *at(10), EstimatedDeparture: at(45), Status: models.StatusDelayed}, false},
+		{"moved into the half hour", models.Flight{ScheduledDeparture: *at(60), EstimatedDeparture: at(20)}, true},
+		{"cancelled", models.Flight{ScheduledDeparture: *at(10), Status: models.StatusCancelled}, false},
+		{"taxiing", models.Flight{ScheduledDeparture: *at(10), Status: models.StatusTaxiingLeftGate}, false},
+		{"arriving soon", models.Flight{Direction: models.Arrival, ScheduledArrival: *at(20)}, true},
+		{"arriving later", models.Flight{Direction: models.Arrival, ScheduledArrival: *at(45)}, false},
+		{"arriving early", models.Flight{Direction: models.Arrival, ScheduledArrival: *at(60), EstimatedArrival: at(25)}, true},
+		{"arrived", models.Flight{Direction: models.Arrival, ScheduledArrival: *at(10), Status: models.StatusArrived}, false},
+		{"arrival cancelled", models.Flight{Direction: models.Arrival, ScheduledArrival: *at(10), Status: models.StatusCancelled}, false},
+	} {
+		if got := isImminent(&tt.flight, now); got != tt.want {
+			t.Errorf("%s: isImminent = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// CellText is the animated text of a table cell
+type CellText interface {
+	// Update sets new text, flipping the characters that change
+	Update(text string)
+	// Tick advances the animation
+	Tick()
+	// Render returns the text as currently shown
+	Render() string
+	// IsAnimating reports whether characters are still flipping
+	IsAnimating() bool
+}
+
+// ParseScrollColumns parses a SCROLL_COLUMNS config value: a comma-separated
+// list of the columns whose long text scrolls instead of being cut short,
+// destination (the origin on arrivals boards) or remarks
+func ParseScrollColumns(value string) (map[ColumnID]bool, error) {
+	columns := make(map[ColumnID]bool)
+	for _, name := range strings.Split(value, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "destination":
+			columns[ColDestination] = true
+			columns[ColOrigin] = true
+		case "remarks":
+			columns[ColRemarks] = true
+		default:
+			return nil, fmt.Errorf("unknown column %q (expected destination or remarks)", strings.TrimSpace(name))
+		}
+	}
+	return columns, nil
+}
+
+// SetScrollColumns sets the columns whose text scrolls when too long for
+// them, rebuilding the rows
+func (b *Board) SetScrollColumns(columns map[ColumnID]bool) {
+	b.ScrollColumns = columns
+	b.applyLayout()
+}
+
+// scrollHold is the number of ticks scrolling text holds still at each end
+const scrollHold = 8
+
+// ScrollingText is the text of a cell that scrolls when longer than the
+// cell: once its start has flipped in, it moves a cell along each tick
+// until its end shows, then goes back to the start, holding still at each
+// end. Text that fits only flips, as AnimatedText does. It flips with the
+// AnimatedText it is made from
+type ScrollingText struct {
+	*AnimatedText        // Flips in the start of the text
+	Text          string // The whole text
+	offset        int    // Cells of the text scrolled past
+	held          int    // Ticks held still at the current end
+}
+
+// Update sets new text, flipping in its start and scrolling it from the
+// start again
+func (st *ScrollingText) Update(text string) {
+	st.Text = text
+	st.offset, st.held = 0, 0
+	st.AnimatedText.Update(text)
+}
+
+// Scrolling reports whether the text is too long for the cell, and so
+// scrolls once it has flipped in
+func (st *ScrollingText) Scrolling() bool {
+	return ansi.StringWidth(st.Text) > st.MaxLength
+}
+
+// Tick advances the flip, or once it is over, the scrolling
+func (st *ScrollingText) Tick() {
+	st.AnimatedText.Tick()
+	if st.AnimatedText.IsAnimating() || !st.Scrolling() {
+		return
+	}
+	if st.held < scrollHold {
+		st.held++
+		return
+	}
+	end := ansi.StringWidth(st.Text) - st.MaxLength
+	if st.offset < end {
+		st.offset++
+		if st.offset == end {
+			st.held = 0
+		}
+		return
+	}
+	st.offset, st.held = 0, 0
+}
+
+// Render returns the text as shown: flipping, or the part scrolled to,
+// padded to exactly the width of the cell so a wide character cut by
+// either edge doesn't shift the columns after it
+func (st *ScrollingText) Render() string {
+	if st.AnimatedText.IsAnimating() || !st.Scrolling() {
+		return st.AnimatedText.Render()
+	}
+	return PadCell(ansi.Cut(st.Text, st.offset, st.offset+st.MaxLength), st.MaxLength, AlignLeft)
+}
+
+// Scrolling reports whether any cell of the row scrolls
+func (fr *FlightRow) Scrolling() bool {
+	for _, cell := range fr.cells {
+		if st, ok := cell.(*ScrollingText); ok && st.Scrolling() {
+			return true
+		}
+	}
+	return false
+}
+
+// Scrolling reports whether any row on the page, or the cancellations
+// strip, has text scrolling, which needs ticking after the rows have settled
+func (b *Board) Scrolling() bool {
+	if b.cancellations != nil && b.cancellations.Scrolling(b.tableWidth()) {
+		return true
+	}
+	for _, row := range b.pageRows() {
+		if row.Scrolling() {
+			return true
+		}
+	}
+	return false
+}
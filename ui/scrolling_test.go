@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseScrollColumns(t *testing.T) {
+	columns, err := ParseScrollColumns(" Destination, remarks,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []ColumnID{ColDestination, ColOrigin, ColRemarks} {
+		if !columns[id] {
+			t.Errorf("column %v doesn't scroll", id)
+		}
+	}
+	if _, err := ParseScrollColumns("gate"); err == nil || !strings.Contains(err.Error(), `"gate"`) {
+		t.Errorf("unknown column: err = %v, want it named", err)
+	}
+}
+
+// TestScrollingTextTick ticks text too long for its cell round twice,
+// checking the part shown after each tick: held at the start, a cell along
+// each tick to the end, held there, then back to the start
+func TestScrollingTextTick(t *testing.T) {
+	text := &ScrollingText{AnimatedText: NewAnimatedText(6)}
+	text.Timing = AnimationTiming{}
+	text.Update("ABCDEFGHIJ")
+	end := 4 // Cells to scroll before the end shows
+	want := map[int]string{
+		1:                        "ABCDEF", // Flipped in
+		scrollHold:               "ABCDEF", // Still held
+		scrollHold + 1:           "BCDEFG",
+		scrollHold + end:         "EFGHIJ",
+		2*scrollHold + end:       "EFGHIJ", // Held at the end
+		2*scrollHold + end + 1:   "ABCDEF", // Back to the start
+		3*scrollHold + end + 1:   "ABCDEF",
+		3*scrollHold + end + 2:   "BCDEFG",
+		3*scrollHold + 2*end + 1: "EFGHIJ",
+	}
+	for tick := range 3*scrollHold + 2*end + 2 {
+		if want, ok := want[tick]; ok {
+			if got := text.Render(); got != want {
+				t.Errorf("tick %d: Render = %q, want %q", tick, got, want)
+			}
+		}
+		text.Tick()
+	}
+
+	// New text starts again from the top
+	text.Update("KLMNOPQRST")
+	text.Tick()
+	if got := text.Render(); got != "KLMNOP" {
+		t.Errorf("updated text shows %q, want its start", got)
+	}
+
+	// Text that fits doesn't move
+	text.Update("ABC")
+	for range 2 * scrollHold {
+		text.Tick()
+	}
+	if text.Scrolling() || text.Render() != "ABC   " {
+		t.Errorf("short text shows %q, scrolling %v", text.Render(), text.Scrolling())
+	}
+}
+
+// TestScrollingTextFlip checks text doesn't scroll while it is flipping in,
+// and that a wide character cut by the edge of the cell keeps it the
+// width of the cell
+func TestScrollingTextFlip(t *testing.T) {
+	text := &ScrollingText{AnimatedText: NewAnimatedText(6)}
+	text.Timing = AnimationTiming{Phase: time.Second, Duration: time.Hour}
+	text.Update("ABCDEFGHIJ")
+	for range 2 * scrollHold {
+		text.Tick()
+	}
+	if text.offset != 0 || !text.IsAnimating() {
+		t.Errorf("flipping text scrolled to %d", text.offset)
+	}
+
+	wide := &ScrollingText{AnimatedText: NewAnimatedText(6)}
+	wide.Timing = AnimationTiming{}
+	wide.Update("成田 Narita")
+	for tick := range 3 * scrollHold {
+		if got := ansi.StringWidth(wide.Render()); got != 6 {
+			t.Errorf("tick %d: %q is %d cells wide, want 6", tick, wide.Render(), got)
+		}
+		wide.Tick()
+	}
+}
+
+// TestBoardScrolling checks that a board scrolls the long remarks of its
+// rows through to their end, only in the columns set to scroll
+func TestBoardScrolling(t *testing.T) {
+	flights := testFlights(2, time.Now())
+	flights[0].Remarks = "Gate change to B22, now boarding at the far end of the concourse"
+	board := newTestBoard(10)
+	board.SetRemarkTemplates(nil)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	if board.Scrolling() {
+		t.Error("board scrolling with no columns set to scroll")
+	}
+
+	board.SetScrollColumns(map[ColumnID]bool{ColRemarks: true})
+	settle(t, board)
+	if !board.Scrolling() {
+		t.Fatal("board with long remarks set to scroll isn't scrolling")
+	}
+	row := func() string {
+		lines := renderedLines(board)
+		return lines[lineOf(lines, 0, "AA 100")]
+	}
+	if !strings.Contains(row(), "Gate change") {
+		t.Fatalf("remarks don't start at their start: %q", row())
+	}
+	for range scrollHold + 3 {
+		board.Tick()
+	}
+	if got := row(); strings.Contains(got, "Gate change") || !strings.Contains(got, "change to B22") {
+		t.Errorf("remarks after %d ticks %q, want them scrolled along", scrollHold+3, got)
+	}
+	for range len(flights[0].Remarks) {
+		if strings.Contains(row(), "concourse") {
+			break
+		}
+		board.Tick()
+	}
+	if !strings.Contains(row(), "concourse") {
+		t.Errorf("remarks never scrolled to their end: %q", row())
+	}
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"fids-tui/models"
+)
+
+// SeenFlightTTL is how long a flight is remembered after it was first seen,
+// comfortably longer than any flight stays on a board
+const SeenFlightTTL = 48 * time.Hour
+
+// newBadge is appended to the remarks of flights added to the schedule
+const newBadge = "NEW"
+
+// SeenFlight records when a flight was first seen
+type SeenFlight struct {
+	FirstSeen time.Time `json:"first_seen"`
+	New       bool      `json:"new,omitempty"` // Added to the schedule after the board's first fetch
+}
+
+// SeenFlights remembers the flights boards have shown, so flights that appear
+// after a board was first fetched can be badged as new. Boards can share one
+// set, and it can be saved and restored so a restart doesn't badge anything
+// again
+type SeenFlights struct {
+	mu      sync.Mutex
+	flights map[string]SeenFlight
+}
+
+// NewSeenFlights creates a set holding flights, such as ones saved by an
+// earlier run, leaving out those seen longer than SeenFlightTTL before now
+func NewSeenFlights(flights map[string]SeenFlight, now time.Time) *SeenFlights {
+	s := &SeenFlights{flights: make(map[string]SeenFlight, len(flights))}
+	for key, seen := range flights {
+		if now.Sub(seen.FirstSeen) < SeenFlightTTL {
+			s.flights[key] = seen
+		}
+	}
+	return s
+}
+
+// Snapshot returns a copy of the flights seen, for saving
+func (s *SeenFlights) Snapshot() map[string]SeenFlight {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.flights)
+}
+
+// see records the flight key as seen at now unless it was seen before
+func (s *SeenFlights) see(key string, now time.Time, isNew bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.flights[key]; !ok {
+		s.flights[key] = SeenFlight{FirstSeen: now, New: isNew}
+	}
+}
+
+// rekey moves what is remembered of flight key from to key to, for a
+// flight whose key changed with its scheduled time, unless to is known
+func (s *SeenFlights) rekey(from, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen, ok := s.flights[from]
+	if _, known := s.flights[to]; !ok || known || from == to {
+		return
+	}
+	s.flights[to] = seen
+	delete(s.flights, from)
+}
+
+// Prune forgets flights seen longer than SeenFlightTTL before now
+func (s *SeenFlights) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, seen := range s.flights {
+		if now.Sub(seen.FirstSeen) >= SeenFlightTTL {
+			delete(s.flights, key)
+		}
+	}
+}
+
+// Len returns the number of flights remembered
+func (s *SeenFlights) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.flights)
+}
+
+// badgeUntil returns when the NEW badge of flight key ends, if it was new
+func (s *SeenFlights) badgeUntil(key string, duration time.Duration) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen, ok := s.flights[key]
+	if !ok || !seen.New {
+		return time.Time{}, false
+	}
+	return seen.FirstSeen.Add(duration), true
+}
+
+// seenKey identifies a flight across updates and runs: its ID, or for sources
+// without IDs its number and scheduled time
+func seenKey(flight *models.Flight) string {
+	if flight.ID != "" {
+		return flight.ID
+	}
+	return strings.TrimSpace(flight.FlightNumber) + "@" + flight.ScheduledTime().UTC().Format(time.RFC3339)
+}
+
+// latestScheduled returns the latest scheduled time among flights, or the
+// zero time if there are none
+func latestScheduled(flights []models.Flight) time.Time {
+	var latest time.Time
+	for i := range flights {
+		if t := flights[i].ScheduledTime(); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fids-tui/models"
+)
+
+func TestSeenFlights(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	seen := NewSeenFlights(map[string]SeenFlight{
+		"kept":    {FirstSeen: now.Add(-SeenFlightTTL + time.Second), New: true},
+		"expired": {FirstSeen: now.Add(-SeenFlightTTL)},
+	}, now)
+	if _, ok := seen.Snapshot()["expired"]; ok || seen.Len() != 1 {
+		t.Errorf("restored %d flights, want only the one seen within SeenFlightTTL", seen.Len())
+	}
+
+	// A flight is first seen once
+	seen.see("AA100", now, false)
+	seen.see("AA100", now.Add(time.Hour), true)
+	if got := seen.Snapshot()["AA100"]; !got.FirstSeen.Equal(now) || got.New {
+		t.Errorf("AA100 seen %+v, want as first seen", got)
+	}
+
+	// Rekeyed only onto a key not already known
+	seen.rekey("AA100", "AA100-late")
+	seen.rekey("kept", "AA100-late")
+	if _, ok := seen.Snapshot()["AA100-late"]; !ok || seen.Len() != 2 {
+		t.Errorf("after rekeying: %v", seen.Snapshot())
+	}
+	if _, ok := seen.badgeUntil("AA100-late", time.Hour); ok {
+		t.Error("flight not new has a badge")
+	}
+	if until, ok := seen.badgeUntil("kept", time.Hour); !ok || !until.Equal(now.Add(-SeenFlightTTL+time.Second+time.Hour)) {
+		t.Errorf("badge of a new flight until %s, %v", until, ok)
+	}
+
+	seen.Prune(now.Add(SeenFlightTTL - time.Nanosecond))
+	if seen.Len() != 1 {
+		t.Errorf("%d flights after pruning, want AA100-late", seen.Len())
+	}
+	seen.Prune(now.Add(SeenFlightTTL))
+	if seen.Len() != 0 {
+		t.Errorf("%d flights kept past SeenFlightTTL", seen.Len())
+	}
+}
+
+// TestNewBadgeExpiry adds a flight to the schedule after the first update,
+// checking it is badged NEW until exactly NewBadgeFor after it was first
+// seen, also on a board restored from the saved flights, and that a flight
+// only just come into the lookahead window isn't
+func TestNewBadgeExpiry(t *testing.T) {
+	now := time.Now()
+	all := testFlights(5, now)
+	board := newTestBoard(10)
+	board.UpdateFlights([]models.Flight{all[0], all[1], all[3]})
+	board.UpdateFlights(all)
+	settle(t, board)
+	remarks := func(board *Board, number string) string {
+		for _, row := range board.Rows() {
+			if row.Flight.FlightNumber == number {
+				return string(row.Flight.Remarks)
+			}
+		}
+		t.Fatalf("%s not on the board", number)
+		return ""
+	}
+	for number, want := range map[string]bool{"AA 100": false, "AA 102": true, "AA 104": false} {
+		if got := strings.HasSuffix(remarks(board, number), newBadge); got != want {
+			t.Errorf("%s badged %v, want %v: %q", number, got, want, remarks(board, number))
+		}
+	}
+
+	until := board.Seen.Snapshot()[seenKey(&all[2])].FirstSeen.Add(board.NewBadgeFor)
+	if board.RefreshRemarks(until.Add(-time.Nanosecond)) || !strings.HasSuffix(remarks(board, "AA 102"), newBadge) {
+		t.Errorf("badge ended before NewBadgeFor: %q", remarks(board, "AA 102"))
+	}
+
+	// Restarted with the flights saved, the badge ends at the same time
+	restored := newTestBoard(10)
+	restored.Seen = NewSeenFlights(board.Seen.Snapshot(), now)
+	restored.UpdateFlights(all)
+	if !strings.HasSuffix(remarks(restored, "AA 102"), newBadge) || strings.HasSuffix(remarks(restored, "AA 100"), newBadge) {
+		t.Errorf("restored board remarks %q and %q, want only AA 102 badged", remarks(restored, "AA 100"), remarks(restored, "AA 102"))
+	}
+
+	for _, board := range []*Board{board, restored} {
+		if !board.RefreshRemarks(until) {
+			t.Error("no remarks changed as the badge ended")
+		}
+		if got := remarks(board, "AA 102"); strings.Contains(got, newBadge) {
+			t.Errorf("AA 102 remarks %q at NewBadgeFor, want the badge gone", got)
+		}
+	}
+}
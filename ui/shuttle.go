@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+// noShuttles is shown in place of the flights of a shuttle destination with
+// none coming up
+const noShuttles = "— no flights —"
+
+// shuttleView shows the next few flights to each of the board's shuttle
+// destinations, a section each under the destination's name, like the next
+// train boards of stations. Everything else is left off
+type shuttleView struct{}
+
+func (shuttleView) Layout(mode LayoutMode, direction models.Direction) Layout {
+	// The section heading names the place, so a row needs only the flight.
+	// Sections are one line per flight whatever the layout mode
+	return Layout{Lines: [][]Column{{
+		{ID: ColStatus, Name: "S", Width: 1},
+		{ID: ColFlight, Name: "FLIGHT", Width: 8},
+		{ID: ColTime, Name: "TIME", Width: 8},
+		{ID: ColRemarks, Name: "REMARKS", Width: 20},
+	}}}
+}
+
+func (shuttleView) Less(a, b *models.Flight) bool {
+	return lessByTime(a, b)
+}
+
+func (shuttleView) Title(direction models.Direction) string {
+	if direction == models.Arrival {
+		return "ARRIVALS BY ORIGIN"
+	}
+	return "DEPARTURES BY DESTINATION"
+}
+
+// SetShuttles sets the destinations of the shuttle view, in the order their
+// sections are shown, and the number of flights shown for each, for
+// arrivals boards the origins. The flights of the last update are selected
+// again straight away
+func (b *Board) SetShuttles(places []string, depth int) {
+	b.Shuttles = places
+	b.ShuttleDepth = depth
+	if b.ViewMode == ViewShuttle {
+		b.refilter()
+	}
+}
+
+// shuttleMode reports whether the board shows the shuttle view, which needs
+// destinations to show
+func (b *Board) shuttleMode() bool {
+	return b.ViewMode == ViewShuttle && len(b.Shuttles) > 0 && b.ShuttleDepth > 0
+}
+
+// shuttlePlace returns the place a flight's section is named after: its
+// destination, or its origin on an arrivals board
+func shuttlePlace(flight *models.Flight) string {
+	if flight.Direction == models.Arrival {
+		return strings.ToUpper(strings.TrimSpace(flight.OriginCode))
+	}
+	return strings.ToUpper(strings.TrimSpace(flight.DestinationCode))
+}
+
+// upcoming reports whether a flight is still to leave, or to land on an
+// arrivals board, at now. Cancelled flights count until their time, so
+// people waiting for them see why
+func upcoming(flight *models.Flight, now time.Time) bool {
+	switch flight.Status {
+	case models.StatusDeparted, models.StatusTaxiingLeftGate, models.StatusTaxiingDelayed, models.StatusArrived:
+		return false
+	case models.StatusCancelled:
+		return flight.ScheduledTime().After(now)
+	default:
+		return true
+	}
+}
+
+// shuttled returns the flights the shuttle view shows, when it is shown,
+// else flights
+func (b *Board) shuttled(flights []models.Flight) []models.Flight {
+	if !b.shuttleMode() {
+		return flights
+	}
+	return b.shuttleFlights(flights, time.Now())
+}
+
+// shuttleFlights returns the next ShuttleDepth upcoming flights to each of
+// the shuttle destinations at now, by time
+func (b *Board) shuttleFlights(flights []models.Flight, now time.Time) []models.Flight {
+	byPlace := make(map[string][]models.Flight, len(b.Shuttles))
+	for _, place := range b.Shuttles {
+		byPlace[place] = nil
+	}
+	for _, flight := range flights {
+		place := shuttlePlace(&flight)
+		if _, ok := byPlace[place]; ok && upcoming(&flight, now) {
+			byPlace[place] = append(byPlace[place], flight)
+		}
+	}
+	var kept []models.Flight
+	for _, place := range b.Shuttles {
+		next := byPlace[place]
+		slices.SortStableFunc(next, func(x, y models.Flight) int {
+			return x.ScheduledTime().Compare(y.ScheduledTime())
+		})
+		kept = append(kept, next[:min(len(next), b.ShuttleDepth)]...)
+	}
+	return kept
+}
+
+// orderShuttles pages the rows a destination at a time: each destination
+// takes ShuttleDepth slots, its flights by time then blank rows, so every
+// section of a page lines up under its heading
+func (b *Board) orderShuttles() {
+	byPlace := make(map[string][]*FlightRow, len(b.Shuttles))
+	for _, row := range b.Rows() {
+		place := shuttlePlace(row.Flight)
+		byPlace[place] = append(byPlace[place], row)
+	}
+	order := make([]*FlightRow, 0, len(b.Shuttles)*b.ShuttleDepth)
+	for _, place := range b.Shuttles {
+		rows := byPlace[place]
+		slices.SortStableFunc(rows, func(x, y *FlightRow) int {
+			return x.Flight.ScheduledTime().Compare(y.Flight.ScheduledTime())
+		})
+		rows = rows[:min(len(rows), b.ShuttleDepth)]
+		order = append(order, rows...)
+		for range b.ShuttleDepth - len(rows) {
+			order = append(order, NewFlightRow(nil, b.Layout, b.displayZone(), b.Glyphs, b.Animation))
+		}
+	}
+	b.pageOrder = order
+}
+
+// shuttlesPerPage returns how many destination sections fit in rows lines,
+// at least one: each takes a line for its heading and one per flight
+func (b *Board) shuttlesPerPage(rows int) int {
+	return max(1, rows/(b.ShuttleDepth+1))
+}
+
+// pageLines returns the lines the rows of a page take, with the section
+// headings of the shuttle view
+func (b *Board) pageLines() int {
+	lines := b.perPage() * b.Layout.LinesPerFlight()
+	if b.shuttleMode() {
+		lines += b.perPage() / b.ShuttleDepth
+	}
+	return lines
+}
+
+// renderShuttles renders the sections of the destinations on the current
+// page from rows, ShuttleDepth rows each. Destinations without flights
+// show noShuttles, and slots past the last destination stay blank so the
+// page keeps its height
+func (b *Board) renderShuttles(rows []*FlightRow) []string {
+	var lines []string
+	first := b.CurrentPage * (len(rows) / b.ShuttleDepth)
+	flippingOut := b.transition != nil && !b.transition.incoming
+	for start := 0; start+b.ShuttleDepth <= len(rows); start += b.ShuttleDepth {
+		section := rows[start : start+b.ShuttleDepth]
+		index := first + start/b.ShuttleDepth
+		if index >= len(b.Shuttles) || flippingOut {
+			// Headings blank with the rows while the page flips out
+			lines = append(lines, "")
+		} else {
+			lines = append(lines, b.Styles.Header.Render(shuttleHeading(b.Shuttles[index], section)))
+		}
+		for i, row := range section {
+			if i == 0 && row.Flight == nil && index < len(b.Shuttles) && !flippingOut {
+				lines = append(lines, b.Styles.Text.Render(noShuttles))
+				continue
+			}
+			styles := b.Styles
+			if row.Flight != nil && row == b.Selected {
+				styles = b.Styles.selectedVariant()
+			}
+			lines = append(lines, row.Render(styles))
+		}
+	}
+	return lines
+}
+
+// shuttleHeading names a section by its place's code, with the city its
+// flights give, e.g. "BOS Boston"
+func shuttleHeading(place string, rows []*FlightRow) string {
+	for _, row := range rows {
+		if row.Flight == nil {
+			continue
+		}
+		city := row.Flight.DestinationCity
+		if row.Flight.Direction == models.Arrival {
+			city = row.Flight.OriginCity
+		}
+		if city = strings.TrimSpace(city); city != "" {
+			return place + " " + city
+		}
+	}
+	return place
+}
+
+// shuttlePageInfo describes the destinations on the current page, e.g.
+// "Page 1/2 (destinations 1-3 of 5)"
+func (b *Board) shuttlePageInfo() string {
+	per := b.perPage() / b.ShuttleDepth
+	start := b.CurrentPage*per + 1
+	end := min(len(b.Shuttles), (b.CurrentPage+1)*per)
+	noun := "destinations"
+	if b.Direction == models.Arrival {
+		noun = "origins"
+	}
+	return fmt.Sprintf("Page %d/%d (%s %d-%d of %d)", b.CurrentPage+1, b.TotalPages, noun, start, end, len(b.Shuttles))
+}
@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The watch sidebar runs down the right of the board on terminals wide
+// enough to hold it beside the table
+const (
+	sidebarWidth    = 24 // Width of the sidebar, in cells
+	sidebarGap      = 2  // Blank cells between the table and the sidebar
+	watchedPerPlace = 2  // Upcoming flights listed under each watched destination
+)
+
+// SetWatch sets the flights, by number without spaces, and the destinations
+// listed in the watch sidebar; on arrivals boards the places are origins
+func (b *Board) SetWatch(flights, places []string) {
+	b.WatchFlights = flights
+	b.WatchPlaces = places
+	b.applyLayout()
+}
+
+// watching reports whether anything is watched
+func (b *Board) watching() bool {
+	return len(b.WatchFlights) > 0 || len(b.WatchPlaces) > 0
+}
+
+// sidebarFit returns layout with its flexible columns squeezed to leave room
+// for the sidebar, or false if nothing is watched, the terminal size is
+// unknown or the table can't be squeezed enough
+func (b *Board) sidebarFit(layout Layout) (Layout, bool) {
+	width := b.contentWidth()
+	if !b.watching() || width <= 0 {
+		return layout, false
+	}
+	return layout.squeeze(width-sidebarGap-sidebarWidth, b.Styles.Separator)
+}
+
+// sidebarShown reports whether the sidebar is drawn beside the table. When
+// it isn't, the watched flights are pinned to the first page instead
+func (b *Board) sidebarShown() bool {
+	width := b.contentWidth()
+	return b.watching() && width > 0 && b.tableWidth()+sidebarGap+sidebarWidth <= width
+}
+
+// sidebarLayout is the compact layout of a flight in the sidebar: its
+// status, number, time and airport code, then its remarks
+func (b *Board) sidebarLayout() Layout {
+	place := Column{ID: ColDestinationCode, Name: "DEST", Width: 4}
+	if b.Direction == models.Arrival {
+		place = Column{ID: ColOriginCode, Name: "ORIG", Width: 4}
+	}
+	return Layout{
+		Lines: [][]Column{
+			{
+				{ID: ColStatus, Name: "S", Width: b.Glyphs.statusWidth()},
+				{ID: ColFlight, Name: "FLIGHT", Width: 8},
+				{ID: ColTime, Name: "TIME", Width: 5},
+				place,
+			},
+			{{ID: ColRemarks, Name: "REMARKS", Width: sidebarWidth - compactIndent}},
+		},
+		Indent: compactIndent,
+	}
+}
+
+// watchedFlight returns the row of the watched flight number, or nil if it
+// isn't on the board. A number flown more than once on the board is
+// followed on its next flight still to leave, else its last
+func (b *Board) watchedFlight(number string, now time.Time) *FlightRow {
+	var found *FlightRow
+	better := func(row *FlightRow) bool {
+		if found == nil {
+			return true
+		}
+		next, foundNext := upcoming(row.Flight, now), upcoming(found.Flight, now)
+		if next != foundNext {
+			return next
+		}
+		if next {
+			return row.Flight.ScheduledTime().Before(found.Flight.ScheduledTime())
+		}
+		return row.Flight.ScheduledTime().After(found.Flight.ScheduledTime())
+	}
+	for _, row := range b.Rows() {
+		if strings.ToUpper(strings.ReplaceAll(row.Flight.FlightNumber, " ", "")) == number && better(row) {
+			found = row
+		}
+	}
+	return found
+}
+
+// WatchedFlight returns the flight the watch follows for the flight number,
+// as the sidebar lists it, or nil if it isn't on the board
+func (b *Board) WatchedFlight(number string, now time.Time) *models.Flight {
+	if row := b.watchedFlight(number, now); row != nil {
+		return row.Flight
+	}
+	return nil
+}
+
+// watchedPlace returns the rows of the next watchedPerPlace upcoming flights
+// to place at now, soonest first, leaving out those in listed
+func (b *Board) watchedPlace(place string, now time.Time, listed map[*FlightRow]bool) []*FlightRow {
+	var next []*FlightRow
+	for _, row := range b.Rows() {
+		if !listed[row] && shuttlePlace(row.Flight) == place && upcoming(row.Flight, now) {
+			next = append(next, row)
+		}
+	}
+	slices.SortStableFunc(next, func(x, y *FlightRow) int {
+		return x.Flight.ScheduledTime().Compare(y.Flight.ScheduledTime())
+	})
+	return next[:min(len(next), watchedPerPlace)]
+}
+
+// watchedRows returns the rows the sidebar lists: the watched flights in
+// the order they are watched, then the next flights to each watched
+// destination, each row once
+func (b *Board) watchedRows(now time.Time) []*FlightRow {
+	var rows []*FlightRow
+	listed := make(map[*FlightRow]bool)
+	for _, number := range b.WatchFlights {
+		if row := b.watchedFlight(number, now); row != nil && !listed[row] {
+			rows = append(rows, row)
+			listed[row] = true
+		}
+	}
+	for _, place := range b.WatchPlaces {
+		for _, row := range b.watchedPlace(place, now, listed) {
+			rows = append(rows, row)
+			listed[row] = true
+		}
+	}
+	return rows
+}
+
+// syncSidebar updates the sidebar's rows from the board's, keeping the rows
+// of flights already listed so their changes animate as on the board
+func (b *Board) syncSidebar(now time.Time) {
+	if !b.watching() {
+		b.sidebarRows = nil
+		return
+	}
+	layout := b.sidebarLayout()
+	rows := make(map[string]*FlightRow)
+	for _, row := range b.watchedRows(now) {
+		key := seenKey(row.Flight)
+		side, ok := b.sidebarRows[key]
+		if ok && side.layout.equal(layout) {
+			side.zone = row.zone
+			side.Update(row.Flight)
+		} else {
+			side = NewFlightRow(row.Flight, layout, row.zone, b.Glyphs, b.Animation)
+		}
+		side.stale = row.stale
+		rows[key] = side
+	}
+	b.sidebarRows = rows
+}
+
+// sidebarRow returns the sidebar's row for a board row
+func (b *Board) sidebarRow(row *FlightRow) *FlightRow {
+	if side, ok := b.sidebarRows[seenKey(row.Flight)]; ok {
+		return side
+	}
+	return NewFlightRow(row.Flight, b.sidebarLayout(), row.zone, b.Glyphs, b.Animation)
+}
+
+// renderSidebar renders the sidebar at most height lines high: the watched
+// flights, then each watched destination under its heading, e.g. "→ LAX".
+// Watched flights followed after leaving the board show how far they have
+// got; others not on the board are listed as such, so a typo shows
+func (b *Board) renderSidebar(height int, now time.Time) []string {
+	styles := *b.Styles
+	styles.Separator = columnSeparator // A narrow strip has no room for box separators
+	line := func(text string) string {
+		return styles.Text.Render(PadCell(text, sidebarWidth, AlignLeft))
+	}
+
+	lines := []string{styles.Header.Render(PadCell("WATCHING", sidebarWidth, AlignLeft))}
+	listed := make(map[*FlightRow]bool)
+	for _, number := range b.WatchFlights {
+		row := b.watchedFlight(number, now)
+		if state, detail, ok := b.trackedParts(number); row == nil && ok {
+			lines = append(lines, line(number+" "+state))
+			if detail != "" {
+				lines = append(lines, line(strings.Repeat(" ", compactIndent)+detail))
+			}
+			continue
+		}
+		if row == nil {
+			lines = append(lines, line(number+" not on board"))
+			continue
+		}
+		listed[row] = true
+		lines = append(lines, strings.Split(b.sidebarRow(row).Render(&styles), "\n")...)
+	}
+	arrow := "→ "
+	if b.Direction == models.Arrival {
+		arrow = "← "
+	}
+	for _, place := range b.WatchPlaces {
+		lines = append(lines, styles.Header.Render(PadCell(arrow+place, sidebarWidth, AlignLeft)))
+		next := b.watchedPlace(place, now, listed)
+		if len(next) == 0 {
+			lines = append(lines, line(noShuttles))
+		}
+		for _, row := range next {
+			listed[row] = true
+			lines = append(lines, strings.Split(b.sidebarRow(row).Render(&styles), "\n")...)
+		}
+	}
+
+	if len(lines) > height && height > 0 {
+		lines = append(lines[:height-1], line("…"))
+	}
+	return lines
+}
+
+// joinSidebar places the sidebar to the right of the rendered board
+// sections, cutting any of their lines wider than the table
+func (b *Board) joinSidebar(content string, now time.Time) string {
+	left := strings.Split(content, "\n")
+	sidebar := b.renderSidebar(len(left), now)
+	width := b.tableWidth()
+	gap := b.Styles.Text.Render(strings.Repeat(" ", sidebarGap))
+	blank := b.Styles.Text.Render(strings.Repeat(" ", sidebarWidth))
+	for i, line := range left {
+		if ansi.StringWidth(line) > width {
+			line = ansi.Truncate(line, width, "…")
+		}
+		if padding := width - ansi.StringWidth(line); padding > 0 {
+			line += b.Styles.Text.Render(strings.Repeat(" ", padding))
+		}
+		side := blank
+		if i < len(sidebar) {
+			side = sidebar[i]
+		}
+		left[i] = line + gap + side
+	}
+	return strings.Join(left, "\n")
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TestSidebarWidths watches AA 105 and LAX on boards either side of the
+// sidebar's breakpoints: beside a full table from 98 cells, beside a squeezed
+// one down to 82, and below that left out, with the watched flights leading
+// the first page instead
+func TestSidebarWidths(t *testing.T) {
+	now := time.Now()
+	for _, tt := range []struct {
+		width int
+		shown bool
+		table int // Width of the table beside the sidebar
+	}{
+		{120, true, 68},
+		{98, true, 68},
+		{97, true, 67},
+		{82, true, 52},
+		{81, false, 0},
+		{60, false, 0},
+	} {
+		board := newTestBoard(4)
+		board.UpdateFlights(testFlights(12, now))
+		board.SetWatch([]string{"AA105"}, []string{"LAX"})
+		board.SetTerminalSize(tt.width, 40)
+		settle(t, board)
+		lines := renderedLines(board)
+		for _, line := range lines {
+			if ansi.StringWidth(line) > tt.width {
+				t.Errorf("width %d: line %d cells wide: %q", tt.width, ansi.StringWidth(line), line)
+			}
+		}
+
+		watching := lineOf(lines, 0, "WATCHING")
+		if !tt.shown {
+			if watching >= 0 {
+				t.Errorf("width %d: sidebar shown on a board too narrow for it", tt.width)
+			}
+			header := lineOf(lines, 0, "FLIGHT")
+			if first := lineOf(lines, header+1, "AA 1"); first < 0 || !strings.Contains(lines[first], "AA 105") {
+				t.Errorf("width %d: first page doesn't lead with the watched AA 105:\n%s", tt.width, strings.Join(lines, "\n"))
+			}
+			continue
+		}
+		if watching < 0 {
+			t.Fatalf("width %d: no sidebar:\n%s", tt.width, strings.Join(lines, "\n"))
+		}
+		if got := board.tableWidth(); got != tt.table {
+			t.Errorf("width %d: table %d cells wide beside the sidebar, want %d", tt.width, got, tt.table)
+		}
+		if got := board.Width(); got != tt.table+sidebarGap+sidebarWidth {
+			t.Errorf("width %d: board %d cells wide, want the table and sidebar", tt.width, got)
+		}
+		// The sidebar starts after the padding, table and gap, listing the
+		// watched flight and then the next two to LAX
+		line := lines[watching]
+		start := 2 + tt.table + sidebarGap
+		if got := ansi.StringWidth(line[:strings.Index(line, "WATCHING")]); got != start {
+			t.Errorf("width %d: sidebar starts at cell %d, want %d", tt.width, got, start)
+		}
+		var sidebar []string
+		for _, line := range lines[watching:] {
+			if side := strings.TrimSpace(ansi.Cut(line, start, start+sidebarWidth)); side != "" {
+				sidebar = append(sidebar, side)
+			}
+		}
+		at := 0
+		for _, want := range []string{"AA 105", "→ LAX", "AA 100", "AA 101"} {
+			if at = lineOf(sidebar, at, want); at < 0 {
+				t.Errorf("width %d: sidebar doesn't list %q in order:\n%s", tt.width, want, strings.Join(sidebar, "\n"))
+				break
+			}
+		}
+	}
+}
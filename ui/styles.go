@@ -12,7 +12,23 @@ type SplitFlapStyles struct {
 	StatusLight  func(color string) lipgloss.Style
 	AirportLabel lipgloss.Style
 	PageInfo     lipgloss.Style
+	StatusBar    lipgloss.Style
 	Error        lipgloss.Style
+	Selected     lipgloss.Style
+	Detail       lipgloss.Style
+	BorderLine   lipgloss.Style
+	Tab          lipgloss.Style
+	ActiveTab    lipgloss.Style
+	Dimmed       lipgloss.Style // Background behind a modal overlay
+	Modal        lipgloss.Style // Box around a modal overlay
+	Badge        lipgloss.Style // Marks simulated data in the status bar
+	NextFlight   lipgloss.Style // Time of the next flight to depart
+	Stale        lipgloss.Style // Time of a delayed flight whose estimate looks stuck
+	HeaderSuffix lipgloss.Style // Branding after the airport header
+	Footer       lipgloss.Style // Custom text below the board in place of the key help
+	Blocked      lipgloss.Style // Rows of flights blocked from public tracking
+	GateChanged  lipgloss.Style // Gate of a flight whose gate keeps changing
+	Separator    string // Placed between table columns
 }
 
 // NewSplitFlapStyles creates a new set of split-flap styles
@@ -22,6 +38,8 @@ func NewSplitFlapStyles() *SplitFlapStyles {
 	textColor := lipgloss.Color("#f0f0f0") // High contrast white text
 	headerColor := lipgloss.Color("#ffffff") // White headers
 	errorColor := lipgloss.Color("#ff0000") // Red for errors
+	borderColor := lipgloss.Color("#666666") // Dim gray for frame lines
+	badgeColor := lipgloss.Color("#ff8c00") // Orange for simulated data
 
 	return &SplitFlapStyles{
 		Background: lipgloss.NewStyle().
@@ -37,24 +55,7 @@ func NewSplitFlapStyles() *SplitFlapStyles {
 			Bold(true).
 			Underline(true),
 
-		StatusLight: func(color string) lipgloss.Style {
-			var statusColor lipgloss.Color
-			switch color {
-			case "green":
-				statusColor = lipgloss.Color("#00ff00")
-			case "yellow":
-				statusColor = lipgloss.Color("#ffff00")
-			case "orange":
-				statusColor = lipgloss.Color("#ff8800")
-			case "red":
-				statusColor = lipgloss.Color("#ff0000")
-			default:
-				statusColor = lipgloss.Color("#ffffff")
-			}
-			return lipgloss.NewStyle().
-				Foreground(statusColor).
-				Bold(true)
-		},
+		StatusLight: DefaultPalette().StatusLight,
 
 		AirportLabel: lipgloss.NewStyle().
 			Foreground(headerColor).
@@ -65,9 +66,85 @@ func NewSplitFlapStyles() *SplitFlapStyles {
 			Foreground(textColor).
 			MarginTop(1),
 
+		StatusBar: lipgloss.NewStyle().
+			Foreground(borderColor),
+
 		Error: lipgloss.NewStyle().
 			Foreground(errorColor).
 			Bold(true),
+
+		Selected: lipgloss.NewStyle().
+			Foreground(textColor).
+			Reverse(true),
+
+		Detail: lipgloss.NewStyle().
+			Foreground(textColor).
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(headerColor).
+			Padding(0, 1).
+			MarginTop(1),
+
+		BorderLine: lipgloss.NewStyle().
+			Foreground(borderColor),
+
+		Tab: lipgloss.NewStyle().
+			Foreground(borderColor).
+			Padding(0, 1),
+
+		ActiveTab: lipgloss.NewStyle().
+			Foreground(headerColor).
+			Bold(true).
+			Reverse(true).
+			Padding(0, 1),
+
+		Dimmed: lipgloss.NewStyle().
+			Foreground(borderColor).
+			Faint(true),
+
+		Modal: lipgloss.NewStyle().
+			Background(bgColor).
+			Foreground(textColor).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(headerColor),
+
+		Badge: lipgloss.NewStyle().
+			Background(badgeColor).
+			Foreground(bgColor).
+			Bold(true).
+			Padding(0, 1),
+
+		NextFlight: lipgloss.NewStyle().
+			Foreground(headerColor).
+			Bold(true).
+			Reverse(true),
+
+		Stale: lipgloss.NewStyle().
+			Foreground(badgeColor),
+
+		HeaderSuffix: lipgloss.NewStyle().
+			Foreground(textColor).
+			Italic(true),
+
+		Footer: lipgloss.NewStyle().
+			Foreground(headerColor).
+			Bold(true),
+
+		Blocked: lipgloss.NewStyle().
+			Foreground(borderColor).
+			Italic(true),
+
+		GateChanged: lipgloss.NewStyle().
+			Foreground(badgeColor).
+			Bold(true),
+
+		Separator: columnSeparator,
 	}
 }
 
+// selectedVariant returns a copy of the styles with row text highlighted
+func (s *SplitFlapStyles) selectedVariant() *SplitFlapStyles {
+	selected := *s
+	selected.Text = s.Selected
+	return &selected
+}
+
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tabGap separates adjacent tab labels in the tab bar
+const tabGap = " "
+
+// tabLabel returns the label shown for the tab at index, prefixed with its number key
+func tabLabel(index int, label string) string {
+	return fmt.Sprintf("%d %s", index+1, label)
+}
+
+// RenderTabBar renders a row of tab labels with the active tab highlighted
+func RenderTabBar(labels []string, active int, styles *SplitFlapStyles) string {
+	tabs := make([]string, 0, len(labels))
+	for i, label := range labels {
+		style := styles.Tab
+		if i == active {
+			style = styles.ActiveTab
+		}
+		tabs = append(tabs, style.Render(tabLabel(i, label)))
+	}
+	return strings.Join(tabs, tabGap)
+}
+
+// TabAt returns the index of the tab rendered at column x of the tab bar, or
+// -1 if x falls between or after the tabs
+func TabAt(labels []string, x int, styles *SplitFlapStyles) int {
+	start := 0
+	for i, label := range labels {
+		width := lipgloss.Width(styles.Tab.Render(tabLabel(i, label)))
+		if x >= start && x < start+width {
+			return i
+		}
+		start += width + lipgloss.Width(tabGap)
+	}
+	return -1
+}
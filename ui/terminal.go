@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Terminal is what the terminal the board runs in was detected to support
+type Terminal struct {
+	Profile termenv.Profile // Colors it shows
+	UTF8    bool            // Whether its locale is UTF-8, so block characters and symbols show
+}
+
+// StyleSet is a set of board styles for what a terminal can show
+type StyleSet int
+
+const (
+	StylesColor StyleSet = iota // The dark split-flap look, for terminals with 256 colors or more
+	StylesBasic                 // The terminal's own background and text color, with ANSI colored accents
+	StylesMono                  // No colors, only bold, faint, underline and reverse video
+)
+
+// String returns the style set's name, as logged
+func (s StyleSet) String() string {
+	switch s {
+	case StylesBasic:
+		return "basic"
+	case StylesMono:
+		return "mono"
+	default:
+		return "color"
+	}
+}
+
+// Display is how the board draws on a terminal, as chosen by ChooseDisplay
+type Display struct {
+	Glyphs      string          // GLYPHS preset
+	ASCIIShades bool            // Draw the flaps and the timeline in ASCII, and the header without the big font
+	Styles      StyleSet        // Styles of the board
+	Profile     termenv.Profile // Color profile the styles are rendered in
+}
+
+// ChooseDisplay picks how the board draws on term, given the GLYPHS preset
+// asked for. Without UTF-8 it falls back to the ascii glyphs and shades.
+// With only the 16 ANSI colors it uses the basic style set; with no colors,
+// mono, rendered as ANSI so bold and reverse video still show. forceUnicode
+// and forceColor skip those checks for terminals detected wrongly; forced
+// color is drawn in 256 colors unless more were detected
+func ChooseDisplay(term Terminal, glyphs string, forceColor, forceUnicode bool) Display {
+	display := Display{Glyphs: glyphs, Styles: StylesColor, Profile: term.Profile}
+	if !term.UTF8 && !forceUnicode {
+		display.Glyphs = "ascii"
+		display.ASCIIShades = true
+	}
+	switch {
+	case forceColor:
+		display.Profile = min(term.Profile, termenv.ANSI256) // Lower profiles have more colors
+	case term.Profile == termenv.Ascii:
+		display.Styles = StylesMono
+		display.Profile = termenv.ANSI
+	case term.Profile == termenv.ANSI:
+		display.Styles = StylesBasic
+	}
+	return display
+}
+
+// NewStyles returns the styles of a style set
+func NewStyles(set StyleSet) *SplitFlapStyles {
+	s := NewSplitFlapStyles()
+	if set == StylesColor {
+		return s
+	}
+	// White and black are left to the terminal's own colors, readable
+	// whatever its scheme, and gray becomes faint
+	plain := []*lipgloss.Style{&s.Background, &s.Text, &s.Header, &s.AirportLabel, &s.PageInfo,
+		&s.Selected, &s.Detail, &s.ActiveTab, &s.Modal, &s.NextFlight, &s.HeaderSuffix, &s.Footer}
+	gray := []*lipgloss.Style{&s.StatusBar, &s.BorderLine, &s.Tab, &s.Dimmed, &s.Blocked}
+	if set == StylesMono {
+		plain = append(plain, &s.Error, &s.Stale, &s.Badge, &s.GateChanged)
+	}
+	for _, style := range plain {
+		*style = style.UnsetForeground().UnsetBackground().UnsetBorderForeground().UnsetBorderBackground()
+	}
+	for _, style := range gray {
+		*style = style.UnsetForeground().UnsetBackground().Faint(true)
+	}
+	if set == StylesMono {
+		s.Badge = s.Badge.Reverse(true)
+		s.Stale = s.Stale.Underline(true)
+		s.GateChanged = s.GateChanged.Reverse(true)
+		s.NextFlight = s.NextFlight.Bold(true).Reverse(true)
+		s.StatusLight = monoStatusLight
+	}
+	return s
+}
+
+// monoStatusLight is the status light of the mono style set, told apart by
+// its glyph alone
+func monoStatusLight(string) lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true)
+}
+
+// SetStyles sets the board's styles to those of set. Palettes set later
+// leave the status lights of the mono set uncolored
+func (b *Board) SetStyles(set StyleSet) {
+	separator := b.Styles.Separator
+	b.StyleSet = set
+	b.Styles = NewStyles(set)
+	b.Styles.Separator = separator
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// sgrColor matches an SGR sequence setting a foreground or background color
+var sgrColor = regexp.MustCompile(`\x1b\[[0-9;]*(3[0-9]|4[0-9]|9[0-7]|10[0-7])(;[0-9;]*)?m`)
+
+// TestMonoNextFlight checks that the next flight out keeps a highlight of its
+// own in the mono styles, where colors are left out
+func TestMonoNextFlight(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	styles := NewStyles(StylesMono)
+	highlighted := styles.NextFlight.Render("12:05")
+	if sgrColor.MatchString(highlighted) {
+		t.Errorf("mono next flight %q has a color", highlighted)
+	}
+	if !strings.Contains(highlighted, "\x1b[") || highlighted == styles.Text.Render("12:05") {
+		t.Errorf("mono next flight %q looks like any other time", highlighted)
+	}
+	if !styles.NextFlight.GetReverse() || !styles.NextFlight.GetBold() {
+		t.Errorf("mono next flight isn't shown bold in reverse video")
+	}
+
+	// Only the first flight's time, padded to the column, is highlighted
+	sgr, _, _ := strings.Cut(highlighted, "12:05")
+	now := time.Now()
+	flights := testFlights(3, now)
+	board := newTestBoard(5)
+	board.SetStyles(StylesMono)
+	board.UpdateFlights(flights)
+	settle(t, board)
+	view := board.Render()
+	if got := strings.Count(view, sgr); got != 1 {
+		t.Errorf("mono board highlights %d times, want 1:\n%q", got, view)
+	}
+	next := flights[0].ScheduledDeparture.In(time.UTC).Format("15:04")
+	if !strings.Contains(view, sgr+next) {
+		t.Errorf("mono board doesn't highlight the next flight at %s:\n%q", next, view)
+	}
+}
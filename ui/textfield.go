@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TextField is a line of text typed from the keyboard, such as an API key.
+// Masked fields show a * for each character instead of the text
+type TextField struct {
+	Label  string
+	Value  string
+	Masked bool
+	MaxLen int // Characters accepted at most, zero for no limit
+	// Accept maps a typed character to the one kept, such as to upper case,
+	// or reports false to drop it; nil keeps printable characters as typed
+	Accept func(r rune) (rune, bool)
+}
+
+// HandleKey edits the value for a key, named as bubbletea names keys, with
+// the characters typed or pasted when the key is text. It reports whether
+// the key was one the field handles
+func (f *TextField) HandleKey(key string, text []rune) bool {
+	switch key {
+	case "backspace":
+		runes := []rune(f.Value)
+		if len(runes) > 0 {
+			f.Value = string(runes[:len(runes)-1])
+		}
+		return true
+	case "ctrl+u":
+		f.Value = ""
+		return true
+	}
+	if len(text) == 0 {
+		return false
+	}
+	var b strings.Builder
+	b.WriteString(f.Value)
+	length := len([]rune(f.Value))
+	for _, r := range text {
+		if f.MaxLen > 0 && length >= f.MaxLen {
+			break
+		}
+		if f.Accept != nil {
+			var ok bool
+			if r, ok = f.Accept(r); !ok {
+				continue
+			}
+		} else if !unicode.IsPrint(r) {
+			continue
+		}
+		b.WriteRune(r)
+		length++
+	}
+	f.Value = b.String()
+	return true
+}
+
+// Render draws the label and the value, with a cursor after it if the
+// field has the focus
+func (f *TextField) Render(focused bool, styles *SplitFlapStyles) string {
+	value := f.Value
+	if f.Masked {
+		value = strings.Repeat("*", len([]rune(f.Value)))
+	}
+	line := fmt.Sprintf("  %-16s %s", f.Label, value)
+	if focused {
+		return styles.Selected.Render("> " + line[2:] + "_")
+	}
+	return styles.Text.Render(line)
+}
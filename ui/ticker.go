@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"fids-tui/models"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// tickerGap separates the flights of a ticker, and its last flight from
+// the first coming round again
+const tickerGap = "  •  "
+
+// Ticker is the one-line view of a board: its flights one after another,
+// either scrolling along a cell each step or, if Swap is set, shown as
+// many at a time as fit, swapping to the next ones every Swap
+type Ticker struct {
+	Width   int           // Cells of the line, 0 for the terminal's width
+	Swap    time.Duration // How long flights show before the next ones swap in, 0 to scroll
+	offset  int           // Cells of the flights scrolled past
+	first   int           // Flight shown first when swapping
+	swapped time.Time     // When the flights shown last swapped
+}
+
+// TickerItems returns the flights of the board as a ticker shows them, in
+// board order, e.g. "UA123 BOS 14:20 ON TIME" or "DL456 ATL 14:35 DELAYED
+// 15:10", the status in its status color
+func (b *Board) TickerItems() []string {
+	flights := b.Flights()
+	items := make([]string, 0, len(flights))
+	for i := range flights {
+		items = append(items, b.tickerItem(&flights[i]))
+	}
+	return items
+}
+
+// tickerItem returns one flight of the ticker: its label, its status and
+// its estimate if it has moved
+func (b *Board) tickerItem(flight *models.Flight) string {
+	zone := b.zoneFor(flight)
+	parts := []string{b.flightLabel(flight)}
+	if status := strings.ToUpper(flight.Status.String()); status != "" {
+		parts = append(parts, b.Styles.StatusLight(flight.GetStatusColor()).Render(status))
+	}
+	if est := flight.EstimatedTime(); est != nil && flight.Status != models.StatusCancelled {
+		if shown := est.In(zone).Format("15:04"); shown != cellValue(ColTime, flight, zone, b.Glyphs) {
+			parts = append(parts, shown)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// flightLabel names a flight in a line of flights, e.g. "DL456 ATL 14:35":
+// its number, the airport it flies to or from and its time. Blocked
+// flights show only their time
+func (b *Board) flightLabel(flight *models.Flight) string {
+	zone := b.zoneFor(flight)
+	place := flight.DestinationCode
+	if flight.Direction == models.Arrival {
+		place = flight.OriginCode
+	}
+	switch {
+	case flight.Blocked:
+		place = blockedLabel
+	case strings.TrimSpace(place) == "" && flight.Direction == models.Arrival:
+		place = airportOrPlaceholder(flight.GetOrigin())
+	case strings.TrimSpace(place) == "":
+		place = airportOrPlaceholder(flight.GetDestination())
+	}
+	return strings.Join([]string{cellValue(ColFlight, flight, zone, b.Glyphs), strings.TrimSpace(place), cellValue(ColTime, flight, zone, b.Glyphs)}, " ")
+}
+
+// Scrolling reports whether the ticker scrolls items in width cells, and so
+// needs stepping each animation tick
+func (tk *Ticker) Scrolling(items []string, width int) bool {
+	width = tk.width(width)
+	return tk.Swap <= 0 && width > 0 && ansi.StringWidth(strings.Join(items, tickerGap)) > width
+}
+
+// Step scrolls the ticker a cell along, if it scrolls
+func (tk *Ticker) Step(items []string, width int) {
+	if !tk.Scrolling(items, width) {
+		tk.offset = 0
+		return
+	}
+	tk.offset = (tk.offset + 1) % ansi.StringWidth(strings.Join(items, tickerGap)+tickerGap)
+}
+
+// SwapAt swaps in the flights after those shown once they have shown for
+// Swap, going back to the first after the last
+func (tk *Ticker) SwapAt(now time.Time, items []string, width int) {
+	if tk.Swap <= 0 {
+		return
+	}
+	if tk.swapped.IsZero() {
+		tk.swapped = now // The first flights have only just shown
+		return
+	}
+	if now.Sub(tk.swapped) < tk.Swap {
+		return
+	}
+	tk.swapped = now
+	if len(items) == 0 {
+		tk.first = 0
+		return
+	}
+	tk.first = (tk.first + len(tk.page(items, tk.width(width)))) % len(items)
+}
+
+// Render returns items as the ticker shows them in width cells, or the
+// ticker's own width if set: exactly that many cells, or every item for
+// a width of 0. Without items it shows empty instead
+func (tk *Ticker) Render(items []string, width int, empty string) string {
+	width = tk.width(width)
+	if len(items) == 0 {
+		items = []string{empty}
+	}
+	if width <= 0 {
+		return strings.Join(items, tickerGap)
+	}
+	if tk.Swap > 0 {
+		tk.first %= len(items)
+		return PadCell(strings.Join(tk.page(items, width), tickerGap), width, AlignLeft)
+	}
+	line := strings.Join(items, tickerGap)
+	if ansi.StringWidth(line) <= width {
+		return PadCell(line, width, AlignLeft)
+	}
+	// A wide character cut by either edge is padded, keeping the width
+	loop := line + tickerGap + line
+	return PadCell(ansi.Cut(loop, tk.offset, tk.offset+width), width, AlignLeft)
+}
+
+// page returns the items from the first shown that fit in width together,
+// at least one, cut short if it alone is too wide
+func (tk *Ticker) page(items []string, width int) []string {
+	var page []string
+	used := 0
+	for i := range items {
+		item := items[(tk.first+i)%len(items)]
+		if len(page) > 0 {
+			used += ansi.StringWidth(tickerGap)
+		}
+		used += ansi.StringWidth(item)
+		if len(page) > 0 && used > width {
+			break
+		}
+		page = append(page, item)
+	}
+	return page
+}
+
+// width returns the cells of the line given the terminal's width
+func (tk *Ticker) width(terminal int) int {
+	if tk.Width > 0 {
+		return tk.Width
+	}
+	return terminal
+}
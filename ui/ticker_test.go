@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+
+	"fids-tui/models"
+)
+
+// TestTickerItems checks the ticker's flights: number, airport, time and
+// status in its color, with the estimate only once the flight has moved
+func TestTickerItems(t *testing.T) {
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+	lipgloss.SetColorProfile(termenv.ANSI)
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	flights := testFlights(4, now)
+	late := flights[1].ScheduledDeparture.Add(35 * time.Minute)
+	flights[1].Status = models.StatusDelayed
+	flights[1].EstimatedDeparture = &late
+	onTime := flights[2].ScheduledDeparture
+	flights[2].EstimatedDeparture = &onTime
+	flights[3].Status = models.StatusCancelled
+	flights[3].EstimatedDeparture = &late
+	board := newTestBoard(10)
+	board.UpdateFlights(flights)
+	settle(t, board)
+
+	items := board.TickerItems()
+	want := []string{
+		"AA 100 LAX 13:00 ON TIME",
+		"AA 101 LAX 14:00 DELAYED 14:35",
+		"AA 102 LAX 15:00 ON TIME",
+		"AA 103 LAX 16:00 CANCELLED",
+	}
+	if len(items) != len(want) {
+		t.Fatalf("%d ticker items, want %d: %q", len(items), len(want), items)
+	}
+	for i := range want {
+		if got := ansi.Strip(items[i]); got != want[i] {
+			t.Errorf("item %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if delayed := board.Styles.StatusLight(flights[1].GetStatusColor()).Render("DELAYED"); delayed == "DELAYED" || !strings.Contains(items[1], delayed) {
+		t.Errorf("delayed item %q doesn't show its status in the status color", items[1])
+	}
+}
+
+// TestTickerArrivalItem checks that an arrival names the airport it flies
+// from
+func TestTickerArrivalItem(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	flight := testFlights(1, now)[0]
+	flight.Direction = models.Arrival
+	flight.OriginCode = "BOS"
+	flight.ScheduledArrival = flight.ScheduledDeparture
+	flight.Status = models.StatusArrived
+	board := newTestBoard(10)
+	if got, want := ansi.Strip(board.tickerItem(&flight)), "AA 100 BOS 13:00 ARRIVED"; got != want {
+		t.Errorf("arrival item = %q, want %q", got, want)
+	}
+}
+
+func TestTickerRender(t *testing.T) {
+	items := []string{"AA 100", "AA 101"}
+	for _, tt := range []struct {
+		name   string
+		ticker Ticker
+		items  []string
+		width  int
+		want   string
+	}{
+		{"every item without a width", Ticker{}, items, 0, "AA 100  •  AA 101"},
+		{"padded to the width", Ticker{}, items, 20, "AA 100  •  AA 101   "},
+		{"own width over the terminal's", Ticker{Width: 8}, items, 80, "AA 100  "},
+		{"empty", Ticker{}, nil, 12, "No flights  "},
+		{"empty without a width", Ticker{}, nil, 0, "No flights"},
+		{"swapping first page", Ticker{Swap: time.Second}, items, 10, "AA 100    "},
+	} {
+		if got := tt.ticker.Render(tt.items, tt.width, "No flights"); got != tt.want {
+			t.Errorf("%s: Render = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestTickerScroll steps a scrolling ticker round its loop, checking each
+// line keeps its width and the line comes round again after the gap
+func TestTickerScroll(t *testing.T) {
+	items := []string{"AAAA", "BBBB"}
+	var ticker Ticker
+	if !ticker.Scrolling(items, 6) {
+		t.Fatal("ticker too narrow for its items doesn't scroll")
+	}
+	want := map[int]string{
+		0:  "AAAA  ",
+		1:  "AAA  •",
+		4:  "  •  B",
+		9:  "BBBB  ",
+		13: "  •  A",
+		17: " AAAA ",
+		18: "AAAA  ", // Round the loop of both items and two gaps
+	}
+	for step := 0; step <= 18; step++ {
+		got := ticker.Render(items, 6, "")
+		if width := ansi.StringWidth(got); width != 6 {
+			t.Errorf("step %d: %q is %d cells, want 6", step, got, width)
+		}
+		if w, ok := want[step]; ok && got != w {
+			t.Errorf("step %d: %q, want %q", step, got, w)
+		}
+		ticker.Step(items, 6)
+	}
+
+	// Once the items fit, the ticker stops, back at the start
+	ticker.Step(items, 6)
+	ticker.Step(items, 40)
+	if ticker.Scrolling(items, 40) || ticker.offset != 0 {
+		t.Errorf("ticker with room for its items scrolls, at offset %d", ticker.offset)
+	}
+	if swapping := (Ticker{Swap: time.Second}); swapping.Scrolling(items, 6) {
+		t.Error("swapping ticker scrolls")
+	}
+}
+
+// TestTickerScrollWide checks that a wide character cut by an edge of the
+// line is padded rather than overflowing it
+func TestTickerScrollWide(t *testing.T) {
+	items := []string{"東京 HND", "AA 100"}
+	var ticker Ticker
+	for step := range 20 {
+		if got := ticker.Render(items, 7, ""); ansi.StringWidth(got) != 7 {
+			t.Errorf("step %d: %q is %d cells, want 7", step, got, ansi.StringWidth(got))
+		}
+		ticker.Step(items, 7)
+	}
+}
+
+// TestTickerSwap checks that a swapping ticker shows as many items as fit,
+// swapping to the next ones every Swap and round to the first after the last
+func TestTickerSwap(t *testing.T) {
+	items := []string{"A 1", "A 2", "A 3", "A 4", "A 5"}
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	ticker := Ticker{Width: 11, Swap: 5 * time.Second}
+	for _, tt := range []struct {
+		at   time.Duration
+		want string
+	}{
+		{0, "A 1  •  A 2"}, // The first flights have only just shown
+		{4 * time.Second, "A 1  •  A 2"},
+		{5 * time.Second, "A 3  •  A 4"},
+		{10 * time.Second, "A 5  •  A 1"},
+		{12 * time.Second, "A 5  •  A 1"},
+		{15 * time.Second, "A 2  •  A 3"},
+	} {
+		ticker.SwapAt(start.Add(tt.at), items, 80)
+		if got := ticker.Render(items, 80, ""); got != tt.want {
+			t.Errorf("at %s: %q, want %q", tt.at, got, tt.want)
+		}
+	}
+
+	// Fewer flights than the one shown first go back to the start
+	if got := ticker.Render(items[:1], 80, ""); got != "A 1        " {
+		t.Errorf("after the flights shrank: %q, want the first", got)
+	}
+	// One item too wide alone is cut to the width
+	if got := ticker.Render([]string{"AA 100 LAX 13:00 ON TIME"}, 80, ""); ansi.StringWidth(got) != 11 {
+		t.Errorf("wide item %q is %d cells, want 11", got, ansi.StringWidth(got))
+	}
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timeline cells: the empty track, flights on other pages, the track under
+// the current page and flights on it
+const (
+	timelineTrack      = "░"
+	timelineFlight     = "▓"
+	timelinePage       = "▒"
+	timelinePageFlight = "█"
+)
+
+// timelineBuckets counts the times falling in each of width equal cells of
+// the window from now to now+window. Times before now count in the first
+// cell and times after the window in the last, so every flight is marked
+func timelineBuckets(times []time.Time, now time.Time, window time.Duration, width int) []int {
+	if width <= 0 {
+		return nil
+	}
+	buckets := make([]int, width)
+	for _, t := range times {
+		buckets[timelineCell(t, now, window, width)]++
+	}
+	return buckets
+}
+
+// timelineCell returns the cell of a width cell timeline from now to
+// now+window that t falls in
+func timelineCell(t time.Time, now time.Time, window time.Duration, width int) int {
+	if window <= 0 {
+		return 0
+	}
+	offset := t.Sub(now)
+	cell := int(int64(offset) * int64(width) / int64(window))
+	return max(0, min(width-1, cell))
+}
+
+// timelineSpan returns the first and last cells holding any of times, or
+// false if there are none
+func timelineSpan(times []time.Time, now time.Time, window time.Duration, width int) (int, int, bool) {
+	if len(times) == 0 || width <= 0 {
+		return 0, 0, false
+	}
+	first, last := width, -1
+	for _, t := range times {
+		cell := timelineCell(t, now, window, width)
+		first, last = min(first, cell), max(last, cell)
+	}
+	return first, last, true
+}
+
+// renderTimeline draws the lookahead window as a bar the width of the table,
+// marking where flights fall and highlighting the times the current page
+// covers, e.g. "NOW ░▓░▒█▒█░░▓░ +6H"
+func (b *Board) renderTimeline(now time.Time) string {
+	rows := b.Rows()
+	if len(rows) == 0 {
+		return ""
+	}
+	times := make([]time.Time, 0, len(rows))
+	for _, row := range rows {
+		if row.Flight != nil {
+			times = append(times, row.Flight.ScheduledTime())
+		}
+	}
+	var page []time.Time
+	for _, row := range b.GetCurrentPageFlights() {
+		if row.Flight != nil {
+			page = append(page, row.Flight.ScheduledTime())
+		}
+	}
+
+	// Without a lookahead limit the bar runs to the last flight
+	window := b.lookahead()
+	if window <= 0 {
+		for _, t := range times {
+			window = max(window, t.Sub(now))
+		}
+	}
+	start, end := "NOW ", fmt.Sprintf(" +%dH", int((window+time.Hour-1)/time.Hour))
+	width := b.tableWidth() - len(start) - len(end)
+	if width < 4 || window <= 0 {
+		return ""
+	}
+
+	// The current page is drawn in the header color, the rest dimmed
+	highlight := b.Styles.Header.UnsetUnderline()
+	buckets := timelineBuckets(times, now, window, width)
+	first, last, ok := timelineSpan(page, now, window, width)
+	track, flight, pageTrack, pageFlight := b.Glyphs.timelineCells()
+	var bar strings.Builder
+	for i, count := range buckets {
+		onPage := ok && i >= first && i <= last
+		switch {
+		case onPage && count > 0:
+			bar.WriteString(highlight.Render(pageFlight))
+		case onPage:
+			bar.WriteString(highlight.Render(pageTrack))
+		case count > 0:
+			bar.WriteString(b.Styles.Text.Render(flight))
+		default:
+			bar.WriteString(b.Styles.StatusBar.Render(track))
+		}
+	}
+	return b.Styles.StatusBar.Render(start) + bar.String() + b.Styles.StatusBar.Render(end)
+}
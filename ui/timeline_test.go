@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTimelineBuckets(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+	at := func(offsets ...time.Duration) []time.Time {
+		times := make([]time.Time, len(offsets))
+		for i, offset := range offsets {
+			times[i] = now.Add(offset)
+		}
+		return times
+	}
+	tests := []struct {
+		name   string
+		times  []time.Time
+		window time.Duration
+		width  int
+		want   string
+	}{
+		{"one an hour", at(0, time.Hour, 2*time.Hour, 3*time.Hour, 4*time.Hour, 5*time.Hour), 6 * time.Hour, 6, "[1 1 1 1 1 1]"},
+		{"clustered", at(10*time.Minute, 20*time.Minute, 89*time.Minute, 90*time.Minute, 359*time.Minute), 6 * time.Hour, 4, "[3 1 0 1]"},
+		{"half hour cells", at(29*time.Minute, 30*time.Minute, 3*time.Hour, 345*time.Minute), 6 * time.Hour, 12, "[1 1 0 0 0 0 1 0 0 0 0 1]"},
+		// Flights outside the window are pinned to its ends
+		{"before now and past the window", at(-30*time.Minute, 6*time.Hour, 7*time.Hour), 6 * time.Hour, 6, "[1 0 0 0 0 2]"},
+		{"one cell", at(0, 3*time.Hour, 9*time.Hour), 6 * time.Hour, 1, "[3]"},
+		{"no window", at(time.Hour, 2*time.Hour), 0, 4, "[2 0 0 0]"},
+		{"no flights", nil, 6 * time.Hour, 3, "[0 0 0]"},
+		{"no width", at(time.Hour), 6 * time.Hour, 0, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(timelineBuckets(tt.times, now, tt.window, tt.width)); got != tt.want {
+				t.Errorf("buckets = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// A wide bar of a day splits it into 24 minute cells
+	buckets := timelineBuckets(at(0, 12*time.Hour, 1439*time.Minute), now, 24*time.Hour, 60)
+	for cell, want := range map[int]int{0: 1, 30: 1, 59: 1} {
+		if buckets[cell] != want {
+			t.Errorf("day cell %d holds %d flights, want %d", cell, buckets[cell], want)
+		}
+	}
+
+	first, last, ok := timelineSpan(at(90*time.Minute, time.Hour, 150*time.Minute), now, 6*time.Hour, 6)
+	if !ok || first != 1 || last != 2 {
+		t.Errorf("span = %d to %d, %v, want 1 to 2", first, last, ok)
+	}
+	if _, _, ok := timelineSpan(nil, now, 6*time.Hour, 6); ok {
+		t.Error("span of no flights")
+	}
+}
+
+func TestTimelineMono(t *testing.T) {
+	for _, tt := range []struct {
+		set   StyleSet
+		shown bool
+	}{
+		{StylesColor, true},
+		{StylesBasic, true},
+		{StylesMono, false},
+	} {
+		board := newTestBoard(3)
+		board.SetStyles(tt.set)
+		board.Timeline = true
+		board.Lookahead = 6 * time.Hour
+		board.UpdateFlights(testFlights(5, time.Now()))
+		settle(t, board)
+		if got := strings.Contains(ansi.Strip(board.Render()), "NOW "); got != tt.shown {
+			t.Errorf("%s styles show the timeline: %v, want %v", tt.set, got, tt.shown)
+		}
+	}
+}
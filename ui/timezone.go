@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeZoneMode selects the timezone flight times are shown in
+type TimeZoneMode int
+
+const (
+	TimeAirport TimeZoneMode = iota // The airport's local time
+	TimeUTC                         // Zulu time, for dispatchers
+	TimeViewer                      // The timezone of the machine running the board
+)
+
+// ParseTimeZoneMode parses a TIME_ZONE_MODE config value (airport, utc or local)
+func ParseTimeZoneMode(value string) (TimeZoneMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "airport":
+		return TimeAirport, nil
+	case "utc", "zulu":
+		return TimeUTC, nil
+	case "local", "viewer":
+		return TimeViewer, nil
+	default:
+		return TimeAirport, fmt.Errorf("unknown time zone mode %q (expected airport, utc or local)", value)
+	}
+}
+
+// Next returns the mode after m, cycling airport → UTC → viewer-local
+func (m TimeZoneMode) Next() TimeZoneMode {
+	return (m + 1) % 3
+}
+
+// location returns the timezone for the mode, given the airport's timezone
+// Boards without a known airport timezone show UTC
+func (m TimeZoneMode) location(airportTZ *time.Location) *time.Location {
+	switch m {
+	case TimeUTC:
+		return time.UTC
+	case TimeViewer:
+		return time.Local
+	default:
+		if airportTZ == nil {
+			return time.UTC
+		}
+		return airportTZ
+	}
+}
+
+// timeColumnName returns the TIME column header for the mode, naming the
+// zone unless times are the airport's own, e.g. "TIME (UTC)"
+func (m TimeZoneMode) timeColumnName(now time.Time) string {
+	switch m {
+	case TimeUTC:
+		return "TIME (UTC)"
+	case TimeViewer:
+		return "TIME (" + now.In(time.Local).Format("MST") + ")"
+	default:
+		return "TIME"
+	}
+}
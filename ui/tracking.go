@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fids-tui/models"
+)
+
+// TrackState is how far a watched flight that has left the board has got
+type TrackState int
+
+const (
+	TrackDeparted  TrackState = iota // Left the gate, not yet airborne
+	TrackEnroute                     // Airborne
+	TrackArrived                     // At its destination
+	TrackCancelled                   // Cancelled after leaving the board
+)
+
+// String returns the state as the tracking line reads it, e.g. "enroute"
+func (s TrackState) String() string {
+	switch s {
+	case TrackEnroute:
+		return "enroute"
+	case TrackArrived:
+		return "arrived"
+	case TrackCancelled:
+		return "cancelled"
+	}
+	return "departed"
+}
+
+// Done reports whether the flight has got as far as it will, so there is
+// nothing more to look up
+func (s TrackState) Done() bool {
+	return s == TrackArrived || s == TrackCancelled
+}
+
+// Tracked is a watched departure followed after it left the board, as last
+// looked up
+type Tracked struct {
+	State  TrackState
+	Flight models.Flight // The flight, with the times last looked up
+}
+
+// trackedParts returns how far the watched flight number has got since it
+// left the board and where it is bound and when, e.g. "enroute" and "ETA
+// ORD 16:42", "arrived" and "ORD 16:40", or false if it isn't tracked
+func (b *Board) trackedParts(number string) (string, string, bool) {
+	tracked, ok := b.Tracking[number]
+	if !ok {
+		return "", "", false
+	}
+	flight := &tracked.Flight
+	place := airportOrPlaceholder(strings.TrimSpace(flight.DestinationCode))
+	switch tracked.State {
+	case TrackCancelled:
+		return tracked.State.String(), "", true
+	case TrackArrived:
+		if flight.EstimatedArrival == nil {
+			return tracked.State.String(), place, true
+		}
+		return tracked.State.String(), place + " " + flight.EstimatedArrival.In(b.zoneFor(flight)).Format("15:04"), true
+	}
+	eta := flight.ScheduledArrival
+	if flight.EstimatedArrival != nil {
+		eta = *flight.EstimatedArrival
+	}
+	if eta.IsZero() {
+		return tracked.State.String(), "to " + place, true
+	}
+	return tracked.State.String(), fmt.Sprintf("ETA %s %s", place, eta.In(b.zoneFor(flight)).Format("15:04")), true
+}
+
+// trackedText returns the tracking line of the watched flight number, e.g.
+// "UA123 enroute, ETA ORD 16:42", or false if it isn't tracked
+func (b *Board) trackedText(number string) (string, bool) {
+	state, detail, ok := b.trackedParts(number)
+	switch {
+	case !ok:
+		return "", false
+	case detail == "":
+		return number + " " + state, true
+	case strings.HasPrefix(detail, "ETA"):
+		return number + " " + state + ", " + detail, true
+	}
+	return number + " " + state + " " + detail, true
+}
+
+// trackingLines returns a line for each watched flight followed after it
+// left the board, e.g. "UA123 enroute, ETA ORD 16:42", for screens without
+// the sidebar
+func (b *Board) trackingLines(now time.Time) []string {
+	var lines []string
+	for _, number := range b.WatchFlights {
+		if b.watchedFlight(number, now) != nil {
+			continue
+		}
+		if text, ok := b.trackedText(number); ok {
+			lines = append(lines, b.Styles.PageInfo.Render(text))
+		}
+	}
+	return lines
+}
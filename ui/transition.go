@@ -0,0 +1,122 @@
+package ui
+
+import "time"
+
+// TransitionTick is how often a page transition should be ticked for it to
+// finish in well under a second, whatever the character animation speed
+const TransitionTick = 100 * time.Millisecond
+
+// maxTransition bounds a page transition, even if its rows are slow to settle
+const maxTransition = 900 * time.Millisecond
+
+// pageTransition flips the rows of the page being left to blanks, then the
+// rows of the page shown from blanks to their values
+type pageTransition struct {
+	outgoing []*FlightRow // Rows of the page being left, shown until they are blank
+	started  time.Time
+	incoming bool // The outgoing rows are blank and the new page is flipping in
+}
+
+// startTransition starts flipping out the rows of the current page, before
+// the page changes. It does nothing unless transitions are enabled
+func (b *Board) startTransition() {
+	if !b.PageTransitions || b.TotalPages <= 1 {
+		return
+	}
+	if b.transition != nil {
+		b.finishTransition()
+	}
+	outgoing := b.GetCurrentPageFlights()
+	for _, row := range outgoing {
+		row.flipOut()
+	}
+	b.transition = &pageTransition{outgoing: outgoing, started: time.Now()}
+}
+
+// tickTransition moves a page transition on once its outgoing rows are blank,
+// and ends it once the new page has flipped in or maxTransition has passed
+func (b *Board) tickTransition(now time.Time) {
+	tr := b.transition
+	if tr == nil {
+		return
+	}
+	if now.Sub(tr.started) >= maxTransition {
+		b.finishTransition()
+		return
+	}
+	if !tr.incoming {
+		if anyAnimating(tr.outgoing) {
+			return
+		}
+		tr.incoming = true
+		b.restoreOutgoing()
+		for _, row := range b.GetCurrentPageFlights() {
+			row.flipIn()
+		}
+		return
+	}
+	if !anyAnimating(b.GetCurrentPageFlights()) {
+		b.transition = nil
+	}
+}
+
+// finishTransition ends a page transition straight away
+func (b *Board) finishTransition() {
+	if b.transition != nil && !b.transition.incoming {
+		b.restoreOutgoing()
+	}
+	b.transition = nil
+}
+
+// restoreOutgoing puts back the values of the rows flipped out, which may be
+// shown again later. They are off screen, so their animation isn't seen
+func (b *Board) restoreOutgoing() {
+	for _, row := range b.transition.outgoing {
+		if row.Flight != nil {
+			row.Update(row.Flight)
+		}
+	}
+}
+
+// Transitioning reports whether a page transition is under way. Updates to
+// the flights should wait until it ends, so rows don't change mid-flip
+func (b *Board) Transitioning() bool {
+	return b.transition != nil
+}
+
+// pageRows returns the rows to draw: the page being left while it flips out,
+// otherwise the current page
+func (b *Board) pageRows() []*FlightRow {
+	if b.transition != nil && !b.transition.incoming {
+		return b.transition.outgoing
+	}
+	return b.GetCurrentPageFlights()
+}
+
+// anyAnimating reports whether any of rows is animating
+func anyAnimating(rows []*FlightRow) bool {
+	for _, row := range rows {
+		if row.IsAnimating() {
+			return true
+		}
+	}
+	return false
+}
+
+// flipOut animates every cell of the row to blank
+func (fr *FlightRow) flipOut() {
+	for _, col := range fr.layout.Columns() {
+		blank := PadCell("", col.Width, col.Align)
+		fr.values[col.ID] = blank
+		fr.cells[col.ID].Update(blank)
+	}
+}
+
+// flipIn animates every cell of the row from blank to its value
+func (fr *FlightRow) flipIn() {
+	if fr.Flight == nil {
+		return
+	}
+	fr.flipOut()
+	fr.Update(fr.Flight)
+}
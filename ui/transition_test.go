@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPageTransition turns the page of a board with transitions on, checking
+// the page being left is drawn until its rows are blank, then the new page
+// flips in and the transition ends
+func TestPageTransition(t *testing.T) {
+	board := newTestBoard(3)
+	board.PageTransitions = true
+	board.UpdateFlights(testFlights(6, time.Now()))
+	settle(t, board)
+	leaving := board.GetCurrentPageFlights()
+
+	board.NextPage()
+	if !board.Transitioning() || board.CurrentPage != 1 {
+		t.Fatalf("next page: transitioning %v on page %d, want a transition to page 1", board.Transitioning(), board.CurrentPage)
+	}
+	if got := board.pageRows(); len(got) != len(leaving) || got[0] != leaving[0] {
+		t.Error("new page drawn before the page being left has flipped out")
+	}
+	if strings.Contains(board.Render(), "AA 103") {
+		t.Error("new page's flights shown while the old page flips out")
+	}
+
+	// The rows left are blank after a tick, and the new page flips in
+	board.Tick()
+	if !board.Transitioning() || !board.transition.incoming {
+		t.Fatal("transition didn't move on to the new page once the old one was blank")
+	}
+	if got := strings.TrimSpace(leaving[0].values[ColFlight]); got != "AA 100" {
+		t.Errorf("row flipped out left with %q, want its flight back for when it is shown again", got)
+	}
+	if !anyAnimating(board.GetCurrentPageFlights()) {
+		t.Error("new page not flipping in")
+	}
+	board.Tick()
+	if board.Transitioning() {
+		t.Error("transition still under way once the new page has flipped in")
+	}
+	if lines := renderedLines(board); lineOf(lines, 0, "AA 103") < 0 || lineOf(lines, 0, "AA 100") >= 0 {
+		t.Errorf("transition ended without the new page:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+// TestPageTransitionEnds checks a transition ends at maxTransition however
+// slow its rows, when the flights are updated, and that none starts when
+// transitions are off or there is only one page
+func TestPageTransitionEnds(t *testing.T) {
+	flights := testFlights(6, time.Now())
+	board := newTestBoard(3)
+	board.PageTransitions = true
+	board.UpdateFlights(flights)
+	settle(t, board)
+	leaving := board.GetCurrentPageFlights()
+
+	board.NextPage()
+	started := board.transition.started
+	board.tickTransition(started.Add(maxTransition - time.Millisecond))
+	if !board.Transitioning() {
+		t.Fatal("transition ended before its rows were blank or maxTransition passed")
+	}
+	board.tickTransition(started.Add(maxTransition))
+	if board.Transitioning() {
+		t.Error("transition still under way after maxTransition")
+	}
+	if got := strings.TrimSpace(leaving[0].values[ColFlight]); got != "AA 100" {
+		t.Errorf("row cut off flipping out left with %q, want its flight back", got)
+	}
+
+	board.NextPage()
+	board.UpdateFlights(flights)
+	if board.Transitioning() {
+		t.Error("transition still under way after the flights were updated")
+	}
+
+	board.PageTransitions = false
+	board.NextPage()
+	if board.Transitioning() {
+		t.Error("transition started with transitions off")
+	}
+	board.PageTransitions = true
+	board.UpdateFlights(flights[:3])
+	board.NextPage()
+	if board.Transitioning() {
+		t.Error("transition started on a board of one page")
+	}
+}
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fids-tui/models"
+)
+
+// ViewMode selects how a board arranges its flights
+type ViewMode int
+
+const (
+	ViewFlights ViewMode = iota // Timetable ordered by time
+	ViewGates                   // Flights grouped by gate for ground staff
+	ViewShuttle                 // Next few flights to each of a set of destinations
+	ViewTicker                  // One line of flights, scrolling or swapping, in time order
+)
+
+// ParseViewMode parses a VIEW config value (flights, gates, shuttle or ticker)
+func ParseViewMode(value string) (ViewMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "flights":
+		return ViewFlights, nil
+	case "gates":
+		return ViewGates, nil
+	case "shuttle":
+		return ViewShuttle, nil
+	case "ticker":
+		return ViewTicker, nil
+	default:
+		return ViewFlights, fmt.Errorf("unknown view %q (expected flights, gates, shuttle or ticker)", value)
+	}
+}
+
+// BoardView decides how a board presents its flights: the columns of each
+// row, the order of the rows and the board title
+type BoardView interface {
+	Layout(mode LayoutMode, direction models.Direction) Layout
+	Less(a, b *models.Flight) bool
+	Title(direction models.Direction) string
+}
+
+// ViewFor returns the board view for a view mode
+func ViewFor(mode ViewMode) BoardView {
+	switch mode {
+	case ViewGates:
+		return gateView{}
+	case ViewShuttle:
+		return shuttleView{}
+	default:
+		return timetableView{}
+	}
+}
+
+// timetableView is the standard board ordered by scheduled time
+type timetableView struct{}
+
+func (timetableView) Layout(mode LayoutMode, direction models.Direction) Layout {
+	return NewLayout(mode, direction)
+}
+
+func (timetableView) Less(a, b *models.Flight) bool {
+	return lessByTime(a, b)
+}
+
+// lessByTime orders flights by scheduled time, breaking ties by airline and
+// then flight number so flights sharing a time keep their order across updates
+func lessByTime(a, b *models.Flight) bool {
+	if ta, tb := a.ScheduledTime(), b.ScheduledTime(); !ta.Equal(tb) {
+		return ta.Before(tb)
+	}
+	if a.AirlineCode != b.AirlineCode {
+		return a.AirlineCode < b.AirlineCode
+	}
+	return a.FlightNumber < b.FlightNumber
+}
+
+func (timetableView) Title(direction models.Direction) string {
+	return strings.ToUpper(direction.String())
+}
+
+// gateView pivots the board by gate: rows start with the gate and are sorted
+// by gate then time, with flights that have no gate yet at the end
+type gateView struct{}
+
+func (gateView) Layout(mode LayoutMode, direction models.Direction) Layout {
+	layout := NewLayout(mode, direction)
+	var gate Column
+	lines := make([][]Column, len(layout.Lines))
+	for i, line := range layout.Lines {
+		for _, col := range line {
+			if col.ID == ColGate {
+				gate = col
+				continue
+			}
+			lines[i] = append(lines[i], col)
+		}
+	}
+	lines[0] = append([]Column{gate}, lines[0]...)
+	layout.Lines = lines
+	return layout
+}
+
+func (gateView) Less(a, b *models.Flight) bool {
+	if a.Gate == "" || b.Gate == "" {
+		if a.Gate != b.Gate {
+			// Ungated flights form a trailing section
+			return b.Gate == ""
+		}
+	} else if c := compareGates(a.Gate, b.Gate); c != 0 {
+		return c < 0
+	}
+	return lessByTime(a, b)
+}
+
+func (gateView) Title(direction models.Direction) string {
+	return strings.ToUpper(direction.String()) + " BY GATE"
+}
+
+// compareGates orders gate names naturally, so B2 comes before B12
+// Gates are compared by their letter prefix, then their number, then the rest
+func compareGates(a, b string) int {
+	prefixA, numA, restA := splitGate(a)
+	prefixB, numB, restB := splitGate(b)
+	if c := strings.Compare(prefixA, prefixB); c != 0 {
+		return c
+	}
+	if numA != numB {
+		if numA < numB {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(restA, restB)
+}
+
+// splitGate splits a gate like "B12A" into its prefix "B", number 12 and rest "A"
+// Gates without a number have number -1 so they sort before numbered gates
+func splitGate(gate string) (string, int, string) {
+	gate = strings.ToUpper(strings.TrimSpace(gate))
+	start := strings.IndexAny(gate, "0123456789")
+	if start < 0 {
+		return gate, -1, ""
+	}
+	end := start
+	for end < len(gate) && gate[end] >= '0' && gate[end] <= '9' {
+		end++
+	}
+	num, err := strconv.Atoi(gate[start:end])
+	if err != nil {
+		return gate, -1, ""
+	}
+	return gate[:start], num, gate[end:]
+}